@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/discovery"
+)
+
+// Styles of the add summary, lipgloss leaves out the colors when stdout
+// isn't a terminal
+var (
+	addLabelStyle = lipgloss.NewStyle().Faint(true)
+	addOKStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Bold(true)
+	addWarnStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+)
+
+// addURL discovers the feed of a URL and adds it to the URLs file, the
+// arguments after the URL are its folders and options like in the UI
+func addURL(args []string) error {
+	flags := flag.NewFlagSet("add", flag.ExitOnError)
+	quiet := flags.Bool("quiet", false, "Only print the feed URL that was added")
+	flags.BoolVar(quiet, "q", false, "Only print the feed URL that was added")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: newsgoat add [--quiet] <url> [folder1,folder2] [!option]\n\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() < 1 {
+		flags.Usage()
+		return fmt.Errorf("'add' command requires a URL argument")
+	}
+	urlArg := flags.Arg(0)
+
+	if !*quiet {
+		fmt.Printf("Discovering feed URL from: %s\n", urlArg)
+	}
+	result, err := discovery.Discover(urlArg)
+	if err != nil {
+		return fmt.Errorf("failed to discover feed: %w", err)
+	}
+
+	existing, err := findURLEntry(result.FeedURL)
+	if err != nil {
+		return fmt.Errorf("failed to read URLs file: %w", err)
+	}
+	added := existing == nil
+	if added {
+		line := strings.Join(append([]string{result.FeedURL}, flags.Args()[1:]...), " ")
+		if err := config.AddURLLine(line); err != nil {
+			return fmt.Errorf("failed to add URL to file: %w", err)
+		}
+		if existing, err = findURLEntry(result.FeedURL); err != nil || existing == nil {
+			return fmt.Errorf("failed to read back the added URL: %v", err)
+		}
+	}
+
+	if *quiet {
+		fmt.Println(result.FeedURL)
+		return nil
+	}
+
+	if added {
+		fmt.Println(addOKStyle.Render("Added feed"))
+	} else {
+		fmt.Println(addWarnStyle.Render("Already in the URLs file, nothing was changed"))
+	}
+	printAddField("Type", urlTypeLabel(result))
+	printAddField("Feed", result.FeedURL)
+	if title, err := discovery.FetchTitle(result.FeedURL); err != nil {
+		printAddField("Title", addWarnStyle.Render("unknown, "+err.Error()))
+	} else if title != "" {
+		printAddField("Title", title)
+	}
+	if len(existing.Folders) > 0 {
+		printAddField("Folders", strings.Join(existing.Folders, ", "))
+	} else {
+		printAddField("Folders", "none")
+	}
+	if line := config.FormatURLLine(*existing); line != existing.URL {
+		printAddField("Line", line)
+	}
+	return nil
+}
+
+func printAddField(label, value string) {
+	fmt.Printf("  %s %s\n", addLabelStyle.Render(fmt.Sprintf("%-8s", label+":")), value)
+}
+
+// findURLEntry returns the entry of the URLs file for a feed URL, or nil
+func findURLEntry(feedURL string) (*config.URLEntry, error) {
+	entries, err := config.ReadURLsFile()
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.URL == feedURL {
+			return &entry, nil
+		}
+	}
+	return nil, nil
+}
+
+// urlTypeLabel describes what kind of URL the feed was found from
+func urlTypeLabel(result discovery.Result) string {
+	switch result.Type {
+	case discovery.URLTypeYouTube:
+		return "YouTube channel"
+	case discovery.URLTypeGitHub:
+		return "GitHub commits"
+	case discovery.URLTypeGitLab:
+		return "GitLab commits"
+	}
+	if result.FromHTML {
+		return "Web page, the feed is linked from its HTML"
+	}
+	return "Feed"
+}
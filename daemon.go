@@ -0,0 +1,486 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/discovery"
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/hooks"
+	"github.com/jarv/newsgoat/internal/ipc"
+	feedsync "github.com/jarv/newsgoat/internal/sync"
+	"github.com/jarv/newsgoat/internal/tasks"
+)
+
+const (
+	// daemonSettingsJobName is the job that applies settings changed in a
+	// window and syncs the URLs file
+	daemonSettingsJobName = "daemon_settings"
+	// daemonSettingsInterval is how often the daemon reads the settings again
+	daemonSettingsInterval = time.Minute
+	// daemonRefreshTimeout bounds how long a refresh request waits for the feeds
+	daemonRefreshTimeout = 10 * time.Minute
+)
+
+// daemon refreshes the feeds on the UI's schedule without a terminal and
+// serves requests of the UI windows and commands on the socket
+type daemon struct {
+	ctx         context.Context
+	feedManager *feeds.Manager
+	taskManager tasks.Manager
+	queries     *database.Queries
+	urlFile     string
+	refreshJob  *tasks.RefreshJob
+
+	urlsMu      sync.Mutex
+	urlsModTime time.Time // The URLs file is synced again when it changed
+
+	mu      sync.Mutex
+	status  ipc.DaemonStatus
+	pending map[string]chan error // Feed refresh tasks by ID, with the channel of a request waiting for them
+}
+
+// daemonCommand runs NewsGoat in the background until it is stopped with
+// SIGINT or SIGTERM, e.g. as a systemd user service
+func daemonCommand(args []string, urlFile string, debug bool) error {
+	flags := flag.NewFlagSet("daemon", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: newsgoat daemon\n\n")
+		fmt.Fprintf(os.Stderr, "Refreshes the feeds in the background, NewsGoat windows started while it runs\n")
+		fmt.Fprintf(os.Stderr, "leave refreshing to it.\n")
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return fmt.Errorf("'daemon' takes no arguments")
+	}
+
+	lock, err := config.AcquireLock()
+	var locked *config.LockedError
+	if errors.As(err, &locked) {
+		return fmt.Errorf("newsgoat is already running (pid %d), quit it before starting the daemon", locked.PID)
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = lock.Release()
+	}()
+
+	db, queries, err := database.InitDB()
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+	if err := RunMigrations(db); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	cfg, err := config.LoadConfig(queries)
+	if err != nil {
+		cfg = config.GetDefaultConfig()
+	}
+	setupLogging(queries, debug)
+	feeds.NewReadLaterConfig(cfg).RegisterSecrets()
+	feedsync.NewConfig(cfg).RegisterSecrets()
+
+	feedManager := feeds.NewManager(db, queries)
+	feedManager.SetRequestOptions(cfg.RequestOptions())
+	feedManager.SetRetentionPolicy(cfg.RetentionPolicy())
+	feedManager.SetHostLimits(cfg.HostLimits())
+	feedManager.SetHistoryPages(cfg.FeedHistoryPages)
+	feedManager.SetFeedIcons(cfg.FeedIcons != config.FeedIconsOff)
+	eventHooks := loadHooks()
+	feedManager.SetHooks(eventHooks)
+	feedManager.SetNotifyPolicy(cfg.NotifyPolicy())
+	if cfg.UpdateRedirects {
+		urlsPath := urlFile
+		if urlsPath == "" {
+			urlsPath, _ = config.GetURLsFilePath()
+		}
+		feedManager.SetRedirectUpdates(urlsPath)
+	}
+
+	taskManager := tasks.NewManagerWithStore(cfg.ReloadConcurrency, tasks.NewDBStore(queries))
+	if err := taskManager.Start(context.Background()); err != nil {
+		return fmt.Errorf("failed to start task manager: %w", err)
+	}
+	defer func() {
+		_ = taskManager.Stop()
+	}()
+	if err := registerTaskHandlers(taskManager, feedManager, queries, tasks.NewFeedRefreshHandler(feedManager, taskManager)); err != nil {
+		return err
+	}
+	taskManager.SetRetryPolicy(tasks.TaskTypeFeedRefresh, cfg.RefreshRetryPolicy())
+	if err := taskManager.RestoreTasks(); err != nil {
+		logger.Warn("Failed to restore unfinished tasks", "error", err)
+	}
+
+	if webSub := feeds.NewWebSubConfig(cfg); webSub.Enabled() {
+		stopWebSub, err := feedManager.StartWebSub(webSub, func(feedID int64, url string) {
+			if err := taskManager.AddTask(tasks.CreatePushedFeedRefreshTask(feedID, url)); err != nil {
+				logger.Warn("Failed to queue pushed feed refresh", "url", url, "error", err)
+			}
+		})
+		if err != nil {
+			logger.Warn("Failed to start WebSub listener", "address", webSub.Listen, "error", err)
+		} else {
+			defer func() {
+				_ = stopWebSub()
+			}()
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	d := &daemon{
+		ctx:         ctx,
+		feedManager: feedManager,
+		taskManager: taskManager,
+		queries:     queries,
+		urlFile:     urlFile,
+		status:      ipc.DaemonStatus{PID: os.Getpid()},
+		pending:     make(map[string]chan error),
+	}
+	d.refreshJob = tasks.NewRefreshJob(feedManager, taskManager, d.loadConfig)
+	if err := config.CreateSampleURLsFile(); err != nil {
+		logger.Warn("Failed to create sample URLs file", "error", err)
+	}
+	if err := d.syncURLs(); err != nil {
+		return err
+	}
+
+	path, err := config.GetSocketFilePath()
+	if err != nil {
+		return err
+	}
+	server, err := ipc.Listen(path, d.handle)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	defer func() {
+		_ = server.Close()
+	}()
+
+	logger.Info("Daemon started", "pid", os.Getpid(), "socket", path)
+	fmt.Fprintf(os.Stderr, "NewsGoat daemon running (pid %d), listening on %s\n", os.Getpid(), path)
+	eventHooks.Fire(hooks.EventStartup, nil)
+	d.run(cfg.PruneSchedule)
+	feedManager.FlushNotifications()
+	eventHooks.Fire(hooks.EventShutdown, nil)
+	eventHooks.Wait(hooksExitTimeout)
+	logger.Info("Daemon stopped")
+	return nil
+}
+
+// run schedules the refreshes, syncs and cleanups with the task manager like
+// the UI does and waits until the daemon is stopped
+func (d *daemon) run(pruneSchedule string) {
+	go d.watchTasks()
+
+	d.taskManager.ScheduleJob(tasks.Job{
+		Name:     daemonSettingsJobName,
+		Schedule: tasks.Every(daemonSettingsInterval),
+		Tasks:    d.applySettings,
+	})
+	d.taskManager.ScheduleJob(d.refreshJob.Job())
+	d.taskManager.ScheduleJob(tasks.LogCleanupJob(d.loadConfig))
+	d.taskManager.ScheduleJob(tasks.SyncJob(d.taskManager, d.loadConfig))
+	if err := tasks.SchedulePrune(d.taskManager, pruneSchedule, d.loadConfig); err != nil {
+		logger.Warn("Ignoring prune schedule", "error", err)
+	}
+	d.taskManager.RunJob(tasks.RefreshJobName)
+	d.taskManager.RunJob(tasks.LogCleanupJobName)
+	d.taskManager.RunJob(tasks.SyncJobName)
+
+	<-d.ctx.Done()
+}
+
+// loadConfig reads the settings again, they may have been changed in a window
+func (d *daemon) loadConfig() config.Config {
+	cfg, err := config.LoadConfig(d.queries)
+	if err != nil {
+		logger.Warn("Failed to load settings", "error", err)
+		return config.GetDefaultConfig()
+	}
+	return cfg
+}
+
+// syncURLs adds, hides and updates feeds like the URLs file has them, when
+// it changed since the last time
+func (d *daemon) syncURLs() error {
+	d.urlsMu.Lock()
+	defer d.urlsMu.Unlock()
+
+	path := d.urlFile
+	if path == "" {
+		var err error
+		if path, err = config.GetURLsFilePath(); err != nil {
+			return err
+		}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to read URLs file: %w", err)
+	}
+	if info.ModTime().Equal(d.urlsModTime) {
+		return nil
+	}
+
+	urlEntries, err := config.ReadURLsFileFromPath(path)
+	if err != nil {
+		return fmt.Errorf("failed to read URLs file: %w", err)
+	}
+	if err := syncFeedsWithURLsFile(d.feedManager, d.queries, urlEntries); err != nil {
+		return fmt.Errorf("failed to sync feeds with URLs file: %w", err)
+	}
+	d.urlsModTime = info.ModTime()
+	d.changed()
+	return nil
+}
+
+// applySettings applies the settings a window may have changed and syncs
+// the feeds with the URLs file, it queues no tasks
+func (d *daemon) applySettings(time.Time) []*tasks.Task {
+	cfg := d.loadConfig()
+	d.taskManager.SetRetryPolicy(tasks.TaskTypeFeedRefresh, cfg.RefreshRetryPolicy())
+	d.feedManager.SetFeedIcons(cfg.FeedIcons != config.FeedIconsOff)
+	d.feedManager.SetHistoryPages(cfg.FeedHistoryPages)
+	if err := d.syncURLs(); err != nil {
+		logger.Warn("Failed to sync feeds with URLs file", "error", err)
+	}
+	return nil
+}
+
+// queueRefreshes adds a refresh task for each feed, with wait it returns the
+// channels their errors are sent on once they are done
+func (d *daemon) queueRefreshes(feedIDs []int64, feedURLs map[int64]string, wait bool) []chan error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var done []chan error
+	for _, feedID := range feedIDs {
+		task := tasks.CreateFeedRefreshTask(feedID, feedURLs[feedID])
+		if err := d.taskManager.AddTask(task); err != nil {
+			logger.Warn("Failed to queue feed refresh", "feedID", feedID, "error", err)
+			continue
+		}
+		// The task's event waits for the lock, so it can't be missed
+		var taskDone chan error
+		if wait {
+			taskDone = make(chan error, 1)
+			done = append(done, taskDone)
+		}
+		d.pending[task.ID] = taskDone
+	}
+	return done
+}
+
+// changed counts a change to the database, windows reload their lists when
+// the count they polled changes
+func (d *daemon) changed() {
+	d.mu.Lock()
+	d.status.Changes++
+	d.mu.Unlock()
+}
+
+// watchTasks counts the finished tasks that changed the database and hands
+// refresh results to the requests waiting for them. Finished tasks are
+// removed, nobody looks at the daemon's task list.
+func (d *daemon) watchTasks() {
+	for event := range d.taskManager.Subscribe() {
+		if event.Type != tasks.TaskEventCompleted && event.Type != tasks.TaskEventFailed {
+			continue
+		}
+
+		d.mu.Lock()
+		switch event.TaskType {
+		case tasks.TaskTypeFeedRefresh:
+			d.status.Changes++
+			d.status.LastRefresh = event.Timestamp
+			if taskDone, ok := d.pending[event.TaskID]; ok {
+				delete(d.pending, event.TaskID)
+				if taskDone != nil {
+					var err error
+					if event.Type == tasks.TaskEventFailed {
+						err = errors.New(event.Error)
+					}
+					taskDone <- err
+				}
+			}
+		case tasks.TaskTypeSync, tasks.TaskTypeItemEnrichment, tasks.TaskTypeCleanup, tasks.TaskTypeFeedIcon:
+			d.status.Changes++
+		}
+		d.mu.Unlock()
+
+		// Retrying tasks stay until they run again
+		if event.Status != tasks.TaskStatusRetrying {
+			_ = d.taskManager.RemoveTask(event.TaskID)
+		}
+	}
+}
+
+// handle serves a request of a window or a newsgoat command
+func (d *daemon) handle(request ipc.Request) (string, error) {
+	switch request.Command {
+	case ipc.CommandStatus:
+		d.mu.Lock()
+		status := d.status
+		d.mu.Unlock()
+		status.Refreshing = d.refreshJob.Refreshing()
+		status.NextRefresh = d.refreshJob.NextRefresh()
+		return ipc.EncodeStatus(status), nil
+	case ipc.CommandRefresh:
+		return d.refresh(request)
+	case ipc.CommandAdd:
+		return d.add(request.URL)
+	case ipc.CommandMarkRead, ipc.CommandMarkItemRead:
+		message, err := markRead(d.feedManager, request)
+		if err == nil {
+			d.changed()
+		}
+		return message, err
+	}
+	return "", fmt.Errorf("unknown request %q", request.Command)
+}
+
+// refresh refreshes the feed of the request, or every feed that isn't paused,
+// and replies once they are done
+func (d *daemon) refresh(request ipc.Request) (string, error) {
+	feedStats, err := d.feedManager.GetFeedStats()
+	if err != nil {
+		return "", err
+	}
+	var feedIDs []int64
+	feedURLs := make(map[int64]string)
+	for _, feed := range feedStats {
+		if request.All && !feed.Paused || !request.All && (feed.ID == request.FeedID || request.URL != "" && feed.Url == request.URL) {
+			feedIDs = append(feedIDs, feed.ID)
+			feedURLs[feed.ID] = feed.Url
+		}
+	}
+	var fresh int
+	if request.All {
+		plan := tasks.PlanRefreshAll(d.feedManager, feedIDs, time.Now())
+		feedIDs, fresh = plan.FeedIDs, plan.Fresh
+	}
+	if len(feedIDs) == 0 {
+		if request.All {
+			if fresh > 0 {
+				return fmt.Sprintf("Nothing to refresh, skipped %d fresh feeds", fresh), nil
+			}
+			return "No feeds to refresh", nil
+		}
+		return "", fmt.Errorf("no such feed, see newsgoat list")
+	}
+
+	done := d.queueRefreshes(feedIDs, feedURLs, true)
+	failed := 0
+	var lastErr error
+	for _, taskDone := range done {
+		select {
+		case err := <-taskDone:
+			if err != nil {
+				failed++
+				lastErr = err
+			}
+		case <-d.ctx.Done():
+			return "", fmt.Errorf("the daemon is stopping")
+		}
+	}
+	if len(feedIDs) == 1 && lastErr != nil {
+		return "", lastErr
+	}
+	message := fmt.Sprintf("Refreshed %d feeds", len(done)-failed)
+	if failed > 0 {
+		message += fmt.Sprintf(", %d failed", failed)
+	}
+	if fresh > 0 {
+		message += fmt.Sprintf(", skipped %d fresh feeds", fresh)
+	}
+	return message, nil
+}
+
+// add discovers the feed of a URL, adds it to the URLs file and refreshes it
+func (d *daemon) add(url string) (string, error) {
+	if url == "" {
+		return "", fmt.Errorf("no feed URL to add")
+	}
+	result, err := discovery.Discover(url)
+	if err != nil {
+		return "", fmt.Errorf("failed to discover feed: %w", err)
+	}
+	existing, err := findURLEntry(result.FeedURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to read URLs file: %w", err)
+	}
+	if existing != nil {
+		return fmt.Sprintf("Already subscribed to %s", result.FeedURL), nil
+	}
+	if err := config.AddURLLine(result.FeedURL); err != nil {
+		return "", fmt.Errorf("failed to add URL to file: %w", err)
+	}
+	if err := d.syncURLs(); err != nil {
+		return "", err
+	}
+
+	feed, err := d.queries.GetFeedByURL(context.Background(), result.FeedURL)
+	if err != nil {
+		return "", err
+	}
+	d.queueRefreshes([]int64{feed.ID}, map[int64]string{feed.ID: feed.Url}, false)
+	return fmt.Sprintf("Added %s", result.FeedURL), nil
+}
+
+// runningDaemon returns the pid of the daemon listening on the socket, or 0
+// when there is none, and the socket's path
+func runningDaemon() (int, string) {
+	path, err := config.GetSocketFilePath()
+	if err != nil {
+		return 0, ""
+	}
+	status, err := ipc.GetStatus(path)
+	if err != nil {
+		return 0, path
+	}
+	return status.PID, path
+}
+
+// daemonRefreshHandler hands the feed refresh tasks of a window to the
+// daemon, which refreshes the feeds and writes them to the database
+type daemonRefreshHandler struct {
+	socketPath string
+}
+
+func newDaemonRefreshHandler(socketPath string) *daemonRefreshHandler {
+	return &daemonRefreshHandler{socketPath: socketPath}
+}
+
+// Execute asks the daemon to refresh the task's feed and waits until it did
+func (h *daemonRefreshHandler) Execute(ctx context.Context, task *tasks.Task) error {
+	feedID, ok := task.Data["feed_id"].(int64)
+	if !ok {
+		return fmt.Errorf("invalid feed_id in task data")
+	}
+	_, err := ipc.SendTimeout(h.socketPath, ipc.Request{Command: ipc.CommandRefresh, FeedID: feedID}, daemonRefreshTimeout)
+	return err
+}
+
+// CanHandle returns true if this handler can handle the given task type
+func (h *daemonRefreshHandler) CanHandle(taskType tasks.TaskType) bool {
+	return taskType == tasks.TaskTypeFeedRefresh
+}
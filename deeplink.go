@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/ipc"
+)
+
+// linksDesktopFile is the name of the desktop entry that opens subscribe
+// links with newsgoat
+const linksDesktopFile = "newsgoat-links.desktop"
+
+// openLink hands the feed of a subscribe link to the running NewsGoat. When
+// none is running the feed is added to the URLs file and sent is false, so
+// NewsGoat starts with it.
+func openLink(feedURL string) (sent bool, err error) {
+	path, err := config.GetSocketFilePath()
+	if err != nil {
+		return false, err
+	}
+	message, err := ipc.Send(path, ipc.Request{Command: ipc.CommandAdd, URL: feedURL})
+	if err == nil {
+		fmt.Println(message)
+		return true, nil
+	}
+	if !errors.Is(err, ipc.ErrNotRunning) {
+		return false, err
+	}
+
+	if err := addURL([]string{feedURL}); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// registerLinks makes newsgoat the handler of feed:// and newsgoat:// links
+// with a desktop entry, which xdg-open and browsers on Linux look up
+func registerLinks() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("registering links is only supported on Linux, register \"%s %%u\" for the %s and %s URL schemes instead", executable, config.SchemeFeed, config.SchemeNewsGoat)
+	}
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		dataHome = filepath.Join(homeDir, ".local", "share")
+	}
+	dir := filepath.Join(dataHome, "applications")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	entry := fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=NewsGoat
+Comment=Subscribe to feeds in NewsGoat
+Exec=%s %%u
+Terminal=true
+NoDisplay=true
+MimeType=x-scheme-handler/%s;x-scheme-handler/%s;
+`, executable, config.SchemeFeed, config.SchemeNewsGoat)
+	path := filepath.Join(dir, linksDesktopFile)
+	if err := os.WriteFile(path, []byte(entry), 0644); err != nil {
+		return err
+	}
+
+	for _, scheme := range []string{config.SchemeFeed, config.SchemeNewsGoat} {
+		output, err := exec.Command("xdg-mime", "default", linksDesktopFile, "x-scheme-handler/"+scheme).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("wrote %s but xdg-mime failed: %v %s", path, err, output)
+		}
+	}
+	fmt.Printf("Registered newsgoat for %s:// and %s:// links in %s\n", config.SchemeFeed, config.SchemeNewsGoat, path)
+	return nil
+}
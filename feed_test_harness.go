@@ -1,7 +1,9 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"html"
 	"math/rand"
 	"net/http"
 	"strconv"
@@ -48,8 +50,68 @@ func generateLoremText(wordCount int) string {
 	return text + "."
 }
 
-// Generate a dummy RSS feed
-func generateDummyFeed(title string, articleCount int) string {
+// Feed formats the harness can serve
+const (
+	formatRSS      = "rss"
+	formatAtom     = "atom"
+	formatJSONFeed = "jsonfeed"
+)
+
+// feedContentTypes are the Content-Type headers of the feed formats
+var feedContentTypes = map[string]string{
+	formatRSS:      "application/rss+xml; charset=utf-8",
+	formatAtom:     "application/atom+xml; charset=utf-8",
+	formatJSONFeed: "application/feed+json; charset=utf-8",
+}
+
+// dummyArticle is an article of a generated feed
+type dummyArticle struct {
+	Title       string
+	Description string
+	Content     string
+	GUID        string
+	Published   time.Time
+}
+
+// Generate lorem ipsum articles numbered from first, with dup every second
+// article repeats the GUID of the one before it
+func generateDummyArticles(first, articleCount int, dup bool) []dummyArticle {
+	articles := make([]dummyArticle, articleCount)
+	for i := range articles {
+		title := generateLoremText(3 + rand.Intn(7)) // 3-10 words
+		guid := fmt.Sprintf("http://example.com/article/%d", first+i)
+		if dup && i%2 == 1 {
+			guid = articles[i-1].GUID
+		}
+		articles[i] = dummyArticle{
+			Title:       strings.TrimSuffix(title, "."),         // Remove period from titles
+			Description: generateLoremText(20 + rand.Intn(30)),  // 20-50 words
+			Content:     generateLoremText(50 + rand.Intn(100)), // 50-150 words
+			GUID:        guid,
+			// Random publish date within last 30 days
+			Published: time.Now().AddDate(0, 0, -rand.Intn(30)),
+		}
+	}
+	return articles
+}
+
+// feedPage is where a generated feed sits in a paginated feed
+type feedPage struct {
+	Number  int    // 1 for the feed itself
+	NextURL string // The older page, "" on the last one
+	Archive bool   // Link older pages with rel="prev-archive" like RFC 5005 archived feeds instead of rel="next"
+}
+
+// rel is the relation the page links its older page with
+func (p feedPage) rel() string {
+	if p.Archive {
+		return "prev-archive"
+	}
+	return "next"
+}
+
+// Generate a dummy feed in one of the feed formats
+func generateDummyFeed(title, format string, articleCount int, dup bool, page feedPage) string {
 	if title == "" {
 		title = loremTitles[rand.Intn(len(loremTitles))]
 	}
@@ -58,9 +120,22 @@ func generateDummyFeed(title string, articleCount int) string {
 		articleCount = 1 + rand.Intn(100) // 1-100 articles
 	}
 
+	// Every page numbers its articles after the ones of the newer pages
+	articles := generateDummyArticles((max(page.Number, 1)-1)*articleCount+1, articleCount, dup)
+	description := generateLoremText(15)
+	switch format {
+	case formatAtom:
+		return generateAtomFeed(title, description, articles, page)
+	case formatJSONFeed:
+		return generateJSONFeed(title, description, articles, page)
+	}
+	return generateRSSFeed(title, description, articles, page)
+}
+
+func generateRSSFeed(title, description string, articles []dummyArticle, page feedPage) string {
 	// Start RSS feed
 	rss := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
-<rss version="2.0">
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom" xmlns:fh="http://purl.org/syndication/history/1.0">
 <channel>
 <title>%s</title>
 <description>%s</description>
@@ -68,21 +143,16 @@ func generateDummyFeed(title string, articleCount int) string {
 <language>en-us</language>
 <lastBuildDate>%s</lastBuildDate>
 <generator>NewsGoat Feed Test Harness</generator>
-`, title, generateLoremText(15), time.Now().Format(time.RFC1123))
+`, title, description, time.Now().Format(time.RFC1123))
+	if page.NextURL != "" {
+		rss += fmt.Sprintf("<atom:link rel=%q href=%q/>\n", page.rel(), html.EscapeString(page.NextURL))
+	}
+	if page.Archive && page.Number > 1 {
+		rss += "<fh:archive/>\n"
+	}
 
 	// Add articles
-	for i := 0; i < articleCount; i++ {
-		articleTitle := generateLoremText(3 + rand.Intn(7)) // 3-10 words
-		articleTitle = strings.TrimSuffix(articleTitle, ".") // Remove period from titles
-
-		description := generateLoremText(20 + rand.Intn(30)) // 20-50 words
-		content := generateLoremText(50 + rand.Intn(100))    // 50-150 words
-
-		// Random publish date within last 30 days
-		publishDate := time.Now().AddDate(0, 0, -rand.Intn(30))
-
-		guid := fmt.Sprintf("http://example.com/article/%d", i+1)
-
+	for _, article := range articles {
 		rss += fmt.Sprintf(`
 <item>
 <title>%s</title>
@@ -91,7 +161,7 @@ func generateDummyFeed(title string, articleCount int) string {
 <link>%s</link>
 <guid>%s</guid>
 <pubDate>%s</pubDate>
-</item>`, articleTitle, description, content, guid, guid, publishDate.Format(time.RFC1123))
+</item>`, article.Title, article.Description, article.Content, article.GUID, article.GUID, article.Published.Format(time.RFC1123))
 	}
 
 	rss += `
@@ -101,6 +171,97 @@ func generateDummyFeed(title string, articleCount int) string {
 	return rss
 }
 
+func generateAtomFeed(title, description string, articles []dummyArticle, page feedPage) string {
+	atom := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:fh="http://purl.org/syndication/history/1.0">
+<title>%s</title>
+<subtitle>%s</subtitle>
+<link href="http://example.com"/>
+<id>http://example.com/</id>
+<updated>%s</updated>
+<generator>NewsGoat Feed Test Harness</generator>
+`, title, description, time.Now().Format(time.RFC3339))
+	if page.NextURL != "" {
+		atom += fmt.Sprintf("<link rel=%q href=%q/>\n", page.rel(), html.EscapeString(page.NextURL))
+	}
+	if page.Archive && page.Number > 1 {
+		atom += "<fh:archive/>\n"
+	}
+
+	for _, article := range articles {
+		atom += fmt.Sprintf(`
+<entry>
+<title>%s</title>
+<summary>%s</summary>
+<content type="html"><![CDATA[%s]]></content>
+<link href="%s"/>
+<id>%s</id>
+<published>%s</published>
+<updated>%s</updated>
+<author><name>%s</name></author>
+</entry>`, article.Title, article.Description, article.Content, article.GUID, article.GUID,
+			article.Published.Format(time.RFC3339), article.Published.Format(time.RFC3339), loremWords[rand.Intn(len(loremWords))])
+	}
+
+	atom += `
+</feed>`
+
+	return atom
+}
+
+// jsonFeedItem is an item of a JSON Feed 1.1 document
+type jsonFeedItem struct {
+	ID            string `json:"id"`
+	URL           string `json:"url"`
+	Title         string `json:"title"`
+	Summary       string `json:"summary"`
+	ContentHTML   string `json:"content_html"`
+	DatePublished string `json:"date_published"`
+}
+
+func generateJSONFeed(title, description string, articles []dummyArticle, page feedPage) string {
+	// JSON Feed only has next_url, archived feeds are linked with it too
+	feed := struct {
+		Version     string         `json:"version"`
+		Title       string         `json:"title"`
+		Description string         `json:"description"`
+		HomePageURL string         `json:"home_page_url"`
+		NextURL     string         `json:"next_url,omitempty"`
+		Items       []jsonFeedItem `json:"items"`
+	}{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       title,
+		Description: description,
+		HomePageURL: "http://example.com",
+		NextURL:     page.NextURL,
+		Items:       []jsonFeedItem{},
+	}
+	for _, article := range articles {
+		feed.Items = append(feed.Items, jsonFeedItem{
+			ID:            article.GUID,
+			URL:           article.GUID,
+			Title:         article.Title,
+			Summary:       article.Description,
+			ContentHTML:   "<p>" + article.Content + "</p>",
+			DatePublished: article.Published.Format(time.RFC3339),
+		})
+	}
+	data, err := json.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		// Plain strings always marshal
+		panic(err)
+	}
+	return string(data)
+}
+
+// Break a feed the ways feeds break in the wild: bytes that aren't valid
+// UTF-8, an unescaped ampersand and a document cut off before its end
+func malformFeed(content string) string {
+	broken := strings.Replace(content, "<title>", "<title>Caf\xe9 \xff\xfe & ", 1)
+	broken = strings.Replace(broken, `"title": "`, `"title": "Caf\xe9 \xff\xfe `, 1)
+	return broken[:len(broken)*2/3]
+}
+
 func feedHandler(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
 
@@ -137,6 +298,43 @@ func feedHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Parse format parameter (default: rss)
+	format := query.Get("format")
+	if format == "" {
+		format = formatRSS
+	}
+	contentType, ok := feedContentTypes[format]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown format %q, use rss, atom or jsonfeed", format), http.StatusBadRequest)
+		return
+	}
+
+	malformed := query.Get("malformed") == "1"
+	dup := query.Get("dup") == "1"
+
+	// Parse pages parameter (default: 1), page is the one requested
+	pageCount := 1
+	if pagesParam := query.Get("pages"); pagesParam != "" {
+		if parsedPages, err := strconv.Atoi(pagesParam); err == nil && parsedPages > 0 {
+			pageCount = parsedPages
+		}
+	}
+	page := feedPage{Number: 1, Archive: query.Get("archive") == "1"}
+	if pageParam := query.Get("page"); pageParam != "" {
+		if parsedPage, err := strconv.Atoi(pageParam); err == nil && parsedPage > 0 && parsedPage <= pageCount {
+			page.Number = parsedPage
+		}
+	}
+	if page.Number < pageCount {
+		next := *r.URL
+		next.Scheme = "http"
+		next.Host = r.Host
+		nextQuery := next.Query()
+		nextQuery.Set("page", strconv.Itoa(page.Number+1))
+		next.RawQuery = nextQuery.Encode()
+		page.NextURL = next.String()
+	}
+
 	// Check for conditional request headers
 	ifNoneMatch := r.Header.Get("If-None-Match")
 	ifModifiedSince := r.Header.Get("If-Modified-Since")
@@ -150,6 +348,16 @@ func feedHandler(w http.ResponseWriter, r *http.Request) {
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Printf("📥 REQUEST: %s %s\n", requestType, r.URL.Path)
 	fmt.Printf("   Feed Title: %s\n", title)
+	fmt.Printf("   Format: %s\n", format)
+	if malformed {
+		fmt.Printf("   💥 Malformed\n")
+	}
+	if dup {
+		fmt.Printf("   👯 Duplicate GUIDs\n")
+	}
+	if pageCount > 1 {
+		fmt.Printf("   📄 Page %d of %d (%s)\n", page.Number, pageCount, page.rel())
+	}
 	fmt.Printf("   User-Agent: %s\n", r.Header.Get("User-Agent"))
 	if ifNoneMatch != "" {
 		fmt.Printf("   If-None-Match: %s\n", ifNoneMatch)
@@ -158,10 +366,15 @@ func feedHandler(w http.ResponseWriter, r *http.Request) {
 		fmt.Printf("   If-Modified-Since: %s\n", ifModifiedSince)
 	}
 
-	// Apply delay
+	// Apply delay, a client that gives up meanwhile gets nothing
 	if delay > 0 {
 		fmt.Printf("   ⏱️  Applying delay: %v\n", delay)
-		time.Sleep(delay)
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			fmt.Printf("   ✖️  Client went away during the delay\n")
+			return
+		}
 	}
 
 	// Generate ETag based on current time (changes every second)
@@ -186,10 +399,13 @@ func feedHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Generate feed content
-	feedContent := generateDummyFeed(title, articleCount)
+	feedContent := generateDummyFeed(title, format, articleCount, dup, page)
+	if malformed {
+		feedContent = malformFeed(feedContent)
+	}
 
 	// Set response headers
-	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	w.Header().Set("Content-Type", contentType)
 	w.Header().Set("ETag", etag)
 	w.Header().Set("Last-Modified", lastModified)
 	w.Header().Set("Cache-Control", "max-age=3600")
@@ -244,6 +460,9 @@ func runFeedTestHarness() error {
 	fmt.Printf("   http://localhost%s/feed.xml?title=Tech+News&articles=10\n", port)
 	fmt.Printf("   http://localhost%s/feed.xml?delay=5&articles=3\n", port)
 	fmt.Printf("   http://localhost%s/feed.xml?status=500\n", port)
+	fmt.Printf("   http://localhost%s/feed.xml?format=jsonfeed&dup=1\n", port)
+	fmt.Printf("   http://localhost%s/feed.xml?format=atom&malformed=1\n", port)
+	fmt.Printf("   http://localhost%s/feed.xml?articles=10&pages=5&archive=1\n", port)
 	fmt.Println()
 	fmt.Println("💡 Testing conditional requests:")
 	fmt.Println("   1. Add the feed URL to NewsGoat")
@@ -256,8 +475,14 @@ func runFeedTestHarness() error {
 	fmt.Println("   articles=N   Number of articles (default: random 1-100)")
 	fmt.Println("   delay=N      Response delay in seconds (default: random 0-5)")
 	fmt.Println("   status=N     HTTP status code (default: 200)")
+	fmt.Println("   format=...   rss, atom or jsonfeed (default: rss)")
+	fmt.Println("   malformed=1  Invalid UTF-8, a bare & and a cut off document")
+	fmt.Println("   dup=1        Every second article repeats the GUID before it")
+	fmt.Println("   pages=N      Link N pages of articles with rel=\"next\" (default: 1)")
+	fmt.Println("   archive=1    Link pages with RFC 5005 rel=\"prev-archive\" instead")
+	fmt.Println("   page=N       Page to serve (default: 1)")
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Println()
 
 	return http.ListenAndServe(port, nil)
-}
\ No newline at end of file
+}
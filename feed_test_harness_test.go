@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/tasks"
+)
+
+// newHarnessRefresh starts a task manager refreshing feeds from the feed test
+// harness, query is added to the URL of the feed and historyPages older pages
+// are fetched
+func newHarnessRefresh(t *testing.T, query string, historyPages int) (tasks.Manager, *database.Queries, int64) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(feedHandler))
+	t.Cleanup(server.Close)
+
+	schema, err := os.ReadFile("sql/schema.sql")
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	db, queries, err := database.Open(filepath.Join(t.TempDir(), "newsgoat.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	if _, err := db.Exec(string(schema)); err != nil {
+		t.Fatalf("failed to create tables: %v", err)
+	}
+
+	feedManager := feeds.NewManager(db, queries)
+	feedManager.SetHistoryPages(historyPages)
+	url := server.URL + "/feed.xml?articles=3&" + query
+	if err := feedManager.AddFeedWithoutFetching(url); err != nil {
+		t.Fatalf("AddFeedWithoutFetching() error = %v", err)
+	}
+	feed, err := queries.GetFeedByURL(context.Background(), url)
+	if err != nil {
+		t.Fatalf("GetFeedByURL() error = %v", err)
+	}
+
+	taskManager := tasks.NewManager(1)
+	if err := taskManager.RegisterHandler(tasks.NewFeedRefreshHandler(feedManager, nil)); err != nil {
+		t.Fatalf("RegisterHandler() error = %v", err)
+	}
+	if err := taskManager.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := taskManager.AddTask(tasks.CreateFeedRefreshTask(feed.ID, url)); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	return taskManager, queries, feed.ID
+}
+
+// waitForTaskEvent returns the first event of the given type
+func waitForTaskEvent(t *testing.T, events <-chan tasks.TaskEvent, eventType tasks.TaskEventType) tasks.TaskEvent {
+	t.Helper()
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case event := <-events:
+			if event.Type == eventType {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("no %s event", eventType)
+		}
+	}
+}
+
+func TestFeedRefreshHarness(t *testing.T) {
+	taskManager, queries, feedID := newHarnessRefresh(t, "delay=0", 0)
+	defer func() { _ = taskManager.Stop() }()
+
+	event := waitForTaskEvent(t, taskManager.Subscribe(), tasks.TaskEventCompleted)
+	if event.Error != "" {
+		t.Fatalf("refresh failed: %s", event.Error)
+	}
+	items, err := queries.GetItemsWithReadStatus(context.Background(), feedID)
+	if err != nil {
+		t.Fatalf("GetItemsWithReadStatus() error = %v", err)
+	}
+	if len(items) != 3 {
+		t.Errorf("refresh saved %d items, want 3", len(items))
+	}
+}
+
+func TestStopAbortsFeedRefresh(t *testing.T) {
+	taskManager, queries, feedID := newHarnessRefresh(t, "delay=5", 0)
+	events := taskManager.Subscribe()
+	waitForTaskEvent(t, events, tasks.TaskEventStarted)
+
+	// The events channel is closed once the workers are done
+	start := time.Now()
+	if err := taskManager.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	for range events {
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("refresh took %v to stop, want it aborted right away", elapsed)
+	}
+
+	feed, err := queries.GetFeed(context.Background(), feedID)
+	if err != nil {
+		t.Fatalf("GetFeed() error = %v", err)
+	}
+	if feed.LastError.Valid {
+		t.Errorf("aborted refresh recorded error %q", feed.LastError.String)
+	}
+}
+
+func TestFeedRefreshHarnessFormats(t *testing.T) {
+	for _, format := range []string{formatRSS, formatAtom, formatJSONFeed} {
+		t.Run(format, func(t *testing.T) {
+			taskManager, queries, feedID := newHarnessRefresh(t, "delay=0&format="+format, 0)
+			defer func() { _ = taskManager.Stop() }()
+
+			event := waitForTaskEvent(t, taskManager.Subscribe(), tasks.TaskEventCompleted)
+			if event.Error != "" {
+				t.Fatalf("refresh failed: %s", event.Error)
+			}
+			items, err := queries.GetItemsWithReadStatus(context.Background(), feedID)
+			if err != nil {
+				t.Fatalf("GetItemsWithReadStatus() error = %v", err)
+			}
+			if len(items) != 3 {
+				t.Errorf("refresh saved %d items, want 3", len(items))
+			}
+		})
+	}
+}
+
+func TestFeedRefreshHarnessMalformed(t *testing.T) {
+	for _, format := range []string{formatRSS, formatAtom, formatJSONFeed} {
+		t.Run(format, func(t *testing.T) {
+			taskManager, queries, feedID := newHarnessRefresh(t, "delay=0&malformed=1&format="+format, 0)
+			defer func() { _ = taskManager.Stop() }()
+
+			waitForTaskEvent(t, taskManager.Subscribe(), tasks.TaskEventFailed)
+			feed, err := queries.GetFeed(context.Background(), feedID)
+			if err != nil {
+				t.Fatalf("GetFeed() error = %v", err)
+			}
+			if !feed.LastError.Valid {
+				t.Error("malformed feed recorded no error")
+			}
+		})
+	}
+}
+
+func TestFeedRefreshHarnessDuplicateGUIDs(t *testing.T) {
+	taskManager, queries, feedID := newHarnessRefresh(t, "delay=0&dup=1", 0)
+	defer func() { _ = taskManager.Stop() }()
+
+	event := waitForTaskEvent(t, taskManager.Subscribe(), tasks.TaskEventCompleted)
+	if event.Error != "" {
+		t.Fatalf("refresh failed: %s", event.Error)
+	}
+	items, err := queries.GetItemsWithReadStatus(context.Background(), feedID)
+	if err != nil {
+		t.Fatalf("GetItemsWithReadStatus() error = %v", err)
+	}
+	// The second article repeats the GUID of the first
+	if len(items) != 2 {
+		t.Errorf("refresh saved %d items, want 2", len(items))
+	}
+}
+
+func TestFeedRefreshHarnessPages(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		historyPages int
+		want         int
+	}{
+		{"not followed", "pages=4", 0, 3},
+		{"next", "pages=4", 2, 9},
+		{"prev-archive", "pages=4&archive=1", 2, 9},
+		{"jsonfeed", "pages=4&format=jsonfeed", 2, 9},
+		{"atom last page", "pages=2&format=atom", 5, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskManager, queries, feedID := newHarnessRefresh(t, "delay=0&"+tt.query, tt.historyPages)
+			defer func() { _ = taskManager.Stop() }()
+
+			event := waitForTaskEvent(t, taskManager.Subscribe(), tasks.TaskEventCompleted)
+			if event.Error != "" {
+				t.Fatalf("refresh failed: %s", event.Error)
+			}
+			items, err := queries.GetItemsWithReadStatus(context.Background(), feedID)
+			if err != nil {
+				t.Fatalf("GetItemsWithReadStatus() error = %v", err)
+			}
+			if len(items) != tt.want {
+				t.Errorf("refresh saved %d items, want %d", len(items), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package bugreport
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/logging"
+	"github.com/jarv/newsgoat/internal/version"
+)
+
+// maxLogs is the number of most recent warnings and errors in a bundle
+const maxLogs = 50
+
+// Bundle is what an issue report needs to reproduce a problem, everything in
+// it is redacted so it can be pasted into a public issue
+type Bundle struct {
+	Version     string
+	OS          string
+	Arch        string
+	GoVersion   string
+	GeneratedAt time.Time
+	Since       time.Duration
+	Settings    []database.Setting
+	Feeds       []database.Feed // The feed the report is about, or every feed whose last refresh failed
+	Logs        []logging.ExportEntry
+
+	secrets []string // Masked on top of what logging.Redact masks
+}
+
+// Collect builds a bundle, feedURL selects the feed the report is about and
+// since limits the logs to recent ones when it isn't zero. The secrets, such
+// as the credentials of the URLs file, are masked wherever they show up.
+func Collect(ctx context.Context, queries *database.Queries, feedURL string, since time.Duration, secrets []string) (Bundle, error) {
+	bundle := Bundle{
+		Version:     version.GetVersion(),
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+		GoVersion:   runtime.Version(),
+		GeneratedAt: time.Now(),
+		Since:       since,
+		secrets:     secrets,
+	}
+
+	settings, err := queries.GetAllSettings(ctx)
+	if err != nil {
+		return bundle, fmt.Errorf("failed to read settings: %w", err)
+	}
+	for _, setting := range settings {
+		setting.Value = redactSetting(setting.Key, setting.Value, secrets)
+		bundle.Settings = append(bundle.Settings, setting)
+	}
+
+	if feedURL != "" {
+		feed, err := queries.GetFeedByURL(ctx, feedURL)
+		if errors.Is(err, sql.ErrNoRows) {
+			return bundle, fmt.Errorf("no feed with URL %s, use the URL from the URLs file", feedURL)
+		}
+		if err != nil {
+			return bundle, fmt.Errorf("failed to read feed: %w", err)
+		}
+		bundle.Feeds = []database.Feed{feed}
+	} else {
+		feeds, err := queries.ListAllFeeds(ctx)
+		if err != nil {
+			return bundle, fmt.Errorf("failed to read feeds: %w", err)
+		}
+		for _, feed := range feeds {
+			if feed.LastError.Valid && feed.LastError.String != "" {
+				bundle.Feeds = append(bundle.Feeds, feed)
+			}
+		}
+	}
+
+	messages, err := queries.GetAllLogMessages(ctx)
+	if err != nil {
+		return bundle, fmt.Errorf("failed to read log messages: %w", err)
+	}
+	filter := logging.ExportFilter{Level: slog.LevelWarn}
+	if since > 0 {
+		filter.Since = bundle.GeneratedAt.Add(-since)
+	}
+	for _, message := range messages {
+		if filter.Match(message) {
+			bundle.Logs = append(bundle.Logs, logging.NewExportEntry(message))
+		}
+	}
+	if len(bundle.Logs) > maxLogs {
+		bundle.Logs = bundle.Logs[len(bundle.Logs)-maxLogs:]
+	}
+
+	return bundle, nil
+}
+
+// redactSetting hides credentials and secrets inside other settings, such as
+// a proxy password
+func redactSetting(key, value string, secrets []string) string {
+	if config.IsSecretSetting(key) && value != "" {
+		return logging.Redacted
+	}
+	return logging.RedactWith(value, secrets)
+}
+
+// Markdown renders the bundle for pasting into a GitHub issue
+func (b Bundle) Markdown() string {
+	var sb strings.Builder
+
+	sb.WriteString("## NewsGoat issue report\n\n")
+	sb.WriteString(fmt.Sprintf("- Version: %s\n", b.Version))
+	sb.WriteString(fmt.Sprintf("- OS: %s/%s\n", b.OS, b.Arch))
+	sb.WriteString(fmt.Sprintf("- Go: %s\n", b.GoVersion))
+	sb.WriteString(fmt.Sprintf("- Generated: %s\n", b.GeneratedAt.UTC().Format(time.RFC3339)))
+
+	sb.WriteString("\n### Settings\n\n")
+	if len(b.Settings) == 0 {
+		sb.WriteString("All settings are at their defaults.\n")
+	} else {
+		sb.WriteString("| Setting | Value |\n|---------|-------|\n")
+		for _, setting := range b.Settings {
+			sb.WriteString(fmt.Sprintf("| %s | `%s` |\n", setting.Key, escapeCell(setting.Value)))
+		}
+	}
+
+	if len(b.Feeds) == 0 {
+		sb.WriteString("\n### Feeds\n\nNo feed failed on its last refresh.\n")
+	}
+	for _, feed := range b.Feeds {
+		sb.WriteString("\n### Feed: " + logging.RedactWith(feed.Title, b.secrets) + "\n\n")
+		sb.WriteString(fmt.Sprintf("- URL: %s\n", logging.RedactWith(feed.Url, b.secrets)))
+		sb.WriteString(fmt.Sprintf("- Last updated: %s\n", formatNullTime(feed.LastUpdated)))
+		if feed.LastError.Valid && feed.LastError.String != "" {
+			sb.WriteString(fmt.Sprintf("- Last error: %s (%s)\n", logging.RedactWith(feed.LastError.String, b.secrets), formatNullTime(feed.LastErrorTime)))
+		}
+		if feed.Etag.Valid {
+			sb.WriteString(fmt.Sprintf("- ETag: %s\n", feed.Etag.String))
+		}
+		if feed.LastModified.Valid {
+			sb.WriteString(fmt.Sprintf("- Last-Modified: %s\n", feed.LastModified.String))
+		}
+		if feed.CacheControlMaxAge.Valid {
+			sb.WriteString(fmt.Sprintf("- Cache-Control max-age: %ds\n", feed.CacheControlMaxAge.Int64))
+		}
+		if feed.ReloadInterval > 0 {
+			sb.WriteString(fmt.Sprintf("- Reload interval: %dm\n", feed.ReloadInterval))
+		}
+		sb.WriteString(fmt.Sprintf("- Full text: %t, enrich: %t, visible: %t\n", feed.FullText, feed.Enrich, feed.Visible))
+	}
+
+	sb.WriteString("\n### Recent warnings and errors")
+	if b.Since > 0 {
+		sb.WriteString(fmt.Sprintf(" (last %s)", formatDuration(b.Since)))
+	}
+	sb.WriteString("\n\n")
+	if len(b.Logs) == 0 {
+		sb.WriteString("None.\n")
+	} else {
+		sb.WriteString("<details>\n<summary>" + fmt.Sprintf("%d log messages", len(b.Logs)) + "</summary>\n\n```\n")
+		for _, entry := range b.Logs {
+			sb.WriteString(fmt.Sprintf("%s %-5s %s", entry.Time.UTC().Format(time.RFC3339), entry.Level, logging.RedactWith(entry.Message, b.secrets)))
+			if errMsg, ok := entry.Attributes["error"]; ok {
+				sb.WriteString(" error=" + logging.RedactWith(fmt.Sprint(errMsg), b.secrets))
+			}
+			if url, ok := entry.Attributes["url"]; ok {
+				sb.WriteString(" url=" + logging.RedactWith(fmt.Sprint(url), b.secrets))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("```\n\n</details>\n")
+	}
+
+	return sb.String()
+}
+
+// formatDuration writes 24h rather than 24h0m0s
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+func formatNullTime(t sql.NullTime) string {
+	if !t.Valid {
+		return "never"
+	}
+	return t.Time.UTC().Format(time.RFC3339)
+}
+
+// escapeCell keeps a value from breaking the markdown table
+func escapeCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	value = strings.ReplaceAll(value, "`", "'")
+	return strings.ReplaceAll(value, "\n", " ")
+}
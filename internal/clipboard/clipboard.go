@@ -0,0 +1,109 @@
+// Package clipboard copies text to the clipboard, with the OSC 52 escape
+// sequence of the terminal so it works over SSH and with the clipboard
+// command of the platform.
+package clipboard
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// maxOSC52Size is the longest escape sequence sent, terminals drop larger
+// ones without telling
+const maxOSC52Size = 100000
+
+// ErrTooLarge is returned over SSH for text too large for the terminal's
+// clipboard
+var ErrTooLarge = errors.New("text too large to copy over SSH")
+
+// Copy puts text on the clipboard. The terminal is asked to copy it with
+// OSC 52, and unless NewsGoat runs over SSH the platform's clipboard command
+// copies it too, for terminals that don't support OSC 52.
+func Copy(text string) error {
+	return copyTo(text, os.Stdout, os.Getenv)
+}
+
+func copyTo(text string, out io.Writer, getenv func(string) string) error {
+	sent := false
+	if sequence := OSC52(text, getenv); len(sequence) <= maxOSC52Size {
+		if _, err := io.WriteString(out, sequence); err != nil {
+			logging.Warn("Failed to write OSC 52 sequence", "error", err)
+		} else {
+			sent = true
+		}
+	}
+
+	if overSSH(getenv) {
+		// The clipboard commands would copy to the remote machine
+		if !sent {
+			return ErrTooLarge
+		}
+		return nil
+	}
+	if err := runCommand(text, getenv); err != nil && !sent {
+		return err
+	}
+	return nil
+}
+
+// OSC52 returns the escape sequence that asks the terminal to copy text.
+// Inside tmux and screen it is wrapped to be passed through to the terminal.
+func OSC52(text string, getenv func(string) string) string {
+	sequence := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	switch {
+	case getenv("TMUX") != "":
+		return "\x1bPtmux;" + strings.ReplaceAll(sequence, "\x1b", "\x1b\x1b") + "\x1b\\"
+	case strings.HasPrefix(getenv("TERM"), "screen"):
+		return "\x1bP" + sequence + "\x1b\\"
+	}
+	return sequence
+}
+
+func overSSH(getenv func(string) string) bool {
+	return getenv("SSH_TTY") != "" || getenv("SSH_CONNECTION") != ""
+}
+
+// runCommand writes text to the system clipboard with the platform's
+// clipboard command
+func runCommand(text string, getenv func(string) string) error {
+	var candidates [][]string
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "windows":
+		candidates = [][]string{{"clip"}}
+	default:
+		if getenv("WAYLAND_DISPLAY") != "" {
+			candidates = append(candidates, []string{"wl-copy"})
+		}
+		candidates = append(candidates, []string{"xclip", "-selection", "clipboard"}, []string{"xsel", "--clipboard", "--input"})
+	}
+
+	for _, candidate := range candidates {
+		path, err := exec.LookPath(candidate[0])
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, candidate[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			logging.Error("Clipboard command failed", "command", candidate[0], "error", err, "output", string(output))
+			return fmt.Errorf("%s: %w", candidate[0], err)
+		}
+		return nil
+	}
+
+	names := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		names = append(names, candidate[0])
+	}
+	return fmt.Errorf("no clipboard command found, install %s", strings.Join(names, " or "))
+}
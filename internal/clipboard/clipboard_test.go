@@ -0,0 +1,50 @@
+package clipboard
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestOSC52(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want string
+	}{
+		{map[string]string{"TERM": "xterm-256color"}, "\x1b]52;c;aGk=\a"},
+		{map[string]string{"TMUX": "/tmp/tmux-1000/default,1,0"}, "\x1bPtmux;\x1b\x1b]52;c;aGk=\a\x1b\\"},
+		{map[string]string{"TERM": "screen-256color"}, "\x1bP\x1b]52;c;aGk=\a\x1b\\"},
+	}
+	for _, tt := range tests {
+		getenv := func(key string) string { return tt.env[key] }
+		if got := OSC52("hi", getenv); got != tt.want {
+			t.Errorf("OSC52(%v) = %q, want %q", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestCopyOverSSH(t *testing.T) {
+	getenv := func(key string) string {
+		if key == "SSH_TTY" {
+			return "/dev/pts/0"
+		}
+		return ""
+	}
+
+	var out strings.Builder
+	if err := copyTo("https://example.com/post", &out, getenv); err != nil {
+		t.Fatalf("copyTo() error = %v", err)
+	}
+	if want := OSC52("https://example.com/post", getenv); out.String() != want {
+		t.Errorf("copyTo() wrote %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	err := copyTo(strings.Repeat("x", maxOSC52Size), &out, getenv)
+	if !errors.Is(err, ErrTooLarge) {
+		t.Errorf("copyTo() of large text error = %v, want ErrTooLarge", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("copyTo() of large text wrote %d bytes", out.Len())
+	}
+}
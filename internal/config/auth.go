@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jarv/newsgoat/internal/secrets"
+)
+
+// Schemes of the auth option
+const (
+	// AuthBasic sends HTTP basic auth, basic:user:password
+	AuthBasic = "basic"
+	// AuthBearer sends an Authorization: Bearer header, bearer:token
+	AuthBearer = "bearer"
+	// AuthToken adds the feed_token query parameter GitHub and GitLab use, token:value
+	AuthToken = "token"
+)
+
+// authEnv is the auth option shorthand for a bearer token from the
+// environment, env:NAME
+const authEnv = "env"
+
+// FeedAuth are the credentials a feed is fetched with
+type FeedAuth struct {
+	Scheme   string // AuthBasic, AuthBearer or AuthToken
+	Username string // Only for AuthBasic
+	Secret   string // Password or token
+}
+
+// Secrets returns the values of the credentials that must not be logged
+func (a FeedAuth) Secrets() []string {
+	if a.Secret == "" {
+		return nil
+	}
+	return []string{a.Secret}
+}
+
+// expandSecret reads a value starting with $ from that environment variable
+// and keyring:name from the OS keychain, so secrets don't have to be stored
+// in the URLs file
+func expandSecret(value string) (string, error) {
+	if strings.HasPrefix(value, "$") {
+		return os.Getenv(strings.Trim(value[1:], "{}")), nil
+	}
+	return secrets.Resolve(value)
+}
+
+// ParseFeedAuth parses the auth option: basic:user:password,
+// bearer:token, token:value or env:NAME for a bearer token read from the
+// environment. Passwords and tokens starting with $ are read from that
+// environment variable, keyring:name from the OS keychain.
+func ParseFeedAuth(value string) (FeedAuth, error) {
+	scheme, rest, ok := strings.Cut(value, ":")
+	scheme = strings.ToLower(strings.TrimSpace(scheme))
+	if !ok || rest == "" {
+		return FeedAuth{}, fmt.Errorf("invalid auth, use basic:user:password, bearer:token, token:value or env:NAME")
+	}
+
+	var auth FeedAuth
+	switch scheme {
+	case AuthBasic:
+		username, password, ok := strings.Cut(rest, ":")
+		if !ok || username == "" {
+			// The value isn't quoted in the error, it holds a password
+			return FeedAuth{}, fmt.Errorf("invalid basic auth, expected basic:user:password")
+		}
+		password, err := expandSecret(password)
+		if err != nil {
+			return FeedAuth{}, err
+		}
+		auth = FeedAuth{Scheme: AuthBasic, Username: username, Secret: password}
+	case AuthBearer, AuthToken:
+		secret, err := expandSecret(rest)
+		if err != nil {
+			return FeedAuth{}, err
+		}
+		auth = FeedAuth{Scheme: scheme, Secret: secret}
+	case authEnv:
+		auth = FeedAuth{Scheme: AuthBearer, Secret: os.Getenv(rest)}
+	default:
+		return FeedAuth{}, fmt.Errorf("invalid auth scheme %q, use basic, bearer, token or env", scheme)
+	}
+	if auth.Secret == "" && auth.Scheme != AuthBasic {
+		return FeedAuth{}, fmt.Errorf("empty %s auth, is its environment variable set?", auth.Scheme)
+	}
+	return auth, nil
+}
+
+// Auth returns the credentials set for the feed with the auth option, or its
+// token option as token auth, and whether it sets any
+func (e URLEntry) Auth() (FeedAuth, bool, error) {
+	if value, ok := e.Option(OptionAuth); ok {
+		auth, err := ParseFeedAuth(value)
+		if err != nil {
+			return FeedAuth{}, false, err
+		}
+		return auth, true, nil
+	}
+	if value, ok := e.Option(OptionToken); ok {
+		token, err := expandSecret(value)
+		if err != nil || token == "" {
+			return FeedAuth{}, false, err
+		}
+		return FeedAuth{Scheme: AuthToken, Secret: token}, true, nil
+	}
+	return FeedAuth{}, false, nil
+}
+
+// FeedAuths returns the credentials of the entries that set any, keyed by
+// URL. Entries with an invalid auth option are returned in errs.
+func FeedAuths(entries []URLEntry) (map[string]FeedAuth, []error) {
+	auths := make(map[string]FeedAuth)
+	var errs []error
+	for _, entry := range entries {
+		auth, ok, err := entry.Auth()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.URL, err))
+			continue
+		}
+		if ok {
+			auths[entry.URL] = auth
+		}
+	}
+	return auths, errs
+}
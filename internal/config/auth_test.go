@@ -0,0 +1,46 @@
+package config
+
+import "testing"
+
+func TestParseFeedAuth(t *testing.T) {
+	t.Setenv("FEED_PASSWORD", "from-env")
+	t.Setenv("MY_TOKEN", "token-env")
+
+	tests := []struct {
+		value   string
+		want    FeedAuth
+		wantErr bool
+	}{
+		{"basic:me:secret", FeedAuth{Scheme: AuthBasic, Username: "me", Secret: "secret"}, false},
+		{"basic:me:pa:ss", FeedAuth{Scheme: AuthBasic, Username: "me", Secret: "pa:ss"}, false},
+		{"basic:me:$FEED_PASSWORD", FeedAuth{Scheme: AuthBasic, Username: "me", Secret: "from-env"}, false},
+		{"bearer:abc", FeedAuth{Scheme: AuthBearer, Secret: "abc"}, false},
+		{"bearer:${MY_TOKEN}", FeedAuth{Scheme: AuthBearer, Secret: "token-env"}, false},
+		{"env:MY_TOKEN", FeedAuth{Scheme: AuthBearer, Secret: "token-env"}, false},
+		{"token:abc", FeedAuth{Scheme: AuthToken, Secret: "abc"}, false},
+		{"env:UNSET_FEED_TOKEN", FeedAuth{}, true},
+		{"basic:me", FeedAuth{}, true},
+		{"digest:me:secret", FeedAuth{}, true},
+		{"abc", FeedAuth{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFeedAuth(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseFeedAuth(%q) = %+v, %v, want %+v, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFeedAuthsPrefersAuthOption(t *testing.T) {
+	entries := []URLEntry{
+		{URL: "https://github.com/work/repo/commits/main.atom", Options: map[string]string{OptionToken: "old", OptionAuth: "token:new"}},
+		{URL: "https://example.com/feed.xml", Options: map[string]string{OptionAuth: "digest:x"}},
+	}
+	auths, errs := FeedAuths(entries)
+	if auths[entries[0].URL].Secret != "new" {
+		t.Errorf("auth = %+v, want the auth option over the token option", auths[entries[0].URL])
+	}
+	if len(errs) != 1 {
+		t.Errorf("FeedAuths() errors = %v, want the invalid scheme", errs)
+	}
+}
@@ -17,10 +17,102 @@ type Config struct {
 	HighlightStyle      string
 	SpinnerType         string
 	ShowReadFeeds       bool
-	UnreadOnTop         bool // Show feeds with unread items at the top
-	CheckForUpdates     bool // Check for updates on launch
+	UnreadOnTop         bool   // Show feeds with unread items at the top
+	CheckForUpdates     bool   // Check for updates on launch
+	ClusterStories      bool   // Group items covering the same story across feeds
+	HotKeywords         string // Comma-separated keywords that boost items in the hot items ranking
+	HighlightKeywords   string // Comma-separated keywords colored in item titles and articles
+	ReadingExport       string // Items in the shared reading log: "starred", "read" or "" when disabled
+	ReadingExportPath   string // File the reading log is written to, .json for JSON and HTML otherwise
+	ReadingExportGist   string // ID of a GitHub gist the reading log is published to
+	HTTPProxy           string // Proxy for feed requests, e.g. http://proxy:3128 or socks5://127.0.0.1:1080
+	UserAgent           string // Replaces the default User-Agent when set
+	RequestHeaders      string // Headers sent with every feed request, "Name: value; Name2: value"
+	FeedListLayout      string // "single" for one feed per line or "columns" to fill wide terminals
+	PipeCommand         string // Shell command the current article is piped to with |
+	PipeFormat          string // What is piped: "markdown" or the raw "html"
+	OldItemDays         int    // Items older than this many days use the theme's old item color (0 = disabled)
+	DebugCategories     string // Comma-separated debug log categories, e.g. "http,tasks" or "all"
+	ReadLater           string // Read-later service: "wallabag", "pocket", "instapaper", "webhook" or "" when disabled
+	ReadLaterURL        string // Wallabag instance or webhook URL
+	ReadLaterAuth       string // Service credentials, the format depends on the service
+	MaxItemsPerFeed     int    // Older items beyond this many per feed are pruned (0 = unlimited)
+	MaxItemAgeDays      int    // Items older than this many days are pruned (0 = keep forever)
+	PruneKeep           string // Comma-separated items pruning never deletes: "unread", "starred"
+	WebSubListen        string // Address the WebSub callback listener binds, e.g. ":8089" ("" = disabled)
+	WebSubCallbackURL   string // Public URL hubs reach the listener at, e.g. https://example.com/websub
+	LogMaxMessages      int    // Older log messages beyond this many are deleted (0 = unlimited)
+	LogMaxAgeDays       int    // Log messages older than this many days are deleted (0 = keep forever)
+	Sync                string // Sync service: "miniflux", "greader" or "" when disabled
+	SyncURL             string // URL of the sync server
+	SyncToken           string // API token of the sync server, username:password for greader
+	RTLDisplay          string // "reorder" to lay out right-to-left text, "terminal" when the terminal does bidi itself
+	Notify              string // Feeds that notify about new items: "all", comma-separated folders or "" when disabled
+	Images              string // Terminal graphics article images are drawn with: "auto", "kitty", "iterm2", "sixel" or "" when disabled
+	ArticleMaxWidth     int    // Most columns article text is wrapped at (0 = terminal width)
+	ItemListFormat      string // Item list rows, e.g. "%D %-16T %a %t" ("" = date and title, with the feed in aggregated lists)
+	DateFormat          string // Dates of items: "relative" like "3h", a Go time layout like "Jan 2 15:04" or "" for month and day
+	DeadFeedThreshold   int    // Failed refreshes in a row after which a feed is shown as dead (0 = never)
+	HostRequestRate     int    // Requests per second started to the same host (0 = no limit)
+	HostMaxParallel     int    // Requests to the same host running at once (0 = no limit)
+	FeedHistoryPages    int    // Older pages of a paginated feed fetched on its first refresh (0 = none)
+	UpdateRedirects     bool   // Move feeds that redirect permanently to their new URL in the URLs file
+	HideDuplicates      bool   // Show articles republished by several feeds once in aggregated lists
+	MarkReadOnOpen      bool   // Mark items read when their link is opened in the browser from the item list
+	MarkSkippedRead     bool   // Mark unread items the cursor moved past read when leaving the item list
+	ShareCommands       string // Share menu commands of the article view, "Name: command; Name2: command"
+	RefreshRetries      int    // Times a failed feed refresh is tried again (0 = never)
+	RefreshRetryDelay   int    // Minutes before the first retry of a failed refresh, doubled for every later one
+	PruneSchedule       string // When old items are pruned, an interval like "6h" or a cron expression ("" = only after refreshes)
+	UpdateCheckSchedule string // When to check for updates while running, an interval or a cron expression ("" = only on launch)
+	FeedIcons           string // "off", "glyph" for a dot in the color of the site's favicon or "nerdfont" for Nerd Font icons
+	Macros              string // Actions run by , followed by a key, "m: action, action; x: action"
+	ExportDir           string // Directory articles are exported to, ~/ is the home directory
+	ExportFileName      string // File name of exported articles with {feed}, {title}, {author} and {date} placeholders
+	ExportPDFCommand    string // Shell command converting the {input} HTML of an article to the {output} PDF ("" = no PDF export)
 }
 
+// DefaultExportFileName is the file name template of exported articles
+const DefaultExportFileName = "{date} {feed} - {title}"
+
+// Feed list layouts
+const (
+	FeedListLayoutSingle  = "single"
+	FeedListLayoutColumns = "columns"
+)
+
+// Feed icon styles
+const (
+	FeedIconsOff      = "off"
+	FeedIconsGlyph    = "glyph"
+	FeedIconsNerdFont = "nerdfont"
+)
+
+// Pipe formats
+const (
+	PipeFormatMarkdown = "markdown"
+	PipeFormatHTML     = "html"
+)
+
+// Ways right-to-left text is displayed
+const (
+	RTLDisplayReorder  = "reorder"
+	RTLDisplayTerminal = "terminal"
+)
+
+// DateFormatRelative shows how long ago items were published, other date
+// formats are Go time layouts
+const DateFormatRelative = "relative"
+
+// Terminal graphics article images are drawn with, "auto" detects the
+// terminal's
+const (
+	ImagesAuto   = "auto"
+	ImagesKitty  = "kitty"
+	ImagesITerm2 = "iterm2"
+	ImagesSixel  = "sixel"
+)
+
 // Setting keys
 const (
 	KeyReloadConcurrency   = "reload_concurrency"
@@ -34,8 +126,71 @@ const (
 	KeyShowReadFeeds       = "show_read_feeds"
 	KeyUnreadOnTop         = "unread_on_top"
 	KeyCheckForUpdates     = "check_for_updates"
+	KeyClusterStories      = "cluster_stories"
+	KeyHotKeywords         = "hot_keywords"
+	KeyHighlightKeywords   = "highlight_keywords"
+	KeyReadingExport       = "reading_export"
+	KeyReadingExportPath   = "reading_export_path"
+	KeyReadingExportGist   = "reading_export_gist"
+	KeyHTTPProxy           = "http_proxy"
+	KeyUserAgent           = "user_agent"
+	KeyRequestHeaders      = "request_headers"
+	KeyFeedListLayout      = "feed_list_layout"
+	KeyPipeCommand         = "pipe_command"
+	KeyPipeFormat          = "pipe_format"
+	KeyOldItemDays         = "old_item_days"
+	KeyDebugCategories     = "debug_categories"
+	KeyReadLater           = "read_later"
+	KeyReadLaterURL        = "read_later_url"
+	KeyReadLaterAuth       = "read_later_auth"
+	KeyMaxItemsPerFeed     = "max_items_per_feed"
+	KeyMaxItemAgeDays      = "max_item_age_days"
+	KeyPruneKeep           = "prune_keep"
+	KeyWebSubListen        = "websub_listen"
+	KeyWebSubCallbackURL   = "websub_callback_url"
+	KeyLogMaxMessages      = "log_max_messages"
+	KeyLogMaxAgeDays       = "log_max_age_days"
+	KeySync                = "sync"
+	KeySyncURL             = "sync_url"
+	KeySyncToken           = "sync_token"
+	KeyRTLDisplay          = "rtl_display"
+	KeyNotify              = "notify"
+	KeyImages              = "images"
+	KeyArticleMaxWidth     = "article_max_width"
+	KeyItemListFormat      = "item_list_format"
+	KeyDateFormat          = "date_format"
+	KeyDeadFeedThreshold   = "dead_feed_threshold"
+	KeyHostRequestRate     = "host_request_rate"
+	KeyHostMaxParallel     = "host_max_parallel"
+	KeyFeedHistoryPages    = "feed_history_pages"
+	KeyUpdateRedirects     = "update_redirects"
+	KeyHideDuplicates      = "hide_duplicates"
+	KeyMarkReadOnOpen      = "mark_read_on_open"
+	KeyMarkSkippedRead     = "mark_skipped_read"
+	KeyShareCommands       = "share_commands"
+	KeyRefreshRetries      = "refresh_retries"
+	KeyRefreshRetryDelay   = "refresh_retry_delay"
+	KeyPruneSchedule       = "prune_schedule"
+	KeyUpdateCheckSchedule = "update_check_schedule"
+	KeyFeedIcons           = "feed_icons"
+	KeyMacros              = "macros"
+	KeyExportDir           = "export_dir"
+	KeyExportFileName      = "export_file_name"
+	KeyExportPDFCommand    = "export_pdf_command"
 )
 
+// secretSettings hold credentials, reports only say whether they are set
+var secretSettings = map[string]bool{
+	KeyRequestHeaders: true,
+	KeyReadLaterAuth:  true,
+	KeySyncToken:      true,
+}
+
+// IsSecretSetting reports whether a setting holds credentials
+func IsSecretSetting(key string) bool {
+	return secretSettings[key]
+}
+
 func GetDefaultConfig() Config {
 	return Config{
 		ReloadConcurrency:   4,
@@ -49,6 +204,57 @@ func GetDefaultConfig() Config {
 		ShowReadFeeds:       true,
 		UnreadOnTop:         true, // Show unread feeds at top by default
 		CheckForUpdates:     true, // Check for updates on launch by default
+		ClusterStories:      false,
+		HotKeywords:         "",
+		HighlightKeywords:   "",
+		ReadingExport:       "",
+		ReadingExportPath:   "",
+		ReadingExportGist:   "",
+		HTTPProxy:           "",
+		UserAgent:           "",
+		RequestHeaders:      "",
+		FeedListLayout:      FeedListLayoutSingle,
+		PipeCommand:         "",
+		PipeFormat:          PipeFormatMarkdown,
+		OldItemDays:         0,
+		DebugCategories:     "",
+		ReadLater:           "",
+		ReadLaterURL:        "",
+		ReadLaterAuth:       "",
+		MaxItemsPerFeed:     0,
+		MaxItemAgeDays:      0,
+		PruneKeep:           PruneKeepUnread + "," + PruneKeepStarred,
+		WebSubListen:        "",
+		WebSubCallbackURL:   "",
+		LogMaxMessages:      10000,
+		LogMaxAgeDays:       30,
+		Sync:                "",
+		SyncURL:             "",
+		SyncToken:           "",
+		RTLDisplay:          RTLDisplayReorder,
+		Notify:              "",
+		Images:              "",
+		ArticleMaxWidth:     0,
+		ItemListFormat:      "",
+		DateFormat:          "",
+		DeadFeedThreshold:   10,
+		HostRequestRate:     2,
+		HostMaxParallel:     2,
+		FeedHistoryPages:    0,
+		UpdateRedirects:     false,
+		HideDuplicates:      false,
+		MarkReadOnOpen:      false,
+		MarkSkippedRead:     false,
+		ShareCommands:       "",
+		RefreshRetries:      3,
+		RefreshRetryDelay:   1,
+		PruneSchedule:       "0 3 * * *",
+		UpdateCheckSchedule: "@daily",
+		FeedIcons:           FeedIconsOff,
+		Macros:              "",
+		ExportDir:           "~/Downloads",
+		ExportFileName:      DefaultExportFileName,
+		ExportPDFCommand:    "",
 	}
 }
 
@@ -117,6 +323,292 @@ func LoadConfig(queries *database.Queries) (Config, error) {
 		config.CheckForUpdates = (val == "true" || val == "yes")
 	}
 
+	// Load cluster stories
+	if val, err := getSetting(queries, ctx, KeyClusterStories); err == nil {
+		config.ClusterStories = (val == "true" || val == "yes")
+	}
+
+	// Load hot keywords
+	if val, err := getSetting(queries, ctx, KeyHotKeywords); err == nil {
+		config.HotKeywords = val
+	}
+
+	// Load highlight keywords
+	if val, err := getSetting(queries, ctx, KeyHighlightKeywords); err == nil {
+		config.HighlightKeywords = val
+	}
+
+	// Load reading export
+	if val, err := getSetting(queries, ctx, KeyReadingExport); err == nil {
+		config.ReadingExport = val
+	}
+
+	// Load reading export path
+	if val, err := getSetting(queries, ctx, KeyReadingExportPath); err == nil {
+		config.ReadingExportPath = val
+	}
+
+	// Load reading export gist
+	if val, err := getSetting(queries, ctx, KeyReadingExportGist); err == nil {
+		config.ReadingExportGist = val
+	}
+
+	// Load HTTP proxy
+	if val, err := getSetting(queries, ctx, KeyHTTPProxy); err == nil {
+		config.HTTPProxy = val
+	}
+
+	// Load User-Agent
+	if val, err := getSetting(queries, ctx, KeyUserAgent); err == nil {
+		config.UserAgent = val
+	}
+
+	// Load request headers
+	if val, err := getSetting(queries, ctx, KeyRequestHeaders); err == nil {
+		config.RequestHeaders = val
+	}
+
+	// Load feed list layout
+	if val, err := getSetting(queries, ctx, KeyFeedListLayout); err == nil {
+		if val == FeedListLayoutSingle || val == FeedListLayoutColumns {
+			config.FeedListLayout = val
+		}
+	}
+
+	// Load pipe command
+	if val, err := getSetting(queries, ctx, KeyPipeCommand); err == nil {
+		config.PipeCommand = val
+	}
+
+	// Load pipe format
+	if val, err := getSetting(queries, ctx, KeyPipeFormat); err == nil {
+		if val == PipeFormatMarkdown || val == PipeFormatHTML {
+			config.PipeFormat = val
+		}
+	}
+
+	// Load old item days
+	if val, err := getSetting(queries, ctx, KeyOldItemDays); err == nil {
+		if intVal, err := strconv.Atoi(val); err == nil && intVal >= 0 {
+			config.OldItemDays = intVal
+		}
+	}
+
+	// Load debug categories
+	if val, err := getSetting(queries, ctx, KeyDebugCategories); err == nil {
+		config.DebugCategories = val
+	}
+
+	// Load read-later service
+	if val, err := getSetting(queries, ctx, KeyReadLater); err == nil {
+		config.ReadLater = val
+	}
+
+	// Load read-later URL
+	if val, err := getSetting(queries, ctx, KeyReadLaterURL); err == nil {
+		config.ReadLaterURL = val
+	}
+
+	// Load read-later auth
+	if val, err := getSetting(queries, ctx, KeyReadLaterAuth); err == nil {
+		config.ReadLaterAuth = val
+	}
+
+	// Load max items per feed
+	if val, err := getSetting(queries, ctx, KeyMaxItemsPerFeed); err == nil {
+		if intVal, err := strconv.Atoi(val); err == nil && intVal >= 0 {
+			config.MaxItemsPerFeed = intVal
+		}
+	}
+
+	// Load max item age
+	if val, err := getSetting(queries, ctx, KeyMaxItemAgeDays); err == nil {
+		if intVal, err := strconv.Atoi(val); err == nil && intVal >= 0 {
+			config.MaxItemAgeDays = intVal
+		}
+	}
+
+	// Load prune keep
+	if val, err := getSetting(queries, ctx, KeyPruneKeep); err == nil {
+		config.PruneKeep = val
+	}
+
+	// Load WebSub listen address
+	if val, err := getSetting(queries, ctx, KeyWebSubListen); err == nil {
+		config.WebSubListen = val
+	}
+
+	// Load WebSub callback URL
+	if val, err := getSetting(queries, ctx, KeyWebSubCallbackURL); err == nil {
+		config.WebSubCallbackURL = val
+	}
+
+	// Load log max messages
+	if val, err := getSetting(queries, ctx, KeyLogMaxMessages); err == nil {
+		if intVal, err := strconv.Atoi(val); err == nil && intVal >= 0 {
+			config.LogMaxMessages = intVal
+		}
+	}
+
+	// Load log max age
+	if val, err := getSetting(queries, ctx, KeyLogMaxAgeDays); err == nil {
+		if intVal, err := strconv.Atoi(val); err == nil && intVal >= 0 {
+			config.LogMaxAgeDays = intVal
+		}
+	}
+
+	// Load sync service
+	if val, err := getSetting(queries, ctx, KeySync); err == nil {
+		config.Sync = val
+	}
+
+	// Load sync URL
+	if val, err := getSetting(queries, ctx, KeySyncURL); err == nil {
+		config.SyncURL = val
+	}
+
+	// Load sync token
+	if val, err := getSetting(queries, ctx, KeySyncToken); err == nil {
+		config.SyncToken = val
+	}
+
+	// Load right-to-left display
+	if val, err := getSetting(queries, ctx, KeyRTLDisplay); err == nil {
+		if val == RTLDisplayReorder || val == RTLDisplayTerminal {
+			config.RTLDisplay = val
+		}
+	}
+
+	// Load notify
+	if val, err := getSetting(queries, ctx, KeyNotify); err == nil {
+		config.Notify = val
+	}
+
+	// Load images
+	if val, err := getSetting(queries, ctx, KeyImages); err == nil {
+		switch val {
+		case "", ImagesAuto, ImagesKitty, ImagesITerm2, ImagesSixel:
+			config.Images = val
+		}
+	}
+
+	// Load article max width
+	if val, err := getSetting(queries, ctx, KeyArticleMaxWidth); err == nil {
+		if intVal, err := strconv.Atoi(val); err == nil && intVal >= 0 {
+			config.ArticleMaxWidth = intVal
+		}
+	}
+
+	// Load item list format
+	if val, err := getSetting(queries, ctx, KeyItemListFormat); err == nil {
+		config.ItemListFormat = val
+	}
+
+	// Load date format
+	if val, err := getSetting(queries, ctx, KeyDateFormat); err == nil {
+		config.DateFormat = val
+	}
+
+	// Load dead feed threshold
+	if val, err := getSetting(queries, ctx, KeyDeadFeedThreshold); err == nil {
+		if intVal, err := strconv.Atoi(val); err == nil && intVal >= 0 {
+			config.DeadFeedThreshold = intVal
+		}
+	}
+
+	// Load host request rate
+	if val, err := getSetting(queries, ctx, KeyHostRequestRate); err == nil {
+		if intVal, err := strconv.Atoi(val); err == nil && intVal >= 0 {
+			config.HostRequestRate = intVal
+		}
+	}
+
+	// Load host max parallel
+	if val, err := getSetting(queries, ctx, KeyHostMaxParallel); err == nil {
+		if intVal, err := strconv.Atoi(val); err == nil && intVal >= 0 {
+			config.HostMaxParallel = intVal
+		}
+	}
+
+	// Load feed history pages
+	if val, err := getSetting(queries, ctx, KeyFeedHistoryPages); err == nil {
+		if intVal, err := strconv.Atoi(val); err == nil && intVal >= 0 {
+			config.FeedHistoryPages = intVal
+		}
+	}
+
+	// Load update redirects
+	if val, err := getSetting(queries, ctx, KeyUpdateRedirects); err == nil {
+		config.UpdateRedirects = (val == "true" || val == "yes")
+	}
+
+	// Load hide duplicates
+	if val, err := getSetting(queries, ctx, KeyHideDuplicates); err == nil {
+		config.HideDuplicates = (val == "true" || val == "yes")
+	}
+
+	// Load mark read on open
+	if val, err := getSetting(queries, ctx, KeyMarkReadOnOpen); err == nil {
+		config.MarkReadOnOpen = (val == "true" || val == "yes")
+	}
+
+	// Load mark skipped read
+	if val, err := getSetting(queries, ctx, KeyMarkSkippedRead); err == nil {
+		config.MarkSkippedRead = (val == "true" || val == "yes")
+	}
+
+	// Load share commands
+	if val, err := getSetting(queries, ctx, KeyShareCommands); err == nil {
+		config.ShareCommands = val
+	}
+
+	// Load refresh retries
+	if val, err := getSetting(queries, ctx, KeyRefreshRetries); err == nil {
+		if intVal, err := strconv.Atoi(val); err == nil && intVal >= 0 {
+			config.RefreshRetries = intVal
+		}
+	}
+
+	// Load refresh retry delay
+	if val, err := getSetting(queries, ctx, KeyRefreshRetryDelay); err == nil {
+		if intVal, err := strconv.Atoi(val); err == nil && intVal > 0 {
+			config.RefreshRetryDelay = intVal
+		}
+	}
+
+	// Load prune schedule
+	if val, err := getSetting(queries, ctx, KeyPruneSchedule); err == nil {
+		config.PruneSchedule = val
+	}
+
+	// Load update check schedule
+	if val, err := getSetting(queries, ctx, KeyUpdateCheckSchedule); err == nil {
+		config.UpdateCheckSchedule = val
+	}
+
+	// Load feed icons
+	if val, err := getSetting(queries, ctx, KeyFeedIcons); err == nil {
+		if val == FeedIconsOff || val == FeedIconsGlyph || val == FeedIconsNerdFont {
+			config.FeedIcons = val
+		}
+	}
+
+	// Load macros
+	if val, err := getSetting(queries, ctx, KeyMacros); err == nil {
+		config.Macros = val
+	}
+
+	// Load article export settings
+	if val, err := getSetting(queries, ctx, KeyExportDir); err == nil && val != "" {
+		config.ExportDir = val
+	}
+	if val, err := getSetting(queries, ctx, KeyExportFileName); err == nil && val != "" {
+		config.ExportFileName = val
+	}
+	if val, err := getSetting(queries, ctx, KeyExportPDFCommand); err == nil {
+		config.ExportPDFCommand = val
+	}
+
 	// Validate config values
 	if config.ReloadConcurrency < 1 {
 		config.ReloadConcurrency = 1
@@ -213,6 +705,277 @@ func SaveConfig(queries *database.Queries, config Config) error {
 		return err
 	}
 
+	// Save cluster stories
+	clusterStoriesStr := "false"
+	if config.ClusterStories {
+		clusterStoriesStr = "true"
+	}
+	if err := setSetting(queries, ctx, KeyClusterStories, clusterStoriesStr); err != nil {
+		return err
+	}
+
+	// Save hot keywords
+	if err := setSetting(queries, ctx, KeyHotKeywords, config.HotKeywords); err != nil {
+		return err
+	}
+
+	// Save highlight keywords
+	if err := setSetting(queries, ctx, KeyHighlightKeywords, config.HighlightKeywords); err != nil {
+		return err
+	}
+
+	// Save reading export
+	if err := setSetting(queries, ctx, KeyReadingExport, config.ReadingExport); err != nil {
+		return err
+	}
+
+	// Save reading export path
+	if err := setSetting(queries, ctx, KeyReadingExportPath, config.ReadingExportPath); err != nil {
+		return err
+	}
+
+	// Save reading export gist
+	if err := setSetting(queries, ctx, KeyReadingExportGist, config.ReadingExportGist); err != nil {
+		return err
+	}
+
+	// Save HTTP proxy
+	if err := setSetting(queries, ctx, KeyHTTPProxy, config.HTTPProxy); err != nil {
+		return err
+	}
+
+	// Save User-Agent
+	if err := setSetting(queries, ctx, KeyUserAgent, config.UserAgent); err != nil {
+		return err
+	}
+
+	// Save request headers
+	if err := setSetting(queries, ctx, KeyRequestHeaders, config.RequestHeaders); err != nil {
+		return err
+	}
+
+	// Save feed list layout
+	if err := setSetting(queries, ctx, KeyFeedListLayout, config.FeedListLayout); err != nil {
+		return err
+	}
+
+	// Save pipe command
+	if err := setSetting(queries, ctx, KeyPipeCommand, config.PipeCommand); err != nil {
+		return err
+	}
+
+	// Save pipe format
+	if err := setSetting(queries, ctx, KeyPipeFormat, config.PipeFormat); err != nil {
+		return err
+	}
+
+	// Save old item days
+	if err := setSetting(queries, ctx, KeyOldItemDays, strconv.Itoa(config.OldItemDays)); err != nil {
+		return err
+	}
+
+	// Save debug categories
+	if err := setSetting(queries, ctx, KeyDebugCategories, config.DebugCategories); err != nil {
+		return err
+	}
+
+	// Save read-later service
+	if err := setSetting(queries, ctx, KeyReadLater, config.ReadLater); err != nil {
+		return err
+	}
+
+	// Save read-later URL
+	if err := setSetting(queries, ctx, KeyReadLaterURL, config.ReadLaterURL); err != nil {
+		return err
+	}
+
+	// Save read-later auth
+	if err := setSetting(queries, ctx, KeyReadLaterAuth, config.ReadLaterAuth); err != nil {
+		return err
+	}
+
+	// Save max items per feed
+	if err := setSetting(queries, ctx, KeyMaxItemsPerFeed, strconv.Itoa(config.MaxItemsPerFeed)); err != nil {
+		return err
+	}
+
+	// Save max item age
+	if err := setSetting(queries, ctx, KeyMaxItemAgeDays, strconv.Itoa(config.MaxItemAgeDays)); err != nil {
+		return err
+	}
+
+	// Save prune keep
+	if err := setSetting(queries, ctx, KeyPruneKeep, config.PruneKeep); err != nil {
+		return err
+	}
+
+	// Save WebSub listen address
+	if err := setSetting(queries, ctx, KeyWebSubListen, config.WebSubListen); err != nil {
+		return err
+	}
+
+	// Save WebSub callback URL
+	if err := setSetting(queries, ctx, KeyWebSubCallbackURL, config.WebSubCallbackURL); err != nil {
+		return err
+	}
+
+	// Save log max messages
+	if err := setSetting(queries, ctx, KeyLogMaxMessages, strconv.Itoa(config.LogMaxMessages)); err != nil {
+		return err
+	}
+
+	// Save log max age
+	if err := setSetting(queries, ctx, KeyLogMaxAgeDays, strconv.Itoa(config.LogMaxAgeDays)); err != nil {
+		return err
+	}
+
+	// Save sync service
+	if err := setSetting(queries, ctx, KeySync, config.Sync); err != nil {
+		return err
+	}
+
+	// Save sync URL
+	if err := setSetting(queries, ctx, KeySyncURL, config.SyncURL); err != nil {
+		return err
+	}
+
+	// Save sync token
+	if err := setSetting(queries, ctx, KeySyncToken, config.SyncToken); err != nil {
+		return err
+	}
+
+	// Save right-to-left display
+	if err := setSetting(queries, ctx, KeyRTLDisplay, config.RTLDisplay); err != nil {
+		return err
+	}
+
+	// Save notify
+	if err := setSetting(queries, ctx, KeyNotify, config.Notify); err != nil {
+		return err
+	}
+
+	// Save images
+	if err := setSetting(queries, ctx, KeyImages, config.Images); err != nil {
+		return err
+	}
+
+	// Save article max width
+	if err := setSetting(queries, ctx, KeyArticleMaxWidth, strconv.Itoa(config.ArticleMaxWidth)); err != nil {
+		return err
+	}
+
+	// Save item list format
+	if err := setSetting(queries, ctx, KeyItemListFormat, config.ItemListFormat); err != nil {
+		return err
+	}
+
+	// Save date format
+	if err := setSetting(queries, ctx, KeyDateFormat, config.DateFormat); err != nil {
+		return err
+	}
+
+	// Save dead feed threshold
+	if err := setSetting(queries, ctx, KeyDeadFeedThreshold, strconv.Itoa(config.DeadFeedThreshold)); err != nil {
+		return err
+	}
+
+	// Save host request rate
+	if err := setSetting(queries, ctx, KeyHostRequestRate, strconv.Itoa(config.HostRequestRate)); err != nil {
+		return err
+	}
+
+	// Save host max parallel
+	if err := setSetting(queries, ctx, KeyHostMaxParallel, strconv.Itoa(config.HostMaxParallel)); err != nil {
+		return err
+	}
+
+	// Save feed history pages
+	if err := setSetting(queries, ctx, KeyFeedHistoryPages, strconv.Itoa(config.FeedHistoryPages)); err != nil {
+		return err
+	}
+
+	// Save update redirects
+	updateRedirectsStr := "false"
+	if config.UpdateRedirects {
+		updateRedirectsStr = "true"
+	}
+	if err := setSetting(queries, ctx, KeyUpdateRedirects, updateRedirectsStr); err != nil {
+		return err
+	}
+
+	// Save hide duplicates
+	hideDuplicatesStr := "false"
+	if config.HideDuplicates {
+		hideDuplicatesStr = "true"
+	}
+	if err := setSetting(queries, ctx, KeyHideDuplicates, hideDuplicatesStr); err != nil {
+		return err
+	}
+
+	// Save mark read on open
+	markReadOnOpenStr := "false"
+	if config.MarkReadOnOpen {
+		markReadOnOpenStr = "true"
+	}
+	if err := setSetting(queries, ctx, KeyMarkReadOnOpen, markReadOnOpenStr); err != nil {
+		return err
+	}
+
+	// Save mark skipped read
+	markSkippedReadStr := "false"
+	if config.MarkSkippedRead {
+		markSkippedReadStr = "true"
+	}
+	if err := setSetting(queries, ctx, KeyMarkSkippedRead, markSkippedReadStr); err != nil {
+		return err
+	}
+
+	// Save share commands
+	if err := setSetting(queries, ctx, KeyShareCommands, config.ShareCommands); err != nil {
+		return err
+	}
+
+	// Save refresh retries
+	if err := setSetting(queries, ctx, KeyRefreshRetries, strconv.Itoa(config.RefreshRetries)); err != nil {
+		return err
+	}
+
+	// Save refresh retry delay
+	if err := setSetting(queries, ctx, KeyRefreshRetryDelay, strconv.Itoa(config.RefreshRetryDelay)); err != nil {
+		return err
+	}
+
+	// Save prune schedule
+	if err := setSetting(queries, ctx, KeyPruneSchedule, config.PruneSchedule); err != nil {
+		return err
+	}
+
+	// Save update check schedule
+	if err := setSetting(queries, ctx, KeyUpdateCheckSchedule, config.UpdateCheckSchedule); err != nil {
+		return err
+	}
+
+	// Save feed icons
+	if err := setSetting(queries, ctx, KeyFeedIcons, config.FeedIcons); err != nil {
+		return err
+	}
+
+	// Save macros
+	if err := setSetting(queries, ctx, KeyMacros, config.Macros); err != nil {
+		return err
+	}
+
+	// Save article export settings
+	if err := setSetting(queries, ctx, KeyExportDir, config.ExportDir); err != nil {
+		return err
+	}
+	if err := setSetting(queries, ctx, KeyExportFileName, config.ExportFileName); err != nil {
+		return err
+	}
+	if err := setSetting(queries, ctx, KeyExportPDFCommand, config.ExportPDFCommand); err != nil {
+		return err
+	}
+
 	return nil
 }
 
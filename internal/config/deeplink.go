@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Deep link schemes NewsGoat is registered for
+const (
+	SchemeFeed     = "feed"
+	SchemeNewsGoat = "newsgoat"
+)
+
+// ParseDeepLink returns the URL to subscribe to of a subscribe link, ok is
+// false for arguments that aren't one. It accepts feed://example.com/rss,
+// feed:https://example.com/rss and newsgoat://add?url=https://example.com/rss.
+func ParseDeepLink(arg string) (feedURL string, ok bool, err error) {
+	scheme, rest, found := strings.Cut(arg, ":")
+	if !found {
+		return "", false, nil
+	}
+
+	switch strings.ToLower(scheme) {
+	case SchemeFeed:
+		rest = strings.TrimPrefix(rest, "//")
+		// feed:https://... and feed://https://... wrap the whole URL
+		lower := strings.ToLower(rest)
+		if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
+			feedURL = rest
+		} else if strings.HasPrefix(lower, "http//") || strings.HasPrefix(lower, "https//") {
+			// Some browsers drop the colon of the wrapped scheme
+			feedURL = strings.Replace(rest, "//", "://", 1)
+		} else {
+			feedURL = "https://" + rest
+		}
+
+	case SchemeNewsGoat:
+		link, err := url.Parse(arg)
+		if err != nil {
+			return "", true, fmt.Errorf("invalid link %q: %w", arg, err)
+		}
+		action := link.Host
+		if action == "" {
+			action = link.Opaque
+		}
+		if action != "add" {
+			return "", true, fmt.Errorf("unknown link %q, expected newsgoat://add?url=<feed url>", arg)
+		}
+		feedURL = link.Query().Get("url")
+
+	default:
+		return "", false, nil
+	}
+
+	parsed, err := url.Parse(feedURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return "", true, fmt.Errorf("link %q has no http(s) URL to subscribe to", arg)
+	}
+	return feedURL, true, nil
+}
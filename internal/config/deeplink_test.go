@@ -0,0 +1,30 @@
+package config
+
+import "testing"
+
+func TestParseDeepLink(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    string
+		ok      bool
+		wantErr bool
+	}{
+		{arg: "feed://example.com/rss.xml", want: "https://example.com/rss.xml", ok: true},
+		{arg: "feed:https://example.com/rss.xml", want: "https://example.com/rss.xml", ok: true},
+		{arg: "feed://http://example.com/rss.xml", want: "http://example.com/rss.xml", ok: true},
+		{arg: "feed://https//example.com/rss.xml", want: "https://example.com/rss.xml", ok: true},
+		{arg: "newsgoat://add?url=https%3A%2F%2Fexample.com%2Ffeed%3Fa%3D1", want: "https://example.com/feed?a=1", ok: true},
+		{arg: "newsgoat:add?url=https://example.com/feed", want: "https://example.com/feed", ok: true},
+		{arg: "newsgoat://add", ok: true, wantErr: true},
+		{arg: "newsgoat://remove?url=https://example.com/feed", ok: true, wantErr: true},
+		{arg: "feed://", ok: true, wantErr: true},
+		{arg: "add", ok: false},
+		{arg: "https://example.com/feed", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok, err := ParseDeepLink(tt.arg)
+		if got != tt.want || ok != tt.ok || (err != nil) != tt.wantErr {
+			t.Errorf("ParseDeepLink(%q) = %q, %v, %v, want %q, %v, error %v", tt.arg, got, ok, err, tt.want, tt.ok, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package config
+
+import "strings"
+
+// HighlightKeyword is a keyword that is colored in item titles and articles.
+// A keyword with a folder only applies to items of feeds in that folder.
+type HighlightKeyword struct {
+	Keyword string
+	Folder  string
+}
+
+// ParseHighlightKeywords parses a comma-separated keyword list. A keyword can
+// be limited to one folder by prefixing it with the folder name in brackets,
+// for example "CVE, Go 1., [Work] Acme Corp".
+func ParseHighlightKeywords(setting string) []HighlightKeyword {
+	var keywords []HighlightKeyword
+	for _, part := range strings.Split(setting, ",") {
+		part = strings.TrimSpace(part)
+
+		var folder string
+		if strings.HasPrefix(part, "[") {
+			if end := strings.Index(part, "]"); end > 0 {
+				folder = strings.TrimSpace(part[1:end])
+				part = strings.TrimSpace(part[end+1:])
+			}
+		}
+
+		if part != "" {
+			keywords = append(keywords, HighlightKeyword{Keyword: part, Folder: folder})
+		}
+	}
+	return keywords
+}
+
+// KeywordsForFolders returns the keywords that apply to a feed in the given
+// folders, which are the global keywords and those of any of the folders
+func KeywordsForFolders(keywords []HighlightKeyword, folders []string) []string {
+	var result []string
+	for _, keyword := range keywords {
+		if keyword.Folder == "" || containsFolder(folders, keyword.Folder) {
+			result = append(result, keyword.Keyword)
+		}
+	}
+	return result
+}
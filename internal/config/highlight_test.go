@@ -0,0 +1,38 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseHighlightKeywords(t *testing.T) {
+	got := ParseHighlightKeywords(" CVE, Go 1.,, [Work] Acme Corp ,[Tech News]kubernetes")
+	expected := []HighlightKeyword{
+		{Keyword: "CVE"},
+		{Keyword: "Go 1."},
+		{Keyword: "Acme Corp", Folder: "Work"},
+		{Keyword: "kubernetes", Folder: "Tech News"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ParseHighlightKeywords() = %+v, want %+v", got, expected)
+	}
+}
+
+func TestKeywordsForFolders(t *testing.T) {
+	keywords := ParseHighlightKeywords("CVE, [Work] Acme, [Tech News] kubernetes")
+
+	tests := []struct {
+		folders  []string
+		expected []string
+	}{
+		{nil, []string{"CVE"}},
+		{[]string{"Work"}, []string{"CVE", "Acme"}},
+		{[]string{"Tech News", "Work"}, []string{"CVE", "Acme", "kubernetes"}},
+	}
+
+	for _, tt := range tests {
+		if got := KeywordsForFolders(keywords, tt.folders); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("KeywordsForFolders(%v) = %v, want %v", tt.folders, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package config
+
+// HostLimits keeps refreshes from sending many requests to the same host at
+// once, like when dozens of feeds are on github.com
+type HostLimits struct {
+	RequestsPerSecond int // Requests started to a host per second, 0 for no limit
+	MaxParallel       int // Requests to a host running at once, 0 for no limit
+}
+
+// HostLimits returns the per-host limits from the settings
+func (c Config) HostLimits() HostLimits {
+	return HostLimits{RequestsPerSecond: c.HostRequestRate, MaxParallel: c.HostMaxParallel}
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LegacyPointerFile is left behind in ~/.newsgoat after its files are moved
+const LegacyPointerFile = "MOVED"
+
+// legacyFiles are the files moved out of ~/.newsgoat, the SQLite -wal and
+// -shm files move together with the database
+var legacyFiles = []string{"urls", "newsgoat.db", "newsgoat.db-wal", "newsgoat.db-shm"}
+
+// LegacyMigration moves files from the old ~/.newsgoat directory to
+// ~/.config/newsgoat
+type LegacyMigration struct {
+	OldDir    string
+	NewDir    string
+	Files     []string // Files in OldDir that will be moved
+	Conflicts []string // Files that exist in both directories, nothing is moved while there are any
+}
+
+// PendingLegacyMigration returns the files still in ~/.newsgoat, or nil when
+// there is nothing to move
+func PendingLegacyMigration() (*LegacyMigration, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return pendingLegacyMigration(filepath.Join(homeDir, ".newsgoat"), filepath.Join(homeDir, ".config", "newsgoat"))
+}
+
+func pendingLegacyMigration(oldDir, newDir string) (*LegacyMigration, error) {
+	migration := &LegacyMigration{OldDir: oldDir, NewDir: newDir}
+	for _, name := range legacyFiles {
+		if _, err := os.Stat(filepath.Join(oldDir, name)); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		migration.Files = append(migration.Files, name)
+		if _, err := os.Stat(filepath.Join(newDir, name)); err == nil {
+			migration.Conflicts = append(migration.Conflicts, name)
+		}
+	}
+
+	if len(migration.Files) == 0 {
+		return nil, nil
+	}
+	return migration, nil
+}
+
+// Run moves the files and writes a pointer file to the new location in the
+// old directory
+func (lm *LegacyMigration) Run() error {
+	if len(lm.Conflicts) > 0 {
+		return fmt.Errorf("%v already exist in %s", lm.Conflicts, lm.NewDir)
+	}
+
+	if err := os.MkdirAll(lm.NewDir, 0755); err != nil {
+		return err
+	}
+
+	for _, name := range lm.Files {
+		if err := moveFile(filepath.Join(lm.OldDir, name), filepath.Join(lm.NewDir, name)); err != nil {
+			return fmt.Errorf("failed to move %s: %w", name, err)
+		}
+	}
+
+	pointer := fmt.Sprintf("NewsGoat files were moved to %s on %s.\nThis directory is no longer used and can be removed.\n",
+		lm.NewDir, time.Now().Format("2006-01-02"))
+	return os.WriteFile(filepath.Join(lm.OldDir, LegacyPointerFile), []byte(pointer), 0644)
+}
+
+// moveFile renames src to dst, copying when they are on different filesystems
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		_ = os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		_ = os.Remove(dst)
+		return err
+	}
+
+	return os.Remove(src)
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLegacyMigration(t *testing.T) {
+	testDir := t.TempDir()
+	oldDir := filepath.Join(testDir, ".newsgoat")
+	newDir := filepath.Join(testDir, ".config", "newsgoat")
+	if err := os.MkdirAll(oldDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"urls", "newsgoat.db"} {
+		if err := os.WriteFile(filepath.Join(oldDir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	migration, err := pendingLegacyMigration(oldDir, newDir)
+	if err != nil {
+		t.Fatalf("pendingLegacyMigration() error = %v", err)
+	}
+	if migration == nil || len(migration.Files) != 2 || len(migration.Conflicts) != 0 {
+		t.Fatalf("pendingLegacyMigration() = %+v, want urls and newsgoat.db", migration)
+	}
+
+	if err := migration.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	for _, name := range []string{"urls", "newsgoat.db"} {
+		data, err := os.ReadFile(filepath.Join(newDir, name))
+		if err != nil || string(data) != name {
+			t.Errorf("%s wasn't moved: %q, %v", name, data, err)
+		}
+		if _, err := os.Stat(filepath.Join(oldDir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s is still in the old directory", name)
+		}
+	}
+
+	pointer, err := os.ReadFile(filepath.Join(oldDir, LegacyPointerFile))
+	if err != nil || !strings.Contains(string(pointer), newDir) {
+		t.Errorf("pointer file = %q, %v, want it to name %s", pointer, err, newDir)
+	}
+
+	// Nothing is left to move afterwards
+	if migration, err := pendingLegacyMigration(oldDir, newDir); err != nil || migration != nil {
+		t.Errorf("pendingLegacyMigration() after Run = %+v, %v, want nil", migration, err)
+	}
+}
+
+func TestLegacyMigrationConflict(t *testing.T) {
+	testDir := t.TempDir()
+	oldDir := filepath.Join(testDir, ".newsgoat")
+	newDir := filepath.Join(testDir, ".config", "newsgoat")
+	for _, dir := range []string{oldDir, newDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "urls"), []byte(dir), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	migration, err := pendingLegacyMigration(oldDir, newDir)
+	if err != nil {
+		t.Fatalf("pendingLegacyMigration() error = %v", err)
+	}
+	if len(migration.Conflicts) != 1 || migration.Conflicts[0] != "urls" {
+		t.Fatalf("Conflicts = %v, want [urls]", migration.Conflicts)
+	}
+	if err := migration.Run(); err == nil {
+		t.Error("Run() should refuse to overwrite existing files")
+	}
+
+	data, _ := os.ReadFile(filepath.Join(newDir, "urls"))
+	if string(data) != newDir {
+		t.Errorf("existing urls file was overwritten with %q", data)
+	}
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Lock is the PID file held by the NewsGoat that refreshes the database, a
+// second one started at the same time opens it read-only
+type Lock struct {
+	path string
+}
+
+// LockedError is returned when another running NewsGoat holds the lock
+type LockedError struct {
+	PID  int
+	Path string
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("newsgoat is already running (pid %d), remove %s if it is not", e.PID, e.Path)
+}
+
+// GetLockFilePath returns the path of the PID file
+func GetLockFilePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "newsgoat", "newsgoat.pid"), nil
+}
+
+// GetSocketFilePath returns the path of the socket the running NewsGoat
+// accepts requests on, such as feeds to add from deep links. It is in a
+// directory of its own that only the user can enter.
+func GetSocketFilePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "newsgoat", "ipc", "newsgoat.sock"), nil
+}
+
+// AcquireLock writes the PID file, it returns a *LockedError when another
+// NewsGoat is running. A PID file left behind by one that crashed is replaced.
+func AcquireLock() (*Lock, error) {
+	path, err := GetLockFilePath()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	return acquireLock(path)
+}
+
+func acquireLock(path string) (*Lock, error) {
+	for attempt := 0; ; attempt++ {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, writeErr := fmt.Fprintf(file, "%d\n", os.Getpid())
+			if closeErr := file.Close(); writeErr == nil {
+				writeErr = closeErr
+			}
+			if writeErr != nil {
+				_ = os.Remove(path)
+				return nil, writeErr
+			}
+			return &Lock{path: path}, nil
+		}
+		if !errors.Is(err, os.ErrExist) || attempt > 0 {
+			return nil, err
+		}
+
+		if pid, ok := readPID(path); ok && pid != os.Getpid() && processRunning(pid) {
+			return nil, &LockedError{PID: pid, Path: path}
+		}
+		// Stale, try once more after removing it
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+}
+
+// Release removes the PID file
+func (l *Lock) Release() error {
+	if pid, ok := readPID(l.path); ok && pid != os.Getpid() {
+		// Someone replaced it, leave theirs alone
+		return nil
+	}
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func readPID(path string) (int, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+	return pid, true
+}
+
+// processRunning reports whether a process with the pid exists, signal 0
+// only checks without sending anything
+func processRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, os.ErrPermission) || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "newsgoat.pid")
+
+	lock, err := acquireLock(path)
+	if err != nil {
+		t.Fatalf("acquireLock() error = %v", err)
+	}
+	if pid, ok := readPID(path); !ok || pid != os.Getpid() {
+		t.Errorf("PID file holds %d, want %d", pid, os.Getpid())
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("PID file still exists after Release()")
+	}
+}
+
+func TestLockHeldByRunningProcess(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "newsgoat.pid")
+	// The parent of the test binary is running for as long as the test is
+	parent := os.Getppid()
+	if err := os.WriteFile(path, []byte(strconv.Itoa(parent)+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := acquireLock(path)
+	var locked *LockedError
+	if !errors.As(err, &locked) || locked.PID != parent {
+		t.Fatalf("acquireLock() error = %v, want a LockedError for pid %d", err, parent)
+	}
+}
+
+func TestLockReplacesStaleFile(t *testing.T) {
+	for name, content := range map[string]string{
+		"exited process": "1073741824\n",
+		"garbage":        "not a pid",
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "newsgoat.pid")
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			lock, err := acquireLock(path)
+			if err != nil {
+				t.Fatalf("acquireLock() error = %v", err)
+			}
+			defer func() { _ = lock.Release() }()
+			if pid, ok := readPID(path); !ok || pid != os.Getpid() {
+				t.Errorf("PID file holds %d, want %d", pid, os.Getpid())
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Macro is a sequence of key actions and : commands run by , followed by
+// its key
+type Macro struct {
+	Key   string
+	Steps []string // Action names like items.open_link, or commands like :sync
+}
+
+// ParseMacros parses macros separated by semicolons, their steps separated
+// by commas, e.g. "m: items.open_link, items.toggle_read, global.down"
+func ParseMacros(value string) ([]Macro, error) {
+	var macros []Macro
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(value, ";") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		key, steps, ok := strings.Cut(field, ":")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("invalid macro %q, expected \"key: action, action\" separated by semicolons", field)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("macro key %q is used more than once", key)
+		}
+		seen[key] = true
+
+		macro := Macro{Key: key}
+		for _, step := range strings.Split(steps, ",") {
+			if step = strings.TrimSpace(step); step != "" {
+				macro.Steps = append(macro.Steps, step)
+			}
+		}
+		if len(macro.Steps) == 0 {
+			return nil, fmt.Errorf("macro %q has no actions", key)
+		}
+		macros = append(macros, macro)
+	}
+	return macros, nil
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMacros(t *testing.T) {
+	macros, err := ParseMacros("m: items.open_link, items.toggle_read, global.down; s: :filter unread ;")
+	if err != nil {
+		t.Fatalf("ParseMacros() error = %v", err)
+	}
+	expected := []Macro{
+		{Key: "m", Steps: []string{"items.open_link", "items.toggle_read", "global.down"}},
+		{Key: "s", Steps: []string{":filter unread"}},
+	}
+	if !reflect.DeepEqual(macros, expected) {
+		t.Errorf("ParseMacros() = %v, want %v", macros, expected)
+	}
+
+	if macros, err := ParseMacros(" "); err != nil || macros != nil {
+		t.Errorf("ParseMacros(empty) = %v, %v, want nil", macros, err)
+	}
+	for _, invalid := range []string{"items.open_link", ": items.open_link", "m:", "m: ,", "m: global.down; m: global.up", "a b: global.down"} {
+		if _, err := ParseMacros(invalid); err == nil {
+			t.Errorf("ParseMacros(%q) expected an error", invalid)
+		}
+	}
+}
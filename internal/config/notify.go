@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NotifyAll is the Notify setting that notifies about new items of every feed
+const NotifyAll = "all"
+
+// NotifyPolicy decides which feeds send a desktop notification when a
+// refresh finds new items
+type NotifyPolicy struct {
+	All     bool            // Every feed notifies unless it sets !notify=off
+	Folders map[string]bool // Feeds in these folders notify
+	Feeds   map[string]bool // Feed URL -> the feed's !notify option, wins over the rest
+}
+
+// NotifyPolicy returns the policy of the Notify setting: "all", or a
+// comma-separated list of folders. Feeds are added from the URLs file with
+// WithFeeds.
+func (c Config) NotifyPolicy() NotifyPolicy {
+	policy := NotifyPolicy{Folders: make(map[string]bool)}
+	value := strings.TrimSpace(c.Notify)
+	if strings.EqualFold(value, NotifyAll) {
+		policy.All = true
+		return policy
+	}
+	for _, folder := range strings.Split(value, ",") {
+		if folder = strings.TrimSpace(folder); folder != "" {
+			policy.Folders[folder] = true
+		}
+	}
+	return policy
+}
+
+// WithFeeds returns the policy with the !notify options of feeds
+func (p NotifyPolicy) WithFeeds(feeds map[string]bool) NotifyPolicy {
+	p.Feeds = feeds
+	return p
+}
+
+// Enabled reports whether any feed can notify
+func (p NotifyPolicy) Enabled() bool {
+	if p.All || len(p.Folders) > 0 {
+		return true
+	}
+	for _, on := range p.Feeds {
+		if on {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsFolders reports whether Notifies needs the folders of a feed
+func (p NotifyPolicy) NeedsFolders() bool {
+	return !p.All && len(p.Folders) > 0
+}
+
+// Notifies reports whether a feed in the given folders notifies
+func (p NotifyPolicy) Notifies(feedURL string, folders []string) bool {
+	if on, ok := p.Feeds[feedURL]; ok {
+		return on
+	}
+	if p.All {
+		return true
+	}
+	for _, folder := range folders {
+		if p.Folders[folder] {
+			return true
+		}
+	}
+	return false
+}
+
+// Notify returns whether the feed sets the notify option and whether it is
+// on, !notify turns it on and !notify=off off
+func (e URLEntry) Notify() (set, on bool, err error) {
+	value, ok := e.Option(OptionNotify)
+	if !ok {
+		return false, false, nil
+	}
+	switch strings.ToLower(value) {
+	case "", "on", "yes", "true":
+		return true, true, nil
+	case "off", "no", "false":
+		return true, false, nil
+	}
+	return false, false, fmt.Errorf("invalid notify value %q, use !notify or !notify=off", value)
+}
+
+// FeedNotify returns the notify option of the entries that set it, keyed by URL
+func FeedNotify(entries []URLEntry) (map[string]bool, []error) {
+	feeds := make(map[string]bool)
+	var errs []error
+	for _, entry := range entries {
+		set, on, err := entry.Notify()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.URL, err))
+			continue
+		}
+		if set {
+			feeds[entry.URL] = on
+		}
+	}
+	return feeds, errs
+}
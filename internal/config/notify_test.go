@@ -0,0 +1,47 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNotifyPolicy(t *testing.T) {
+	entries := []URLEntry{
+		{URL: "https://example.com/a.xml", Options: map[string]string{OptionNotify: ""}},
+		{URL: "https://example.com/b.xml", Options: map[string]string{OptionNotify: "off"}},
+		{URL: "https://example.com/c.xml"},
+		{URL: "https://example.com/d.xml", Options: map[string]string{OptionNotify: "loud"}},
+	}
+	feeds, errs := FeedNotify(entries)
+	if len(errs) != 1 {
+		t.Errorf("FeedNotify() errors = %v, want 1", errs)
+	}
+	if want := map[string]bool{"https://example.com/a.xml": true, "https://example.com/b.xml": false}; !reflect.DeepEqual(feeds, want) {
+		t.Errorf("FeedNotify() = %v, want %v", feeds, want)
+	}
+
+	tests := []struct {
+		setting string
+		url     string
+		folders []string
+		want    bool
+	}{
+		{"", "https://example.com/a.xml", nil, true},
+		{"", "https://example.com/c.xml", nil, false},
+		{"all", "https://example.com/c.xml", nil, true},
+		{"All", "https://example.com/b.xml", nil, false},
+		{"Tech, News", "https://example.com/c.xml", []string{"News"}, true},
+		{"Tech, News", "https://example.com/c.xml", []string{"Sports"}, false},
+		{"Tech", "https://example.com/b.xml", []string{"Tech"}, false},
+	}
+	for _, tt := range tests {
+		policy := Config{Notify: tt.setting}.NotifyPolicy().WithFeeds(feeds)
+		if got := policy.Notifies(tt.url, tt.folders); got != tt.want {
+			t.Errorf("Notify %q: Notifies(%s, %v) = %v, want %v", tt.setting, tt.url, tt.folders, got, tt.want)
+		}
+	}
+
+	if (Config{}).NotifyPolicy().Enabled() {
+		t.Errorf("an empty Notify setting without feed options is enabled")
+	}
+}
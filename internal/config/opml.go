@@ -0,0 +1,83 @@
+package config
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// opmlDocument is the subset of the OPML format needed to read feed subscriptions
+type opmlDocument struct {
+	XMLName xml.Name      `xml:"opml"`
+	Body    []opmlOutline `xml:"body>outline"`
+}
+
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr"`
+	XMLURL   string        `xml:"xmlUrl,attr"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// ParseOPML reads an OPML document and returns its feed subscriptions.
+// Outlines without a feed URL are treated as folders, feeds nested in them
+// get the path of every enclosing folder, like "Tech/Go". A feed that appears
+// more than once is returned once with all of its folders.
+func ParseOPML(r io.Reader) ([]URLEntry, error) {
+	var doc opmlDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OPML: %w", err)
+	}
+
+	var entries []URLEntry
+	seen := make(map[string]int)
+
+	var walk func(outlines []opmlOutline, folder string)
+	walk = func(outlines []opmlOutline, folder string) {
+		for _, outline := range outlines {
+			feedURL := strings.TrimSpace(outline.XMLURL)
+			if feedURL == "" {
+				// Folder outline
+				name := strings.TrimSpace(outline.Text)
+				if name == "" {
+					name = strings.TrimSpace(outline.Title)
+				}
+				// Unnamed outlines keep their feeds in the enclosing folder
+				path := folder
+				if name = cleanFolder(name); name != "" && folder != "" {
+					path = folder + "/" + name
+				} else if name != "" {
+					path = name
+				}
+				walk(outline.Outlines, path)
+				continue
+			}
+
+			idx, exists := seen[feedURL]
+			if !exists {
+				idx = len(entries)
+				seen[feedURL] = idx
+				entries = append(entries, URLEntry{URL: feedURL})
+			}
+			if folder != "" && !containsFolder(entries[idx].Folders, folder) {
+				entries[idx].Folders = append(entries[idx].Folders, folder)
+			}
+
+			// Some exporters nest feeds under feeds, keep the current folder
+			walk(outline.Outlines, folder)
+		}
+	}
+	walk(doc.Body, "")
+
+	return entries, nil
+}
+
+func containsFolder(folders []string, folder string) bool {
+	for _, f := range folders {
+		if f == folder {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseOPML(t *testing.T) {
+	opml := `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head><title>newsboat subscriptions</title></head>
+  <body>
+    <outline type="rss" text="No Folder" xmlUrl="https://example.com/feed.xml"/>
+    <outline text="Tech News">
+      <outline type="rss" text="Ars" xmlUrl="https://arstechnica.com/feed/"/>
+      <outline title="Programming">
+        <outline type="rss" text="Go" xmlUrl="https://go.dev/blog/feed.atom"/>
+      </outline>
+    </outline>
+    <outline text="Science">
+      <outline type="rss" text="Ars" xmlUrl="https://arstechnica.com/feed/"/>
+    </outline>
+  </body>
+</opml>`
+
+	entries, err := ParseOPML(strings.NewReader(opml))
+	if err != nil {
+		t.Fatalf("ParseOPML failed: %v", err)
+	}
+
+	expected := []URLEntry{
+		{URL: "https://example.com/feed.xml"},
+		{URL: "https://arstechnica.com/feed/", Folders: []string{"Tech News", "Science"}},
+		{URL: "https://go.dev/blog/feed.atom", Folders: []string{"Tech News/Programming"}},
+	}
+
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("ParseOPML() = %+v, want %+v", entries, expected)
+	}
+}
+
+func TestImportNestedOPML(t *testing.T) {
+	opml := `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <body>
+    <outline text="Tech">
+      <outline text="Go">
+        <outline text="Releases">
+          <outline type="rss" text="Go" xmlUrl="https://go.dev/blog/feed.atom"/>
+        </outline>
+      </outline>
+      <outline>
+        <outline type="rss" text="Ars" xmlUrl="https://arstechnica.com/feed/"/>
+      </outline>
+    </outline>
+  </body>
+</opml>`
+
+	entries, err := ParseOPML(strings.NewReader(opml))
+	if err != nil {
+		t.Fatalf("ParseOPML failed: %v", err)
+	}
+
+	// The folders are kept when the import is written to the URLs file and
+	// read back
+	path := filepath.Join(t.TempDir(), "urls")
+	var urls []string
+	for _, entry := range entries {
+		urls = append(urls, FormatURLLine(entry))
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(urls, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	imported, err := ReadURLsFileFromPath(path)
+	if err != nil {
+		t.Fatalf("ReadURLsFileFromPath failed: %v", err)
+	}
+
+	folders := make(map[string][]string)
+	for _, entry := range imported {
+		folders[entry.URL] = entry.Folders
+	}
+	expected := map[string][]string{
+		"https://go.dev/blog/feed.atom": {"Tech/Go/Releases"},
+		"https://arstechnica.com/feed/": {"Tech"},
+	}
+	if !reflect.DeepEqual(folders, expected) {
+		t.Errorf("folders after import = %v, want %v", folders, expected)
+	}
+}
+
+func TestParseOPMLInvalid(t *testing.T) {
+	if _, err := ParseOPML(strings.NewReader("not xml")); err == nil {
+		t.Error("Expected error for invalid OPML")
+	}
+}
+
+func TestFormatURLLine(t *testing.T) {
+	tests := []struct {
+		entry    URLEntry
+		expected string
+	}{
+		{URLEntry{URL: "https://example.com/feed"}, "https://example.com/feed"},
+		{URLEntry{URL: "https://example.com/feed", Folders: []string{"Tech"}}, "https://example.com/feed Tech"},
+		{URLEntry{URL: "https://example.com/feed", Folders: []string{"Tech News", "Go"}}, `https://example.com/feed "Tech News",Go`},
+	}
+
+	for _, tt := range tests {
+		if got := FormatURLLine(tt.entry); got != tt.expected {
+			t.Errorf("FormatURLLine(%+v) = %q, want %q", tt.entry, got, tt.expected)
+		}
+	}
+}
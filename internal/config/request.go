@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RequestOptions are the HTTP settings used when fetching feeds, set globally
+// in the settings and per feed in the URLs file
+type RequestOptions struct {
+	Proxy     string      // HTTP(S) or SOCKS5 proxy URL, empty uses the environment (HTTP_PROXY etc.)
+	UserAgent string      // Replaces the default User-Agent when set
+	Headers   http.Header // Sent with every feed request
+}
+
+// Merge returns the options with the fields set in override taking precedence
+func (o RequestOptions) Merge(override RequestOptions) RequestOptions {
+	merged := RequestOptions{Proxy: o.Proxy, UserAgent: o.UserAgent}
+	if override.Proxy != "" {
+		merged.Proxy = override.Proxy
+	}
+	if override.UserAgent != "" {
+		merged.UserAgent = override.UserAgent
+	}
+	if len(o.Headers) > 0 || len(override.Headers) > 0 {
+		merged.Headers = o.Headers.Clone()
+		if merged.Headers == nil {
+			merged.Headers = make(http.Header)
+		}
+		for name, values := range override.Headers {
+			merged.Headers[name] = values
+		}
+	}
+	return merged
+}
+
+// Secrets returns the values of the credential headers, to keep them out of
+// logs
+func (o RequestOptions) Secrets() []string {
+	var secrets []string
+	for name, values := range o.Headers {
+		lower := strings.ToLower(name)
+		if lower == "authorization" || lower == "proxy-authorization" || lower == "cookie" ||
+			strings.Contains(lower, "token") || strings.Contains(lower, "key") || strings.Contains(lower, "secret") {
+			secrets = append(secrets, values...)
+		}
+	}
+	return secrets
+}
+
+// RequestOptions returns the global request options from the settings,
+// invalid values are left out
+func (c Config) RequestOptions() RequestOptions {
+	options := RequestOptions{UserAgent: c.UserAgent}
+	if _, err := ParseProxyURL(c.HTTPProxy); err == nil {
+		options.Proxy = c.HTTPProxy
+	}
+	if headers, err := ParseHeaders(c.RequestHeaders); err == nil {
+		options.Headers = headers
+	}
+	return options
+}
+
+// ParseProxyURL parses an http, https, socks5 or socks5h proxy URL
+func ParseProxyURL(value string) (*url.URL, error) {
+	if value == "" {
+		return nil, nil
+	}
+	proxyURL, err := url.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy %q: %w", value, err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid proxy %q: scheme must be http, https, socks5 or socks5h", value)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy %q: missing host", value)
+	}
+	return proxyURL, nil
+}
+
+// ParseHeaders parses headers separated by semicolons, e.g.
+// "Authorization: Bearer abc; Accept-Language: en"
+func ParseHeaders(value string) (http.Header, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	headers := make(http.Header)
+	for _, field := range strings.Split(value, ";") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		name, headerValue, ok := strings.Cut(field, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			// The field isn't quoted in the error, it may hold a secret
+			return nil, fmt.Errorf("invalid header, expected \"Name: value\" pairs separated by semicolons")
+		}
+		headers.Add(name, strings.TrimSpace(headerValue))
+	}
+	return headers, nil
+}
+
+// FeedRequestOptions returns the request options of the entries that set
+// any, keyed by URL. Entries with invalid options are returned in errs.
+func FeedRequestOptions(entries []URLEntry) (map[string]RequestOptions, []error) {
+	options := make(map[string]RequestOptions)
+	var errs []error
+	for _, entry := range entries {
+		entryOptions, err := entry.RequestOptions()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.URL, err))
+			continue
+		}
+		if entryOptions.Proxy != "" || entryOptions.UserAgent != "" || len(entryOptions.Headers) > 0 {
+			options[entry.URL] = entryOptions
+		}
+	}
+	return options, errs
+}
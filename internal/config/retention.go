@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Items pruning never deletes, set in PruneKeep
+const (
+	PruneKeepUnread  = "unread"
+	PruneKeepStarred = "starred"
+)
+
+// RetentionPolicy decides which items are deleted when feeds are pruned
+type RetentionPolicy struct {
+	MaxItems    int  // Items of a feed beyond the newest MaxItems are pruned, 0 for no limit
+	MaxAgeDays  int  // Items published more than MaxAgeDays ago are pruned, 0 for no limit
+	KeepUnread  bool // Unread items are never pruned
+	KeepStarred bool // Starred items are never pruned
+}
+
+// Enabled reports whether the policy prunes anything
+func (p RetentionPolicy) Enabled() bool {
+	return p.MaxItems > 0 || p.MaxAgeDays > 0
+}
+
+// RetentionPolicy returns the retention policy from the settings, an invalid
+// PruneKeep keeps both unread and starred items
+func (c Config) RetentionPolicy() RetentionPolicy {
+	policy := RetentionPolicy{MaxItems: c.MaxItemsPerFeed, MaxAgeDays: c.MaxItemAgeDays}
+	keep, err := ParsePruneKeep(c.PruneKeep)
+	if err != nil {
+		keep = []string{PruneKeepUnread, PruneKeepStarred}
+	}
+	for _, kind := range keep {
+		switch kind {
+		case PruneKeepUnread:
+			policy.KeepUnread = true
+		case PruneKeepStarred:
+			policy.KeepStarred = true
+		}
+	}
+	return policy
+}
+
+// ParsePruneKeep parses a comma-separated list of "unread" and "starred",
+// "none" or an empty value keeps nothing
+func ParsePruneKeep(value string) ([]string, error) {
+	var keep []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		switch field {
+		case "", "none":
+			continue
+		case PruneKeepUnread, PruneKeepStarred:
+			keep = append(keep, field)
+		default:
+			return nil, fmt.Errorf("unknown prune keep %q, use \"unread\", \"starred\" or \"none\"", field)
+		}
+	}
+	return keep, nil
+}
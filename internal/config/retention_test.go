@@ -0,0 +1,58 @@
+package config
+
+import "testing"
+
+func TestRetentionPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   RetentionPolicy
+	}{
+		{
+			name:   "defaults keep unread and starred items",
+			config: GetDefaultConfig(),
+			want:   RetentionPolicy{KeepUnread: true, KeepStarred: true},
+		},
+		{
+			name:   "limits",
+			config: Config{MaxItemsPerFeed: 200, MaxItemAgeDays: 30, PruneKeep: "starred"},
+			want:   RetentionPolicy{MaxItems: 200, MaxAgeDays: 30, KeepStarred: true},
+		},
+		{
+			name:   "keep nothing",
+			config: Config{MaxItemAgeDays: 7, PruneKeep: "none"},
+			want:   RetentionPolicy{MaxAgeDays: 7},
+		},
+		{
+			name:   "invalid keep falls back to keeping both",
+			config: Config{MaxItemsPerFeed: 50, PruneKeep: "unread,old"},
+			want:   RetentionPolicy{MaxItems: 50, KeepUnread: true, KeepStarred: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.RetentionPolicy(); got != tt.want {
+				t.Errorf("RetentionPolicy() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if GetDefaultConfig().RetentionPolicy().Enabled() {
+		t.Error("the default policy should not prune anything")
+	}
+}
+
+func TestParsePruneKeep(t *testing.T) {
+	keep, err := ParsePruneKeep(" Starred , unread")
+	if err != nil {
+		t.Fatalf("ParsePruneKeep() error = %v", err)
+	}
+	if len(keep) != 2 || keep[0] != PruneKeepStarred || keep[1] != PruneKeepUnread {
+		t.Errorf("ParsePruneKeep() = %v", keep)
+	}
+
+	if _, err := ParsePruneKeep("read"); err == nil {
+		t.Error("ParsePruneKeep() should reject unknown values")
+	}
+}
@@ -0,0 +1,30 @@
+package config
+
+import "time"
+
+// maxRetryDelay bounds how long a failed task waits before it runs again
+const maxRetryDelay = 24 * time.Hour
+
+// RetryPolicy decides how often and how long after a failed task runs again
+type RetryPolicy struct {
+	MaxRetries int           // Times a failed task runs again, 0 never retries
+	Delay      time.Duration // Wait before the first retry, doubled for every later one
+}
+
+// Backoff returns how long to wait before the given retry, the first is 1
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	delay := p.Delay
+	for i := 1; i < retry && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// RefreshRetryPolicy returns the retry policy of failed feed refreshes from
+// the settings
+func (c Config) RefreshRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: c.RefreshRetries,
+		Delay:      time.Duration(c.RefreshRetryDelay) * time.Minute,
+	}
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// ShareAction is a command an article can be shared with from the share menu
+// of the article view
+type ShareAction struct {
+	Name    string
+	Command string // Shell command, {url}, {title} and {author} are replaced with the article's
+}
+
+// ParseShareActions parses share actions separated by semicolons, e.g.
+// "Mastodon: toot {url}; Mail: mail -s {title} me@example.com"
+func ParseShareActions(value string) ([]ShareAction, error) {
+	var actions []ShareAction
+	for _, field := range strings.Split(value, ";") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		name, command, ok := strings.Cut(field, ":")
+		name, command = strings.TrimSpace(name), strings.TrimSpace(command)
+		if !ok || name == "" || command == "" {
+			return nil, fmt.Errorf("invalid share command %q, expected \"Name: command\" pairs separated by semicolons", field)
+		}
+		actions = append(actions, ShareAction{Name: name, Command: command})
+	}
+	return actions, nil
+}
+
+// ExpandShareCommand replaces the placeholders of a share command with the
+// values of an article. The values are quoted for the shell, titles and
+// authors come from feeds and can't be trusted to be plain text.
+func ExpandShareCommand(command string, values map[string]string) string {
+	pairs := make([]string, 0, 2*len(values))
+	for name, value := range values {
+		pairs = append(pairs, "{"+name+"}", shellQuote(value))
+	}
+	return strings.NewReplacer(pairs...).Replace(command)
+}
+
+// shellQuote quotes a value as a single argument of sh, or of cmd on Windows
+func shellQuote(value string) string {
+	if runtime.GOOS == "windows" {
+		return `"` + strings.ReplaceAll(value, `"`, `'`) + `"`
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseShareActions(t *testing.T) {
+	actions, err := ParseShareActions("Mastodon: toot {url}; Mail: mail -s {title} me@example.com ;")
+	if err != nil {
+		t.Fatalf("ParseShareActions() error = %v", err)
+	}
+	expected := []ShareAction{
+		{Name: "Mastodon", Command: "toot {url}"},
+		{Name: "Mail", Command: "mail -s {title} me@example.com"},
+	}
+	if !reflect.DeepEqual(actions, expected) {
+		t.Errorf("ParseShareActions() = %v, want %v", actions, expected)
+	}
+
+	if actions, err := ParseShareActions(" "); err != nil || actions != nil {
+		t.Errorf("ParseShareActions(empty) = %v, %v, want nil", actions, err)
+	}
+	for _, invalid := range []string{"toot {url}", ": toot {url}", "Mastodon:"} {
+		if _, err := ParseShareActions(invalid); err == nil {
+			t.Errorf("ParseShareActions(%q) expected an error", invalid)
+		}
+	}
+}
+
+func TestExpandShareCommand(t *testing.T) {
+	values := map[string]string{
+		"url":   "https://example.com/post?a=1&b=2",
+		"title": "It's $(rm -rf ~) time",
+	}
+	got := ExpandShareCommand("mail -s {title} me@example.com <<< {url} {author}", values)
+	expected := `mail -s 'It'\''s $(rm -rf ~) time' me@example.com <<< 'https://example.com/post?a=1&b=2' {author}`
+	if got != expected {
+		t.Errorf("ExpandShareCommand() = %q, want %q", got, expected)
+	}
+}
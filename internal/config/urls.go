@@ -2,24 +2,182 @@ package config
 
 import (
 	"bufio"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
-// URLEntry represents a feed URL with optional folders
+// Per-feed options that can be set in the URLs file with !name or !name=value
+const (
+	// OptionFullText downloads the linked article for every item of the feed
+	OptionFullText = "fulltext"
+	// OptionReloadInterval sets how often the feed is refreshed automatically, e.g. 30m or 2h
+	OptionReloadInterval = "reload_interval"
+	// OptionEnrich looks up the pull request and CI state of GitHub/GitLab commit feed items
+	OptionEnrich = "enrich"
+	// OptionToken sets the feed token of a GitHub/GitLab feed, $NAME reads it from the environment
+	OptionToken = "token"
+	// OptionAuth sets the credentials of the feed, e.g. basic:user:$PASSWORD, env:MY_TOKEN or bearer:keyring:work
+	OptionAuth = "auth"
+	// OptionProxy fetches the feed through an HTTP or SOCKS5 proxy, e.g. socks5://127.0.0.1:1080
+	OptionProxy = "proxy"
+	// OptionUserAgent replaces the User-Agent sent for the feed
+	OptionUserAgent = "user_agent"
+	// OptionHeaders adds request headers to the feed, e.g. "X-Api-Key: abc; Accept-Language: en"
+	OptionHeaders = "headers"
+	// OptionPause keeps the feed in the list but skips it when feeds are reloaded
+	OptionPause = "pause"
+	// OptionDirection sets the text direction of the feed's articles: rtl, ltr or auto
+	OptionDirection = "direction"
+	// OptionNotify sends a desktop notification about new items, =off turns it off for the feed
+	OptionNotify = "notify"
+)
+
+// Text directions of the direction option
+const (
+	DirectionAuto = "auto" // Each paragraph's direction comes from its first letter
+	DirectionLTR  = "ltr"
+	DirectionRTL  = "rtl"
+)
+
+// MinReloadInterval is the shortest accepted per-feed reload interval
+const MinReloadInterval = time.Minute
+
+// URLEntry represents a feed URL with optional folders, a custom title and
+// per-feed options
 type URLEntry struct {
 	URL     string
 	Folders []string
+	Title   string // Shown instead of the feed's own title, "~Title" in the file
+	Options map[string]string
+}
+
+// HasOption reports whether the option is set for the entry
+func (e URLEntry) HasOption(name string) bool {
+	_, ok := e.Options[name]
+	return ok
+}
+
+// Option returns the value of an option and whether it is set
+func (e URLEntry) Option(name string) (string, bool) {
+	value, ok := e.Options[name]
+	return value, ok
+}
+
+// ReloadInterval returns the feed's reload_interval option, or 0 when it isn't set
+func (e URLEntry) ReloadInterval() (time.Duration, error) {
+	value, ok := e.Option(OptionReloadInterval)
+	if !ok {
+		return 0, nil
+	}
+	return ParseReloadInterval(value)
+}
+
+// Direction returns the feed's direction option, DirectionAuto when it isn't set
+func (e URLEntry) Direction() (string, error) {
+	value, ok := e.Option(OptionDirection)
+	if !ok {
+		return DirectionAuto, nil
+	}
+	switch direction := strings.ToLower(value); direction {
+	case DirectionAuto, DirectionLTR, DirectionRTL:
+		return direction, nil
+	}
+	return DirectionAuto, fmt.Errorf("invalid direction %q, use rtl, ltr or auto", value)
+}
+
+// FeedDirections returns the direction of the entries that set one other
+// than auto, keyed by URL
+func FeedDirections(entries []URLEntry) (map[string]string, []error) {
+	directions := make(map[string]string)
+	var errs []error
+	for _, entry := range entries {
+		direction, err := entry.Direction()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.URL, err))
+			continue
+		}
+		if direction != DirectionAuto {
+			directions[entry.URL] = direction
+		}
+	}
+	return directions, errs
+}
+
+// RequestOptions returns the proxy, User-Agent and headers set for the feed
+func (e URLEntry) RequestOptions() (RequestOptions, error) {
+	var options RequestOptions
+	if proxy, ok := e.Option(OptionProxy); ok {
+		if _, err := ParseProxyURL(proxy); err != nil {
+			return options, err
+		}
+		options.Proxy = proxy
+	}
+	options.UserAgent, _ = e.Option(OptionUserAgent)
+	if value, ok := e.Option(OptionHeaders); ok {
+		headers, err := ParseHeaders(value)
+		if err != nil {
+			return options, err
+		}
+		options.Headers = headers
+	}
+	return options, nil
+}
+
+// ParseReloadInterval parses a duration such as 30m or 2h, a plain number is
+// taken as minutes like the global reload time
+func ParseReloadInterval(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		minutes, convErr := strconv.Atoi(value)
+		if convErr != nil {
+			return 0, fmt.Errorf("invalid reload interval %q: %w", value, err)
+		}
+		interval = time.Duration(minutes) * time.Minute
+	}
+	if interval < MinReloadInterval {
+		return 0, fmt.Errorf("reload interval %q is shorter than %s", value, MinReloadInterval)
+	}
+	return interval, nil
+}
+
+// QueryPrefix starts a line of the URLs file that defines a query feed, e.g.
+// query:"Unread Go" unread and feed.folder == "Go"
+const QueryPrefix = "query:"
+
+// QueryFeed is a virtual feed showing the items of every feed that match a
+// filter expression
+type QueryFeed struct {
+	Title  string
+	Filter string
 }
 
 // Line represents a line in the URLs file (either a URL entry or a comment/blank line)
 type Line struct {
 	Entry   *URLEntry
-	Raw     string // For comments and blank lines
+	Query   *QueryFeed // For query feeds, which are written back as they were read
+	Raw     string     // For comments, blank lines and query feeds
 	IsEntry bool
 }
 
+// parseQueryFeed parses the title and filter of a query feed line, the title
+// is quoted when it has spaces
+func parseQueryFeed(line string) QueryFeed {
+	rest := strings.TrimSpace(strings.TrimPrefix(line, QueryPrefix))
+	if strings.HasPrefix(rest, `"`) {
+		if end := strings.Index(rest[1:], `"`); end >= 0 {
+			return QueryFeed{Title: rest[1 : end+1], Filter: strings.TrimSpace(rest[end+2:])}
+		}
+	}
+	title, filter, _ := strings.Cut(rest, " ")
+	return QueryFeed{Title: strings.Trim(title, `"`), Filter: strings.TrimSpace(filter)}
+}
+
 // GetEditor returns the editor to use from the EDITOR environment variable
 func GetEditor() string {
 	return os.Getenv("EDITOR")
@@ -37,7 +195,7 @@ func GetURLsFilePath() (string, error) {
 		return newPath, nil
 	}
 
-	// Fall back to old location: ~/.newsgoat/urls
+	// Fall back to old location: ~/.newsgoat/urls, until it is moved at startup
 	oldPath := filepath.Join(homeDir, ".newsgoat", "urls")
 	if _, err := os.Stat(oldPath); err == nil {
 		return oldPath, nil
@@ -47,6 +205,44 @@ func GetURLsFilePath() (string, error) {
 	return newPath, nil
 }
 
+// GetKeysFilePath returns the path of the key bindings file
+func GetKeysFilePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "newsgoat", "keys"), nil
+}
+
+// GetHooksFilePath returns the path of the file with the commands run on
+// events
+func GetHooksFilePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "newsgoat", "hooks"), nil
+}
+
+// GetThemesDir returns the directory custom themes are loaded from
+func GetThemesDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "newsgoat", "themes"), nil
+}
+
+// GetImageCacheDir returns the directory downloaded article images are kept
+// in
+func GetImageCacheDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "newsgoat", "images"), nil
+}
+
 func ReadURLsFile() ([]URLEntry, error) {
 	urlsPath, err := GetURLsFilePath()
 	if err != nil {
@@ -56,8 +252,21 @@ func ReadURLsFile() ([]URLEntry, error) {
 	return ReadURLsFileFromPath(urlsPath)
 }
 
-// parseFolders parses a comma-separated list of folders, handling quoted strings
-func parseFolders(folderStr string) []string {
+// cleanFolder removes the spaces around the parts of a folder path like
+// "Tech / Go" and empty parts
+func cleanFolder(folder string) string {
+	var parts []string
+	for _, part := range strings.Split(folder, "/") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// ParseFolders parses a comma-separated list of folders, handling quoted
+// strings. A folder can be inside another one, like "Tech/Go".
+func ParseFolders(folderStr string) []string {
 	if folderStr == "" {
 		return nil
 	}
@@ -77,7 +286,7 @@ func parseFolders(folderStr string) []string {
 				current.WriteByte(ch)
 			} else {
 				// End of folder name
-				folder := strings.TrimSpace(current.String())
+				folder := cleanFolder(current.String())
 				if folder != "" {
 					folders = append(folders, folder)
 				}
@@ -89,7 +298,7 @@ func parseFolders(folderStr string) []string {
 	}
 
 	// Add last folder
-	folder := strings.TrimSpace(current.String())
+	folder := cleanFolder(current.String())
 	if folder != "" {
 		folders = append(folders, folder)
 	}
@@ -97,6 +306,103 @@ func parseFolders(folderStr string) []string {
 	return folders
 }
 
+// parseTitle reads a custom title written like newsboat does, "~Title", or
+// as ~"Title" or ~Title
+func parseTitle(field string) string {
+	title := strings.TrimPrefix(field, `"`)
+	title = strings.TrimPrefix(title, "~")
+	return strings.TrimSpace(strings.Trim(title, `"`))
+}
+
+// parseEntry builds an entry from the whitespace separated fields of a line.
+// Fields starting with ! outside of quotes are options, ~ starts the title,
+// everything else after the URL is the folder list.
+func parseEntry(parts []string) URLEntry {
+	entry := URLEntry{
+		URL: parts[0],
+	}
+
+	var folderParts []string
+	inQuotes := false
+	for i := 1; i < len(parts); i++ {
+		part := parts[i]
+		if !inQuotes && len(part) > 1 && strings.HasPrefix(part, "!") {
+			name, value, _ := strings.Cut(part[1:], "=")
+			// A quoted value such as !user_agent="My Reader" spans several fields
+			if strings.HasPrefix(value, `"`) {
+				for strings.Count(value, `"`)%2 == 1 && i+1 < len(parts) {
+					i++
+					value += " " + parts[i]
+				}
+				value = strings.Trim(value, `"`)
+			}
+			if entry.Options == nil {
+				entry.Options = make(map[string]string)
+			}
+			entry.Options[strings.ToLower(name)] = value
+			continue
+		}
+		if !inQuotes && (strings.HasPrefix(part, "~") || strings.HasPrefix(part, `"~`)) {
+			// A title with spaces is quoted and spans several fields
+			for strings.Count(part, `"`)%2 == 1 && i+1 < len(parts) {
+				i++
+				part += " " + parts[i]
+			}
+			entry.Title = parseTitle(part)
+			continue
+		}
+		if strings.Count(part, `"`)%2 == 1 {
+			inQuotes = !inQuotes
+		}
+		folderParts = append(folderParts, part)
+	}
+
+	if len(folderParts) > 0 {
+		entry.Folders = ParseFolders(strings.Join(folderParts, " "))
+	}
+
+	return entry
+}
+
+// FormatURLLine formats an entry as a line of the URLs file, quoting folder
+// names that contain spaces or commas. The title is written the way newsboat
+// reads it.
+func FormatURLLine(entry URLEntry) string {
+	line := entry.URL
+
+	if len(entry.Folders) > 0 {
+		folders := make([]string, len(entry.Folders))
+		for i, folder := range entry.Folders {
+			if strings.ContainsAny(folder, " ,") {
+				folder = `"` + strings.ReplaceAll(folder, `"`, "") + `"`
+			}
+			folders[i] = folder
+		}
+		line += " " + strings.Join(folders, ",")
+	}
+
+	if entry.Title != "" {
+		line += ` "~` + strings.ReplaceAll(entry.Title, `"`, "") + `"`
+	}
+
+	names := make([]string, 0, len(entry.Options))
+	for name := range entry.Options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		line += " !" + name
+		if value := entry.Options[name]; value != "" {
+			if strings.ContainsAny(value, " \t") {
+				value = `"` + strings.ReplaceAll(value, `"`, "") + `"`
+			}
+			line += "=" + value
+		}
+	}
+
+	return line
+}
+
 func ReadURLsFileFromPath(urlsPath string) ([]URLEntry, error) {
 	lines, err := ReadAllLinesFromPath(urlsPath)
 	if err != nil {
@@ -113,6 +419,24 @@ func ReadURLsFileFromPath(urlsPath string) ([]URLEntry, error) {
 	return entries, nil
 }
 
+// ReadQueryFeedsFromPath returns the query feeds defined in the URLs file, in
+// the order they are written
+func ReadQueryFeedsFromPath(urlsPath string) ([]QueryFeed, error) {
+	lines, err := ReadAllLinesFromPath(urlsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var queries []QueryFeed
+	for _, line := range lines {
+		if line.Query != nil {
+			queries = append(queries, *line.Query)
+		}
+	}
+
+	return queries, nil
+}
+
 // ReadAllLinesFromPath reads all lines from the URLs file, preserving comments and blank lines
 func ReadAllLinesFromPath(urlsPath string) ([]Line, error) {
 	file, err := os.Open(urlsPath)
@@ -141,7 +465,16 @@ func ReadAllLinesFromPath(urlsPath string) ([]Line, error) {
 			continue
 		}
 
-		// Split on first whitespace to separate URL from folders
+		if strings.HasPrefix(trimmedLine, QueryPrefix) {
+			query := parseQueryFeed(trimmedLine)
+			lines = append(lines, Line{
+				Query: &query,
+				Raw:   rawLine,
+			})
+			continue
+		}
+
+		// Split on whitespace to separate URL from folders and options
 		parts := strings.Fields(trimmedLine)
 		if len(parts) == 0 {
 			lines = append(lines, Line{
@@ -151,16 +484,7 @@ func ReadAllLinesFromPath(urlsPath string) ([]Line, error) {
 			continue
 		}
 
-		entry := URLEntry{
-			URL: parts[0],
-		}
-
-		// If there are more parts, parse folders
-		if len(parts) > 1 {
-			// Join remaining parts and parse as folders
-			folderStr := strings.Join(parts[1:], " ")
-			entry.Folders = parseFolders(folderStr)
-		}
+		entry := parseEntry(parts)
 
 		lines = append(lines, Line{
 			Entry:   &entry,
@@ -219,10 +543,7 @@ func WriteAllLines(urlsPath string, lines []Line) error {
 	for _, line := range lines {
 		var output string
 		if line.IsEntry {
-			output = line.Entry.URL
-			if len(line.Entry.Folders) > 0 {
-				output += " " + strings.Join(line.Entry.Folders, ",")
-			}
+			output = FormatURLLine(*line.Entry)
 		} else {
 			output = line.Raw
 		}
@@ -263,7 +584,7 @@ func AddURL(url string) error {
 	return WriteAllLines(urlsPath, lines)
 }
 
-// AddURLLine adds a complete URL line (including folders) to the URLs file
+// AddURLLine adds a complete URL line (including folders and options) to the URLs file
 func AddURLLine(lineStr string) error {
 	urlsPath, err := GetURLsFilePath()
 	if err != nil {
@@ -290,13 +611,7 @@ func AddURLLine(lineStr string) error {
 	}
 
 	// Parse the new entry
-	entry := URLEntry{
-		URL: newURL,
-	}
-	if len(parts) > 1 {
-		folderStr := strings.Join(parts[1:], " ")
-		entry.Folders = parseFolders(folderStr)
-	}
+	entry := parseEntry(parts)
 
 	// Add the new line
 	lines = append(lines, Line{
@@ -330,6 +645,112 @@ func RemoveURL(url string) error {
 	return WriteAllLines(urlsPath, newLines)
 }
 
+// SetURLOption sets or clears a flag option, such as !pause, on the line of a
+// URL in the URLs file at urlsPath
+func SetURLOption(urlsPath, url, name string, set bool) error {
+	lines, err := ReadAllLinesFromPath(urlsPath)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, line := range lines {
+		if !line.IsEntry || line.Entry.URL != url {
+			continue
+		}
+		found = true
+		if set {
+			if line.Entry.Options == nil {
+				line.Entry.Options = make(map[string]string)
+			}
+			line.Entry.Options[name] = ""
+		} else {
+			delete(line.Entry.Options, name)
+		}
+	}
+	if !found {
+		return fmt.Errorf("%s is not in the URLs file", url)
+	}
+
+	return WriteAllLines(urlsPath, lines)
+}
+
+// SetURLFolders replaces the folders of the line of a URL in the URLs file at
+// urlsPath, no folders removes the feed from all of them
+func SetURLFolders(urlsPath, url string, folders []string) error {
+	lines, err := ReadAllLinesFromPath(urlsPath)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, line := range lines {
+		if line.IsEntry && line.Entry.URL == url {
+			found = true
+			line.Entry.Folders = folders
+		}
+	}
+	if !found {
+		return fmt.Errorf("%s is not in the URLs file", url)
+	}
+
+	return WriteAllLines(urlsPath, lines)
+}
+
+// SetURLTitle sets the custom title of the line of a URL in the URLs file at
+// urlsPath, an empty title goes back to the feed's own
+func SetURLTitle(urlsPath, url, title string) error {
+	lines, err := ReadAllLinesFromPath(urlsPath)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, line := range lines {
+		if line.IsEntry && line.Entry.URL == url {
+			found = true
+			line.Entry.Title = strings.TrimSpace(title)
+		}
+	}
+	if !found {
+		return fmt.Errorf("%s is not in the URLs file", url)
+	}
+
+	return WriteAllLines(urlsPath, lines)
+}
+
+// ReplaceURL changes the URL of a line in the URLs file at urlsPath, keeping
+// its folders and options
+func ReplaceURL(urlsPath, oldURL, newURL string) error {
+	lines, err := ReadAllLinesFromPath(urlsPath)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, line := range lines {
+		if !line.IsEntry {
+			continue
+		}
+		if line.Entry.URL == newURL {
+			return fmt.Errorf("%s is already in the URLs file", newURL)
+		}
+		if line.Entry.URL == oldURL {
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("%s is not in the URLs file", oldURL)
+	}
+
+	for _, line := range lines {
+		if line.IsEntry && line.Entry.URL == oldURL {
+			line.Entry.URL = newURL
+		}
+	}
+	return WriteAllLines(urlsPath, lines)
+}
+
 func CreateSampleURLsFile() error {
 	urlsPath, err := GetURLsFilePath()
 	if err != nil {
@@ -358,15 +779,29 @@ func CreateSampleURLsFile() error {
 	// Write header with instructions and examples
 	header := `# Add your RSS feeds to this file
 #
-# Format: <url> [folder1,folder2,...]
+# Format: <url> [folder1,folder2,...] ["~Title"] [!option ...]
 # - Each line should contain a feed URL
 # - Optionally, you can add one or more folder names after the URL (comma-separated)
 # - Folders with spaces should be quoted: "Folder Name"
+# - "~My Title" shows the feed as My Title instead of the title it has
+# - Options start with ! and change how a feed is fetched:
+#     !fulltext  download the full article for feeds that only publish summaries
+#     !reload_interval=30m  refresh the feed on its own schedule instead of the global reload time
+#     !enrich  show the pull request and CI state of GitHub/GitLab commits
+#     !token=$WORK_GITHUB_TOKEN  feed token for this GitHub/GitLab feed, read from the environment
+#     !auth=basic:user:$FEED_PASSWORD  credentials of the feed, also bearer:$TOKEN or env:MY_TOKEN
+#     !proxy=socks5://127.0.0.1:1080  fetch the feed through a proxy
+#     !user_agent="My Reader"  User-Agent sent for the feed
+#     !headers="X-Api-Key: abc; Accept-Language: en"  extra request headers
+#     !pause  keep the feed but stop refreshing it
+#     !direction=rtl  show articles right-to-left, or ltr, instead of detecting it
+#     !notify  send a desktop notification about new items, !notify=off to mute the feed
 # - Lines starting with # are comments and will be ignored
 #
 # For example:
 # https://www.newscientist.com/feed/home/
 # https://arstechnica.com/feed/ "Tech News"
+# https://example.com/summaries.xml News !fulltext
 #
 `
 
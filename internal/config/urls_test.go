@@ -3,7 +3,10 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestCommentPreservation(t *testing.T) {
@@ -189,3 +192,425 @@ https://example.com/feed3.xml
 		t.Errorf("Content mismatch after RemoveURL.\nExpected:\n%s\n\nGot:\n%s", expectedContent, finalContent)
 	}
 }
+
+func TestFeedOptions(t *testing.T) {
+	testDir := t.TempDir()
+	urlsPath := filepath.Join(testDir, "urls")
+
+	initialContent := `https://example.com/feed1.xml !fulltext
+https://example.com/feed2.xml "Tech News",Go !fulltext
+https://example.com/feed3.xml News
+`
+
+	err := os.WriteFile(urlsPath, []byte(initialContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write initial file: %v", err)
+	}
+
+	entries, err := ReadURLsFileFromPath(urlsPath)
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	if !entries[0].HasOption(OptionFullText) || len(entries[0].Folders) != 0 {
+		t.Errorf("Expected fulltext option without folders, got %+v", entries[0])
+	}
+	if !entries[1].HasOption(OptionFullText) {
+		t.Errorf("Expected fulltext option, got %+v", entries[1])
+	}
+	if len(entries[1].Folders) != 2 || entries[1].Folders[0] != "Tech News" || entries[1].Folders[1] != "Go" {
+		t.Errorf("Expected folders [Tech News Go], got %v", entries[1].Folders)
+	}
+	if entries[2].HasOption(OptionFullText) {
+		t.Errorf("Expected no options, got %+v", entries[2])
+	}
+
+	// Options and quoted folders survive a rewrite
+	lines, err := ReadAllLinesFromPath(urlsPath)
+	if err != nil {
+		t.Fatalf("Failed to read lines: %v", err)
+	}
+	if err := WriteAllLines(urlsPath, lines); err != nil {
+		t.Fatalf("Failed to write lines: %v", err)
+	}
+
+	content, err := os.ReadFile(urlsPath)
+	if err != nil {
+		t.Fatalf("Failed to read final file: %v", err)
+	}
+	if string(content) != initialContent {
+		t.Errorf("Content mismatch after rewrite.\nExpected:\n%s\n\nGot:\n%s", initialContent, string(content))
+	}
+}
+
+func TestParseReloadInterval(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"30m", 30 * time.Minute, false},
+		{"2h", 2 * time.Hour, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"45", 45 * time.Minute, false},
+		{"30s", 0, true},
+		{"0", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseReloadInterval(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseReloadInterval(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseReloadInterval(%q) = %v, want %v", tt.value, got, tt.expected)
+		}
+	}
+
+	entry := URLEntry{URL: "https://example.com/feed.xml", Options: map[string]string{OptionReloadInterval: "15m"}}
+	if got, err := entry.ReloadInterval(); err != nil || got != 15*time.Minute {
+		t.Errorf("ReloadInterval() = %v, %v, want 15m", got, err)
+	}
+	if got, err := (URLEntry{}).ReloadInterval(); err != nil || got != 0 {
+		t.Errorf("ReloadInterval() without option = %v, %v, want 0", got, err)
+	}
+}
+
+func TestFeedTokens(t *testing.T) {
+	t.Setenv("WORK_GITHUB_TOKEN", "from-env")
+
+	entries := []URLEntry{
+		{URL: "https://github.com/work/repo/commits/main.atom", Options: map[string]string{OptionToken: "$WORK_GITHUB_TOKEN"}},
+		{URL: "https://github.com/work/other/commits/main.atom", Options: map[string]string{OptionToken: "${WORK_GITHUB_TOKEN}"}},
+		{URL: "https://gitlab.com/me/repo/-/commits/main?format=atom", Options: map[string]string{OptionToken: "literal"}},
+		{URL: "https://github.com/me/repo/commits/main.atom", Options: map[string]string{OptionToken: "$UNSET_GITHUB_TOKEN"}},
+		{URL: "https://example.com/feed.xml"},
+	}
+
+	auths, errs := FeedAuths(entries)
+	if len(errs) > 0 {
+		t.Fatalf("FeedAuths() errors = %v", errs)
+	}
+	expected := map[string]string{
+		entries[0].URL: "from-env",
+		entries[1].URL: "from-env",
+		entries[2].URL: "literal",
+	}
+	if len(auths) != len(expected) {
+		t.Fatalf("FeedAuths() = %v, want tokens %v", auths, expected)
+	}
+	for url, token := range expected {
+		if auths[url].Scheme != AuthToken || auths[url].Secret != token {
+			t.Errorf("auth for %s = %+v, want token %q", url, auths[url], token)
+		}
+	}
+}
+
+func TestFeedDirections(t *testing.T) {
+	entries := []URLEntry{
+		{URL: "https://example.com/ar.xml", Options: map[string]string{OptionDirection: "RTL"}},
+		{URL: "https://example.com/en.xml", Options: map[string]string{OptionDirection: "ltr"}},
+		{URL: "https://example.com/mixed.xml", Options: map[string]string{OptionDirection: "auto"}},
+		{URL: "https://example.com/bad.xml", Options: map[string]string{OptionDirection: "up"}},
+		{URL: "https://example.com/feed.xml"},
+	}
+
+	directions, errs := FeedDirections(entries)
+	expected := map[string]string{
+		entries[0].URL: DirectionRTL,
+		entries[1].URL: DirectionLTR,
+	}
+	if !reflect.DeepEqual(directions, expected) {
+		t.Errorf("FeedDirections() = %v, want %v", directions, expected)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), entries[3].URL) {
+		t.Errorf("FeedDirections() errors = %v, want one for %s", errs, entries[3].URL)
+	}
+}
+
+func TestQuotedFeedOptions(t *testing.T) {
+	testDir := t.TempDir()
+	urlsPath := filepath.Join(testDir, "urls")
+
+	initialContent := `https://example.com/feed.xml News !headers="X-Api-Key: abc; Accept-Language: en" !proxy=socks5://127.0.0.1:1080 !user_agent="My Reader/1.0"
+`
+
+	if err := os.WriteFile(urlsPath, []byte(initialContent), 0644); err != nil {
+		t.Fatalf("Failed to write initial file: %v", err)
+	}
+
+	entries, err := ReadURLsFileFromPath(urlsPath)
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+	if len(entries) != 1 || len(entries[0].Folders) != 1 || entries[0].Folders[0] != "News" {
+		t.Fatalf("Unexpected entries: %+v", entries)
+	}
+
+	options, err := entries[0].RequestOptions()
+	if err != nil {
+		t.Fatalf("RequestOptions() error = %v", err)
+	}
+	if options.Proxy != "socks5://127.0.0.1:1080" || options.UserAgent != "My Reader/1.0" {
+		t.Errorf("Unexpected request options: %+v", options)
+	}
+	if options.Headers.Get("X-Api-Key") != "abc" || options.Headers.Get("Accept-Language") != "en" {
+		t.Errorf("Unexpected headers: %v", options.Headers)
+	}
+
+	// Quoted option values survive a rewrite
+	lines, err := ReadAllLinesFromPath(urlsPath)
+	if err != nil {
+		t.Fatalf("Failed to read lines: %v", err)
+	}
+	if err := WriteAllLines(urlsPath, lines); err != nil {
+		t.Fatalf("Failed to write lines: %v", err)
+	}
+	content, err := os.ReadFile(urlsPath)
+	if err != nil {
+		t.Fatalf("Failed to read final file: %v", err)
+	}
+	if string(content) != initialContent {
+		t.Errorf("Content mismatch after rewrite.\nExpected:\n%s\n\nGot:\n%s", initialContent, string(content))
+	}
+
+	invalid := URLEntry{URL: "https://example.com/feed.xml", Options: map[string]string{OptionProxy: "proxy:3128"}}
+	if _, errs := FeedRequestOptions([]URLEntry{invalid, entries[0]}); len(errs) != 1 {
+		t.Errorf("FeedRequestOptions() errors = %v, want one for the invalid proxy", errs)
+	}
+}
+
+func TestSetURLOption(t *testing.T) {
+	testDir := t.TempDir()
+	urlsPath := filepath.Join(testDir, "urls")
+
+	initialContent := `# Noisy feeds
+https://example.com/feed1.xml News
+https://example.com/feed2.xml
+`
+
+	if err := os.WriteFile(urlsPath, []byte(initialContent), 0644); err != nil {
+		t.Fatalf("Failed to write initial file: %v", err)
+	}
+
+	if err := SetURLOption(urlsPath, "https://example.com/feed1.xml", OptionPause, true); err != nil {
+		t.Fatalf("SetURLOption() error = %v", err)
+	}
+	content, err := os.ReadFile(urlsPath)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	paused := `# Noisy feeds
+https://example.com/feed1.xml News !pause
+https://example.com/feed2.xml
+`
+	if string(content) != paused {
+		t.Errorf("Content mismatch after pausing.\nExpected:\n%s\n\nGot:\n%s", paused, string(content))
+	}
+
+	if err := SetURLOption(urlsPath, "https://example.com/feed1.xml", OptionPause, false); err != nil {
+		t.Fatalf("SetURLOption() error = %v", err)
+	}
+	content, err = os.ReadFile(urlsPath)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(content) != initialContent {
+		t.Errorf("Content mismatch after resuming.\nExpected:\n%s\n\nGot:\n%s", initialContent, string(content))
+	}
+
+	if err := SetURLOption(urlsPath, "https://example.com/missing.xml", OptionPause, true); err == nil {
+		t.Error("SetURLOption() should fail for a URL that isn't in the file")
+	}
+}
+
+func TestParseNestedFolders(t *testing.T) {
+	got := ParseFolders(`Tech/Go, "Tech / Web Dev",/News/, Blogs`)
+	want := []string{"Tech/Go", "Tech/Web Dev", "News", "Blogs"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseFolders() = %q, want %q", got, want)
+	}
+}
+
+func TestSetURLFolders(t *testing.T) {
+	testDir := t.TempDir()
+	urlsPath := filepath.Join(testDir, "urls")
+
+	initialContent := `# Blogs
+https://example.com/feed1.xml News !pause
+https://example.com/feed2.xml
+`
+
+	if err := os.WriteFile(urlsPath, []byte(initialContent), 0644); err != nil {
+		t.Fatalf("Failed to write initial file: %v", err)
+	}
+
+	if err := SetURLFolders(urlsPath, "https://example.com/feed2.xml", ParseFolders(`Tech, "Go Blogs"`)); err != nil {
+		t.Fatalf("SetURLFolders() error = %v", err)
+	}
+	if err := SetURLFolders(urlsPath, "https://example.com/feed1.xml", nil); err != nil {
+		t.Fatalf("SetURLFolders() error = %v", err)
+	}
+	content, err := os.ReadFile(urlsPath)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	expected := `# Blogs
+https://example.com/feed1.xml !pause
+https://example.com/feed2.xml Tech,"Go Blogs"
+`
+	if string(content) != expected {
+		t.Errorf("Content mismatch after setting folders.\nExpected:\n%s\n\nGot:\n%s", expected, string(content))
+	}
+
+	if err := SetURLFolders(urlsPath, "https://example.com/missing.xml", []string{"Tech"}); err == nil {
+		t.Error("SetURLFolders() should fail for a URL that isn't in the file")
+	}
+}
+
+func TestFeedTitles(t *testing.T) {
+	tests := []struct {
+		line    string
+		title   string
+		folders []string
+	}{
+		{`https://example.com/feed.xml "~Go Blog"`, "Go Blog", nil},
+		{`https://example.com/feed.xml Tech ~"Go Blog" !pause`, "Go Blog", []string{"Tech"}},
+		{`https://example.com/feed.xml "Go Blogs",Tech ~Golang`, "Golang", []string{"Go Blogs", "Tech"}},
+		{`https://example.com/feed.xml Tech`, "", []string{"Tech"}},
+	}
+	for _, tt := range tests {
+		entry := parseEntry(strings.Fields(tt.line))
+		if entry.Title != tt.title {
+			t.Errorf("parseEntry(%q).Title = %q, want %q", tt.line, entry.Title, tt.title)
+		}
+		if !reflect.DeepEqual(entry.Folders, tt.folders) {
+			t.Errorf("parseEntry(%q).Folders = %v, want %v", tt.line, entry.Folders, tt.folders)
+		}
+	}
+
+	testDir := t.TempDir()
+	urlsPath := filepath.Join(testDir, "urls")
+	initialContent := `https://example.com/feed1.xml Tech !pause
+https://example.com/feed2.xml "~Old Name"
+`
+	if err := os.WriteFile(urlsPath, []byte(initialContent), 0644); err != nil {
+		t.Fatalf("Failed to write initial file: %v", err)
+	}
+	if err := SetURLTitle(urlsPath, "https://example.com/feed1.xml", "My Tech"); err != nil {
+		t.Fatalf("SetURLTitle() error = %v", err)
+	}
+	if err := SetURLTitle(urlsPath, "https://example.com/feed2.xml", ""); err != nil {
+		t.Fatalf("SetURLTitle() error = %v", err)
+	}
+	content, err := os.ReadFile(urlsPath)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	expected := `https://example.com/feed1.xml Tech "~My Tech" !pause
+https://example.com/feed2.xml
+`
+	if string(content) != expected {
+		t.Errorf("Content mismatch after setting titles.\nExpected:\n%s\n\nGot:\n%s", expected, string(content))
+	}
+
+	if err := SetURLTitle(urlsPath, "https://example.com/missing.xml", "Missing"); err == nil {
+		t.Error("SetURLTitle() should fail for a URL that isn't in the file")
+	}
+}
+
+func TestReplaceURL(t *testing.T) {
+	testDir := t.TempDir()
+	urlsPath := filepath.Join(testDir, "urls")
+
+	initialContent := `# Moved feeds
+https://example.com/old.xml News !fulltext
+https://example.com/other.xml
+`
+	if err := os.WriteFile(urlsPath, []byte(initialContent), 0644); err != nil {
+		t.Fatalf("Failed to write initial file: %v", err)
+	}
+
+	if err := ReplaceURL(urlsPath, "https://example.com/old.xml", "https://example.com/new.xml"); err != nil {
+		t.Fatalf("ReplaceURL() error = %v", err)
+	}
+	content, err := os.ReadFile(urlsPath)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	expected := `# Moved feeds
+https://example.com/new.xml News !fulltext
+https://example.com/other.xml
+`
+	if string(content) != expected {
+		t.Errorf("Content mismatch.\nExpected:\n%s\n\nGot:\n%s", expected, string(content))
+	}
+
+	if err := ReplaceURL(urlsPath, "https://example.com/new.xml", "https://example.com/other.xml"); err == nil {
+		t.Error("ReplaceURL() should fail for a URL that is already in the file")
+	}
+	if err := ReplaceURL(urlsPath, "https://example.com/missing.xml", "https://example.com/x.xml"); err == nil {
+		t.Error("ReplaceURL() should fail for a URL that isn't in the file")
+	}
+}
+
+func TestQueryFeeds(t *testing.T) {
+	testDir := t.TempDir()
+	urlsPath := filepath.Join(testDir, "urls")
+
+	initialContent := `https://example.com/feed.xml Go
+query:"Unread Go" unread and feed.folder == "Go"
+  query:Starred starred
+`
+
+	if err := os.WriteFile(urlsPath, []byte(initialContent), 0644); err != nil {
+		t.Fatalf("Failed to write initial file: %v", err)
+	}
+
+	// Query feeds aren't feed URLs
+	entries, err := ReadURLsFileFromPath(urlsPath)
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Unexpected entries: %+v", entries)
+	}
+
+	queries, err := ReadQueryFeedsFromPath(urlsPath)
+	if err != nil {
+		t.Fatalf("Failed to read query feeds: %v", err)
+	}
+	want := []QueryFeed{
+		{Title: "Unread Go", Filter: `unread and feed.folder == "Go"`},
+		{Title: "Starred", Filter: "starred"},
+	}
+	if len(queries) != len(want) {
+		t.Fatalf("ReadQueryFeedsFromPath() = %+v, want %+v", queries, want)
+	}
+	for i := range want {
+		if queries[i] != want[i] {
+			t.Errorf("query feed %d = %+v, want %+v", i, queries[i], want[i])
+		}
+	}
+
+	// Query feeds are written back unchanged
+	lines, err := ReadAllLinesFromPath(urlsPath)
+	if err != nil {
+		t.Fatalf("Failed to read lines: %v", err)
+	}
+	if err := WriteAllLines(urlsPath, lines); err != nil {
+		t.Fatalf("Failed to write lines: %v", err)
+	}
+	content, err := os.ReadFile(urlsPath)
+	if err != nil {
+		t.Fatalf("Failed to read final file: %v", err)
+	}
+	if string(content) != initialContent {
+		t.Errorf("Content mismatch after rewrite.\nExpected:\n%s\n\nGot:\n%s", initialContent, string(content))
+	}
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/ncruces/go-sqlite3"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+)
+
+// foldReplacer spells out letters that don't decompose into a base letter and
+// a combining mark
+var foldReplacer = strings.NewReplacer(
+	"ß", "ss",
+	"æ", "ae",
+	"œ", "oe",
+	"ø", "o",
+	"đ", "d",
+	"ð", "d",
+	"ł", "l",
+	"þ", "th",
+	"ı", "i",
+)
+
+// NormalizeText returns s in Unicode normalization form C so that equal
+// strings are stored with the same bytes
+func NormalizeText(s string) string {
+	return norm.NFC.String(s)
+}
+
+// FoldText returns the key used to compare text ignoring case, accents and
+// character width, so "München" and "MUNCHEN" fold to the same string
+func FoldText(s string) string {
+	t := transform.Chain(width.Fold, norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, s)
+	if err != nil {
+		folded = s
+	}
+	return foldReplacer.Replace(strings.ToLower(folded))
+}
+
+// registerFunctions adds the custom SQL functions used by the queries to a
+// new connection
+func registerFunctions(conn *sqlite3.Conn) error {
+	return conn.CreateFunction("fold", 1, sqlite3.DETERMINISTIC|sqlite3.INNOCUOUS, func(ctx sqlite3.Context, arg ...sqlite3.Value) {
+		if arg[0].Type() == sqlite3.NULL {
+			ctx.ResultNull()
+			return
+		}
+		ctx.ResultText(FoldText(arg[0].Text()))
+	})
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/ncruces/go-sqlite3/driver"
+)
+
+func TestNormalizeText(t *testing.T) {
+	decomposed := "Mu\u0308nchen"
+	if got := NormalizeText(decomposed); got != "München" {
+		t.Errorf("NormalizeText(%q) = %q, want %q", decomposed, got, "München")
+	}
+}
+
+func TestFoldText(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"München", "munchen"},
+		{"Mu\u0308nchen", "munchen"},
+		{"MÜNCHEN", "munchen"},
+		{"Crème Brûlée", "creme brulee"},
+		{"Straße", "strasse"},
+		{"Łódź", "lodz"},
+		{"ＧＯ言語", "go言語"},
+		{"東京", "東京"},
+	}
+
+	for _, tt := range tests {
+		if got := FoldText(tt.input); got != tt.expected {
+			t.Errorf("FoldText(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestFoldFunction(t *testing.T) {
+	db, err := driver.Open(":memory:", registerFunctions)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	var matches bool
+	err = db.QueryRow("SELECT fold(?) LIKE '%' || fold(?) || '%'", "Oktoberfest in München", "Munchen").Scan(&matches)
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if !matches {
+		t.Error("expected \"Munchen\" to match \"Oktoberfest in München\"")
+	}
+
+	var folded *string
+	if err := db.QueryRow("SELECT fold(NULL)").Scan(&folded); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if folded != nil {
+		t.Errorf("fold(NULL) = %q, want NULL", *folded)
+	}
+}
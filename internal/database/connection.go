@@ -2,45 +2,46 @@ package database
 
 import (
 	"database/sql"
+	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"time"
 
-	_ "github.com/ncruces/go-sqlite3/driver"
+	"github.com/ncruces/go-sqlite3/driver"
 	_ "github.com/ncruces/go-sqlite3/embed"
 )
 
+// busyTimeout is how long a connection waits for another one, possibly of
+// another NewsGoat, to finish writing before it fails with SQLITE_BUSY
+const busyTimeout = 10 * time.Second
+
 func InitDB() (*sql.DB, *Queries, error) {
 	return InitDBWithSchema("")
 }
 
-func InitDBWithSchema(schemaSQL string) (*sql.DB, *Queries, error) {
-	homeDir, err := os.UserHomeDir()
+// InitDBReadOnly opens the database without being able to change it, for a
+// NewsGoat started while another one is running
+func InitDBReadOnly() (*sql.DB, *Queries, error) {
+	dbPath, err := databasePath()
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// Try new location first: ~/.config/newsgoat/
-	newDir := filepath.Join(homeDir, ".config", "newsgoat")
-	newPath := filepath.Join(newDir, "newsgoat.db")
-
-	// Check if database exists in old location
-	oldDir := filepath.Join(homeDir, ".newsgoat")
-	oldPath := filepath.Join(oldDir, "newsgoat.db")
+	db, err := driver.Open(dataSourceName(dbPath, true), registerFunctions)
+	if err != nil {
+		return nil, nil, err
+	}
+	return db, New(db), nil
+}
 
-	var dbPath string
-	if _, err := os.Stat(oldPath); err == nil {
-		// Use old location if it exists
-		dbPath = oldPath
-	} else {
-		// Use new location (create directory if needed)
-		if err := os.MkdirAll(newDir, 0755); err != nil {
-			return nil, nil, err
-		}
-		dbPath = newPath
+func InitDBWithSchema(schemaSQL string) (*sql.DB, *Queries, error) {
+	dbPath, err := databasePath()
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Open database with standard SQLite driver
-	db, err := sql.Open("sqlite3", dbPath)
+	db, queries, err := Open(dbPath)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -59,10 +60,68 @@ func InitDBWithSchema(schemaSQL string) (*sql.DB, *Queries, error) {
 		}
 	}
 
-	queries := New(db)
 	return db, queries, nil
 }
 
+// Open opens the database file at path like InitDB does, without running
+// migrations. Tests and benchmarks use it for a database in a temp dir.
+func Open(path string) (*sql.DB, *Queries, error) {
+	// Open database with the SQLite driver, registering custom functions on each connection
+	db, err := driver.Open(dataSourceName(path, false), registerFunctions)
+	if err != nil {
+		return nil, nil, err
+	}
+	return db, New(db), nil
+}
+
+// dataSourceName returns the URI the database at path is opened with. Every
+// connection waits for writers instead of failing, and the write-ahead log
+// lets the UI and a NewsGoat opened read-only read while a refresh writes.
+// Transactions take the write lock when they begin, one that started
+// reading couldn't wait for it without deadlocking.
+func dataSourceName(path string, readOnly bool) string {
+	query := url.Values{}
+	query.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()))
+	if readOnly {
+		query.Set("mode", "ro")
+	} else {
+		query.Add("_pragma", "journal_mode(wal)")
+		query.Add("_pragma", "synchronous(normal)")
+		query.Set("_txlock", "immediate")
+	}
+	uri := url.URL{Scheme: "file", Path: path, RawQuery: query.Encode()}
+	return uri.String()
+}
+
+// databasePath returns the path of the database file
+func databasePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	// Try new location first: ~/.config/newsgoat/
+	newDir := filepath.Join(homeDir, ".config", "newsgoat")
+	newPath := filepath.Join(newDir, "newsgoat.db")
+
+	// Check if database exists in old location
+	oldDir := filepath.Join(homeDir, ".newsgoat")
+	oldPath := filepath.Join(oldDir, "newsgoat.db")
+
+	var dbPath string
+	if _, err := os.Stat(oldPath); err == nil {
+		// Use old location if it exists, until it is moved at startup
+		dbPath = oldPath
+	} else {
+		// Use new location (create directory if needed)
+		if err := os.MkdirAll(newDir, 0755); err != nil {
+			return "", err
+		}
+		dbPath = newPath
+	}
+	return dbPath, nil
+}
+
 func createTables(db *sql.DB, schemaSQL string) error {
 	_, err := db.Exec(schemaSQL)
 	return err
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ncruces/go-sqlite3/driver"
+)
+
+func TestDataSourceNameWAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "newsgoat.db")
+	db, err := driver.Open(dataSourceName(path, false), registerFunctions)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	var mode string
+	if err := db.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil {
+		t.Fatalf("PRAGMA journal_mode error = %v", err)
+	}
+	if mode != "wal" {
+		t.Errorf("journal_mode = %q, want wal", mode)
+	}
+	var timeout int
+	if err := db.QueryRow("PRAGMA busy_timeout").Scan(&timeout); err != nil {
+		t.Fatalf("PRAGMA busy_timeout error = %v", err)
+	}
+	if timeout != int(busyTimeout.Milliseconds()) {
+		t.Errorf("busy_timeout = %d, want %d", timeout, busyTimeout.Milliseconds())
+	}
+	if _, err := db.Exec("CREATE TABLE feeds (id INTEGER PRIMARY KEY, title TEXT)"); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO feeds (title) VALUES ('first')"); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	// A second NewsGoat opened read-only reads while the first one writes
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("failed to begin: %v", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	if _, err := tx.Exec("INSERT INTO feeds (title) VALUES ('second')"); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	readOnly, err := driver.Open(dataSourceName(path, true), registerFunctions)
+	if err != nil {
+		t.Fatalf("failed to open database read-only: %v", err)
+	}
+	defer func() {
+		_ = readOnly.Close()
+	}()
+	var count int
+	if err := readOnly.QueryRow("SELECT count(*) FROM feeds").Scan(&count); err != nil {
+		t.Fatalf("read during a write error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("read-only connection sees %d feeds, want the 1 committed", count)
+	}
+	if _, err := readOnly.Exec("INSERT INTO feeds (title) VALUES ('third')"); err == nil {
+		t.Error("read-only connection could write")
+	}
+}
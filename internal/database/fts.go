@@ -0,0 +1,17 @@
+package database
+
+import "strings"
+
+// MatchQuery turns what was typed into a search box into an FTS5 query that
+// finds items containing every word, each word matching as a prefix so
+// "feed pars" finds "Feed parsing". Quoting the words keeps characters like
+// '-' or ':' from being read as FTS5 operators. An empty string means there is
+// nothing to search for.
+func MatchQuery(input string) string {
+	words := strings.Fields(input)
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, `"`+strings.ReplaceAll(word, `"`, `""`)+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/ncruces/go-sqlite3/driver"
+)
+
+func TestMatchQuery(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"   ", ""},
+		{"go", `"go"*`},
+		{"feed  pars", `"feed"* "pars"*`},
+		{`say "hi"`, `"say"* """hi"""*`},
+		{"NOT -x AND:y", `"NOT"* "-x"* "AND:y"*`},
+	}
+
+	for _, tt := range tests {
+		if got := MatchQuery(tt.input); got != tt.expected {
+			t.Errorf("MatchQuery(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestSearchItemsGlobally(t *testing.T) {
+	schema, err := os.ReadFile("../../sql/schema.sql")
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	db, err := driver.Open(":memory:", registerFunctions)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+	if err := createTables(db, string(schema)); err != nil {
+		t.Fatalf("failed to create tables: %v", err)
+	}
+
+	ctx := context.Background()
+	q := New(db)
+	feed, err := q.CreateFeed(ctx, CreateFeedParams{Url: "https://example.com/feed.xml", Title: "Example"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+
+	upsert := func(guid, title, content string) {
+		t.Helper()
+		if _, err := q.UpsertItem(ctx, UpsertItemParams{FeedID: feed.ID, Guid: guid, Title: title, Content: content}); err != nil {
+			t.Fatalf("UpsertItem(%s) error = %v", guid, err)
+		}
+	}
+	search := func(input string) []string {
+		t.Helper()
+		rows, err := q.SearchItemsGlobally(ctx, SearchItemsGloballyParams{FeedID: feed.ID, Match: MatchQuery(input)})
+		if err != nil {
+			t.Fatalf("SearchItemsGlobally(%q) error = %v", input, err)
+		}
+		guids := make([]string, 0, len(rows))
+		for _, row := range rows {
+			guids = append(guids, row.Guid)
+		}
+		return guids
+	}
+
+	upsert("body", "Weekly notes", "A long post that mentions parsing once")
+	upsert("title", "Parsing feeds in Go", "Notes")
+	upsert("accent", "Oktoberfest in München", "")
+
+	got := search("parsing")
+	if len(got) != 2 || got[0] != "title" || got[1] != "body" {
+		t.Errorf("search(parsing) = %v, want [title body], title matches rank first", got)
+	}
+	if got := search("munch"); len(got) != 1 || got[0] != "accent" {
+		t.Errorf("search(munch) = %v, want [accent]", got)
+	}
+	if got := search("go feeds"); len(got) != 1 || got[0] != "title" {
+		t.Errorf("search(go feeds) = %v, want [title]", got)
+	}
+
+	// Updating an item replaces its indexed text
+	upsert("title", "Rendering feeds", "Notes")
+	if got := search("parsing"); len(got) != 1 || got[0] != "body" {
+		t.Errorf("search(parsing) after update = %v, want [body]", got)
+	}
+
+	// Deleting the feed removes its items from the index
+	if err := q.DeleteFeed(ctx, feed.ID); err != nil {
+		t.Fatalf("DeleteFeed() error = %v", err)
+	}
+	var indexed int
+	if err := db.QueryRow("SELECT COUNT(*) FROM items_fts WHERE items_fts MATCH 'notes'").Scan(&indexed); err != nil {
+		t.Fatalf("failed to count indexed items: %v", err)
+	}
+	if indexed != 0 {
+		t.Errorf("%d items still indexed after deleting their feed", indexed)
+	}
+}
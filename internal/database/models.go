@@ -6,21 +6,32 @@ package database
 
 import (
 	"database/sql"
+	"time"
 )
 
 type Feed struct {
-	ID                 int64          `json:"id"`
-	Url                string         `json:"url"`
-	Title              string         `json:"title"`
-	Description        string         `json:"description"`
-	LastUpdated        sql.NullTime   `json:"last_updated"`
-	LastError          sql.NullString `json:"last_error"`
-	LastErrorTime      sql.NullTime   `json:"last_error_time"`
-	Visible            bool           `json:"visible"`
-	CreatedAt          sql.NullTime   `json:"created_at"`
-	Etag               sql.NullString `json:"etag"`
-	LastModified       sql.NullString `json:"last_modified"`
-	CacheControlMaxAge sql.NullInt64  `json:"cache_control_max_age"`
+	ID                  int64          `json:"id"`
+	Url                 string         `json:"url"`
+	Title               string         `json:"title"`
+	Description         string         `json:"description"`
+	LastUpdated         sql.NullTime   `json:"last_updated"`
+	LastError           sql.NullString `json:"last_error"`
+	LastErrorTime       sql.NullTime   `json:"last_error_time"`
+	Visible             bool           `json:"visible"`
+	CreatedAt           sql.NullTime   `json:"created_at"`
+	Etag                sql.NullString `json:"etag"`
+	LastModified        sql.NullString `json:"last_modified"`
+	CacheControlMaxAge  sql.NullInt64  `json:"cache_control_max_age"`
+	FullText            bool           `json:"full_text"`
+	ReloadInterval      int64          `json:"reload_interval"`
+	Enrich              bool           `json:"enrich"`
+	Paused              bool           `json:"paused"`
+	BodyHash            string         `json:"body_hash"`
+	UnchangedFetches    int64          `json:"unchanged_fetches"`
+	ConsecutiveFailures int64          `json:"consecutive_failures"`
+	RetryAfter          sql.NullTime   `json:"retry_after"`
+	CustomTitle         string         `json:"custom_title"`
+	LastResult          string         `json:"last_result"`
 }
 
 type FeedFolder struct {
@@ -29,16 +40,52 @@ type FeedFolder struct {
 	FolderName string `json:"folder_name"`
 }
 
+type FeedIcon struct {
+	Host      string    `json:"host"`
+	Color     string    `json:"color"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
 type Item struct {
-	ID          int64        `json:"id"`
-	FeedID      int64        `json:"feed_id"`
-	Guid        string       `json:"guid"`
-	Title       string       `json:"title"`
-	Description string       `json:"description"`
-	Content     string       `json:"content"`
-	Link        string       `json:"link"`
-	Published   sql.NullTime `json:"published"`
-	CreatedAt   sql.NullTime `json:"created_at"`
+	ID            int64        `json:"id"`
+	FeedID        int64        `json:"feed_id"`
+	Guid          string       `json:"guid"`
+	Title         string       `json:"title"`
+	Description   string       `json:"description"`
+	Content       string       `json:"content"`
+	Link          string       `json:"link"`
+	Published     sql.NullTime `json:"published"`
+	CreatedAt     sql.NullTime `json:"created_at"`
+	FullContent   string       `json:"full_content"`
+	Starred       bool         `json:"starred"`
+	PrNumber      int64        `json:"pr_number"`
+	PrState       string       `json:"pr_state"`
+	CiState       string       `json:"ci_state"`
+	EnrichedAt    sql.NullTime `json:"enriched_at"`
+	SeenAt        sql.NullTime `json:"seen_at"`
+	Author        string       `json:"author"`
+	CanonicalLink string       `json:"canonical_link"`
+	ContentHash   string       `json:"content_hash"`
+}
+
+type ItemEvent struct {
+	ID        int64        `json:"id"`
+	ItemID    int64        `json:"item_id"`
+	FeedID    int64        `json:"feed_id"`
+	Event     string       `json:"event"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type ItemRule struct {
+	ID        int64         `json:"id"`
+	FeedID    sql.NullInt64 `json:"feed_id"`
+	Field     string        `json:"field"`
+	Pattern   string        `json:"pattern"`
+	Action    string        `json:"action"`
+	CreatedAt sql.NullTime  `json:"created_at"`
+	Color     string        `json:"color"`
+	Marker    string        `json:"marker"`
+	Notify    bool          `json:"notify"`
 }
 
 type LogMessage struct {
@@ -49,6 +96,14 @@ type LogMessage struct {
 	Attributes sql.NullString `json:"attributes"`
 }
 
+type ReadEvent struct {
+	ID          int64         `json:"id"`
+	ItemID      sql.NullInt64 `json:"item_id"`
+	FeedID      int64         `json:"feed_id"`
+	ReadAt      time.Time     `json:"read_at"`
+	PublishedAt sql.NullTime  `json:"published_at"`
+}
+
 type ReadStatus struct {
 	ID     int64        `json:"id"`
 	ItemID int64        `json:"item_id"`
@@ -61,3 +116,35 @@ type Setting struct {
 	Value     string       `json:"value"`
 	UpdatedAt sql.NullTime `json:"updated_at"`
 }
+
+type SyncEntry struct {
+	Service   string        `json:"service"`
+	RemoteID  string        `json:"remote_id"`
+	ItemID    sql.NullInt64 `json:"item_id"`
+	FeedUrl   string        `json:"feed_url"`
+	Url       string        `json:"url"`
+	Read      bool          `json:"read"`
+	Starred   bool          `json:"starred"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+type Task struct {
+	ID        string       `json:"id"`
+	Type      string       `json:"type"`
+	Status    string       `json:"status"`
+	Priority  int64        `json:"priority"`
+	Data      string       `json:"data"`
+	Attempts  int64        `json:"attempts"`
+	NextRetry sql.NullTime `json:"next_retry"`
+	Error     string       `json:"error"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type WebsubSubscription struct {
+	FeedID       int64        `json:"feed_id"`
+	Hub          string       `json:"hub"`
+	Topic        string       `json:"topic"`
+	RequestedAt  time.Time    `json:"requested_at"`
+	LeaseExpires sql.NullTime `json:"lease_expires"`
+	Secret       string       `json:"secret"`
+}
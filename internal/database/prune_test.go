@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ncruces/go-sqlite3/driver"
+)
+
+func TestPruneFeedItems(t *testing.T) {
+	schema, err := os.ReadFile("../../sql/schema.sql")
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	db, err := driver.Open(":memory:", registerFunctions)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+	if err := createTables(db, string(schema)); err != nil {
+		t.Fatalf("failed to create tables: %v", err)
+	}
+
+	ctx := context.Background()
+	q := New(db)
+	feed, err := q.CreateFeed(ctx, CreateFeedParams{Url: "https://example.com/feed.xml", Title: "Example"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	previousFetch := sql.NullTime{Time: now.Add(-time.Hour), Valid: true}
+	latestFetch := sql.NullTime{Time: now, Valid: true}
+	daysAgo := func(days int) sql.NullTime {
+		return sql.NullTime{Time: now.AddDate(0, 0, -days), Valid: true}
+	}
+
+	items := []struct {
+		guid    string
+		age     int
+		latest  bool
+		read    bool
+		starred bool
+	}{
+		{"current-old", 90, true, true, false},
+		{"gone-old-read", 60, false, true, false},
+		{"gone-old-unread", 60, false, false, false},
+		{"gone-old-starred", 60, false, true, true},
+		{"gone-new-read", 1, false, true, false},
+	}
+	ids := make(map[string]int64)
+	for _, item := range items {
+		seenAt := previousFetch
+		if item.latest {
+			seenAt = latestFetch
+		}
+		created, err := q.UpsertItem(ctx, UpsertItemParams{
+			FeedID:    feed.ID,
+			Guid:      item.guid,
+			Title:     item.guid,
+			Published: daysAgo(item.age),
+			SeenAt:    seenAt,
+		})
+		if err != nil {
+			t.Fatalf("UpsertItem(%s) error = %v", item.guid, err)
+		}
+		ids[item.guid] = created.ID
+		if item.read {
+			if err := q.MarkItemRead(ctx, created.ID); err != nil {
+				t.Fatalf("MarkItemRead() error = %v", err)
+			}
+		}
+		if item.starred {
+			if err := q.SetItemStarred(ctx, SetItemStarredParams{Starred: true, ID: created.ID}); err != nil {
+				t.Fatalf("SetItemStarred() error = %v", err)
+			}
+		}
+	}
+
+	exists := func(guid string) bool {
+		_, err := q.GetItem(ctx, ids[guid])
+		return err == nil
+	}
+
+	// Only read, unstarred items older than 30 days that left the feed go
+	pruned, err := q.PruneFeedItems(ctx, PruneFeedItemsParams{FeedID: feed.ID, KeepStarred: true, KeepUnread: true, MaxAgeDays: 30})
+	if err != nil {
+		t.Fatalf("PruneFeedItems() error = %v", err)
+	}
+	if pruned != 1 || exists("gone-old-read") {
+		t.Errorf("PruneFeedItems() pruned %d, want only gone-old-read", pruned)
+	}
+	for _, guid := range []string{"current-old", "gone-old-unread", "gone-old-starred", "gone-new-read"} {
+		if !exists(guid) {
+			t.Errorf("%s should have been kept", guid)
+		}
+	}
+
+	// Keeping nothing with a limit of one item drops everything that left the feed except the newest
+	pruned, err = q.PruneFeedItems(ctx, PruneFeedItemsParams{FeedID: feed.ID, MaxItems: 1})
+	if err != nil {
+		t.Fatalf("PruneFeedItems() error = %v", err)
+	}
+	if pruned != 2 || exists("gone-old-unread") || exists("gone-old-starred") {
+		t.Errorf("PruneFeedItems() pruned %d, want gone-old-unread and gone-old-starred", pruned)
+	}
+	if !exists("current-old") || !exists("gone-new-read") {
+		t.Error("the item in the latest fetch and the newest item should have been kept")
+	}
+}
@@ -8,6 +8,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 const addFeedFolder = `-- name: AddFeedFolder :exec
@@ -26,9 +27,21 @@ func (q *Queries) AddFeedFolder(ctx context.Context, arg AddFeedFolderParams) er
 	return err
 }
 
+const clearFeedCache = `-- name: ClearFeedCache :exec
+UPDATE feeds
+SET etag = NULL, last_modified = NULL, cache_control_max_age = NULL, body_hash = ''
+WHERE id = ?
+`
+
+// The next refresh fetches the whole feed again, without conditional headers
+func (q *Queries) ClearFeedCache(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, clearFeedCache, id)
+	return err
+}
+
 const clearFeedError = `-- name: ClearFeedError :exec
 UPDATE feeds
-SET last_error = NULL, last_error_time = NULL
+SET last_error = NULL, last_error_time = NULL, consecutive_failures = 0
 WHERE id = ?
 `
 
@@ -37,10 +50,78 @@ func (q *Queries) ClearFeedError(ctx context.Context, id int64) error {
 	return err
 }
 
+const countFeedItems = `-- name: CountFeedItems :one
+SELECT COUNT(*) FROM items WHERE feed_id = ?
+`
+
+func (q *Queries) CountFeedItems(ctx context.Context, feedID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFeedItems, feedID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countFilteredLogMessages = `-- name: CountFilteredLogMessages :one
+SELECT COUNT(*) FROM log_messages
+WHERE instr(?1, ',' || level || ',') = 0
+  AND (?2 = ''
+       OR instr(lower(message || ' ' || coalesce(json_extract(attributes, '$.error'), '')), lower(?2)) > 0)
+  AND (?3 = 0
+       OR json_extract(attributes, '$.feedID') = ?3
+       OR json_extract(attributes, '$.feed_id') = ?3
+       OR json_extract(attributes, '$.url') = ?4)
+`
+
+type CountFilteredLogMessagesParams struct {
+	HiddenLevels string `json:"hidden_levels"`
+	Search       string `json:"search"`
+	FeedID       int64  `json:"feed_id"`
+	FeedUrl      string `json:"feed_url"`
+}
+
+// Counts the log messages GetFilteredLogMessages pages through
+func (q *Queries) CountFilteredLogMessages(ctx context.Context, arg CountFilteredLogMessagesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFilteredLogMessages,
+		arg.HiddenLevels,
+		arg.Search,
+		arg.FeedID,
+		arg.FeedUrl,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countLogMessages = `-- name: CountLogMessages :one
+SELECT COUNT(*) FROM log_messages
+`
+
+func (q *Queries) CountLogMessages(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countLogMessages)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUnreadItems = `-- name: CountUnreadItems :one
+SELECT COUNT(*)
+FROM items i
+JOIN feeds f ON i.feed_id = f.id
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE f.visible = TRUE AND COALESCE(rs.read, FALSE) = FALSE
+`
+
+func (q *Queries) CountUnreadItems(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUnreadItems)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createFeed = `-- name: CreateFeed :one
 INSERT INTO feeds (url, title, description, last_updated, visible)
 VALUES (?, ?, ?, ?, ?)
-RETURNING id, url, title, description, last_updated, last_error, last_error_time, visible, created_at, etag, last_modified, cache_control_max_age
+RETURNING id, url, title, description, last_updated, last_error, last_error_time, visible, created_at, etag, last_modified, cache_control_max_age, full_text, reload_interval, enrich, paused, body_hash, unchanged_fetches, consecutive_failures, retry_after, custom_title, last_result
 `
 
 type CreateFeedParams struct {
@@ -73,6 +154,16 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.Etag,
 		&i.LastModified,
 		&i.CacheControlMaxAge,
+		&i.FullText,
+		&i.ReloadInterval,
+		&i.Enrich,
+		&i.Paused,
+		&i.BodyHash,
+		&i.UnchangedFetches,
+		&i.ConsecutiveFailures,
+		&i.RetryAfter,
+		&i.CustomTitle,
+		&i.LastResult,
 	)
 	return i, err
 }
@@ -80,7 +171,7 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 const createItem = `-- name: CreateItem :one
 INSERT INTO items (feed_id, guid, title, description, content, link, published)
 VALUES (?, ?, ?, ?, ?, ?, ?)
-RETURNING id, feed_id, guid, title, description, content, link, published, created_at
+RETURNING id, feed_id, guid, title, description, content, link, published, created_at, full_content, starred, pr_number, pr_state, ci_state, enriched_at, seen_at, author, canonical_link, content_hash
 `
 
 type CreateItemParams struct {
@@ -114,6 +205,57 @@ func (q *Queries) CreateItem(ctx context.Context, arg CreateItemParams) (Item, e
 		&i.Link,
 		&i.Published,
 		&i.CreatedAt,
+		&i.FullContent,
+		&i.Starred,
+		&i.PrNumber,
+		&i.PrState,
+		&i.CiState,
+		&i.EnrichedAt,
+		&i.SeenAt,
+		&i.Author,
+		&i.CanonicalLink,
+		&i.ContentHash,
+	)
+	return i, err
+}
+
+const createItemRule = `-- name: CreateItemRule :one
+INSERT INTO item_rules (feed_id, field, pattern, action, color, marker, notify)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, feed_id, field, pattern, action, created_at, color, marker, notify
+`
+
+type CreateItemRuleParams struct {
+	FeedID  sql.NullInt64 `json:"feed_id"`
+	Field   string        `json:"field"`
+	Pattern string        `json:"pattern"`
+	Action  string        `json:"action"`
+	Color   string        `json:"color"`
+	Marker  string        `json:"marker"`
+	Notify  bool          `json:"notify"`
+}
+
+func (q *Queries) CreateItemRule(ctx context.Context, arg CreateItemRuleParams) (ItemRule, error) {
+	row := q.db.QueryRowContext(ctx, createItemRule,
+		arg.FeedID,
+		arg.Field,
+		arg.Pattern,
+		arg.Action,
+		arg.Color,
+		arg.Marker,
+		arg.Notify,
+	)
+	var i ItemRule
+	err := row.Scan(
+		&i.ID,
+		&i.FeedID,
+		&i.Field,
+		&i.Pattern,
+		&i.Action,
+		&i.CreatedAt,
+		&i.Color,
+		&i.Marker,
+		&i.Notify,
 	)
 	return i, err
 }
@@ -140,6 +282,20 @@ func (q *Queries) CreateLogMessage(ctx context.Context, arg CreateLogMessagePara
 	return err
 }
 
+const createReadEvent = `-- name: CreateReadEvent :exec
+INSERT INTO read_events (item_id, feed_id, published_at)
+SELECT i.id, i.feed_id, COALESCE(i.published, i.created_at)
+FROM items i
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE i.id = ? AND COALESCE(rs.read, FALSE) = FALSE
+`
+
+// Records an item being read, unless it already is
+func (q *Queries) CreateReadEvent(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, createReadEvent, id)
+	return err
+}
+
 const deleteAllLogMessages = `-- name: DeleteAllLogMessages :exec
 DELETE FROM log_messages
 `
@@ -167,6 +323,15 @@ func (q *Queries) DeleteFeedFolders(ctx context.Context, feedID int64) error {
 	return err
 }
 
+const deleteItemRule = `-- name: DeleteItemRule :exec
+DELETE FROM item_rules WHERE id = ?
+`
+
+func (q *Queries) DeleteItemRule(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteItemRule, id)
+	return err
+}
+
 const deleteItemsByFeed = `-- name: DeleteItemsByFeed :exec
 DELETE FROM items WHERE feed_id = ?
 `
@@ -185,98 +350,76 @@ func (q *Queries) DeleteSetting(ctx context.Context, key string) error {
 	return err
 }
 
-const getAllSettings = `-- name: GetAllSettings :many
-SELECT key, value, updated_at FROM settings ORDER BY key
+const deleteTask = `-- name: DeleteTask :exec
+DELETE FROM tasks WHERE id = ?
 `
 
-func (q *Queries) GetAllSettings(ctx context.Context) ([]Setting, error) {
-	rows, err := q.db.QueryContext(ctx, getAllSettings)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Setting
-	for rows.Next() {
-		var i Setting
-		if err := rows.Scan(&i.Key, &i.Value, &i.UpdatedAt); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) DeleteTask(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteTask, id)
+	return err
 }
 
-const getFeed = `-- name: GetFeed :one
-SELECT id, url, title, description, last_updated, last_error, last_error_time, visible, created_at, etag, last_modified, cache_control_max_age FROM feeds WHERE id = ?
+const deleteWebSubSubscription = `-- name: DeleteWebSubSubscription :exec
+DELETE FROM websub_subscriptions WHERE feed_id = ?
 `
 
-func (q *Queries) GetFeed(ctx context.Context, id int64) (Feed, error) {
-	row := q.db.QueryRowContext(ctx, getFeed, id)
-	var i Feed
-	err := row.Scan(
-		&i.ID,
-		&i.Url,
-		&i.Title,
-		&i.Description,
-		&i.LastUpdated,
-		&i.LastError,
-		&i.LastErrorTime,
-		&i.Visible,
-		&i.CreatedAt,
-		&i.Etag,
-		&i.LastModified,
-		&i.CacheControlMaxAge,
-	)
-	return i, err
+func (q *Queries) DeleteWebSubSubscription(ctx context.Context, feedID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteWebSubSubscription, feedID)
+	return err
 }
 
-const getFeedByURL = `-- name: GetFeedByURL :one
-SELECT id, url, title, description, last_updated, last_error, last_error_time, visible, created_at, etag, last_modified, cache_control_max_age FROM feeds WHERE url = ?
+const findSyncItem = `-- name: FindSyncItem :one
+SELECT i.id, i.starred, COALESCE(rs.read, FALSE) as read
+FROM items i
+JOIN feeds f ON i.feed_id = f.id
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE i.link = ?
+ORDER BY f.url = ? DESC, i.id
+LIMIT 1
 `
 
-func (q *Queries) GetFeedByURL(ctx context.Context, url string) (Feed, error) {
-	row := q.db.QueryRowContext(ctx, getFeedByURL, url)
-	var i Feed
-	err := row.Scan(
-		&i.ID,
-		&i.Url,
-		&i.Title,
-		&i.Description,
-		&i.LastUpdated,
-		&i.LastError,
-		&i.LastErrorTime,
-		&i.Visible,
-		&i.CreatedAt,
-		&i.Etag,
-		&i.LastModified,
-		&i.CacheControlMaxAge,
-	)
+type FindSyncItemParams struct {
+	Link string `json:"link"`
+	Url  string `json:"url"`
+}
+
+type FindSyncItemRow struct {
+	ID      int64 `json:"id"`
+	Starred bool  `json:"starred"`
+	Read    bool  `json:"read"`
+}
+
+// Items of the entry's feed are preferred, the feed URL can differ when the
+// service followed a redirect
+func (q *Queries) FindSyncItem(ctx context.Context, arg FindSyncItemParams) (FindSyncItemRow, error) {
+	row := q.db.QueryRowContext(ctx, findSyncItem, arg.Link, arg.Url)
+	var i FindSyncItemRow
+	err := row.Scan(&i.ID, &i.Starred, &i.Read)
 	return i, err
 }
 
-const getFeedFolders = `-- name: GetFeedFolders :many
-SELECT folder_name FROM feed_folders WHERE feed_id = ? ORDER BY folder_name
+const getAllFeedFolders = `-- name: GetAllFeedFolders :many
+SELECT feed_id, folder_name FROM feed_folders ORDER BY feed_id, folder_name
 `
 
-func (q *Queries) GetFeedFolders(ctx context.Context, feedID int64) ([]string, error) {
-	rows, err := q.db.QueryContext(ctx, getFeedFolders, feedID)
+type GetAllFeedFoldersRow struct {
+	FeedID     int64  `json:"feed_id"`
+	FolderName string `json:"folder_name"`
+}
+
+func (q *Queries) GetAllFeedFolders(ctx context.Context) ([]GetAllFeedFoldersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllFeedFolders)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []string
+	var items []GetAllFeedFoldersRow
 	for rows.Next() {
-		var folder_name string
-		if err := rows.Scan(&folder_name); err != nil {
+		var i GetAllFeedFoldersRow
+		if err := rows.Scan(&i.FeedID, &i.FolderName); err != nil {
 			return nil, err
 		}
-		items = append(items, folder_name)
+		items = append(items, i)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -287,50 +430,45 @@ func (q *Queries) GetFeedFolders(ctx context.Context, feedID int64) ([]string, e
 	return items, nil
 }
 
-const getFeedStats = `-- name: GetFeedStats :many
+const getAllItemHeaders = `-- name: GetAllItemHeaders :many
 SELECT
-    f.id,
-    f.title,
-    f.url,
-    f.last_error,
-    f.last_error_time,
-    COUNT(i.id) as total_items,
-    COUNT(CASE WHEN i.id IS NOT NULL AND COALESCE(rs.read, FALSE) = FALSE THEN 1 END) as unread_items
-FROM feeds f
-LEFT JOIN items i ON f.id = i.feed_id
+    i.id, i.feed_id, i.title, i.link, i.published, i.created_at, i.starred,
+    COALESCE(rs.read, FALSE) as read
+FROM items i
+JOIN feeds f ON i.feed_id = f.id
 LEFT JOIN read_status rs ON i.id = rs.item_id
 WHERE f.visible = TRUE
-GROUP BY f.id, f.title, f.url, f.last_error, f.last_error_time
-ORDER BY f.title
 `
 
-type GetFeedStatsRow struct {
-	ID            int64          `json:"id"`
-	Title         string         `json:"title"`
-	Url           string         `json:"url"`
-	LastError     sql.NullString `json:"last_error"`
-	LastErrorTime sql.NullTime   `json:"last_error_time"`
-	TotalItems    int64          `json:"total_items"`
-	UnreadItems   int64          `json:"unread_items"`
+type GetAllItemHeadersRow struct {
+	ID        int64        `json:"id"`
+	FeedID    int64        `json:"feed_id"`
+	Title     string       `json:"title"`
+	Link      string       `json:"link"`
+	Published sql.NullTime `json:"published"`
+	CreatedAt sql.NullTime `json:"created_at"`
+	Starred   bool         `json:"starred"`
+	Read      bool         `json:"read"`
 }
 
-func (q *Queries) GetFeedStats(ctx context.Context) ([]GetFeedStatsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getFeedStats)
+func (q *Queries) GetAllItemHeaders(ctx context.Context) ([]GetAllItemHeadersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllItemHeaders)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetFeedStatsRow
+	var items []GetAllItemHeadersRow
 	for rows.Next() {
-		var i GetFeedStatsRow
+		var i GetAllItemHeadersRow
 		if err := rows.Scan(
 			&i.ID,
+			&i.FeedID,
 			&i.Title,
-			&i.Url,
-			&i.LastError,
-			&i.LastErrorTime,
-			&i.TotalItems,
-			&i.UnreadItems,
+			&i.Link,
+			&i.Published,
+			&i.CreatedAt,
+			&i.Starred,
+			&i.Read,
 		); err != nil {
 			return nil, err
 		}
@@ -345,36 +483,71 @@ func (q *Queries) GetFeedStats(ctx context.Context) ([]GetFeedStatsRow, error) {
 	return items, nil
 }
 
-const getFolderStats = `-- name: GetFolderStats :many
+const getAllItemsWithReadStatus = `-- name: GetAllItemsWithReadStatus :many
 SELECT
-    ff.folder_name,
-    COUNT(DISTINCT i.id) as total_items,
-    COUNT(DISTINCT CASE WHEN i.id IS NOT NULL AND COALESCE(rs.read, FALSE) = FALSE THEN i.id END) as unread_items
-FROM feed_folders ff
-INNER JOIN feeds f ON ff.feed_id = f.id
-LEFT JOIN items i ON f.id = i.feed_id
+    i.id, i.feed_id, i.guid, i.title, i.description, i.content, i.link, i.published, i.created_at, i.full_content, i.starred, i.pr_number, i.pr_state, i.ci_state, i.enriched_at, i.seen_at, i.author, i.canonical_link, i.content_hash,
+    COALESCE(rs.read, FALSE) as read
+FROM items i
+JOIN feeds f ON i.feed_id = f.id
 LEFT JOIN read_status rs ON i.id = rs.item_id
 WHERE f.visible = TRUE
-GROUP BY ff.folder_name
-ORDER BY ff.folder_name
+ORDER BY i.published DESC
 `
 
-type GetFolderStatsRow struct {
-	FolderName  string `json:"folder_name"`
-	TotalItems  int64  `json:"total_items"`
-	UnreadItems int64  `json:"unread_items"`
+type GetAllItemsWithReadStatusRow struct {
+	ID            int64        `json:"id"`
+	FeedID        int64        `json:"feed_id"`
+	Guid          string       `json:"guid"`
+	Title         string       `json:"title"`
+	Description   string       `json:"description"`
+	Content       string       `json:"content"`
+	Link          string       `json:"link"`
+	Published     sql.NullTime `json:"published"`
+	CreatedAt     sql.NullTime `json:"created_at"`
+	FullContent   string       `json:"full_content"`
+	Starred       bool         `json:"starred"`
+	PrNumber      int64        `json:"pr_number"`
+	PrState       string       `json:"pr_state"`
+	CiState       string       `json:"ci_state"`
+	EnrichedAt    sql.NullTime `json:"enriched_at"`
+	SeenAt        sql.NullTime `json:"seen_at"`
+	Author        string       `json:"author"`
+	CanonicalLink string       `json:"canonical_link"`
+	ContentHash   string       `json:"content_hash"`
+	Read          bool         `json:"read"`
 }
 
-func (q *Queries) GetFolderStats(ctx context.Context) ([]GetFolderStatsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getFolderStats)
+func (q *Queries) GetAllItemsWithReadStatus(ctx context.Context) ([]GetAllItemsWithReadStatusRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllItemsWithReadStatus)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetFolderStatsRow
+	var items []GetAllItemsWithReadStatusRow
 	for rows.Next() {
-		var i GetFolderStatsRow
-		if err := rows.Scan(&i.FolderName, &i.TotalItems, &i.UnreadItems); err != nil {
+		var i GetAllItemsWithReadStatusRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FeedID,
+			&i.Guid,
+			&i.Title,
+			&i.Description,
+			&i.Content,
+			&i.Link,
+			&i.Published,
+			&i.CreatedAt,
+			&i.FullContent,
+			&i.Starred,
+			&i.PrNumber,
+			&i.PrState,
+			&i.CiState,
+			&i.EnrichedAt,
+			&i.SeenAt,
+			&i.Author,
+			&i.CanonicalLink,
+			&i.ContentHash,
+			&i.Read,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -388,59 +561,56 @@ func (q *Queries) GetFolderStats(ctx context.Context) ([]GetFolderStatsRow, erro
 	return items, nil
 }
 
-const getItem = `-- name: GetItem :one
-SELECT id, feed_id, guid, title, description, content, link, published, created_at FROM items WHERE id = ?
-`
-
-func (q *Queries) GetItem(ctx context.Context, id int64) (Item, error) {
-	row := q.db.QueryRowContext(ctx, getItem, id)
-	var i Item
-	err := row.Scan(
-		&i.ID,
-		&i.FeedID,
-		&i.Guid,
-		&i.Title,
-		&i.Description,
-		&i.Content,
-		&i.Link,
-		&i.Published,
-		&i.CreatedAt,
-	)
-	return i, err
-}
-
-const getItemsWithReadStatus = `-- name: GetItemsWithReadStatus :many
+const getAllItemsWithReadStatusPage = `-- name: GetAllItemsWithReadStatusPage :many
 SELECT
-    i.id, i.feed_id, i.guid, i.title, i.description, i.content, i.link, i.published, i.created_at,
+    i.id, i.feed_id, i.guid, i.title, i.description, i.content, i.link, i.published, i.created_at, i.full_content, i.starred, i.pr_number, i.pr_state, i.ci_state, i.enriched_at, i.seen_at, i.author, i.canonical_link, i.content_hash,
     COALESCE(rs.read, FALSE) as read
 FROM items i
+JOIN feeds f ON i.feed_id = f.id
 LEFT JOIN read_status rs ON i.id = rs.item_id
-WHERE i.feed_id = ?
-ORDER BY i.published DESC
+WHERE f.visible = TRUE
+ORDER BY CASE WHEN ? THEN COALESCE(rs.read, FALSE) ELSE FALSE END, i.published DESC, i.id DESC
+LIMIT ? OFFSET ?
 `
 
-type GetItemsWithReadStatusRow struct {
-	ID          int64        `json:"id"`
-	FeedID      int64        `json:"feed_id"`
-	Guid        string       `json:"guid"`
-	Title       string       `json:"title"`
-	Description string       `json:"description"`
-	Content     string       `json:"content"`
-	Link        string       `json:"link"`
-	Published   sql.NullTime `json:"published"`
-	CreatedAt   sql.NullTime `json:"created_at"`
-	Read        bool         `json:"read"`
+type GetAllItemsWithReadStatusPageParams struct {
+	UnreadFirst bool  `json:"unread_first"`
+	Limit       int64 `json:"limit"`
+	Offset      int64 `json:"offset"`
 }
 
-func (q *Queries) GetItemsWithReadStatus(ctx context.Context, feedID int64) ([]GetItemsWithReadStatusRow, error) {
-	rows, err := q.db.QueryContext(ctx, getItemsWithReadStatus, feedID)
+type GetAllItemsWithReadStatusPageRow struct {
+	ID            int64        `json:"id"`
+	FeedID        int64        `json:"feed_id"`
+	Guid          string       `json:"guid"`
+	Title         string       `json:"title"`
+	Description   string       `json:"description"`
+	Content       string       `json:"content"`
+	Link          string       `json:"link"`
+	Published     sql.NullTime `json:"published"`
+	CreatedAt     sql.NullTime `json:"created_at"`
+	FullContent   string       `json:"full_content"`
+	Starred       bool         `json:"starred"`
+	PrNumber      int64        `json:"pr_number"`
+	PrState       string       `json:"pr_state"`
+	CiState       string       `json:"ci_state"`
+	EnrichedAt    sql.NullTime `json:"enriched_at"`
+	SeenAt        sql.NullTime `json:"seen_at"`
+	Author        string       `json:"author"`
+	CanonicalLink string       `json:"canonical_link"`
+	ContentHash   string       `json:"content_hash"`
+	Read          bool         `json:"read"`
+}
+
+func (q *Queries) GetAllItemsWithReadStatusPage(ctx context.Context, arg GetAllItemsWithReadStatusPageParams) ([]GetAllItemsWithReadStatusPageRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllItemsWithReadStatusPage, arg.UnreadFirst, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetItemsWithReadStatusRow
+	var items []GetAllItemsWithReadStatusPageRow
 	for rows.Next() {
-		var i GetItemsWithReadStatusRow
+		var i GetAllItemsWithReadStatusPageRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.FeedID,
@@ -451,6 +621,16 @@ func (q *Queries) GetItemsWithReadStatus(ctx context.Context, feedID int64) ([]G
 			&i.Link,
 			&i.Published,
 			&i.CreatedAt,
+			&i.FullContent,
+			&i.Starred,
+			&i.PrNumber,
+			&i.PrState,
+			&i.CiState,
+			&i.EnrichedAt,
+			&i.SeenAt,
+			&i.Author,
+			&i.CanonicalLink,
+			&i.ContentHash,
 			&i.Read,
 		); err != nil {
 			return nil, err
@@ -466,47 +646,1174 @@ func (q *Queries) GetItemsWithReadStatus(ctx context.Context, feedID int64) ([]G
 	return items, nil
 }
 
-const getLogMessage = `-- name: GetLogMessage :one
+const getAllLogMessages = `-- name: GetAllLogMessages :many
 SELECT id, level, message, timestamp, attributes
 FROM log_messages
-WHERE id = ?
+ORDER BY timestamp ASC, id ASC
 `
 
-func (q *Queries) GetLogMessage(ctx context.Context, id int64) (LogMessage, error) {
-	row := q.db.QueryRowContext(ctx, getLogMessage, id)
-	var i LogMessage
-	err := row.Scan(
-		&i.ID,
-		&i.Level,
-		&i.Message,
-		&i.Timestamp,
-		&i.Attributes,
-	)
-	return i, err
+func (q *Queries) GetAllLogMessages(ctx context.Context) ([]LogMessage, error) {
+	rows, err := q.db.QueryContext(ctx, getAllLogMessages)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LogMessage
+	for rows.Next() {
+		var i LogMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.Level,
+			&i.Message,
+			&i.Timestamp,
+			&i.Attributes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAllSettings = `-- name: GetAllSettings :many
+SELECT key, value, updated_at FROM settings ORDER BY key
+`
+
+func (q *Queries) GetAllSettings(ctx context.Context) ([]Setting, error) {
+	rows, err := q.db.QueryContext(ctx, getAllSettings)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Setting
+	for rows.Next() {
+		var i Setting
+		if err := rows.Scan(&i.Key, &i.Value, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBacklogChanges = `-- name: GetBacklogChanges :many
+SELECT i.created_at, rs.read_at
+FROM items i
+JOIN feeds f ON i.feed_id = f.id
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE f.visible = TRUE
+  AND (datetime(i.created_at) >= datetime('now', printf('-%d days', ?1))
+       OR (rs.read AND datetime(rs.read_at) >= datetime('now', printf('-%d days', ?1))))
+`
+
+type GetBacklogChangesRow struct {
+	CreatedAt sql.NullTime `json:"created_at"`
+	ReadAt    sql.NullTime `json:"read_at"`
+}
+
+// The items first seen or read in the last days, to work the unread backlog
+// of earlier days out from the current one
+func (q *Queries) GetBacklogChanges(ctx context.Context, days int64) ([]GetBacklogChangesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBacklogChanges, days)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBacklogChangesRow
+	for rows.Next() {
+		var i GetBacklogChangesRow
+		if err := rows.Scan(&i.CreatedAt, &i.ReadAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeed = `-- name: GetFeed :one
+SELECT id, url, title, description, last_updated, last_error, last_error_time, visible, created_at, etag, last_modified, cache_control_max_age, full_text, reload_interval, enrich, paused, body_hash, unchanged_fetches, consecutive_failures, retry_after, custom_title, last_result FROM feeds WHERE id = ?
+`
+
+func (q *Queries) GetFeed(ctx context.Context, id int64) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeed, id)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Title,
+		&i.Description,
+		&i.LastUpdated,
+		&i.LastError,
+		&i.LastErrorTime,
+		&i.Visible,
+		&i.CreatedAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.CacheControlMaxAge,
+		&i.FullText,
+		&i.ReloadInterval,
+		&i.Enrich,
+		&i.Paused,
+		&i.BodyHash,
+		&i.UnchangedFetches,
+		&i.ConsecutiveFailures,
+		&i.RetryAfter,
+		&i.CustomTitle,
+		&i.LastResult,
+	)
+	return i, err
+}
+
+const getFeedActivity = `-- name: GetFeedActivity :many
+SELECT
+    f.id,
+    f.title,
+    (SELECT COUNT(*) FROM items i WHERE i.feed_id = f.id AND datetime(i.created_at) >= datetime('now', printf('-%d days', ?1))) AS new_items,
+    (SELECT COUNT(*) FROM read_events re WHERE re.feed_id = f.id AND datetime(re.read_at) >= datetime('now', printf('-%d days', ?1))) AS reads
+FROM feeds f
+WHERE f.visible = TRUE
+ORDER BY new_items DESC, reads DESC, f.title
+LIMIT ?2
+`
+
+type GetFeedActivityParams struct {
+	Days  int64 `json:"days"`
+	Limit int64 `json:"limit"`
+}
+
+type GetFeedActivityRow struct {
+	ID       int64  `json:"id"`
+	Title    string `json:"title"`
+	NewItems int64  `json:"new_items"`
+	Reads    int64  `json:"reads"`
+}
+
+// new_items counts the items first seen and reads the items read in the last
+// days, the busiest feeds first
+func (q *Queries) GetFeedActivity(ctx context.Context, arg GetFeedActivityParams) ([]GetFeedActivityRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedActivity, arg.Days, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeedActivityRow
+	for rows.Next() {
+		var i GetFeedActivityRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.NewItems,
+			&i.Reads,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeedByURL = `-- name: GetFeedByURL :one
+SELECT id, url, title, description, last_updated, last_error, last_error_time, visible, created_at, etag, last_modified, cache_control_max_age, full_text, reload_interval, enrich, paused, body_hash, unchanged_fetches, consecutive_failures, retry_after, custom_title, last_result FROM feeds WHERE url = ?
+`
+
+func (q *Queries) GetFeedByURL(ctx context.Context, url string) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeedByURL, url)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Title,
+		&i.Description,
+		&i.LastUpdated,
+		&i.LastError,
+		&i.LastErrorTime,
+		&i.Visible,
+		&i.CreatedAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.CacheControlMaxAge,
+		&i.FullText,
+		&i.ReloadInterval,
+		&i.Enrich,
+		&i.Paused,
+		&i.BodyHash,
+		&i.UnchangedFetches,
+		&i.ConsecutiveFailures,
+		&i.RetryAfter,
+		&i.CustomTitle,
+		&i.LastResult,
+	)
+	return i, err
+}
+
+const getFeedEngagement = `-- name: GetFeedEngagement :many
+SELECT
+    feed_id,
+    COUNT(CASE WHEN event = 'open' THEN 1 END) as opens,
+    COUNT(CASE WHEN event = 'skip' THEN 1 END) as skips
+FROM item_events
+GROUP BY feed_id
+`
+
+type GetFeedEngagementRow struct {
+	FeedID int64 `json:"feed_id"`
+	Opens  int64 `json:"opens"`
+	Skips  int64 `json:"skips"`
+}
+
+func (q *Queries) GetFeedEngagement(ctx context.Context) ([]GetFeedEngagementRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedEngagement)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeedEngagementRow
+	for rows.Next() {
+		var i GetFeedEngagementRow
+		if err := rows.Scan(&i.FeedID, &i.Opens, &i.Skips); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeedFolders = `-- name: GetFeedFolders :many
+SELECT folder_name FROM feed_folders WHERE feed_id = ? ORDER BY folder_name
+`
+
+func (q *Queries) GetFeedFolders(ctx context.Context, feedID int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedFolders, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var folder_name string
+		if err := rows.Scan(&folder_name); err != nil {
+			return nil, err
+		}
+		items = append(items, folder_name)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeedIcon = `-- name: GetFeedIcon :one
+SELECT host, color, fetched_at FROM feed_icons WHERE host = ?
+`
+
+func (q *Queries) GetFeedIcon(ctx context.Context, host string) (FeedIcon, error) {
+	row := q.db.QueryRowContext(ctx, getFeedIcon, host)
+	var i FeedIcon
+	err := row.Scan(&i.Host, &i.Color, &i.FetchedAt)
+	return i, err
+}
+
+const getFeedIcons = `-- name: GetFeedIcons :many
+SELECT host, color, fetched_at FROM feed_icons WHERE color != ''
+`
+
+func (q *Queries) GetFeedIcons(ctx context.Context) ([]FeedIcon, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedIcons)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FeedIcon
+	for rows.Next() {
+		var i FeedIcon
+		if err := rows.Scan(&i.Host, &i.Color, &i.FetchedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeedRefreshStats = `-- name: GetFeedRefreshStats :many
+SELECT
+    f.id,
+    f.last_updated,
+    f.cache_control_max_age,
+    f.consecutive_failures,
+    (SELECT COUNT(*) FROM items i WHERE i.feed_id = f.id AND i.created_at >= datetime('now', '-30 days')) AS recent_items
+FROM feeds f
+`
+
+type GetFeedRefreshStatsRow struct {
+	ID                  int64         `json:"id"`
+	LastUpdated         sql.NullTime  `json:"last_updated"`
+	CacheControlMaxAge  sql.NullInt64 `json:"cache_control_max_age"`
+	ConsecutiveFailures int64         `json:"consecutive_failures"`
+	RecentItems         int64         `json:"recent_items"`
+}
+
+// recent_items counts the items first seen in the last 30 days, how often
+// the feed changed lately
+func (q *Queries) GetFeedRefreshStats(ctx context.Context) ([]GetFeedRefreshStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedRefreshStats)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeedRefreshStatsRow
+	for rows.Next() {
+		var i GetFeedRefreshStatsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.LastUpdated,
+			&i.CacheControlMaxAge,
+			&i.ConsecutiveFailures,
+			&i.RecentItems,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeedReloadIntervals = `-- name: GetFeedReloadIntervals :many
+SELECT id, reload_interval FROM feeds WHERE visible = TRUE AND reload_interval > 0
+`
+
+type GetFeedReloadIntervalsRow struct {
+	ID             int64 `json:"id"`
+	ReloadInterval int64 `json:"reload_interval"`
+}
+
+func (q *Queries) GetFeedReloadIntervals(ctx context.Context) ([]GetFeedReloadIntervalsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedReloadIntervals)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeedReloadIntervalsRow
+	for rows.Next() {
+		var i GetFeedReloadIntervalsRow
+		if err := rows.Scan(&i.ID, &i.ReloadInterval); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeedStats = `-- name: GetFeedStats :many
+SELECT
+    f.id,
+    f.title,
+    f.url,
+    f.last_error,
+    f.last_error_time,
+    f.paused,
+    f.consecutive_failures,
+    COUNT(i.id) as total_items,
+    COUNT(CASE WHEN i.id IS NOT NULL AND COALESCE(rs.read, FALSE) = FALSE THEN 1 END) as unread_items
+FROM feeds f
+LEFT JOIN items i ON f.id = i.feed_id
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE f.visible = TRUE
+GROUP BY f.id, f.title, f.url, f.last_error, f.last_error_time, f.paused, f.consecutive_failures
+ORDER BY fold(f.title)
+`
+
+type GetFeedStatsRow struct {
+	ID                  int64          `json:"id"`
+	Title               string         `json:"title"`
+	Url                 string         `json:"url"`
+	LastError           sql.NullString `json:"last_error"`
+	LastErrorTime       sql.NullTime   `json:"last_error_time"`
+	Paused              bool           `json:"paused"`
+	ConsecutiveFailures int64          `json:"consecutive_failures"`
+	TotalItems          int64          `json:"total_items"`
+	UnreadItems         int64          `json:"unread_items"`
+}
+
+func (q *Queries) GetFeedStats(ctx context.Context) ([]GetFeedStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedStats)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeedStatsRow
+	for rows.Next() {
+		var i GetFeedStatsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Url,
+			&i.LastError,
+			&i.LastErrorTime,
+			&i.Paused,
+			&i.ConsecutiveFailures,
+			&i.TotalItems,
+			&i.UnreadItems,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeedStatsByID = `-- name: GetFeedStatsByID :one
+SELECT
+    f.id,
+    f.title,
+    f.url,
+    f.last_error,
+    f.last_error_time,
+    f.paused,
+    f.consecutive_failures,
+    COUNT(i.id) as total_items,
+    COUNT(CASE WHEN i.id IS NOT NULL AND COALESCE(rs.read, FALSE) = FALSE THEN 1 END) as unread_items
+FROM feeds f
+LEFT JOIN items i ON f.id = i.feed_id
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE f.id = ? AND f.visible = TRUE
+GROUP BY f.id, f.title, f.url, f.last_error, f.last_error_time, f.paused, f.consecutive_failures
+`
+
+type GetFeedStatsByIDRow struct {
+	ID                  int64          `json:"id"`
+	Title               string         `json:"title"`
+	Url                 string         `json:"url"`
+	LastError           sql.NullString `json:"last_error"`
+	LastErrorTime       sql.NullTime   `json:"last_error_time"`
+	Paused              bool           `json:"paused"`
+	ConsecutiveFailures int64          `json:"consecutive_failures"`
+	TotalItems          int64          `json:"total_items"`
+	UnreadItems         int64          `json:"unread_items"`
+}
+
+func (q *Queries) GetFeedStatsByID(ctx context.Context, id int64) (GetFeedStatsByIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getFeedStatsByID, id)
+	var i GetFeedStatsByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Url,
+		&i.LastError,
+		&i.LastErrorTime,
+		&i.Paused,
+		&i.ConsecutiveFailures,
+		&i.TotalItems,
+		&i.UnreadItems,
+	)
+	return i, err
+}
+
+const getFilteredLogMessages = `-- name: GetFilteredLogMessages :many
+SELECT id, level, message, timestamp, attributes
+FROM log_messages
+WHERE instr(?1, ',' || level || ',') = 0
+  AND (?2 = ''
+       OR instr(lower(message || ' ' || coalesce(json_extract(attributes, '$.error'), '')), lower(?2)) > 0)
+  AND (?3 = 0
+       OR json_extract(attributes, '$.feedID') = ?3
+       OR json_extract(attributes, '$.feed_id') = ?3
+       OR json_extract(attributes, '$.url') = ?4)
+ORDER BY timestamp DESC, id DESC
+LIMIT ?5 OFFSET ?6
+`
+
+type GetFilteredLogMessagesParams struct {
+	HiddenLevels string `json:"hidden_levels"`
+	Search       string `json:"search"`
+	FeedID       int64  `json:"feed_id"`
+	FeedUrl      string `json:"feed_url"`
+	Limit        int64  `json:"limit"`
+	Offset       int64  `json:"offset"`
+}
+
+// Log messages of the levels not in hidden_levels (",ERROR,DEBUG,"), whose
+// message or error contains search and that are about the feed, when feed_id
+// isn't 0, newest first
+func (q *Queries) GetFilteredLogMessages(ctx context.Context, arg GetFilteredLogMessagesParams) ([]LogMessage, error) {
+	rows, err := q.db.QueryContext(ctx, getFilteredLogMessages,
+		arg.HiddenLevels,
+		arg.Search,
+		arg.FeedID,
+		arg.FeedUrl,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LogMessage
+	for rows.Next() {
+		var i LogMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.Level,
+			&i.Message,
+			&i.Timestamp,
+			&i.Attributes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFolderStats = `-- name: GetFolderStats :many
+SELECT
+    ff.folder_name,
+    COUNT(DISTINCT i.id) as total_items,
+    COUNT(DISTINCT CASE WHEN i.id IS NOT NULL AND COALESCE(rs.read, FALSE) = FALSE THEN i.id END) as unread_items
+FROM feed_folders ff
+INNER JOIN feeds f ON ff.feed_id = f.id
+LEFT JOIN items i ON f.id = i.feed_id
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE f.visible = TRUE
+GROUP BY ff.folder_name
+ORDER BY ff.folder_name
+`
+
+type GetFolderStatsRow struct {
+	FolderName  string `json:"folder_name"`
+	TotalItems  int64  `json:"total_items"`
+	UnreadItems int64  `json:"unread_items"`
+}
+
+func (q *Queries) GetFolderStats(ctx context.Context) ([]GetFolderStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFolderStats)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFolderStatsRow
+	for rows.Next() {
+		var i GetFolderStatsRow
+		if err := rows.Scan(&i.FolderName, &i.TotalItems, &i.UnreadItems); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getItem = `-- name: GetItem :one
+SELECT id, feed_id, guid, title, description, content, link, published, created_at, full_content, starred, pr_number, pr_state, ci_state, enriched_at, seen_at, author, canonical_link, content_hash FROM items WHERE id = ?
+`
+
+func (q *Queries) GetItem(ctx context.Context, id int64) (Item, error) {
+	row := q.db.QueryRowContext(ctx, getItem, id)
+	var i Item
+	err := row.Scan(
+		&i.ID,
+		&i.FeedID,
+		&i.Guid,
+		&i.Title,
+		&i.Description,
+		&i.Content,
+		&i.Link,
+		&i.Published,
+		&i.CreatedAt,
+		&i.FullContent,
+		&i.Starred,
+		&i.PrNumber,
+		&i.PrState,
+		&i.CiState,
+		&i.EnrichedAt,
+		&i.SeenAt,
+		&i.Author,
+		&i.CanonicalLink,
+		&i.ContentHash,
+	)
+	return i, err
+}
+
+const getItemCopies = `-- name: GetItemCopies :many
+SELECT i.id, i.feed_id, f.title as feed_title
+FROM items i
+JOIN feeds f ON i.feed_id = f.id
+WHERE i.feed_id != ?1
+  AND ((?2 != '' AND i.canonical_link = ?2)
+    OR (?3 != '' AND i.content_hash = ?3))
+ORDER BY f.title, i.id
+`
+
+type GetItemCopiesParams struct {
+	FeedID        int64  `json:"feed_id"`
+	CanonicalLink string `json:"canonical_link"`
+	ContentHash   string `json:"content_hash"`
+}
+
+type GetItemCopiesRow struct {
+	ID        int64  `json:"id"`
+	FeedID    int64  `json:"feed_id"`
+	FeedTitle string `json:"feed_title"`
+}
+
+// Items of other feeds that are the same article, with the same canonical
+// link or content hash
+func (q *Queries) GetItemCopies(ctx context.Context, arg GetItemCopiesParams) ([]GetItemCopiesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getItemCopies, arg.FeedID, arg.CanonicalLink, arg.ContentHash)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetItemCopiesRow
+	for rows.Next() {
+		var i GetItemCopiesRow
+		if err := rows.Scan(&i.ID, &i.FeedID, &i.FeedTitle); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getItemGUIDs = `-- name: GetItemGUIDs :many
+SELECT guid FROM items WHERE feed_id = ?
+`
+
+func (q *Queries) GetItemGUIDs(ctx context.Context, feedID int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getItemGUIDs, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var guid string
+		if err := rows.Scan(&guid); err != nil {
+			return nil, err
+		}
+		items = append(items, guid)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getItemsToEnrich = `-- name: GetItemsToEnrich :many
+SELECT id, feed_id, guid, title, description, content, link, published, created_at, full_content, starred, pr_number, pr_state, ci_state, enriched_at, seen_at, author, canonical_link, content_hash FROM items
+WHERE feed_id = ?
+  AND (enriched_at IS NULL
+       OR (enriched_at < ? AND (pr_state IN ('open', 'draft') OR ci_state = 'pending')))
+ORDER BY published DESC
+LIMIT ?
+`
+
+type GetItemsToEnrichParams struct {
+	FeedID     int64        `json:"feed_id"`
+	EnrichedAt sql.NullTime `json:"enriched_at"`
+	Limit      int64        `json:"limit"`
+}
+
+func (q *Queries) GetItemsToEnrich(ctx context.Context, arg GetItemsToEnrichParams) ([]Item, error) {
+	rows, err := q.db.QueryContext(ctx, getItemsToEnrich, arg.FeedID, arg.EnrichedAt, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Item
+	for rows.Next() {
+		var i Item
+		if err := rows.Scan(
+			&i.ID,
+			&i.FeedID,
+			&i.Guid,
+			&i.Title,
+			&i.Description,
+			&i.Content,
+			&i.Link,
+			&i.Published,
+			&i.CreatedAt,
+			&i.FullContent,
+			&i.Starred,
+			&i.PrNumber,
+			&i.PrState,
+			&i.CiState,
+			&i.EnrichedAt,
+			&i.SeenAt,
+			&i.Author,
+			&i.CanonicalLink,
+			&i.ContentHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getItemsWithReadStatus = `-- name: GetItemsWithReadStatus :many
+SELECT
+    i.id, i.feed_id, i.guid, i.title, i.description, i.content, i.link, i.published, i.created_at, i.full_content, i.starred, i.pr_number, i.pr_state, i.ci_state, i.enriched_at, i.seen_at, i.author, i.canonical_link, i.content_hash,
+    COALESCE(rs.read, FALSE) as read
+FROM items i
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE i.feed_id = ?
+ORDER BY i.published DESC
+`
+
+type GetItemsWithReadStatusRow struct {
+	ID            int64        `json:"id"`
+	FeedID        int64        `json:"feed_id"`
+	Guid          string       `json:"guid"`
+	Title         string       `json:"title"`
+	Description   string       `json:"description"`
+	Content       string       `json:"content"`
+	Link          string       `json:"link"`
+	Published     sql.NullTime `json:"published"`
+	CreatedAt     sql.NullTime `json:"created_at"`
+	FullContent   string       `json:"full_content"`
+	Starred       bool         `json:"starred"`
+	PrNumber      int64        `json:"pr_number"`
+	PrState       string       `json:"pr_state"`
+	CiState       string       `json:"ci_state"`
+	EnrichedAt    sql.NullTime `json:"enriched_at"`
+	SeenAt        sql.NullTime `json:"seen_at"`
+	Author        string       `json:"author"`
+	CanonicalLink string       `json:"canonical_link"`
+	ContentHash   string       `json:"content_hash"`
+	Read          bool         `json:"read"`
+}
+
+func (q *Queries) GetItemsWithReadStatus(ctx context.Context, feedID int64) ([]GetItemsWithReadStatusRow, error) {
+	rows, err := q.db.QueryContext(ctx, getItemsWithReadStatus, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetItemsWithReadStatusRow
+	for rows.Next() {
+		var i GetItemsWithReadStatusRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FeedID,
+			&i.Guid,
+			&i.Title,
+			&i.Description,
+			&i.Content,
+			&i.Link,
+			&i.Published,
+			&i.CreatedAt,
+			&i.FullContent,
+			&i.Starred,
+			&i.PrNumber,
+			&i.PrState,
+			&i.CiState,
+			&i.EnrichedAt,
+			&i.SeenAt,
+			&i.Author,
+			&i.CanonicalLink,
+			&i.ContentHash,
+			&i.Read,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getItemsWithReadStatusPage = `-- name: GetItemsWithReadStatusPage :many
+SELECT
+    i.id, i.feed_id, i.guid, i.title, i.description, i.content, i.link, i.published, i.created_at, i.full_content, i.starred, i.pr_number, i.pr_state, i.ci_state, i.enriched_at, i.seen_at, i.author, i.canonical_link, i.content_hash,
+    COALESCE(rs.read, FALSE) as read
+FROM items i
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE i.feed_id = ?
+ORDER BY CASE WHEN ? THEN COALESCE(rs.read, FALSE) ELSE FALSE END, i.published DESC, i.id DESC
+LIMIT ? OFFSET ?
+`
+
+type GetItemsWithReadStatusPageParams struct {
+	FeedID      int64 `json:"feed_id"`
+	UnreadFirst bool  `json:"unread_first"`
+	Limit       int64 `json:"limit"`
+	Offset      int64 `json:"offset"`
+}
+
+type GetItemsWithReadStatusPageRow struct {
+	ID            int64        `json:"id"`
+	FeedID        int64        `json:"feed_id"`
+	Guid          string       `json:"guid"`
+	Title         string       `json:"title"`
+	Description   string       `json:"description"`
+	Content       string       `json:"content"`
+	Link          string       `json:"link"`
+	Published     sql.NullTime `json:"published"`
+	CreatedAt     sql.NullTime `json:"created_at"`
+	FullContent   string       `json:"full_content"`
+	Starred       bool         `json:"starred"`
+	PrNumber      int64        `json:"pr_number"`
+	PrState       string       `json:"pr_state"`
+	CiState       string       `json:"ci_state"`
+	EnrichedAt    sql.NullTime `json:"enriched_at"`
+	SeenAt        sql.NullTime `json:"seen_at"`
+	Author        string       `json:"author"`
+	CanonicalLink string       `json:"canonical_link"`
+	ContentHash   string       `json:"content_hash"`
+	Read          bool         `json:"read"`
+}
+
+// Unread items first when unread_first is set, a negative limit returns all the rest
+func (q *Queries) GetItemsWithReadStatusPage(ctx context.Context, arg GetItemsWithReadStatusPageParams) ([]GetItemsWithReadStatusPageRow, error) {
+	rows, err := q.db.QueryContext(ctx, getItemsWithReadStatusPage,
+		arg.FeedID,
+		arg.UnreadFirst,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetItemsWithReadStatusPageRow
+	for rows.Next() {
+		var i GetItemsWithReadStatusPageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FeedID,
+			&i.Guid,
+			&i.Title,
+			&i.Description,
+			&i.Content,
+			&i.Link,
+			&i.Published,
+			&i.CreatedAt,
+			&i.FullContent,
+			&i.Starred,
+			&i.PrNumber,
+			&i.PrState,
+			&i.CiState,
+			&i.EnrichedAt,
+			&i.SeenAt,
+			&i.Author,
+			&i.CanonicalLink,
+			&i.ContentHash,
+			&i.Read,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLogMessage = `-- name: GetLogMessage :one
+SELECT id, level, message, timestamp, attributes
+FROM log_messages
+WHERE id = ?
+`
+
+func (q *Queries) GetLogMessage(ctx context.Context, id int64) (LogMessage, error) {
+	row := q.db.QueryRowContext(ctx, getLogMessage, id)
+	var i LogMessage
+	err := row.Scan(
+		&i.ID,
+		&i.Level,
+		&i.Message,
+		&i.Timestamp,
+		&i.Attributes,
+	)
+	return i, err
+}
+
+const getLogMessages = `-- name: GetLogMessages :many
+SELECT id, level, message, timestamp, attributes
+FROM log_messages
+ORDER BY timestamp DESC, id DESC
+LIMIT ? OFFSET ?
+`
+
+type GetLogMessagesParams struct {
+	Limit  int64 `json:"limit"`
+	Offset int64 `json:"offset"`
+}
+
+func (q *Queries) GetLogMessages(ctx context.Context, arg GetLogMessagesParams) ([]LogMessage, error) {
+	rows, err := q.db.QueryContext(ctx, getLogMessages, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LogMessage
+	for rows.Next() {
+		var i LogMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.Level,
+			&i.Message,
+			&i.Timestamp,
+			&i.Attributes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLogStorage = `-- name: GetLogStorage :one
+SELECT COUNT(*) AS messages,
+       CAST(COALESCE(SUM(length(CAST(level AS BLOB)) + length(CAST(message AS BLOB))
+                         + length(CAST(COALESCE(timestamp, '') AS BLOB))
+                         + length(CAST(COALESCE(attributes, '') AS BLOB))), 0) AS INTEGER) AS bytes
+FROM log_messages
+`
+
+type GetLogStorageRow struct {
+	Messages int64 `json:"messages"`
+	Bytes    int64 `json:"bytes"`
+}
+
+// Number of log messages and the bytes their values take up, without the
+// database's own overhead
+func (q *Queries) GetLogStorage(ctx context.Context) (GetLogStorageRow, error) {
+	row := q.db.QueryRowContext(ctx, getLogStorage)
+	var i GetLogStorageRow
+	err := row.Scan(&i.Messages, &i.Bytes)
+	return i, err
+}
+
+const getReadEventsSince = `-- name: GetReadEventsSince :many
+SELECT read_at, published_at
+FROM read_events
+WHERE datetime(read_at) >= datetime('now', printf('-%d days', ?1))
+ORDER BY read_at
+`
+
+type GetReadEventsSinceRow struct {
+	ReadAt      time.Time    `json:"read_at"`
+	PublishedAt sql.NullTime `json:"published_at"`
+}
+
+func (q *Queries) GetReadEventsSince(ctx context.Context, days int64) ([]GetReadEventsSinceRow, error) {
+	rows, err := q.db.QueryContext(ctx, getReadEventsSince, days)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReadEventsSinceRow
+	for rows.Next() {
+		var i GetReadEventsSinceRow
+		if err := rows.Scan(&i.ReadAt, &i.PublishedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReadingLogRecent = `-- name: GetReadingLogRecent :many
+SELECT
+    i.id,
+    i.title,
+    i.link,
+    i.published,
+    f.title as feed_title,
+    rs.read_at
+FROM items i
+JOIN feeds f ON i.feed_id = f.id
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE COALESCE(rs.read, FALSE) = TRUE AND rs.read_at IS NOT NULL
+ORDER BY rs.read_at DESC
+LIMIT ?
+`
+
+type GetReadingLogRecentRow struct {
+	ID        int64        `json:"id"`
+	Title     string       `json:"title"`
+	Link      string       `json:"link"`
+	Published sql.NullTime `json:"published"`
+	FeedTitle string       `json:"feed_title"`
+	ReadAt    sql.NullTime `json:"read_at"`
+}
+
+func (q *Queries) GetReadingLogRecent(ctx context.Context, limit int64) ([]GetReadingLogRecentRow, error) {
+	rows, err := q.db.QueryContext(ctx, getReadingLogRecent, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReadingLogRecentRow
+	for rows.Next() {
+		var i GetReadingLogRecentRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Link,
+			&i.Published,
+			&i.FeedTitle,
+			&i.ReadAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getLogMessages = `-- name: GetLogMessages :many
-SELECT id, level, message, timestamp, attributes
-FROM log_messages
-ORDER BY timestamp DESC
+const getReadingLogStarred = `-- name: GetReadingLogStarred :many
+SELECT
+    i.id,
+    i.title,
+    i.link,
+    i.published,
+    f.title as feed_title,
+    rs.read_at
+FROM items i
+JOIN feeds f ON i.feed_id = f.id
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE i.starred = TRUE
+ORDER BY i.published DESC
 LIMIT ?
 `
 
-func (q *Queries) GetLogMessages(ctx context.Context, limit int64) ([]LogMessage, error) {
-	rows, err := q.db.QueryContext(ctx, getLogMessages, limit)
+type GetReadingLogStarredRow struct {
+	ID        int64        `json:"id"`
+	Title     string       `json:"title"`
+	Link      string       `json:"link"`
+	Published sql.NullTime `json:"published"`
+	FeedTitle string       `json:"feed_title"`
+	ReadAt    sql.NullTime `json:"read_at"`
+}
+
+func (q *Queries) GetReadingLogStarred(ctx context.Context, limit int64) ([]GetReadingLogStarredRow, error) {
+	rows, err := q.db.QueryContext(ctx, getReadingLogStarred, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []LogMessage
+	var items []GetReadingLogStarredRow
 	for rows.Next() {
-		var i LogMessage
+		var i GetReadingLogStarredRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.Level,
-			&i.Message,
-			&i.Timestamp,
-			&i.Attributes,
+			&i.Title,
+			&i.Link,
+			&i.Published,
+			&i.FeedTitle,
+			&i.ReadAt,
 		); err != nil {
 			return nil, err
 		}
@@ -532,6 +1839,438 @@ func (q *Queries) GetSetting(ctx context.Context, key string) (Setting, error) {
 	return i, err
 }
 
+const getStarredItems = `-- name: GetStarredItems :many
+SELECT
+    i.id, i.feed_id, i.guid, i.title, i.description, i.content, i.link, i.published, i.created_at, i.full_content, i.starred, i.pr_number, i.pr_state, i.ci_state, i.enriched_at, i.seen_at, i.author, i.canonical_link, i.content_hash,
+    COALESCE(rs.read, FALSE) as read
+FROM items i
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE i.starred = TRUE
+ORDER BY i.published DESC
+`
+
+type GetStarredItemsRow struct {
+	ID            int64        `json:"id"`
+	FeedID        int64        `json:"feed_id"`
+	Guid          string       `json:"guid"`
+	Title         string       `json:"title"`
+	Description   string       `json:"description"`
+	Content       string       `json:"content"`
+	Link          string       `json:"link"`
+	Published     sql.NullTime `json:"published"`
+	CreatedAt     sql.NullTime `json:"created_at"`
+	FullContent   string       `json:"full_content"`
+	Starred       bool         `json:"starred"`
+	PrNumber      int64        `json:"pr_number"`
+	PrState       string       `json:"pr_state"`
+	CiState       string       `json:"ci_state"`
+	EnrichedAt    sql.NullTime `json:"enriched_at"`
+	SeenAt        sql.NullTime `json:"seen_at"`
+	Author        string       `json:"author"`
+	CanonicalLink string       `json:"canonical_link"`
+	ContentHash   string       `json:"content_hash"`
+	Read          bool         `json:"read"`
+}
+
+func (q *Queries) GetStarredItems(ctx context.Context) ([]GetStarredItemsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getStarredItems)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetStarredItemsRow
+	for rows.Next() {
+		var i GetStarredItemsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FeedID,
+			&i.Guid,
+			&i.Title,
+			&i.Description,
+			&i.Content,
+			&i.Link,
+			&i.Published,
+			&i.CreatedAt,
+			&i.FullContent,
+			&i.Starred,
+			&i.PrNumber,
+			&i.PrState,
+			&i.CiState,
+			&i.EnrichedAt,
+			&i.SeenAt,
+			&i.Author,
+			&i.CanonicalLink,
+			&i.ContentHash,
+			&i.Read,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStarredItemsPage = `-- name: GetStarredItemsPage :many
+SELECT
+    i.id, i.feed_id, i.guid, i.title, i.description, i.content, i.link, i.published, i.created_at, i.full_content, i.starred, i.pr_number, i.pr_state, i.ci_state, i.enriched_at, i.seen_at, i.author, i.canonical_link, i.content_hash,
+    COALESCE(rs.read, FALSE) as read
+FROM items i
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE i.starred = TRUE
+ORDER BY CASE WHEN ? THEN COALESCE(rs.read, FALSE) ELSE FALSE END, i.published DESC, i.id DESC
+LIMIT ? OFFSET ?
+`
+
+type GetStarredItemsPageParams struct {
+	UnreadFirst bool  `json:"unread_first"`
+	Limit       int64 `json:"limit"`
+	Offset      int64 `json:"offset"`
+}
+
+type GetStarredItemsPageRow struct {
+	ID            int64        `json:"id"`
+	FeedID        int64        `json:"feed_id"`
+	Guid          string       `json:"guid"`
+	Title         string       `json:"title"`
+	Description   string       `json:"description"`
+	Content       string       `json:"content"`
+	Link          string       `json:"link"`
+	Published     sql.NullTime `json:"published"`
+	CreatedAt     sql.NullTime `json:"created_at"`
+	FullContent   string       `json:"full_content"`
+	Starred       bool         `json:"starred"`
+	PrNumber      int64        `json:"pr_number"`
+	PrState       string       `json:"pr_state"`
+	CiState       string       `json:"ci_state"`
+	EnrichedAt    sql.NullTime `json:"enriched_at"`
+	SeenAt        sql.NullTime `json:"seen_at"`
+	Author        string       `json:"author"`
+	CanonicalLink string       `json:"canonical_link"`
+	ContentHash   string       `json:"content_hash"`
+	Read          bool         `json:"read"`
+}
+
+func (q *Queries) GetStarredItemsPage(ctx context.Context, arg GetStarredItemsPageParams) ([]GetStarredItemsPageRow, error) {
+	rows, err := q.db.QueryContext(ctx, getStarredItemsPage, arg.UnreadFirst, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetStarredItemsPageRow
+	for rows.Next() {
+		var i GetStarredItemsPageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FeedID,
+			&i.Guid,
+			&i.Title,
+			&i.Description,
+			&i.Content,
+			&i.Link,
+			&i.Published,
+			&i.CreatedAt,
+			&i.FullContent,
+			&i.Starred,
+			&i.PrNumber,
+			&i.PrState,
+			&i.CiState,
+			&i.EnrichedAt,
+			&i.SeenAt,
+			&i.Author,
+			&i.CanonicalLink,
+			&i.ContentHash,
+			&i.Read,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStarredStats = `-- name: GetStarredStats :one
+SELECT
+    COUNT(i.id) as total_items,
+    COUNT(CASE WHEN COALESCE(rs.read, FALSE) = FALSE THEN 1 END) as unread_items
+FROM items i
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE i.starred = TRUE
+`
+
+type GetStarredStatsRow struct {
+	TotalItems  int64 `json:"total_items"`
+	UnreadItems int64 `json:"unread_items"`
+}
+
+func (q *Queries) GetStarredStats(ctx context.Context) (GetStarredStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getStarredStats)
+	var i GetStarredStatsRow
+	err := row.Scan(&i.TotalItems, &i.UnreadItems)
+	return i, err
+}
+
+const getSyncEntries = `-- name: GetSyncEntries :many
+SELECT
+    s.remote_id, i.id as item_id, s.feed_url, s.url, s.read, s.starred,
+    COALESCE(rs.read, FALSE) as local_read,
+    COALESCE(i.starred, FALSE) as local_starred
+FROM sync_entries s
+LEFT JOIN items i ON i.id = s.item_id
+LEFT JOIN read_status rs ON rs.item_id = s.item_id
+WHERE s.service = ?
+`
+
+type GetSyncEntriesRow struct {
+	RemoteID     string        `json:"remote_id"`
+	ItemID       sql.NullInt64 `json:"item_id"`
+	FeedUrl      string        `json:"feed_url"`
+	Url          string        `json:"url"`
+	Read         bool          `json:"read"`
+	Starred      bool          `json:"starred"`
+	LocalRead    bool          `json:"local_read"`
+	LocalStarred bool          `json:"local_starred"`
+}
+
+// item_id is NULL when the item isn't fetched yet or was deleted
+func (q *Queries) GetSyncEntries(ctx context.Context, service string) ([]GetSyncEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSyncEntries, service)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSyncEntriesRow
+	for rows.Next() {
+		var i GetSyncEntriesRow
+		if err := rows.Scan(
+			&i.RemoteID,
+			&i.ItemID,
+			&i.FeedUrl,
+			&i.Url,
+			&i.Read,
+			&i.Starred,
+			&i.LocalRead,
+			&i.LocalStarred,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTasks = `-- name: GetTasks :many
+SELECT id, type, status, priority, data, attempts, next_retry, error, created_at FROM tasks ORDER BY created_at
+`
+
+func (q *Queries) GetTasks(ctx context.Context) ([]Task, error) {
+	rows, err := q.db.QueryContext(ctx, getTasks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Task
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Status,
+			&i.Priority,
+			&i.Data,
+			&i.Attempts,
+			&i.NextRetry,
+			&i.Error,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnreadFeedIDs = `-- name: GetUnreadFeedIDs :many
+SELECT DISTINCT i.feed_id
+FROM items i
+JOIN feeds f ON i.feed_id = f.id
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE f.visible = TRUE AND COALESCE(rs.read, FALSE) = FALSE
+`
+
+// The visible feeds with unread items, for jumping to the next unread item
+func (q *Queries) GetUnreadFeedIDs(ctx context.Context) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, getUnreadFeedIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var feed_id int64
+		if err := rows.Scan(&feed_id); err != nil {
+			return nil, err
+		}
+		items = append(items, feed_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnreadItemIDsInFeed = `-- name: GetUnreadItemIDsInFeed :many
+SELECT i.id
+FROM items i
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE i.feed_id = ? AND COALESCE(rs.read, FALSE) = FALSE
+`
+
+func (q *Queries) GetUnreadItemIDsInFeed(ctx context.Context, feedID int64) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, getUnreadItemIDsInFeed, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnreadItems = `-- name: GetUnreadItems :many
+SELECT
+    i.id, i.feed_id, i.guid, i.title, i.description, i.content, i.link, i.published, i.created_at, i.full_content, i.starred, i.pr_number, i.pr_state, i.ci_state, i.enriched_at, i.seen_at, i.author, i.canonical_link, i.content_hash,
+    COALESCE(rs.read, FALSE) as read
+FROM items i
+INNER JOIN feeds f ON i.feed_id = f.id
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE f.visible = TRUE AND COALESCE(rs.read, FALSE) = FALSE
+ORDER BY i.published DESC
+`
+
+type GetUnreadItemsRow struct {
+	ID            int64        `json:"id"`
+	FeedID        int64        `json:"feed_id"`
+	Guid          string       `json:"guid"`
+	Title         string       `json:"title"`
+	Description   string       `json:"description"`
+	Content       string       `json:"content"`
+	Link          string       `json:"link"`
+	Published     sql.NullTime `json:"published"`
+	CreatedAt     sql.NullTime `json:"created_at"`
+	FullContent   string       `json:"full_content"`
+	Starred       bool         `json:"starred"`
+	PrNumber      int64        `json:"pr_number"`
+	PrState       string       `json:"pr_state"`
+	CiState       string       `json:"ci_state"`
+	EnrichedAt    sql.NullTime `json:"enriched_at"`
+	SeenAt        sql.NullTime `json:"seen_at"`
+	Author        string       `json:"author"`
+	CanonicalLink string       `json:"canonical_link"`
+	ContentHash   string       `json:"content_hash"`
+	Read          bool         `json:"read"`
+}
+
+func (q *Queries) GetUnreadItems(ctx context.Context) ([]GetUnreadItemsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUnreadItems)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUnreadItemsRow
+	for rows.Next() {
+		var i GetUnreadItemsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FeedID,
+			&i.Guid,
+			&i.Title,
+			&i.Description,
+			&i.Content,
+			&i.Link,
+			&i.Published,
+			&i.CreatedAt,
+			&i.FullContent,
+			&i.Starred,
+			&i.PrNumber,
+			&i.PrState,
+			&i.CiState,
+			&i.EnrichedAt,
+			&i.SeenAt,
+			&i.Author,
+			&i.CanonicalLink,
+			&i.ContentHash,
+			&i.Read,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWebSubSubscription = `-- name: GetWebSubSubscription :one
+SELECT feed_id, hub, topic, requested_at, lease_expires, secret FROM websub_subscriptions WHERE feed_id = ?
+`
+
+func (q *Queries) GetWebSubSubscription(ctx context.Context, feedID int64) (WebsubSubscription, error) {
+	row := q.db.QueryRowContext(ctx, getWebSubSubscription, feedID)
+	var i WebsubSubscription
+	err := row.Scan(
+		&i.FeedID,
+		&i.Hub,
+		&i.Topic,
+		&i.RequestedAt,
+		&i.LeaseExpires,
+		&i.Secret,
+	)
+	return i, err
+}
+
 const hideFeed = `-- name: HideFeed :exec
 UPDATE feeds SET visible = FALSE WHERE id = ?
 `
@@ -564,7 +2303,7 @@ func (q *Queries) IsItemRead(ctx context.Context, itemID int64) (bool, error) {
 }
 
 const listAllFeeds = `-- name: ListAllFeeds :many
-SELECT id, url, title, description, last_updated, last_error, last_error_time, visible, created_at, etag, last_modified, cache_control_max_age FROM feeds ORDER BY title
+SELECT id, url, title, description, last_updated, last_error, last_error_time, visible, created_at, etag, last_modified, cache_control_max_age, full_text, reload_interval, enrich, paused, body_hash, unchanged_fetches, consecutive_failures, retry_after, custom_title, last_result FROM feeds ORDER BY fold(title)
 `
 
 func (q *Queries) ListAllFeeds(ctx context.Context) ([]Feed, error) {
@@ -589,6 +2328,53 @@ func (q *Queries) ListAllFeeds(ctx context.Context) ([]Feed, error) {
 			&i.Etag,
 			&i.LastModified,
 			&i.CacheControlMaxAge,
+			&i.FullText,
+			&i.ReloadInterval,
+			&i.Enrich,
+			&i.Paused,
+			&i.BodyHash,
+			&i.UnchangedFetches,
+			&i.ConsecutiveFailures,
+			&i.RetryAfter,
+			&i.CustomTitle,
+			&i.LastResult,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFeedItemRules = `-- name: ListFeedItemRules :many
+SELECT id, feed_id, field, pattern, action, created_at, color, marker, notify FROM item_rules WHERE feed_id IS NULL OR feed_id = ? ORDER BY id
+`
+
+func (q *Queries) ListFeedItemRules(ctx context.Context, feedID sql.NullInt64) ([]ItemRule, error) {
+	rows, err := q.db.QueryContext(ctx, listFeedItemRules, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ItemRule
+	for rows.Next() {
+		var i ItemRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.FeedID,
+			&i.Field,
+			&i.Pattern,
+			&i.Action,
+			&i.CreatedAt,
+			&i.Color,
+			&i.Marker,
+			&i.Notify,
 		); err != nil {
 			return nil, err
 		}
@@ -604,7 +2390,7 @@ func (q *Queries) ListAllFeeds(ctx context.Context) ([]Feed, error) {
 }
 
 const listFeeds = `-- name: ListFeeds :many
-SELECT id, url, title, description, last_updated, last_error, last_error_time, visible, created_at, etag, last_modified, cache_control_max_age FROM feeds WHERE visible = TRUE ORDER BY title
+SELECT id, url, title, description, last_updated, last_error, last_error_time, visible, created_at, etag, last_modified, cache_control_max_age, full_text, reload_interval, enrich, paused, body_hash, unchanged_fetches, consecutive_failures, retry_after, custom_title, last_result FROM feeds WHERE visible = TRUE ORDER BY fold(title)
 `
 
 func (q *Queries) ListFeeds(ctx context.Context) ([]Feed, error) {
@@ -629,6 +2415,53 @@ func (q *Queries) ListFeeds(ctx context.Context) ([]Feed, error) {
 			&i.Etag,
 			&i.LastModified,
 			&i.CacheControlMaxAge,
+			&i.FullText,
+			&i.ReloadInterval,
+			&i.Enrich,
+			&i.Paused,
+			&i.BodyHash,
+			&i.UnchangedFetches,
+			&i.ConsecutiveFailures,
+			&i.RetryAfter,
+			&i.CustomTitle,
+			&i.LastResult,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listItemRules = `-- name: ListItemRules :many
+SELECT id, feed_id, field, pattern, action, created_at, color, marker, notify FROM item_rules ORDER BY id
+`
+
+func (q *Queries) ListItemRules(ctx context.Context) ([]ItemRule, error) {
+	rows, err := q.db.QueryContext(ctx, listItemRules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ItemRule
+	for rows.Next() {
+		var i ItemRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.FeedID,
+			&i.Field,
+			&i.Pattern,
+			&i.Action,
+			&i.CreatedAt,
+			&i.Color,
+			&i.Marker,
+			&i.Notify,
 		); err != nil {
 			return nil, err
 		}
@@ -644,7 +2477,7 @@ func (q *Queries) ListFeeds(ctx context.Context) ([]Feed, error) {
 }
 
 const listItemsByFeed = `-- name: ListItemsByFeed :many
-SELECT id, feed_id, guid, title, description, content, link, published, created_at FROM items
+SELECT id, feed_id, guid, title, description, content, link, published, created_at, full_content, starred, pr_number, pr_state, ci_state, enriched_at, seen_at, author, canonical_link, content_hash FROM items
 WHERE feed_id = ?
 ORDER BY published DESC
 `
@@ -668,6 +2501,16 @@ func (q *Queries) ListItemsByFeed(ctx context.Context, feedID int64) ([]Item, er
 			&i.Link,
 			&i.Published,
 			&i.CreatedAt,
+			&i.FullContent,
+			&i.Starred,
+			&i.PrNumber,
+			&i.PrState,
+			&i.CiState,
+			&i.EnrichedAt,
+			&i.SeenAt,
+			&i.Author,
+			&i.CanonicalLink,
+			&i.ContentHash,
 		); err != nil {
 			return nil, err
 		}
@@ -723,6 +2566,206 @@ func (q *Queries) MarkItemUnread(ctx context.Context, itemID int64) error {
 	return err
 }
 
+const pruneFeedItems = `-- name: PruneFeedItems :execrows
+DELETE FROM items
+WHERE items.feed_id = ?1
+  AND COALESCE(items.seen_at, '') < (SELECT MAX(latest.seen_at) FROM items AS latest WHERE latest.feed_id = items.feed_id)
+  AND NOT (?2 AND items.starred)
+  AND NOT (?3 AND items.id NOT IN (SELECT item_id FROM read_status WHERE read = TRUE))
+  AND (
+    (?4 > 0
+     AND datetime(COALESCE(items.published, items.created_at)) < datetime('now', printf('-%d days', ?4)))
+    OR (?5 > 0
+     AND items.id NOT IN (
+       SELECT newest.id FROM items AS newest
+       WHERE newest.feed_id = items.feed_id
+       ORDER BY datetime(COALESCE(newest.published, newest.created_at)) DESC, newest.id DESC
+       LIMIT ?5))
+  )
+`
+
+type PruneFeedItemsParams struct {
+	FeedID      int64 `json:"feed_id"`
+	KeepStarred bool  `json:"keep_starred"`
+	KeepUnread  bool  `json:"keep_unread"`
+	MaxAgeDays  int64 `json:"max_age_days"`
+	MaxItems    int64 `json:"max_items"`
+}
+
+// Items missing from the latest fetch of the feed are the only ones pruned,
+// anything the feed still publishes would come back unread on the next refresh
+func (q *Queries) PruneFeedItems(ctx context.Context, arg PruneFeedItemsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, pruneFeedItems,
+		arg.FeedID,
+		arg.KeepStarred,
+		arg.KeepUnread,
+		arg.MaxAgeDays,
+		arg.MaxItems,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const pruneLogMessages = `-- name: PruneLogMessages :execrows
+DELETE FROM log_messages
+WHERE (?1 > 0
+       AND datetime(timestamp) < datetime('now', printf('-%d days', ?1)))
+   OR (?2 > 0
+       AND id NOT IN (
+         SELECT newest.id FROM log_messages AS newest
+         ORDER BY newest.id DESC
+         LIMIT ?2))
+`
+
+type PruneLogMessagesParams struct {
+	MaxAgeDays  int64 `json:"max_age_days"`
+	MaxMessages int64 `json:"max_messages"`
+}
+
+func (q *Queries) PruneLogMessages(ctx context.Context, arg PruneLogMessagesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, pruneLogMessages, arg.MaxAgeDays, arg.MaxMessages)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const pruneSyncEntries = `-- name: PruneSyncEntries :execrows
+DELETE FROM sync_entries
+WHERE service = ? AND item_id IS NULL AND updated_at < ?
+`
+
+type PruneSyncEntriesParams struct {
+	Service   string    `json:"service"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (q *Queries) PruneSyncEntries(ctx context.Context, arg PruneSyncEntriesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, pruneSyncEntries, arg.Service, arg.UpdatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const recordItemEvent = `-- name: RecordItemEvent :exec
+INSERT INTO item_events (item_id, feed_id, event)
+VALUES (?, ?, ?)
+`
+
+type RecordItemEventParams struct {
+	ItemID int64  `json:"item_id"`
+	FeedID int64  `json:"feed_id"`
+	Event  string `json:"event"`
+}
+
+func (q *Queries) RecordItemEvent(ctx context.Context, arg RecordItemEventParams) error {
+	_, err := q.db.ExecContext(ctx, recordItemEvent, arg.ItemID, arg.FeedID, arg.Event)
+	return err
+}
+
+const recordUnchangedFetch = `-- name: RecordUnchangedFetch :exec
+UPDATE feeds SET unchanged_fetches = unchanged_fetches + 1 WHERE id = ?
+`
+
+func (q *Queries) RecordUnchangedFetch(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, recordUnchangedFetch, id)
+	return err
+}
+
+const requestWebSubSubscription = `-- name: RequestWebSubSubscription :exec
+INSERT INTO websub_subscriptions (feed_id, hub, topic, requested_at, secret)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(feed_id) DO UPDATE SET
+    lease_expires = CASE
+        WHEN websub_subscriptions.hub = excluded.hub AND websub_subscriptions.topic = excluded.topic
+        THEN websub_subscriptions.lease_expires
+    END,
+    hub = excluded.hub,
+    topic = excluded.topic,
+    requested_at = excluded.requested_at,
+    secret = excluded.secret
+`
+
+type RequestWebSubSubscriptionParams struct {
+	FeedID      int64     `json:"feed_id"`
+	Hub         string    `json:"hub"`
+	Topic       string    `json:"topic"`
+	RequestedAt time.Time `json:"requested_at"`
+	Secret      string    `json:"secret"`
+}
+
+// The lease stays valid while the feed keeps its hub and topic
+func (q *Queries) RequestWebSubSubscription(ctx context.Context, arg RequestWebSubSubscriptionParams) error {
+	_, err := q.db.ExecContext(ctx, requestWebSubSubscription,
+		arg.FeedID,
+		arg.Hub,
+		arg.Topic,
+		arg.RequestedAt,
+		arg.Secret,
+	)
+	return err
+}
+
+const saveFeedIcon = `-- name: SaveFeedIcon :exec
+INSERT INTO feed_icons (host, color, fetched_at)
+VALUES (?, ?, ?)
+ON CONFLICT(host) DO UPDATE SET
+    color = excluded.color,
+    fetched_at = excluded.fetched_at
+`
+
+type SaveFeedIconParams struct {
+	Host      string    `json:"host"`
+	Color     string    `json:"color"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+func (q *Queries) SaveFeedIcon(ctx context.Context, arg SaveFeedIconParams) error {
+	_, err := q.db.ExecContext(ctx, saveFeedIcon, arg.Host, arg.Color, arg.FetchedAt)
+	return err
+}
+
+const saveTask = `-- name: SaveTask :exec
+INSERT INTO tasks (id, type, status, priority, data, attempts, next_retry, error, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+    status = excluded.status,
+    data = excluded.data,
+    attempts = excluded.attempts,
+    next_retry = excluded.next_retry,
+    error = excluded.error
+`
+
+type SaveTaskParams struct {
+	ID        string       `json:"id"`
+	Type      string       `json:"type"`
+	Status    string       `json:"status"`
+	Priority  int64        `json:"priority"`
+	Data      string       `json:"data"`
+	Attempts  int64        `json:"attempts"`
+	NextRetry sql.NullTime `json:"next_retry"`
+	Error     string       `json:"error"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+func (q *Queries) SaveTask(ctx context.Context, arg SaveTaskParams) error {
+	_, err := q.db.ExecContext(ctx, saveTask,
+		arg.ID,
+		arg.Type,
+		arg.Status,
+		arg.Priority,
+		arg.Data,
+		arg.Attempts,
+		arg.NextRetry,
+		arg.Error,
+		arg.CreatedAt,
+	)
+	return err
+}
+
 const searchFeedsByTitle = `-- name: SearchFeedsByTitle :many
 SELECT
     f.id,
@@ -735,9 +2778,9 @@ SELECT
 FROM feeds f
 LEFT JOIN items i ON f.id = i.feed_id
 LEFT JOIN read_status rs ON i.id = rs.item_id
-WHERE f.visible = TRUE AND f.title LIKE '%' || ? || '%'
+WHERE f.visible = TRUE AND fold(f.title) LIKE '%' || fold(?) || '%'
 GROUP BY f.id, f.title, f.url, f.last_error, f.last_error_time
-ORDER BY f.title
+ORDER BY fold(f.title)
 `
 
 type SearchFeedsByTitleRow struct {
@@ -782,6 +2825,13 @@ func (q *Queries) SearchFeedsByTitle(ctx context.Context, dollar_1 sql.NullStrin
 }
 
 const searchFeedsGlobally = `-- name: SearchFeedsGlobally :many
+WITH matches AS (
+    SELECT mi.feed_id, COUNT(*) AS hits
+    FROM items_fts
+    INNER JOIN items mi ON mi.id = items_fts.rowid
+    WHERE items_fts MATCH ?1
+    GROUP BY mi.feed_id
+)
 SELECT
     f.id,
     f.title,
@@ -791,26 +2841,20 @@ SELECT
     COUNT(i.id) as total_items,
     COUNT(CASE WHEN i.id IS NOT NULL AND COALESCE(rs.read, FALSE) = FALSE THEN 1 END) as unread_items
 FROM feeds f
+LEFT JOIN matches m ON f.id = m.feed_id
 LEFT JOIN items i ON f.id = i.feed_id
 LEFT JOIN read_status rs ON i.id = rs.item_id
 WHERE f.visible = TRUE
-    AND (f.title LIKE '%' || ? || '%'
-         OR f.description LIKE '%' || ? || '%'
-         OR EXISTS (
-             SELECT 1 FROM items i2
-             WHERE i2.feed_id = f.id
-             AND (i2.title LIKE '%' || ? || '%' OR i2.description LIKE '%' || ? || '%' OR i2.content LIKE '%' || ? || '%')
-         ))
+    AND (m.hits IS NOT NULL
+         OR fold(f.title) LIKE '%' || fold(?2) || '%'
+         OR fold(f.description) LIKE '%' || fold(?2) || '%')
 GROUP BY f.id, f.title, f.url, f.last_error, f.last_error_time
-ORDER BY f.title
+ORDER BY COALESCE(MAX(m.hits), 0) DESC, fold(f.title)
 `
 
 type SearchFeedsGloballyParams struct {
-	Column1 sql.NullString `json:"column_1"`
-	Column2 sql.NullString `json:"column_2"`
-	Column3 sql.NullString `json:"column_3"`
-	Column4 sql.NullString `json:"column_4"`
-	Column5 sql.NullString `json:"column_5"`
+	Match   string         `json:"match"`
+	Pattern sql.NullString `json:"pattern"`
 }
 
 type SearchFeedsGloballyRow struct {
@@ -823,14 +2867,9 @@ type SearchFeedsGloballyRow struct {
 	UnreadItems   int64          `json:"unread_items"`
 }
 
+// Feeds with the most matching items come first
 func (q *Queries) SearchFeedsGlobally(ctx context.Context, arg SearchFeedsGloballyParams) ([]SearchFeedsGloballyRow, error) {
-	rows, err := q.db.QueryContext(ctx, searchFeedsGlobally,
-		arg.Column1,
-		arg.Column2,
-		arg.Column3,
-		arg.Column4,
-		arg.Column5,
-	)
+	rows, err := q.db.QueryContext(ctx, searchFeedsGlobally, arg.Match, arg.Pattern)
 	if err != nil {
 		return nil, err
 	}
@@ -862,11 +2901,11 @@ func (q *Queries) SearchFeedsGlobally(ctx context.Context, arg SearchFeedsGlobal
 
 const searchItemsByTitle = `-- name: SearchItemsByTitle :many
 SELECT
-    i.id, i.feed_id, i.guid, i.title, i.description, i.content, i.link, i.published, i.created_at,
+    i.id, i.feed_id, i.guid, i.title, i.description, i.content, i.link, i.published, i.created_at, i.full_content, i.starred, i.pr_number, i.pr_state, i.ci_state, i.enriched_at, i.seen_at, i.author, i.canonical_link, i.content_hash,
     COALESCE(rs.read, FALSE) as read
 FROM items i
 LEFT JOIN read_status rs ON i.id = rs.item_id
-WHERE i.feed_id = ? AND i.title LIKE '%' || ? || '%'
+WHERE i.feed_id = ? AND fold(i.title) LIKE '%' || fold(?) || '%'
 ORDER BY i.published DESC
 `
 
@@ -876,16 +2915,26 @@ type SearchItemsByTitleParams struct {
 }
 
 type SearchItemsByTitleRow struct {
-	ID          int64        `json:"id"`
-	FeedID      int64        `json:"feed_id"`
-	Guid        string       `json:"guid"`
-	Title       string       `json:"title"`
-	Description string       `json:"description"`
-	Content     string       `json:"content"`
-	Link        string       `json:"link"`
-	Published   sql.NullTime `json:"published"`
-	CreatedAt   sql.NullTime `json:"created_at"`
-	Read        bool         `json:"read"`
+	ID            int64        `json:"id"`
+	FeedID        int64        `json:"feed_id"`
+	Guid          string       `json:"guid"`
+	Title         string       `json:"title"`
+	Description   string       `json:"description"`
+	Content       string       `json:"content"`
+	Link          string       `json:"link"`
+	Published     sql.NullTime `json:"published"`
+	CreatedAt     sql.NullTime `json:"created_at"`
+	FullContent   string       `json:"full_content"`
+	Starred       bool         `json:"starred"`
+	PrNumber      int64        `json:"pr_number"`
+	PrState       string       `json:"pr_state"`
+	CiState       string       `json:"ci_state"`
+	EnrichedAt    sql.NullTime `json:"enriched_at"`
+	SeenAt        sql.NullTime `json:"seen_at"`
+	Author        string       `json:"author"`
+	CanonicalLink string       `json:"canonical_link"`
+	ContentHash   string       `json:"content_hash"`
+	Read          bool         `json:"read"`
 }
 
 func (q *Queries) SearchItemsByTitle(ctx context.Context, arg SearchItemsByTitleParams) ([]SearchItemsByTitleRow, error) {
@@ -907,6 +2956,16 @@ func (q *Queries) SearchItemsByTitle(ctx context.Context, arg SearchItemsByTitle
 			&i.Link,
 			&i.Published,
 			&i.CreatedAt,
+			&i.FullContent,
+			&i.Starred,
+			&i.PrNumber,
+			&i.PrState,
+			&i.CiState,
+			&i.EnrichedAt,
+			&i.SeenAt,
+			&i.Author,
+			&i.CanonicalLink,
+			&i.ContentHash,
 			&i.Read,
 		); err != nil {
 			return nil, err
@@ -924,41 +2983,46 @@ func (q *Queries) SearchItemsByTitle(ctx context.Context, arg SearchItemsByTitle
 
 const searchItemsGlobally = `-- name: SearchItemsGlobally :many
 SELECT
-    i.id, i.feed_id, i.guid, i.title, i.description, i.content, i.link, i.published, i.created_at,
+    i.id, i.feed_id, i.guid, i.title, i.description, i.content, i.link, i.published, i.created_at, i.full_content, i.starred, i.pr_number, i.pr_state, i.ci_state, i.enriched_at, i.seen_at, i.author, i.canonical_link, i.content_hash,
     COALESCE(rs.read, FALSE) as read
-FROM items i
+FROM items_fts
+INNER JOIN items i ON i.id = items_fts.rowid
 LEFT JOIN read_status rs ON i.id = rs.item_id
-WHERE i.feed_id = ? AND (i.title LIKE '%' || ? || '%' OR i.description LIKE '%' || ? || '%' OR i.content LIKE '%' || ? || '%')
-ORDER BY i.published DESC
+WHERE i.feed_id = ?1 AND items_fts MATCH ?2
+ORDER BY bm25(items_fts, 10.0, 2.0, 1.0), i.published DESC
 `
 
 type SearchItemsGloballyParams struct {
-	FeedID  int64          `json:"feed_id"`
-	Column2 sql.NullString `json:"column_2"`
-	Column3 sql.NullString `json:"column_3"`
-	Column4 sql.NullString `json:"column_4"`
+	FeedID int64  `json:"feed_id"`
+	Match  string `json:"match"`
 }
 
 type SearchItemsGloballyRow struct {
-	ID          int64        `json:"id"`
-	FeedID      int64        `json:"feed_id"`
-	Guid        string       `json:"guid"`
-	Title       string       `json:"title"`
-	Description string       `json:"description"`
-	Content     string       `json:"content"`
-	Link        string       `json:"link"`
-	Published   sql.NullTime `json:"published"`
-	CreatedAt   sql.NullTime `json:"created_at"`
-	Read        bool         `json:"read"`
+	ID            int64        `json:"id"`
+	FeedID        int64        `json:"feed_id"`
+	Guid          string       `json:"guid"`
+	Title         string       `json:"title"`
+	Description   string       `json:"description"`
+	Content       string       `json:"content"`
+	Link          string       `json:"link"`
+	Published     sql.NullTime `json:"published"`
+	CreatedAt     sql.NullTime `json:"created_at"`
+	FullContent   string       `json:"full_content"`
+	Starred       bool         `json:"starred"`
+	PrNumber      int64        `json:"pr_number"`
+	PrState       string       `json:"pr_state"`
+	CiState       string       `json:"ci_state"`
+	EnrichedAt    sql.NullTime `json:"enriched_at"`
+	SeenAt        sql.NullTime `json:"seen_at"`
+	Author        string       `json:"author"`
+	CanonicalLink string       `json:"canonical_link"`
+	ContentHash   string       `json:"content_hash"`
+	Read          bool         `json:"read"`
 }
 
+// Best matches first, a match in the title weighs more than one in the body
 func (q *Queries) SearchItemsGlobally(ctx context.Context, arg SearchItemsGloballyParams) ([]SearchItemsGloballyRow, error) {
-	rows, err := q.db.QueryContext(ctx, searchItemsGlobally,
-		arg.FeedID,
-		arg.Column2,
-		arg.Column3,
-		arg.Column4,
-	)
+	rows, err := q.db.QueryContext(ctx, searchItemsGlobally, arg.FeedID, arg.Match)
 	if err != nil {
 		return nil, err
 	}
@@ -976,6 +3040,16 @@ func (q *Queries) SearchItemsGlobally(ctx context.Context, arg SearchItemsGlobal
 			&i.Link,
 			&i.Published,
 			&i.CreatedAt,
+			&i.FullContent,
+			&i.Starred,
+			&i.PrNumber,
+			&i.PrState,
+			&i.CiState,
+			&i.EnrichedAt,
+			&i.SeenAt,
+			&i.Author,
+			&i.CanonicalLink,
+			&i.ContentHash,
 			&i.Read,
 		); err != nil {
 			return nil, err
@@ -991,6 +3065,141 @@ func (q *Queries) SearchItemsGlobally(ctx context.Context, arg SearchItemsGlobal
 	return items, nil
 }
 
+const setFeedBodyHash = `-- name: SetFeedBodyHash :exec
+UPDATE feeds SET body_hash = ? WHERE id = ?
+`
+
+type SetFeedBodyHashParams struct {
+	BodyHash string `json:"body_hash"`
+	ID       int64  `json:"id"`
+}
+
+func (q *Queries) SetFeedBodyHash(ctx context.Context, arg SetFeedBodyHashParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedBodyHash, arg.BodyHash, arg.ID)
+	return err
+}
+
+const setFeedCustomTitle = `-- name: SetFeedCustomTitle :exec
+UPDATE feeds
+SET title = CASE WHEN ?1 = '' THEN title ELSE ?1 END,
+    etag = CASE WHEN ?1 = '' AND custom_title != '' THEN NULL ELSE etag END,
+    last_modified = CASE WHEN ?1 = '' AND custom_title != '' THEN NULL ELSE last_modified END,
+    cache_control_max_age = CASE WHEN ?1 = '' AND custom_title != '' THEN NULL ELSE cache_control_max_age END,
+    body_hash = CASE WHEN ?1 = '' AND custom_title != '' THEN '' ELSE body_hash END,
+    custom_title = ?1
+WHERE id = ?2
+`
+
+type SetFeedCustomTitleParams struct {
+	CustomTitle string `json:"custom_title"`
+	ID          int64  `json:"id"`
+}
+
+// Clearing the title starts the cache over, the next refresh parses the feed
+// again for its own title
+func (q *Queries) SetFeedCustomTitle(ctx context.Context, arg SetFeedCustomTitleParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedCustomTitle, arg.CustomTitle, arg.ID)
+	return err
+}
+
+const setFeedEnrich = `-- name: SetFeedEnrich :exec
+UPDATE feeds SET enrich = ? WHERE id = ?
+`
+
+type SetFeedEnrichParams struct {
+	Enrich bool  `json:"enrich"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) SetFeedEnrich(ctx context.Context, arg SetFeedEnrichParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedEnrich, arg.Enrich, arg.ID)
+	return err
+}
+
+const setFeedFullText = `-- name: SetFeedFullText :exec
+UPDATE feeds SET full_text = ? WHERE id = ?
+`
+
+type SetFeedFullTextParams struct {
+	FullText bool  `json:"full_text"`
+	ID       int64 `json:"id"`
+}
+
+func (q *Queries) SetFeedFullText(ctx context.Context, arg SetFeedFullTextParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedFullText, arg.FullText, arg.ID)
+	return err
+}
+
+const setFeedLastResult = `-- name: SetFeedLastResult :exec
+UPDATE feeds SET last_result = ? WHERE id = ?
+`
+
+type SetFeedLastResultParams struct {
+	LastResult string `json:"last_result"`
+	ID         int64  `json:"id"`
+}
+
+func (q *Queries) SetFeedLastResult(ctx context.Context, arg SetFeedLastResultParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedLastResult, arg.LastResult, arg.ID)
+	return err
+}
+
+const setFeedPaused = `-- name: SetFeedPaused :exec
+UPDATE feeds SET paused = ? WHERE id = ?
+`
+
+type SetFeedPausedParams struct {
+	Paused bool  `json:"paused"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) SetFeedPaused(ctx context.Context, arg SetFeedPausedParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedPaused, arg.Paused, arg.ID)
+	return err
+}
+
+const setFeedReloadInterval = `-- name: SetFeedReloadInterval :exec
+UPDATE feeds SET reload_interval = ? WHERE id = ?
+`
+
+type SetFeedReloadIntervalParams struct {
+	ReloadInterval int64 `json:"reload_interval"`
+	ID             int64 `json:"id"`
+}
+
+func (q *Queries) SetFeedReloadInterval(ctx context.Context, arg SetFeedReloadIntervalParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedReloadInterval, arg.ReloadInterval, arg.ID)
+	return err
+}
+
+const setFeedRetryAfter = `-- name: SetFeedRetryAfter :exec
+UPDATE feeds SET retry_after = ? WHERE id = ?
+`
+
+type SetFeedRetryAfterParams struct {
+	RetryAfter sql.NullTime `json:"retry_after"`
+	ID         int64        `json:"id"`
+}
+
+func (q *Queries) SetFeedRetryAfter(ctx context.Context, arg SetFeedRetryAfterParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedRetryAfter, arg.RetryAfter, arg.ID)
+	return err
+}
+
+const setItemStarred = `-- name: SetItemStarred :exec
+UPDATE items SET starred = ? WHERE id = ?
+`
+
+type SetItemStarredParams struct {
+	Starred bool  `json:"starred"`
+	ID      int64 `json:"id"`
+}
+
+func (q *Queries) SetItemStarred(ctx context.Context, arg SetItemStarredParams) error {
+	_, err := q.db.ExecContext(ctx, setItemStarred, arg.Starred, arg.ID)
+	return err
+}
+
 const setSetting = `-- name: SetSetting :exec
 INSERT INTO settings (key, value, updated_at)
 VALUES (?, ?, CURRENT_TIMESTAMP)
@@ -1009,6 +3218,20 @@ func (q *Queries) SetSetting(ctx context.Context, arg SetSettingParams) error {
 	return err
 }
 
+const setWebSubLease = `-- name: SetWebSubLease :exec
+UPDATE websub_subscriptions SET lease_expires = ? WHERE feed_id = ?
+`
+
+type SetWebSubLeaseParams struct {
+	LeaseExpires sql.NullTime `json:"lease_expires"`
+	FeedID       int64        `json:"feed_id"`
+}
+
+func (q *Queries) SetWebSubLease(ctx context.Context, arg SetWebSubLeaseParams) error {
+	_, err := q.db.ExecContext(ctx, setWebSubLease, arg.LeaseExpires, arg.FeedID)
+	return err
+}
+
 const showFeed = `-- name: ShowFeed :exec
 UPDATE feeds SET visible = TRUE WHERE id = ?
 `
@@ -1029,7 +3252,7 @@ func (q *Queries) ShowFeedByURL(ctx context.Context, url string) error {
 
 const updateFeed = `-- name: UpdateFeed :exec
 UPDATE feeds
-SET title = ?, description = ?, last_updated = ?, etag = ?, last_modified = ?, cache_control_max_age = ?
+SET title = CASE WHEN custom_title = '' THEN ? ELSE custom_title END, description = ?, last_updated = ?, etag = ?, last_modified = ?, cache_control_max_age = ?
 WHERE id = ?
 `
 
@@ -1040,9 +3263,13 @@ type UpdateFeedParams struct {
 	Etag               sql.NullString `json:"etag"`
 	LastModified       sql.NullString `json:"last_modified"`
 	CacheControlMaxAge sql.NullInt64  `json:"cache_control_max_age"`
+	FullText           bool           `json:"full_text"`
+	ReloadInterval     int64          `json:"reload_interval"`
+	Enrich             bool           `json:"enrich"`
 	ID                 int64          `json:"id"`
 }
 
+// A custom title stays
 func (q *Queries) UpdateFeed(ctx context.Context, arg UpdateFeedParams) error {
 	_, err := q.db.ExecContext(ctx, updateFeed,
 		arg.Title,
@@ -1058,7 +3285,7 @@ func (q *Queries) UpdateFeed(ctx context.Context, arg UpdateFeedParams) error {
 
 const updateFeedError = `-- name: UpdateFeedError :exec
 UPDATE feeds
-SET last_error = ?, last_error_time = ?
+SET last_error = ?, last_error_time = ?, consecutive_failures = consecutive_failures + 1
 WHERE id = ?
 `
 
@@ -1073,26 +3300,89 @@ func (q *Queries) UpdateFeedError(ctx context.Context, arg UpdateFeedErrorParams
 	return err
 }
 
+const updateFeedURL = `-- name: UpdateFeedURL :exec
+UPDATE feeds
+SET url = ?, etag = NULL, last_modified = NULL, cache_control_max_age = NULL, body_hash = '', unchanged_fetches = 0,
+    last_error = NULL, last_error_time = NULL, consecutive_failures = 0
+WHERE id = ?
+`
+
+type UpdateFeedURLParams struct {
+	Url string `json:"url"`
+	ID  int64  `json:"id"`
+}
+
+// A new URL starts over, the cache headers and failures were the old one's
+func (q *Queries) UpdateFeedURL(ctx context.Context, arg UpdateFeedURLParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedURL, arg.Url, arg.ID)
+	return err
+}
+
+const updateItemEnrichment = `-- name: UpdateItemEnrichment :exec
+UPDATE items SET pr_number = ?, pr_state = ?, ci_state = ?, enriched_at = ? WHERE id = ?
+`
+
+type UpdateItemEnrichmentParams struct {
+	PrNumber   int64        `json:"pr_number"`
+	PrState    string       `json:"pr_state"`
+	CiState    string       `json:"ci_state"`
+	EnrichedAt sql.NullTime `json:"enriched_at"`
+	ID         int64        `json:"id"`
+}
+
+func (q *Queries) UpdateItemEnrichment(ctx context.Context, arg UpdateItemEnrichmentParams) error {
+	_, err := q.db.ExecContext(ctx, updateItemEnrichment,
+		arg.PrNumber,
+		arg.PrState,
+		arg.CiState,
+		arg.EnrichedAt,
+		arg.ID,
+	)
+	return err
+}
+
+const updateItemFullContent = `-- name: UpdateItemFullContent :exec
+UPDATE items SET full_content = ? WHERE id = ?
+`
+
+type UpdateItemFullContentParams struct {
+	FullContent string `json:"full_content"`
+	ID          int64  `json:"id"`
+}
+
+func (q *Queries) UpdateItemFullContent(ctx context.Context, arg UpdateItemFullContentParams) error {
+	_, err := q.db.ExecContext(ctx, updateItemFullContent, arg.FullContent, arg.ID)
+	return err
+}
+
 const upsertItem = `-- name: UpsertItem :one
-INSERT INTO items (feed_id, guid, title, description, content, link, published)
-VALUES (?, ?, ?, ?, ?, ?, ?)
+INSERT INTO items (feed_id, guid, title, description, content, link, published, seen_at, author, canonical_link, content_hash)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 ON CONFLICT(feed_id, guid) DO UPDATE SET
     title = excluded.title,
     description = excluded.description,
     content = excluded.content,
     link = excluded.link,
-    published = excluded.published
-RETURNING id, feed_id, guid, title, description, content, link, published, created_at
+    published = excluded.published,
+    seen_at = excluded.seen_at,
+    author = excluded.author,
+    canonical_link = excluded.canonical_link,
+    content_hash = excluded.content_hash
+RETURNING id, feed_id, guid, title, description, content, link, published, created_at, full_content, starred, pr_number, pr_state, ci_state, enriched_at, seen_at, author, canonical_link, content_hash
 `
 
 type UpsertItemParams struct {
-	FeedID      int64        `json:"feed_id"`
-	Guid        string       `json:"guid"`
-	Title       string       `json:"title"`
-	Description string       `json:"description"`
-	Content     string       `json:"content"`
-	Link        string       `json:"link"`
-	Published   sql.NullTime `json:"published"`
+	FeedID        int64        `json:"feed_id"`
+	Guid          string       `json:"guid"`
+	Title         string       `json:"title"`
+	Description   string       `json:"description"`
+	Content       string       `json:"content"`
+	Link          string       `json:"link"`
+	Published     sql.NullTime `json:"published"`
+	SeenAt        sql.NullTime `json:"seen_at"`
+	Author        string       `json:"author"`
+	CanonicalLink string       `json:"canonical_link"`
+	ContentHash   string       `json:"content_hash"`
 }
 
 func (q *Queries) UpsertItem(ctx context.Context, arg UpsertItemParams) (Item, error) {
@@ -1104,6 +3394,10 @@ func (q *Queries) UpsertItem(ctx context.Context, arg UpsertItemParams) (Item, e
 		arg.Content,
 		arg.Link,
 		arg.Published,
+		arg.SeenAt,
+		arg.Author,
+		arg.CanonicalLink,
+		arg.ContentHash,
 	)
 	var i Item
 	err := row.Scan(
@@ -1116,6 +3410,53 @@ func (q *Queries) UpsertItem(ctx context.Context, arg UpsertItemParams) (Item, e
 		&i.Link,
 		&i.Published,
 		&i.CreatedAt,
+		&i.FullContent,
+		&i.Starred,
+		&i.PrNumber,
+		&i.PrState,
+		&i.CiState,
+		&i.EnrichedAt,
+		&i.SeenAt,
+		&i.Author,
+		&i.CanonicalLink,
+		&i.ContentHash,
 	)
 	return i, err
 }
+
+const upsertSyncEntry = `-- name: UpsertSyncEntry :exec
+INSERT INTO sync_entries (service, remote_id, item_id, feed_url, url, read, starred, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(service, remote_id) DO UPDATE SET
+    item_id = excluded.item_id,
+    feed_url = excluded.feed_url,
+    url = excluded.url,
+    read = excluded.read,
+    starred = excluded.starred,
+    updated_at = excluded.updated_at
+`
+
+type UpsertSyncEntryParams struct {
+	Service   string        `json:"service"`
+	RemoteID  string        `json:"remote_id"`
+	ItemID    sql.NullInt64 `json:"item_id"`
+	FeedUrl   string        `json:"feed_url"`
+	Url       string        `json:"url"`
+	Read      bool          `json:"read"`
+	Starred   bool          `json:"starred"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+func (q *Queries) UpsertSyncEntry(ctx context.Context, arg UpsertSyncEntryParams) error {
+	_, err := q.db.ExecContext(ctx, upsertSyncEntry,
+		arg.Service,
+		arg.RemoteID,
+		arg.ItemID,
+		arg.FeedUrl,
+		arg.Url,
+		arg.Read,
+		arg.Starred,
+		arg.UpdatedAt,
+	)
+	return err
+}
@@ -6,7 +6,10 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/jarv/newsgoat/internal/logging"
+	"github.com/mmcdole/gofeed"
 	"golang.org/x/net/html"
 )
 
@@ -20,6 +23,31 @@ const (
 	URLTypeGeneric URLType = "generic"
 )
 
+// Result describes how a feed URL was found
+type Result struct {
+	FeedURL  string
+	Type     URLType
+	FromHTML bool // The feed was linked from an HTML page
+}
+
+// Discover is DiscoverFeed, also reporting the type of URL and whether the
+// feed was linked from an HTML page
+func Discover(url string) (Result, error) {
+	result := Result{Type: GetURLType(url)}
+	if isLikelyFeedURL(url) {
+		result.Type = URLTypeGeneric
+	}
+
+	feedURL, err := DiscoverFeed(url)
+	if err != nil {
+		return result, err
+	}
+	result.FeedURL = feedURL
+	// Only generic URLs are looked up in HTML, the others are rewritten
+	result.FromHTML = result.Type == URLTypeGeneric && feedURL != url
+	return result, nil
+}
+
 // DiscoverFeed attempts to discover an RSS/Atom feed URL from a given URL.
 // If the URL is already a feed, it returns it as-is.
 // If it's a YouTube URL, it extracts the channel ID and returns the YouTube RSS feed.
@@ -35,6 +63,7 @@ func DiscoverFeed(url string) (string, error) {
 
 	// Check URL type and handle accordingly
 	urlType := GetURLType(url)
+	logging.DebugCategory(logging.CategoryDiscovery, "Discovering feed", "url", url, "type", urlType)
 
 	switch urlType {
 	case URLTypeYouTube:
@@ -110,6 +139,7 @@ func discoverFeedFromHTML(htmlContent string, baseURL string) (string, error) {
 	if feedURL == "" {
 		return "", fmt.Errorf("no feed link found in HTML")
 	}
+	logging.DebugCategory(logging.CategoryDiscovery, "Found feed link in HTML", "baseURL", baseURL, "href", feedURL)
 
 	// If the feed URL is relative, make it absolute
 	if !strings.HasPrefix(feedURL, "http://") && !strings.HasPrefix(feedURL, "https://") {
@@ -280,6 +310,31 @@ func discoverGitLabFeed(url string) (string, error) {
 	return feedURL, nil
 }
 
+// titleTimeout bounds fetching a feed for its title
+const titleTimeout = 15 * time.Second
+
+// FetchTitle fetches a feed and returns its title
+func FetchTitle(feedURL string) (string, error) {
+	client := &http.Client{Timeout: titleTimeout}
+	resp, err := client.Get(feedURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP error: %d", resp.StatusCode)
+	}
+
+	feed, err := gofeed.NewParser().Parse(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse feed: %w", err)
+	}
+	return strings.TrimSpace(feed.Title), nil
+}
+
 // discoverYouTubeFeed extracts the channel ID from a YouTube URL and returns the RSS feed URL
 func discoverYouTubeFeed(url string) (string, error) {
 	resp, err := http.Get(url)
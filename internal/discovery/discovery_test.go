@@ -1,6 +1,9 @@
 package discovery
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -382,3 +385,44 @@ func TestIsLikelyFeedURL(t *testing.T) {
 		})
 	}
 }
+
+func TestDiscover(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/feed":
+			w.Header().Set("Content-Type", "application/rss+xml")
+			_, _ = fmt.Fprint(w, `<rss version="2.0"><channel><title> Example Feed </title></channel></rss>`)
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = fmt.Fprint(w, `<html><head><link rel="alternate" type="application/rss+xml" href="/feed"></head></html>`)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		url  string
+		want Result
+	}{
+		{"feed", server.URL + "/feed", Result{FeedURL: server.URL + "/feed", Type: URLTypeGeneric}},
+		{"HTML page", server.URL + "/blog", Result{FeedURL: server.URL + "/feed", Type: URLTypeGeneric, FromHTML: true}},
+		{"GitHub", "https://github.com/owner/repo/commits/main", Result{FeedURL: "https://github.com/owner/repo/commits/main.atom", Type: URLTypeGitHub}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Discover(tt.url)
+			if err != nil {
+				t.Fatalf("Discover() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Discover() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	title, err := FetchTitle(server.URL + "/feed")
+	if err != nil || title != "Example Feed" {
+		t.Errorf("FetchTitle() = %q, %v, want Example Feed", title, err)
+	}
+}
@@ -0,0 +1,224 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jarv/newsgoat/internal/config"
+)
+
+// Formats an article can be exported to
+const (
+	ArticleMarkdown = "md"
+	ArticleHTML     = "html"
+	ArticlePDF      = "pdf"
+)
+
+const (
+	// maxFileNameValue is the most runes of a value put in a file name
+	maxFileNameValue = 80
+	// pdfCommandTimeout is how long the PDF converter may take
+	pdfCommandTimeout = 2 * time.Minute
+)
+
+// ExportedArticle is an article to save to a file
+type ExportedArticle struct {
+	Title     string
+	Link      string
+	Author    string
+	Feed      string
+	Published time.Time // Zero when the feed didn't date the article
+	Content   string    // HTML
+}
+
+// ArticleFileName fills in the {feed}, {title}, {author} and {date}
+// placeholders of a file name template and adds the extension of the format.
+// Characters that can't be in a file name are replaced.
+func ArticleFileName(template string, article ExportedArticle, format string) string {
+	if strings.TrimSpace(template) == "" {
+		template = config.DefaultExportFileName
+	}
+	date := ""
+	if !article.Published.IsZero() {
+		date = article.Published.Local().Format("2006-01-02")
+	}
+	name := strings.NewReplacer(
+		"{feed}", fileNameValue(article.Feed),
+		"{title}", fileNameValue(article.Title),
+		"{author}", fileNameValue(article.Author),
+		"{date}", date,
+	).Replace(template)
+
+	// Placeholders without a value may leave separators at the ends
+	name = strings.Join(strings.Fields(fileNameValue(name)), " ")
+	name = strings.Trim(name, " -_.")
+	if name == "" {
+		name = "article"
+	}
+	return name + "." + format
+}
+
+// fileNameValue replaces the characters that aren't allowed in file names on
+// any system and shortens long values
+func fileNameValue(value string) string {
+	value = strings.Map(func(r rune) rune {
+		switch {
+		case strings.ContainsRune(`/\:*?"<>|`, r):
+			return '-'
+		case unicode.IsControl(r):
+			return ' '
+		}
+		return r
+	}, value)
+	if runes := []rune(value); len(runes) > maxFileNameValue {
+		value = strings.TrimSpace(string(runes[:maxFileNameValue]))
+	}
+	return value
+}
+
+// ArticleDocument returns an article as a markdown or HTML document, with
+// its title, feed, author, date and link above the content
+func (m *Manager) ArticleDocument(article ExportedArticle, format string) string {
+	var details []string
+	for _, detail := range []string{article.Feed, article.Author} {
+		if detail != "" {
+			details = append(details, detail)
+		}
+	}
+	if !article.Published.IsZero() {
+		details = append(details, article.Published.Local().Format("2006-01-02 15:04"))
+	}
+
+	if format == ArticleMarkdown {
+		var b strings.Builder
+		b.WriteString("# " + article.Title + "\n\n")
+		if len(details) > 0 {
+			b.WriteString(strings.Join(details, " · ") + "\n\n")
+		}
+		if article.Link != "" {
+			b.WriteString("<" + article.Link + ">\n\n")
+		}
+		if content := m.ConvertHTMLToMarkdown(article.Content); content != "" {
+			b.WriteString(content + "\n")
+		}
+		return b.String()
+	}
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	if article.Link != "" {
+		// Relative links and images of the content resolve against the article
+		b.WriteString("<base href=\"" + html.EscapeString(article.Link) + "\">\n")
+	}
+	b.WriteString("<title>" + html.EscapeString(article.Title) + "</title>\n</head>\n<body>\n")
+	b.WriteString("<h1>" + html.EscapeString(article.Title) + "</h1>\n")
+	if len(details) > 0 {
+		b.WriteString("<p>" + html.EscapeString(strings.Join(details, " · ")) + "</p>\n")
+	}
+	if article.Link != "" {
+		b.WriteString("<p><a href=\"" + html.EscapeString(article.Link) + "\">" + html.EscapeString(article.Link) + "</a></p>\n")
+	}
+	b.WriteString("<hr>\n" + article.Content + "\n</body>\n</html>\n")
+	return b.String()
+}
+
+// ExportArticle saves an article to a new file in dir, named after the
+// template. PDFs are the HTML document converted by pdfCommand, a shell
+// command with {input} and {output} placeholders for the HTML and the PDF
+// file. An existing file is never overwritten, a number is added to the name
+// instead. It returns the path of the file.
+func (m *Manager) ExportArticle(ctx context.Context, article ExportedArticle, dir, template, format, pdfCommand string) (string, error) {
+	if format == ArticlePDF && strings.TrimSpace(pdfCommand) == "" {
+		return "", errors.New("no export PDF command set, add one in settings (c)")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path, file, err := createExportFile(dir, ArticleFileName(template, article, format))
+	if err != nil {
+		return "", err
+	}
+
+	if format != ArticlePDF {
+		_, err := file.WriteString(m.ArticleDocument(article, format))
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = os.Remove(path)
+			return "", err
+		}
+		return path, nil
+	}
+
+	// The converter writes the PDF, the file is only created to claim the name
+	_ = file.Close()
+	if err := convertToPDF(ctx, m.ArticleDocument(article, ArticleHTML), path, pdfCommand); err != nil {
+		_ = os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// createExportFile creates a file that doesn't exist yet, adding " (2)",
+// " (3)" and so on to the name when it does
+func createExportFile(dir, name string) (string, *os.File, error) {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for n := 1; ; n++ {
+		path := filepath.Join(dir, name)
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err == nil {
+			return path, file, nil
+		}
+		if !errors.Is(err, os.ErrExist) || n == 100 {
+			return "", nil, err
+		}
+		name = fmt.Sprintf("%s (%d)%s", base, n+1, ext)
+	}
+}
+
+// convertToPDF runs the PDF command on a temp file holding the HTML
+func convertToPDF(ctx context.Context, document, output, command string) error {
+	input, err := os.CreateTemp("", "newsgoat-export-*.html")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(input.Name())
+	}()
+	if _, err := input.WriteString(document); err != nil {
+		_ = input.Close()
+		return err
+	}
+	if err := input.Close(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pdfCommandTimeout)
+	defer cancel()
+	command = config.ExpandShareCommand(command, map[string]string{"input": input.Name(), "output": output})
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	if out, err := exec.CommandContext(ctx, shell, flag, command).CombinedOutput(); err != nil {
+		if message := strings.TrimSpace(string(out)); message != "" {
+			return fmt.Errorf("PDF command failed: %w: %s", err, message)
+		}
+		return fmt.Errorf("PDF command failed: %w", err)
+	}
+	if info, err := os.Stat(output); err != nil || info.Size() == 0 {
+		return errors.New("PDF command didn't write the {output} file")
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package feeds
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testExportedArticle() ExportedArticle {
+	return ExportedArticle{
+		Title:     "Tom & Jerry: a <history>",
+		Link:      "https://example.com/posts/tom",
+		Author:    "Jane",
+		Feed:      "Cartoons/Weekly",
+		Published: time.Date(2025, 10, 1, 12, 0, 0, 0, time.Local),
+		Content:   `<p>They <a href="/chase">chase</a> each other.</p>`,
+	}
+}
+
+func TestArticleFileName(t *testing.T) {
+	article := testExportedArticle()
+	undated := article
+	undated.Published = time.Time{}
+
+	tests := []struct {
+		name     string
+		template string
+		article  ExportedArticle
+		want     string
+	}{
+		{"default", "", article, "2025-10-01 Cartoons-Weekly - Tom & Jerry- a -history.md"},
+		{"author", "{author}_{title}", article, "Jane_Tom & Jerry- a -history.md"},
+		{"no date", "{date} {title}", undated, "Tom & Jerry- a -history.md"},
+		{"nothing left", "{date}", undated, "article.md"},
+		{"long title", "{title}", ExportedArticle{Title: strings.Repeat("a", 200)}, strings.Repeat("a", maxFileNameValue) + ".md"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ArticleFileName(tt.template, tt.article, ArticleMarkdown); got != tt.want {
+				t.Errorf("ArticleFileName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportArticle(t *testing.T) {
+	m := &Manager{}
+	dir := filepath.Join(t.TempDir(), "articles")
+	article := testExportedArticle()
+
+	path, err := m.ExportArticle(context.Background(), article, dir, "{title}", ArticleMarkdown, "")
+	if err != nil {
+		t.Fatalf("ExportArticle() error = %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# Tom & Jerry: a <history>", "Cartoons/Weekly · Jane · 2025-10-01 12:00", "<https://example.com/posts/tom>", "chase"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("markdown is missing %q:\n%s", want, content)
+		}
+	}
+
+	// A second export doesn't overwrite the first
+	again, err := m.ExportArticle(context.Background(), article, dir, "{title}", ArticleMarkdown, "")
+	if err != nil {
+		t.Fatalf("ExportArticle() error = %v", err)
+	}
+	if filepath.Base(again) != "Tom & Jerry- a -history (2).md" {
+		t.Errorf("second export = %q, want a numbered name", filepath.Base(again))
+	}
+
+	path, err = m.ExportArticle(context.Background(), article, dir, "{title}", ArticleHTML, "")
+	if err != nil {
+		t.Fatalf("ExportArticle() error = %v", err)
+	}
+	content, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`<base href="https://example.com/posts/tom">`, "<h1>Tom &amp; Jerry: a &lt;history&gt;</h1>", `<a href="/chase">`} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("HTML is missing %q:\n%s", want, content)
+		}
+	}
+
+	if _, err := m.ExportArticle(context.Background(), article, dir, "{title}", ArticlePDF, ""); err == nil {
+		t.Error("expected an error exporting a PDF without a PDF command")
+	}
+}
+
+func TestExportArticlePDF(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test converter is a sh command")
+	}
+	m := &Manager{}
+	dir := t.TempDir()
+	article := testExportedArticle()
+
+	// The converter gets the HTML document
+	path, err := m.ExportArticle(context.Background(), article, dir, "{title}", ArticlePDF, "cp {input} {output}")
+	if err != nil {
+		t.Fatalf("ExportArticle() error = %v", err)
+	}
+	if filepath.Ext(path) != ".pdf" {
+		t.Errorf("path = %q, want a .pdf file", path)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(content), "<!DOCTYPE html>") {
+		t.Errorf("converter input = %q, want the HTML document", content)
+	}
+
+	// A failing converter leaves no file behind
+	_, err = m.ExportArticle(context.Background(), article, dir, "failed", ArticlePDF, "echo no converter >&2; exit 3")
+	if err == nil || !strings.Contains(err.Error(), "no converter") {
+		t.Errorf("error = %v, want the converter's output", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(dir, "failed.pdf")); !os.IsNotExist(statErr) {
+		t.Errorf("failed export left a file behind: %v", statErr)
+	}
+}
@@ -0,0 +1,142 @@
+package feeds
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// DefaultClusterWindow is the maximum distance between publish times of
+	// items that are considered to cover the same story
+	DefaultClusterWindow = 48 * time.Hour
+	// DefaultClusterThreshold is the minimum title similarity (0-1) for two
+	// items to be grouped together
+	DefaultClusterThreshold = 0.5
+)
+
+// ClusterEntry is the information needed to group an item into a story cluster
+type ClusterEntry struct {
+	ID        int64
+	FeedID    int64
+	Title     string
+	Published time.Time
+}
+
+// titleStopWords are ignored when comparing titles
+var titleStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "has": true, "have": true,
+	"in": true, "is": true, "it": true, "its": true, "of": true, "on": true,
+	"or": true, "that": true, "the": true, "this": true, "to": true, "was": true,
+	"with": true, "after": true, "over": true, "new": true, "says": true,
+}
+
+// titleTokens returns the set of significant lowercase words in a title
+func titleTokens(title string) map[string]bool {
+	tokens := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for _, word := range words {
+		if len(word) < 2 || titleStopWords[word] {
+			continue
+		}
+		tokens[word] = true
+	}
+	return tokens
+}
+
+// titleSimilarity returns the Jaccard similarity of two token sets
+func titleSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for token := range a {
+		if b[token] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// ClusterItems groups entries from different feeds that cover the same story.
+// Two entries belong to the same cluster when their titles are at least
+// threshold similar and they were published within window of each other.
+// Each returned cluster is a list of indices into entries in their original
+// order, and clusters are ordered by their first member. Entries that don't
+// match anything are returned as single-element clusters.
+//
+// Entries are sorted by publish time, so each one is only compared with the
+// entries published within window of it.
+func ClusterItems(entries []ClusterEntry, window time.Duration, threshold float64) [][]int {
+	tokens := make([]map[string]bool, len(entries))
+	for i, entry := range entries {
+		tokens[i] = titleTokens(entry.Title)
+	}
+
+	// Entries without a publish time are never grouped
+	byTime := make([]int, 0, len(entries))
+	for i, entry := range entries {
+		if !entry.Published.IsZero() {
+			byTime = append(byTime, i)
+		}
+	}
+	sort.SliceStable(byTime, func(a, b int) bool {
+		return entries[byTime[a]].Published.Before(entries[byTime[b]].Published)
+	})
+	position := make([]int, len(entries))
+	for pos, i := range byTime {
+		position[i] = pos
+	}
+
+	assigned := make([]bool, len(entries))
+	var clusters [][]int
+
+	for i := range entries {
+		if assigned[i] {
+			continue
+		}
+		assigned[i] = true
+		cluster := []int{i}
+		if entries[i].Published.IsZero() {
+			clusters = append(clusters, cluster)
+			continue
+		}
+
+		// Every entry before i is assigned already, so the candidates are
+		// the unassigned entries within window of i
+		var candidates []int
+		published := entries[i].Published
+		for pos := position[i] - 1; pos >= 0 && published.Sub(entries[byTime[pos]].Published) <= window; pos-- {
+			if j := byTime[pos]; !assigned[j] {
+				candidates = append(candidates, j)
+			}
+		}
+		for pos := position[i] + 1; pos < len(byTime) && entries[byTime[pos]].Published.Sub(published) <= window; pos++ {
+			if j := byTime[pos]; !assigned[j] {
+				candidates = append(candidates, j)
+			}
+		}
+		sort.Ints(candidates)
+
+		feedsInCluster := map[int64]bool{entries[i].FeedID: true}
+		for _, j := range candidates {
+			if feedsInCluster[entries[j].FeedID] {
+				continue
+			}
+			if titleSimilarity(tokens[i], tokens[j]) < threshold {
+				continue
+			}
+			assigned[j] = true
+			cluster = append(cluster, j)
+			feedsInCluster[entries[j].FeedID] = true
+		}
+
+		clusters = append(clusters, cluster)
+	}
+
+	return clusters
+}
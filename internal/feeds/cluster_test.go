@@ -0,0 +1,93 @@
+package feeds
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClusterItems(t *testing.T) {
+	base := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		entries  []ClusterEntry
+		expected [][]int
+	}{
+		{
+			name: "same story in different feeds",
+			entries: []ClusterEntry{
+				{ID: 1, FeedID: 1, Title: "Apple announces new M5 MacBook Pro", Published: base},
+				{ID: 2, FeedID: 2, Title: "Apple announces M5 MacBook Pro lineup", Published: base.Add(2 * time.Hour)},
+				{ID: 3, FeedID: 3, Title: "Rust 2.0 released", Published: base},
+			},
+			expected: [][]int{{0, 1}, {2}},
+		},
+		{
+			name: "same feed is never clustered",
+			entries: []ClusterEntry{
+				{ID: 1, FeedID: 1, Title: "Apple announces M5 MacBook Pro", Published: base},
+				{ID: 2, FeedID: 1, Title: "Apple announces M5 MacBook Pro", Published: base},
+			},
+			expected: [][]int{{0}, {1}},
+		},
+		{
+			name: "outside of time window",
+			entries: []ClusterEntry{
+				{ID: 1, FeedID: 1, Title: "Apple announces M5 MacBook Pro", Published: base},
+				{ID: 2, FeedID: 2, Title: "Apple announces M5 MacBook Pro", Published: base.Add(72 * time.Hour)},
+			},
+			expected: [][]int{{0}, {1}},
+		},
+		{
+			name: "missing publish date",
+			entries: []ClusterEntry{
+				{ID: 1, FeedID: 1, Title: "Apple announces M5 MacBook Pro"},
+				{ID: 2, FeedID: 2, Title: "Apple announces M5 MacBook Pro", Published: base},
+			},
+			expected: [][]int{{0}, {1}},
+		},
+		{
+			name: "newest first",
+			entries: []ClusterEntry{
+				{ID: 1, FeedID: 1, Title: "Rust 2.0 released", Published: base.Add(30 * time.Hour)},
+				{ID: 2, FeedID: 2, Title: "Apple announces M5 MacBook Pro", Published: base.Add(20 * time.Hour)},
+				{ID: 3, FeedID: 3, Title: "Rust 2.0 released today", Published: base.Add(10 * time.Hour)},
+				{ID: 4, FeedID: 4, Title: "Apple announces M5 MacBook Pro", Published: base},
+				{ID: 5, FeedID: 5, Title: "Rust 2.0 released", Published: base.Add(-20 * time.Hour)},
+			},
+			expected: [][]int{{0, 2}, {1, 3}, {4}},
+		},
+		{
+			name:     "empty",
+			entries:  nil,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClusterItems(tt.entries, DefaultClusterWindow, DefaultClusterThreshold)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ClusterItems() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func BenchmarkClusterItems(b *testing.B) {
+	base := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	entries := make([]ClusterEntry, 10000)
+	for i := range entries {
+		entries[i] = ClusterEntry{
+			ID:        int64(i),
+			FeedID:    int64(i % 50),
+			Title:     fmt.Sprintf("Story %d about topic %d", i/3, i%7),
+			Published: base.Add(-time.Duration(i) * 10 * time.Minute),
+		}
+	}
+	for b.Loop() {
+		ClusterItems(entries, DefaultClusterWindow, DefaultClusterThreshold)
+	}
+}
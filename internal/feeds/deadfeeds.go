@@ -0,0 +1,66 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/discovery"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// siteHomepages returns the pages a feed's site links its feed from, the
+// directory of the feed and the home page of its host
+func siteHomepages(feedURL string) ([]string, error) {
+	u, err := url.Parse(feedURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid feed URL %q", feedURL)
+	}
+	u.RawQuery = ""
+	u.Fragment = ""
+
+	var pages []string
+	if dir := path.Dir(u.Path); dir != "/" && dir != "." {
+		dirURL := *u
+		dirURL.Path = dir + "/"
+		pages = append(pages, dirURL.String())
+	}
+	u.Path = "/"
+	return append(pages, u.String()), nil
+}
+
+// RediscoverFeed looks for a feed on the site of a feed that stopped working,
+// for when it moved. It returns the first feed URL other than the dead one
+// linked from the feed's directory or the home page of its host.
+func (m *Manager) RediscoverFeed(feedURL string) (string, error) {
+	pages, err := siteHomepages(feedURL)
+	if err != nil {
+		return "", err
+	}
+
+	var lastErr error
+	for _, page := range pages {
+		found, err := discovery.DiscoverFeed(page)
+		if err != nil {
+			logging.DebugCategory(logging.CategoryDiscovery, "No feed on the site page", "page", page, "error", err)
+			lastErr = err
+			continue
+		}
+		if found != feedURL {
+			return found, nil
+		}
+	}
+	if lastErr != nil {
+		return "", fmt.Errorf("no other feed found on %s: %w", pages[len(pages)-1], lastErr)
+	}
+	return "", fmt.Errorf("no other feed found on %s", pages[len(pages)-1])
+}
+
+// ChangeFeedURL moves a feed to a new URL, keeping its items and read state
+func (m *Manager) ChangeFeedURL(feedID int64, feedURL string) error {
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
+	return m.queries.UpdateFeedURL(context.Background(), database.UpdateFeedURLParams{Url: feedURL, ID: feedID})
+}
@@ -0,0 +1,68 @@
+package feeds
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+func TestSiteHomepages(t *testing.T) {
+	pages, err := siteHomepages("https://example.com/blog/feed.xml?format=rss")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) != 2 || pages[0] != "https://example.com/blog/" || pages[1] != "https://example.com/" {
+		t.Errorf("siteHomepages() = %v", pages)
+	}
+	if pages, _ := siteHomepages("https://example.com/feed"); len(pages) != 1 {
+		t.Errorf("siteHomepages() of a feed at the root = %v", pages)
+	}
+}
+
+func TestRediscoverFeed(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><head><link rel="alternate" type="application/rss+xml" href="/new/feed.xml"></head></html>`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	db, queries := openTestDB(t)
+	ctx := context.Background()
+	oldURL := server.URL + "/old/feed.xml"
+	feed, err := queries.CreateFeed(ctx, database.CreateFeedParams{Url: oldURL, Title: "Moved"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	if _, err := queries.UpsertItem(ctx, database.UpsertItemParams{FeedID: feed.ID, Guid: "kept", Title: "Kept"}); err != nil {
+		t.Fatalf("UpsertItem() error = %v", err)
+	}
+
+	m := NewManager(db, queries)
+	found, err := m.RediscoverFeed(oldURL)
+	if err != nil {
+		t.Fatalf("RediscoverFeed() error = %v", err)
+	}
+	if want := server.URL + "/new/feed.xml"; found != want {
+		t.Fatalf("RediscoverFeed() = %q, want %q", found, want)
+	}
+
+	if err := m.ChangeFeedURL(feed.ID, found); err != nil {
+		t.Fatalf("ChangeFeedURL() error = %v", err)
+	}
+	moved, err := queries.GetFeed(ctx, feed.ID)
+	if err != nil || moved.Url != found {
+		t.Fatalf("GetFeed() = %q, %v, want the new URL", moved.Url, err)
+	}
+	if guids, _ := queries.GetItemGUIDs(ctx, feed.ID); len(guids) != 1 {
+		t.Errorf("items after moving = %v, want the old item kept", guids)
+	}
+}
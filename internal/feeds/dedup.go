@@ -0,0 +1,142 @@
+package feeds
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+
+	"github.com/jarv/newsgoat/internal/database"
+	"golang.org/x/net/html"
+)
+
+// minDedupContent is the length of normalized text content needs to be
+// hashed, short summaries like "Read more..." are shared by unrelated items
+const minDedupContent = 200
+
+// trackingParams are query parameters added for analytics that don't change
+// which article a link points to
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "mc_cid": true, "mc_eid": true, "ref_src": true,
+}
+
+// CanonicalLink returns the link of an item in a form that is the same
+// wherever the article is republished: https, lowercase host without www.,
+// no fragment, tracking parameters or trailing slash. Links that aren't
+// absolute URLs return an empty string.
+func CanonicalLink(link string) string {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+
+	query := u.Query()
+	for name := range query {
+		if strings.HasPrefix(strings.ToLower(name), "utm_") || trackingParams[strings.ToLower(name)] {
+			query.Del(name)
+		}
+	}
+
+	canonical := "https://" + host + strings.TrimSuffix(u.EscapedPath(), "/")
+	if len(query) > 0 {
+		// Encode sorts the parameters by name
+		canonical += "?" + query.Encode()
+	}
+	return canonical
+}
+
+// ContentHash returns a hash of the text of an item's content, ignoring
+// markup, case and whitespace that aggregators change when they republish
+// it. Content too short to identify an article returns an empty string.
+func ContentHash(content string) string {
+	text := contentText(content)
+	if len(text) < minDedupContent {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:16])
+}
+
+// contentText returns the lowercase words of the text in HTML content
+// separated by single spaces
+func contentText(content string) string {
+	var b strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(content))
+	skip := 0
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(strings.ToLower(b.String())), " ")
+		case html.StartTagToken:
+			if name, _ := tokenizer.TagName(); string(name) == "script" || string(name) == "style" {
+				skip++
+			}
+		case html.EndTagToken:
+			if name, _ := tokenizer.TagName(); (string(name) == "script" || string(name) == "style") && skip > 0 {
+				skip--
+			}
+		case html.TextToken:
+			if skip == 0 {
+				b.Write(tokenizer.Text())
+				b.WriteByte(' ')
+			}
+		}
+	}
+}
+
+// HideDuplicates removes the items that are copies of another item in the
+// list, with the same canonical link or content hash. The copy fetched
+// first is kept, the list stays in its order.
+func HideDuplicates(items []database.GetItemsWithReadStatusRow) []database.GetItemsWithReadStatusRow {
+	// The lowest item ID of each key is the first copy, keys are prefixed
+	// so a link can't match a hash
+	first := make(map[string]int64)
+	keys := func(item database.GetItemsWithReadStatusRow) []string {
+		var keys []string
+		if item.CanonicalLink != "" {
+			keys = append(keys, "link:"+item.CanonicalLink)
+		}
+		if item.ContentHash != "" {
+			keys = append(keys, "content:"+item.ContentHash)
+		}
+		return keys
+	}
+	for _, item := range items {
+		for _, key := range keys(item) {
+			if id, ok := first[key]; !ok || item.ID < id {
+				first[key] = item.ID
+			}
+		}
+	}
+
+	kept := make([]database.GetItemsWithReadStatusRow, 0, len(items))
+	for _, item := range items {
+		isCopy := false
+		for _, key := range keys(item) {
+			isCopy = isCopy || first[key] != item.ID
+		}
+		if !isCopy {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// GetItemCopies returns the items of other feeds than feedID with the same
+// canonical link or content hash, the same article republished there
+func (m *Manager) GetItemCopies(feedID int64, canonicalLink, contentHash string) ([]database.GetItemCopiesRow, error) {
+	if canonicalLink == "" && contentHash == "" {
+		return nil, nil
+	}
+	return m.queries.GetItemCopies(context.Background(), database.GetItemCopiesParams{
+		FeedID:        feedID,
+		CanonicalLink: canonicalLink,
+		ContentHash:   contentHash,
+	})
+}
@@ -0,0 +1,114 @@
+package feeds
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+func TestCanonicalLink(t *testing.T) {
+	tests := []struct {
+		link     string
+		expected string
+	}{
+		{"https://example.com/post/1", "https://example.com/post/1"},
+		{"http://www.Example.com/post/1/", "https://example.com/post/1"},
+		{"https://example.com/post/1?utm_source=rss&utm_medium=feed#comments", "https://example.com/post/1"},
+		{"https://example.com/post?b=2&a=1&fbclid=x", "https://example.com/post?a=1&b=2"},
+		{"https://example.com:443/post", "https://example.com/post"},
+		{"https://example.com:8080/post", "https://example.com:8080/post"},
+		{"/post/1", ""},
+		{"mailto:someone@example.com", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := CanonicalLink(tt.link); got != tt.expected {
+			t.Errorf("CanonicalLink(%q) = %q, want %q", tt.link, got, tt.expected)
+		}
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	text := strings.Repeat("The same article republished by a planet aggregator. ", 5)
+
+	original := ContentHash("<p>" + text + "</p>")
+	if original == "" {
+		t.Fatal("ContentHash() of a long article is empty")
+	}
+	republished := "<div class=\"planet\">\n  <P>" + strings.ToUpper(text) + "</P><script>track()</script></div>"
+	if got := ContentHash(republished); got != original {
+		t.Errorf("ContentHash() of the republished article = %q, want %q", got, original)
+	}
+	if got := ContentHash("<p>" + text + "And an update.</p>"); got == original {
+		t.Error("ContentHash() of a changed article should differ")
+	}
+	if got := ContentHash("<p>Read more...</p>"); got != "" {
+		t.Errorf("ContentHash() of a short summary = %q, want empty", got)
+	}
+}
+
+func TestHideDuplicates(t *testing.T) {
+	items := []database.GetItemsWithReadStatusRow{
+		{ID: 5, Title: "Copy on a planet", CanonicalLink: "https://example.com/post"},
+		{ID: 2, Title: "Original", CanonicalLink: "https://example.com/post", ContentHash: "abc"},
+		{ID: 7, Title: "Copy with another link", CanonicalLink: "https://planet.example.org/1", ContentHash: "abc"},
+		{ID: 3, Title: "Unrelated"},
+		{ID: 4, Title: "Unrelated too"},
+	}
+
+	var titles []string
+	for _, item := range HideDuplicates(items) {
+		titles = append(titles, item.Title)
+	}
+	if got, want := strings.Join(titles, ", "), "Original, Unrelated, Unrelated too"; got != want {
+		t.Errorf("HideDuplicates() = %s, want %s", got, want)
+	}
+}
+
+func TestGetItemCopies(t *testing.T) {
+	db, queries := openTestDB(t)
+	ctx := context.Background()
+	m := NewManager(db, queries)
+
+	var feedIDs []int64
+	for _, title := range []string{"Blog", "Planet", "Other"} {
+		feed, err := queries.CreateFeed(ctx, database.CreateFeedParams{Url: "https://" + title + ".example.com/feed", Title: title})
+		if err != nil {
+			t.Fatalf("CreateFeed() error = %v", err)
+		}
+		feedIDs = append(feedIDs, feed.ID)
+	}
+	upsert := func(feedID int64, guid, link, hash string) {
+		t.Helper()
+		if _, err := queries.UpsertItem(ctx, database.UpsertItemParams{
+			FeedID: feedID, Guid: guid, Link: link, CanonicalLink: link, ContentHash: hash,
+		}); err != nil {
+			t.Fatalf("UpsertItem() error = %v", err)
+		}
+	}
+	upsert(feedIDs[0], "1", "https://example.com/post", "abc")
+	upsert(feedIDs[1], "planet-1", "https://example.com/post", "")
+	upsert(feedIDs[2], "other-1", "https://other.example.com/post", "abc")
+	upsert(feedIDs[2], "other-2", "https://other.example.com/unrelated", "")
+
+	copies, err := m.GetItemCopies(feedIDs[0], "https://example.com/post", "abc")
+	if err != nil {
+		t.Fatalf("GetItemCopies() error = %v", err)
+	}
+	var titles []string
+	for _, c := range copies {
+		titles = append(titles, c.FeedTitle)
+	}
+	if got, want := strings.Join(titles, ", "), "Other, Planet"; got != want {
+		t.Errorf("GetItemCopies() feeds = %s, want %s", got, want)
+	}
+
+	// Items without keys have no copies, empty keys must not match each other
+	copies, err = m.GetItemCopies(feedIDs[2], "https://other.example.com/unrelated", "")
+	if err != nil || len(copies) != 0 {
+		t.Errorf("GetItemCopies() = %v, %v, want no copies", copies, err)
+	}
+}
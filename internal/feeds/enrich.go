@@ -0,0 +1,416 @@
+package feeds
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/discovery"
+	"github.com/jarv/newsgoat/internal/logging"
+	"github.com/jarv/newsgoat/internal/version"
+)
+
+// Pull request states stored on enriched items
+const (
+	PRStateOpen   = "open"
+	PRStateDraft  = "draft"
+	PRStateMerged = "merged"
+	PRStateClosed = "closed"
+)
+
+// CI states stored on enriched items
+const (
+	CIStateSuccess = "success"
+	CIStateFailure = "failure"
+	CIStatePending = "pending"
+)
+
+const (
+	// maxEnrichmentsPerTask caps the items looked up by a single enrichment task
+	maxEnrichmentsPerTask = 10
+	// enrichmentRefreshAge is how long an open pull request or a running
+	// pipeline is trusted before it's looked up again
+	enrichmentRefreshAge = 15 * time.Minute
+	// rateLimitReserve is the number of API requests left for other tools
+	// before enrichment stops until the rate limit resets
+	rateLimitReserve = 10
+	// maxAPIResponseSize is the largest API response that will be read
+	maxAPIResponseSize = 2 * 1024 * 1024
+)
+
+// API endpoints, variables so tests can point them at a local server
+var (
+	githubAPIURL = "https://api.github.com"
+	gitlabAPIURL = "https://gitlab.com/api/v4"
+)
+
+// ErrRateLimited is returned while the API of a forge is rate limited
+var ErrRateLimited = errors.New("API rate limit reached")
+
+var errAPINotFound = errors.New("not found")
+
+var (
+	githubCommitPath = regexp.MustCompile(`^/([^/]+/[^/]+)/commit/([0-9a-fA-F]{7,40})/?$`)
+	gitlabCommitPath = regexp.MustCompile(`^/(.+?)/-/commit/([0-9a-fA-F]{7,40})/?$`)
+)
+
+// rateLimitKey identifies a rate limit, limits are counted per token
+type rateLimitKey struct {
+	urlType discovery.URLType
+	token   string
+}
+
+// CommitRef identifies a commit on GitHub or GitLab
+type CommitRef struct {
+	Type    discovery.URLType
+	Project string // owner/repo on GitHub, the full project path on GitLab
+	SHA     string
+}
+
+// Enrichment is the pull request and CI state of a commit
+type Enrichment struct {
+	PRNumber int64
+	PRState  string
+	CIState  string
+}
+
+// ParseCommitLink extracts the project and commit from a GitHub or GitLab
+// commit link
+func ParseCommitLink(link string) (CommitRef, bool) {
+	urlType := discovery.GetURLType(link)
+	pattern := githubCommitPath
+	switch urlType {
+	case discovery.URLTypeGitHub:
+	case discovery.URLTypeGitLab:
+		pattern = gitlabCommitPath
+	default:
+		return CommitRef{}, false
+	}
+
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return CommitRef{}, false
+	}
+	match := pattern.FindStringSubmatch(parsed.Path)
+	if match == nil {
+		return CommitRef{}, false
+	}
+	return CommitRef{Type: urlType, Project: match[1], SHA: match[2]}, true
+}
+
+// SetFeedEnrich enables or disables pull request and CI lookups for a feed
+func (m *Manager) SetFeedEnrich(feedID int64, enrich bool) error {
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
+	return m.queries.SetFeedEnrich(context.Background(), database.SetFeedEnrichParams{
+		Enrich: enrich,
+		ID:     feedID,
+	})
+}
+
+// FeedEnrichEnabled reports whether the items of a feed get pull request and
+// CI lookups
+func (m *Manager) FeedEnrichEnabled(feedID int64) bool {
+	feed, err := m.queries.GetFeed(context.Background(), feedID)
+	if err != nil {
+		return false
+	}
+	urlType := discovery.GetURLType(feed.Url)
+	return feed.Enrich && (urlType == discovery.URLTypeGitHub || urlType == discovery.URLTypeGitLab)
+}
+
+// EnrichFeedItems looks up the pull request and CI state of the newest items
+// of a commit feed that haven't been looked up yet or are still in progress.
+// It stops early without an error when the API is rate limited, the remaining
+// items are picked up by a later task.
+func (m *Manager) EnrichFeedItems(ctx context.Context, feedID int64) (int, error) {
+	feed, err := m.queries.GetFeed(ctx, feedID)
+	if err != nil {
+		return 0, err
+	}
+	token := m.tokenForURL(feed.Url)
+
+	items, err := m.queries.GetItemsToEnrich(ctx, database.GetItemsToEnrichParams{
+		FeedID:     feedID,
+		EnrichedAt: sql.NullTime{Time: time.Now().UTC().Add(-enrichmentRefreshAge), Valid: true},
+		Limit:      maxEnrichmentsPerTask,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	enriched := 0
+	for _, item := range items {
+		var enrichment Enrichment
+		if ref, ok := ParseCommitLink(item.Link); ok {
+			enrichment, err = m.fetchEnrichment(ctx, ref, token)
+			if errors.Is(err, ErrRateLimited) {
+				logging.DebugCategory(logging.CategoryHTTP, "Enrichment paused", "feedID", feedID, "error", err)
+				return enriched, nil
+			}
+			if err != nil && !errors.Is(err, errAPINotFound) {
+				logging.Warn("Failed to enrich item", "url", item.Link, "error", err)
+				continue
+			}
+		}
+
+		// Items that aren't commits or can't be found are stored without a
+		// state so they aren't looked up again
+		m.writeMutex.Lock()
+		err = m.queries.UpdateItemEnrichment(ctx, database.UpdateItemEnrichmentParams{
+			PrNumber:   enrichment.PRNumber,
+			PrState:    enrichment.PRState,
+			CiState:    enrichment.CIState,
+			EnrichedAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+			ID:         item.ID,
+		})
+		m.writeMutex.Unlock()
+		if err != nil {
+			return enriched, err
+		}
+		enriched++
+	}
+
+	return enriched, nil
+}
+
+func (m *Manager) fetchEnrichment(ctx context.Context, ref CommitRef, token string) (Enrichment, error) {
+	if ref.Type == discovery.URLTypeGitLab {
+		return m.fetchGitLabEnrichment(ctx, ref, token)
+	}
+	return m.fetchGitHubEnrichment(ctx, ref, token)
+}
+
+func (m *Manager) fetchGitHubEnrichment(ctx context.Context, ref CommitRef, token string) (Enrichment, error) {
+	var enrichment Enrichment
+	key := rateLimitKey{ref.Type, token}
+	commitURL := githubAPIURL + "/repos/" + ref.Project + "/commits/" + ref.SHA
+
+	var pulls []struct {
+		Number   int64      `json:"number"`
+		State    string     `json:"state"`
+		Draft    bool       `json:"draft"`
+		MergedAt *time.Time `json:"merged_at"`
+	}
+	if err := m.apiGet(ctx, key, commitURL+"/pulls", &pulls); err != nil {
+		return enrichment, err
+	}
+	if len(pulls) > 0 {
+		pull := pulls[0]
+		enrichment.PRNumber = pull.Number
+		switch {
+		case pull.MergedAt != nil:
+			enrichment.PRState = PRStateMerged
+		case pull.State == "open" && pull.Draft:
+			enrichment.PRState = PRStateDraft
+		case pull.State == "open":
+			enrichment.PRState = PRStateOpen
+		default:
+			enrichment.PRState = PRStateClosed
+		}
+	}
+
+	// Commit statuses come from external CI services, check runs from GitHub Actions
+	var status struct {
+		State      string `json:"state"`
+		TotalCount int    `json:"total_count"`
+	}
+	if err := m.apiGet(ctx, key, commitURL+"/status", &status); err != nil {
+		return enrichment, err
+	}
+	if status.TotalCount > 0 {
+		enrichment.CIState = githubStatusState(status.State)
+		return enrichment, nil
+	}
+
+	var checks struct {
+		CheckRuns []struct {
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+		} `json:"check_runs"`
+	}
+	if err := m.apiGet(ctx, key, commitURL+"/check-runs", &checks); err != nil {
+		return enrichment, err
+	}
+	for _, run := range checks.CheckRuns {
+		if run.Status != "completed" {
+			enrichment.CIState = CIStatePending
+			break
+		}
+		switch run.Conclusion {
+		case "failure", "timed_out", "cancelled", "action_required":
+			enrichment.CIState = CIStateFailure
+		case "success", "neutral", "skipped":
+			if enrichment.CIState == "" {
+				enrichment.CIState = CIStateSuccess
+			}
+		}
+	}
+
+	return enrichment, nil
+}
+
+func githubStatusState(state string) string {
+	switch state {
+	case "success":
+		return CIStateSuccess
+	case "failure", "error":
+		return CIStateFailure
+	case "pending":
+		return CIStatePending
+	}
+	return ""
+}
+
+func (m *Manager) fetchGitLabEnrichment(ctx context.Context, ref CommitRef, token string) (Enrichment, error) {
+	var enrichment Enrichment
+	key := rateLimitKey{ref.Type, token}
+	commitURL := gitlabAPIURL + "/projects/" + url.PathEscape(ref.Project) + "/repository/commits/" + ref.SHA
+
+	var mergeRequests []struct {
+		IID   int64  `json:"iid"`
+		State string `json:"state"`
+		Draft bool   `json:"draft"`
+	}
+	if err := m.apiGet(ctx, key, commitURL+"/merge_requests", &mergeRequests); err != nil {
+		return enrichment, err
+	}
+	if len(mergeRequests) > 0 {
+		mr := mergeRequests[0]
+		enrichment.PRNumber = mr.IID
+		switch {
+		case mr.State == "merged":
+			enrichment.PRState = PRStateMerged
+		case mr.State == "opened" && mr.Draft:
+			enrichment.PRState = PRStateDraft
+		case mr.State == "opened":
+			enrichment.PRState = PRStateOpen
+		default:
+			enrichment.PRState = PRStateClosed
+		}
+	}
+
+	var commit struct {
+		LastPipeline *struct {
+			Status string `json:"status"`
+		} `json:"last_pipeline"`
+	}
+	if err := m.apiGet(ctx, key, commitURL, &commit); err != nil {
+		return enrichment, err
+	}
+	if commit.LastPipeline != nil {
+		switch commit.LastPipeline.Status {
+		case "success":
+			enrichment.CIState = CIStateSuccess
+		case "failed":
+			enrichment.CIState = CIStateFailure
+		case "created", "waiting_for_resource", "preparing", "pending", "running", "scheduled":
+			enrichment.CIState = CIStatePending
+		}
+	}
+
+	return enrichment, nil
+}
+
+// apiGet fetches a GitHub or GitLab API endpoint and decodes the JSON response
+func (m *Manager) apiGet(ctx context.Context, key rateLimitKey, apiURL string, v interface{}) error {
+	if until := m.rateLimitedUntil(key); !until.IsZero() {
+		return fmt.Errorf("%w for %s until %s", ErrRateLimited, key.urlType, until.Format(time.Kitchen))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", version.GetUserAgent())
+	switch key.urlType {
+	case discovery.URLTypeGitHub:
+		req.Header.Set("Accept", "application/vnd.github+json")
+		if key.token != "" {
+			req.Header.Set("Authorization", "Bearer "+key.token)
+		}
+	case discovery.URLTypeGitLab:
+		if key.token != "" {
+			req.Header.Set("PRIVATE-TOKEN", key.token)
+		}
+	}
+
+	client := &http.Client{Timeout: FeedTimeout, Transport: m.globalTransport()}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	limited := m.trackRateLimit(key, resp)
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errAPINotFound
+	case limited && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests):
+		return fmt.Errorf("%w for %s", ErrRateLimited, key.urlType)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	return json.NewDecoder(io.LimitReader(resp.Body, maxAPIResponseSize)).Decode(v)
+}
+
+// trackRateLimit pauses API requests to a forge when its rate limit is
+// (nearly) used up and reports whether requests are paused
+func (m *Manager) trackRateLimit(key rateLimitKey, resp *http.Response) bool {
+	remaining, err := strconv.Atoi(rateLimitHeader(resp.Header, "Remaining"))
+	hasRemaining := err == nil
+	exhausted := resp.StatusCode == http.StatusTooManyRequests ||
+		(hasRemaining && remaining <= rateLimitReserve) ||
+		(resp.StatusCode == http.StatusForbidden && resp.Header.Get("Retry-After") != "")
+	if !exhausted {
+		return false
+	}
+
+	until := time.Now().Add(time.Minute)
+	if reset, err := strconv.ParseInt(rateLimitHeader(resp.Header, "Reset"), 10, 64); err == nil {
+		until = time.Unix(reset, 0)
+	} else if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		until = time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+
+	m.rateLimitMutex.Lock()
+	if m.rateLimits == nil {
+		m.rateLimits = make(map[rateLimitKey]time.Time)
+	}
+	m.rateLimits[key] = until
+	m.rateLimitMutex.Unlock()
+
+	logging.DebugCategory(logging.CategoryHTTP, "API rate limit reached, pausing enrichment", "type", key.urlType, "until", until)
+	return true
+}
+
+// rateLimitHeader reads a GitHub (X-RateLimit-*) or GitLab (RateLimit-*) rate limit header
+func rateLimitHeader(header http.Header, name string) string {
+	if value := header.Get("X-RateLimit-" + name); value != "" {
+		return value
+	}
+	return header.Get("RateLimit-" + name)
+}
+
+// rateLimitedUntil returns when requests with a token may resume, zero if they aren't paused
+func (m *Manager) rateLimitedUntil(key rateLimitKey) time.Time {
+	m.rateLimitMutex.Lock()
+	defer m.rateLimitMutex.Unlock()
+	until := m.rateLimits[key]
+	if time.Now().After(until) {
+		return time.Time{}
+	}
+	return until
+}
@@ -0,0 +1,128 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/discovery"
+)
+
+func TestParseCommitLink(t *testing.T) {
+	tests := []struct {
+		link     string
+		expected CommitRef
+		ok       bool
+	}{
+		{
+			"https://github.com/jarv/newsgoat/commit/0a1b2c3d4e5f",
+			CommitRef{discovery.URLTypeGitHub, "jarv/newsgoat", "0a1b2c3d4e5f"},
+			true,
+		},
+		{
+			"https://gitlab.com/gitlab-org/charts/gitlab/-/commit/abcdef1234567",
+			CommitRef{discovery.URLTypeGitLab, "gitlab-org/charts/gitlab", "abcdef1234567"},
+			true,
+		},
+		{"https://github.com/jarv/newsgoat/releases/tag/v1.0.0", CommitRef{}, false},
+		{"https://gitlab.com/gitlab-org/gitlab/-/merge_requests/12", CommitRef{}, false},
+		{"https://example.com/jarv/newsgoat/commit/0a1b2c3d4e5f", CommitRef{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseCommitLink(tt.link)
+		if ok != tt.ok || got != tt.expected {
+			t.Errorf("ParseCommitLink(%q) = %+v, %v, want %+v, %v", tt.link, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestFetchGitHubEnrichment(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/jarv/newsgoat/commits/abc1234/pulls", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q, want the feed token", got)
+		}
+		_, _ = fmt.Fprint(w, `[{"number": 12, "state": "closed", "draft": false, "merged_at": "2025-10-01T12:00:00Z"}]`)
+	})
+	mux.HandleFunc("/repos/jarv/newsgoat/commits/abc1234/status", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"state": "pending", "total_count": 0}`)
+	})
+	mux.HandleFunc("/repos/jarv/newsgoat/commits/abc1234/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"check_runs": [{"status": "completed", "conclusion": "success"}, {"status": "completed", "conclusion": "failure"}]}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	githubAPIURL = server.URL
+	defer func() { githubAPIURL = "https://api.github.com" }()
+
+	m := &Manager{}
+	got, err := m.fetchEnrichment(context.Background(), CommitRef{discovery.URLTypeGitHub, "jarv/newsgoat", "abc1234"}, "secret")
+	if err != nil {
+		t.Fatalf("fetchEnrichment() error = %v", err)
+	}
+	want := Enrichment{PRNumber: 12, PRState: PRStateMerged, CIState: CIStateFailure}
+	if got != want {
+		t.Errorf("fetchEnrichment() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFetchGitLabEnrichment(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/group%2Fproject/repository/commits/abc1234/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"iid": 7, "state": "opened", "draft": true}]`)
+	})
+	mux.HandleFunc("/projects/group%2Fproject/repository/commits/abc1234", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"last_pipeline": {"status": "running"}}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	gitlabAPIURL = server.URL
+	defer func() { gitlabAPIURL = "https://gitlab.com/api/v4" }()
+
+	m := &Manager{}
+	got, err := m.fetchEnrichment(context.Background(), CommitRef{discovery.URLTypeGitLab, "group/project", "abc1234"}, "")
+	if err != nil {
+		t.Fatalf("fetchEnrichment() error = %v", err)
+	}
+	want := Enrichment{PRNumber: 7, PRState: PRStateDraft, CIState: CIStatePending}
+	if got != want {
+		t.Errorf("fetchEnrichment() = %+v, want %+v", got, want)
+	}
+}
+
+func TestEnrichmentRateLimit(t *testing.T) {
+	requests := 0
+	reset := time.Now().Add(time.Hour).Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Remaining", "3")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(reset))
+		_, _ = fmt.Fprint(w, `[]`)
+	}))
+	defer server.Close()
+	githubAPIURL = server.URL
+	defer func() { githubAPIURL = "https://api.github.com" }()
+
+	m := &Manager{}
+	ref := CommitRef{discovery.URLTypeGitHub, "jarv/newsgoat", "abc1234"}
+
+	// The first response uses up the reserve, the remaining lookups wait for the reset
+	_, err := m.fetchEnrichment(context.Background(), ref, "work")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("fetchEnrichment() error = %v, want ErrRateLimited", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request before pausing, got %d", requests)
+	}
+	if until := m.rateLimitedUntil(rateLimitKey{discovery.URLTypeGitHub, "work"}); until.Unix() != reset {
+		t.Errorf("rateLimitedUntil() = %v, want the reset time", until)
+	}
+	if !m.rateLimitedUntil(rateLimitKey{discovery.URLTypeGitHub, "personal"}).IsZero() {
+		t.Error("requests with another token shouldn't be paused")
+	}
+}
@@ -0,0 +1,234 @@
+package feeds
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/version"
+)
+
+// Reading log sources
+const (
+	ReadingLogStarred = "starred"
+	ReadingLogRead    = "read"
+)
+
+const (
+	// DefaultReadingLogLimit is the number of items written to a reading log
+	DefaultReadingLogLimit = 50
+	// gistAPIURL is the GitHub endpoint a reading log gist is updated through
+	gistAPIURL = "https://api.github.com/gists/"
+)
+
+// ReadingLog is a shareable list of starred or recently read items
+type ReadingLog struct {
+	Source      string            `json:"source"`
+	GeneratedAt time.Time         `json:"generated_at"`
+	Items       []ReadingLogEntry `json:"items"`
+}
+
+// ReadingLogEntry is a single item in a reading log
+type ReadingLogEntry struct {
+	Title     string     `json:"title"`
+	Link      string     `json:"link"`
+	Feed      string     `json:"feed"`
+	Published *time.Time `json:"published,omitempty"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+}
+
+// GetReadingLog returns the most recent starred or read items
+func (m *Manager) GetReadingLog(source string, limit int) (ReadingLog, error) {
+	if limit <= 0 {
+		limit = DefaultReadingLogLimit
+	}
+
+	log := ReadingLog{Source: source, GeneratedAt: time.Now().UTC()}
+
+	switch source {
+	case ReadingLogStarred:
+		rows, err := m.queries.GetReadingLogStarred(context.Background(), int64(limit))
+		if err != nil {
+			return log, err
+		}
+		for _, row := range rows {
+			log.Items = append(log.Items, newReadingLogEntry(row.Title, row.Link, row.FeedTitle, row.Published.Time, row.ReadAt.Time))
+		}
+	case ReadingLogRead:
+		rows, err := m.queries.GetReadingLogRecent(context.Background(), int64(limit))
+		if err != nil {
+			return log, err
+		}
+		for _, row := range rows {
+			log.Items = append(log.Items, newReadingLogEntry(row.Title, row.Link, row.FeedTitle, row.Published.Time, row.ReadAt.Time))
+		}
+	default:
+		return log, fmt.Errorf("unknown reading log source %q, expected %q or %q", source, ReadingLogStarred, ReadingLogRead)
+	}
+
+	return log, nil
+}
+
+func newReadingLogEntry(title, link, feed string, published, readAt time.Time) ReadingLogEntry {
+	entry := ReadingLogEntry{Title: title, Link: link, Feed: feed}
+	if !published.IsZero() {
+		entry.Published = &published
+	}
+	if !readAt.IsZero() {
+		entry.ReadAt = &readAt
+	}
+	return entry
+}
+
+var readingLogTemplate = template.Must(template.New("reading-log").Funcs(template.FuncMap{
+	"date": func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format("2006-01-02")
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="generator" content="{{.Generator}}">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 46rem; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; }
+li { margin-bottom: 0.75rem; }
+.meta { color: #666; font-size: 0.875rem; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">Updated {{.Log.GeneratedAt.Format "2006-01-02 15:04 UTC"}}</p>
+<ul>
+{{- range .Log.Items}}
+<li>{{if .Link}}<a href="{{.Link}}">{{.Title}}</a>{{else}}{{.Title}}{{end}}<br><span class="meta">{{.Feed}}{{with date .Published}} · {{.}}{{end}}</span></li>
+{{- end}}
+</ul>
+</body>
+</html>
+`))
+
+// WriteReadingLogHTML writes the reading log as a standalone HTML page
+func WriteReadingLogHTML(w io.Writer, log ReadingLog) error {
+	title := "What I'm reading"
+	if log.Source == ReadingLogStarred {
+		title = "Starred reading"
+	}
+	return readingLogTemplate.Execute(w, struct {
+		Title     string
+		Generator string
+		Log       ReadingLog
+	}{title, version.GetUserAgent(), log})
+}
+
+// WriteReadingLogJSON writes the reading log as indented JSON
+func WriteReadingLogJSON(w io.Writer, log ReadingLog) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
+
+// RenderReadingLog renders the reading log as JSON when name ends in .json and
+// as HTML otherwise
+func RenderReadingLog(name string, log ReadingLog) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		err = WriteReadingLogJSON(&buf, log)
+	} else {
+		err = WriteReadingLogHTML(&buf, log)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteReadingLogFile renders the reading log and replaces the file at path
+func WriteReadingLogFile(path string, log ReadingLog) error {
+	content, err := RenderReadingLog(path, log)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see a partial page
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".newsgoat-reading-*")
+	if err != nil {
+		return fmt.Errorf("failed to create reading log: %w", err)
+	}
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write reading log: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write reading log: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to replace reading log: %w", err)
+	}
+	return nil
+}
+
+// PublishReadingLogGist replaces a file in an existing GitHub gist with the
+// rendered reading log
+func PublishReadingLogGist(ctx context.Context, gistID, filename, token string, log ReadingLog) error {
+	if token == "" {
+		return fmt.Errorf("no GitHub token set for publishing the reading log")
+	}
+
+	content, err := RenderReadingLog(filename, log)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"files": map[string]interface{}{
+			filename: map[string]string{"content": string(content)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, gistAPIURL+gistID, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", version.GetUserAgent())
+
+	client := &http.Client{Timeout: FeedTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to update gist: HTTP %d: %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	return nil
+}
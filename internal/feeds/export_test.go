@@ -0,0 +1,86 @@
+package feeds
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testReadingLog() ReadingLog {
+	published := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	return ReadingLog{
+		Source:      ReadingLogStarred,
+		GeneratedAt: time.Date(2025, 10, 2, 8, 30, 0, 0, time.UTC),
+		Items: []ReadingLogEntry{
+			{Title: "Tom & Jerry <3", Link: "https://example.com/a", Feed: "Example", Published: &published},
+			{Title: "No link", Feed: "Other"},
+		},
+	}
+}
+
+func TestWriteReadingLogHTML(t *testing.T) {
+	content, err := RenderReadingLog("reading.html", testReadingLog())
+	if err != nil {
+		t.Fatalf("RenderReadingLog() error = %v", err)
+	}
+
+	html := string(content)
+	for _, want := range []string{
+		"<title>Starred reading</title>",
+		`<a href="https://example.com/a">Tom &amp; Jerry &lt;3</a>`,
+		"Example · 2025-10-01",
+		"Updated 2025-10-02 08:30 UTC",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML reading log missing %q", want)
+		}
+	}
+}
+
+func TestWriteReadingLogJSON(t *testing.T) {
+	content, err := RenderReadingLog("reading.JSON", testReadingLog())
+	if err != nil {
+		t.Fatalf("RenderReadingLog() error = %v", err)
+	}
+
+	var got ReadingLog
+	if err := json.Unmarshal(content, &got); err != nil {
+		t.Fatalf("reading log is not valid JSON: %v", err)
+	}
+	if got.Source != ReadingLogStarred || len(got.Items) != 2 {
+		t.Fatalf("unexpected reading log: %+v", got)
+	}
+	if got.Items[0].Title != "Tom & Jerry <3" || got.Items[1].Published != nil {
+		t.Errorf("unexpected items: %+v", got.Items)
+	}
+}
+
+func TestWriteReadingLogFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reading.json")
+	if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteReadingLogFile(path, testReadingLog()); err != nil {
+		t.Fatalf("WriteReadingLogFile() error = %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !json.Valid(content) {
+		t.Errorf("reading log file was not replaced with JSON: %q", content)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the reading log in the directory, found %d entries", len(entries))
+	}
+}
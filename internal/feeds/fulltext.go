@@ -0,0 +1,254 @@
+package feeds
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/logging"
+	"github.com/jarv/newsgoat/internal/version"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	// maxArticleSize limits how much of a linked page is read
+	maxArticleSize = 5 << 20
+	// minArticleTextLength is the least amount of text accepted as an article
+	minArticleTextLength = 200
+	// maxFullTextFetchesPerRefresh bounds the article downloads done by a single refresh
+	maxFullTextFetchesPerRefresh = 20
+)
+
+// ignoredArticleElements are dropped before looking for the article body
+var ignoredArticleElements = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Nav:      true,
+	atom.Header:   true,
+	atom.Footer:   true,
+	atom.Aside:    true,
+	atom.Form:     true,
+	atom.Iframe:   true,
+	atom.Button:   true,
+	atom.Svg:      true,
+}
+
+// ExtractArticle returns the HTML of the main content of a web page.
+// An <article> or <main> element is used when the page has one, otherwise the
+// element holding the most paragraph text wins. Relative links and images are
+// resolved against baseURL.
+func ExtractArticle(r io.Reader, baseURL *url.URL) (string, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse article: %w", err)
+	}
+
+	removeIgnoredElements(doc)
+
+	candidate := findElement(doc, atom.Article)
+	if candidate == nil || len(textContent(candidate)) < minArticleTextLength {
+		candidate = findElement(doc, atom.Main)
+	}
+	if candidate == nil || len(textContent(candidate)) < minArticleTextLength {
+		candidate = densestParagraphParent(doc)
+	}
+	if candidate == nil || len(textContent(candidate)) < minArticleTextLength {
+		return "", fmt.Errorf("no article content found")
+	}
+
+	if baseURL != nil {
+		resolveLinks(candidate, baseURL)
+	}
+
+	var buf bytes.Buffer
+	for c := candidate.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&buf, c); err != nil {
+			return "", fmt.Errorf("failed to render article: %w", err)
+		}
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func removeIgnoredElements(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.CommentNode || (c.Type == html.ElementNode && ignoredArticleElements[c.DataAtom]) {
+			n.RemoveChild(c)
+		} else {
+			removeIgnoredElements(c)
+		}
+		c = next
+	}
+}
+
+func findElement(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// densestParagraphParent returns the element whose direct <p> children hold the most text
+func densestParagraphParent(doc *html.Node) *html.Node {
+	scores := make(map[*html.Node]int)
+	var best *html.Node
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.DataAtom == atom.P && n.Parent != nil {
+			scores[n.Parent] += len(textContent(n))
+			if best == nil || scores[n.Parent] > scores[best] {
+				best = n.Parent
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	return best
+}
+
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(strings.TrimSpace(n.Data))
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+func resolveLinks(n *html.Node, baseURL *url.URL) {
+	if n.Type == html.ElementNode {
+		for i, attr := range n.Attr {
+			if attr.Key != "href" && attr.Key != "src" {
+				continue
+			}
+			if ref, err := url.Parse(attr.Val); err == nil {
+				n.Attr[i].Val = baseURL.ResolveReference(ref).String()
+			}
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		resolveLinks(c, baseURL)
+	}
+}
+
+// SetFeedFullText enables or disables full-text fetching for a feed
+func (m *Manager) SetFeedFullText(feedID int64, fullText bool) error {
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
+	return m.queries.SetFeedFullText(context.Background(), database.SetFeedFullTextParams{
+		FullText: fullText,
+		ID:       feedID,
+	})
+}
+
+// FetchFullContent downloads the page an item links to, extracts the article
+// and stores it as the item's full content
+func (m *Manager) FetchFullContent(itemID int64) (string, error) {
+	item, err := m.queries.GetItem(context.Background(), itemID)
+	if err != nil {
+		return "", err
+	}
+
+	return m.fetchFullContent(context.Background(), item)
+}
+
+func (m *Manager) fetchFullContent(ctx context.Context, item database.Item) (string, error) {
+	if item.Link == "" {
+		return "", fmt.Errorf("item has no link")
+	}
+
+	baseURL, err := url.Parse(item.Link)
+	if err != nil {
+		return "", fmt.Errorf("invalid item link: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, FeedTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", item.Link, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", version.GetUserAgent())
+
+	client := &http.Client{Timeout: FeedTimeout, Transport: m.globalTransport()}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	// Resolve relative links against the final URL after redirects
+	if resp.Request != nil && resp.Request.URL != nil {
+		baseURL = resp.Request.URL
+	}
+
+	content, err := ExtractArticle(io.LimitReader(resp.Body, maxArticleSize), baseURL)
+	if err != nil {
+		return "", err
+	}
+
+	m.writeMutex.Lock()
+	err = m.queries.UpdateItemFullContent(context.Background(), database.UpdateItemFullContentParams{
+		FullContent: content,
+		ID:          item.ID,
+	})
+	m.writeMutex.Unlock()
+	if err != nil {
+		return "", err
+	}
+
+	return content, nil
+}
+
+// fetchMissingFullContent downloads the articles of items that have no full
+// content yet, a failed download is retried on the next refresh
+func (m *Manager) fetchMissingFullContent(ctx context.Context, items []database.Item) {
+	fetched := 0
+	for _, item := range items {
+		if ctx.Err() != nil {
+			return
+		}
+		if item.FullContent != "" || item.Link == "" {
+			continue
+		}
+		if fetched >= maxFullTextFetchesPerRefresh {
+			logging.DebugCategory(logging.CategoryHTTP, "Full-text fetch limit reached, remaining items wait for the next refresh", "feedID", item.FeedID)
+			return
+		}
+		fetched++
+
+		if _, err := m.fetchFullContent(ctx, item); err != nil {
+			logging.Warn("Failed to fetch full article", "url", item.Link, "error", err)
+		}
+	}
+}
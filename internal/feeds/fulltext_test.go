@@ -0,0 +1,63 @@
+package feeds
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestExtractArticle(t *testing.T) {
+	body := strings.Repeat("This is the body of the post with enough text to count. ", 5)
+	baseURL, _ := url.Parse("https://example.com/posts/1")
+
+	tests := []struct {
+		name     string
+		page     string
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "article element",
+			page: `<html><head><script>var x = 1;</script></head><body>
+<nav><a href="/">Home</a></nav>
+<article><h1>Title</h1><p>` + body + `</p><img src="/img/a.png"></article>
+<footer>Copyright</footer></body></html>`,
+			contains: []string{"<h1>Title</h1>", body, `src="https://example.com/img/a.png"`},
+			excludes: []string{"Home", "Copyright", "var x"},
+		},
+		{
+			name: "densest paragraphs",
+			page: `<html><body>
+<div class="sidebar"><p>Short sidebar text</p></div>
+<div class="content"><p>` + body + `</p><p>` + body + `</p><a href="other">more</a></div>
+</body></html>`,
+			contains: []string{body, `href="https://example.com/posts/other"`},
+			excludes: []string{"Short sidebar text"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractArticle(strings.NewReader(tt.page), baseURL)
+			if err != nil {
+				t.Fatalf("ExtractArticle failed: %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("Expected article to contain %q, got:\n%s", want, got)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("Expected article not to contain %q, got:\n%s", unwanted, got)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractArticleNoContent(t *testing.T) {
+	if _, err := ExtractArticle(strings.NewReader("<html><body><p>Too short</p></body></html>"), nil); err == nil {
+		t.Error("Expected error for page without article content")
+	}
+}
@@ -0,0 +1,95 @@
+package feeds
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/hooks"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// SetHooks sets the hooks run when items are read, new items arrive and
+// refreshes fail
+func (m *Manager) SetHooks(h *hooks.Hooks) {
+	m.hooksMutex.Lock()
+	defer m.hooksMutex.Unlock()
+	m.hooks = h
+}
+
+func (m *Manager) currentHooks() *hooks.Hooks {
+	m.hooksMutex.RLock()
+	defer m.hooksMutex.RUnlock()
+	return m.hooks
+}
+
+// fireItemRead runs the item-read hooks with the item and its feed
+func (m *Manager) fireItemRead(itemID int64) {
+	h := m.currentHooks()
+	if !h.Has(hooks.EventItemRead) {
+		return
+	}
+	ctx := context.Background()
+	item, err := m.queries.GetItem(ctx, itemID)
+	var feed database.Feed
+	if err == nil {
+		feed, err = m.queries.GetFeed(ctx, item.FeedID)
+	}
+	if err != nil {
+		return
+	}
+	h.Fire(hooks.EventItemRead, map[string]string{
+		hooks.EnvItemID:    strconv.FormatInt(item.ID, 10),
+		hooks.EnvTitle:     item.Title,
+		hooks.EnvURL:       item.Link,
+		hooks.EnvFeedTitle: feed.Title,
+		hooks.EnvFeedURL:   feed.Url,
+	})
+}
+
+// fireRefreshFailed runs the feed-refresh-failed hooks
+func (m *Manager) fireRefreshFailed(feed database.Feed, err error) {
+	m.currentHooks().Fire(hooks.EventFeedRefreshFailed, map[string]string{
+		hooks.EnvFeedTitle: feed.Title,
+		hooks.EnvFeedURL:   feed.Url,
+		hooks.EnvError:     logging.Redact(err.Error()),
+	})
+}
+
+// knownGUIDs returns the GUIDs of a feed's items before a refresh when
+// new-items hooks or notifications need to tell which items are new, nil
+// otherwise. The items of a feed that was never fetched aren't new to anyone.
+func (m *Manager) knownGUIDs(feed database.Feed) map[string]bool {
+	if !feed.LastUpdated.Valid || (!m.currentHooks().Has(hooks.EventNewItems) && !m.notifies(feed)) {
+		return nil
+	}
+	return m.itemGUIDSet(feed.ID)
+}
+
+// fireNewItems runs the new-items hooks and queues a notification for the
+// items of a refresh that weren't known before it
+func (m *Manager) fireNewItems(feed database.Feed, known map[string]bool, upserted []database.Item) {
+	if known == nil {
+		return
+	}
+	var titles []string
+	for _, item := range upserted {
+		if !known[item.Guid] {
+			titles = append(titles, item.Title)
+		}
+	}
+	if len(titles) == 0 {
+		return
+	}
+	m.currentHooks().Fire(hooks.EventNewItems, map[string]string{
+		hooks.EnvFeedTitle: feed.Title,
+		hooks.EnvFeedURL:   feed.Url,
+		hooks.EnvNewItems:  strconv.Itoa(len(titles)),
+		hooks.EnvNewTitles: strings.Join(titles, "\n"),
+	})
+	// New items have no read status yet, so they are all unread
+	if m.notifies(feed) {
+		m.notifier.Add(feed.Title, len(titles))
+	}
+}
@@ -0,0 +1,124 @@
+package feeds
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// hostGate limits the requests to one host
+type hostGate struct {
+	slots chan struct{} // Holds a value for every request running, nil for no limit
+	next  time.Time     // When the next request may start
+}
+
+// hostLimiter spaces out and caps the requests to each host, independent of
+// how many feeds are refreshed at once
+type hostLimiter struct {
+	limits config.HostLimits
+	hosts  map[string]*hostGate
+	mutex  sync.Mutex
+}
+
+// setLimits replaces the limits, requests already waiting keep the old ones
+func (l *hostLimiter) setLimits(limits config.HostLimits) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.limits = limits
+	l.hosts = nil
+}
+
+// acquire waits until a request to host may start. The returned function
+// must be called once the request is done.
+func (l *hostLimiter) acquire(ctx context.Context, host string) (func(), error) {
+	l.mutex.Lock()
+	limits := l.limits
+	gate, ok := l.hosts[host]
+	if !ok {
+		gate = &hostGate{}
+		if limits.MaxParallel > 0 {
+			gate.slots = make(chan struct{}, limits.MaxParallel)
+		}
+		if l.hosts == nil {
+			l.hosts = make(map[string]*hostGate)
+		}
+		l.hosts[host] = gate
+	}
+	l.mutex.Unlock()
+
+	release := func() {}
+	if gate.slots != nil {
+		select {
+		case gate.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		var once sync.Once
+		release = func() {
+			once.Do(func() { <-gate.slots })
+		}
+	}
+
+	if limits.RequestsPerSecond > 0 {
+		interval := time.Second / time.Duration(limits.RequestsPerSecond)
+		l.mutex.Lock()
+		now := time.Now()
+		start := gate.next
+		if start.Before(now) {
+			start = now
+		}
+		gate.next = start.Add(interval)
+		l.mutex.Unlock()
+
+		if wait := time.Until(start); wait > 0 {
+			logging.DebugCategory(logging.CategoryHTTP, "Waiting for the host's request rate", "host", host, "wait", wait)
+			timer := time.NewTimer(wait)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				release()
+				return nil, ctx.Err()
+			}
+		}
+	}
+	return release, nil
+}
+
+// SetHostLimits sets how many requests feeds may send to the same host
+func (m *Manager) SetHostLimits(limits config.HostLimits) {
+	m.hostLimiter.setLimits(limits)
+}
+
+// releaseOnClose releases a host's request slot once the response body is
+// closed, the request runs until then
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.release()
+	return err
+}
+
+// limitHost waits for the host of req before sending it with transport
+func (m *Manager) limitHost(transport http.RoundTripper, req *http.Request) (*http.Response, error) {
+	release, err := m.hostLimiter.acquire(req.Context(), req.URL.Host)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
@@ -0,0 +1,85 @@
+package feeds
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/config"
+)
+
+func TestHostLimiterMaxParallel(t *testing.T) {
+	var l hostLimiter
+	l.setLimits(config.HostLimits{MaxParallel: 2})
+
+	var running, most atomic.Int32
+	var wg sync.WaitGroup
+	for range 6 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := l.acquire(context.Background(), "github.com")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer release()
+			n := running.Add(1)
+			for {
+				old := most.Load()
+				if n <= old || most.CompareAndSwap(old, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+		}()
+	}
+	wg.Wait()
+	if most.Load() != 2 {
+		t.Errorf("%d requests ran at once, want 2", most.Load())
+	}
+}
+
+func TestHostLimiterRate(t *testing.T) {
+	var l hostLimiter
+	l.setLimits(config.HostLimits{RequestsPerSecond: 20})
+
+	start := time.Now()
+	for range 3 {
+		release, err := l.acquire(context.Background(), "github.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+	}
+	// Other hosts don't wait for github.com
+	release, err := l.acquire(context.Background(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond || elapsed > time.Second {
+		t.Errorf("3 requests at 20 per second took %v, want about 100ms", elapsed)
+	}
+}
+
+func TestHostLimiterCanceled(t *testing.T) {
+	var l hostLimiter
+	l.setLimits(config.HostLimits{MaxParallel: 1})
+
+	release, err := l.acquire(context.Background(), "github.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx, "github.com"); err == nil {
+		t.Error("acquire() of a busy host succeeded after its context was done")
+	}
+}
@@ -0,0 +1,122 @@
+package feeds
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+// Item event types recorded for ranking
+const (
+	ItemEventOpen = "open"
+	ItemEventSkip = "skip"
+)
+
+const (
+	// hotRecencyHalfLife is the age at which the recency part of the score halves
+	hotRecencyHalfLife = 24 * time.Hour
+	// hotFeedWeight scales the feed click-through rate
+	hotFeedWeight = 2.0
+	// hotKeywordWeight is added for every configured keyword found in the title
+	hotKeywordWeight = 0.5
+)
+
+// FeedEngagement holds how often items of a feed were opened or skipped
+type FeedEngagement struct {
+	Opens int64
+	Skips int64
+}
+
+// ClickThroughRate returns the smoothed share of items that were opened,
+// feeds without history get a neutral 0.5
+func (e FeedEngagement) ClickThroughRate() float64 {
+	return float64(e.Opens+1) / float64(e.Opens+e.Skips+2)
+}
+
+// ParseHotKeywords splits a comma-separated keyword setting into lowercase keywords
+func ParseHotKeywords(keywords string) []string {
+	var result []string
+	for _, keyword := range strings.Split(keywords, ",") {
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		if keyword != "" {
+			result = append(result, keyword)
+		}
+	}
+	return result
+}
+
+// HotScore scores an item by the click-through rate of its feed, the
+// configured keywords in its title and how recently it was published
+func HotScore(title string, published time.Time, engagement FeedEngagement, keywords []string, now time.Time) float64 {
+	score := hotFeedWeight * engagement.ClickThroughRate()
+
+	lowerTitle := strings.ToLower(title)
+	for _, keyword := range keywords {
+		if strings.Contains(lowerTitle, keyword) {
+			score += hotKeywordWeight
+		}
+	}
+
+	if !published.IsZero() {
+		age := now.Sub(published)
+		if age < 0 {
+			age = 0
+		}
+		score += math.Pow(0.5, float64(age)/float64(hotRecencyHalfLife))
+	}
+
+	return score
+}
+
+// RecordItemEvent records that an item was opened or skipped
+func (m *Manager) RecordItemEvent(itemID, feedID int64, event string) error {
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
+	return m.queries.RecordItemEvent(context.Background(), database.RecordItemEventParams{
+		ItemID: itemID,
+		FeedID: feedID,
+		Event:  event,
+	})
+}
+
+// GetHotItems returns all unread items ordered best-first by HotScore
+func (m *Manager) GetHotItems(keywords string) ([]database.GetItemsWithReadStatusRow, error) {
+	ctx := context.Background()
+
+	unread, err := m.queries.GetUnreadItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	engagementRows, err := m.queries.GetFeedEngagement(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	engagement := make(map[int64]FeedEngagement)
+	for _, row := range engagementRows {
+		engagement[row.FeedID] = FeedEngagement{Opens: row.Opens, Skips: row.Skips}
+	}
+
+	parsedKeywords := ParseHotKeywords(keywords)
+	now := time.Now()
+	items := make([]database.GetItemsWithReadStatusRow, len(unread))
+	scores := make(map[int64]float64, len(unread))
+	for i, item := range unread {
+		items[i] = database.GetItemsWithReadStatusRow(item)
+		var published time.Time
+		if item.Published.Valid {
+			published = item.Published.Time
+		}
+		scores[item.ID] = HotScore(item.Title, published, engagement[item.FeedID], parsedKeywords, now)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return scores[items[i].ID] > scores[items[j].ID]
+	})
+
+	return items, nil
+}
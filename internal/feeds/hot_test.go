@@ -0,0 +1,38 @@
+package feeds
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseHotKeywords(t *testing.T) {
+	got := ParseHotKeywords(" CVE, Go 1.,, kubernetes ")
+	expected := []string{"cve", "go 1.", "kubernetes"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ParseHotKeywords() = %v, want %v", got, expected)
+	}
+}
+
+func TestHotScore(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	neutral := FeedEngagement{}
+	keywords := []string{"cve"}
+
+	fresh := HotScore("Release notes", now, neutral, keywords, now)
+	old := HotScore("Release notes", now.Add(-72*time.Hour), neutral, keywords, now)
+	if fresh <= old {
+		t.Errorf("Expected recent item to score higher: fresh=%f old=%f", fresh, old)
+	}
+
+	boosted := HotScore("New CVE in OpenSSL", now.Add(-72*time.Hour), neutral, keywords, now)
+	if boosted <= old {
+		t.Errorf("Expected keyword match to score higher: boosted=%f old=%f", boosted, old)
+	}
+
+	popular := HotScore("Release notes", now, FeedEngagement{Opens: 20, Skips: 1}, keywords, now)
+	ignored := HotScore("Release notes", now, FeedEngagement{Opens: 0, Skips: 20}, keywords, now)
+	if popular <= ignored {
+		t.Errorf("Expected often opened feed to score higher: popular=%f ignored=%f", popular, ignored)
+	}
+}
@@ -0,0 +1,489 @@
+package feeds
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/logging"
+	"github.com/jarv/newsgoat/internal/version"
+	"github.com/mmcdole/gofeed"
+	"golang.org/x/net/html"
+)
+
+// iconRefetchAge is how long the icon of a host is kept before it is looked
+// up again, also when the host has none
+const iconRefetchAge = 30 * 24 * time.Hour
+
+// maxIconSize limits how much of a favicon or homepage is downloaded
+const maxIconSize = 1 << 20
+
+// ErrNoIcon is returned when none of the places an icon could be at has one
+var ErrNoIcon = errors.New("no icon found")
+
+// iconSource is where a feed says its site and image are
+type iconSource struct {
+	link  string
+	image string
+}
+
+// SetFeedIcons sets whether the favicons of the sites feeds come from are
+// looked up after refreshes
+func (m *Manager) SetFeedIcons(enabled bool) {
+	m.iconMutex.Lock()
+	defer m.iconMutex.Unlock()
+	m.feedIcons = enabled
+}
+
+// FeedIconHost returns the host the icon of a feed is kept under, without
+// www. so a site and its feed on www share it
+func FeedIconHost(feedURL string) string {
+	u, err := url.Parse(feedURL)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// recordIconSource remembers the site link and image of a parsed feed, the
+// icon is looked for there first
+func (m *Manager) recordIconSource(feedID int64, parsedFeed *gofeed.Feed) {
+	source := iconSource{link: parsedFeed.Link}
+	if parsedFeed.Image != nil {
+		source.image = parsedFeed.Image.URL
+	}
+	m.iconMutex.Lock()
+	defer m.iconMutex.Unlock()
+	m.iconSources[feedID] = source
+}
+
+// NeedsFeedIcon reports whether the icon of a feed's host should be looked
+// up, each host is only looked at once per run
+func (m *Manager) NeedsFeedIcon(feedID int64) bool {
+	m.iconMutex.Lock()
+	enabled := m.feedIcons
+	m.iconMutex.Unlock()
+	if !enabled {
+		return false
+	}
+
+	feed, err := m.queries.GetFeed(context.Background(), feedID)
+	if err != nil {
+		return false
+	}
+	host := FeedIconHost(feed.Url)
+	if host == "" {
+		return false
+	}
+
+	m.iconMutex.Lock()
+	defer m.iconMutex.Unlock()
+	if m.iconHosts[host] {
+		return false
+	}
+	m.iconHosts[host] = true
+
+	icon, err := m.queries.GetFeedIcon(context.Background(), host)
+	return err != nil || time.Since(icon.FetchedAt) > iconRefetchAge
+}
+
+// FetchFeedIcon looks up the favicon of a feed's site and saves its main
+// color under the feed's host. Hosts without an icon are saved without a
+// color, so they aren't looked up again until iconRefetchAge passed.
+func (m *Manager) FetchFeedIcon(ctx context.Context, feedID int64) error {
+	feed, err := m.queries.GetFeed(ctx, feedID)
+	if err != nil {
+		return err
+	}
+	host := FeedIconHost(feed.Url)
+	if host == "" {
+		return fmt.Errorf("feed URL without a host: %s", logging.Redact(feed.Url))
+	}
+
+	m.iconMutex.Lock()
+	source := m.iconSources[feedID]
+	m.iconMutex.Unlock()
+
+	var iconColor string
+	for _, candidate := range m.iconCandidates(ctx, feed.Url, source) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		data, err := m.fetchIconData(ctx, candidate)
+		if err != nil {
+			logging.DebugCategory(logging.CategoryHTTP, "Icon not available", "url", candidate, "error", err)
+			continue
+		}
+		if iconColor, err = IconColor(data); err == nil {
+			break
+		}
+		logging.DebugCategory(logging.CategoryHTTP, "Icon not readable", "url", candidate, "error", err)
+	}
+
+	m.writeMutex.Lock()
+	err = m.queries.SaveFeedIcon(context.Background(), database.SaveFeedIconParams{
+		Host:      host,
+		Color:     iconColor,
+		FetchedAt: time.Now(),
+	})
+	m.writeMutex.Unlock()
+	if err != nil {
+		return err
+	}
+	if iconColor == "" {
+		return ErrNoIcon
+	}
+	return nil
+}
+
+// GetFeedIcons returns the color of every host an icon was found for
+func (m *Manager) GetFeedIcons() (map[string]string, error) {
+	icons, err := m.queries.GetFeedIcons(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	colors := make(map[string]string, len(icons))
+	for _, icon := range icons {
+		colors[icon.Host] = icon.Color
+	}
+	return colors, nil
+}
+
+// iconCandidates returns the URLs an icon may be at, best first: the icons
+// the site's homepage links to, the site's and the feed host's favicon.ico
+// and the feed's image
+func (m *Manager) iconCandidates(ctx context.Context, feedURL string, source iconSource) []string {
+	var candidates []string
+	seen := make(map[string]bool)
+	add := func(candidate string) {
+		if candidate != "" && !seen[candidate] {
+			seen[candidate] = true
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	site := source.link
+	if site == "" {
+		site = siteOrigin(feedURL)
+	}
+	if site != "" {
+		if links, err := m.homepageIcons(ctx, site); err == nil {
+			for _, link := range links {
+				add(link)
+			}
+		} else {
+			logging.DebugCategory(logging.CategoryHTTP, "Homepage not available", "url", site, "error", err)
+		}
+	}
+	for _, origin := range []string{siteOrigin(site), siteOrigin(feedURL)} {
+		if origin != "" {
+			add(origin + "/favicon.ico")
+		}
+	}
+	add(source.image)
+	return candidates
+}
+
+// siteOrigin returns the scheme and host of a URL
+func siteOrigin(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// homepageIcons returns the icons a page links to, icons before touch icons
+// and without SVGs, which can't be decoded
+func (m *Manager) homepageIcons(ctx context.Context, pageURL string) ([]string, error) {
+	body, err := m.fetchIconURL(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var icons, touchIcons []string
+	tokenizer := html.NewTokenizer(bytes.NewReader(body))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return append(icons, touchIcons...), nil
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			if token.Data == "body" {
+				return append(icons, touchIcons...), nil
+			}
+			if token.Data != "link" {
+				continue
+			}
+			var rel, href, linkType string
+			for _, attr := range token.Attr {
+				switch attr.Key {
+				case "rel":
+					rel = strings.ToLower(attr.Val)
+				case "href":
+					href = strings.TrimSpace(attr.Val)
+				case "type":
+					linkType = strings.ToLower(attr.Val)
+				}
+			}
+			if href == "" || linkType == "image/svg+xml" || strings.HasSuffix(strings.ToLower(href), ".svg") {
+				continue
+			}
+			ref, err := url.Parse(href)
+			if err != nil {
+				continue
+			}
+			resolved := base.ResolveReference(ref).String()
+			for _, value := range strings.Fields(rel) {
+				if value == "icon" {
+					icons = append(icons, resolved)
+					break
+				}
+				if value == "apple-touch-icon" || value == "apple-touch-icon-precomposed" {
+					touchIcons = append(touchIcons, resolved)
+					break
+				}
+			}
+		}
+	}
+}
+
+// fetchIconData downloads an icon, pages that aren't images are refused
+func (m *Manager) fetchIconData(ctx context.Context, iconURL string) ([]byte, error) {
+	data, err := m.fetchIconURL(ctx, iconURL)
+	if err != nil {
+		return nil, err
+	}
+	if contentType := http.DetectContentType(data); strings.HasPrefix(contentType, "text/html") {
+		return nil, fmt.Errorf("not an image: %s", contentType)
+	}
+	return data, nil
+}
+
+// fetchIconURL downloads a homepage or icon of at most maxIconSize
+func (m *Manager) fetchIconURL(ctx context.Context, rawURL string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, FeedTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", version.GetUserAgent())
+
+	client := &http.Client{Timeout: FeedTimeout, Transport: m.globalTransport()}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxIconSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxIconSize {
+		return nil, fmt.Errorf("larger than %d MB", maxIconSize>>20)
+	}
+	return data, nil
+}
+
+// IconColor returns the main color of a PNG, JPEG, GIF or ICO image as
+// #rrggbb. Colorful pixels win over gray ones and those over the near white
+// and near black backgrounds most icons have.
+func IconColor(data []byte) (string, error) {
+	img, err := decodeIcon(data)
+	if err != nil {
+		return "", err
+	}
+
+	// Pixels are counted in buckets of similar colors, the biggest bucket
+	// of the most colorful tier gives the color
+	type bucket struct {
+		count   int
+		r, g, b int
+	}
+	var tiers [3]map[int]*bucket
+	for i := range tiers {
+		tiers[i] = make(map[int]*bucket)
+	}
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			pixel := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if pixel.A < 128 {
+				continue
+			}
+			r, g, b := int(pixel.R), int(pixel.G), int(pixel.B)
+			high, low := max(r, g, b), min(r, g, b)
+			tier := 0
+			switch {
+			case low > 230 || high < 25:
+				tier = 2
+			case high-low < 40:
+				tier = 1
+			}
+			key := r>>5<<6 | g>>5<<3 | b>>5
+			bk := tiers[tier][key]
+			if bk == nil {
+				bk = &bucket{}
+				tiers[tier][key] = bk
+			}
+			bk.count++
+			bk.r += r
+			bk.g += g
+			bk.b += b
+		}
+	}
+
+	for _, buckets := range tiers {
+		var best *bucket
+		for _, bk := range buckets {
+			if best == nil || bk.count > best.count {
+				best = bk
+			}
+		}
+		if best != nil {
+			return fmt.Sprintf("#%02x%02x%02x", best.r/best.count, best.g/best.count, best.b/best.count), nil
+		}
+	}
+	return "", errors.New("icon is transparent")
+}
+
+// decodeIcon decodes a PNG, JPEG, GIF or ICO image
+func decodeIcon(data []byte) (image.Image, error) {
+	if len(data) >= 6 && binary.LittleEndian.Uint16(data[0:]) == 0 && binary.LittleEndian.Uint16(data[2:]) == 1 {
+		return decodeICO(data)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// decodeICO decodes the biggest image of an ICO file, stored as a PNG or
+// as a bitmap without its file header
+func decodeICO(data []byte) (image.Image, error) {
+	count := int(binary.LittleEndian.Uint16(data[4:]))
+	if count == 0 || len(data) < 6+16*count {
+		return nil, errors.New("invalid ICO header")
+	}
+
+	var offset, size uint32
+	bestWidth := -1
+	for i := range count {
+		entry := data[6+16*i:]
+		width := int(entry[0])
+		if width == 0 {
+			width = 256
+		}
+		if width > bestWidth {
+			bestWidth = width
+			size = binary.LittleEndian.Uint32(entry[8:])
+			offset = binary.LittleEndian.Uint32(entry[12:])
+		}
+	}
+	if uint64(offset)+uint64(size) > uint64(len(data)) {
+		return nil, errors.New("ICO image out of bounds")
+	}
+	entry := data[offset : offset+size]
+	if bytes.HasPrefix(entry, []byte("\x89PNG")) {
+		return png.Decode(bytes.NewReader(entry))
+	}
+	return decodeDIB(entry)
+}
+
+// decodeDIB decodes the bitmap of an ICO entry with 1, 4, 8, 24 or 32 bits
+// per pixel. Its height counts the transparency mask too, which is ignored
+// and left to the near black and white pixels being skipped.
+func decodeDIB(data []byte) (image.Image, error) {
+	if len(data) < 40 {
+		return nil, errors.New("invalid bitmap header")
+	}
+	headerSize := int(binary.LittleEndian.Uint32(data[0:]))
+	width := int(int32(binary.LittleEndian.Uint32(data[4:])))
+	height := int(int32(binary.LittleEndian.Uint32(data[8:]))) / 2
+	bitCount := int(binary.LittleEndian.Uint16(data[14:]))
+	compression := binary.LittleEndian.Uint32(data[16:])
+	colorsUsed := int(binary.LittleEndian.Uint32(data[32:]))
+	if height < 0 {
+		height = -height
+	}
+	if headerSize < 40 || width <= 0 || height <= 0 || width > 256 || height > 256 || compression != 0 {
+		return nil, errors.New("unsupported bitmap")
+	}
+
+	var palette []color.NRGBA
+	if bitCount <= 8 {
+		if colorsUsed == 0 {
+			colorsUsed = 1 << bitCount
+		}
+		start := headerSize
+		if len(data) < start+4*colorsUsed {
+			return nil, errors.New("bitmap palette out of bounds")
+		}
+		for i := range colorsUsed {
+			entry := data[start+4*i:]
+			palette = append(palette, color.NRGBA{R: entry[2], G: entry[1], B: entry[0], A: 255})
+		}
+	}
+	pixels := data[headerSize+4*len(palette):]
+
+	stride := (width*bitCount + 31) / 32 * 4
+	if len(pixels) < stride*height {
+		return nil, errors.New("bitmap pixels out of bounds")
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	var hasAlpha bool
+	for y := range height {
+		row := pixels[stride*y:]
+		for x := range width {
+			var pixel color.NRGBA
+			switch bitCount {
+			case 32:
+				pixel = color.NRGBA{R: row[4*x+2], G: row[4*x+1], B: row[4*x], A: row[4*x+3]}
+				hasAlpha = hasAlpha || pixel.A != 0
+			case 24:
+				pixel = color.NRGBA{R: row[3*x+2], G: row[3*x+1], B: row[3*x], A: 255}
+			case 1, 4, 8:
+				bit := x * bitCount
+				index := int(row[bit/8]>>(8-bitCount-bit%8)) & (1<<bitCount - 1)
+				if index >= len(palette) {
+					return nil, errors.New("bitmap color out of palette")
+				}
+				pixel = palette[index]
+			default:
+				return nil, fmt.Errorf("unsupported bitmap with %d bits per pixel", bitCount)
+			}
+			// Bitmaps are stored bottom up
+			img.SetNRGBA(x, height-1-y, pixel)
+		}
+	}
+
+	// 32 bit bitmaps without any alpha are opaque
+	if bitCount == 32 && !hasAlpha {
+		for i := 3; i < len(img.Pix); i += 4 {
+			img.Pix[i] = 255
+		}
+	}
+	return img, nil
+}
@@ -0,0 +1,216 @@
+package feeds
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+// testIcon returns a 16x16 icon of c on a white background with a black
+// border, like most favicons
+func testIcon(c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 16, 16))
+	for y := range 16 {
+		for x := range 16 {
+			switch {
+			case x == 0 || y == 0 || x == 15 || y == 15:
+				img.Set(x, y, color.Black)
+			case x >= 4 && x < 12 && y >= 4 && y < 12:
+				img.Set(x, y, c)
+			default:
+				img.Set(x, y, color.White)
+			}
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+// encodeICO stores img as a 32 bit bitmap in an ICO file
+func encodeICO(img *image.NRGBA) []byte {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	var dib bytes.Buffer
+	header := make([]byte, 40)
+	binary.LittleEndian.PutUint32(header[0:], 40)
+	binary.LittleEndian.PutUint32(header[4:], uint32(width))
+	binary.LittleEndian.PutUint32(header[8:], uint32(2*height))
+	binary.LittleEndian.PutUint16(header[12:], 1)
+	binary.LittleEndian.PutUint16(header[14:], 32)
+	dib.Write(header)
+	for y := height - 1; y >= 0; y-- {
+		for x := range width {
+			c := img.NRGBAAt(x, y)
+			dib.Write([]byte{c.B, c.G, c.R, c.A})
+		}
+	}
+	// Transparency mask, rows padded to 4 bytes
+	dib.Write(make([]byte, (width+31)/32*4*height))
+
+	var ico bytes.Buffer
+	_ = binary.Write(&ico, binary.LittleEndian, []uint16{0, 1, 1})
+	entry := make([]byte, 16)
+	entry[0], entry[1] = byte(width), byte(height)
+	binary.LittleEndian.PutUint16(entry[4:], 1)
+	binary.LittleEndian.PutUint16(entry[6:], 32)
+	binary.LittleEndian.PutUint32(entry[8:], uint32(dib.Len()))
+	binary.LittleEndian.PutUint32(entry[12:], 6+16)
+	ico.Write(entry)
+	ico.Write(dib.Bytes())
+	return ico.Bytes()
+}
+
+func TestIconColor(t *testing.T) {
+	red := color.NRGBA{R: 0xe0, G: 0x20, B: 0x10, A: 0xff}
+	gray := color.NRGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"png", encodePNG(t, testIcon(red)), "#e02010"},
+		{"ico", encodeICO(testIcon(red)), "#e02010"},
+		// Gray is used when there is no color, the background when there is nothing else
+		{"gray", encodePNG(t, testIcon(gray)), "#808080"},
+		{"white", encodePNG(t, testIcon(color.White)), "#ffffff"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IconColor(tt.data)
+			if err != nil {
+				t.Fatalf("IconColor() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IconColor() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := IconColor(encodePNG(t, image.NewNRGBA(image.Rect(0, 0, 4, 4)))); err == nil {
+		t.Error("IconColor() of a transparent icon succeeded")
+	}
+	if _, err := IconColor([]byte("<html></html>")); err == nil {
+		t.Error("IconColor() of a page succeeded")
+	}
+}
+
+func TestFetchFeedIcon(t *testing.T) {
+	blue := encodePNG(t, testIcon(color.NRGBA{R: 0x10, G: 0x40, B: 0xc0, A: 0xff}))
+	var faviconRequests atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/feed", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `<rss version="2.0"><channel><title>Example</title><link>`+"http://"+r.Host+`/blog/</link></channel></rss>`)
+	})
+	mux.HandleFunc("/blog/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `<html><head>
+<link rel="apple-touch-icon" href="/touch.png">
+<link rel="icon" type="image/svg+xml" href="/icon.svg">
+<link rel="shortcut icon" href="../static/icon.png">
+</head><body><link rel="icon" href="/ignored.png"></body></html>`)
+	})
+	mux.HandleFunc("/static/icon.png", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(blue)
+	})
+	mux.HandleFunc("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {
+		faviconRequests.Add(1)
+		http.NotFound(w, r)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	db, queries := openTestDB(t)
+	ctx := context.Background()
+	feed, err := queries.CreateFeed(ctx, database.CreateFeedParams{Url: server.URL + "/feed", Title: "Example"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	m := NewManager(db, queries)
+
+	if m.NeedsFeedIcon(feed.ID) {
+		t.Error("NeedsFeedIcon() = true with icons off")
+	}
+	m.SetFeedIcons(true)
+	if err := m.RefreshFeed(feed.ID); err != nil {
+		t.Fatalf("RefreshFeed() error = %v", err)
+	}
+	if !m.NeedsFeedIcon(feed.ID) {
+		t.Fatal("NeedsFeedIcon() = false for a feed without an icon")
+	}
+	if m.NeedsFeedIcon(feed.ID) {
+		t.Error("NeedsFeedIcon() = true for a host that was already looked at")
+	}
+
+	if err := m.FetchFeedIcon(ctx, feed.ID); err != nil {
+		t.Fatalf("FetchFeedIcon() error = %v", err)
+	}
+	if n := faviconRequests.Load(); n != 0 {
+		t.Errorf("favicon.ico was requested %d times before the linked icon", n)
+	}
+	icons, err := m.GetFeedIcons()
+	if err != nil {
+		t.Fatalf("GetFeedIcons() error = %v", err)
+	}
+	if got := icons[FeedIconHost(feed.Url)]; got != "#1040c0" {
+		t.Errorf("icon color = %q, want #1040c0", got)
+	}
+}
+
+func TestFetchFeedIconMissing(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	db, queries := openTestDB(t)
+	ctx := context.Background()
+	feed, err := queries.CreateFeed(ctx, database.CreateFeedParams{Url: server.URL + "/feed", Title: "Example"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	m := NewManager(db, queries)
+
+	if err := m.FetchFeedIcon(ctx, feed.ID); !errors.Is(err, ErrNoIcon) {
+		t.Fatalf("FetchFeedIcon() error = %v, want ErrNoIcon", err)
+	}
+	// The host is remembered without a color so it isn't looked up again
+	icon, err := queries.GetFeedIcon(ctx, FeedIconHost(feed.Url))
+	if err != nil {
+		t.Fatalf("GetFeedIcon() error = %v", err)
+	}
+	if icon.Color != "" {
+		t.Errorf("icon color = %q, want none", icon.Color)
+	}
+	m.SetFeedIcons(true)
+	if m.NeedsFeedIcon(feed.ID) {
+		t.Error("NeedsFeedIcon() = true for a host without an icon that was just looked up")
+	}
+}
+
+func TestFeedIconHost(t *testing.T) {
+	tests := map[string]string{
+		"https://www.Example.com/feed.xml": "example.com",
+		"https://blog.example.com:8443/":   "blog.example.com",
+		"not a url":                        "",
+	}
+	for feedURL, want := range tests {
+		if got := FeedIconHost(feedURL); got != want {
+			t.Errorf("FeedIconHost(%q) = %q, want %q", feedURL, got, want)
+		}
+	}
+}
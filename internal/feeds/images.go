@@ -0,0 +1,139 @@
+package feeds
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/logging"
+	"github.com/jarv/newsgoat/internal/version"
+)
+
+// maxImageSize limits how much of an article image is downloaded
+const maxImageSize = 10 << 20
+
+// SetImageCacheDir sets the directory downloaded article images are kept in,
+// without one they are downloaded every time
+func (m *Manager) SetImageCacheDir(dir string) {
+	m.imageCacheDir = dir
+}
+
+// imageCachePath returns the file an image URL is cached in
+func (m *Manager) imageCachePath(imageURL string) string {
+	sum := sha256.Sum256([]byte(imageURL))
+	return filepath.Join(m.imageCacheDir, hex.EncodeToString(sum[:]))
+}
+
+// FetchImage returns an article image from the cache, downloading it the
+// first time it is shown
+func (m *Manager) FetchImage(imageURL string) ([]byte, error) {
+	var path string
+	if m.imageCacheDir != "" {
+		path = m.imageCachePath(imageURL)
+		if data, err := os.ReadFile(path); err == nil {
+			// Images still being shown are kept by PruneImageCache
+			now := time.Now()
+			_ = os.Chtimes(path, now, now)
+			return data, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), FeedTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", imageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", version.GetUserAgent())
+
+	client := &http.Client{Timeout: FeedTimeout, Transport: m.globalTransport()}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" && !strings.HasPrefix(contentType, "image/") {
+		return nil, fmt.Errorf("not an image: %s", contentType)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImageSize {
+		return nil, fmt.Errorf("image larger than %d MB", maxImageSize>>20)
+	}
+
+	if path != "" {
+		if err := writeFileAtomic(path, data); err != nil {
+			logging.Warn("Failed to cache image", "url", imageURL, "error", err)
+		}
+	}
+	return data, nil
+}
+
+// writeFileAtomic writes through a temporary file, so an image shown by
+// another NewsGoat is never read half written
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".image-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// PruneImageCache deletes the cached images that weren't shown for maxAge
+// and returns how many it deleted
+func (m *Manager) PruneImageCache(maxAge time.Duration) (int, error) {
+	if m.imageCacheDir == "" {
+		return 0, nil
+	}
+	entries, err := os.ReadDir(m.imageCacheDir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-maxAge)
+	pruned := 0
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || info.ModTime().After(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(m.imageCacheDir, entry.Name())); err == nil {
+			pruned++
+		}
+	}
+	return pruned, nil
+}
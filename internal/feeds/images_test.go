@@ -0,0 +1,56 @@
+package feeds
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchImage(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path == "/page" {
+			w.Header().Set("Content-Type", "text/html")
+		} else {
+			w.Header().Set("Content-Type", "image/png")
+		}
+		_, _ = w.Write([]byte("picture"))
+	}))
+	defer server.Close()
+
+	m := NewManager(nil, nil)
+	m.SetImageCacheDir(filepath.Join(t.TempDir(), "images"))
+
+	// The second fetch comes from the cache
+	for range 2 {
+		data, err := m.FetchImage(server.URL + "/a.png")
+		if err != nil {
+			t.Fatalf("FetchImage() error = %v", err)
+		}
+		if string(data) != "picture" {
+			t.Errorf("FetchImage() = %q", data)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("%d requests, want 1", got)
+	}
+
+	if _, err := m.FetchImage(server.URL + "/page"); err == nil {
+		t.Error("FetchImage() of a page succeeded")
+	}
+
+	// Images not shown for longer than the max age are pruned
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(m.imageCachePath(server.URL+"/a.png"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	pruned, err := m.PruneImageCache(24 * time.Hour)
+	if err != nil || pruned != 1 {
+		t.Errorf("PruneImageCache() = %d, %v, want 1", pruned, err)
+	}
+}
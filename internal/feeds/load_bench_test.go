@@ -0,0 +1,112 @@
+package feeds
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// benchFeeds is how many feeds are refreshed while the item list loads
+const benchFeeds = 8
+
+// benchLatency is how long the server takes to answer, like a feed on the
+// internet would
+const benchLatency = 20 * time.Millisecond
+
+// newBenchManager serves feeds of 200 items whose titles change on every
+// request, so each refresh writes all of them, and adds benchFeeds+1 of them
+func newBenchManager(b *testing.B) (*Manager, []int64) {
+	b.Helper()
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(benchLatency)
+		n := requests.Add(1)
+		var body strings.Builder
+		body.WriteString(`<?xml version="1.0"?><rss version="2.0"><channel><title>Bench</title>`)
+		for i := range 200 {
+			fmt.Fprintf(&body, "<item><title>Item %d version %d</title><guid>%s-%d</guid><description>%s</description></item>",
+				i, n, r.URL.Path, i, strings.Repeat("Some text of the item. ", 20))
+		}
+		body.WriteString(`</channel></rss>`)
+		_, _ = w.Write([]byte(body.String()))
+	}))
+	b.Cleanup(server.Close)
+
+	db, queries := openTestDB(b)
+	manager := NewManager(db, queries)
+	var feedIDs []int64
+	for i := range benchFeeds + 1 {
+		url := fmt.Sprintf("%s/feed/%d", server.URL, i)
+		if err := manager.AddFeedWithoutFetching(url); err != nil {
+			b.Fatalf("AddFeedWithoutFetching() error = %v", err)
+		}
+		feed, err := queries.GetFeedByURL(b.Context(), url)
+		if err != nil {
+			b.Fatalf("GetFeedByURL() error = %v", err)
+		}
+		if err := manager.RefreshFeedContext(b.Context(), feed.ID, true); err != nil {
+			b.Fatalf("RefreshFeedContext() error = %v", err)
+		}
+		feedIDs = append(feedIDs, feed.ID)
+	}
+	return manager, feedIDs
+}
+
+// benchmarkItemList loads the item list of the first feed, with refresh
+// refreshing the other feeds over and over in the background like a
+// refresh-all. The slowest load is reported as max-ms.
+func benchmarkItemList(b *testing.B, refresh bool) {
+	manager, feedIDs := newBenchManager(b)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	if refresh {
+		for _, feedID := range feedIDs[1:] {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+					}
+					if err := manager.RefreshFeedContext(b.Context(), feedID, true); err != nil {
+						b.Errorf("RefreshFeedContext() error = %v", err)
+						return
+					}
+				}
+			}()
+		}
+	}
+
+	var slowest time.Duration
+	for b.Loop() {
+		start := time.Now()
+		items, err := manager.GetItemsWithReadStatus(feedIDs[0])
+		if err != nil {
+			b.Fatalf("GetItemsWithReadStatus() error = %v", err)
+		}
+		if len(items) != 200 {
+			b.Fatalf("GetItemsWithReadStatus() returned %d items, want 200", len(items))
+		}
+		slowest = max(slowest, time.Since(start))
+	}
+	b.StopTimer()
+	close(stop)
+	wg.Wait()
+	b.ReportMetric(float64(slowest.Microseconds())/1000, "max-ms")
+}
+
+func BenchmarkItemListIdle(b *testing.B) {
+	benchmarkItemList(b, false)
+}
+
+func BenchmarkItemListDuringRefreshAll(b *testing.B) {
+	benchmarkItemList(b, true)
+}
@@ -1,9 +1,13 @@
 package feeds
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -11,12 +15,17 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	md "github.com/JohannesKaufmann/html-to-markdown/v2"
+	"github.com/jarv/newsgoat/internal/config"
 	"github.com/jarv/newsgoat/internal/database"
 	"github.com/jarv/newsgoat/internal/discovery"
+	"github.com/jarv/newsgoat/internal/hooks"
 	"github.com/jarv/newsgoat/internal/logging"
+	"github.com/jarv/newsgoat/internal/notify"
+	"github.com/jarv/newsgoat/internal/rules"
 	"github.com/jarv/newsgoat/internal/version"
 	"github.com/mmcdole/gofeed"
 )
@@ -26,10 +35,13 @@ const FeedTimeout = 30 * time.Second
 // Type aliases for convenience
 type LogMessage = database.LogMessage
 
-// conditionalRequestTransport wraps http.RoundTripper to add conditional request headers and User-Agent
+// conditionalRequestTransport wraps http.RoundTripper to add conditional request headers, User-Agent
+// and the custom headers and credentials configured for the feed
 type conditionalRequestTransport struct {
 	Transport http.RoundTripper
 	UserAgent string
+	Headers   http.Header
+	Auth      config.FeedAuth
 	Manager   *Manager
 	FeedURL   string
 }
@@ -38,11 +50,25 @@ func (t *conditionalRequestTransport) RoundTrip(req *http.Request) (*http.Respon
 	// Set User-Agent
 	req.Header.Set("User-Agent", t.UserAgent)
 
+	// Custom headers and credentials are only sent to the feed's host, not to
+	// where it redirects
+	if len(t.Headers) > 0 || t.Auth.Scheme != "" {
+		if feedURL, err := url.Parse(t.FeedURL); err == nil && feedURL.Host == req.URL.Host {
+			for name, values := range t.Headers {
+				req.Header[name] = values
+			}
+			switch t.Auth.Scheme {
+			case config.AuthBasic:
+				req.SetBasicAuth(t.Auth.Username, t.Auth.Secret)
+			case config.AuthBearer:
+				req.Header.Set("Authorization", "Bearer "+t.Auth.Secret)
+			}
+		}
+	}
+
 	// Add conditional request headers if we have them
 	if t.Manager != nil && t.FeedURL != "" {
-		t.Manager.dbMutex.RLock()
 		feed, err := t.Manager.queries.GetFeedByURL(context.Background(), t.FeedURL)
-		t.Manager.dbMutex.RUnlock()
 
 		if err == nil {
 			// Add If-None-Match header if we have an ETag
@@ -57,6 +83,9 @@ func (t *conditionalRequestTransport) RoundTrip(req *http.Request) (*http.Respon
 		}
 	}
 
+	if t.Manager != nil {
+		return t.Manager.limitHost(t.Transport, req)
+	}
 	return t.Transport.RoundTrip(req)
 }
 
@@ -65,22 +94,155 @@ type Manager struct {
 	queries          *database.Queries
 	parser           *gofeed.Parser
 	refreshCallbacks map[int64]func(int64) // Callbacks for refresh events
-	dbMutex          sync.RWMutex          // Global RWMutex for database operations
+	writeMutex       sync.Mutex            // Serializes writes, reads run alongside them on the write-ahead log
+
+	// When API requests to a forge may resume after hitting its rate limit
+	rateLimits     map[rateLimitKey]time.Time
+	rateLimitMutex sync.Mutex
+
+	// Feed credentials from the URLs file, keyed by feed URL
+	feedAuths     map[string]config.FeedAuth
+	feedAuthMutex sync.RWMutex
+
+	// Proxy, User-Agent and headers from the settings and per feed from the URLs file
+	requestOptions     config.RequestOptions
+	feedRequestOptions map[string]config.RequestOptions
+	transports         map[string]http.RoundTripper // Keyed by proxy URL
+	requestMutex       sync.RWMutex
+
+	// Which items are pruned after a refresh
+	retentionPolicy config.RetentionPolicy
+	retentionMutex  sync.RWMutex
+
+	// Where hubs push updates to and what happens when they do
+	webSub      WebSubConfig
+	onPush      func(feedID int64, url string)
+	webSubMutex sync.RWMutex
+
+	// Commands run on events, from the hooks file
+	hooks      *hooks.Hooks
+	hooksMutex sync.RWMutex
+
+	// Which feeds send a desktop notification about new items, collected
+	// until the refreshes running together are done
+	notifyPolicy config.NotifyPolicy
+	feedNotify   map[string]bool
+	notifier     *notify.Notifier
+	notifyMutex  sync.RWMutex
+
+	// Directory article images are kept in once downloaded, set at startup
+	imageCacheDir string
+
+	// Spaces out the requests to each host
+	hostLimiter hostLimiter
+
+	// Older pages of a paginated feed fetched on its first refresh
+	historyPages atomic.Int32
+
+	// Where feeds redirect permanently to, and the URLs file they are moved
+	// in when that is done automatically
+	movedFeeds       map[int64]string
+	redirectURLsPath string
+	urlsFileChanged  atomic.Bool
+	movedMutex       sync.Mutex
+
+	// Whether favicons are fetched, where each feed's site and image are
+	// from its last fetch, and the hosts whose icon was looked at this run
+	feedIcons   bool
+	iconSources map[int64]iconSource
+	iconHosts   map[string]bool
+	iconMutex   sync.Mutex
 }
 
 // createHTTPClientForFeed creates an HTTP client with conditional request support for a specific feed URL
 func (m *Manager) createHTTPClientForFeed(feedURL string) *http.Client {
+	options := m.requestOptionsForURL(feedURL)
+	userAgent := options.UserAgent
+	if userAgent == "" {
+		userAgent = version.GetUserAgent()
+	}
+
 	return &http.Client{
 		Timeout: FeedTimeout,
 		Transport: &conditionalRequestTransport{
-			Transport: http.DefaultTransport,
-			UserAgent: version.GetUserAgent(),
+			Transport: m.transportForProxy(options.Proxy),
+			UserAgent: userAgent,
+			Headers:   options.Headers,
+			Auth:      m.authForURL(feedURL),
 			Manager:   m,
 			FeedURL:   feedURL,
 		},
 	}
 }
 
+// SetRequestOptions sets the proxy, User-Agent and headers used for every feed
+func (m *Manager) SetRequestOptions(options config.RequestOptions) {
+	for _, secret := range options.Secrets() {
+		logging.RegisterSecret(secret)
+	}
+
+	m.requestMutex.Lock()
+	defer m.requestMutex.Unlock()
+	m.requestOptions = options
+}
+
+// SetFeedRequestOptions replaces the per-feed request options, keyed by feed URL
+func (m *Manager) SetFeedRequestOptions(options map[string]config.RequestOptions) {
+	for _, feedOptions := range options {
+		for _, secret := range feedOptions.Secrets() {
+			logging.RegisterSecret(secret)
+		}
+	}
+
+	m.requestMutex.Lock()
+	defer m.requestMutex.Unlock()
+	m.feedRequestOptions = options
+}
+
+// requestOptionsForURL returns the global request options with the feed's
+// own options taking precedence
+func (m *Manager) requestOptionsForURL(feedURL string) config.RequestOptions {
+	m.requestMutex.RLock()
+	defer m.requestMutex.RUnlock()
+	return m.requestOptions.Merge(m.feedRequestOptions[feedURL])
+}
+
+// globalTransport returns the transport for requests that don't belong to a
+// feed, such as full articles and API lookups, using the global proxy
+func (m *Manager) globalTransport() http.RoundTripper {
+	m.requestMutex.RLock()
+	proxy := m.requestOptions.Proxy
+	m.requestMutex.RUnlock()
+	return m.transportForProxy(proxy)
+}
+
+// transportForProxy returns a shared transport for a proxy URL so connections
+// are reused between refreshes. Without a proxy the default transport, which
+// honors HTTP_PROXY and friends, is used.
+func (m *Manager) transportForProxy(proxy string) http.RoundTripper {
+	if proxy == "" {
+		return http.DefaultTransport
+	}
+
+	m.requestMutex.Lock()
+	defer m.requestMutex.Unlock()
+	if transport, ok := m.transports[proxy]; ok {
+		return transport
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	proxyURL, err := config.ParseProxyURL(proxy)
+	transport.Proxy = func(*http.Request) (*url.URL, error) {
+		// Fail the request rather than silently bypass an invalid proxy
+		return proxyURL, err
+	}
+	if m.transports == nil {
+		m.transports = make(map[string]http.RoundTripper)
+	}
+	m.transports[proxy] = transport
+	return transport
+}
+
 // parseCacheControl extracts max-age from Cache-Control header
 func parseCacheControl(cacheControl string) (maxAge int64, hasMaxAge bool) {
 	parts := strings.Split(cacheControl, ",")
@@ -96,6 +258,31 @@ func parseCacheControl(cacheControl string) (maxAge int64, hasMaxAge bool) {
 	return 0, false
 }
 
+// maxRetryAfter bounds how long a Retry-After header keeps a feed from being
+// fetched
+const maxRetryAfter = 24 * time.Hour
+
+// parseRetryAfter returns the time a Retry-After header, in seconds or as an
+// HTTP date, asks to wait until, at most maxRetryAfter from now
+func parseRetryAfter(value string, now time.Time) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	var until time.Time
+	if seconds, err := strconv.Atoi(value); err == nil {
+		until = now.Add(time.Duration(seconds) * time.Second)
+	} else if date, err := http.ParseTime(value); err == nil {
+		until = date
+	} else {
+		return time.Time{}, false
+	}
+	if !until.After(now) {
+		return time.Time{}, false
+	}
+	if limit := now.Add(maxRetryAfter); until.After(limit) {
+		until = limit
+	}
+	return until, true
+}
+
 func (m *Manager) ConvertHTMLToMarkdown(input string) string {
 	if input == "" {
 		return ""
@@ -123,20 +310,59 @@ func (m *Manager) ConvertHTMLToMarkdown(input string) string {
 	return strings.Join(cleanLines, "\n")
 }
 
-// addFeedTokenIfNeeded adds feed_token query parameter for GitHub/GitLab feeds if env vars are set
-func (m *Manager) addFeedTokenIfNeeded(feedURL string) string {
-	urlType := discovery.GetURLType(feedURL)
-
+// defaultFeedAuth returns the token auth of GitHub/GitLab feeds from the
+// environment, for feeds without credentials in the URLs file
+func defaultFeedAuth(urlType discovery.URLType) config.FeedAuth {
 	var token string
 	switch urlType {
 	case discovery.URLTypeGitHub:
 		token = os.Getenv("GITHUB_FEED_TOKEN")
 	case discovery.URLTypeGitLab:
 		token = os.Getenv("GITLAB_FEED_TOKEN")
-	default:
-		return feedURL
 	}
+	if token == "" {
+		return config.FeedAuth{}
+	}
+	return config.FeedAuth{Scheme: config.AuthToken, Secret: token}
+}
 
+// SetFeedAuths replaces the per-feed credentials, keyed by feed URL
+func (m *Manager) SetFeedAuths(auths map[string]config.FeedAuth) {
+	for _, auth := range auths {
+		for _, secret := range auth.Secrets() {
+			logging.RegisterSecret(secret)
+		}
+	}
+
+	m.feedAuthMutex.Lock()
+	defer m.feedAuthMutex.Unlock()
+	m.feedAuths = auths
+}
+
+// authForURL returns the credentials configured for a feed in the URLs file,
+// or the GitHub/GitLab token from the environment
+func (m *Manager) authForURL(feedURL string) config.FeedAuth {
+	m.feedAuthMutex.RLock()
+	auth, ok := m.feedAuths[feedURL]
+	m.feedAuthMutex.RUnlock()
+	if ok {
+		return auth
+	}
+	return defaultFeedAuth(discovery.GetURLType(feedURL))
+}
+
+// tokenForURL returns the feed token of a feed with token auth
+func (m *Manager) tokenForURL(feedURL string) string {
+	if auth := m.authForURL(feedURL); auth.Scheme == config.AuthToken {
+		return auth.Secret
+	}
+	return ""
+}
+
+// addFeedTokenIfNeeded adds feed_token query parameter for feeds with a token
+// in the URLs file and GitHub/GitLab feeds if env vars are set
+func (m *Manager) addFeedTokenIfNeeded(feedURL string) string {
+	token := m.tokenForURL(feedURL)
 	if token == "" {
 		return feedURL
 	}
@@ -164,6 +390,12 @@ func NewManager(db *sql.DB, queries *database.Queries) *Manager {
 		queries:          queries,
 		parser:           parser,
 		refreshCallbacks: make(map[int64]func(int64)),
+		rateLimits:       make(map[rateLimitKey]time.Time),
+		feedAuths:        make(map[string]config.FeedAuth),
+		transports:       make(map[string]http.RoundTripper),
+		notifier:         notify.NewNotifier(notify.Send),
+		iconSources:      make(map[int64]iconSource),
+		iconHosts:        make(map[string]bool),
 	}
 }
 
@@ -187,15 +419,15 @@ func (m *Manager) AddFeed(url string) error {
 
 	now := sql.NullTime{Time: time.Now(), Valid: true}
 
-	m.dbMutex.Lock()
+	m.writeMutex.Lock()
 	_, err = m.queries.CreateFeed(context.Background(), database.CreateFeedParams{
 		Url:         url,
-		Title:       feed.Title,
-		Description: feed.Description,
+		Title:       database.NormalizeText(feed.Title),
+		Description: database.NormalizeText(feed.Description),
 		LastUpdated: now,
 		Visible:     true,
 	})
-	m.dbMutex.Unlock()
+	m.writeMutex.Unlock()
 
 	if err != nil {
 		return err
@@ -207,8 +439,8 @@ func (m *Manager) AddFeed(url string) error {
 // AddFeedWithoutFetching adds a feed to the database without fetching its content
 // The feed title will be the URL until it's manually refreshed
 func (m *Manager) AddFeedWithoutFetching(url string) error {
-	m.dbMutex.Lock()
-	defer m.dbMutex.Unlock()
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
 
 	_, err := m.queries.CreateFeed(context.Background(), database.CreateFeedParams{
 		Url:         url,
@@ -223,32 +455,27 @@ func (m *Manager) AddFeedWithoutFetching(url string) error {
 
 // HideFeedByURL hides a feed by setting visible = false
 func (m *Manager) HideFeedByURL(url string) error {
-	m.dbMutex.Lock()
-	defer m.dbMutex.Unlock()
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
 
 	return m.queries.HideFeedByURL(context.Background(), url)
 }
 
 // ShowFeedByURL shows a feed by setting visible = true
 func (m *Manager) ShowFeedByURL(url string) error {
-	m.dbMutex.Lock()
-	defer m.dbMutex.Unlock()
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
 
 	return m.queries.ShowFeedByURL(context.Background(), url)
 }
 
 // GetAllFeeds returns all feeds (both visible and hidden)
 func (m *Manager) GetAllFeeds() ([]database.Feed, error) {
-	m.dbMutex.RLock()
-	defer m.dbMutex.RUnlock()
-
 	return m.queries.ListAllFeeds(context.Background())
 }
 
 func (m *Manager) RefreshFeedByURL(url string) error {
-	m.dbMutex.RLock()
 	feed, err := m.queries.GetFeedByURL(context.Background(), url)
-	m.dbMutex.RUnlock()
 	if err != nil {
 		return err
 	}
@@ -257,21 +484,42 @@ func (m *Manager) RefreshFeedByURL(url string) error {
 }
 
 func (m *Manager) RefreshFeed(feedID int64) error {
+	return m.refreshFeed(context.Background(), feedID, false)
+}
+
+// RefreshFeedContext refreshes a feed like RefreshFeed and gives up as soon
+// as ctx is cancelled. Giving up isn't recorded as an error of the feed. With
+// pushed set, because its hub pushed an update, the feed is fetched even
+// while its Cache-Control max-age says it hasn't changed.
+func (m *Manager) RefreshFeedContext(ctx context.Context, feedID int64, pushed bool) error {
+	return m.refreshFeed(ctx, feedID, pushed)
+}
+
+func (m *Manager) refreshFeed(ctx context.Context, feedID int64, pushed bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	var feed database.Feed
 
 	// Get feed with read lock
-	m.dbMutex.RLock()
 	feed, err := m.queries.GetFeed(context.Background(), feedID)
-	m.dbMutex.RUnlock()
 	if err != nil {
 		return err
 	}
 
+	// A rate limited feed waits for as long as its server asked
+	if feed.RetryAfter.Valid && time.Now().Before(feed.RetryAfter.Time) {
+		logging.DebugCategory(logging.CategoryHTTP, "Feed is rate limited, skipping fetch",
+			"url", feed.Url,
+			"retryAfter", feed.RetryAfter.Time)
+		return nil
+	}
+
 	// Check if feed is still within cache control max age period
-	if feed.CacheControlMaxAge.Valid && feed.LastUpdated.Valid {
+	if feed.CacheControlMaxAge.Valid && feed.LastUpdated.Valid && !pushed {
 		cacheExpiry := feed.LastUpdated.Time.Add(time.Duration(feed.CacheControlMaxAge.Int64) * time.Second)
 		if time.Now().Before(cacheExpiry) {
-			logging.Debug("Feed still within cache control period, skipping fetch",
+			logging.DebugCategory(logging.CategoryHTTP, "Feed still within cache control period, skipping fetch",
 				"url", feed.Url,
 				"lastUpdated", feed.LastUpdated.Time,
 				"maxAge", feed.CacheControlMaxAge.Int64,
@@ -280,27 +528,32 @@ func (m *Manager) RefreshFeed(feedID int64) error {
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), FeedTimeout)
+	requestCtx, cancel := context.WithTimeout(ctx, FeedTimeout)
 	defer cancel()
 
 	// Create HTTP client with conditional request support
 	client := m.createHTTPClientForFeed(feed.Url)
+	redirects := &redirectTracker{}
+	client.CheckRedirect = redirects.checkRedirect
 
 	// Build the request URL with feed token if needed
 	requestURL := m.addFeedTokenIfNeeded(feed.Url)
 
 	// Make the HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+	req, err := http.NewRequestWithContext(requestCtx, "GET", requestURL, nil)
 	if err != nil {
 		logging.Error("Error creating request", "url", feed.Url, "error", err)
-		m.recordFeedError(feedID, err)
+		m.refreshFailed(feed, err)
 		return err
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		logging.Error("Error fetching feed", "url", feed.Url, "error", err)
-		m.recordFeedError(feedID, err)
+		m.refreshFailed(feed, err)
 		return err
 	}
 	defer func() {
@@ -309,12 +562,13 @@ func (m *Manager) RefreshFeed(feedID int64) error {
 
 	// Handle 304 Not Modified - feed hasn't changed
 	if resp.StatusCode == http.StatusNotModified {
-		logging.Debug("Feed not modified", "url", feed.Url, "status", resp.StatusCode)
+		logging.DebugCategory(logging.CategoryHTTP, "Feed not modified", "url", feed.Url, "status", resp.StatusCode)
 		// Clear any previous error since we successfully connected
 		m.recordFeedError(feedID, nil)
+		m.feedMoved(feed, redirects.movedTo(resp, feed.Url, requestURL != feed.Url))
 		// Update last_updated to track that we checked
 		now := sql.NullTime{Time: time.Now(), Valid: true}
-		m.dbMutex.Lock()
+		m.writeMutex.Lock()
 		err = m.queries.UpdateFeed(context.Background(), database.UpdateFeedParams{
 			ID:                 feedID,
 			Title:              feed.Title,
@@ -324,17 +578,30 @@ func (m *Manager) RefreshFeed(feedID int64) error {
 			LastModified:       feed.LastModified,
 			CacheControlMaxAge: feed.CacheControlMaxAge,
 		})
-		m.dbMutex.Unlock()
+		if err == nil {
+			err = m.queries.SetFeedLastResult(context.Background(), database.SetFeedLastResultParams{
+				LastResult: fetchResult(resp, "not modified"),
+				ID:         feedID,
+			})
+		}
+		m.writeMutex.Unlock()
 		return err
 	}
 
 	// Check for HTTP error status codes (anything not 2xx)
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		err := fmt.Errorf("HTTP %d: %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+			if until, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				m.setRetryAfter(feedID, until)
+				err = fmt.Errorf("%w, cooling down until %s", err, until.Local().Format("15:04"))
+			}
+		}
 		logging.Error("HTTP error fetching feed", "url", feed.Url, "status", resp.StatusCode, "error", err)
-		m.recordFeedError(feedID, err)
+		m.refreshFailed(feed, err)
 		return err
 	}
+	m.feedMoved(feed, redirects.movedTo(resp, feed.Url, requestURL != feed.Url))
 
 	// Parse response headers
 	etag := sql.NullString{String: resp.Header.Get("ETag"), Valid: resp.Header.Get("ETag") != ""}
@@ -347,35 +614,111 @@ func (m *Manager) RefreshFeed(feedID int64) error {
 		}
 	}
 
+	// Read the whole feed, the WebSub hub it advertises is looked up in it too
+	body, err := io.ReadAll(newProgressReader(ctx, resp.Body, resp.ContentLength))
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		logging.Error("Error reading feed", "url", feed.Url, "error", err)
+		m.refreshFailed(feed, err)
+		return err
+	}
+
+	// Some servers ignore conditional requests and send the same feed again,
+	// an unchanged body is handled like 304 Not Modified
+	bodyHash := hashBody(body)
+	if bodyHash == feed.BodyHash {
+		logging.DebugCategory(logging.CategoryHTTP, "Feed unchanged, same body as the last fetch", "url", feed.Url)
+		m.recordFeedError(feedID, nil)
+		now := sql.NullTime{Time: time.Now(), Valid: true}
+		m.writeMutex.Lock()
+		err = m.queries.UpdateFeed(context.Background(), database.UpdateFeedParams{
+			ID:                 feedID,
+			Title:              feed.Title,
+			Description:        feed.Description,
+			LastUpdated:        now,
+			Etag:               etag,
+			LastModified:       lastModified,
+			CacheControlMaxAge: cacheControlMaxAge,
+		})
+		if err == nil {
+			err = m.queries.RecordUnchangedFetch(context.Background(), feedID)
+		}
+		if err == nil {
+			err = m.queries.SetFeedLastResult(context.Background(), database.SetFeedLastResultParams{
+				LastResult: fetchResult(resp, "same feed as the last fetch"),
+				ID:         feedID,
+			})
+		}
+		m.writeMutex.Unlock()
+		if err != nil {
+			return err
+		}
+		m.checkWebSub(feed, resp.Header, body)
+		return nil
+	}
+
 	// Parse the feed
-	parsedFeed, err := m.parser.Parse(resp.Body)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	parsedFeed, err := m.parser.Parse(bytes.NewReader(body))
 	if err != nil {
 		logging.Error("Error parsing feed", "url", feed.Url, "error", err)
-		m.recordFeedError(feedID, err)
+		m.refreshFailed(feed, err)
 		return err
 	}
 
+	// A feed's first refresh back-fills the older pages it links to
+	if !feed.LastUpdated.Valid {
+		parsedFeed.Items = mergeItems(parsedFeed.Items, m.fetchHistoryPages(ctx, client, requestURL, body))
+	}
+
 	// Clear any previous error since this fetch was successful
 	m.recordFeedError(feedID, nil)
+	m.recordIconSource(feedID, parsedFeed)
 
 	// Update feed with headers
 	now := sql.NullTime{Time: time.Now(), Valid: true}
-	m.dbMutex.Lock()
+	m.writeMutex.Lock()
 	err = m.queries.UpdateFeed(context.Background(), database.UpdateFeedParams{
 		ID:                 feedID,
-		Title:              parsedFeed.Title,
-		Description:        parsedFeed.Description,
+		Title:              database.NormalizeText(parsedFeed.Title),
+		Description:        database.NormalizeText(parsedFeed.Description),
 		LastUpdated:        now,
 		Etag:               etag,
 		LastModified:       lastModified,
 		CacheControlMaxAge: cacheControlMaxAge,
 	})
-	m.dbMutex.Unlock()
+	m.writeMutex.Unlock()
 	if err != nil {
 		return err
 	}
 
-	for _, item := range parsedFeed.Items {
+	// Every item of this fetch gets the same seen_at, pruning skips them.
+	// Whole seconds in UTC keep the stored values in order as text.
+	seenAt := sql.NullTime{Time: time.Now().UTC().Truncate(time.Second), Valid: true}
+
+	known := m.knownGUIDs(feed)
+
+	// Rules hide items, mark the new ones read or notify about them
+	itemRules := m.feedItemRules(feedID)
+	var existing map[string]bool
+	if len(itemRules) > 0 {
+		existing = m.itemGUIDSet(feedID)
+	}
+	var upserted, unread []database.Item
+	var ruledRead []int64
+	hidden := 0
+	for i, item := range parsedFeed.Items {
+		// The body hash isn't saved yet, so the next refresh saves the rest
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if i > 0 {
+			reportProgress(ctx, Progress{Stage: "saved", Done: int64(i), Total: int64(len(parsedFeed.Items)), Unit: "items"})
+		}
 		var published sql.NullTime
 		if item.PublishedParsed != nil {
 			published = sql.NullTime{Time: *item.PublishedParsed, Valid: true}
@@ -401,41 +744,159 @@ func (m *Manager) RefreshFeed(feedID int64) error {
 			}
 		}
 
-		// Use GUID if available, otherwise use Link as unique identifier
-		guid := item.GUID
-		if guid == "" {
-			guid = item.Link
+		guid := itemGUID(item)
+		ruleItem := rules.Item{Title: item.Title, Author: itemAuthor(item), Link: item.Link}
+		action, ruled := rules.Match(itemRules, feedID, ruleItem)
+		if ruled && action == rules.Hide {
+			hidden++
+			continue
 		}
 
 		// Upsert item
-		m.dbMutex.Lock()
-		_, err := m.queries.UpsertItem(context.Background(), database.UpsertItemParams{
-			FeedID:      feedID,
-			Guid:        guid,
-			Title:       item.Title,
-			Description: description,
-			Content:     content,
-			Link:        item.Link,
-			Published:   published,
+		m.writeMutex.Lock()
+		dbItem, err := m.queries.UpsertItem(context.Background(), database.UpsertItemParams{
+			FeedID:        feedID,
+			Guid:          guid,
+			Title:         database.NormalizeText(item.Title),
+			Description:   database.NormalizeText(description),
+			Content:       database.NormalizeText(content),
+			Link:          item.Link,
+			Published:     published,
+			SeenAt:        seenAt,
+			Author:        itemAuthor(item),
+			CanonicalLink: CanonicalLink(item.Link),
+			ContentHash:   ContentHash(content),
 		})
-		m.dbMutex.Unlock()
+		m.writeMutex.Unlock()
 		if err != nil {
 			logging.Error("Error upserting item", "guid", guid, "error", err)
+			continue
+		}
+		upserted = append(upserted, dbItem)
+		if ruled && action == rules.Read && !existing[guid] {
+			ruledRead = append(ruledRead, dbItem.ID)
+			continue
+		}
+		unread = append(unread, dbItem)
+		// Like other notifications, none for the items of a feed's first fetch
+		if highlight, ok := rules.HighlightFor(itemRules, feedID, ruleItem); ok && highlight.Notify && feed.LastUpdated.Valid && !existing[guid] {
+			m.notifier.AddHighlighted(feed.Title, dbItem.Title)
+		}
+	}
+	reportProgress(ctx, Progress{Stage: "saved", Done: int64(len(parsedFeed.Items)), Total: int64(len(parsedFeed.Items)), Unit: "items"})
+	logging.DebugCategory(logging.CategoryDB, "Items saved", "feedID", feedID, "items", len(upserted))
+	if hidden > 0 || len(ruledRead) > 0 {
+		logging.Info("Item rules applied", "url", feed.Url, "hidden", hidden, "markedRead", len(ruledRead))
+	}
+	if len(ruledRead) > 0 {
+		if err := m.MarkItemsRead(ruledRead); err != nil {
+			logging.Warn("Failed to mark items read by rules", "url", feed.Url, "error", err)
 		}
 	}
+	// Items a rule marked read aren't announced
+	m.fireNewItems(feed, known, unread)
 
+	// Download the linked articles for feeds that only publish summaries
+	if feed.FullText {
+		m.fetchMissingFullContent(ctx, upserted)
+	}
+
+	if policy := m.RetentionPolicy(); policy.Enabled() {
+		if _, err := m.PruneFeedItems(context.Background(), feedID, policy); err != nil {
+			logging.Warn("Failed to prune items", "url", feed.Url, "error", err)
+		}
+	}
+
+	// Saved last so a refresh that fails halfway doesn't make the next one skip the items
+	result := fmt.Sprintf("%d items", len(upserted))
+	if hidden > 0 {
+		result += fmt.Sprintf(", %d hidden by rules", hidden)
+	}
+	m.writeMutex.Lock()
+	err = m.queries.SetFeedBodyHash(context.Background(), database.SetFeedBodyHashParams{
+		BodyHash: bodyHash,
+		ID:       feedID,
+	})
+	if err == nil {
+		err = m.queries.SetFeedLastResult(context.Background(), database.SetFeedLastResultParams{
+			LastResult: fetchResult(resp, result),
+			ID:         feedID,
+		})
+	}
+	m.writeMutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	m.checkWebSub(feed, resp.Header, body)
 	return nil
 }
 
+// checkWebSub subscribes to the hub a feed advertises so updates are pushed
+// instead of waiting for the next reload
+func (m *Manager) checkWebSub(feed database.Feed, header http.Header, body []byte) {
+	hub, topic := discoverWebSub(header, body)
+	if hub == "" {
+		return
+	}
+	if topic == "" {
+		topic = feed.Url
+	}
+	if err := m.subscribeWebSub(context.Background(), feed.ID, hub, topic); err != nil {
+		logging.Warn("Failed to subscribe to WebSub hub", "url", feed.Url, "hub", hub, "error", err)
+	}
+}
+
+// fetchResult describes a successful fetch for feed info, e.g. "200 OK, 25
+// items"
+func fetchResult(resp *http.Response, outcome string) string {
+	return fmt.Sprintf("%d %s, %s", resp.StatusCode, http.StatusText(resp.StatusCode), outcome)
+}
+
+// hashBody identifies the content of a feed to notice when it didn't change
+func hashBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// itemGUID identifies an item within its feed, the GUID if available,
+// otherwise the link
+func itemGUID(item *gofeed.Item) string {
+	if item.GUID != "" {
+		return item.GUID
+	}
+	return item.Link
+}
+
+// itemAuthor returns the names of an item's authors, their email addresses
+// when a feed only has those
+func itemAuthor(item *gofeed.Item) string {
+	var names []string
+	for _, author := range item.Authors {
+		if author == nil {
+			continue
+		}
+		name := strings.TrimSpace(author.Name)
+		if name == "" {
+			name = strings.TrimSpace(author.Email)
+		}
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return database.NormalizeText(strings.Join(names, ", "))
+}
+
 func (m *Manager) RefreshAllFeeds() error {
-	m.dbMutex.RLock()
 	feeds, err := m.queries.ListFeeds(context.Background())
-	m.dbMutex.RUnlock()
 	if err != nil {
 		return err
 	}
 
 	for _, feed := range feeds {
+		if feed.Paused {
+			continue
+		}
 		if err := m.RefreshFeed(feed.ID); err != nil {
 			logging.Error("Error refreshing feed", "url", feed.Url, "error", err)
 		}
@@ -444,88 +905,340 @@ func (m *Manager) RefreshAllFeeds() error {
 	return nil
 }
 
+// SetFeedPaused pauses or resumes refreshing a feed
+func (m *Manager) SetFeedPaused(feedID int64, paused bool) error {
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
+	return m.queries.SetFeedPaused(context.Background(), database.SetFeedPausedParams{
+		Paused: paused,
+		ID:     feedID,
+	})
+}
+
+// PauseFeed keeps a feed in the list but skips it when feeds are reloaded
+func (m *Manager) PauseFeed(feedID int64) error {
+	return m.SetFeedPaused(feedID, true)
+}
+
+// ResumeFeed refreshes a paused feed again
+func (m *Manager) ResumeFeed(feedID int64) error {
+	return m.SetFeedPaused(feedID, false)
+}
+
+// ClearFeedCache forgets the ETag, Last-Modified, Cache-Control max-age and
+// body hash of a feed, so its next refresh fetches and parses it in full
+func (m *Manager) ClearFeedCache(feedID int64) error {
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
+	return m.queries.ClearFeedCache(context.Background(), feedID)
+}
+
+// SetFeedCustomTitle shows a feed with a title of its own instead of the
+// one it has, an empty title goes back to the feed's own on its next refresh
+func (m *Manager) SetFeedCustomTitle(feedID int64, title string) error {
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
+	return m.queries.SetFeedCustomTitle(context.Background(), database.SetFeedCustomTitleParams{
+		CustomTitle: title,
+		ID:          feedID,
+	})
+}
+
 func (m *Manager) GetFeedStats() ([]database.GetFeedStatsRow, error) {
-	m.dbMutex.RLock()
 	result, err := m.queries.GetFeedStats(context.Background())
-	m.dbMutex.RUnlock()
 	return result, err
 }
 
+// GetFeedStatsByID returns the stats row of one visible feed, to update it
+// in the feed list without loading every feed
+func (m *Manager) GetFeedStatsByID(feedID int64) (database.GetFeedStatsRow, error) {
+	row, err := m.queries.GetFeedStatsByID(context.Background(), feedID)
+	return database.GetFeedStatsRow(row), err
+}
+
+// GetFeedFolders returns the folders of every feed that has some
+func (m *Manager) GetFeedFolders() (map[int64][]string, error) {
+	rows, err := m.queries.GetAllFeedFolders(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	folders := make(map[int64][]string)
+	for _, row := range rows {
+		folders[row.FeedID] = append(folders[row.FeedID], row.FolderName)
+	}
+	return folders, nil
+}
+
 func (m *Manager) GetItemsWithReadStatus(feedID int64) ([]database.GetItemsWithReadStatusRow, error) {
-	m.dbMutex.RLock()
 	result, err := m.queries.GetItemsWithReadStatus(context.Background(), feedID)
-	m.dbMutex.RUnlock()
 	return result, err
 }
 
+// GetItemsWithReadStatusPage returns limit items of a feed after skipping
+// offset of them, newest first and the unread ones before the others with
+// unreadFirst. A negative limit returns all the rest.
+func (m *Manager) GetItemsWithReadStatusPage(feedID int64, unreadFirst bool, limit, offset int64) ([]database.GetItemsWithReadStatusRow, error) {
+	page, err := m.queries.GetItemsWithReadStatusPage(context.Background(), database.GetItemsWithReadStatusPageParams{FeedID: feedID, UnreadFirst: unreadFirst, Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]database.GetItemsWithReadStatusRow, len(page))
+	for i, item := range page {
+		items[i] = database.GetItemsWithReadStatusRow(item)
+	}
+	return items, nil
+}
+
+// GetAllItemsWithReadStatus returns the items of all visible feeds, newest first
+func (m *Manager) GetAllItemsWithReadStatus() ([]database.GetItemsWithReadStatusRow, error) {
+	all, err := m.queries.GetAllItemsWithReadStatus(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]database.GetItemsWithReadStatusRow, len(all))
+	for i, item := range all {
+		items[i] = database.GetItemsWithReadStatusRow(item)
+	}
+	return items, nil
+}
+
+// GetAllItemHeaders returns the items of all visible feeds without their
+// description and content, which is much less to read when items are only
+// counted
+func (m *Manager) GetAllItemHeaders() ([]database.GetItemsWithReadStatusRow, error) {
+	all, err := m.queries.GetAllItemHeaders(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]database.GetItemsWithReadStatusRow, len(all))
+	for i, item := range all {
+		items[i] = database.GetItemsWithReadStatusRow{
+			ID:        item.ID,
+			FeedID:    item.FeedID,
+			Title:     item.Title,
+			Link:      item.Link,
+			Published: item.Published,
+			CreatedAt: item.CreatedAt,
+			Starred:   item.Starred,
+			Read:      item.Read,
+		}
+	}
+	return items, nil
+}
+
 func (m *Manager) SearchFeedsByTitle(pattern string) ([]database.SearchFeedsByTitleRow, error) {
-	m.dbMutex.RLock()
 	result, err := m.queries.SearchFeedsByTitle(context.Background(), sql.NullString{String: pattern, Valid: true})
-	m.dbMutex.RUnlock()
 	return result, err
 }
 
+// SearchFeedsGlobally finds feeds whose title or description contains pattern
+// or that have items matching it in the full-text index
 func (m *Manager) SearchFeedsGlobally(pattern string) ([]database.SearchFeedsGloballyRow, error) {
-	m.dbMutex.RLock()
-	nullPattern := sql.NullString{String: pattern, Valid: true}
+	match := database.MatchQuery(pattern)
+	if match == "" {
+		return nil, nil
+	}
 	result, err := m.queries.SearchFeedsGlobally(context.Background(), database.SearchFeedsGloballyParams{
-		Column1: nullPattern,
-		Column2: nullPattern,
-		Column3: nullPattern,
-		Column4: nullPattern,
-		Column5: nullPattern,
+		Match:   match,
+		Pattern: sql.NullString{String: pattern, Valid: true},
 	})
-	m.dbMutex.RUnlock()
 	return result, err
 }
 
 func (m *Manager) SearchItemsByTitle(feedID int64, pattern string) ([]database.SearchItemsByTitleRow, error) {
-	m.dbMutex.RLock()
 	result, err := m.queries.SearchItemsByTitle(context.Background(), database.SearchItemsByTitleParams{
 		FeedID:  feedID,
 		Column2: sql.NullString{String: pattern, Valid: true},
 	})
-	m.dbMutex.RUnlock()
 	return result, err
 }
 
+// SearchItemsGlobally finds the items of a feed matching pattern in the
+// full-text index, best matches first
 func (m *Manager) SearchItemsGlobally(feedID int64, pattern string) ([]database.SearchItemsGloballyRow, error) {
-	m.dbMutex.RLock()
-	nullPattern := sql.NullString{String: pattern, Valid: true}
+	match := database.MatchQuery(pattern)
+	if match == "" {
+		return nil, nil
+	}
 	result, err := m.queries.SearchItemsGlobally(context.Background(), database.SearchItemsGloballyParams{
-		FeedID:  feedID,
-		Column2: nullPattern,
-		Column3: nullPattern,
-		Column4: nullPattern,
+		FeedID: feedID,
+		Match:  match,
 	})
-	m.dbMutex.RUnlock()
 	return result, err
 }
 
+// GetItem returns an item by its ID
+func (m *Manager) GetItem(itemID int64) (database.Item, error) {
+	return m.queries.GetItem(context.Background(), itemID)
+}
+
 func (m *Manager) MarkItemRead(itemID int64) error {
-	m.dbMutex.Lock()
-	defer m.dbMutex.Unlock()
-	return m.queries.MarkItemRead(context.Background(), itemID)
+	if err := m.markItemRead(itemID); err != nil {
+		return err
+	}
+	m.fireItemRead(itemID)
+	return nil
+}
+
+// markItemRead marks an item read and records a read event for the reading
+// statistics when it wasn't read yet
+func (m *Manager) markItemRead(itemID int64) error {
+	ctx := context.Background()
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	queries := m.queries.WithTx(tx)
+	if err := queries.CreateReadEvent(ctx, itemID); err != nil {
+		return err
+	}
+	if err := queries.MarkItemRead(ctx, itemID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// MarkItemsRead marks several items as read at once, like marking a whole
+// feed read it doesn't run item-read hooks
+func (m *Manager) MarkItemsRead(itemIDs []int64) error {
+	ctx := context.Background()
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
+	for _, itemID := range itemIDs {
+		if err := m.queries.MarkItemRead(ctx, itemID); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (m *Manager) MarkItemUnread(itemID int64) error {
-	m.dbMutex.Lock()
+	m.writeMutex.Lock()
 	err := m.queries.MarkItemUnread(context.Background(), itemID)
-	m.dbMutex.Unlock()
+	m.writeMutex.Unlock()
 	return err
 }
 
-func (m *Manager) MarkAllItemsReadInFeed(feedID int64) error {
-	m.dbMutex.Lock()
-	err := m.queries.MarkAllItemsReadInFeed(context.Background(), feedID)
-	m.dbMutex.Unlock()
+// SetItemStarred stars or unstars an item
+func (m *Manager) SetItemStarred(itemID int64, starred bool) error {
+	m.writeMutex.Lock()
+	err := m.queries.SetItemStarred(context.Background(), database.SetItemStarredParams{
+		Starred: starred,
+		ID:      itemID,
+	})
+	m.writeMutex.Unlock()
 	return err
 }
 
+// GetStarredItems returns the starred items of all feeds, newest first
+func (m *Manager) GetStarredItems() ([]database.GetItemsWithReadStatusRow, error) {
+	starred, err := m.queries.GetStarredItems(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]database.GetItemsWithReadStatusRow, len(starred))
+	for i, item := range starred {
+		items[i] = database.GetItemsWithReadStatusRow(item)
+	}
+	return items, nil
+}
+
+// GetAllItemsWithReadStatusPage returns a page of the items of all visible
+// feeds like GetItemsWithReadStatusPage does for one feed
+func (m *Manager) GetAllItemsWithReadStatusPage(unreadFirst bool, limit, offset int64) ([]database.GetItemsWithReadStatusRow, error) {
+	page, err := m.queries.GetAllItemsWithReadStatusPage(context.Background(), database.GetAllItemsWithReadStatusPageParams{UnreadFirst: unreadFirst, Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]database.GetItemsWithReadStatusRow, len(page))
+	for i, item := range page {
+		items[i] = database.GetItemsWithReadStatusRow(item)
+	}
+	return items, nil
+}
+
+// GetStarredItemsPage returns a page of the starred items like
+// GetItemsWithReadStatusPage does for one feed
+func (m *Manager) GetStarredItemsPage(unreadFirst bool, limit, offset int64) ([]database.GetItemsWithReadStatusRow, error) {
+	page, err := m.queries.GetStarredItemsPage(context.Background(), database.GetStarredItemsPageParams{UnreadFirst: unreadFirst, Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]database.GetItemsWithReadStatusRow, len(page))
+	for i, item := range page {
+		items[i] = database.GetItemsWithReadStatusRow(item)
+	}
+	return items, nil
+}
+
+// GetStarredStats returns how many items are starred and how many of them are unread
+func (m *Manager) GetStarredStats() (database.GetStarredStatsRow, error) {
+	result, err := m.queries.GetStarredStats(context.Background())
+	return result, err
+}
+
+// GetUnreadFeedIDs returns the feeds with unread items
+func (m *Manager) GetUnreadFeedIDs() ([]int64, error) {
+	return m.queries.GetUnreadFeedIDs(context.Background())
+}
+
+// MarkAllItemsReadInFeed marks every item of a feed as read and returns the
+// ones that were unread, so it can be undone with MarkItemsUnread
+func (m *Manager) MarkAllItemsReadInFeed(feedID int64) ([]int64, error) {
+	ctx := context.Background()
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
+
+	unread, err := m.queries.GetUnreadItemIDsInFeed(ctx, feedID)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.queries.MarkAllItemsReadInFeed(ctx, feedID); err != nil {
+		return nil, err
+	}
+	return unread, nil
+}
+
+// MarkItemsUnread marks items as unread again, in one transaction
+func (m *Manager) MarkItemsUnread(itemIDs []int64) error {
+	ctx := context.Background()
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	queries := m.queries.WithTx(tx)
+	for _, itemID := range itemIDs {
+		if err := queries.MarkItemUnread(ctx, itemID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
 func (m *Manager) DeleteFeed(feedID int64) error {
-	m.dbMutex.Lock()
+	m.writeMutex.Lock()
 	err := m.queries.DeleteFeed(context.Background(), feedID)
-	m.dbMutex.Unlock()
+	m.writeMutex.Unlock()
 	return err
 }
 
@@ -641,32 +1354,116 @@ func (m *Manager) AddLinkMarkersToHTML(content string) (string, []string) {
 	return result, links
 }
 
-func (m *Manager) GetLogMessages(limit int64) ([]LogMessage, error) {
-	m.dbMutex.RLock()
-	result, err := m.queries.GetLogMessages(context.Background(), limit)
-	m.dbMutex.RUnlock()
+// GetLogMessages returns a page of log messages, newest first
+func (m *Manager) GetLogMessages(limit, offset int64) ([]LogMessage, error) {
+	result, err := m.queries.GetLogMessages(context.Background(), database.GetLogMessagesParams{Limit: limit, Offset: offset})
 	return result, err
 }
 
+// CountLogMessages returns how many log messages are stored
+func (m *Manager) CountLogMessages() (int64, error) {
+	count, err := m.queries.CountLogMessages(context.Background())
+	return count, err
+}
+
+// LogFilter picks the log messages the log view shows
+type LogFilter struct {
+	HiddenLevels []string // Levels left out, as stored: DEBUG, INFO, WARN or ERROR
+	Search       string   // Only messages whose text or error contains this, ignoring case
+	FeedID       int64    // Only messages about this feed, when not 0
+	FeedURL      string   // URL of the feed, for messages that only log its URL
+}
+
+// Active reports whether the filter leaves out any messages
+func (f LogFilter) Active() bool {
+	return len(f.HiddenLevels) > 0 || f.Search != "" || f.FeedID != 0
+}
+
+func (f LogFilter) hiddenLevels() string {
+	if len(f.HiddenLevels) == 0 {
+		return ""
+	}
+	return "," + strings.Join(f.HiddenLevels, ",") + ","
+}
+
+// GetFilteredLogMessages returns a page of the log messages matching a
+// filter, newest first
+func (m *Manager) GetFilteredLogMessages(filter LogFilter, limit, offset int64) ([]LogMessage, error) {
+	return m.queries.GetFilteredLogMessages(context.Background(), database.GetFilteredLogMessagesParams{
+		HiddenLevels: filter.hiddenLevels(),
+		Search:       filter.Search,
+		FeedID:       filter.FeedID,
+		FeedUrl:      logging.Redact(filter.FeedURL),
+		Limit:        limit,
+		Offset:       offset,
+	})
+}
+
+// CountFilteredLogMessages returns how many log messages match a filter
+func (m *Manager) CountFilteredLogMessages(filter LogFilter) (int64, error) {
+	return m.queries.CountFilteredLogMessages(context.Background(), database.CountFilteredLogMessagesParams{
+		HiddenLevels: filter.hiddenLevels(),
+		Search:       filter.Search,
+		FeedID:       filter.FeedID,
+		FeedUrl:      logging.Redact(filter.FeedURL),
+	})
+}
+
+// LogStorage returns how many log messages are stored and roughly how many
+// bytes of the database they take up
+func (m *Manager) LogStorage() (messages, bytes int64, err error) {
+	storage, err := m.queries.GetLogStorage(context.Background())
+	return storage.Messages, storage.Bytes, err
+}
+
 func (m *Manager) GetLogMessage(id int64) (LogMessage, error) {
-	m.dbMutex.RLock()
 	result, err := m.queries.GetLogMessage(context.Background(), id)
-	m.dbMutex.RUnlock()
 	return result, err
 }
 
 func (m *Manager) DeleteAllLogMessages() error {
-	m.dbMutex.Lock()
-	defer m.dbMutex.Unlock()
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
 	return m.queries.DeleteAllLogMessages(context.Background())
 }
 
+// PruneLogMessages deletes the log messages older than maxAgeDays and those
+// beyond the newest maxMessages, a limit of 0 doesn't apply
+func (m *Manager) PruneLogMessages(ctx context.Context, maxMessages, maxAgeDays int) (int64, error) {
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
+	return m.queries.PruneLogMessages(ctx, database.PruneLogMessagesParams{
+		MaxAgeDays:  int64(maxAgeDays),
+		MaxMessages: int64(maxMessages),
+	})
+}
+
+// setRetryAfter keeps the feed from being fetched before until
+func (m *Manager) setRetryAfter(feedID int64, until time.Time) {
+	m.writeMutex.Lock()
+	err := m.queries.SetFeedRetryAfter(context.Background(), database.SetFeedRetryAfterParams{
+		ID:         feedID,
+		RetryAfter: sql.NullTime{Time: until, Valid: true},
+	})
+	m.writeMutex.Unlock()
+	if err != nil {
+		logging.Error("Failed to save feed retry time", "feedID", feedID, "error", err)
+	}
+}
+
+// refreshFailed records the error of a refresh and runs the
+// feed-refresh-failed hooks
+func (m *Manager) refreshFailed(feed database.Feed, err error) {
+	m.recordFeedError(feed.ID, err)
+	m.fireRefreshFailed(feed, err)
+}
+
 func (m *Manager) recordFeedError(feedID int64, err error) {
 	if err == nil {
 		// Clear any previous error
-		m.dbMutex.Lock()
+		m.writeMutex.Lock()
 		retryErr := m.queries.ClearFeedError(context.Background(), feedID)
-		m.dbMutex.Unlock()
+		m.writeMutex.Unlock()
 		if retryErr != nil {
 			logging.Error("Failed to clear feed error", "feedID", feedID, "error", retryErr)
 		}
@@ -675,15 +1472,15 @@ func (m *Manager) recordFeedError(feedID int64, err error) {
 
 	// Record the error
 	now := sql.NullTime{Time: time.Now(), Valid: true}
-	errorText := sql.NullString{String: err.Error(), Valid: true}
+	errorText := sql.NullString{String: logging.Redact(err.Error()), Valid: true}
 
-	m.dbMutex.Lock()
+	m.writeMutex.Lock()
 	retryErr := m.queries.UpdateFeedError(context.Background(), database.UpdateFeedErrorParams{
 		ID:            feedID,
 		LastError:     errorText,
 		LastErrorTime: now,
 	})
-	m.dbMutex.Unlock()
+	m.writeMutex.Unlock()
 	if retryErr != nil {
 		logging.Error("Failed to update feed error", "feedID", feedID, "error", retryErr)
 	}
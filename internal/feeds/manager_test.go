@@ -1,9 +1,19 @@
 package feeds
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"slices"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/database"
 )
 
 func TestAddLinkMarkersToHTML(t *testing.T) {
@@ -162,3 +172,510 @@ func TestExtractLinks(t *testing.T) {
 		})
 	}
 }
+
+func TestAddFeedTokenIfNeeded(t *testing.T) {
+	t.Setenv("GITHUB_FEED_TOKEN", "env-token")
+	t.Setenv("GITLAB_FEED_TOKEN", "")
+
+	manager := &Manager{}
+	manager.SetFeedAuths(map[string]config.FeedAuth{
+		"https://github.com/work/repo/commits/main.atom": {Scheme: config.AuthToken, Secret: "work-token"},
+		"https://git.example.com/me/repo.atom":           {Scheme: config.AuthToken, Secret: "self-hosted"},
+	})
+
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"https://github.com/work/repo/commits/main.atom", "https://github.com/work/repo/commits/main.atom?feed_token=work-token"},
+		{"https://github.com/me/repo/commits/main.atom", "https://github.com/me/repo/commits/main.atom?feed_token=env-token"},
+		{"https://git.example.com/me/repo.atom", "https://git.example.com/me/repo.atom?feed_token=self-hosted"},
+		{"https://gitlab.com/me/repo/-/commits/main?format=atom", "https://gitlab.com/me/repo/-/commits/main?format=atom"},
+		{"https://example.com/feed.xml", "https://example.com/feed.xml"},
+	}
+
+	for _, tt := range tests {
+		if got := manager.addFeedTokenIfNeeded(tt.url); got != tt.expected {
+			t.Errorf("addFeedTokenIfNeeded(%q) = %q, want %q", tt.url, got, tt.expected)
+		}
+	}
+}
+
+func TestFeedRequestOptions(t *testing.T) {
+	var redirected http.Header
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = r.Header.Clone()
+	}))
+	defer other.Close()
+
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		http.Redirect(w, r, other.URL, http.StatusFound)
+	}))
+	defer server.Close()
+
+	feedURL := server.URL + "/feed.xml"
+	manager := &Manager{}
+	manager.SetRequestOptions(config.RequestOptions{
+		UserAgent: "Global/1.0",
+		Headers:   http.Header{"Accept-Language": {"en"}},
+	})
+	manager.SetFeedRequestOptions(map[string]config.RequestOptions{
+		feedURL: {Headers: http.Header{"Authorization": {"Bearer secret"}}},
+	})
+
+	client := manager.createHTTPClientForFeed(feedURL)
+	// There is no database to look up conditional request headers in
+	client.Transport.(*conditionalRequestTransport).Manager = nil
+
+	resp, err := client.Get(feedURL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if received.Get("User-Agent") != "Global/1.0" || received.Get("Accept-Language") != "en" || received.Get("Authorization") != "Bearer secret" {
+		t.Errorf("feed request headers = %v", received)
+	}
+	// Headers meant for the feed don't follow a redirect to another host
+	if redirected.Get("Authorization") != "" || redirected.Get("Accept-Language") != "" {
+		t.Errorf("redirected request headers = %v", redirected)
+	}
+}
+
+func TestFeedRequestAuth(t *testing.T) {
+	var received []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Clone())
+	}))
+	defer server.Close()
+
+	basicURL := server.URL + "/basic.xml"
+	bearerURL := server.URL + "/bearer.xml"
+	manager := &Manager{}
+	manager.SetFeedAuths(map[string]config.FeedAuth{
+		basicURL:  {Scheme: config.AuthBasic, Username: "me", Secret: "pass"},
+		bearerURL: {Scheme: config.AuthBearer, Secret: "abc"},
+	})
+
+	for _, feedURL := range []string{basicURL, bearerURL, server.URL + "/open.xml"} {
+		client := manager.createHTTPClientForFeed(feedURL)
+		client.Transport.(*conditionalRequestTransport).Manager = nil
+		resp, err := client.Get(feedURL)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	want := []string{"Basic bWU6cGFzcw==", "Bearer abc", ""}
+	for i, header := range received {
+		if got := header.Get("Authorization"); got != want[i] {
+			t.Errorf("request %d Authorization = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestFeedRequestInvalidProxy(t *testing.T) {
+	manager := &Manager{}
+	manager.SetFeedRequestOptions(map[string]config.RequestOptions{
+		"http://example.invalid/feed.xml": {Proxy: "ftp://proxy"},
+	})
+
+	// An invalid proxy fails the request instead of connecting directly
+	client := manager.createHTTPClientForFeed("http://example.invalid/feed.xml")
+	client.Transport.(*conditionalRequestTransport).Manager = nil
+	_, err := client.Get("http://example.invalid/feed.xml")
+	if err == nil || !strings.Contains(err.Error(), "invalid proxy") {
+		t.Errorf("expected an invalid proxy error, got %v", err)
+	}
+}
+
+func TestPruneLogMessages(t *testing.T) {
+	db, queries := openTestDB(t)
+	ctx := context.Background()
+	m := NewManager(db, queries)
+
+	addLog := func(message string, age time.Duration) {
+		t.Helper()
+		err := queries.CreateLogMessage(ctx, database.CreateLogMessageParams{
+			Level:     "INFO",
+			Message:   message,
+			Timestamp: sql.NullTime{Time: time.Now().Add(-age), Valid: true},
+		})
+		if err != nil {
+			t.Fatalf("CreateLogMessage() error = %v", err)
+		}
+	}
+	addLog("old", 40*24*time.Hour)
+	for i := 0; i < 5; i++ {
+		addLog("recent", time.Duration(5-i)*time.Minute)
+	}
+
+	pruned, err := m.PruneLogMessages(ctx, 0, 30)
+	if err != nil {
+		t.Fatalf("PruneLogMessages() error = %v", err)
+	}
+	if pruned != 1 {
+		t.Errorf("pruned %d messages older than 30 days, want 1", pruned)
+	}
+
+	pruned, err = m.PruneLogMessages(ctx, 3, 30)
+	if err != nil {
+		t.Fatalf("PruneLogMessages() error = %v", err)
+	}
+	if pruned != 2 {
+		t.Errorf("pruned %d messages beyond the newest 3, want 2", pruned)
+	}
+
+	// Pages are newest first
+	total, err := m.CountLogMessages()
+	if err != nil {
+		t.Fatalf("CountLogMessages() error = %v", err)
+	}
+	if total != 3 {
+		t.Fatalf("%d messages left, want 3", total)
+	}
+	page, err := m.GetLogMessages(2, 2)
+	if err != nil {
+		t.Fatalf("GetLogMessages() error = %v", err)
+	}
+	if len(page) != 1 || page[0].Timestamp.Time.After(time.Now().Add(-2*time.Minute)) {
+		t.Errorf("second page = %+v, want only the oldest remaining message", page)
+	}
+
+	messages, bytes, err := m.LogStorage()
+	if err != nil {
+		t.Fatalf("LogStorage() error = %v", err)
+	}
+	if messages != 3 || bytes < int64(3*len("INFOrecent")) {
+		t.Errorf("LogStorage() = %d messages, %d bytes, want 3 messages of at least %d bytes", messages, bytes, 3*len("INFOrecent"))
+	}
+}
+
+func TestFilteredLogMessages(t *testing.T) {
+	db, queries := openTestDB(t)
+	ctx := context.Background()
+	m := NewManager(db, queries)
+
+	addLog := func(level, message, attributes string) {
+		t.Helper()
+		err := queries.CreateLogMessage(ctx, database.CreateLogMessageParams{
+			Level:      level,
+			Message:    message,
+			Timestamp:  sql.NullTime{Time: time.Now(), Valid: true},
+			Attributes: sql.NullString{String: attributes, Valid: attributes != ""},
+		})
+		if err != nil {
+			t.Fatalf("CreateLogMessage() error = %v", err)
+		}
+	}
+	addLog("DEBUG", "Fetching feed", `{"url":"https://example.com/feed.xml"}`)
+	addLog("INFO", "Feed refreshed", `{"feedID":7}`)
+	addLog("ERROR", "Failed to refresh feed", `{"feed_id":7,"error":"HTTP 503: Service Unavailable"}`)
+	addLog("WARN", "Slow response", `{"feedID":8}`)
+	addLog("INFO", "Started", "")
+
+	tests := []struct {
+		name   string
+		filter LogFilter
+		want   []string
+	}{
+		{"everything", LogFilter{}, []string{"Started", "Slow response", "Failed to refresh feed", "Feed refreshed", "Fetching feed"}},
+		{"hidden levels", LogFilter{HiddenLevels: []string{"DEBUG", "INFO"}}, []string{"Slow response", "Failed to refresh feed"}},
+		{"search in message", LogFilter{Search: "FEED"}, []string{"Failed to refresh feed", "Feed refreshed", "Fetching feed"}},
+		{"search in error", LogFilter{Search: "unavailable"}, []string{"Failed to refresh feed"}},
+		{"feed", LogFilter{FeedID: 7, FeedURL: "https://example.com/feed.xml"}, []string{"Failed to refresh feed", "Feed refreshed", "Fetching feed"}},
+		{"feed and level", LogFilter{FeedID: 7, HiddenLevels: []string{"ERROR"}}, []string{"Feed refreshed"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages, err := m.GetFilteredLogMessages(tt.filter, 100, 0)
+			if err != nil {
+				t.Fatalf("GetFilteredLogMessages() error = %v", err)
+			}
+			var got []string
+			for _, message := range messages {
+				got = append(got, message.Message)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("messages = %q, want %q", got, tt.want)
+			}
+			count, err := m.CountFilteredLogMessages(tt.filter)
+			if err != nil {
+				t.Fatalf("CountFilteredLogMessages() error = %v", err)
+			}
+			if count != int64(len(tt.want)) {
+				t.Errorf("count = %d, want %d", count, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestMarkAllItemsReadInFeedUndo(t *testing.T) {
+	db, queries := openTestDB(t)
+	ctx := context.Background()
+	m := NewManager(db, queries)
+
+	feed, err := queries.CreateFeed(ctx, database.CreateFeedParams{Url: "https://example.com/feed.xml", Title: "Example"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	var ids []int64
+	for _, guid := range []string{"a", "b", "c"} {
+		item, err := queries.UpsertItem(ctx, database.UpsertItemParams{FeedID: feed.ID, Guid: guid, Title: guid})
+		if err != nil {
+			t.Fatalf("UpsertItem() error = %v", err)
+		}
+		ids = append(ids, item.ID)
+	}
+	if err := m.MarkItemRead(ids[0]); err != nil {
+		t.Fatalf("MarkItemRead() error = %v", err)
+	}
+
+	// Only the items that were unread are returned
+	marked, err := m.MarkAllItemsReadInFeed(feed.ID)
+	if err != nil {
+		t.Fatalf("MarkAllItemsReadInFeed() error = %v", err)
+	}
+	if len(marked) != 2 || marked[0] == ids[0] || marked[1] == ids[0] {
+		t.Fatalf("MarkAllItemsReadInFeed() = %v, want %v", marked, ids[1:])
+	}
+
+	if err := m.MarkItemsUnread(marked); err != nil {
+		t.Fatalf("MarkItemsUnread() error = %v", err)
+	}
+	items, err := m.GetItemsWithReadStatus(feed.ID)
+	if err != nil {
+		t.Fatalf("GetItemsWithReadStatus() error = %v", err)
+	}
+	for _, item := range items {
+		if want := item.ID == ids[0]; item.Read != want {
+			t.Errorf("item %s read = %v, want %v", item.Guid, item.Read, want)
+		}
+	}
+}
+
+func TestGetUnreadFeedIDs(t *testing.T) {
+	db, queries := openTestDB(t)
+	ctx := context.Background()
+	m := NewManager(db, queries)
+
+	var feedIDs, itemIDs []int64
+	for _, name := range []string{"read", "unread", "hidden"} {
+		feed, err := queries.CreateFeed(ctx, database.CreateFeedParams{Url: "https://" + name + ".example.com/feed", Title: name, Visible: name != "hidden"})
+		if err != nil {
+			t.Fatalf("CreateFeed() error = %v", err)
+		}
+		item, err := queries.UpsertItem(ctx, database.UpsertItemParams{FeedID: feed.ID, Guid: name, Title: name})
+		if err != nil {
+			t.Fatalf("UpsertItem() error = %v", err)
+		}
+		feedIDs = append(feedIDs, feed.ID)
+		itemIDs = append(itemIDs, item.ID)
+	}
+	if err := m.MarkItemRead(itemIDs[0]); err != nil {
+		t.Fatalf("MarkItemRead() error = %v", err)
+	}
+
+	// Hidden feeds aren't in the feed list to jump to
+	unread, err := m.GetUnreadFeedIDs()
+	if err != nil {
+		t.Fatalf("GetUnreadFeedIDs() error = %v", err)
+	}
+	if len(unread) != 1 || unread[0] != feedIDs[1] {
+		t.Errorf("GetUnreadFeedIDs() = %v, want [%d]", unread, feedIDs[1])
+	}
+}
+
+func TestGetItemsWithReadStatusPage(t *testing.T) {
+	db, queries := openTestDB(t)
+	ctx := context.Background()
+	m := NewManager(db, queries)
+
+	feed, err := queries.CreateFeed(ctx, database.CreateFeedParams{Url: "https://example.com/feed.xml", Title: "Example", Visible: true})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	// Items published at the same time still page in a fixed order
+	published := sql.NullTime{Time: time.Now(), Valid: true}
+	var ids []int64
+	for i := range 5 {
+		item, err := queries.UpsertItem(ctx, database.UpsertItemParams{FeedID: feed.ID, Guid: fmt.Sprint(i), Title: fmt.Sprint(i), Published: published})
+		if err != nil {
+			t.Fatalf("UpsertItem() error = %v", err)
+		}
+		ids = append(ids, item.ID)
+	}
+
+	var guids []string
+	for offset := int64(0); offset < 5; offset += 2 {
+		page, err := m.GetItemsWithReadStatusPage(feed.ID, false, 2, offset)
+		if err != nil {
+			t.Fatalf("GetItemsWithReadStatusPage() error = %v", err)
+		}
+		for _, item := range page {
+			guids = append(guids, item.Guid)
+		}
+	}
+	if got := strings.Join(guids, ""); got != "43210" {
+		t.Errorf("pages returned items %q, want %q", got, "43210")
+	}
+
+	// Unread items come first, a negative limit returns the rest
+	if err := m.MarkItemRead(ids[4]); err != nil {
+		t.Fatalf("MarkItemRead() error = %v", err)
+	}
+	rest, err := m.GetAllItemsWithReadStatusPage(true, -1, 1)
+	if err != nil {
+		t.Fatalf("GetAllItemsWithReadStatusPage() error = %v", err)
+	}
+	guids = nil
+	for _, item := range rest {
+		guids = append(guids, item.Guid)
+	}
+	if got := strings.Join(guids, ""); got != "2104" {
+		t.Errorf("GetAllItemsWithReadStatusPage(true, -1, 1) returned items %q, want %q", got, "2104")
+	}
+}
+
+func TestRefreshFeedSavesAuthor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		_, _ = w.Write([]byte(`<?xml version="1.0"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/"><channel><title>Example</title>
+<item><title>Signed</title><guid>signed</guid><dc:creator>Ada Lovelace</dc:creator></item>
+<item><title>Unsigned</title><guid>unsigned</guid></item>
+</channel></rss>`))
+	}))
+	defer server.Close()
+
+	db, queries := openTestDB(t)
+	ctx := context.Background()
+	feed, err := queries.CreateFeed(ctx, database.CreateFeedParams{Url: server.URL, Title: "Example"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	if err := NewManager(db, queries).RefreshFeed(feed.ID); err != nil {
+		t.Fatalf("RefreshFeed() error = %v", err)
+	}
+
+	items, err := queries.ListItemsByFeed(ctx, feed.ID)
+	if err != nil {
+		t.Fatalf("ListItemsByFeed() error = %v", err)
+	}
+	authors := make(map[string]string)
+	for _, item := range items {
+		authors[item.Guid] = item.Author
+	}
+	if authors["signed"] != "Ada Lovelace" || authors["unsigned"] != "" {
+		t.Errorf("authors = %v, want Ada Lovelace for the signed item only", authors)
+	}
+}
+
+func TestRefreshFeedKeepsCustomTitle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		_, _ = w.Write([]byte(`<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Example</title>
+<item><title>First</title><guid>first</guid></item>
+</channel></rss>`))
+	}))
+	defer server.Close()
+
+	db, queries := openTestDB(t)
+	ctx := context.Background()
+	feed, err := queries.CreateFeed(ctx, database.CreateFeedParams{Url: server.URL})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	m := NewManager(db, queries)
+	titleAfterRefresh := func() string {
+		t.Helper()
+		if err := m.RefreshFeed(feed.ID); err != nil {
+			t.Fatalf("RefreshFeed() error = %v", err)
+		}
+		got, err := queries.GetFeed(ctx, feed.ID)
+		if err != nil {
+			t.Fatalf("GetFeed() error = %v", err)
+		}
+		return got.Title
+	}
+
+	if title := titleAfterRefresh(); title != "Example" {
+		t.Fatalf("title = %q, want the feed's own", title)
+	}
+	if err := m.SetFeedCustomTitle(feed.ID, "Mine"); err != nil {
+		t.Fatalf("SetFeedCustomTitle() error = %v", err)
+	}
+	if title := titleAfterRefresh(); title != "Mine" {
+		t.Errorf("title = %q, want the custom title kept by the refresh", title)
+	}
+	// The feed didn't change, clearing the title still gets its own back
+	if err := m.SetFeedCustomTitle(feed.ID, ""); err != nil {
+		t.Fatalf("SetFeedCustomTitle() error = %v", err)
+	}
+	if title := titleAfterRefresh(); title != "Example" {
+		t.Errorf("title = %q, want the feed's own after clearing the custom title", title)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Time
+		ok    bool
+	}{
+		{"120", now.Add(2 * time.Minute), true},
+		{"Sun, 18 Oct 2026 13:30:00 GMT", now.Add(90 * time.Minute), true},
+		{"604800", now.Add(maxRetryAfter), true},
+		{"0", time.Time{}, false},
+		{"Sun, 18 Oct 2026 11:00:00 GMT", time.Time{}, false},
+		{"soon", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if ok != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRefreshFeedRetryAfter(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	db, queries := openTestDB(t)
+	ctx := context.Background()
+	feed, err := queries.CreateFeed(ctx, database.CreateFeedParams{Url: server.URL, Title: "Limited"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	m := NewManager(db, queries)
+	if err := m.RefreshFeed(feed.ID); err == nil || !strings.Contains(err.Error(), "cooling down until") {
+		t.Fatalf("RefreshFeed() error = %v, want a 429 cooling down", err)
+	}
+
+	stored, err := queries.GetFeed(ctx, feed.ID)
+	if err != nil {
+		t.Fatalf("GetFeed() error = %v", err)
+	}
+	if !stored.RetryAfter.Valid || time.Until(stored.RetryAfter.Time) < 9*time.Minute {
+		t.Errorf("retry_after = %v, want about 10 minutes from now", stored.RetryAfter)
+	}
+
+	// Refreshing again waits for the server instead of asking it again
+	if err := m.RefreshFeed(feed.ID); err != nil {
+		t.Errorf("RefreshFeed() while cooling down error = %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("%d requests, want 1", requests)
+	}
+}
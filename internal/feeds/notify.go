@@ -0,0 +1,49 @@
+package feeds
+
+import (
+	"context"
+
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// SetNotifyPolicy sets which feeds and folders send a desktop notification
+// about new items, from the Notify setting
+func (m *Manager) SetNotifyPolicy(policy config.NotifyPolicy) {
+	m.notifyMutex.Lock()
+	defer m.notifyMutex.Unlock()
+	m.notifyPolicy = policy
+}
+
+// SetFeedNotify sets the !notify options of feeds from the URLs file, keyed
+// by feed URL
+func (m *Manager) SetFeedNotify(feeds map[string]bool) {
+	m.notifyMutex.Lock()
+	defer m.notifyMutex.Unlock()
+	m.feedNotify = feeds
+}
+
+// notifies reports whether new items of a feed send a notification
+func (m *Manager) notifies(feed database.Feed) bool {
+	m.notifyMutex.RLock()
+	policy := m.notifyPolicy.WithFeeds(m.feedNotify)
+	m.notifyMutex.RUnlock()
+	if !policy.Enabled() {
+		return false
+	}
+
+	var folders []string
+	if policy.NeedsFolders() {
+		folders, _ = m.queries.GetFeedFolders(context.Background(), feed.ID)
+	}
+	return policy.Notifies(feed.Url, folders)
+}
+
+// FlushNotifications sends one notification about the new items of the
+// refreshes since the last flush, called when no refreshes are left running
+func (m *Manager) FlushNotifications() {
+	if err := m.notifier.Flush(); err != nil {
+		logging.Warn("Desktop notification failed", "error", err)
+	}
+}
@@ -0,0 +1,183 @@
+package feeds
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jarv/newsgoat/internal/logging"
+	"github.com/mmcdole/gofeed"
+)
+
+// SetHistoryPages sets how many older pages of a paginated feed its first
+// refresh fetches, 0 fetches only the feed itself
+func (m *Manager) SetHistoryPages(pages int) {
+	m.historyPages.Store(int32(max(pages, 0)))
+}
+
+// HistoryPages returns the number of pages set with SetHistoryPages
+func (m *Manager) HistoryPages() int {
+	return int(m.historyPages.Load())
+}
+
+// nextPageURL returns the older page a feed links to, with rel="next" for
+// paged feeds and rel="prev-archive" for archived feeds (RFC 5005), or
+// next_url in a JSON Feed. Links of entries are ignored.
+func nextPageURL(body []byte, pageURL string) string {
+	var href string
+	if trimmed := bytes.TrimSpace(body); bytes.HasPrefix(trimmed, []byte("{")) {
+		var feed struct {
+			NextURL string `json:"next_url"`
+		}
+		if err := json.Unmarshal(trimmed, &feed); err != nil {
+			return ""
+		}
+		href = feed.NextURL
+	} else {
+		href = xmlNextPageLink(body)
+	}
+	if href == "" {
+		return ""
+	}
+
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	next, err := base.Parse(href)
+	if err != nil || (next.Scheme != "http" && next.Scheme != "https") {
+		return ""
+	}
+	return next.String()
+}
+
+// xmlNextPageLink finds the href of the feed level next or prev-archive link
+// of an RSS or Atom document
+func xmlNextPageLink(body []byte) string {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = false
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	inEntry := 0
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "entry", "item":
+				inEntry++
+			case "link":
+				if inEntry > 0 {
+					continue
+				}
+				var rel, href string
+				for _, attr := range element.Attr {
+					switch attr.Name.Local {
+					case "rel":
+						rel = attr.Value
+					case "href":
+						href = attr.Value
+					}
+				}
+				if (rel == "next" || rel == "prev-archive") && href != "" {
+					return strings.TrimSpace(href)
+				}
+			}
+		case xml.EndElement:
+			if element.Name.Local == "entry" || element.Name.Local == "item" {
+				inEntry--
+			}
+		}
+	}
+}
+
+// fetchHistoryPages follows the pages a feed links to, up to the number set
+// with SetHistoryPages, and returns their items. A page that fails ends the
+// walk, the items of the pages before it are still returned. Every page
+// fetched is reported as progress.
+func (m *Manager) fetchHistoryPages(ctx context.Context, client *http.Client, feedURL string, body []byte) []*gofeed.Item {
+	limit := m.HistoryPages()
+	if limit == 0 {
+		return nil
+	}
+
+	var items []*gofeed.Item
+	visited := map[string]bool{feedURL: true}
+	pageURL := feedURL
+	for page := 1; page <= limit; page++ {
+		next := nextPageURL(body, pageURL)
+		if next == "" || visited[next] {
+			break
+		}
+		visited[next] = true
+
+		var err error
+		body, err = m.fetchPage(ctx, client, next)
+		if err != nil {
+			if ctx.Err() == nil {
+				logging.Warn("Failed to fetch feed page", "url", feedURL, "page", next, "error", err)
+			}
+			break
+		}
+		parsed, err := m.parser.Parse(bytes.NewReader(body))
+		if err != nil {
+			logging.Warn("Failed to parse feed page", "url", feedURL, "page", next, "error", err)
+			break
+		}
+		items = append(items, parsed.Items...)
+		pageURL = next
+		reportProgress(ctx, Progress{Stage: "fetched", Done: int64(page), Total: int64(limit), Unit: "older pages"})
+	}
+	if len(items) > 0 {
+		logging.Info("Fetched older feed pages", "url", feedURL, "items", len(items))
+	}
+	return items
+}
+
+// fetchPage downloads one page of a paginated feed
+func (m *Manager) fetchPage(ctx context.Context, client *http.Client, pageURL string) ([]byte, error) {
+	requestCtx, cancel := context.WithTimeout(ctx, FeedTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(requestCtx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// mergeItems appends the items of older pages that the newer ones don't
+// have already
+func mergeItems(items, older []*gofeed.Item) []*gofeed.Item {
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		seen[itemGUID(item)] = true
+	}
+	for _, item := range older {
+		if guid := itemGUID(item); !seen[guid] {
+			seen[guid] = true
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,109 @@
+package feeds
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+func TestNextPageURL(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "atom next",
+			body: `<feed xmlns="http://www.w3.org/2005/Atom"><link href="https://example.com/"/><link rel="next" href="/feed?page=2"/></feed>`,
+			want: "https://example.com/feed?page=2",
+		},
+		{
+			name: "rss prev-archive",
+			body: `<rss xmlns:atom="http://www.w3.org/2005/Atom"><channel><link>https://example.com/</link><atom:link rel="prev-archive" href="https://example.com/2024.xml"/></channel></rss>`,
+			want: "https://example.com/2024.xml",
+		},
+		{
+			name: "entry links are ignored",
+			body: `<feed xmlns="http://www.w3.org/2005/Atom"><entry><link rel="next" href="/post/2"/></entry></feed>`,
+			want: "",
+		},
+		{
+			name: "json feed",
+			body: `{"version": "https://jsonfeed.org/version/1.1", "next_url": "https://example.com/feed.json?page=2", "items": []}`,
+			want: "https://example.com/feed.json?page=2",
+		},
+		{
+			name: "no pages",
+			body: `<rss><channel><link>https://example.com/</link></channel></rss>`,
+			want: "",
+		},
+		{
+			name: "not http",
+			body: `<feed><link rel="next" href="javascript:alert(1)"/></feed>`,
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextPageURL([]byte(tt.body), "https://example.com/feed"); got != tt.want {
+				t.Errorf("nextPageURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefreshFeedHistoryPages(t *testing.T) {
+	pages := map[string]string{
+		"/feed":        `<feed xmlns="http://www.w3.org/2005/Atom"><title>Paged</title><link rel="next" href="/feed?page=2"/><entry><id>1</id><title>One</title></entry></feed>`,
+		"/feed?page=2": `<feed xmlns="http://www.w3.org/2005/Atom"><title>Paged</title><link rel="next" href="/feed?page=3"/><entry><id>1</id><title>One</title></entry><entry><id>2</id><title>Two</title></entry></feed>`,
+		"/feed?page=3": `<feed xmlns="http://www.w3.org/2005/Atom"><title>Paged</title><link rel="next" href="/feed?page=4"/><entry><id>3</id><title>Three</title></entry></feed>`,
+		"/feed?page=4": `<feed xmlns="http://www.w3.org/2005/Atom"><title>Paged</title><entry><id>4</id><title>Four</title></entry></feed>`,
+	}
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.RequestURI())
+		_, _ = io.WriteString(w, pages[r.URL.RequestURI()])
+	}))
+	defer server.Close()
+
+	db, queries := openTestDB(t)
+	feed, err := queries.CreateFeed(context.Background(), database.CreateFeedParams{Url: server.URL + "/feed", Title: "Paged"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	m := NewManager(db, queries)
+	m.SetHistoryPages(2)
+
+	var reported []Progress
+	ctx := WithProgress(context.Background(), func(progress Progress) {
+		reported = append(reported, progress)
+	})
+	if err := m.RefreshFeedContext(ctx, feed.ID, false); err != nil {
+		t.Fatalf("RefreshFeedContext() error = %v", err)
+	}
+	items, err := queries.GetItemsWithReadStatus(context.Background(), feed.ID)
+	if err != nil {
+		t.Fatalf("GetItemsWithReadStatus() error = %v", err)
+	}
+	if len(items) != 3 {
+		t.Errorf("first refresh saved %d items, want the 3 of the feed and 2 older pages", len(items))
+	}
+	want := Progress{Stage: "fetched", Done: 2, Total: 2, Unit: "older pages"}
+	if !slices.Contains(reported, want) {
+		t.Errorf("reported %+v, want %+v", reported, want)
+	}
+
+	// Later refreshes only fetch the feed
+	requested = nil
+	if err := m.RefreshFeedContext(context.Background(), feed.ID, true); err != nil {
+		t.Fatalf("RefreshFeedContext() error = %v", err)
+	}
+	if !slices.Equal(requested, []string{"/feed"}) {
+		t.Errorf("second refresh requested %v, want only the feed", requested)
+	}
+}
@@ -0,0 +1,72 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// Progress is how far a refresh or prune got, like "saved 120/450 items"
+type Progress struct {
+	Stage string // What is being done, like downloaded or saved
+	Done  int64
+	Total int64  // 0 when the total isn't known
+	Unit  string // bytes, items or feeds
+}
+
+// String describes the progress, downloads as a percentage when their size
+// is known
+func (p Progress) String() string {
+	if p.Unit == "bytes" {
+		if p.Total > 0 {
+			return fmt.Sprintf("%s %d%%", p.Stage, min(p.Done, p.Total)*100/p.Total)
+		}
+		return fmt.Sprintf("%s %d KB", p.Stage, p.Done/1024)
+	}
+	if p.Total > 0 {
+		return fmt.Sprintf("%s %d/%d %s", p.Stage, p.Done, p.Total, p.Unit)
+	}
+	return fmt.Sprintf("%s %d %s", p.Stage, p.Done, p.Unit)
+}
+
+// progressKey is the context key of the function progress is reported to
+type progressKey struct{}
+
+// WithProgress returns a context that refreshes and prunes run with report
+// how far they got to report
+func WithProgress(ctx context.Context, report func(Progress)) context.Context {
+	return context.WithValue(ctx, progressKey{}, report)
+}
+
+// reportProgress hands progress to the function ctx was given by
+// WithProgress, if any
+func reportProgress(ctx context.Context, progress Progress) {
+	if report, ok := ctx.Value(progressKey{}).(func(Progress)); ok {
+		report(progress)
+	}
+}
+
+// progressReader reports how much of a download was read
+type progressReader struct {
+	ctx   context.Context
+	r     io.Reader
+	read  int64
+	total int64
+}
+
+// newProgressReader reports reading r of size total, negative when unknown
+func newProgressReader(ctx context.Context, r io.Reader, total int64) io.Reader {
+	if _, ok := ctx.Value(progressKey{}).(func(Progress)); !ok {
+		return r
+	}
+	return &progressReader{ctx: ctx, r: r, total: max(total, 0)}
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.read += int64(n)
+		reportProgress(p.ctx, Progress{Stage: "downloaded", Done: p.read, Total: p.total, Unit: "bytes"})
+	}
+	return n, err
+}
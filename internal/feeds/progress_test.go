@@ -0,0 +1,73 @@
+package feeds
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+func TestProgressString(t *testing.T) {
+	tests := []struct {
+		progress Progress
+		want     string
+	}{
+		{Progress{Stage: "downloaded", Done: 400, Total: 1000, Unit: "bytes"}, "downloaded 40%"},
+		{Progress{Stage: "downloaded", Done: 4096, Unit: "bytes"}, "downloaded 4 KB"},
+		{Progress{Stage: "saved", Done: 120, Total: 450, Unit: "items"}, "saved 120/450 items"},
+		{Progress{Stage: "pruned", Done: 3, Unit: "feeds"}, "pruned 3 feeds"},
+	}
+	for _, tt := range tests {
+		if got := tt.progress.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.progress, got, tt.want)
+		}
+	}
+}
+
+func TestRefreshFeedReportsProgress(t *testing.T) {
+	var items strings.Builder
+	for i := range 3 {
+		items.WriteString("<item><title>Item</title><guid>" + string(rune('a'+i)) + "</guid></item>")
+	}
+	body := `<?xml version="1.0"?><rss version="2.0"><channel><title>Example</title>` + items.String() + `</channel></rss>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, body)
+	}))
+	defer server.Close()
+
+	db, queries := openTestDB(t)
+	feed, err := queries.CreateFeed(context.Background(), database.CreateFeedParams{Url: server.URL, Title: "Example"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	m := NewManager(db, queries)
+
+	var reported []Progress
+	ctx := WithProgress(context.Background(), func(progress Progress) {
+		reported = append(reported, progress)
+	})
+	if err := m.RefreshFeedContext(ctx, feed.ID, false); err != nil {
+		t.Fatalf("RefreshFeedContext() error = %v", err)
+	}
+
+	if len(reported) == 0 || reported[0].Stage != "downloaded" {
+		t.Fatalf("reported %+v, want the download first", reported)
+	}
+	var downloaded int64
+	for _, progress := range reported {
+		if progress.Stage == "downloaded" {
+			downloaded = progress.Done
+		}
+	}
+	if downloaded != int64(len(body)) {
+		t.Errorf("downloaded %d bytes, want %d", downloaded, len(body))
+	}
+	last := reported[len(reported)-1]
+	if last != (Progress{Stage: "saved", Done: 3, Total: 3, Unit: "items"}) {
+		t.Errorf("last progress = %+v, want all 3 items saved", last)
+	}
+}
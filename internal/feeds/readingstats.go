@@ -0,0 +1,143 @@
+package feeds
+
+import (
+	"context"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+// Periods the reading statistics cover
+const (
+	// StatsDays is how many days of reads and unread backlog are shown
+	StatsDays = 14
+	// StatsWeeks is how many weeks of reads are shown
+	StatsWeeks = 8
+	// StatsActivityDays is how far back the busiest feeds and the average
+	// article age look
+	StatsActivityDays = 30
+	// statsBusiestFeeds is how many of the busiest feeds are listed
+	statsBusiestFeeds = 10
+)
+
+// PeriodCount is a count for the day or week starting at Start, in local time
+type PeriodCount struct {
+	Start time.Time
+	Count int
+}
+
+// ReadingStats are the reading habits and feed activity shown in the stats
+// view, periods are oldest first
+type ReadingStats struct {
+	ReadsPerDay  []PeriodCount
+	ReadsPerWeek []PeriodCount // Weeks start on Monday
+	Backlog      []PeriodCount // Unread items at the end of each day, today's is the current count
+	BusiestFeeds []database.GetFeedActivityRow
+	AverageAge   time.Duration // How old articles were when read, over StatsActivityDays
+	AgedReads    int           // How many reads AverageAge is the average of
+}
+
+// ReadingStats works out the reading statistics from the read events, which
+// are recorded when single items are read, not when a feed is marked read
+func (m *Manager) ReadingStats(now time.Time) (ReadingStats, error) {
+	ctx := context.Background()
+	firstWeek := periodStarts(now, StatsWeeks, weekStart, 7)[0]
+	reads, err := m.queries.GetReadEventsSince(ctx, int64(now.Sub(firstWeek)/(24*time.Hour))+1)
+	if err != nil {
+		return ReadingStats{}, err
+	}
+	unread, err := m.queries.CountUnreadItems(ctx)
+	if err != nil {
+		return ReadingStats{}, err
+	}
+	changes, err := m.queries.GetBacklogChanges(ctx, StatsDays)
+	if err != nil {
+		return ReadingStats{}, err
+	}
+	busiest, err := m.queries.GetFeedActivity(ctx, database.GetFeedActivityParams{Days: StatsActivityDays, Limit: statsBusiestFeeds})
+	if err != nil {
+		return ReadingStats{}, err
+	}
+	return buildReadingStats(reads, changes, unread, busiest, now), nil
+}
+
+func buildReadingStats(reads []database.GetReadEventsSinceRow, changes []database.GetBacklogChangesRow, unread int64, busiest []database.GetFeedActivityRow, now time.Time) ReadingStats {
+	stats := ReadingStats{}
+	readTimes := make([]time.Time, 0, len(reads))
+	var totalAge time.Duration
+	ageSince := now.AddDate(0, 0, -StatsActivityDays)
+	for _, read := range reads {
+		readTimes = append(readTimes, read.ReadAt)
+		if read.PublishedAt.Valid && !read.ReadAt.Before(ageSince) {
+			// Feeds dating articles in the future don't make the average negative
+			totalAge += max(read.ReadAt.Sub(read.PublishedAt.Time), 0)
+			stats.AgedReads++
+		}
+	}
+	if stats.AgedReads > 0 {
+		stats.AverageAge = totalAge / time.Duration(stats.AgedReads)
+	}
+	stats.ReadsPerDay = countPerPeriod(readTimes, periodStarts(now, StatsDays, dayStart, 1), 1)
+	stats.ReadsPerWeek = countPerPeriod(readTimes, periodStarts(now, StatsWeeks, weekStart, 7), 7)
+
+	// Working back from the current backlog, items first seen after a day
+	// weren't in its backlog yet and items read after it still were
+	for _, start := range periodStarts(now, StatsDays, dayStart, 1) {
+		end := start.AddDate(0, 0, 1)
+		backlog := int(unread)
+		for _, change := range changes {
+			if change.CreatedAt.Valid && !change.CreatedAt.Time.Before(end) {
+				backlog--
+			}
+			if change.ReadAt.Valid && !change.ReadAt.Time.Before(end) {
+				backlog++
+			}
+		}
+		stats.Backlog = append(stats.Backlog, PeriodCount{Start: start, Count: max(backlog, 0)})
+	}
+
+	for _, feed := range busiest {
+		if feed.NewItems > 0 || feed.Reads > 0 {
+			stats.BusiestFeeds = append(stats.BusiestFeeds, feed)
+		}
+	}
+	return stats
+}
+
+// countPerPeriod counts the times falling in each period of days starting at
+// the starts
+func countPerPeriod(times []time.Time, starts []time.Time, days int) []PeriodCount {
+	counts := make([]PeriodCount, len(starts))
+	for i, start := range starts {
+		counts[i].Start = start
+		end := start.AddDate(0, 0, days)
+		for _, t := range times {
+			if !t.Before(start) && t.Before(end) {
+				counts[i].Count++
+			}
+		}
+	}
+	return counts
+}
+
+// periodStarts returns the starts of the last n periods of days up to now,
+// oldest first
+func periodStarts(now time.Time, n int, start func(time.Time) time.Time, days int) []time.Time {
+	starts := make([]time.Time, n)
+	last := start(now)
+	for i := range starts {
+		starts[i] = last.AddDate(0, 0, -days*(n-1-i))
+	}
+	return starts
+}
+
+// dayStart returns the local midnight starting the day of t
+func dayStart(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// weekStart returns the local midnight starting the Monday of the week of t
+func weekStart(t time.Time) time.Time {
+	return dayStart(t).AddDate(0, 0, -(int(t.Weekday())+6)%7)
+}
@@ -0,0 +1,130 @@
+package feeds
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+func TestBuildReadingStats(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2026, 10, 14, 18, 0, 0, 0, time.UTC)
+	at := func(daysAgo, hour int) time.Time {
+		return time.Date(2026, 10, 14-daysAgo, hour, 0, 0, 0, time.UTC)
+	}
+	valid := func(t time.Time) sql.NullTime {
+		return sql.NullTime{Time: t, Valid: true}
+	}
+	reads := []database.GetReadEventsSinceRow{
+		{ReadAt: at(9, 10), PublishedAt: valid(at(10, 10))},
+		{ReadAt: at(1, 10), PublishedAt: valid(at(1, 8))},
+		{ReadAt: at(0, 9), PublishedAt: valid(at(0, 7))},
+		// Dated in the future, counts as read right away
+		{ReadAt: at(0, 12), PublishedAt: valid(at(-1, 12))},
+		// Long ago, only counted in the weeks
+		{ReadAt: at(40, 12), PublishedAt: valid(at(41, 12))},
+	}
+	changes := []database.GetBacklogChangesRow{
+		// Seen yesterday and read today
+		{CreatedAt: valid(at(1, 9)), ReadAt: valid(at(0, 9))},
+		// Seen today, still unread
+		{CreatedAt: valid(at(0, 8))},
+		// Seen long ago, read yesterday and today
+		{CreatedAt: valid(at(30, 8)), ReadAt: valid(at(1, 10))},
+		{CreatedAt: valid(at(30, 8)), ReadAt: valid(at(0, 10))},
+	}
+	busiest := []database.GetFeedActivityRow{
+		{ID: 1, Title: "Busy", NewItems: 20, Reads: 2},
+		{ID: 2, Title: "Quiet"},
+	}
+
+	stats := buildReadingStats(reads, changes, 5, busiest, now)
+
+	if len(stats.ReadsPerDay) != StatsDays {
+		t.Fatalf("len(ReadsPerDay) = %d, want %d", len(stats.ReadsPerDay), StatsDays)
+	}
+	today := stats.ReadsPerDay[StatsDays-1]
+	if !today.Start.Equal(at(0, 0)) || today.Count != 2 {
+		t.Errorf("today = %v %d, want %v 2", today.Start, today.Count, at(0, 0))
+	}
+	if got := stats.ReadsPerDay[StatsDays-2].Count; got != 1 {
+		t.Errorf("yesterday = %d, want 1", got)
+	}
+	if got := stats.ReadsPerDay[StatsDays-10].Count; got != 1 {
+		t.Errorf("9 days ago = %d, want 1", got)
+	}
+
+	thisWeek := stats.ReadsPerWeek[StatsWeeks-1]
+	if !thisWeek.Start.Equal(at(2, 0)) || thisWeek.Count != 3 {
+		t.Errorf("this week = %v %d, want Monday %v 3", thisWeek.Start, thisWeek.Count, at(2, 0))
+	}
+	if got := stats.ReadsPerWeek[StatsWeeks-7].Count; got != 1 {
+		t.Errorf("6 weeks ago = %d, want 1", got)
+	}
+
+	// 5 unread now, at the end of yesterday the item seen today wasn't there yet
+	// and the two read today were unread; the day before the one seen yesterday
+	// wasn't there either and the one read yesterday was unread
+	for daysAgo, want := range map[int]int{0: 5, 1: 6, 2: 6} {
+		if got := stats.Backlog[StatsDays-1-daysAgo].Count; got != want {
+			t.Errorf("backlog %d days ago = %d, want %d", daysAgo, got, want)
+		}
+	}
+	if got := stats.Backlog[0].Count; got != 6 {
+		t.Errorf("backlog 13 days ago = %d, want 6", got)
+	}
+
+	// Ages of 1 day, 2 hours, 2 hours and 0, the read 40 days ago is left out
+	if want := (26*time.Hour + 2*time.Hour) / 4; stats.AverageAge != want || stats.AgedReads != 4 {
+		t.Errorf("AverageAge = %v over %d, want %v over 4", stats.AverageAge, stats.AgedReads, want)
+	}
+
+	if len(stats.BusiestFeeds) != 1 || stats.BusiestFeeds[0].Title != "Busy" {
+		t.Errorf("BusiestFeeds = %v, want only Busy", stats.BusiestFeeds)
+	}
+}
+
+func TestMarkItemReadRecordsReadEvent(t *testing.T) {
+	db, queries := openTestDB(t)
+	ctx := context.Background()
+	m := NewManager(db, queries)
+
+	feed, err := queries.CreateFeed(ctx, database.CreateFeedParams{Url: "https://example.com/feed.xml", Title: "Example", Visible: true})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	item, err := queries.UpsertItem(ctx, database.UpsertItemParams{FeedID: feed.ID, Guid: "1", Title: "First"})
+	if err != nil {
+		t.Fatalf("UpsertItem() error = %v", err)
+	}
+	if _, err := queries.UpsertItem(ctx, database.UpsertItemParams{FeedID: feed.ID, Guid: "2", Title: "Second"}); err != nil {
+		t.Fatalf("UpsertItem() error = %v", err)
+	}
+
+	// Reading an item already read isn't another read
+	for range 2 {
+		if err := m.MarkItemRead(item.ID); err != nil {
+			t.Fatalf("MarkItemRead() error = %v", err)
+		}
+	}
+
+	stats, err := m.ReadingStats(time.Now())
+	if err != nil {
+		t.Fatalf("ReadingStats() error = %v", err)
+	}
+	if got := stats.ReadsPerDay[StatsDays-1].Count; got != 1 {
+		t.Errorf("reads today = %d, want 1", got)
+	}
+	if got := stats.Backlog[StatsDays-1].Count; got != 1 {
+		t.Errorf("backlog today = %d, want 1", got)
+	}
+	if stats.AgedReads != 1 {
+		t.Errorf("AgedReads = %d, want 1", stats.AgedReads)
+	}
+	if len(stats.BusiestFeeds) != 1 || stats.BusiestFeeds[0].NewItems != 2 || stats.BusiestFeeds[0].Reads != 1 {
+		t.Errorf("BusiestFeeds = %+v, want Example with 2 new items and 1 read", stats.BusiestFeeds)
+	}
+}
@@ -0,0 +1,254 @@
+package feeds
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/logging"
+	"github.com/jarv/newsgoat/internal/version"
+)
+
+// Read-later services items can be sent to
+const (
+	ReadLaterWallabag   = "wallabag"
+	ReadLaterPocket     = "pocket"
+	ReadLaterInstapaper = "instapaper"
+	ReadLaterWebhook    = "webhook"
+)
+
+// ReadLaterServices lists the read-later services in the order they are offered
+var ReadLaterServices = []string{ReadLaterWallabag, ReadLaterPocket, ReadLaterInstapaper, ReadLaterWebhook}
+
+// IsReadLaterService reports whether items can be sent to a service
+func IsReadLaterService(service string) bool {
+	for _, s := range ReadLaterServices {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
+
+// Endpoints of the hosted services, variables so tests can point them at a local server
+var (
+	pocketAddURL     = "https://getpocket.com/v3/add"
+	instapaperAddURL = "https://www.instapaper.com/api/add"
+)
+
+// ReadLaterConfig selects the service items are sent to and how to sign in
+type ReadLaterConfig struct {
+	Service string
+	// URL is the Wallabag instance or the webhook, the hosted services ignore it
+	URL string
+	// Credentials depend on the service:
+	//   wallabag:   client_id:client_secret:username:password
+	//   pocket:     consumer_key:access_token
+	//   instapaper: username:password
+	//   webhook:    value of the Authorization header, optional
+	Credentials string
+}
+
+// ReadLaterItem is the article sent to a read-later service
+type ReadLaterItem struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+	Feed  string `json:"feed,omitempty"`
+}
+
+// NewReadLaterConfig takes the read-later settings from the config
+func NewReadLaterConfig(cfg config.Config) ReadLaterConfig {
+	return ReadLaterConfig{
+		Service:     cfg.ReadLater,
+		URL:         cfg.ReadLaterURL,
+		Credentials: cfg.ReadLaterAuth,
+	}
+}
+
+// RegisterSecrets keeps the credentials out of logs, the password or token
+// is always the last field
+func (c ReadLaterConfig) RegisterSecrets() {
+	if c.Credentials == "" {
+		return
+	}
+	logging.RegisterSecret(c.Credentials)
+	if i := strings.LastIndex(c.Credentials, ":"); i >= 0 {
+		logging.RegisterSecret(c.Credentials[i+1:])
+	}
+}
+
+// ServiceName is the service's name for status messages
+func (c ReadLaterConfig) ServiceName() string {
+	switch c.Service {
+	case ReadLaterWallabag:
+		return "Wallabag"
+	case ReadLaterPocket:
+		return "Pocket"
+	case ReadLaterInstapaper:
+		return "Instapaper"
+	}
+	return c.Service
+}
+
+// credentials splits the credentials into n fields, the last one keeps any
+// further colons so passwords can contain them
+func (c ReadLaterConfig) credentials(n int, format string) ([]string, error) {
+	fields := strings.SplitN(c.Credentials, ":", n)
+	if len(fields) != n {
+		return nil, fmt.Errorf("%s credentials must be %s", c.Service, format)
+	}
+	for _, field := range fields {
+		if field == "" {
+			return nil, fmt.Errorf("%s credentials must be %s", c.Service, format)
+		}
+	}
+	return fields, nil
+}
+
+// SaveForLater sends an item to the configured read-later service
+func (m *Manager) SaveForLater(ctx context.Context, cfg ReadLaterConfig, item ReadLaterItem) error {
+	if item.URL == "" {
+		return fmt.Errorf("item has no link to save")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, FeedTimeout)
+	defer cancel()
+	client := &http.Client{Timeout: FeedTimeout, Transport: m.globalTransport()}
+
+	switch cfg.Service {
+	case ReadLaterWallabag:
+		return saveToWallabag(ctx, client, cfg, item)
+	case ReadLaterPocket:
+		return saveToPocket(ctx, client, cfg, item)
+	case ReadLaterInstapaper:
+		return saveToInstapaper(ctx, client, cfg, item)
+	case ReadLaterWebhook:
+		return saveToWebhook(ctx, client, cfg, item)
+	case "":
+		return fmt.Errorf("no read-later service set, choose one in settings (c)")
+	}
+	return fmt.Errorf("unknown read-later service %q", cfg.Service)
+}
+
+// saveToWallabag signs in with the password grant, Wallabag has no long-lived
+// API tokens, and creates an entry
+func saveToWallabag(ctx context.Context, client *http.Client, cfg ReadLaterConfig, item ReadLaterItem) error {
+	if cfg.URL == "" {
+		return fmt.Errorf("no Wallabag URL set")
+	}
+	creds, err := cfg.credentials(4, "client_id:client_secret:username:password")
+	if err != nil {
+		return err
+	}
+	baseURL := strings.TrimSuffix(cfg.URL, "/")
+
+	form := url.Values{
+		"grant_type":    {"password"},
+		"client_id":     {creds[0]},
+		"client_secret": {creds[1]},
+		"username":      {creds[2]},
+		"password":      {creds[3]},
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := postReadLater(ctx, client, baseURL+"/oauth/v2/token", "application/x-www-form-urlencoded", strings.NewReader(form.Encode()), nil, &token); err != nil {
+		return fmt.Errorf("wallabag sign in failed: %w", err)
+	}
+	if token.AccessToken == "" {
+		return fmt.Errorf("wallabag sign in failed: no access token returned")
+	}
+
+	entry := url.Values{"url": {item.URL}, "title": {item.Title}}
+	headers := map[string]string{"Authorization": "Bearer " + token.AccessToken}
+	return postReadLater(ctx, client, baseURL+"/api/entries.json", "application/x-www-form-urlencoded", strings.NewReader(entry.Encode()), headers, nil)
+}
+
+func saveToPocket(ctx context.Context, client *http.Client, cfg ReadLaterConfig, item ReadLaterItem) error {
+	creds, err := cfg.credentials(2, "consumer_key:access_token")
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(map[string]string{
+		"url":          item.URL,
+		"title":        item.Title,
+		"consumer_key": creds[0],
+		"access_token": creds[1],
+	})
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{"X-Accept": "application/json"}
+	return postReadLater(ctx, client, pocketAddURL, "application/json", bytes.NewReader(payload), headers, nil)
+}
+
+// saveToInstapaper uses the Simple API, which takes the account password
+// instead of OAuth
+func saveToInstapaper(ctx context.Context, client *http.Client, cfg ReadLaterConfig, item ReadLaterItem) error {
+	creds, err := cfg.credentials(2, "username:password")
+	if err != nil {
+		return err
+	}
+	form := url.Values{
+		"username": {creds[0]},
+		"password": {creds[1]},
+		"url":      {item.URL},
+		"title":    {item.Title},
+	}
+	return postReadLater(ctx, client, instapaperAddURL, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()), nil, nil)
+}
+
+// saveToWebhook posts the item as JSON for services without a dedicated
+// integration, such as a self-hosted bookmark manager
+func saveToWebhook(ctx context.Context, client *http.Client, cfg ReadLaterConfig, item ReadLaterItem) error {
+	if cfg.URL == "" {
+		return fmt.Errorf("no webhook URL set")
+	}
+	payload, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	var headers map[string]string
+	if cfg.Credentials != "" {
+		headers = map[string]string{"Authorization": cfg.Credentials}
+	}
+	return postReadLater(ctx, client, cfg.URL, "application/json", bytes.NewReader(payload), headers, nil)
+}
+
+// postReadLater sends a POST request and decodes a JSON response into result
+// when it isn't nil
+func postReadLater(ctx context.Context, client *http.Client, endpoint, contentType string, body io.Reader, headers map[string]string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", version.GetUserAgent())
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return fmt.Errorf("invalid response: %w", err)
+		}
+	}
+	return nil
+}
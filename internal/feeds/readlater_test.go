@@ -0,0 +1,113 @@
+package feeds
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSaveForLaterWallabag(t *testing.T) {
+	var saved string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		switch r.URL.Path {
+		case "/oauth/v2/token":
+			if r.PostForm.Get("client_id") != "id" || r.PostForm.Get("password") != "pass:word" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"abc"}`))
+		case "/api/entries.json":
+			if r.Header.Get("Authorization") != "Bearer abc" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			saved = r.PostForm.Get("url")
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	m := NewManager(nil, nil)
+	cfg := ReadLaterConfig{Service: ReadLaterWallabag, URL: server.URL + "/", Credentials: "id:secret:user:pass:word"}
+	if err := m.SaveForLater(context.Background(), cfg, ReadLaterItem{URL: "https://example.com/a", Title: "A"}); err != nil {
+		t.Fatalf("SaveForLater() error = %v", err)
+	}
+	if saved != "https://example.com/a" {
+		t.Errorf("saved URL = %q", saved)
+	}
+
+	cfg.Credentials = "id:secret:user:wrong"
+	if err := m.SaveForLater(context.Background(), cfg, ReadLaterItem{URL: "https://example.com/a"}); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("SaveForLater() with a wrong password error = %v, want HTTP 401", err)
+	}
+}
+
+func TestSaveForLaterWebhook(t *testing.T) {
+	var got ReadLaterItem
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	m := NewManager(nil, nil)
+	cfg := ReadLaterConfig{Service: ReadLaterWebhook, URL: server.URL, Credentials: "Bearer xyz"}
+	item := ReadLaterItem{URL: "https://example.com/a", Title: "A", Feed: "Example"}
+	if err := m.SaveForLater(context.Background(), cfg, item); err != nil {
+		t.Fatalf("SaveForLater() error = %v", err)
+	}
+	if got != item || auth != "Bearer xyz" {
+		t.Errorf("webhook got %+v with Authorization %q", got, auth)
+	}
+}
+
+func TestSaveForLaterInvalidConfig(t *testing.T) {
+	m := NewManager(nil, nil)
+	item := ReadLaterItem{URL: "https://example.com/a"}
+	for _, cfg := range []ReadLaterConfig{
+		{},
+		{Service: "delicious"},
+		{Service: ReadLaterPocket, Credentials: "only-a-key"},
+		{Service: ReadLaterInstapaper, Credentials: "user:"},
+		{Service: ReadLaterWallabag, Credentials: "id:secret:user:pass"},
+	} {
+		if err := m.SaveForLater(context.Background(), cfg, item); err == nil {
+			t.Errorf("SaveForLater(%+v) should fail", cfg)
+		}
+	}
+}
+
+func TestSaveForLaterPocket(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		_, _ = w.Write([]byte(`{"status":1}`))
+	}))
+	defer server.Close()
+
+	defer func(original string) { pocketAddURL = original }(pocketAddURL)
+	pocketAddURL = server.URL
+
+	m := NewManager(nil, nil)
+	cfg := ReadLaterConfig{Service: ReadLaterPocket, Credentials: "key:token"}
+	if err := m.SaveForLater(context.Background(), cfg, ReadLaterItem{URL: "https://example.com/a", Title: "A"}); err != nil {
+		t.Fatalf("SaveForLater() error = %v", err)
+	}
+	if got["consumer_key"] != "key" || got["access_token"] != "token" || got["url"] != "https://example.com/a" {
+		t.Errorf("pocket got %v", got)
+	}
+}
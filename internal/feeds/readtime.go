@@ -0,0 +1,30 @@
+package feeds
+
+import (
+	"strings"
+	"unicode"
+)
+
+// wordsPerMinute is the reading speed reading times are estimated with
+const wordsPerMinute = 200
+
+// WordCount returns the number of words in the text of HTML content,
+// punctuation on its own between tags isn't counted
+func WordCount(content string) int {
+	words := 0
+	for _, field := range strings.Fields(contentText(content)) {
+		if strings.IndexFunc(field, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) >= 0 {
+			words++
+		}
+	}
+	return words
+}
+
+// ReadingMinutes estimates how many minutes it takes to read a number of
+// words, at least one minute for any text
+func ReadingMinutes(words int) int {
+	if words == 0 {
+		return 0
+	}
+	return (words + wordsPerMinute - 1) / wordsPerMinute
+}
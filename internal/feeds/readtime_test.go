@@ -0,0 +1,48 @@
+package feeds
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWordCount(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected int
+	}{
+		{"", 0},
+		{"<p>Hello, <b>world</b>!</p>", 2},
+		{"<p>One two</p><script>var three = 4;</script><style>p { }</style><p>three</p>", 3},
+		{"plain text  with\nspaces", 4},
+	}
+
+	for _, tt := range tests {
+		if got := WordCount(tt.content); got != tt.expected {
+			t.Errorf("WordCount(%q) = %d, want %d", tt.content, got, tt.expected)
+		}
+	}
+}
+
+func TestReadingMinutes(t *testing.T) {
+	tests := []struct {
+		words    int
+		expected int
+	}{
+		{0, 0},
+		{1, 1},
+		{200, 1},
+		{201, 2},
+		{1000, 5},
+	}
+
+	for _, tt := range tests {
+		if got := ReadingMinutes(tt.words); got != tt.expected {
+			t.Errorf("ReadingMinutes(%d) = %d, want %d", tt.words, got, tt.expected)
+		}
+	}
+
+	words := strings.Repeat("word ", 450)
+	if got := ReadingMinutes(WordCount(words)); got != 3 {
+		t.Errorf("ReadingMinutes of 450 words = %d, want 3", got)
+	}
+}
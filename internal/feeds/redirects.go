@@ -0,0 +1,122 @@
+package feeds
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// maxRedirects is how many redirects a feed request follows, like net/http
+const maxRedirects = 10
+
+// redirectTracker follows the redirects of a feed request and remembers
+// whether every one of them was permanent
+type redirectTracker struct {
+	hops      int
+	temporary bool
+}
+
+// checkRedirect is the CheckRedirect of the feed's http.Client
+func (r *redirectTracker) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return errors.New("stopped after 10 redirects")
+	}
+	r.hops++
+	if req.Response != nil && req.Response.StatusCode != http.StatusMovedPermanently &&
+		req.Response.StatusCode != http.StatusPermanentRedirect {
+		r.temporary = true
+	}
+	return nil
+}
+
+// movedTo returns the URL a feed moved to when its request only followed
+// permanent redirects, or "" when it didn't move
+func (r *redirectTracker) movedTo(resp *http.Response, feedURL string, withToken bool) string {
+	if r.hops == 0 || r.temporary || resp.Request == nil {
+		return ""
+	}
+	final := *resp.Request.URL
+	// The feed token is added to every request, it doesn't belong in the URLs file
+	if withToken {
+		q := final.Query()
+		q.Del("feed_token")
+		final.RawQuery = q.Encode()
+	}
+	if moved := final.String(); moved != feedURL {
+		return moved
+	}
+	return ""
+}
+
+// SetRedirectUpdates moves feeds that redirect permanently to their new URL,
+// in the URLs file at urlsPath and in the database. An empty path only
+// remembers the new URL, for MovedURL.
+func (m *Manager) SetRedirectUpdates(urlsPath string) {
+	m.movedMutex.Lock()
+	defer m.movedMutex.Unlock()
+	m.redirectURLsPath = urlsPath
+}
+
+// MovedURL returns the URL a feed redirected to permanently on its last
+// refresh, or "" when it didn't
+func (m *Manager) MovedURL(feedID int64) string {
+	m.movedMutex.Lock()
+	defer m.movedMutex.Unlock()
+	return m.movedFeeds[feedID]
+}
+
+// URLsFileChanged reports whether feeds that redirect were moved in the URLs
+// file since it was last called
+func (m *Manager) URLsFileChanged() bool {
+	return m.urlsFileChanged.Swap(false)
+}
+
+// feedMoved records where a feed redirected to permanently, and moves it
+// there when SetRedirectUpdates asked to. An empty movedTo means the feed
+// no longer redirects.
+func (m *Manager) feedMoved(feed database.Feed, movedTo string) {
+	m.movedMutex.Lock()
+	urlsPath := m.redirectURLsPath
+	if movedTo == "" {
+		delete(m.movedFeeds, feed.ID)
+	} else if urlsPath == "" {
+		if m.movedFeeds == nil {
+			m.movedFeeds = make(map[int64]string)
+		}
+		m.movedFeeds[feed.ID] = movedTo
+	}
+	m.movedMutex.Unlock()
+	if movedTo == "" {
+		return
+	}
+
+	if urlsPath == "" {
+		logging.Info("Feed redirects permanently", "url", feed.Url, "to", movedTo)
+		return
+	}
+	if err := m.MoveFeed(feed.ID, feed.Url, movedTo, urlsPath); err != nil {
+		logging.Warn("Failed to move feed to where it redirects", "url", feed.Url, "to", movedTo, "error", err)
+		return
+	}
+	m.urlsFileChanged.Store(true)
+	logging.Info("Feed moved to where it redirects permanently", "from", feed.Url, "to", movedTo)
+}
+
+// MoveFeed changes the URL of a feed in the URLs file at urlsPath and in the
+// database, keeping its items
+func (m *Manager) MoveFeed(feedID int64, oldURL, newURL, urlsPath string) error {
+	if err := config.ReplaceURL(urlsPath, oldURL, newURL); err != nil {
+		return err
+	}
+	if err := m.ChangeFeedURL(feedID, newURL); err != nil {
+		return err
+	}
+
+	m.movedMutex.Lock()
+	delete(m.movedFeeds, feedID)
+	m.movedMutex.Unlock()
+	return nil
+}
@@ -0,0 +1,102 @@
+package feeds
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+func redirectServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old.xml", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new.xml", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/temporary.xml", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/old.xml", http.StatusFound)
+	})
+	mux.HandleFunc("/new.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<rss version="2.0"><channel><title>Example</title>
+<item><guid>1</guid><title>One</title></item></channel></rss>`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRefreshFeedPermanentRedirect(t *testing.T) {
+	server := redirectServer(t)
+	db, queries := openTestDB(t)
+	ctx := context.Background()
+
+	m := NewManager(db, queries)
+	moved, err := queries.CreateFeed(ctx, database.CreateFeedParams{Url: server.URL + "/old.xml", Title: "Moved"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	temporary, err := queries.CreateFeed(ctx, database.CreateFeedParams{Url: server.URL + "/temporary.xml", Title: "Temporary"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+
+	if err := m.RefreshFeed(moved.ID); err != nil {
+		t.Fatalf("RefreshFeed() error = %v", err)
+	}
+	if got, want := m.MovedURL(moved.ID), server.URL+"/new.xml"; got != want {
+		t.Errorf("MovedURL() = %q, want %q", got, want)
+	}
+
+	// A chain with a temporary redirect in it may change back
+	if err := m.RefreshFeed(temporary.ID); err != nil {
+		t.Fatalf("RefreshFeed() error = %v", err)
+	}
+	if got := m.MovedURL(temporary.ID); got != "" {
+		t.Errorf("MovedURL() after a temporary redirect = %q, want none", got)
+	}
+}
+
+func TestRefreshFeedUpdatesRedirects(t *testing.T) {
+	server := redirectServer(t)
+	db, queries := openTestDB(t)
+	ctx := context.Background()
+
+	oldURL := server.URL + "/old.xml"
+	urlsPath := filepath.Join(t.TempDir(), "urls")
+	if err := os.WriteFile(urlsPath, []byte(oldURL+" tech\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	feed, err := queries.CreateFeed(ctx, database.CreateFeedParams{Url: oldURL, Title: "Moved"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+
+	m := NewManager(db, queries)
+	m.SetRedirectUpdates(urlsPath)
+	if err := m.RefreshFeed(feed.ID); err != nil {
+		t.Fatalf("RefreshFeed() error = %v", err)
+	}
+
+	newURL := server.URL + "/new.xml"
+	stored, err := queries.GetFeed(ctx, feed.ID)
+	if err != nil || stored.Url != newURL {
+		t.Errorf("GetFeed() = %q, %v, want %q", stored.Url, err, newURL)
+	}
+	data, err := os.ReadFile(urlsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := newURL + " tech\n"; string(data) != want {
+		t.Errorf("URLs file = %q, want %q", data, want)
+	}
+	if !m.URLsFileChanged() || m.URLsFileChanged() {
+		t.Error("URLsFileChanged() should be true once after moving a feed")
+	}
+	if m.MovedURL(feed.ID) != "" {
+		t.Error("MovedURL() of a feed that was moved should be empty")
+	}
+}
@@ -0,0 +1,247 @@
+package feeds
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/hooks"
+	"github.com/jarv/newsgoat/internal/notify"
+)
+
+// openTestDB creates a database with the current schema that is removed
+// when the test ends
+func openTestDB(t testing.TB) (*sql.DB, *database.Queries) {
+	t.Helper()
+	schema, err := os.ReadFile("../../sql/schema.sql")
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	db, queries, err := database.Open(filepath.Join(t.TempDir(), "newsgoat.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	if _, err := db.Exec(string(schema)); err != nil {
+		t.Fatalf("failed to create tables: %v", err)
+	}
+	return db, queries
+}
+
+func TestRefreshFeedUnchangedBody(t *testing.T) {
+	var mu sync.Mutex
+	body := `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Example</title>
+<item><title>First</title><guid>first</guid></item>
+</channel></rss>`
+	// The server ignores conditional requests and always sends the full feed
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("ETag", `"same"`)
+		_, _ = io.WriteString(w, body)
+	}))
+	defer server.Close()
+
+	db, queries := openTestDB(t)
+	ctx := context.Background()
+	feed, err := queries.CreateFeed(ctx, database.CreateFeedParams{Url: server.URL, Title: "Example"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	m := NewManager(db, queries)
+
+	countItems := func() int {
+		t.Helper()
+		items, err := queries.GetItemGUIDs(ctx, feed.ID)
+		if err != nil {
+			t.Fatalf("GetItemGUIDs() error = %v", err)
+		}
+		return len(items)
+	}
+
+	if err := m.RefreshFeed(feed.ID); err != nil {
+		t.Fatalf("RefreshFeed() error = %v", err)
+	}
+	if got := countItems(); got != 1 {
+		t.Fatalf("%d items after the first refresh, want 1", got)
+	}
+
+	// The same body again skips the items, deleting one shows it wasn't stored again
+	if _, err := db.Exec("DELETE FROM items WHERE feed_id = ?", feed.ID); err != nil {
+		t.Fatalf("failed to delete items: %v", err)
+	}
+	if err := m.RefreshFeed(feed.ID); err != nil {
+		t.Fatalf("RefreshFeed() error = %v", err)
+	}
+	if got := countItems(); got != 0 {
+		t.Errorf("%d items after refreshing an unchanged feed, want 0", got)
+	}
+	updated, err := queries.GetFeed(ctx, feed.ID)
+	if err != nil {
+		t.Fatalf("GetFeed() error = %v", err)
+	}
+	if updated.UnchangedFetches != 1 {
+		t.Errorf("UnchangedFetches = %d, want 1", updated.UnchangedFetches)
+	}
+	if want := "200 OK, same feed as the last fetch"; updated.LastResult != want {
+		t.Errorf("LastResult = %q, want %q", updated.LastResult, want)
+	}
+
+	// A changed body is processed again
+	mu.Lock()
+	body = `<rss version="2.0"><channel><title>Example</title>
+<item><title>First</title><guid>first</guid></item>
+<item><title>Second</title><guid>second</guid></item>
+</channel></rss>`
+	mu.Unlock()
+	if err := m.RefreshFeed(feed.ID); err != nil {
+		t.Fatalf("RefreshFeed() error = %v", err)
+	}
+	if got := countItems(); got != 2 {
+		t.Errorf("%d items after the feed changed, want 2", got)
+	}
+
+	// Clearing the cache processes the same body again
+	if _, err := db.Exec("DELETE FROM items WHERE feed_id = ?", feed.ID); err != nil {
+		t.Fatalf("failed to delete items: %v", err)
+	}
+	if err := m.ClearFeedCache(feed.ID); err != nil {
+		t.Fatalf("ClearFeedCache() error = %v", err)
+	}
+	if err := m.RefreshFeed(feed.ID); err != nil {
+		t.Fatalf("RefreshFeed() error = %v", err)
+	}
+	if got := countItems(); got != 2 {
+		t.Errorf("%d items after refreshing with a cleared cache, want 2", got)
+	}
+	updated, err = queries.GetFeed(ctx, feed.ID)
+	if err != nil {
+		t.Fatalf("GetFeed() error = %v", err)
+	}
+	if want := "200 OK, 2 items"; updated.LastResult != want {
+		t.Errorf("LastResult = %q, want %q", updated.LastResult, want)
+	}
+}
+
+func TestRefreshFeedHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks run through sh")
+	}
+	var mu sync.Mutex
+	status := http.StatusOK
+	body := `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Example</title>
+<item><title>First</title><guid>first</guid></item>
+</channel></rss>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, body)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	hooksPath := filepath.Join(dir, "hooks")
+	hooksFile := "new-items echo \"$NEWSGOAT_NEW_ITEMS $NEWSGOAT_NEW_TITLES\" >> " + filepath.Join(dir, "new") + "\n" +
+		"feed-refresh-failed echo \"$NEWSGOAT_ERROR\" >> " + filepath.Join(dir, "failed") + "\n"
+	if err := os.WriteFile(hooksPath, []byte(hooksFile), 0644); err != nil {
+		t.Fatal(err)
+	}
+	h, errs := hooks.Load(hooksPath)
+	if len(errs) > 0 {
+		t.Fatalf("hooks.Load() errors = %v", errs)
+	}
+
+	db, queries := openTestDB(t)
+	feed, err := queries.CreateFeed(context.Background(), database.CreateFeedParams{Url: server.URL, Title: "Example"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	m := NewManager(db, queries)
+	m.SetHooks(h)
+
+	refresh := func(newBody string, newStatus int) {
+		t.Helper()
+		mu.Lock()
+		body, status = newBody, newStatus
+		mu.Unlock()
+		_ = m.RefreshFeed(feed.ID)
+		h.Wait(5 * time.Second)
+	}
+	refresh(body, http.StatusOK)
+	refresh(strings.Replace(body, "<item>", "<item><title>Second</title><guid>second</guid></item><item>", 1), http.StatusOK)
+	refresh(body, http.StatusInternalServerError)
+
+	newItems, _ := os.ReadFile(filepath.Join(dir, "new"))
+	// The items of the first fetch aren't new
+	if want := "1 Second\n"; string(newItems) != want {
+		t.Errorf("new-items hooks wrote %q, want %q", newItems, want)
+	}
+	failed, _ := os.ReadFile(filepath.Join(dir, "failed"))
+	if want := "HTTP 500: Internal Server Error\n"; string(failed) != want {
+		t.Errorf("feed-refresh-failed hooks wrote %q, want %q", failed, want)
+	}
+}
+
+func TestRefreshFeedNotify(t *testing.T) {
+	var mu sync.Mutex
+	body := `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Example</title>
+<item><title>First</title><guid>first</guid></item>
+</channel></rss>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = io.WriteString(w, body)
+	}))
+	defer server.Close()
+
+	db, queries := openTestDB(t)
+	feed, err := queries.CreateFeed(context.Background(), database.CreateFeedParams{Url: server.URL, Title: "Example"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	m := NewManager(db, queries)
+	var sent []string
+	m.notifier = notify.NewNotifier(func(title, body string) error {
+		sent = append(sent, title)
+		return nil
+	})
+
+	refresh := func(newBody string) {
+		t.Helper()
+		mu.Lock()
+		body = newBody
+		mu.Unlock()
+		_ = m.RefreshFeed(feed.ID)
+		m.FlushNotifications()
+	}
+	second := strings.Replace(body, "<item>", "<item><title>Second</title><guid>second</guid></item><item>", 1)
+	third := strings.Replace(second, "<item>", "<item><title>Third</title><guid>third</guid></item><item>", 1)
+
+	refresh(body)
+	// Notifications are off until the feed or one of its folders turns them on
+	refresh(second)
+	m.SetFeedNotify(map[string]bool{server.URL: true})
+	refresh(third)
+
+	// The items of the first fetch aren't new
+	if want := []string{"1 new item in Example"}; !reflect.DeepEqual(sent, want) {
+		t.Errorf("sent %q, want %q", sent, want)
+	}
+}
@@ -0,0 +1,84 @@
+package feeds
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+// recentItemsWindow is the period GetFeedRefreshStats counts new items over
+const recentItemsWindow = 30 * 24 * time.Hour
+
+// RefreshPlan is the order a refresh of every feed fetches them in
+type RefreshPlan struct {
+	FeedIDs []int64 // The feeds to refresh, likeliest to have changed first
+	Fresh   int     // Feeds left out, still within their Cache-Control max-age
+}
+
+// PlanRefreshAll leaves out the feeds whose Cache-Control max-age hasn't
+// passed and orders the rest by how many new items they probably have: feeds
+// never fetched first, then by the items they got lately times the time
+// since their last fetch, and feeds failing to refresh last
+func (m *Manager) PlanRefreshAll(feedIDs []int64, now time.Time) (RefreshPlan, error) {
+	stats, err := m.queries.GetFeedRefreshStats(context.Background())
+	if err != nil {
+		return RefreshPlan{}, err
+	}
+	return planRefresh(feedIDs, stats, now), nil
+}
+
+func planRefresh(feedIDs []int64, stats []database.GetFeedRefreshStatsRow, now time.Time) RefreshPlan {
+	byID := make(map[int64]database.GetFeedRefreshStatsRow, len(stats))
+	for _, feed := range stats {
+		byID[feed.ID] = feed
+	}
+
+	type scored struct {
+		id    int64
+		score float64
+		since time.Duration
+	}
+	var plan RefreshPlan
+	var feeds []scored
+	for _, feedID := range feedIDs {
+		feed, ok := byID[feedID]
+		if !ok || !feed.LastUpdated.Valid {
+			feeds = append(feeds, scored{id: feedID, score: math.Inf(1)})
+			continue
+		}
+		if feed.CacheControlMaxAge.Valid && now.Before(feed.LastUpdated.Time.Add(time.Duration(feed.CacheControlMaxAge.Int64)*time.Second)) {
+			plan.Fresh++
+			continue
+		}
+		since := now.Sub(feed.LastUpdated.Time)
+		score := float64(feed.RecentItems) * since.Hours() / recentItemsWindow.Hours()
+		if feed.ConsecutiveFailures > 0 {
+			score = -1
+		}
+		feeds = append(feeds, scored{id: feedID, score: score, since: since})
+	}
+
+	// Equally likely feeds go longest unchecked first
+	slices.SortStableFunc(feeds, func(a, b scored) int {
+		if a.score != b.score {
+			if a.score > b.score {
+				return -1
+			}
+			return 1
+		}
+		if a.since != b.since {
+			if a.since > b.since {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	})
+	for _, feed := range feeds {
+		plan.FeedIDs = append(plan.FeedIDs, feed.id)
+	}
+	return plan
+}
@@ -0,0 +1,73 @@
+package feeds
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+func TestPlanRefresh(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	fetched := func(ago time.Duration) sql.NullTime {
+		return sql.NullTime{Time: now.Add(-ago), Valid: true}
+	}
+	stats := []database.GetFeedRefreshStatsRow{
+		// Still fresh for another half hour
+		{ID: 1, LastUpdated: fetched(30 * time.Minute), CacheControlMaxAge: sql.NullInt64{Int64: 3600, Valid: true}, RecentItems: 100},
+		// Quiet feeds, the one unchecked for longer goes first
+		{ID: 2, LastUpdated: fetched(time.Hour)},
+		{ID: 3, LastUpdated: fetched(2 * time.Hour)},
+		// Busy, its max-age has passed
+		{ID: 4, LastUpdated: fetched(2 * time.Hour), CacheControlMaxAge: sql.NullInt64{Int64: 3600, Valid: true}, RecentItems: 60},
+		// Busier but checked recently
+		{ID: 5, LastUpdated: fetched(10 * time.Minute), RecentItems: 300},
+		// Never fetched
+		{ID: 6},
+		// Failing
+		{ID: 7, LastUpdated: fetched(5 * time.Hour), ConsecutiveFailures: 3, RecentItems: 100},
+	}
+
+	plan := planRefresh([]int64{1, 2, 3, 4, 5, 6, 7}, stats, now)
+	if want := []int64{6, 4, 5, 3, 2, 7}; !reflect.DeepEqual(plan.FeedIDs, want) {
+		t.Errorf("FeedIDs = %v, want %v", plan.FeedIDs, want)
+	}
+	if plan.Fresh != 1 {
+		t.Errorf("Fresh = %d, want 1", plan.Fresh)
+	}
+}
+
+func TestPlanRefreshAll(t *testing.T) {
+	db, queries := openTestDB(t)
+	ctx := context.Background()
+	quiet, err := queries.CreateFeed(ctx, database.CreateFeedParams{Url: "https://example.com/quiet.xml", Title: "Quiet"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	busy, err := queries.CreateFeed(ctx, database.CreateFeedParams{Url: "https://example.com/busy.xml", Title: "Busy"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	for _, feed := range []database.Feed{quiet, busy} {
+		if err := queries.UpdateFeed(ctx, database.UpdateFeedParams{ID: feed.ID, Title: feed.Title, LastUpdated: sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true}}); err != nil {
+			t.Fatalf("UpdateFeed() error = %v", err)
+		}
+	}
+	for _, guid := range []string{"a", "b"} {
+		if _, err := queries.UpsertItem(ctx, database.UpsertItemParams{FeedID: busy.ID, Guid: guid}); err != nil {
+			t.Fatalf("UpsertItem() error = %v", err)
+		}
+	}
+
+	m := NewManager(db, queries)
+	plan, err := m.PlanRefreshAll([]int64{quiet.ID, busy.ID}, time.Now())
+	if err != nil {
+		t.Fatalf("PlanRefreshAll() error = %v", err)
+	}
+	if want := []int64{busy.ID, quiet.ID}; !reflect.DeepEqual(plan.FeedIDs, want) {
+		t.Errorf("FeedIDs = %v, want the feed with new items first %v", plan.FeedIDs, want)
+	}
+}
@@ -0,0 +1,71 @@
+package feeds
+
+import (
+	"context"
+
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// SetRetentionPolicy sets which items are pruned after every refresh
+func (m *Manager) SetRetentionPolicy(policy config.RetentionPolicy) {
+	m.retentionMutex.Lock()
+	defer m.retentionMutex.Unlock()
+	m.retentionPolicy = policy
+}
+
+// RetentionPolicy returns the policy set with SetRetentionPolicy
+func (m *Manager) RetentionPolicy() config.RetentionPolicy {
+	m.retentionMutex.RLock()
+	defer m.retentionMutex.RUnlock()
+	return m.retentionPolicy
+}
+
+// PruneFeedItems deletes the items of a feed that are too old or beyond the
+// newest policy.MaxItems. Items still in the feed's latest fetch are kept,
+// deleting them would bring them back as unread on the next refresh.
+func (m *Manager) PruneFeedItems(ctx context.Context, feedID int64, policy config.RetentionPolicy) (int64, error) {
+	if !policy.Enabled() {
+		return 0, nil
+	}
+
+	m.writeMutex.Lock()
+	pruned, err := m.queries.PruneFeedItems(ctx, database.PruneFeedItemsParams{
+		FeedID:      feedID,
+		KeepStarred: policy.KeepStarred,
+		KeepUnread:  policy.KeepUnread,
+		MaxAgeDays:  int64(policy.MaxAgeDays),
+		MaxItems:    int64(policy.MaxItems),
+	})
+	m.writeMutex.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	if pruned > 0 {
+		logging.DebugCategory(logging.CategoryDB, "Pruned items", "feedID", feedID, "items", pruned)
+	}
+	return pruned, nil
+}
+
+// PruneAllFeeds prunes the items of every feed and returns how many were deleted
+func (m *Manager) PruneAllFeeds(ctx context.Context, policy config.RetentionPolicy) (int64, error) {
+	feeds, err := m.queries.ListAllFeeds(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for i, feed := range feeds {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		pruned, err := m.PruneFeedItems(ctx, feed.ID, policy)
+		if err != nil {
+			return total, err
+		}
+		total += pruned
+		reportProgress(ctx, Progress{Stage: "pruned", Done: int64(i + 1), Total: int64(len(feeds)), Unit: "feeds"})
+	}
+	return total, nil
+}
@@ -0,0 +1,73 @@
+package feeds
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jarv/newsgoat/internal/logging"
+	"github.com/jarv/newsgoat/internal/rules"
+)
+
+// GetItemRules returns the rules of every feed, the ones stored rules that
+// no longer compile are left out and logged
+func (m *Manager) GetItemRules() ([]rules.Rule, error) {
+	stored, err := m.queries.ListItemRules(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	compiled, errs := rules.FromDB(stored)
+	for _, err := range errs {
+		logging.Warn("Skipping item rule", "error", err)
+	}
+	return compiled, nil
+}
+
+// AddItemRule stores a rule, rules hiding or marking read items apply from
+// the next refresh on
+func (m *Manager) AddItemRule(rule rules.Rule) (rules.Rule, error) {
+	m.writeMutex.Lock()
+	stored, err := m.queries.CreateItemRule(context.Background(), rule.Params())
+	m.writeMutex.Unlock()
+	if err != nil {
+		return rules.Rule{}, err
+	}
+	rule.ID = stored.ID
+	return rule, nil
+}
+
+// DeleteItemRule removes a rule
+func (m *Manager) DeleteItemRule(ruleID int64) error {
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
+	return m.queries.DeleteItemRule(context.Background(), ruleID)
+}
+
+// feedItemRules returns the rules applying to a feed's items, none when they
+// can't be loaded so a broken rule doesn't stop refreshes
+func (m *Manager) feedItemRules(feedID int64) []rules.Rule {
+	stored, err := m.queries.ListFeedItemRules(context.Background(), sql.NullInt64{Int64: feedID, Valid: true})
+	if err != nil {
+		logging.Warn("Failed to load item rules", "feedID", feedID, "error", err)
+		return nil
+	}
+	compiled, errs := rules.FromDB(stored)
+	for _, err := range errs {
+		logging.Warn("Skipping item rule", "error", err)
+	}
+	return compiled
+}
+
+// itemGUIDSet returns the GUIDs of the items a feed already has, to tell the
+// new items of a refresh apart
+func (m *Manager) itemGUIDSet(feedID int64) map[string]bool {
+	guids, err := m.queries.GetItemGUIDs(context.Background(), feedID)
+	if err != nil {
+		logging.Warn("Failed to load item GUIDs", "feedID", feedID, "error", err)
+		return nil
+	}
+	set := make(map[string]bool, len(guids))
+	for _, guid := range guids {
+		set[guid] = true
+	}
+	return set
+}
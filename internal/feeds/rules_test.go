@@ -0,0 +1,107 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/notify"
+	"github.com/jarv/newsgoat/internal/rules"
+)
+
+func TestRefreshFeedItemRules(t *testing.T) {
+	var mu sync.Mutex
+	items := `<item><title>Bump old</title><guid>old</guid><author>dependabot@example.com</author></item>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = io.WriteString(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>Example</title>`+items+`</channel></rss>`)
+	}))
+	defer server.Close()
+
+	db, queries := openTestDB(t)
+	ctx := context.Background()
+	feed, err := queries.CreateFeed(ctx, database.CreateFeedParams{Url: server.URL, Title: "Example"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	m := NewManager(db, queries)
+	var sent []string
+	m.notifier = notify.NewNotifier(func(title, body string) error {
+		sent = append(sent, title+"|"+body)
+		return nil
+	})
+	if err := m.RefreshFeed(feed.ID); err != nil {
+		t.Fatalf("RefreshFeed() error = %v", err)
+	}
+
+	hide, err := rules.Parse("hide title (?i)^sponsored")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hide.FeedID = feed.ID
+	read, err := rules.Parse("read author ^dependabot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	highlight, err := rules.Parse("highlight=196,notify title ^Release")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rule := range []rules.Rule{hide, read, highlight} {
+		if _, err := m.AddItemRule(rule); err != nil {
+			t.Fatalf("AddItemRule() error = %v", err)
+		}
+	}
+
+	mu.Lock()
+	items += `<item><title>SPONSORED: buy this</title><guid>ad</guid></item>
+<item><title>Bump new</title><guid>new</guid><author>dependabot@example.com</author></item>
+<item><title>Release</title><guid>release</guid></item>`
+	mu.Unlock()
+	if err := m.RefreshFeed(feed.ID); err != nil {
+		t.Fatalf("RefreshFeed() error = %v", err)
+	}
+	m.FlushNotifications()
+	if want := []string{"1 highlighted item|Example: Release"}; fmt.Sprint(sent) != fmt.Sprint(want) {
+		t.Errorf("notifications = %q, want %q", sent, want)
+	}
+
+	saved, err := m.GetItemsWithReadStatus(feed.ID)
+	if err != nil {
+		t.Fatalf("GetItemsWithReadStatus() error = %v", err)
+	}
+	got := make(map[string]bool)
+	for _, item := range saved {
+		got[item.Guid] = item.Read
+	}
+	// Rules only mark new items read, the one saved before them keeps its state
+	want := map[string]bool{"old": false, "new": true, "release": false}
+	if len(got) != len(want) {
+		t.Fatalf("saved items = %v, want %v", got, want)
+	}
+	for guid, read := range want {
+		if r, ok := got[guid]; !ok || r != read {
+			t.Errorf("item %s read = %v (saved %v), want %v", guid, r, ok, read)
+		}
+	}
+
+	all, err := m.GetItemRules()
+	if err != nil || len(all) != 3 {
+		t.Fatalf("GetItemRules() = %v, %v, want 3 rules", all, err)
+	}
+	if all[2].String() != "highlight=196,notify title ^Release" {
+		t.Errorf("stored highlight rule = %q", all[2].String())
+	}
+	if err := m.DeleteItemRule(all[0].ID); err != nil {
+		t.Fatalf("DeleteItemRule() error = %v", err)
+	}
+	if all, _ := m.GetItemRules(); len(all) != 2 || all[0].Action != rules.Read {
+		t.Errorf("rules after deleting the hide rule = %v", all)
+	}
+}
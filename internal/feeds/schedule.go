@@ -0,0 +1,145 @@
+package feeds
+
+import (
+	"context"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+const (
+	// minScheduleWait is the shortest time between two runs of the auto-reload scheduler
+	minScheduleWait = time.Minute
+	// scheduleTolerance lets a feed that is due within this window refresh with the current run
+	scheduleTolerance = 30 * time.Second
+)
+
+// SetFeedReloadInterval sets how often a feed is refreshed automatically, 0
+// uses the global reload time
+func (m *Manager) SetFeedReloadInterval(feedID int64, interval time.Duration) error {
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
+	return m.queries.SetFeedReloadInterval(context.Background(), database.SetFeedReloadIntervalParams{
+		ReloadInterval: int64(interval / time.Second),
+		ID:             feedID,
+	})
+}
+
+// GetFeedReloadIntervals returns the feeds that have their own reload interval
+func (m *Manager) GetFeedReloadIntervals() (map[int64]time.Duration, error) {
+	rows, err := m.queries.GetFeedReloadIntervals(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	intervals := make(map[int64]time.Duration, len(rows))
+	for _, row := range rows {
+		intervals[row.ID] = time.Duration(row.ReloadInterval) * time.Second
+	}
+	return intervals, nil
+}
+
+// RefreshSchedule decides which feeds the auto-reload timer refreshes. Feeds
+// use their own reload interval when they have one and the global reload time
+// otherwise. A feed counts as refreshed when it is queued so a failing feed
+// isn't retried on every run.
+type RefreshSchedule struct {
+	intervals  map[int64]time.Duration
+	lastQueued map[int64]time.Time
+	started    time.Time
+}
+
+// NewRefreshSchedule creates a schedule that counts intervals from started
+func NewRefreshSchedule(started time.Time) *RefreshSchedule {
+	return &RefreshSchedule{
+		intervals:  make(map[int64]time.Duration),
+		lastQueued: make(map[int64]time.Time),
+		started:    started,
+	}
+}
+
+// SetIntervals replaces the per-feed reload intervals
+func (s *RefreshSchedule) SetIntervals(intervals map[int64]time.Duration) {
+	s.intervals = intervals
+}
+
+func (s *RefreshSchedule) interval(feedID int64, defaultInterval time.Duration) time.Duration {
+	if interval, ok := s.intervals[feedID]; ok && interval > 0 {
+		return interval
+	}
+	return defaultInterval
+}
+
+// NextRefresh returns when the feed is due next and how often it is due
+func (s *RefreshSchedule) NextRefresh(feedID int64, defaultInterval time.Duration) (time.Time, time.Duration) {
+	return s.nextRefresh(feedID, defaultInterval), s.interval(feedID, defaultInterval)
+}
+
+func (s *RefreshSchedule) nextRefresh(feedID int64, defaultInterval time.Duration) time.Time {
+	last, ok := s.lastQueued[feedID]
+	if !ok {
+		last = s.started
+	}
+	return last.Add(s.interval(feedID, defaultInterval))
+}
+
+// Due returns the feeds whose interval has elapsed and records them as queued
+func (s *RefreshSchedule) Due(feedIDs []int64, defaultInterval time.Duration, now time.Time) []int64 {
+	var due []int64
+	for _, feedID := range feedIDs {
+		if !now.Add(scheduleTolerance).Before(s.nextRefresh(feedID, defaultInterval)) {
+			due = append(due, feedID)
+			s.lastQueued[feedID] = now
+		}
+	}
+	return due
+}
+
+// Skip records the feeds as queued without refreshing them, so they wait a full interval
+func (s *RefreshSchedule) Skip(feedIDs []int64, now time.Time) {
+	for _, feedID := range feedIDs {
+		s.lastQueued[feedID] = now
+	}
+}
+
+// Next returns how long to wait until the next feed is due
+func (s *RefreshSchedule) Next(feedIDs []int64, defaultInterval time.Duration, now time.Time) time.Duration {
+	wait := defaultInterval
+	for _, feedID := range feedIDs {
+		if untilDue := s.nextRefresh(feedID, defaultInterval).Sub(now); untilDue < wait {
+			wait = untilDue
+		}
+	}
+	if wait < minScheduleWait {
+		wait = minScheduleWait
+	}
+	return wait
+}
+
+// FetchEligibleAt returns when a refresh fetches the feed instead of
+// skipping it, while the Retry-After its server sent or its Cache-Control
+// max-age hasn't passed, and which of them holds it back. It is the zero
+// time when the next refresh fetches it.
+func FetchEligibleAt(feed database.Feed, now time.Time) (time.Time, string) {
+	if feed.RetryAfter.Valid && now.Before(feed.RetryAfter.Time) {
+		return feed.RetryAfter.Time, "Retry-After"
+	}
+	if feed.CacheControlMaxAge.Valid && feed.LastUpdated.Valid {
+		expiry := feed.LastUpdated.Time.Add(time.Duration(feed.CacheControlMaxAge.Int64) * time.Second)
+		if now.Before(expiry) {
+			return expiry, "Cache-Control max-age"
+		}
+	}
+	return time.Time{}, ""
+}
+
+// NextFetch returns the first of the refreshes due at next and every
+// interval after it that fetches the feed, the ones before it is eligible
+// skip it
+func NextFetch(next time.Time, interval time.Duration, eligible time.Time) time.Time {
+	if interval <= 0 || !next.Before(eligible) {
+		return next
+	}
+	steps := (eligible.Sub(next) + interval - 1) / interval
+	return next.Add(steps * interval)
+}
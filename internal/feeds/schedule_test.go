@@ -0,0 +1,95 @@
+package feeds
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+func TestRefreshSchedule(t *testing.T) {
+	start := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	feedIDs := []int64{1, 2, 3}
+	defaultInterval := time.Hour
+
+	s := NewRefreshSchedule(start)
+	s.SetIntervals(map[int64]time.Duration{2: 45 * time.Minute})
+
+	// The feed with its own interval is due first
+	if got := s.Next(feedIDs, defaultInterval, start); got != 45*time.Minute {
+		t.Fatalf("Next() = %v, want 45m", got)
+	}
+
+	now := start.Add(45 * time.Minute)
+	if got := s.Due(feedIDs, defaultInterval, now); !reflect.DeepEqual(got, []int64{2}) {
+		t.Fatalf("Due() at 45m = %v, want [2]", got)
+	}
+	if got := s.Next(feedIDs, defaultInterval, now); got != 15*time.Minute {
+		t.Errorf("Next() after refreshing feed 2 = %v, want 15m", got)
+	}
+
+	// A timer firing slightly early still refreshes the feeds that are due
+	now = start.Add(time.Hour - 10*time.Second)
+	if got := s.Due(feedIDs, defaultInterval, now); !reflect.DeepEqual(got, []int64{1, 3}) {
+		t.Fatalf("Due() at 1h = %v, want [1 3]", got)
+	}
+
+	// Nothing is due right after a run, the wait never drops below a minute
+	if got := s.Due(feedIDs, defaultInterval, now); got != nil {
+		t.Errorf("Due() right after a run = %v, want none", got)
+	}
+	if got := s.Next(feedIDs, defaultInterval, now); got != 30*time.Minute+10*time.Second {
+		t.Errorf("Next() after refreshing feeds 1 and 3 = %v, want 30m10s", got)
+	}
+	if got := s.Next(feedIDs, defaultInterval, start.Add(2*time.Hour)); got != minScheduleWait {
+		t.Errorf("Next() for an overdue feed = %v, want %v", got, minScheduleWait)
+	}
+}
+
+func TestRefreshScheduleSkip(t *testing.T) {
+	start := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	s := NewRefreshSchedule(start)
+
+	now := start.Add(time.Hour)
+	s.Skip([]int64{1}, now)
+	if got := s.Due([]int64{1}, time.Hour, now); got != nil {
+		t.Errorf("Due() after Skip() = %v, want none", got)
+	}
+	if got := s.Due([]int64{1}, time.Hour, now.Add(time.Hour)); !reflect.DeepEqual(got, []int64{1}) {
+		t.Errorf("Due() an interval after Skip() = %v, want [1]", got)
+	}
+}
+
+func TestNextFetch(t *testing.T) {
+	now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	lastUpdated := sql.NullTime{Time: now.Add(-10 * time.Minute), Valid: true}
+
+	feed := database.Feed{LastUpdated: lastUpdated, CacheControlMaxAge: sql.NullInt64{Int64: 3600, Valid: true}}
+	eligible, reason := FetchEligibleAt(feed, now)
+	if want := now.Add(50 * time.Minute); !eligible.Equal(want) || reason != "Cache-Control max-age" {
+		t.Errorf("FetchEligibleAt() = %v, %q, want %v for the max-age", eligible, reason, want)
+	}
+
+	// Retry-After wins over the max-age
+	feed.RetryAfter = sql.NullTime{Time: now.Add(2 * time.Hour), Valid: true}
+	if eligible, reason := FetchEligibleAt(feed, now); !eligible.Equal(feed.RetryAfter.Time) || reason != "Retry-After" {
+		t.Errorf("FetchEligibleAt() = %v, %q, want the Retry-After", eligible, reason)
+	}
+
+	// An expired max-age doesn't hold the feed back
+	feed = database.Feed{LastUpdated: lastUpdated, CacheControlMaxAge: sql.NullInt64{Int64: 60, Valid: true}}
+	if eligible, _ := FetchEligibleAt(feed, now); !eligible.IsZero() {
+		t.Errorf("FetchEligibleAt() = %v, want zero for an expired max-age", eligible)
+	}
+
+	// The refreshes before the feed is eligible skip it
+	next := now.Add(15 * time.Minute)
+	if got := NextFetch(next, 15*time.Minute, now.Add(50*time.Minute)); !got.Equal(now.Add(time.Hour)) {
+		t.Errorf("NextFetch() = %v, want the refresh at 1h", got)
+	}
+	if got := NextFetch(next, 15*time.Minute, time.Time{}); !got.Equal(next) {
+		t.Errorf("NextFetch() = %v, want the next refresh for an eligible feed", got)
+	}
+}
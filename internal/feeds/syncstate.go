@@ -0,0 +1,84 @@
+package feeds
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+// syncCursorKey is the setting holding when a sync service was last synced
+func syncCursorKey(service string) string {
+	return "sync_cursor_" + service
+}
+
+// GetSyncEntries returns the entries of a sync service with the current state
+// of their items
+func (m *Manager) GetSyncEntries(ctx context.Context, service string) ([]database.GetSyncEntriesRow, error) {
+	return m.queries.GetSyncEntries(ctx, service)
+}
+
+// FindSyncItem finds the item of an entry by its link, found is false when
+// no feed has it yet
+func (m *Manager) FindSyncItem(ctx context.Context, feedURL, link string) (database.FindSyncItemRow, bool, error) {
+	if link == "" {
+		return database.FindSyncItemRow{}, false, nil
+	}
+	item, err := m.queries.FindSyncItem(ctx, database.FindSyncItemParams{Link: link, Url: feedURL})
+	if errors.Is(err, sql.ErrNoRows) {
+		return item, false, nil
+	}
+	return item, err == nil, err
+}
+
+// SaveSyncEntry stores the state both sides agreed on for an entry
+func (m *Manager) SaveSyncEntry(ctx context.Context, entry database.UpsertSyncEntryParams) error {
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
+	return m.queries.UpsertSyncEntry(ctx, entry)
+}
+
+// PruneSyncEntries deletes the entries of a service that were never matched
+// to an item and haven't changed since before
+func (m *Manager) PruneSyncEntries(ctx context.Context, service string, before time.Time) (int64, error) {
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
+	return m.queries.PruneSyncEntries(ctx, database.PruneSyncEntriesParams{Service: service, UpdatedAt: before})
+}
+
+// SyncCursor returns when a sync service was last synced, the zero time
+// before the first sync
+func (m *Manager) SyncCursor(ctx context.Context, service string) (time.Time, error) {
+	setting, err := m.queries.GetSetting(ctx, syncCursorKey(service))
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	cursor, err := time.Parse(time.RFC3339, setting.Value)
+	if err != nil {
+		// Start over rather than failing every sync
+		return time.Time{}, nil
+	}
+	return cursor, nil
+}
+
+// SetSyncCursor records when a sync service was last synced
+func (m *Manager) SetSyncCursor(ctx context.Context, service string, cursor time.Time) error {
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
+	return m.queries.SetSetting(ctx, database.SetSettingParams{
+		Key:   syncCursorKey(service),
+		Value: cursor.UTC().Format(time.RFC3339),
+	})
+}
+
+// SyncClient returns the HTTP client sync services are reached with, it goes
+// through the proxy from the settings
+func (m *Manager) SyncClient() *http.Client {
+	return &http.Client{Timeout: FeedTimeout, Transport: m.globalTransport()}
+}
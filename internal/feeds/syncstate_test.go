@@ -0,0 +1,68 @@
+package feeds
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+func TestSyncEntries(t *testing.T) {
+	db, queries := openTestDB(t)
+	ctx := context.Background()
+	m := NewManager(db, queries)
+
+	feed, err := queries.CreateFeed(ctx, database.CreateFeedParams{Url: "https://example.com/feed.xml", Title: "Example"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	item, err := queries.UpsertItem(ctx, database.UpsertItemParams{FeedID: feed.ID, Guid: "a", Link: "https://example.com/a"})
+	if err != nil {
+		t.Fatalf("UpsertItem() error = %v", err)
+	}
+	if err := m.MarkItemRead(item.ID); err != nil {
+		t.Fatalf("MarkItemRead() error = %v", err)
+	}
+
+	found, ok, err := m.FindSyncItem(ctx, "https://moved.example.com/feed.xml", "https://example.com/a")
+	if err != nil || !ok || found.ID != item.ID || !found.Read {
+		t.Fatalf("FindSyncItem() = %+v, %v, %v", found, ok, err)
+	}
+	if _, ok, err := m.FindSyncItem(ctx, feed.Url, "https://example.com/missing"); ok || err != nil {
+		t.Fatalf("FindSyncItem() of a missing link = %v, %v", ok, err)
+	}
+
+	now := time.Now()
+	for _, entry := range []database.UpsertSyncEntryParams{
+		{Service: "miniflux", RemoteID: "1", ItemID: sql.NullInt64{Int64: item.ID, Valid: true}, Url: "https://example.com/a", UpdatedAt: now},
+		{Service: "miniflux", RemoteID: "2", Url: "https://example.com/b", UpdatedAt: now.Add(-48 * time.Hour)},
+	} {
+		if err := m.SaveSyncEntry(ctx, entry); err != nil {
+			t.Fatalf("SaveSyncEntry() error = %v", err)
+		}
+	}
+
+	// Only unmatched entries are pruned
+	if pruned, err := m.PruneSyncEntries(ctx, "miniflux", now.Add(-24*time.Hour)); err != nil || pruned != 1 {
+		t.Fatalf("PruneSyncEntries() = %d, %v, want 1", pruned, err)
+	}
+	rows, err := m.GetSyncEntries(ctx, "miniflux")
+	if err != nil {
+		t.Fatalf("GetSyncEntries() error = %v", err)
+	}
+	if len(rows) != 1 || rows[0].ItemID.Int64 != item.ID || rows[0].Read || !rows[0].LocalRead {
+		t.Errorf("GetSyncEntries() = %+v", rows)
+	}
+
+	if cursor, err := m.SyncCursor(ctx, "miniflux"); err != nil || !cursor.IsZero() {
+		t.Fatalf("SyncCursor() before the first sync = %v, %v", cursor, err)
+	}
+	if err := m.SetSyncCursor(ctx, "miniflux", now); err != nil {
+		t.Fatalf("SetSyncCursor() error = %v", err)
+	}
+	if cursor, err := m.SyncCursor(ctx, "miniflux"); err != nil || cursor.Unix() != now.Unix() {
+		t.Errorf("SyncCursor() = %v, %v, want %v", cursor, err, now)
+	}
+}
@@ -0,0 +1,198 @@
+package feeds
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// maxReportItems is the number of new item titles listed in a fetch report
+const maxReportItems = 20
+
+// FetchReport describes what refreshing a feed would do, see TestFetch
+type FetchReport struct {
+	URL      string // Redacted feed URL
+	FinalURL string // Redacted URL after redirects, empty when there were none
+	Duration time.Duration
+
+	// CachedUntil is when a refresh would fetch the feed again, it is skipped
+	// until then because of the Cache-Control max-age of the last response
+	CachedUntil time.Time
+
+	// Conditional request headers from the last fetch, the server answers
+	// 304 Not Modified when they still match
+	IfNoneMatch     string
+	IfModifiedSince string
+
+	StatusCode      int
+	Status          string
+	ResponseHeaders http.Header // ETag, Last-Modified, Cache-Control and Content-Type of the response
+
+	FeedTitle string
+	FeedType  string // e.g. "rss 2.0" or "atom 1.0"
+	Items     int
+	NewItems  []string // Titles of the items a refresh would add, at most maxReportItems
+	NewCount  int
+
+	Warnings []string
+	Err      error // Why the fetch or parse failed, the fields above show how far it got
+}
+
+// reportHeaders are the response headers that decide whether a feed is fetched again
+var reportHeaders = []string{"ETag", "Last-Modified", "Cache-Control", "Content-Type"}
+
+// TestFetch fetches and parses a feed the way RefreshFeed does without
+// writing anything to the database, to find out why a feed never updates
+func (m *Manager) TestFetch(ctx context.Context, feedID int64) (FetchReport, error) {
+	feed, err := m.queries.GetFeed(ctx, feedID)
+	if err != nil {
+		return FetchReport{}, err
+	}
+
+	report := FetchReport{URL: logging.Redact(feed.Url)}
+	if feed.Etag.Valid {
+		report.IfNoneMatch = feed.Etag.String
+	}
+	if feed.LastModified.Valid {
+		report.IfModifiedSince = feed.LastModified.String
+	}
+	if feed.CacheControlMaxAge.Valid && feed.LastUpdated.Valid {
+		cacheExpiry := feed.LastUpdated.Time.Add(time.Duration(feed.CacheControlMaxAge.Int64) * time.Second)
+		if time.Now().Before(cacheExpiry) {
+			report.CachedUntil = cacheExpiry
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, FeedTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", m.addFeedTokenIfNeeded(feed.Url), nil)
+	if err != nil {
+		report.Err = err
+		return report, nil
+	}
+
+	start := time.Now()
+	resp, err := m.createHTTPClientForFeed(feed.Url).Do(req)
+	report.Duration = time.Since(start)
+	if err != nil {
+		report.Err = err
+		return report, nil
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	report.StatusCode = resp.StatusCode
+	report.Status = http.StatusText(resp.StatusCode)
+	if resp.Request != nil && resp.Request.URL.String() != req.URL.String() {
+		report.FinalURL = logging.Redact(resp.Request.URL.String())
+		report.Warnings = append(report.Warnings, "The feed redirects, consider updating its URL in the URLs file")
+	}
+	report.ResponseHeaders = make(http.Header)
+	for _, name := range reportHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			report.ResponseHeaders.Set(name, value)
+		}
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		report.Warnings = append(report.Warnings, "Not modified: the server says nothing changed since the last fetch")
+		return report, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		report.Err = fmt.Errorf("HTTP %d: %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+		return report, nil
+	}
+
+	if report.IfNoneMatch != "" && resp.Header.Get("ETag") == report.IfNoneMatch {
+		report.Warnings = append(report.Warnings, "The server sent the same ETag with a full response instead of 304, it ignores If-None-Match")
+	}
+	if !report.CachedUntil.IsZero() {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("Refreshes skip this feed until %s because of its Cache-Control max-age", report.CachedUntil.Local().Format("2006-01-02 15:04")))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		report.Err = err
+		return report, nil
+	}
+	if hashBody(body) == feed.BodyHash {
+		report.Warnings = append(report.Warnings, "Same body as the last fetch, a refresh handles it like 304 Not Modified and skips the items")
+	}
+
+	parsedFeed, err := m.parser.Parse(bytes.NewReader(body))
+	if err != nil {
+		report.Err = fmt.Errorf("parse error: %w", err)
+		return report, nil
+	}
+	report.FeedTitle = parsedFeed.Title
+	report.FeedType = parsedFeed.FeedType + " " + parsedFeed.FeedVersion
+	report.Items = len(parsedFeed.Items)
+
+	guids, err := m.queries.GetItemGUIDs(ctx, feedID)
+	if err != nil {
+		return report, err
+	}
+	known := make(map[string]bool, len(guids))
+	for _, guid := range guids {
+		known[guid] = true
+	}
+
+	seen := make(map[string]bool, len(parsedFeed.Items))
+	var noGUID, noID, noDate, duplicates int
+	for _, item := range parsedFeed.Items {
+		guid := itemGUID(item)
+		switch {
+		case guid == "":
+			noID++
+		case item.GUID == "":
+			noGUID++
+		}
+		if item.PublishedParsed == nil {
+			noDate++
+		}
+		if seen[guid] {
+			duplicates++
+			continue
+		}
+		seen[guid] = true
+
+		if !known[guid] {
+			report.NewCount++
+			if len(report.NewItems) < maxReportItems {
+				report.NewItems = append(report.NewItems, item.Title)
+			}
+		}
+	}
+
+	if report.Items == 0 {
+		report.Warnings = append(report.Warnings, "The feed has no items")
+	}
+	if noID > 0 {
+		report.Warnings = append(report.Warnings, countItems(noID)+" with neither a GUID nor a link, they are stored as a single item")
+	}
+	if noGUID > 0 {
+		report.Warnings = append(report.Warnings, countItems(noGUID)+" without a GUID, the link is used instead")
+	}
+	if duplicates > 0 {
+		report.Warnings = append(report.Warnings, countItems(duplicates)+" repeating the GUID of another item, only one of each is kept")
+	}
+	if noDate > 0 {
+		report.Warnings = append(report.Warnings, countItems(noDate)+" without a date NewsGoat can read")
+	}
+
+	return report, nil
+}
+
+func countItems(n int) string {
+	if n == 1 {
+		return "1 item"
+	}
+	return fmt.Sprintf("%d items", n)
+}
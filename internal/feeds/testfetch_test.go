@@ -0,0 +1,76 @@
+package feeds
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+const testFetchFeed = `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Example</title>
+<item><title>Known</title><guid>known</guid><pubDate>Mon, 05 Oct 2026 10:00:00 GMT</pubDate></item>
+<item><title>Fresh</title><guid>fresh</guid><pubDate>Tue, 06 Oct 2026 10:00:00 GMT</pubDate></item>
+<item><title>Fresh again</title><guid>fresh</guid><pubDate>Tue, 06 Oct 2026 10:00:00 GMT</pubDate></item>
+<item><title>Undated</title><link>https://example.com/undated</link></item>
+</channel></rss>`
+
+func TestTestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v2"`)
+		w.Header().Set("Content-Type", "application/rss+xml")
+		_, _ = w.Write([]byte(testFetchFeed))
+	}))
+	defer server.Close()
+
+	db, queries := openTestDB(t)
+	ctx := context.Background()
+	feed, err := queries.CreateFeed(ctx, database.CreateFeedParams{Url: server.URL, Title: "Example"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	if _, err := queries.UpsertItem(ctx, database.UpsertItemParams{FeedID: feed.ID, Guid: "known", Title: "Known"}); err != nil {
+		t.Fatalf("UpsertItem() error = %v", err)
+	}
+
+	m := NewManager(db, queries)
+	report, err := m.TestFetch(ctx, feed.ID)
+	if err != nil {
+		t.Fatalf("TestFetch() error = %v", err)
+	}
+	if report.Err != nil {
+		t.Fatalf("report.Err = %v", report.Err)
+	}
+	if report.StatusCode != http.StatusOK || report.ResponseHeaders.Get("ETag") != `"v2"` {
+		t.Errorf("unexpected response: %d %v", report.StatusCode, report.ResponseHeaders)
+	}
+	if report.Items != 4 || report.NewCount != 2 || report.NewItems[0] != "Fresh" || report.NewItems[1] != "Undated" {
+		t.Errorf("items = %d, new = %d %v, want 4 items with Fresh and Undated new", report.Items, report.NewCount, report.NewItems)
+	}
+
+	warnings := strings.Join(report.Warnings, "\n")
+	for _, want := range []string{"1 item without a GUID", "1 item repeating the GUID", "1 item without a date"} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("warnings %q don't mention %q", warnings, want)
+		}
+	}
+
+	// Nothing was written
+	guids, err := queries.GetItemGUIDs(ctx, feed.ID)
+	if err != nil {
+		t.Fatalf("GetItemGUIDs() error = %v", err)
+	}
+	if len(guids) != 1 {
+		t.Errorf("TestFetch() wrote items: %v", guids)
+	}
+	stored, err := queries.GetFeed(ctx, feed.ID)
+	if err != nil {
+		t.Fatalf("GetFeed() error = %v", err)
+	}
+	if stored.Etag.Valid {
+		t.Errorf("TestFetch() stored the ETag %q", stored.Etag.String)
+	}
+}
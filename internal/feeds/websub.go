@@ -0,0 +1,410 @@
+package feeds
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"database/sql"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/logging"
+	"github.com/jarv/newsgoat/internal/version"
+)
+
+const (
+	// webSubRenewBefore is how long before its lease ends a subscription is
+	// renewed, feeds are refreshed at least daily so it doesn't lapse
+	webSubRenewBefore = 24 * time.Hour
+
+	// webSubRetryAfter is how long a hub has to verify a subscription before
+	// it is requested again
+	webSubRetryAfter = time.Hour
+
+	// defaultWebSubLease is assumed when a hub doesn't say how long a
+	// subscription lasts
+	defaultWebSubLease = 24 * time.Hour
+
+	// maxWebSubNotification is how much of a pushed update is read
+	maxWebSubNotification = 1 << 20
+)
+
+// WebSubConfig is where hubs push updates to
+type WebSubConfig struct {
+	Listen      string // Address the callback listener binds, e.g. ":8089"
+	CallbackURL string // Public URL of the listener, each feed gets CallbackURL/<feed id>
+}
+
+// NewWebSubConfig takes the WebSub settings from the config
+func NewWebSubConfig(cfg config.Config) WebSubConfig {
+	return WebSubConfig{
+		Listen:      strings.TrimSpace(cfg.WebSubListen),
+		CallbackURL: strings.TrimSuffix(strings.TrimSpace(cfg.WebSubCallbackURL), "/"),
+	}
+}
+
+// Enabled reports whether feeds subscribe to the hubs they advertise
+func (c WebSubConfig) Enabled() bool {
+	return c.Listen != "" && c.CallbackURL != ""
+}
+
+// callback is the URL a hub pushes the updates of a feed to
+func (c WebSubConfig) callback(feedID int64) string {
+	return c.CallbackURL + "/" + strconv.FormatInt(feedID, 10)
+}
+
+// StartWebSub starts the listener hubs push updates to and subscribes feeds
+// to their hubs when they are refreshed. onPush is called with the feed a hub
+// pushed an update for, the returned function stops the listener.
+func (m *Manager) StartWebSub(cfg WebSubConfig, onPush func(feedID int64, url string)) (func() error, error) {
+	listener, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		return nil, err
+	}
+
+	m.webSubMutex.Lock()
+	m.webSub = cfg
+	m.onPush = onPush
+	m.webSubMutex.Unlock()
+
+	server := &http.Server{
+		Handler:           http.HandlerFunc(m.handleWebSub),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.Error("WebSub listener stopped", "error", err)
+		}
+	}()
+	logging.Info("WebSub listener started", "address", listener.Addr().String(), "callback", cfg.CallbackURL)
+
+	return func() error {
+		m.webSubMutex.Lock()
+		m.webSub = WebSubConfig{}
+		m.onPush = nil
+		m.webSubMutex.Unlock()
+		return server.Close()
+	}, nil
+}
+
+func (m *Manager) webSubConfig() WebSubConfig {
+	m.webSubMutex.RLock()
+	defer m.webSubMutex.RUnlock()
+	return m.webSub
+}
+
+// discoverWebSub finds the hub a feed advertises and the topic URL to
+// subscribe to, in the Link header or the feed's own <link> elements
+func discoverWebSub(header http.Header, body []byte) (hub, self string) {
+	for _, value := range header.Values("Link") {
+		for _, link := range strings.Split(value, ",") {
+			href, rels := parseLinkHeader(link)
+			for _, rel := range rels {
+				switch {
+				case rel == "hub" && hub == "":
+					hub = href
+				case rel == "self" && self == "":
+					self = href
+				}
+			}
+		}
+	}
+	if hub != "" && self != "" {
+		return hub, self
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		// Only links of the feed count, not those of its items
+		if start.Name.Local == "item" || start.Name.Local == "entry" {
+			break
+		}
+		if start.Name.Local != "link" {
+			continue
+		}
+
+		var rel, href string
+		for _, attr := range start.Attr {
+			switch attr.Name.Local {
+			case "rel":
+				rel = attr.Value
+			case "href":
+				href = attr.Value
+			}
+		}
+		switch {
+		case rel == "hub" && hub == "":
+			hub = href
+		case rel == "self" && self == "":
+			self = href
+		}
+	}
+	return hub, self
+}
+
+// parseLinkHeader splits one link of a Link header, e.g.
+// <https://hub.example.com/>; rel="hub"
+func parseLinkHeader(link string) (href string, rels []string) {
+	parts := strings.Split(link, ";")
+	target := strings.TrimSpace(parts[0])
+	if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+		return "", nil
+	}
+	for _, param := range parts[1:] {
+		name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "rel") {
+			rels = append(rels, strings.Fields(strings.Trim(strings.TrimSpace(value), `"`))...)
+		}
+	}
+	return target[1 : len(target)-1], rels
+}
+
+// subscribeWebSub asks a hub to push the updates of a feed, unless the feed is
+// already subscribed or waiting for the hub to verify it. Only hubs reached
+// over https are subscribed to, the secret updates are signed with mustn't be
+// sent in the clear and unsigned updates are ignored.
+func (m *Manager) subscribeWebSub(ctx context.Context, feedID int64, hub, topic string) error {
+	cfg := m.webSubConfig()
+	if !cfg.Enabled() {
+		return nil
+	}
+	if hubURL, err := url.Parse(hub); err != nil || hubURL.Scheme != "https" {
+		logging.DebugCategory(logging.CategoryHTTP, "Not subscribing to a WebSub hub without https", "hub", hub, "topic", logging.Redact(topic))
+		return nil
+	}
+
+	sub, err := m.queries.GetWebSubSubscription(ctx, feedID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	// A subscription without a secret is requested again to get one
+	sameTopic := err == nil && sub.Hub == hub && sub.Topic == topic && sub.Secret != ""
+	if sameTopic {
+		if sub.LeaseExpires.Valid && time.Until(sub.LeaseExpires.Time) > webSubRenewBefore {
+			return nil
+		}
+		if !sub.LeaseExpires.Valid && time.Since(sub.RequestedAt) < webSubRetryAfter {
+			return nil
+		}
+	}
+
+	// Renewals keep the secret, the hub signs with the old one until it has
+	// verified the renewal
+	secret := sub.Secret
+	if !sameTopic {
+		if secret, err = newWebSubSecret(); err != nil {
+			return err
+		}
+	}
+
+	// Saved first, hubs may verify the subscription before they answer
+	m.writeMutex.Lock()
+	err = m.queries.RequestWebSubSubscription(ctx, database.RequestWebSubSubscriptionParams{
+		FeedID:      feedID,
+		Hub:         hub,
+		Topic:       topic,
+		RequestedAt: time.Now(),
+		Secret:      secret,
+	})
+	m.writeMutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{
+		"hub.mode":     {"subscribe"},
+		"hub.topic":    {topic},
+		"hub.callback": {cfg.callback(feedID)},
+		"hub.secret":   {secret},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hub, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", version.GetUserAgent())
+
+	client := &http.Client{Timeout: FeedTimeout, Transport: m.globalTransport()}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("hub answered HTTP %d: %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	logging.DebugCategory(logging.CategoryHTTP, "WebSub subscription requested", "hub", hub, "topic", logging.Redact(topic))
+	return nil
+}
+
+// handleWebSub answers the hubs, a GET verifies a subscription and a POST
+// pushes an update
+func (m *Manager) handleWebSub(w http.ResponseWriter, r *http.Request) {
+	feedID, err := strconv.ParseInt(path.Base(r.URL.Path), 10, 64)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	sub, err := m.queries.GetWebSubSubscription(r.Context(), feedID)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		m.verifyWebSub(w, r, sub)
+	case http.MethodPost:
+		m.receiveWebSub(w, r, sub)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// verifyWebSub confirms a subscription this feed requested by echoing the
+// hub's challenge
+func (m *Manager) verifyWebSub(w http.ResponseWriter, r *http.Request, sub database.WebsubSubscription) {
+	query := r.URL.Query()
+	if query.Get("hub.topic") != sub.Topic {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch query.Get("hub.mode") {
+	case "subscribe":
+		challenge := query.Get("hub.challenge")
+		if challenge == "" {
+			http.NotFound(w, r)
+			return
+		}
+		lease := defaultWebSubLease
+		if seconds, err := strconv.Atoi(query.Get("hub.lease_seconds")); err == nil && seconds > 0 {
+			lease = time.Duration(seconds) * time.Second
+		}
+		m.writeMutex.Lock()
+		err := m.queries.SetWebSubLease(r.Context(), database.SetWebSubLeaseParams{
+			LeaseExpires: sql.NullTime{Time: time.Now().Add(lease), Valid: true},
+			FeedID:       sub.FeedID,
+		})
+		m.writeMutex.Unlock()
+		if err != nil {
+			logging.Error("Failed to save WebSub lease", "topic", logging.Redact(sub.Topic), "error", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		logging.Info("WebSub subscription verified", "hub", sub.Hub, "topic", logging.Redact(sub.Topic), "lease", lease.String())
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = io.WriteString(w, challenge)
+
+	case "denied":
+		m.writeMutex.Lock()
+		err := m.queries.DeleteWebSubSubscription(r.Context(), sub.FeedID)
+		m.writeMutex.Unlock()
+		if err != nil {
+			logging.Error("Failed to delete WebSub subscription", "topic", logging.Redact(sub.Topic), "error", err)
+		}
+		logging.Warn("WebSub hub denied the subscription", "hub", sub.Hub, "topic", logging.Redact(sub.Topic), "reason", query.Get("hub.reason"))
+		w.WriteHeader(http.StatusOK)
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// receiveWebSub handles an update pushed by a hub. The pushed content isn't
+// trusted, the feed is fetched again from its own URL. Updates that aren't
+// signed with the subscription's secret are ignored, as are all updates of a
+// subscription without one, hubs are still told they were received so they
+// can't probe for the secret.
+func (m *Manager) receiveWebSub(w http.ResponseWriter, r *http.Request, sub database.WebsubSubscription) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebSubNotification))
+	w.WriteHeader(http.StatusAccepted)
+	if err != nil {
+		return
+	}
+	if sub.Secret == "" || !validWebSubSignature(sub.Secret, r.Header.Get("X-Hub-Signature"), body) {
+		logging.Warn("Ignoring WebSub update with an invalid signature", "hub", sub.Hub, "topic", logging.Redact(sub.Topic))
+		return
+	}
+
+	m.webSubMutex.RLock()
+	onPush := m.onPush
+	m.webSubMutex.RUnlock()
+	if onPush == nil {
+		return
+	}
+
+	feed, err := m.queries.GetFeed(r.Context(), sub.FeedID)
+	if err != nil || feed.Paused {
+		return
+	}
+	logging.DebugCategory(logging.CategoryHTTP, "WebSub update pushed", "url", feed.Url)
+	onPush(feed.ID, feed.Url)
+}
+
+// newWebSubSecret returns a random secret for a hub to sign updates with
+func newWebSubSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// webSubSignatureHashes are the hashes hubs sign updates with, by the name
+// X-Hub-Signature gives them
+var webSubSignatureHashes = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha384": sha512.New384,
+	"sha512": sha512.New,
+}
+
+// validWebSubSignature checks an X-Hub-Signature header, "method=signature"
+// where signature is the hex HMAC of the body keyed with the secret
+func validWebSubSignature(secret, header string, body []byte) bool {
+	method, signature, ok := strings.Cut(header, "=")
+	if !ok {
+		return false
+	}
+	newHash, ok := webSubSignatureHashes[strings.ToLower(method)]
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,309 @@
+package feeds
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+func TestDiscoverWebSub(t *testing.T) {
+	tests := []struct {
+		name     string
+		link     string
+		body     string
+		wantHub  string
+		wantSelf string
+	}{
+		{
+			name:     "link header",
+			link:     `<https://hub.example.com/>; rel="hub", <https://example.com/feed.xml>; rel="self"`,
+			wantHub:  "https://hub.example.com/",
+			wantSelf: "https://example.com/feed.xml",
+		},
+		{
+			name: "atom",
+			body: `<feed xmlns="http://www.w3.org/2005/Atom"><link rel="hub" href="https://hub.example.com/"/>
+				<link rel="self" href="https://example.com/atom.xml"/><entry><link rel="hub" href="https://other.example.com/"/></entry></feed>`,
+			wantHub:  "https://hub.example.com/",
+			wantSelf: "https://example.com/atom.xml",
+		},
+		{
+			name: "rss with atom links",
+			body: `<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom"><channel><link>https://example.com/</link>
+				<atom:link rel="hub" href="https://hub.example.com/"/></channel></rss>`,
+			wantHub: "https://hub.example.com/",
+		},
+		{
+			name: "no hub",
+			body: `<rss version="2.0"><channel><link>https://example.com/</link></channel></rss>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.link != "" {
+				header.Set("Link", tt.link)
+			}
+			hub, self := discoverWebSub(header, []byte(tt.body))
+			if hub != tt.wantHub || self != tt.wantSelf {
+				t.Errorf("discoverWebSub() = %q, %q, want %q, %q", hub, self, tt.wantHub, tt.wantSelf)
+			}
+		})
+	}
+}
+
+func TestWebSubSubscription(t *testing.T) {
+	// The hub verifies every subscription request right away
+	var hubMutex sync.Mutex
+	var requests []url.Values
+	var verifyErr error
+	hub := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		hubMutex.Lock()
+		requests = append(requests, r.PostForm)
+		hubMutex.Unlock()
+
+		verify := r.PostForm.Get("hub.callback") + "?" + url.Values{
+			"hub.mode":          {"subscribe"},
+			"hub.topic":         {r.PostForm.Get("hub.topic")},
+			"hub.challenge":     {"c4ll3ng3"},
+			"hub.lease_seconds": {"864000"},
+		}.Encode()
+		resp, err := http.Get(verify)
+		if err == nil {
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			if string(body) != "c4ll3ng3" {
+				err = io.ErrUnexpectedEOF
+			}
+		}
+		hubMutex.Lock()
+		verifyErr = err
+		hubMutex.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer hub.Close()
+	// Hubs only get a secret over https, the test hub's certificate is
+	// trusted for the subscription request
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = hub.Client().Transport
+	t.Cleanup(func() {
+		http.DefaultTransport = defaultTransport
+	})
+
+	var feedURL string
+	feedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/atom+xml")
+		_, _ = io.WriteString(w, `<?xml version="1.0"?>
+<feed xmlns="http://www.w3.org/2005/Atom"><title>Pushed</title>
+<link rel="hub" href="`+hub.URL+`"/><link rel="self" href="`+feedURL+`"/>
+<entry><title>First</title><id>first</id><updated>2026-10-06T10:00:00Z</updated></entry>
+</feed>`)
+	}))
+	defer feedServer.Close()
+	feedURL = feedServer.URL + "/feed.xml"
+
+	db, queries := openTestDB(t)
+	ctx := context.Background()
+	feed, err := queries.CreateFeed(ctx, database.CreateFeedParams{Url: feedURL, Title: "Pushed"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+
+	m := NewManager(db, queries)
+	callback := httptest.NewServer(http.HandlerFunc(m.handleWebSub))
+	defer callback.Close()
+	pushed := make(chan int64, 1)
+	m.webSub = WebSubConfig{Listen: "127.0.0.1:0", CallbackURL: callback.URL + "/websub"}
+	m.onPush = func(feedID int64, url string) {
+		pushed <- feedID
+	}
+
+	if err := m.RefreshFeed(feed.ID); err != nil {
+		t.Fatalf("RefreshFeed() error = %v", err)
+	}
+	hubMutex.Lock()
+	if len(requests) != 1 {
+		t.Fatalf("hub got %d requests, want 1", len(requests))
+	}
+	if got := requests[0].Get("hub.topic"); got != feedURL {
+		t.Errorf("hub.topic = %q, want %q", got, feedURL)
+	}
+	secret := requests[0].Get("hub.secret")
+	if secret == "" {
+		t.Errorf("subscription request has no hub.secret")
+	}
+	if verifyErr != nil {
+		t.Errorf("verification failed: %v", verifyErr)
+	}
+	hubMutex.Unlock()
+
+	sub, err := queries.GetWebSubSubscription(ctx, feed.ID)
+	if err != nil {
+		t.Fatalf("GetWebSubSubscription() error = %v", err)
+	}
+	if !sub.LeaseExpires.Valid {
+		t.Errorf("subscription has no lease after verification")
+	}
+
+	// A subscribed feed isn't subscribed again on the next refresh
+	if err := m.RefreshFeedContext(t.Context(), feed.ID, true); err != nil {
+		t.Fatalf("RefreshFeedContext() error = %v", err)
+	}
+	hubMutex.Lock()
+	if len(requests) != 1 {
+		t.Errorf("hub got %d requests after a second refresh, want 1", len(requests))
+	}
+	hubMutex.Unlock()
+
+	// Verification of a topic the feed didn't subscribe to is refused
+	callbackURL := m.webSub.callback(feed.ID)
+	resp, err := http.Get(callbackURL + "?hub.mode=subscribe&hub.topic=https://evil.example.com/&hub.challenge=x")
+	if err != nil {
+		t.Fatalf("GET callback error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("verifying another topic returned %d, want 404", resp.StatusCode)
+	}
+
+	// An unsigned push is acknowledged but ignored
+	resp, err = http.Post(callbackURL, "application/atom+xml", strings.NewReader("<feed/>"))
+	if err != nil {
+		t.Fatalf("POST callback error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("unsigned push returned %d, want 202", resp.StatusCode)
+	}
+	select {
+	case <-pushed:
+		t.Errorf("unsigned push called onPush")
+	default:
+	}
+
+	// A push signed with the secret queues a refresh of the feed
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("<feed/>"))
+	req, err := http.NewRequest(http.MethodPost, callbackURL, strings.NewReader("<feed/>"))
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	req.Header.Set("X-Hub-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST callback error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("push returned %d, want 202", resp.StatusCode)
+	}
+	select {
+	case feedID := <-pushed:
+		if feedID != feed.ID {
+			t.Errorf("pushed feed %d, want %d", feedID, feed.ID)
+		}
+	default:
+		t.Errorf("push didn't call onPush")
+	}
+
+	// A subscription without a secret ignores every push
+	if err := queries.RequestWebSubSubscription(ctx, database.RequestWebSubSubscriptionParams{
+		FeedID: feed.ID, Hub: hub.URL, Topic: feedURL, RequestedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("RequestWebSubSubscription() error = %v", err)
+	}
+	resp, err = http.Post(callbackURL, "application/atom+xml", strings.NewReader("<feed/>"))
+	if err != nil {
+		t.Fatalf("POST callback error = %v", err)
+	}
+	_ = resp.Body.Close()
+	select {
+	case <-pushed:
+		t.Errorf("push to a subscription without a secret called onPush")
+	default:
+	}
+}
+
+func TestWebSubPlainHTTPHub(t *testing.T) {
+	var hubRequests atomic.Int32
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hubRequests.Add(1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer hub.Close()
+
+	feedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/atom+xml")
+		_, _ = io.WriteString(w, `<?xml version="1.0"?>
+<feed xmlns="http://www.w3.org/2005/Atom"><title>Plain</title>
+<link rel="hub" href="`+hub.URL+`"/>
+<entry><title>First</title><id>first</id><updated>2026-10-06T10:00:00Z</updated></entry>
+</feed>`)
+	}))
+	defer feedServer.Close()
+
+	db, queries := openTestDB(t)
+	feed, err := queries.CreateFeed(context.Background(), database.CreateFeedParams{Url: feedServer.URL + "/feed.xml", Title: "Plain"})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	m := NewManager(db, queries)
+	m.webSub = WebSubConfig{Listen: "127.0.0.1:0", CallbackURL: "https://example.com/websub"}
+
+	if err := m.RefreshFeed(feed.ID); err != nil {
+		t.Fatalf("RefreshFeed() error = %v", err)
+	}
+	// The secret would be sent in the clear
+	if got := hubRequests.Load(); got != 0 {
+		t.Errorf("plain http hub got %d requests, want 0", got)
+	}
+}
+
+func TestValidWebSubSignature(t *testing.T) {
+	body := []byte("<feed/>")
+	sign := func(newHash func() hash.Hash, secret string) string {
+		mac := hmac.New(newHash, []byte(secret))
+		mac.Write(body)
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{"sha1", "sha1=" + sign(sha1.New, "s3cr3t"), true},
+		{"sha256", "sha256=" + sign(sha256.New, "s3cr3t"), true},
+		{"sha512", "sha512=" + sign(sha512.New, "s3cr3t"), true},
+		{"wrong secret", "sha256=" + sign(sha256.New, "guess"), false},
+		{"unknown method", "md5=" + sign(sha256.New, "s3cr3t"), false},
+		{"missing", "", false},
+		{"not hex", "sha256=zz", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validWebSubSignature("s3cr3t", tt.header, body); got != tt.want {
+				t.Errorf("validWebSubSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
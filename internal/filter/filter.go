@@ -0,0 +1,190 @@
+// Package filter parses and evaluates the expressions that narrow the feed
+// and item lists, e.g. `unread and age < 7d and title ~ "rust"`.
+//
+// An expression compares fields with values and combines the comparisons
+// with and, or, not and parentheses. A boolean field can be used on its own.
+//
+//	==, !=          equal, ignoring case and accents for text
+//	~, !~           text contains, or doesn't contain, the value
+//	<, <=, >, >=    numbers and durations
+//
+// Values are "quoted text", whole numbers, true or false, and durations like
+// 30m, 12h, 7d or 2w. A field holding a list, like the folders of a feed,
+// matches when any of its entries matches.
+package filter
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+// Kind is the type of a field's values
+type Kind int
+
+const (
+	Bool     Kind = iota
+	String        // Text, compared ignoring case and accents
+	Strings       // A list of texts
+	Number        // A whole number, int64
+	Duration      // A time.Duration
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Bool:
+		return "boolean"
+	case String:
+		return "text"
+	case Strings:
+		return "list of text"
+	case Number:
+		return "number"
+	case Duration:
+		return "duration"
+	}
+	return "unknown"
+}
+
+// Fields are the fields an expression can use and the kind of their values
+type Fields map[string]Kind
+
+// Names returns the field names, sorted
+func (f Fields) Names() []string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Record returns the value of a field of the feed or item being matched, a
+// bool, string, []string, int64 or time.Duration depending on the field's Kind
+type Record func(field string) any
+
+// Filter is a parsed expression
+type Filter struct {
+	source string
+	expr   node
+	uses   map[string]bool
+}
+
+// Parse parses an expression that may only use the given fields
+func Parse(input string, fields Fields) (*Filter, error) {
+	p := &parser{lexer: newLexer(input), fields: fields, uses: make(map[string]bool)}
+	expr, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &Filter{source: strings.TrimSpace(input), expr: expr, uses: p.uses}, nil
+}
+
+// Match reports whether a record matches the expression
+func (f *Filter) Match(record Record) bool {
+	return f.expr.eval(record)
+}
+
+// Uses reports whether the expression looks at a field, so expensive fields
+// are only computed when needed
+func (f *Filter) Uses(field string) bool {
+	return f.uses[field]
+}
+
+// String returns the expression as it was typed
+func (f *Filter) String() string {
+	return f.source
+}
+
+type node interface {
+	eval(record Record) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) eval(record Record) bool { return n.left.eval(record) && n.right.eval(record) }
+
+type orNode struct{ left, right node }
+
+func (n orNode) eval(record Record) bool { return n.left.eval(record) || n.right.eval(record) }
+
+type notNode struct{ expr node }
+
+func (n notNode) eval(record Record) bool { return !n.expr.eval(record) }
+
+// comparison compares a field with a value, a boolean field on its own is
+// compared with true
+type comparison struct {
+	field string
+	kind  Kind
+	op    string
+	value any // bool, string (folded), int64 or time.Duration
+}
+
+func (c comparison) eval(record Record) bool {
+	switch c.kind {
+	case Bool:
+		got, _ := record(c.field).(bool)
+		return (got == c.value.(bool)) == (c.op == "==")
+	case String:
+		got, _ := record(c.field).(string)
+		return matchText(database.FoldText(got), c.op, c.value.(string))
+	case Strings:
+		got, _ := record(c.field).([]string)
+		// The negated operators hold when no entry matches the positive one
+		op, negate := c.op, false
+		switch c.op {
+		case "!=":
+			op, negate = "==", true
+		case "!~":
+			op, negate = "~", true
+		}
+		for _, entry := range got {
+			if matchText(database.FoldText(entry), op, c.value.(string)) {
+				return !negate
+			}
+		}
+		return negate
+	case Number:
+		got, _ := record(c.field).(int64)
+		return compareOrdered(got, c.op, c.value.(int64))
+	case Duration:
+		got, _ := record(c.field).(time.Duration)
+		return compareOrdered(got, c.op, c.value.(time.Duration))
+	}
+	return false
+}
+
+func matchText(got, op, want string) bool {
+	switch op {
+	case "==":
+		return got == want
+	case "!=":
+		return got != want
+	case "~":
+		return strings.Contains(got, want)
+	case "!~":
+		return !strings.Contains(got, want)
+	}
+	return false
+}
+
+func compareOrdered[T int64 | time.Duration](got T, op string, want T) bool {
+	switch op {
+	case "==":
+		return got == want
+	case "!=":
+		return got != want
+	case "<":
+		return got < want
+	case "<=":
+		return got <= want
+	case ">":
+		return got > want
+	case ">=":
+		return got >= want
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var testFields = Fields{
+	"title":       String,
+	"feed.folder": Strings,
+	"unread":      Bool,
+	"age":         Duration,
+	"total":       Number,
+}
+
+func testRecord(field string) any {
+	switch field {
+	case "title":
+		return "Rust 1.90 released"
+	case "feed.folder":
+		return []string{"Tech", "Programming"}
+	case "unread":
+		return true
+	case "age":
+		return 3 * 24 * time.Hour
+	case "total":
+		return int64(42)
+	}
+	return nil
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`unread`, true},
+		{`not unread`, false},
+		{`!unread`, false},
+		{`unread == false`, false},
+		{`age > 7d`, false},
+		{`age < 1w`, true},
+		{`age >= 72h`, true},
+		{`title ~ "rust"`, true},
+		{`title ~ "RÜST"`, true},
+		{`title !~ "rust"`, false},
+		{`title == "rust 1.90 released"`, true},
+		{`title = "rust"`, false},
+		{`feed.folder == "Tech"`, true},
+		{`feed.folder == "News"`, false},
+		{`feed.folder != "Tech"`, false},
+		{`feed.folder != "News"`, true},
+		{`feed.folder ~ "gram"`, true},
+		{`total > 40 and total <= 42`, true},
+		{`total == 41 or title ~ "go"`, false},
+		{`unread and (title ~ "go" or feed.folder == "tech")`, true},
+		{`unread && title ~ "go" || total != 0`, true},
+		{`not (unread or total == 0)`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := Parse(tt.expr, testFields)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := f.Match(testRecord); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{``, "empty filter"},
+		{`author == "x"`, "unknown field"},
+		{`title`, "compare it with a value"},
+		{`title > "a"`, "can't be used with title"},
+		{`age > 7`, "duration like 7d"},
+		{`age > 7y`, "invalid duration"},
+		{`total ~ "4"`, "can't be used with total"},
+		{`title ~ rust`, "quoted text"},
+		{`title ~ "rust`, "missing closing quote"},
+		{`(unread`, "missing )"},
+		{`unread unread`, "combine conditions"},
+		{`unread and`, "expected a field"},
+		{`unread == yes`, "true or false"},
+		{`unread # 1`, "unexpected '#'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr, testFields)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUses(t *testing.T) {
+	f, err := Parse(`unread and Title ~ "x"`, testFields)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !f.Uses("title") || !f.Uses("unread") || f.Uses("age") {
+		t.Errorf("Uses() reports the wrong fields for %q", f)
+	}
+}
@@ -0,0 +1,358 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenDuration
+	tokenOp
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	pos   int // Position in the input, for errors
+	value any // int64 for numbers, time.Duration for durations, the unquoted text for strings
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of filter"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// durationUnits are the units a duration may end with
+var durationUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+type lexer struct {
+	input []rune
+	pos   int
+}
+
+func newLexer(input string) *lexer {
+	return &lexer{input: []rune(input)}
+}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.input) && unicode.IsSpace(l.input[l.pos]) {
+		l.pos++
+	}
+	start := l.pos
+	if l.pos >= len(l.input) {
+		return token{kind: tokenEOF, pos: start}, nil
+	}
+
+	r := l.input[l.pos]
+	switch {
+	case r == '(':
+		l.pos++
+		return token{kind: tokenLParen, text: "(", pos: start}, nil
+	case r == ')':
+		l.pos++
+		return token{kind: tokenRParen, text: ")", pos: start}, nil
+	case r == '"':
+		return l.quoted()
+	case unicode.IsDigit(r):
+		return l.number()
+	case unicode.IsLetter(r) || r == '_':
+		for l.pos < len(l.input) && isIdentRune(l.input[l.pos]) {
+			l.pos++
+		}
+		text := string(l.input[start:l.pos])
+		switch strings.ToLower(text) {
+		case "and":
+			return token{kind: tokenAnd, text: text, pos: start}, nil
+		case "or":
+			return token{kind: tokenOr, text: text, pos: start}, nil
+		case "not":
+			return token{kind: tokenNot, text: text, pos: start}, nil
+		}
+		return token{kind: tokenIdent, text: text, pos: start}, nil
+	}
+
+	// Operators, the longest one that matches
+	for _, op := range []string{"&&", "||", "==", "!=", "!~", "<=", ">=", "=", "~", "<", ">", "!"} {
+		if strings.HasPrefix(string(l.input[l.pos:]), op) {
+			l.pos += len(op)
+			switch op {
+			case "&&":
+				return token{kind: tokenAnd, text: op, pos: start}, nil
+			case "||":
+				return token{kind: tokenOr, text: op, pos: start}, nil
+			case "!":
+				return token{kind: tokenNot, text: op, pos: start}, nil
+			case "=":
+				op = "=="
+			}
+			return token{kind: tokenOp, text: op, pos: start}, nil
+		}
+	}
+	return token{}, fmt.Errorf("unexpected %q at position %d", r, start+1)
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.'
+}
+
+// quoted reads a double-quoted string, a backslash escapes the next rune
+func (l *lexer) quoted() (token, error) {
+	start := l.pos
+	l.pos++
+	var b strings.Builder
+	for l.pos < len(l.input) {
+		r := l.input[l.pos]
+		l.pos++
+		switch r {
+		case '\\':
+			if l.pos < len(l.input) {
+				b.WriteRune(l.input[l.pos])
+				l.pos++
+			}
+		case '"':
+			return token{kind: tokenString, text: string(l.input[start:l.pos]), pos: start, value: b.String()}, nil
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return token{}, fmt.Errorf("missing closing quote for the text at position %d", start+1)
+}
+
+// number reads a whole number or a duration like 7d
+func (l *lexer) number() (token, error) {
+	start := l.pos
+	for l.pos < len(l.input) && unicode.IsDigit(l.input[l.pos]) {
+		l.pos++
+	}
+	digits := string(l.input[start:l.pos])
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return token{}, fmt.Errorf("invalid number %s at position %d", digits, start+1)
+	}
+
+	unitStart := l.pos
+	for l.pos < len(l.input) && unicode.IsLetter(l.input[l.pos]) {
+		l.pos++
+	}
+	text := string(l.input[start:l.pos])
+	if l.pos == unitStart {
+		return token{kind: tokenNumber, text: text, pos: start, value: n}, nil
+	}
+	unit, ok := durationUnits[strings.ToLower(string(l.input[unitStart:l.pos]))]
+	if !ok {
+		return token{}, fmt.Errorf("invalid duration %s at position %d, use s, m, h, d or w like 7d", text, start+1)
+	}
+	return token{kind: tokenDuration, text: text, pos: start, value: time.Duration(n) * unit}, nil
+}
+
+// parser builds the expression tree, and binds tighter than or:
+//
+//	expr       = and { "or" and }
+//	and        = unary { "and" unary }
+//	unary      = "not" unary | primary
+//	primary    = "(" expr ")" | field [ operator value ]
+type parser struct {
+	lexer  *lexer
+	token  token
+	fields Fields
+	uses   map[string]bool
+}
+
+func (p *parser) advance() error {
+	t, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.token = t
+	return nil
+}
+
+func (p *parser) parse() (node, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.token.kind == tokenEOF {
+		return nil, fmt.Errorf("empty filter")
+	}
+	expr, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.token.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d, combine conditions with and/or", p.token, p.token.pos+1)
+	}
+	return expr, nil
+}
+
+func (p *parser) or() (node, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.token.kind == tokenOr {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.token.kind == tokenAnd {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) unary() (node, error) {
+	if p.token.kind == tokenNot {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		expr, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{expr}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (node, error) {
+	switch p.token.kind {
+	case tokenLParen:
+		open := p.token
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		expr, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.token.kind != tokenRParen {
+			return nil, fmt.Errorf("missing ) for the ( at position %d", open.pos+1)
+		}
+		return expr, p.advance()
+	case tokenIdent:
+		return p.comparison()
+	}
+	return nil, fmt.Errorf("expected a field at position %d, got %s", p.token.pos+1, p.token)
+}
+
+func (p *parser) comparison() (node, error) {
+	field := p.token
+	name := strings.ToLower(field.text)
+	kind, ok := p.fields[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %s, use %s", field, strings.Join(p.fields.Names(), ", "))
+	}
+	p.uses[name] = true
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	if p.token.kind != tokenOp {
+		if kind != Bool {
+			return nil, fmt.Errorf("%s is %s, compare it with a value", name, kindArticle(kind))
+		}
+		return comparison{field: name, kind: Bool, op: "==", value: true}, nil
+	}
+	op := p.token
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	value := p.token
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	if !operatorAllowed(kind, op.text) {
+		return nil, fmt.Errorf("%s can't be used with %s, it is %s", op.text, name, kindArticle(kind))
+	}
+
+	c := comparison{field: name, kind: kind, op: op.text}
+	switch kind {
+	case Bool:
+		if value.kind != tokenIdent || (strings.ToLower(value.text) != "true" && strings.ToLower(value.text) != "false") {
+			return nil, fmt.Errorf("compare %s with true or false, got %s", name, value)
+		}
+		c.value = strings.ToLower(value.text) == "true"
+	case String, Strings:
+		if value.kind != tokenString {
+			return nil, fmt.Errorf("compare %s with quoted text, got %s", name, value)
+		}
+		c.value = database.FoldText(value.value.(string))
+	case Number:
+		if value.kind != tokenNumber {
+			return nil, fmt.Errorf("compare %s with a number, got %s", name, value)
+		}
+		c.value = value.value
+	case Duration:
+		if value.kind != tokenDuration {
+			return nil, fmt.Errorf("compare %s with a duration like 7d, got %s", name, value)
+		}
+		c.value = value.value
+	}
+	return c, nil
+}
+
+func operatorAllowed(kind Kind, op string) bool {
+	switch op {
+	case "==", "!=":
+		return true
+	case "~", "!~":
+		return kind == String || kind == Strings
+	default:
+		return kind == Number || kind == Duration
+	}
+}
+
+func kindArticle(kind Kind) string {
+	switch kind {
+	case String:
+		return "text"
+	case Strings:
+		return "a list of text"
+	}
+	return "a " + kind.String()
+}
@@ -0,0 +1,183 @@
+// Package hooks runs the shell commands users attach to events in the hooks
+// file, e.g. to log what they read or get notified about new items.
+package hooks
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// Events hooks can be attached to
+const (
+	EventStartup           = "startup"
+	EventShutdown          = "shutdown"
+	EventItemRead          = "item-read"
+	EventNewItems          = "new-items"
+	EventFeedRefreshFailed = "feed-refresh-failed"
+)
+
+// Events lists every event in the order they are documented
+var Events = []string{EventStartup, EventShutdown, EventItemRead, EventNewItems, EventFeedRefreshFailed}
+
+// Environment variables hooks get besides NEWSGOAT_EVENT
+const (
+	EnvItemID    = "NEWSGOAT_ITEM_ID"
+	EnvTitle     = "NEWSGOAT_TITLE"
+	EnvURL       = "NEWSGOAT_URL"
+	EnvFeedTitle = "NEWSGOAT_FEED_TITLE"
+	EnvFeedURL   = "NEWSGOAT_FEED_URL"
+	EnvNewItems  = "NEWSGOAT_NEW_ITEMS"
+	EnvNewTitles = "NEWSGOAT_NEW_TITLES"
+	EnvError     = "NEWSGOAT_ERROR"
+)
+
+// hookTimeout is how long a hook may run before it is killed
+const hookTimeout = 30 * time.Second
+
+// maxOutput caps the output of a failed hook that is logged
+const maxOutput = 500
+
+func isEvent(event string) bool {
+	for _, e := range Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Hooks holds the commands of each event. A nil *Hooks has none, so callers
+// don't have to check whether a hooks file was loaded.
+type Hooks struct {
+	commands map[string][]string
+	running  sync.WaitGroup
+}
+
+// Load reads hooks from a file with an event followed by a shell command on
+// each line, e.g. "item-read echo $NEWSGOAT_URL >> ~/read.txt". An event can
+// have several lines. A missing file has no hooks, invalid lines are
+// returned as errors.
+func Load(path string) (*Hooks, []error) {
+	h := &Hooks{commands: make(map[string][]string)}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return h, nil
+		}
+		return h, []error{err}
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var errs []error
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		event, command, _ := strings.Cut(line, " ")
+		command = strings.TrimSpace(command)
+		switch {
+		case !isEvent(event):
+			errs = append(errs, fmt.Errorf("%s:%d: unknown event %q, use one of %s", path, lineNumber, event, strings.Join(Events, ", ")))
+		case command == "":
+			errs = append(errs, fmt.Errorf("%s:%d: expected an event followed by a command", path, lineNumber))
+		default:
+			h.commands[event] = append(h.commands[event], command)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return h, errs
+}
+
+// Has reports whether an event has hooks, so callers only look up the
+// details of an event someone listens to
+func (h *Hooks) Has(event string) bool {
+	return h != nil && len(h.commands[event]) > 0
+}
+
+// Fire runs the hooks of an event in the background with the event and env
+// set in their environment
+func (h *Hooks) Fire(event string, env map[string]string) {
+	if !h.Has(event) {
+		return
+	}
+	for _, command := range h.commands[event] {
+		h.running.Add(1)
+		go func(command string) {
+			defer h.running.Done()
+			h.run(event, command, env)
+		}(command)
+	}
+}
+
+// Wait waits up to timeout for running hooks to finish, so the shutdown
+// hook runs before NewsGoat exits
+func (h *Hooks) Wait(timeout time.Duration) {
+	if h == nil {
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		h.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		logging.Warn("Hooks still running at exit")
+	}
+}
+
+func (h *Hooks) run(event, command string, env map[string]string) {
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Env = append(os.Environ(), "NEWSGOAT_EVENT="+event)
+	// Sorted so the environment is the same on every run
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		cmd.Env = append(cmd.Env, key+"="+env[key])
+	}
+
+	start := time.Now()
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		out := strings.TrimSpace(string(output))
+		if len(out) > maxOutput {
+			out = out[:maxOutput] + "..."
+		}
+		logging.Warn("Hook failed", "event", event, "command", command, "error", err, "output", out)
+		return
+	}
+	logging.DebugCategory(logging.CategoryTasks, "Hook ran", "event", event, "command", command, "duration", time.Since(start))
+}
@@ -0,0 +1,65 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hooks")
+	content := `# Log what I read
+item-read echo "$NEWSGOAT_URL" >> ~/read.txt
+new-items notify-send "$NEWSGOAT_FEED_TITLE"
+new-items echo second
+item-opened echo unknown
+startup
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	h, errs := Load(path)
+	if len(errs) != 2 {
+		t.Fatalf("Load() errors = %v, want 2", errs)
+	}
+	if !strings.Contains(errs[0].Error(), `unknown event "item-opened"`) || !strings.Contains(errs[1].Error(), ":6:") {
+		t.Errorf("Load() errors = %v", errs)
+	}
+	if !h.Has(EventItemRead) || len(h.commands[EventNewItems]) != 2 || h.Has(EventStartup) {
+		t.Errorf("Load() commands = %v", h.commands)
+	}
+
+	missing, errs := Load(filepath.Join(t.TempDir(), "missing"))
+	if len(errs) != 0 || missing.Has(EventItemRead) {
+		t.Errorf("Load() of a missing file = %v, %v", missing.commands, errs)
+	}
+
+	var none *Hooks
+	none.Fire(EventStartup, nil)
+	none.Wait(time.Second)
+}
+
+func TestFire(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks run through sh")
+	}
+	out := filepath.Join(t.TempDir(), "out")
+	h := &Hooks{commands: map[string][]string{
+		EventItemRead: {`echo "$NEWSGOAT_EVENT $NEWSGOAT_URL" > ` + out},
+	}}
+
+	h.Fire(EventItemRead, map[string]string{EnvURL: "https://example.com/1"})
+	h.Wait(5 * time.Second)
+
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("hook didn't run: %v", err)
+	}
+	if want := "item-read https://example.com/1\n"; string(got) != want {
+		t.Errorf("hook wrote %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,201 @@
+// Package ipc lets a second newsgoat hand requests, such as a feed to
+// subscribe to, to the running one over a Unix socket.
+package ipc
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// Commands the running NewsGoat accepts
+const (
+	CommandAdd          = "add"            // Add the feed of URL like pressing u
+	CommandMarkRead     = "mark-read"      // Mark the items of the feed URL, or of All feeds, read
+	CommandMarkItemRead = "mark-item-read" // Mark the items ItemIDs read
+	CommandRefresh      = "refresh"        // Refresh the feed FeedID, or All feeds, and reply once done
+	CommandStatus       = "status"         // Reply with the DaemonStatus of a daemon
+)
+
+// ErrNotRunning is returned by Send when no NewsGoat listens on the socket
+var ErrNotRunning = errors.New("no running newsgoat")
+
+// requestTimeout bounds connecting, writing and waiting for the reply
+const requestTimeout = 5 * time.Second
+
+// Request is sent as one line of JSON
+type Request struct {
+	Command string  `json:"command"`
+	URL     string  `json:"url,omitempty"`
+	All     bool    `json:"all,omitempty"`
+	ItemIDs []int64 `json:"item_ids,omitempty"`
+	FeedID  int64   `json:"feed_id,omitempty"`
+}
+
+// DaemonStatus is the reply of a daemon to CommandStatus
+type DaemonStatus struct {
+	PID         int       `json:"pid"`
+	Refreshing  bool      `json:"refreshing"`
+	Changes     int64     `json:"changes"` // Counts the refreshes and syncs that wrote to the database
+	LastRefresh time.Time `json:"last_refresh"`
+	NextRefresh time.Time `json:"next_refresh"`
+}
+
+// EncodeStatus is the message a daemon replies to CommandStatus with
+func EncodeStatus(status DaemonStatus) string {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// GetStatus asks the NewsGoat listening at path for its status, it fails
+// when that isn't a daemon
+func GetStatus(path string) (DaemonStatus, error) {
+	var status DaemonStatus
+	message, err := Send(path, Request{Command: CommandStatus})
+	if err != nil {
+		return status, err
+	}
+	if err := json.Unmarshal([]byte(message), &status); err != nil {
+		return status, fmt.Errorf("not a newsgoat daemon: %w", err)
+	}
+	return status, nil
+}
+
+type response struct {
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Server accepts requests on a Unix socket
+type Server struct {
+	listener net.Listener
+	path     string
+	handle   func(Request) (string, error)
+	wg       sync.WaitGroup
+}
+
+// Listen creates the socket at path and passes every request to handle, the
+// message it returns is shown by the sender. Only the NewsGoat holding the
+// lock listens, so a socket left at path by one that crashed is replaced.
+// The directory of path is made private to the user, so it should hold
+// nothing but the socket.
+func Listen(path string, handle func(Request) (string, error)) (*Server, error) {
+	// Other users shouldn't add feeds for us. The socket is created with the
+	// umask's permissions, so they are kept out by the directory until it is
+	// made private too.
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(dir, 0700); err != nil {
+		return nil, err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+
+	s := &Server{listener: listener, path: path, handle: handle}
+	s.wg.Add(1)
+	go s.accept()
+	return s, nil
+}
+
+func (s *Server) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				logging.Warn("IPC socket stopped accepting", "error", err)
+			}
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.serve(conn)
+		}()
+	}
+}
+
+func (s *Server) serve(conn net.Conn) {
+	defer func() {
+		_ = conn.Close()
+	}()
+	_ = conn.SetReadDeadline(time.Now().Add(requestTimeout))
+
+	var reply response
+	var request Request
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err == nil {
+		err = json.Unmarshal(line, &request)
+	}
+	if err != nil {
+		reply.Error = fmt.Sprintf("invalid request: %v", err)
+	} else if reply.Message, err = s.handle(request); err != nil {
+		reply.Error = err.Error()
+	}
+	// A refresh the sender waits for may take longer than a request
+	_ = conn.SetWriteDeadline(time.Now().Add(requestTimeout))
+	_ = json.NewEncoder(conn).Encode(reply)
+}
+
+// Close stops listening and removes the socket
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	if removeErr := os.Remove(s.path); removeErr != nil && !os.IsNotExist(removeErr) && err == nil {
+		err = removeErr
+	}
+	return err
+}
+
+// Send hands a request to the NewsGoat listening at path and returns its
+// message, or ErrNotRunning when there is none
+func Send(path string, request Request) (string, error) {
+	return SendTimeout(path, request, requestTimeout)
+}
+
+// SendTimeout is Send waiting up to timeout for the reply, for requests such
+// as CommandRefresh that reply once their work is done
+func SendTimeout(path string, request Request, timeout time.Duration) (string, error) {
+	conn, err := net.DialTimeout("unix", path, requestTimeout)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrNotRunning, err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return "", err
+	}
+	var reply response
+	if err := json.NewDecoder(conn).Decode(&reply); err != nil {
+		return "", fmt.Errorf("no reply from the running newsgoat: %w", err)
+	}
+	if reply.Error != "" {
+		return "", errors.New(reply.Error)
+	}
+	return reply.Message, nil
+}
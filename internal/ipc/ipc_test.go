@@ -0,0 +1,132 @@
+package ipc
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestSend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "newsgoat.sock")
+
+	if _, err := Send(path, Request{Command: CommandAdd, URL: "https://example.com/feed"}); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("Send() without a server error = %v, want ErrNotRunning", err)
+	}
+
+	received := make(chan Request, 1)
+	server, err := Listen(path, func(request Request) (string, error) {
+		if request.Command != CommandAdd {
+			return "", fmt.Errorf("unknown command %q", request.Command)
+		}
+		received <- request
+		return "Adding " + request.URL, nil
+	})
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer func() {
+		_ = server.Close()
+	}()
+
+	message, err := Send(path, Request{Command: CommandAdd, URL: "https://example.com/feed"})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if message != "Adding https://example.com/feed" {
+		t.Errorf("Send() message = %q", message)
+	}
+	if got := <-received; got.URL != "https://example.com/feed" {
+		t.Errorf("received URL %q", got.URL)
+	}
+
+	if _, err := Send(path, Request{Command: "remove"}); err == nil || err.Error() != `unknown command "remove"` {
+		t.Errorf("Send() of an unknown command error = %v", err)
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "newsgoat.sock")
+	want := DaemonStatus{PID: 42, Refreshing: true, Changes: 3, LastRefresh: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+	server, err := Listen(path, func(request Request) (string, error) {
+		switch request.Command {
+		case CommandStatus:
+			return EncodeStatus(want), nil
+		case CommandRefresh:
+			// Longer than the read deadline of the request
+			time.Sleep(50 * time.Millisecond)
+			return fmt.Sprintf("Refreshed feed %d", request.FeedID), nil
+		}
+		return "Adding " + request.URL, nil
+	})
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer func() {
+		_ = server.Close()
+	}()
+
+	got, err := GetStatus(path)
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	if got.PID != want.PID || !got.Refreshing || got.Changes != want.Changes || !got.LastRefresh.Equal(want.LastRefresh) {
+		t.Errorf("GetStatus() = %+v, want %+v", got, want)
+	}
+
+	message, err := SendTimeout(path, Request{Command: CommandRefresh, FeedID: 7}, time.Second)
+	if err != nil || message != "Refreshed feed 7" {
+		t.Errorf("SendTimeout() = %q, %v", message, err)
+	}
+}
+
+func TestGetStatusNotDaemon(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "newsgoat.sock")
+	server, err := Listen(path, func(request Request) (string, error) {
+		return "", fmt.Errorf("unknown command %q", request.Command)
+	})
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer func() {
+		_ = server.Close()
+	}()
+
+	if _, err := GetStatus(path); err == nil {
+		t.Error("GetStatus() of a NewsGoat that isn't a daemon succeeded")
+	}
+}
+
+func TestListenMakesDirectoryPrivate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no Unix permissions")
+	}
+	dir := filepath.Join(t.TempDir(), "ipc")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "newsgoat.sock")
+	server, err := Listen(path, func(request Request) (string, error) {
+		return "", nil
+	})
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer func() {
+		_ = server.Close()
+	}()
+
+	for name, want := range map[string]os.FileMode{dir: 0700, path: 0600} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("mode of %s = %o, want %o", name, got, want)
+		}
+	}
+}
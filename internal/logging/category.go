@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Debug categories, each can be enabled on its own with -debugCategories or
+// the Debug Categories setting
+const (
+	CategoryHTTP      = "http"
+	CategoryDB        = "db"
+	CategoryUI        = "ui"
+	CategoryTasks     = "tasks"
+	CategoryDiscovery = "discovery"
+
+	// CategoryAll enables debug messages of every category, like -debug
+	CategoryAll = "all"
+)
+
+// Categories lists the debug categories in the order they are documented
+var Categories = []string{CategoryHTTP, CategoryDB, CategoryUI, CategoryTasks, CategoryDiscovery}
+
+var (
+	debugCategories      = make(map[string]bool)
+	debugCategoriesMutex sync.RWMutex
+)
+
+// ParseDebugCategories parses a comma-separated list of categories such as
+// "http,tasks", an empty string disables them all
+func ParseDebugCategories(s string) ([]string, error) {
+	var categories []string
+	for _, part := range strings.Split(s, ",") {
+		category := strings.ToLower(strings.TrimSpace(part))
+		if category == "" {
+			continue
+		}
+		if category != CategoryAll && !isCategory(category) {
+			return nil, fmt.Errorf("unknown debug category %q, use %s or %s", category, strings.Join(Categories, ", "), CategoryAll)
+		}
+		categories = append(categories, category)
+	}
+	return categories, nil
+}
+
+func isCategory(category string) bool {
+	for _, c := range Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// SetDebugCategories replaces the enabled debug categories
+func SetDebugCategories(categories []string) {
+	enabled := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		enabled[category] = true
+	}
+
+	debugCategoriesMutex.Lock()
+	defer debugCategoriesMutex.Unlock()
+	debugCategories = enabled
+}
+
+// debugCategoryEnabled reports whether debug messages of a category are
+// logged, messages without a category only show up with "all"
+func debugCategoryEnabled(category string) bool {
+	debugCategoriesMutex.RLock()
+	defer debugCategoriesMutex.RUnlock()
+	return debugCategories[CategoryAll] || (category != "" && debugCategories[category])
+}
+
+func anyDebugCategory() bool {
+	debugCategoriesMutex.RLock()
+	defer debugCategoriesMutex.RUnlock()
+	return len(debugCategories) > 0
+}
+
+// DebugCategory logs at debug level for one subsystem, the message is kept
+// when -debug is set or its category is enabled
+func DebugCategory(category, msg string, args ...any) {
+	if logger != nil {
+		logger.Debug(msg, append([]any{"category", category}, args...)...)
+	}
+}
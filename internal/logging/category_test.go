@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDebugCategories(t *testing.T) {
+	got, err := ParseDebugCategories(" HTTP, tasks,,")
+	if err != nil {
+		t.Fatalf("ParseDebugCategories() error = %v", err)
+	}
+	if want := []string{CategoryHTTP, CategoryTasks}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseDebugCategories() = %v, want %v", got, want)
+	}
+
+	if got, err := ParseDebugCategories(""); err != nil || got != nil {
+		t.Errorf("ParseDebugCategories(\"\") = %v, %v, want nil", got, err)
+	}
+
+	if _, err := ParseDebugCategories("http,network"); err == nil {
+		t.Error("ParseDebugCategories() should reject unknown categories")
+	}
+}
+
+func TestDebugCategoryEnabled(t *testing.T) {
+	defer SetDebugCategories(nil)
+
+	SetDebugCategories([]string{CategoryHTTP})
+	if !debugCategoryEnabled(CategoryHTTP) {
+		t.Error("http debug messages should be enabled")
+	}
+	if debugCategoryEnabled(CategoryDB) || debugCategoryEnabled("") {
+		t.Error("only http debug messages should be enabled")
+	}
+
+	SetDebugCategories([]string{CategoryAll})
+	if !debugCategoryEnabled(CategoryDB) || !debugCategoryEnabled("") {
+		t.Error("all debug messages should be enabled")
+	}
+}
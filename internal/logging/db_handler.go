@@ -30,30 +30,42 @@ func NewDatabaseHandlerWithDebug(queries *database.Queries, debug bool) *Databas
 }
 
 func (h *DatabaseHandler) Enabled(_ context.Context, level slog.Level) bool {
-	// Filter out debug messages unless debug mode is enabled
-	if level == slog.LevelDebug && !h.debugEnabled {
+	// Filter out debug messages unless debug mode or a debug category is enabled
+	if level == slog.LevelDebug && !h.debugEnabled && !anyDebugCategory() {
 		return false
 	}
 	return true
 }
 
 func (h *DatabaseHandler) Handle(ctx context.Context, r slog.Record) error {
-	// Collect all attributes into a map
+	// Collect all attributes into a map, masking tokens in URLs and errors
 	attrs := make(map[string]interface{})
+	var category string
 	r.Attrs(func(a slog.Attr) bool {
-		// Special handling for error types - convert to string
-		if a.Key == "error" {
-			if err, ok := a.Value.Any().(error); ok {
-				attrs[a.Key] = err.Error()
+		if a.Key == "category" {
+			category = a.Value.String()
+		}
+		switch v := a.Value.Any().(type) {
+		case error:
+			// Special handling for error types - convert to string
+			attrs[a.Key] = Redact(v.Error())
+		case string:
+			attrs[a.Key] = Redact(v)
+		default:
+			if a.Key == "error" {
+				attrs[a.Key] = Redact(a.Value.String())
 			} else {
-				attrs[a.Key] = a.Value.String()
+				attrs[a.Key] = v
 			}
-		} else {
-			attrs[a.Key] = a.Value.Any()
 		}
 		return true
 	})
 
+	// Without -debug only debug messages of the enabled categories are kept
+	if r.Level == slog.LevelDebug && !h.debugEnabled && !debugCategoryEnabled(category) {
+		return nil
+	}
+
 	// Add source location if available
 	if r.PC != 0 {
 		frames := runtime.CallersFrames([]uintptr{r.PC})
@@ -77,7 +89,7 @@ func (h *DatabaseHandler) Handle(ctx context.Context, r slog.Record) error {
 	// Store the log message in the database
 	return h.queries.CreateLogMessage(ctx, database.CreateLogMessageParams{
 		Level:      r.Level.String(),
-		Message:    r.Message,
+		Message:    Redact(r.Message),
 		Timestamp:  sql.NullTime{Time: r.Time, Valid: true},
 		Attributes: attributesJSON,
 	})
@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+// ExportEntry is a log message as written by "newsgoat logs export", one
+// JSON object per line
+type ExportEntry struct {
+	Time       time.Time      `json:"time"`
+	Level      string         `json:"level"`
+	Message    string         `json:"message"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// ParseLevel parses a level name such as "error" or "warn"
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return level, fmt.Errorf("unknown log level %q, use debug, info, warn or error", s)
+	}
+	return level, nil
+}
+
+// NewExportEntry converts a stored log message, secrets are redacted again
+// for messages logged before redaction existed
+func NewExportEntry(message database.LogMessage) ExportEntry {
+	entry := ExportEntry{
+		Level:   message.Level,
+		Message: Redact(message.Message),
+	}
+	if message.Timestamp.Valid {
+		entry.Time = message.Timestamp.Time
+	}
+	if message.Attributes.Valid && message.Attributes.String != "" {
+		attributes := Redact(message.Attributes.String)
+		if err := json.Unmarshal([]byte(attributes), &entry.Attributes); err != nil {
+			entry.Attributes = map[string]any{"raw": attributes}
+		}
+	}
+	return entry
+}
+
+// ExportFilter selects the log messages that are exported
+type ExportFilter struct {
+	Since time.Time  // Zero exports messages of any age
+	Level slog.Level // Minimum level
+}
+
+// Match reports whether a stored log message passes the filter
+func (f ExportFilter) Match(message database.LogMessage) bool {
+	if !f.Since.IsZero() && (!message.Timestamp.Valid || message.Timestamp.Time.Before(f.Since)) {
+		return false
+	}
+	level, err := ParseLevel(message.Level)
+	if err != nil {
+		// Keep messages whose level can't be parsed rather than losing them
+		return true
+	}
+	return level >= f.Level
+}
+
+// WriteJSONLines writes the messages passing the filter as JSON Lines and
+// returns how many were written
+func WriteJSONLines(w io.Writer, messages []database.LogMessage, filter ExportFilter) (int, error) {
+	encoder := json.NewEncoder(w)
+	written := 0
+	for _, message := range messages {
+		if !filter.Match(message) {
+			continue
+		}
+		if err := encoder.Encode(NewExportEntry(message)); err != nil {
+			return written, err
+		}
+		written++
+	}
+	return written, nil
+}
@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+func TestWriteJSONLines(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	messages := []database.LogMessage{
+		{Level: "ERROR", Message: "too old", Timestamp: sql.NullTime{Time: now.Add(-48 * time.Hour), Valid: true}},
+		{Level: "INFO", Message: "too quiet", Timestamp: sql.NullTime{Time: now, Valid: true}},
+		{
+			Level:      "ERROR",
+			Message:    "Failed to refresh feed",
+			Timestamp:  sql.NullTime{Time: now, Valid: true},
+			Attributes: sql.NullString{String: `{"url":"https://example.com/feed?token=abcdef123","status":500}`, Valid: true},
+		},
+		{Level: "WARN", Message: "Slow response", Timestamp: sql.NullTime{Time: now, Valid: true}},
+	}
+
+	var buf bytes.Buffer
+	written, err := WriteJSONLines(&buf, messages, ExportFilter{Since: now.Add(-24 * time.Hour), Level: slog.LevelWarn})
+	if err != nil {
+		t.Fatalf("WriteJSONLines() error = %v", err)
+	}
+	if written != 2 {
+		t.Fatalf("WriteJSONLines() wrote %d messages, want 2:\n%s", written, buf.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var entry ExportEntry
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("first line isn't JSON: %v", err)
+	}
+	if entry.Message != "Failed to refresh feed" || !entry.Time.Equal(now) {
+		t.Errorf("first entry = %+v", entry)
+	}
+	if url := entry.Attributes["url"]; url != "https://example.com/feed?token="+Redacted {
+		t.Errorf("url attribute = %v, want the token redacted", url)
+	}
+	if status := entry.Attributes["status"]; status != float64(500) {
+		t.Errorf("status attribute = %v, want 500", status)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for input, want := range map[string]slog.Level{"error": slog.LevelError, "WARN": slog.LevelWarn, " info ": slog.LevelInfo} {
+		if got, err := ParseLevel(input); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("ParseLevel() should reject unknown levels")
+	}
+}
@@ -0,0 +1,149 @@
+// Package notify sends desktop notifications about the new items a refresh
+// found and the ones matching highlight rules.
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// maxFeeds is how many feeds a summary names, the rest are counted
+const maxFeeds = 5
+
+// Send shows a desktop notification with notify-send on Linux and BSD,
+// osascript on macOS and PowerShell on Windows
+func Send(title, body string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		// Passed as arguments so quotes in titles don't need escaping
+		cmd = exec.Command("osascript",
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, body)
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsToast)
+		cmd.Env = append(os.Environ(), "NEWSGOAT_NOTIFY_TITLE="+title, "NEWSGOAT_NOTIFY_BODY="+body)
+	default:
+		cmd = exec.Command("notify-send", "--app-name=NewsGoat", title, body)
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if out := strings.TrimSpace(string(output)); out != "" {
+			return fmt.Errorf("%w: %s", err, out)
+		}
+		return err
+	}
+	return nil
+}
+
+// windowsToast shows a toast with the title and body from the environment
+const windowsToast = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $xml.GetElementsByTagName('text')
+$text.Item(0).AppendChild($xml.CreateTextNode($env:NEWSGOAT_NOTIFY_TITLE)) > $null
+$text.Item(1).AppendChild($xml.CreateTextNode($env:NEWSGOAT_NOTIFY_BODY)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('NewsGoat').Show([Windows.UI.Notifications.ToastNotification]::new($xml))`
+
+// Notifier collects the new items of the feeds refreshed together and sends
+// one notification about all of them
+type Notifier struct {
+	mutex       sync.Mutex
+	counts      map[string]int
+	order       []string // Feed titles in the order their items arrived
+	highlighted []string // "Feed: title" of new items matching highlight rules
+	send        func(title, body string) error
+}
+
+// NewNotifier creates a notifier that sends its notifications with send,
+// usually Send
+func NewNotifier(send func(title, body string) error) *Notifier {
+	return &Notifier{counts: make(map[string]int), send: send}
+}
+
+// Add records new items of a feed
+func (n *Notifier) Add(feedTitle string, count int) {
+	if count <= 0 {
+		return
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if _, ok := n.counts[feedTitle]; !ok {
+		n.order = append(n.order, feedTitle)
+	}
+	n.counts[feedTitle] += count
+}
+
+// AddHighlighted records a new item matching a highlight rule that asks for
+// a notification, whatever the Notify setting says about its feed
+func (n *Notifier) AddHighlighted(feedTitle, itemTitle string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.highlighted = append(n.highlighted, feedTitle+": "+itemTitle)
+}
+
+// Flush sends a notification about the items added since the last one, if
+// there are any, and one naming the highlighted items
+func (n *Notifier) Flush() error {
+	n.mutex.Lock()
+	counts, order, highlighted := n.counts, n.order, n.highlighted
+	n.counts, n.order, n.highlighted = make(map[string]int), nil, nil
+	n.mutex.Unlock()
+
+	var errs []error
+	if len(order) > 0 {
+		title, body := Summary(counts, order)
+		errs = append(errs, n.send(title, body))
+	}
+	if len(highlighted) > 0 {
+		title, body := HighlightSummary(highlighted)
+		errs = append(errs, n.send(title, body))
+	}
+	return errors.Join(errs...)
+}
+
+// HighlightSummary is the title and body of a notification about items
+// matching highlight rules, one line per item
+func HighlightSummary(items []string) (title, body string) {
+	title = fmt.Sprintf("%d highlighted items", len(items))
+	if len(items) == 1 {
+		title = "1 highlighted item"
+	}
+	lines := items
+	if len(items) > maxFeeds {
+		lines = append(slices.Clone(items[:maxFeeds]), fmt.Sprintf("and %d more", len(items)-maxFeeds))
+	}
+	return title, strings.Join(lines, "\n")
+}
+
+// Summary is the title and body of a notification about new items, one line
+// per feed
+func Summary(counts map[string]int, order []string) (title, body string) {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	title = fmt.Sprintf("%d new items", total)
+	if total == 1 {
+		title = "1 new item"
+	}
+	if len(order) == 1 {
+		return title + " in " + order[0], ""
+	}
+
+	var lines []string
+	for i, feed := range order {
+		if i == maxFeeds {
+			lines = append(lines, fmt.Sprintf("and %d more feeds", len(order)-maxFeeds))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%s: %d", feed, counts[feed]))
+	}
+	return title, strings.Join(lines, "\n")
+}
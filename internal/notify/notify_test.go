@@ -0,0 +1,80 @@
+package notify
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNotifier(t *testing.T) {
+	var sent []string
+	n := NewNotifier(func(title, body string) error {
+		sent = append(sent, title+"|"+body)
+		return nil
+	})
+
+	if err := n.Flush(); err != nil || len(sent) != 0 {
+		t.Fatalf("Flush() without items sent %v, %v", sent, err)
+	}
+
+	n.Add("Go Blog", 1)
+	if err := n.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	n.Add("Go Blog", 2)
+	n.Add("Hacker News", 5)
+	n.Add("Go Blog", 1)
+	n.Add("Empty", 0)
+	if err := n.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"1 new item in Go Blog|",
+		"8 new items|Go Blog: 3\nHacker News: 5",
+	}
+	if fmt.Sprint(sent) != fmt.Sprint(want) {
+		t.Errorf("sent %q, want %q", sent, want)
+	}
+}
+
+func TestSummaryManyFeeds(t *testing.T) {
+	counts := map[string]int{}
+	var order []string
+	for i := 1; i <= 7; i++ {
+		feed := fmt.Sprintf("Feed %d", i)
+		counts[feed] = 1
+		order = append(order, feed)
+	}
+	title, body := Summary(counts, order)
+	want := "Feed 1: 1\nFeed 2: 1\nFeed 3: 1\nFeed 4: 1\nFeed 5: 1\nand 2 more feeds"
+	if title != "7 new items" || body != want {
+		t.Errorf("Summary() = %q, %q", title, body)
+	}
+}
+
+func TestNotifierHighlighted(t *testing.T) {
+	var sent []string
+	n := NewNotifier(func(title, body string) error {
+		sent = append(sent, title+"|"+body)
+		return nil
+	})
+
+	n.Add("Security", 2)
+	n.AddHighlighted("Security", "CVE-2026-1234 in libfoo")
+	if err := n.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 6; i++ {
+		n.AddHighlighted("Security", fmt.Sprintf("CVE %d", i))
+	}
+	if err := n.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"2 new items in Security|",
+		"1 highlighted item|Security: CVE-2026-1234 in libfoo",
+		"6 highlighted items|Security: CVE 1\nSecurity: CVE 2\nSecurity: CVE 3\nSecurity: CVE 4\nSecurity: CVE 5\nand 1 more",
+	}
+	if fmt.Sprint(sent) != fmt.Sprint(want) {
+		t.Errorf("sent %q, want %q", sent, want)
+	}
+}
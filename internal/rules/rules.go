@@ -0,0 +1,216 @@
+// Package rules matches items against the rules that hide them or mark them
+// read when a feed is refreshed, like newsboat's ignore-article, and the ones
+// that highlight them in the item list.
+//
+// A rule is written as an action, a field and a regular expression:
+//
+//	hide title Sponsored
+//	read author ^dependabot
+//	hide link /shorts/
+//	highlight=#ff5555,🔒,notify title (?i)\bcve-
+//
+// The expression is the rest of the line and may contain spaces, (?i) at
+// its start ignores case. Highlight options follow an =, separated by
+// commas: a color number or #rrggbb, notify to send a desktop notification
+// about new matching items, anything else is shown in front of the title.
+package rules
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+// Action is what happens to an item matching a rule
+type Action string
+
+const (
+	Hide      Action = "hide"      // The item isn't saved
+	Read      Action = "read"      // The item is saved marked read
+	Highlight Action = "highlight" // The item is shown in a color or with a marker
+)
+
+// colorPattern matches the colors lipgloss takes, an ANSI color number or a
+// hex color
+var colorPattern = regexp.MustCompile(`^([0-9]{1,3}|#[0-9a-fA-F]{3}|#[0-9a-fA-F]{6})$`)
+
+// Fields are the parts of an item a rule can match
+var Fields = []string{"title", "author", "link"}
+
+// Item is the part of an item rules look at
+type Item struct {
+	Title  string
+	Author string
+	Link   string
+}
+
+func (item Item) field(name string) string {
+	switch name {
+	case "title":
+		return item.Title
+	case "author":
+		return item.Author
+	case "link":
+		return item.Link
+	}
+	return ""
+}
+
+// Rule is a compiled rule, for every feed when FeedID is 0
+type Rule struct {
+	ID      int64
+	FeedID  int64
+	Field   string
+	Pattern string
+	Action  Action
+	Color   string // Color of highlighted items
+	Marker  string // Shown in front of the titles of highlighted items
+	Notify  bool   // New highlighted items send a desktop notification
+	re      *regexp.Regexp
+}
+
+// Parse reads a rule written as "action field pattern"
+func Parse(text string) (Rule, error) {
+	fields := strings.Fields(text)
+	if len(fields) < 3 {
+		return Rule{}, fmt.Errorf("expected action field pattern, e.g. hide title Sponsored")
+	}
+	// The pattern keeps its inner spaces
+	rest := strings.TrimSpace(text)
+	for _, word := range fields[:2] {
+		rest = strings.TrimSpace(strings.TrimPrefix(rest, word))
+	}
+
+	action, options, _ := strings.Cut(fields[0], "=")
+	rule := Rule{Field: fields[1], Pattern: rest, Action: Action(action)}
+	if options != "" {
+		if rule.Action != Highlight {
+			return Rule{}, fmt.Errorf("only highlight rules take options")
+		}
+		for _, option := range strings.Split(options, ",") {
+			switch {
+			case option == "notify":
+				rule.Notify = true
+			case colorPattern.MatchString(option):
+				rule.Color = option
+			case option != "":
+				rule.Marker = option
+			}
+		}
+	}
+	return New(rule)
+}
+
+// New checks a rule and compiles its pattern
+func New(rule Rule) (Rule, error) {
+	if rule.Action != Hide && rule.Action != Read && rule.Action != Highlight {
+		return Rule{}, fmt.Errorf("unknown action %q, use hide, read or highlight", rule.Action)
+	}
+	if !slices.Contains(Fields, rule.Field) {
+		return Rule{}, fmt.Errorf("unknown field %q, use %s", rule.Field, strings.Join(Fields, ", "))
+	}
+	if n, err := strconv.Atoi(rule.Color); err == nil && n > 255 {
+		return Rule{}, fmt.Errorf("color %q isn't a color number or #rrggbb", rule.Color)
+	}
+	re, err := regexp.Compile(rule.Pattern)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid pattern: %w", err)
+	}
+	rule.re = re
+	return rule, nil
+}
+
+// FromDB compiles the stored rules, rules that no longer compile are
+// returned as errors and left out
+func FromDB(stored []database.ItemRule) ([]Rule, []error) {
+	var compiled []Rule
+	var errs []error
+	for _, r := range stored {
+		rule, err := New(Rule{
+			ID:      r.ID,
+			FeedID:  r.FeedID.Int64,
+			Field:   r.Field,
+			Pattern: r.Pattern,
+			Action:  Action(r.Action),
+			Color:   r.Color,
+			Marker:  r.Marker,
+			Notify:  r.Notify,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rule %d: %w", r.ID, err))
+			continue
+		}
+		compiled = append(compiled, rule)
+	}
+	return compiled, errs
+}
+
+// Params returns the values to store a rule with
+func (r Rule) Params() database.CreateItemRuleParams {
+	return database.CreateItemRuleParams{
+		FeedID:  sql.NullInt64{Int64: r.FeedID, Valid: r.FeedID != 0},
+		Field:   r.Field,
+		Pattern: r.Pattern,
+		Action:  string(r.Action),
+		Color:   r.Color,
+		Marker:  r.Marker,
+		Notify:  r.Notify,
+	}
+}
+
+// String writes a rule the way Parse reads it
+func (r Rule) String() string {
+	action := string(r.Action)
+	var options []string
+	for _, option := range []string{r.Color, r.Marker} {
+		if option != "" {
+			options = append(options, option)
+		}
+	}
+	if r.Notify {
+		options = append(options, "notify")
+	}
+	if len(options) > 0 {
+		action += "=" + strings.Join(options, ",")
+	}
+	return action + " " + r.Field + " " + r.Pattern
+}
+
+// Matches reports whether the rule applies to an item of a feed
+func (r Rule) Matches(feedID int64, item Item) bool {
+	if r.FeedID != 0 && r.FeedID != feedID {
+		return false
+	}
+	return r.re.MatchString(item.field(r.Field))
+}
+
+// Match returns the action for an item of a feed, hiding wins over marking
+// read. ok is false when no rule hides or marks the item read, highlight
+// rules are left to HighlightFor.
+func Match(rules []Rule, feedID int64, item Item) (action Action, ok bool) {
+	for _, rule := range rules {
+		if rule.Action == Highlight || !rule.Matches(feedID, item) {
+			continue
+		}
+		if rule.Action == Hide {
+			return Hide, true
+		}
+		action, ok = rule.Action, true
+	}
+	return action, ok
+}
+
+// HighlightFor returns the first highlight rule matching an item of a feed
+func HighlightFor(rules []Rule, feedID int64, item Item) (Rule, bool) {
+	for _, rule := range rules {
+		if rule.Action == Highlight && rule.Matches(feedID, item) {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
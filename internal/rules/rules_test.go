@@ -0,0 +1,88 @@
+package rules
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+func TestParse(t *testing.T) {
+	rule, err := Parse("  hide title (?i)sponsored  post ")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if rule.Action != Hide || rule.Field != "title" || rule.Pattern != "(?i)sponsored  post" {
+		t.Errorf("Parse() = %+v", rule)
+	}
+	if got := rule.String(); got != "hide title (?i)sponsored  post" {
+		t.Errorf("String() = %q", got)
+	}
+
+	rule, err = Parse("highlight=#ff5555,🔒,notify title (?i)\\bcve-")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if rule.Color != "#ff5555" || rule.Marker != "🔒" || !rule.Notify || rule.Pattern != `(?i)\bcve-` {
+		t.Errorf("Parse() = %+v", rule)
+	}
+	if got := rule.String(); got != `highlight=#ff5555,🔒,notify title (?i)\bcve-` {
+		t.Errorf("String() = %q", got)
+	}
+
+	for _, invalid := range []string{"", "hide title", "drop title x", "hide body x", "read link (", "hide=red title x", "highlight=300 title x"} {
+		if _, err := Parse(invalid); err == nil {
+			t.Errorf("Parse(%q) expected an error", invalid)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	stored := []database.ItemRule{
+		{ID: 1, Field: "author", Pattern: "^dependabot", Action: "read"},
+		{ID: 2, FeedID: sql.NullInt64{Int64: 7, Valid: true}, Field: "title", Pattern: "(?i)sponsored", Action: "hide"},
+		{ID: 3, Field: "link", Pattern: "(", Action: "hide"},
+		{ID: 4, Field: "title", Pattern: "(?i)bump", Action: "highlight", Marker: "⬆"},
+	}
+	compiled, errs := FromDB(stored)
+	if len(compiled) != 3 || len(errs) != 1 {
+		t.Fatalf("FromDB() = %d rules, %v, want 3 rules and 1 error", len(compiled), errs)
+	}
+
+	tests := []struct {
+		name   string
+		feedID int64
+		item   Item
+		want   Action
+		ok     bool
+	}{
+		{"no match", 7, Item{Title: "Release 1.0", Author: "me"}, "", false},
+		{"global rule", 3, Item{Title: "Bump x", Author: "dependabot[bot]"}, Read, true},
+		{"feed rule", 7, Item{Title: "SPONSORED: buy this"}, Hide, true},
+		{"feed rule in another feed", 8, Item{Title: "SPONSORED: buy this"}, "", false},
+		{"hide wins", 7, Item{Title: "Sponsored bump", Author: "dependabot"}, Hide, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Match(compiled, tt.feedID, tt.item)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Match() = %q, %v, want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestHighlightFor(t *testing.T) {
+	compiled, _ := FromDB([]database.ItemRule{
+		{ID: 1, Field: "title", Pattern: "CVE", Action: "hide", FeedID: sql.NullInt64{Int64: 2, Valid: true}},
+		{ID: 2, Field: "title", Pattern: "CVE", Action: "highlight", Color: "1"},
+		{ID: 3, Field: "title", Pattern: "CVE", Action: "highlight", Color: "2"},
+	})
+	rule, ok := HighlightFor(compiled, 1, Item{Title: "CVE-2026-1"})
+	if !ok || rule.ID != 2 {
+		t.Errorf("HighlightFor() = %+v, %v, want the first highlight rule", rule, ok)
+	}
+	if _, ok := HighlightFor(compiled, 1, Item{Title: "Release"}); ok {
+		t.Error("HighlightFor() matched an item without CVE")
+	}
+}
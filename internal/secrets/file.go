@@ -0,0 +1,157 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// keySize is the size of the AES-256 key of the file store
+const keySize = 32
+
+// FileStore keeps secrets in a file encrypted with AES-GCM. The key is kept
+// in a separate file only the user can read, so the secrets don't end up
+// readable in backups or dotfile repositories that leave the key out.
+type FileStore struct {
+	path    string
+	keyPath string
+	mutex   sync.Mutex
+}
+
+// NewFileStore returns the store of secrets encrypted in path with the key in
+// keyPath, both are created on the first Set
+func NewFileStore(path, keyPath string) *FileStore {
+	return &FileStore{path: path, keyPath: keyPath}
+}
+
+func (s *FileStore) Get(name string) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	values, err := s.read(false)
+	if err != nil {
+		return "", err
+	}
+	value, ok := values[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+func (s *FileStore) Set(name, value string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	values, err := s.read(true)
+	if err != nil {
+		return err
+	}
+	values[name] = value
+	return s.write(values)
+}
+
+func (s *FileStore) Delete(name string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	values, err := s.read(false)
+	if err != nil {
+		return err
+	}
+	if _, ok := values[name]; !ok {
+		return ErrNotFound
+	}
+	delete(values, name)
+	return s.write(values)
+}
+
+// read decrypts the secrets, a missing file is an empty store when create is
+// set and ErrNotFound otherwise
+func (s *FileStore) read(create bool) (map[string]string, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		if create {
+			return make(map[string]string), nil
+		}
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := s.cipher(false)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("%s is damaged", s.path)
+	}
+	nonce, sealed := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't decrypt %s, was %s replaced? %w", s.path, s.keyPath, err)
+	}
+
+	values := make(map[string]string)
+	if err := json.Unmarshal(plain, &values); err != nil {
+		return nil, fmt.Errorf("%s is damaged: %w", s.path, err)
+	}
+	return values, nil
+}
+
+// write encrypts the secrets with a new nonce and replaces the file
+func (s *FileStore) write(values map[string]string) error {
+	aead, err := s.cipher(true)
+	if err != nil {
+		return err
+	}
+	plain, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, aead.Seal(nonce, nonce, plain, nil), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, s.path)
+}
+
+// cipher returns the AES-GCM cipher of the key file, creating the key when
+// create is set and there is none yet
+func (s *FileStore) cipher(create bool) (cipher.AEAD, error) {
+	key, err := os.ReadFile(s.keyPath)
+	if errors.Is(err, os.ErrNotExist) && create {
+		key = make([]byte, keySize)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(s.keyPath), 0700); err != nil {
+			return nil, err
+		}
+		err = os.WriteFile(s.keyPath, key, 0600)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("%s is not a %d byte key", s.keyPath, keySize)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,78 @@
+//go:build darwin
+
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// macKeychain keeps secrets as generic passwords in the macOS Keychain with
+// the security command
+type macKeychain struct {
+	security string
+}
+
+// errItemNotFound is the exit status of security for a missing item
+const errItemNotFound = 44
+
+// newKeychain returns the macOS Keychain when the security command is there
+func newKeychain() (Store, bool) {
+	security, err := exec.LookPath("security")
+	if err != nil {
+		return nil, false
+	}
+	return &macKeychain{security: security}, true
+}
+
+func (k *macKeychain) run(args ...string) (string, error) {
+	out, err := exec.Command(k.security, args...).Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == errItemNotFound {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func (k *macKeychain) Get(name string) (string, error) {
+	return k.run("find-generic-password", "-s", Service, "-a", name, "-w")
+}
+
+// Set hands the command to security on stdin in its interactive mode, so the
+// value isn't on the command line where ps shows it
+func (k *macKeychain) Set(name, value string) error {
+	if strings.ContainsAny(name+value, "\r\n") {
+		return errors.New("secrets can't contain line breaks")
+	}
+	cmd := exec.Command(k.security, "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+		quoteArg(Service), quoteArg(name), quoteArg(value)))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	// The interactive mode exits cleanly after a failed command, it only
+	// says so on stderr
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return fmt.Errorf("security: %s", msg)
+	}
+	return nil
+}
+
+// quoteArg quotes an argument for a command line of security's interactive
+// mode
+func quoteArg(arg string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
+
+func (k *macKeychain) Delete(name string) error {
+	_, err := k.run("delete-generic-password", "-s", Service, "-a", name)
+	return err
+}
@@ -0,0 +1,69 @@
+//go:build linux
+
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// secretService keeps secrets in the Secret Service of GNOME Keyring or
+// KWallet with the secret-tool command
+type secretService struct {
+	secretTool string
+}
+
+// newKeychain returns the Secret Service when secret-tool is installed and
+// there is a session bus to reach it on
+func newKeychain() (Store, bool) {
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return nil, false
+	}
+	secretTool, err := exec.LookPath("secret-tool")
+	if err != nil {
+		return nil, false
+	}
+	return &secretService{secretTool: secretTool}, true
+}
+
+func (s *secretService) run(stdin string, args ...string) (string, error) {
+	cmd := exec.Command(s.secretTool, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("secret-tool: %s", msg)
+		}
+		return "", err
+	}
+	return string(out), nil
+}
+
+func (s *secretService) Get(name string) (string, error) {
+	value, err := s.run("", "lookup", "service", Service, "account", name)
+	var exitErr *exec.ExitError
+	// lookup fails without a message when nothing matches
+	if errors.As(err, &exitErr) || (err == nil && value == "") {
+		return "", ErrNotFound
+	}
+	return value, err
+}
+
+func (s *secretService) Set(name, value string) error {
+	_, err := s.run(value, "store", "--label", Service+" "+name, "service", Service, "account", name)
+	return err
+}
+
+func (s *secretService) Delete(name string) error {
+	if _, err := s.Get(name); err != nil {
+		return err
+	}
+	_, err := s.run("", "clear", "service", Service, "account", name)
+	return err
+}
@@ -0,0 +1,9 @@
+//go:build !darwin && !linux && !windows
+
+package secrets
+
+// newKeychain reports there is no keychain, secrets are kept in the
+// encrypted file
+func newKeychain() (Store, bool) {
+	return nil, false
+}
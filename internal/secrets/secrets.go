@@ -0,0 +1,148 @@
+// Package secrets keeps feed credentials and sync tokens out of the URLs file
+// and the database, in the OS keychain or, without one, an encrypted file.
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Service is the name secrets are stored under in the OS keychain
+const Service = "newsgoat"
+
+// Prefix starts values that refer to a stored secret, e.g. keyring:work-feed
+const Prefix = "keyring:"
+
+// ErrNotFound is returned for a secret that isn't stored
+var ErrNotFound = errors.New("secret not found")
+
+// Store keeps secrets by name
+type Store interface {
+	Get(name string) (string, error)
+	Set(name, value string) error
+	Delete(name string) error
+}
+
+// open returns the store the package functions use, tests replace it
+var open = Default
+
+// Default returns the OS keychain, macOS Keychain, the Secret Service or the
+// Windows Credential Manager, falling back to the encrypted file when there
+// is none or it fails
+func Default() (Store, error) {
+	file, err := defaultFileStore()
+	if err != nil {
+		return nil, err
+	}
+	if keychain, ok := newKeychain(); ok {
+		return &fallbackStore{primary: keychain, fallback: file}, nil
+	}
+	return file, nil
+}
+
+// defaultFileStore returns the encrypted file in the config directory
+func defaultFileStore() (*FileStore, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(homeDir, ".config", "newsgoat")
+	return NewFileStore(filepath.Join(dir, "secrets"), filepath.Join(dir, "secrets.key")), nil
+}
+
+// Reference returns the value that refers to the secret with the name
+func Reference(name string) string {
+	return Prefix + name
+}
+
+// IsReference reports whether a value refers to a stored secret
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// Resolve returns the secret a value refers to with keyring:name, values
+// without the prefix are returned as they are
+func Resolve(value string) (string, error) {
+	name, ok := strings.CutPrefix(value, Prefix)
+	if !ok {
+		return value, nil
+	}
+	store, err := open()
+	if err != nil {
+		return "", err
+	}
+	secret, err := store.Get(name)
+	if err != nil {
+		return "", fmt.Errorf("secret %q: %w", name, err)
+	}
+	return secret, nil
+}
+
+// Set stores a secret in the default store
+func Set(name, value string) error {
+	if name == "" {
+		return errors.New("secret name is empty")
+	}
+	store, err := open()
+	if err != nil {
+		return err
+	}
+	return store.Set(name, value)
+}
+
+// Delete removes a secret from the default store
+func Delete(name string) error {
+	store, err := open()
+	if err != nil {
+		return err
+	}
+	return store.Delete(name)
+}
+
+// fallbackStore keeps secrets in the keychain, and in the encrypted file when
+// the keychain fails, e.g. without a Secret Service running
+type fallbackStore struct {
+	primary  Store
+	fallback Store
+}
+
+func (s *fallbackStore) Get(name string) (string, error) {
+	value, err := s.primary.Get(name)
+	if err == nil {
+		return value, nil
+	}
+	value, fallbackErr := s.fallback.Get(name)
+	if fallbackErr == nil {
+		return value, nil
+	}
+	if errors.Is(fallbackErr, ErrNotFound) {
+		return "", err
+	}
+	return "", fallbackErr
+}
+
+func (s *fallbackStore) Set(name, value string) error {
+	if err := s.primary.Set(name, value); err != nil {
+		return s.fallback.Set(name, value)
+	}
+	// An older copy in the file would be found if the keychain fails later
+	if err := s.fallback.Delete(name); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (s *fallbackStore) Delete(name string) error {
+	err := s.primary.Delete(name)
+	fallbackErr := s.fallback.Delete(name)
+	if err == nil || fallbackErr == nil {
+		return nil
+	}
+	if errors.Is(err, ErrNotFound) {
+		return fallbackErr
+	}
+	return err
+}
@@ -0,0 +1,101 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFileStore(filepath.Join(dir, "secrets"), filepath.Join(dir, "secrets.key"))
+
+	if _, err := store.Get("work"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() before any Set error = %v, want ErrNotFound", err)
+	}
+	if err := store.Set("work", "s3cret"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := store.Set("sync_token", "abc"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if value, err := store.Get("work"); err != nil || value != "s3cret" {
+		t.Errorf("Get() = %q, %v, want s3cret", value, err)
+	}
+
+	// The secrets aren't in the file as plain text
+	data, err := os.ReadFile(filepath.Join(dir, "secrets"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "s3cret") {
+		t.Error("the secrets file holds the secret in plain text")
+	}
+	if info, err := os.Stat(filepath.Join(dir, "secrets.key")); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("key file mode = %v, %v, want 0600", info.Mode(), err)
+	}
+
+	if err := store.Delete("work"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Get("work"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want ErrNotFound", err)
+	}
+	if value, err := store.Get("sync_token"); err != nil || value != "abc" {
+		t.Errorf("Get() of the other secret = %q, %v", value, err)
+	}
+
+	// A different key can't read the file
+	other := NewFileStore(filepath.Join(dir, "secrets"), filepath.Join(dir, "other.key"))
+	if err := os.WriteFile(filepath.Join(dir, "other.key"), make([]byte, keySize), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Get("sync_token"); err == nil {
+		t.Error("Get() with the wrong key succeeded")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFileStore(filepath.Join(dir, "secrets"), filepath.Join(dir, "secrets.key"))
+	open = func() (Store, error) { return store, nil }
+	t.Cleanup(func() { open = Default })
+
+	if err := Set("work", "s3cret"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if value, err := Resolve(Reference("work")); err != nil || value != "s3cret" {
+		t.Errorf("Resolve() = %q, %v, want s3cret", value, err)
+	}
+	if value, err := Resolve("plain"); err != nil || value != "plain" {
+		t.Errorf("Resolve() of a plain value = %q, %v", value, err)
+	}
+	if _, err := Resolve("keyring:missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Resolve() of a missing secret error = %v, want ErrNotFound", err)
+	}
+}
+
+// failingStore is a keychain that can't be reached
+type failingStore struct{}
+
+func (failingStore) Get(string) (string, error) { return "", errors.New("no keychain") }
+func (failingStore) Set(string, string) error   { return errors.New("no keychain") }
+func (failingStore) Delete(string) error        { return errors.New("no keychain") }
+
+func TestFallbackStore(t *testing.T) {
+	dir := t.TempDir()
+	file := NewFileStore(filepath.Join(dir, "secrets"), filepath.Join(dir, "secrets.key"))
+	store := &fallbackStore{primary: failingStore{}, fallback: file}
+
+	if err := store.Set("work", "s3cret"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if value, err := store.Get("work"); err != nil || value != "s3cret" {
+		t.Errorf("Get() = %q, %v, want the secret from the file", value, err)
+	}
+	if err := store.Delete("work"); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+}
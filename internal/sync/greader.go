@@ -0,0 +1,379 @@
+package sync
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/logging"
+	"github.com/jarv/newsgoat/internal/version"
+)
+
+const (
+	// greaderPageSize is how many entries are requested at a time
+	greaderPageSize = 250
+
+	// greaderIDsPageSize is how many IDs are requested at a time
+	greaderIDsPageSize = 10000
+
+	// greaderInitialEntries caps the recent entries fetched by the first
+	// sync, like minifluxInitialEntries
+	greaderInitialEntries = 1000
+
+	// greaderEditBatch is how many entries one edit-tag request changes
+	greaderEditBatch = 250
+
+	greaderReadingList = "user/-/state/com.google/reading-list"
+	greaderRead        = "user/-/state/com.google/read"
+	greaderStarred     = "user/-/state/com.google/starred"
+
+	// greaderItemPrefix starts the long form of entry IDs, the hexadecimal
+	// form of the decimal IDs the ID lists return
+	greaderItemPrefix = "tag:google.com,2005:reader/item/"
+)
+
+// GoogleReader is a client of the Google Reader API that FreshRSS, The Old
+// Reader and other servers implement, signed in with a username and password.
+// FreshRSS uses the API password set in its profile.
+type GoogleReader struct {
+	baseURL  string
+	username string
+	password string
+	client   *http.Client
+
+	auth     string            // Token from ClientLogin
+	editKey  string            // Token that has to be sent with changes
+	feedURLs map[string]string // Stream ID -> feed URL
+}
+
+// NewGoogleReader creates a client of the API at baseURL, e.g.
+// https://freshrss.example.com/api/greader.php
+func NewGoogleReader(baseURL, username, password string, client *http.Client) *GoogleReader {
+	return &GoogleReader{baseURL: baseURL, username: username, password: password, client: client}
+}
+
+type greaderSubscription struct {
+	ID         string `json:"id"` // "feed/" and the feed URL or a server ID
+	Title      string `json:"title"`
+	URL        string `json:"url"`
+	Categories []struct {
+		ID    string `json:"id"`
+		Label string `json:"label"`
+	} `json:"categories"`
+}
+
+type greaderLink struct {
+	Href string `json:"href"`
+}
+
+type greaderItem struct {
+	ID         string        `json:"id"`
+	Categories []string      `json:"categories"`
+	Canonical  []greaderLink `json:"canonical"`
+	Alternate  []greaderLink `json:"alternate"`
+	Origin     struct {
+		StreamID string `json:"streamId"`
+	} `json:"origin"`
+}
+
+type greaderStream struct {
+	Items        []greaderItem `json:"items"`
+	Continuation string        `json:"continuation"`
+}
+
+type greaderItemIDs struct {
+	ItemRefs []struct {
+		ID string `json:"id"`
+	} `json:"itemRefs"`
+	Continuation string `json:"continuation"`
+}
+
+// Name implements Backend
+func (c *GoogleReader) Name() string {
+	return ServiceGoogleReader
+}
+
+// Subscriptions implements Backend
+func (c *GoogleReader) Subscriptions(ctx context.Context) ([]Subscription, error) {
+	var list struct {
+		Subscriptions []greaderSubscription `json:"subscriptions"`
+	}
+	if err := c.get(ctx, "/reader/api/0/subscription/list", url.Values{}, &list); err != nil {
+		return nil, err
+	}
+	c.feedURLs = make(map[string]string, len(list.Subscriptions))
+	subscriptions := make([]Subscription, 0, len(list.Subscriptions))
+	for _, sub := range list.Subscriptions {
+		feedURL := sub.URL
+		if feedURL == "" {
+			feedURL = strings.TrimPrefix(sub.ID, "feed/")
+		}
+		c.feedURLs[sub.ID] = feedURL
+		subscription := Subscription{FeedURL: feedURL, Title: sub.Title}
+		if len(sub.Categories) > 0 {
+			subscription.Category = sub.Categories[0].Label
+			if subscription.Category == "" {
+				_, subscription.Category, _ = strings.Cut(sub.Categories[0].ID, "/label/")
+			}
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, nil
+}
+
+// Entries implements Backend. The API can't list the entries whose state
+// changed, so after the first sync it only returns new entries and the state
+// of older ones comes from UnreadIDs and StarredIDs.
+func (c *GoogleReader) Entries(ctx context.Context, since time.Time) ([]Entry, error) {
+	if c.feedURLs == nil {
+		if _, err := c.Subscriptions(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if !since.IsZero() {
+		return c.stream(ctx, greaderReadingList, url.Values{"ot": {strconv.FormatInt(since.Unix(), 10)}}, 0)
+	}
+
+	recent, err := c.stream(ctx, greaderReadingList, url.Values{}, greaderInitialEntries)
+	if err != nil {
+		return nil, err
+	}
+	starred, err := c.stream(ctx, greaderStarred, url.Values{}, 0)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(recent))
+	for _, entry := range recent {
+		seen[entry.ID] = true
+	}
+	for _, entry := range starred {
+		if !seen[entry.ID] {
+			recent = append(recent, entry)
+		}
+	}
+	return recent, nil
+}
+
+// stream pages through the entries of a stream, up to maxEntries when it
+// isn't 0
+func (c *GoogleReader) stream(ctx context.Context, streamID string, query url.Values, maxEntries int) ([]Entry, error) {
+	var entries []Entry
+	query.Set("n", strconv.Itoa(greaderPageSize))
+	for {
+		var page greaderStream
+		if err := c.get(ctx, "/reader/api/0/stream/contents/"+streamID, query, &page); err != nil {
+			return nil, err
+		}
+		for _, item := range page.Items {
+			entries = append(entries, c.entry(item))
+		}
+		if page.Continuation == "" || len(page.Items) == 0 || (maxEntries > 0 && len(entries) >= maxEntries) {
+			return entries, nil
+		}
+		query.Set("c", page.Continuation)
+	}
+}
+
+func (c *GoogleReader) entry(item greaderItem) Entry {
+	entry := Entry{ID: greaderShortID(item.ID), FeedURL: c.feedURLs[item.Origin.StreamID]}
+	if entry.FeedURL == "" {
+		entry.FeedURL = strings.TrimPrefix(item.Origin.StreamID, "feed/")
+	}
+	switch {
+	case len(item.Canonical) > 0:
+		entry.URL = item.Canonical[0].Href
+	case len(item.Alternate) > 0:
+		entry.URL = item.Alternate[0].Href
+	}
+	// Categories name the user, e.g. user/1234/state/com.google/read
+	for _, category := range item.Categories {
+		switch {
+		case strings.HasSuffix(category, "/state/com.google/read"):
+			entry.Read = true
+		case strings.HasSuffix(category, "/state/com.google/starred"):
+			entry.Starred = true
+		}
+	}
+	return entry
+}
+
+// UnreadIDs implements StateLister
+func (c *GoogleReader) UnreadIDs(ctx context.Context) ([]string, error) {
+	return c.itemIDs(ctx, url.Values{"s": {greaderReadingList}, "xt": {greaderRead}})
+}
+
+// StarredIDs implements StateLister
+func (c *GoogleReader) StarredIDs(ctx context.Context) ([]string, error) {
+	return c.itemIDs(ctx, url.Values{"s": {greaderStarred}})
+}
+
+func (c *GoogleReader) itemIDs(ctx context.Context, query url.Values) ([]string, error) {
+	var ids []string
+	query.Set("n", strconv.Itoa(greaderIDsPageSize))
+	for {
+		var page greaderItemIDs
+		if err := c.get(ctx, "/reader/api/0/stream/items/ids", query, &page); err != nil {
+			return nil, err
+		}
+		for _, ref := range page.ItemRefs {
+			ids = append(ids, greaderShortID(ref.ID))
+		}
+		if page.Continuation == "" || len(page.ItemRefs) == 0 {
+			return ids, nil
+		}
+		query.Set("c", page.Continuation)
+	}
+}
+
+// SetRead implements Backend
+func (c *GoogleReader) SetRead(ctx context.Context, ids []string, read bool) error {
+	return c.editTag(ctx, ids, greaderRead, read)
+}
+
+// SetStarred implements Backend
+func (c *GoogleReader) SetStarred(ctx context.Context, ids []string, starred bool) error {
+	return c.editTag(ctx, ids, greaderStarred, starred)
+}
+
+// editTag adds the tag to the entries, or removes it
+func (c *GoogleReader) editTag(ctx context.Context, ids []string, tag string, add bool) error {
+	if c.editKey == "" {
+		key, err := c.request(ctx, http.MethodGet, "/reader/api/0/token", nil)
+		if err != nil {
+			return err
+		}
+		c.editKey = strings.TrimSpace(string(key))
+	}
+	for start := 0; start < len(ids); start += greaderEditBatch {
+		form := url.Values{"T": {c.editKey}}
+		if add {
+			form.Set("a", tag)
+		} else {
+			form.Set("r", tag)
+		}
+		for _, id := range ids[start:min(start+greaderEditBatch, len(ids))] {
+			form.Add("i", greaderLongID(id))
+		}
+		if _, err := c.request(ctx, http.MethodPost, "/reader/api/0/edit-tag", form); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// greaderShortID turns the long form of an entry ID into the decimal one,
+// so entries are stored the same whichever form the server returned
+func greaderShortID(id string) string {
+	hex, ok := strings.CutPrefix(id, greaderItemPrefix)
+	if !ok {
+		return id
+	}
+	n, err := strconv.ParseUint(hex, 16, 64)
+	if err != nil {
+		return id
+	}
+	return strconv.FormatInt(int64(n), 10)
+}
+
+// greaderLongID turns a decimal entry ID into the long form every server
+// accepts
+func greaderLongID(id string) string {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return id
+	}
+	return fmt.Sprintf("%s%016x", greaderItemPrefix, uint64(n))
+}
+
+// login signs in with ClientLogin for the token sent with every request
+func (c *GoogleReader) login(ctx context.Context) error {
+	form := url.Values{"Email": {c.username}, "Passwd": {c.password}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/accounts/ClientLogin", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", version.GetUserAgent())
+	body, err := c.do(req)
+	if err != nil {
+		return err
+	}
+
+	// The response has a key=value pair on each line
+	scanner := bufio.NewScanner(strings.NewReader(string(body)))
+	for scanner.Scan() {
+		if auth, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "Auth="); ok && auth != "" {
+			c.auth = auth
+			logging.RegisterSecret(auth)
+			return nil
+		}
+	}
+	return fmt.Errorf("greader: sign in didn't return a token")
+}
+
+// get sends a GET request and decodes the JSON response into result
+func (c *GoogleReader) get(ctx context.Context, path string, query url.Values, result interface{}) error {
+	query.Set("output", "json")
+	body, err := c.request(ctx, http.MethodGet, path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("greader: invalid response: %w", err)
+	}
+	return nil
+}
+
+// request sends an API request, with form as the body when it isn't nil,
+// and signs in first when needed
+func (c *GoogleReader) request(ctx context.Context, method, path string, form url.Values) ([]byte, error) {
+	if c.auth == "" {
+		if err := c.login(ctx); err != nil {
+			return nil, err
+		}
+	}
+	var reader io.Reader
+	if form != nil {
+		reader = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "GoogleLogin auth="+c.auth)
+	req.Header.Set("User-Agent", version.GetUserAgent())
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	return c.do(req)
+}
+
+func (c *GoogleReader) do(req *http.Request) ([]byte, error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message := strings.TrimSpace(string(body))
+		if message == "" || len(message) > 200 || strings.HasPrefix(message, "<") {
+			message = http.StatusText(resp.StatusCode)
+		}
+		return nil, fmt.Errorf("greader: HTTP %d: %s", resp.StatusCode, message)
+	}
+	return body, nil
+}
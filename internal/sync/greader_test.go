@@ -0,0 +1,124 @@
+package sync
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGoogleReader(t *testing.T) {
+	var edits []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/accounts/ClientLogin" {
+			if r.FormValue("Email") != "alice" || r.FormValue("Passwd") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte("Unauthorized!"))
+				return
+			}
+			_, _ = w.Write([]byte("SID=alice/abc\nLSID=null\nAuth=alice/abc\n"))
+			return
+		}
+		if r.Header.Get("Authorization") != "GoogleLogin auth=alice/abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/reader/api/0/subscription/list":
+			_, _ = w.Write([]byte(`{"subscriptions":[
+				{"id":"feed/1","title":"A","url":"https://example.com/a.xml","categories":[]},
+				{"id":"feed/https://example.com/b.xml","title":"B","categories":[{"id":"user/-/label/Tech","label":"Tech"}]}
+			]}`))
+		case "/reader/api/0/stream/contents/" + greaderReadingList:
+			if r.URL.Query().Get("ot") != "1700000000" {
+				t.Errorf("ot = %q", r.URL.Query().Get("ot"))
+			}
+			if r.URL.Query().Get("c") == "" {
+				_, _ = w.Write([]byte(`{"items":[
+					{"id":"tag:google.com,2005:reader/item/000000000000001f","categories":["user/1/state/com.google/read"],
+					 "alternate":[{"href":"https://example.com/1"}],"origin":{"streamId":"feed/1"}}
+				],"continuation":"next"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"items":[
+				{"id":"tag:google.com,2005:reader/item/0000000000000020","categories":["user/-/state/com.google/starred"],
+				 "canonical":[{"href":"https://example.com/2"}],"origin":{"streamId":"feed/https://example.com/b.xml"}}
+			]}`))
+		case "/reader/api/0/stream/items/ids":
+			if r.URL.Query().Get("xt") == greaderRead {
+				_, _ = w.Write([]byte(`{"itemRefs":[{"id":"32"}]}`))
+			} else {
+				_, _ = w.Write([]byte(`{"itemRefs":[{"id":"32"},{"id":"33"}]}`))
+			}
+		case "/reader/api/0/token":
+			_, _ = w.Write([]byte("edit-token\n"))
+		case "/reader/api/0/edit-tag":
+			if r.FormValue("T") != "edit-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			edits = append(edits, r.FormValue("a")+r.FormValue("r")+" "+strings.Join(r.Form["i"], ","))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := NewGoogleReader(server.URL, "alice", "secret", server.Client())
+
+	subscriptions, err := client.Subscriptions(ctx)
+	if err != nil {
+		t.Fatalf("Subscriptions() error = %v", err)
+	}
+	wantSubscriptions := []Subscription{
+		{FeedURL: "https://example.com/a.xml", Title: "A"},
+		{FeedURL: "https://example.com/b.xml", Title: "B", Category: "Tech"},
+	}
+	if !reflect.DeepEqual(subscriptions, wantSubscriptions) {
+		t.Errorf("Subscriptions() = %+v, want %+v", subscriptions, wantSubscriptions)
+	}
+
+	entries, err := client.Entries(ctx, time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	wantEntries := []Entry{
+		{ID: "31", FeedURL: "https://example.com/a.xml", URL: "https://example.com/1", Read: true},
+		{ID: "32", FeedURL: "https://example.com/b.xml", URL: "https://example.com/2", Starred: true},
+	}
+	if !reflect.DeepEqual(entries, wantEntries) {
+		t.Errorf("Entries() = %+v, want %+v", entries, wantEntries)
+	}
+
+	unread, err := client.UnreadIDs(ctx)
+	if err != nil || !reflect.DeepEqual(unread, []string{"32"}) {
+		t.Errorf("UnreadIDs() = %v, %v", unread, err)
+	}
+	starred, err := client.StarredIDs(ctx)
+	if err != nil || !reflect.DeepEqual(starred, []string{"32", "33"}) {
+		t.Errorf("StarredIDs() = %v, %v", starred, err)
+	}
+
+	if err := client.SetRead(ctx, []string{"31"}, false); err != nil {
+		t.Fatalf("SetRead() error = %v", err)
+	}
+	if err := client.SetStarred(ctx, []string{"32"}, true); err != nil {
+		t.Fatalf("SetStarred() error = %v", err)
+	}
+	wantEdits := []string{
+		greaderRead + " tag:google.com,2005:reader/item/000000000000001f",
+		greaderStarred + " tag:google.com,2005:reader/item/0000000000000020",
+	}
+	if !reflect.DeepEqual(edits, wantEdits) {
+		t.Errorf("edits = %q, want %q", edits, wantEdits)
+	}
+
+	_, err = NewGoogleReader(server.URL, "alice", "wrong", server.Client()).Subscriptions(ctx)
+	if err == nil || !strings.Contains(err.Error(), "Unauthorized!") {
+		t.Errorf("Subscriptions() with a wrong password error = %v", err)
+	}
+}
@@ -0,0 +1,233 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/version"
+)
+
+const (
+	// minifluxPageSize is how many entries are requested at a time
+	minifluxPageSize = 250
+
+	// minifluxInitialEntries caps the recent entries fetched by the first
+	// sync, older ones are matched once they change
+	minifluxInitialEntries = 1000
+
+	// minifluxDefaultCategory is the category Miniflux puts feeds in when
+	// none was chosen, it doesn't become a folder
+	minifluxDefaultCategory = "All"
+)
+
+// Miniflux is a client of the Miniflux API, signed in with an API token
+// created under Settings > API Keys
+type Miniflux struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewMiniflux creates a client of the Miniflux server at baseURL
+func NewMiniflux(baseURL, token string, client *http.Client) *Miniflux {
+	return &Miniflux{baseURL: baseURL, token: token, client: client}
+}
+
+type minifluxFeed struct {
+	FeedURL  string `json:"feed_url"`
+	Title    string `json:"title"`
+	Category struct {
+		Title string `json:"title"`
+	} `json:"category"`
+}
+
+type minifluxEntry struct {
+	ID      int64  `json:"id"`
+	Status  string `json:"status"` // "read", "unread" or "removed"
+	Starred bool   `json:"starred"`
+	URL     string `json:"url"`
+	Feed    struct {
+		FeedURL string `json:"feed_url"`
+	} `json:"feed"`
+}
+
+type minifluxEntries struct {
+	Total   int             `json:"total"`
+	Entries []minifluxEntry `json:"entries"`
+}
+
+// Name implements Backend
+func (c *Miniflux) Name() string {
+	return ServiceMiniflux
+}
+
+// Subscriptions implements Backend
+func (c *Miniflux) Subscriptions(ctx context.Context) ([]Subscription, error) {
+	var feeds []minifluxFeed
+	if err := c.do(ctx, http.MethodGet, "/v1/feeds", nil, &feeds); err != nil {
+		return nil, err
+	}
+	subscriptions := make([]Subscription, len(feeds))
+	for i, feed := range feeds {
+		subscriptions[i] = Subscription{FeedURL: feed.FeedURL, Title: feed.Title}
+		if feed.Category.Title != minifluxDefaultCategory {
+			subscriptions[i].Category = feed.Category.Title
+		}
+	}
+	return subscriptions, nil
+}
+
+// Entries implements Backend. The first sync fetches the most recent entries
+// and every starred one.
+func (c *Miniflux) Entries(ctx context.Context, since time.Time) ([]Entry, error) {
+	if !since.IsZero() {
+		query := url.Values{
+			"changed_after": {strconv.FormatInt(since.Unix(), 10)},
+			"order":         {"id"},
+			"direction":     {"asc"},
+		}
+		return c.entries(ctx, query, 0)
+	}
+
+	recent, err := c.entries(ctx, url.Values{"order": {"published_at"}, "direction": {"desc"}}, minifluxInitialEntries)
+	if err != nil {
+		return nil, err
+	}
+	starred, err := c.entries(ctx, url.Values{"starred": {"true"}, "order": {"id"}, "direction": {"asc"}}, 0)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(recent))
+	for _, entry := range recent {
+		seen[entry.ID] = true
+	}
+	for _, entry := range starred {
+		if !seen[entry.ID] {
+			recent = append(recent, entry)
+		}
+	}
+	return recent, nil
+}
+
+// entries pages through /v1/entries, up to maxEntries when it isn't 0.
+// Removed entries are left out.
+func (c *Miniflux) entries(ctx context.Context, query url.Values, maxEntries int) ([]Entry, error) {
+	var entries []Entry
+	for offset := 0; ; offset += minifluxPageSize {
+		query.Set("limit", strconv.Itoa(minifluxPageSize))
+		query.Set("offset", strconv.Itoa(offset))
+		var page minifluxEntries
+		if err := c.do(ctx, http.MethodGet, "/v1/entries?"+query.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		for _, entry := range page.Entries {
+			if entry.Status == "removed" {
+				continue
+			}
+			entries = append(entries, Entry{
+				ID:      strconv.FormatInt(entry.ID, 10),
+				FeedURL: entry.Feed.FeedURL,
+				URL:     entry.URL,
+				Read:    entry.Status == "read",
+				Starred: entry.Starred,
+			})
+		}
+		if len(page.Entries) < minifluxPageSize || (maxEntries > 0 && offset+minifluxPageSize >= maxEntries) {
+			return entries, nil
+		}
+	}
+}
+
+// SetRead implements Backend
+func (c *Miniflux) SetRead(ctx context.Context, ids []string, read bool) error {
+	entryIDs, err := minifluxIDs(ids)
+	if err != nil {
+		return err
+	}
+	status := "unread"
+	if read {
+		status = "read"
+	}
+	body := map[string]interface{}{"entry_ids": entryIDs, "status": status}
+	return c.do(ctx, http.MethodPut, "/v1/entries", body, nil)
+}
+
+// SetStarred implements Backend, Miniflux only has a request that toggles
+// the star of an entry
+func (c *Miniflux) SetStarred(ctx context.Context, ids []string, starred bool) error {
+	entryIDs, err := minifluxIDs(ids)
+	if err != nil {
+		return err
+	}
+	for _, id := range entryIDs {
+		if err := c.do(ctx, http.MethodPut, fmt.Sprintf("/v1/entries/%d/bookmark", id), nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func minifluxIDs(ids []string) ([]int64, error) {
+	entryIDs := make([]int64, len(ids))
+	for i, id := range ids {
+		var err error
+		if entryIDs[i], err = strconv.ParseInt(id, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid miniflux entry ID %q", id)
+		}
+	}
+	return entryIDs, nil
+}
+
+// do sends an API request with body encoded as JSON when it isn't nil and
+// decodes the response into result when it isn't nil
+func (c *Miniflux) do(ctx context.Context, method, path string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Auth-Token", c.token)
+	req.Header.Set("User-Agent", version.GetUserAgent())
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"error_message"`
+		}
+		if json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("miniflux: HTTP %d: %s", resp.StatusCode, apiErr.Message)
+		}
+		return fmt.Errorf("miniflux: HTTP %d: %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return fmt.Errorf("miniflux: invalid response: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMiniflux(t *testing.T) {
+	var requests []string
+	var statusBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error_message":"Access Unauthorized"}`))
+			return
+		}
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.URL.Path == "/v1/feeds":
+			_, _ = w.Write([]byte(`[
+				{"feed_url":"https://example.com/a.xml","title":"A","category":{"title":"All"}},
+				{"feed_url":"https://example.com/b.xml","title":"B","category":{"title":"Tech"}}
+			]`))
+		case r.URL.Path == "/v1/entries" && r.Method == http.MethodGet:
+			if r.URL.Query().Get("changed_after") != "1700000000" {
+				t.Errorf("changed_after = %q", r.URL.Query().Get("changed_after"))
+			}
+			_, _ = w.Write([]byte(`{"total":3,"entries":[
+				{"id":1,"status":"read","starred":false,"url":"https://example.com/1","feed":{"feed_url":"https://example.com/a.xml"}},
+				{"id":2,"status":"unread","starred":true,"url":"https://example.com/2","feed":{"feed_url":"https://example.com/a.xml"}},
+				{"id":3,"status":"removed","starred":false,"url":"https://example.com/3","feed":{"feed_url":"https://example.com/a.xml"}}
+			]}`))
+		case r.URL.Path == "/v1/entries" && r.Method == http.MethodPut:
+			if err := json.NewDecoder(r.Body).Decode(&statusBody); err != nil {
+				t.Errorf("invalid body: %v", err)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case strings.HasSuffix(r.URL.Path, "/bookmark"):
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := NewMiniflux(server.URL, "secret", server.Client())
+
+	subscriptions, err := client.Subscriptions(ctx)
+	if err != nil {
+		t.Fatalf("Subscriptions() error = %v", err)
+	}
+	wantSubscriptions := []Subscription{
+		{FeedURL: "https://example.com/a.xml", Title: "A"},
+		{FeedURL: "https://example.com/b.xml", Title: "B", Category: "Tech"},
+	}
+	if !reflect.DeepEqual(subscriptions, wantSubscriptions) {
+		t.Errorf("Subscriptions() = %+v, want %+v", subscriptions, wantSubscriptions)
+	}
+
+	entries, err := client.Entries(ctx, time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	wantEntries := []Entry{
+		{ID: "1", FeedURL: "https://example.com/a.xml", URL: "https://example.com/1", Read: true},
+		{ID: "2", FeedURL: "https://example.com/a.xml", URL: "https://example.com/2", Starred: true},
+	}
+	if !reflect.DeepEqual(entries, wantEntries) {
+		t.Errorf("Entries() = %+v, want %+v", entries, wantEntries)
+	}
+
+	if err := client.SetRead(ctx, []string{"1", "2"}, false); err != nil {
+		t.Fatalf("SetRead() error = %v", err)
+	}
+	if statusBody["status"] != "unread" || len(statusBody["entry_ids"].([]interface{})) != 2 {
+		t.Errorf("SetRead() sent %v", statusBody)
+	}
+	if err := client.SetStarred(ctx, []string{"2"}, false); err != nil {
+		t.Fatalf("SetStarred() error = %v", err)
+	}
+	if last := requests[len(requests)-1]; last != "PUT /v1/entries/2/bookmark" {
+		t.Errorf("SetStarred() sent %q", last)
+	}
+
+	_, err = NewMiniflux(server.URL, "wrong", server.Client()).Subscriptions(ctx)
+	if err == nil || !strings.Contains(err.Error(), "Access Unauthorized") {
+		t.Errorf("Subscriptions() with a wrong token error = %v", err)
+	}
+}
@@ -0,0 +1,477 @@
+// Package sync keeps the read and starred state of items in step with a
+// feed reader server, so NewsGoat can be used next to its web and mobile
+// clients.
+package sync
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/logging"
+	"github.com/jarv/newsgoat/internal/secrets"
+)
+
+// Sync services
+const (
+	ServiceMiniflux     = "miniflux"
+	ServiceGoogleReader = "greader"
+)
+
+// Services lists the sync services in the order they are offered
+var Services = []string{ServiceMiniflux, ServiceGoogleReader}
+
+// IsService reports whether a service can be synced with
+func IsService(service string) bool {
+	for _, s := range Services {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// cursorOverlap is subtracted from the start of a sync for the next one,
+	// changes made on the server while it ran are fetched again
+	cursorOverlap = 5 * time.Minute
+
+	// unmatchedRetention is how long an entry whose item was never fetched
+	// is kept to match it later
+	unmatchedRetention = 30 * 24 * time.Hour
+)
+
+// TokenSecret is the name the Sync Token setting is kept under in the OS
+// keychain
+const TokenSecret = "sync_token"
+
+// Config selects the sync service and how to sign in
+type Config struct {
+	Service string
+	URL     string
+	Token   string
+}
+
+// NewConfig takes the sync settings from the config, a token kept in the
+// keychain is read from there
+func NewConfig(cfg config.Config) Config {
+	token, err := secrets.Resolve(strings.TrimSpace(cfg.SyncToken))
+	if err != nil {
+		logging.Warn("Failed to read the sync token", "error", err)
+	}
+	return Config{
+		Service: strings.TrimSpace(cfg.Sync),
+		URL:     strings.TrimSuffix(strings.TrimSpace(cfg.SyncURL), "/"),
+		Token:   token,
+	}
+}
+
+// Enabled reports whether a sync service is set
+func (c Config) Enabled() bool {
+	return c.Service != ""
+}
+
+// RegisterSecrets keeps the token out of logs
+func (c Config) RegisterSecrets() {
+	if c.Token != "" {
+		logging.RegisterSecret(c.Token)
+	}
+	if c.Service == ServiceGoogleReader {
+		if _, password, ok := strings.Cut(c.Token, ":"); ok && password != "" {
+			logging.RegisterSecret(password)
+		}
+	}
+}
+
+// ServiceName is the service's name for status messages
+func (c Config) ServiceName() string {
+	switch c.Service {
+	case ServiceMiniflux:
+		return "Miniflux"
+	case ServiceGoogleReader:
+		return "Google Reader API"
+	}
+	return c.Service
+}
+
+// NewBackend returns the client of the configured service
+func NewBackend(cfg Config, client *http.Client) (Backend, error) {
+	switch cfg.Service {
+	case ServiceMiniflux:
+		if cfg.URL == "" || cfg.Token == "" {
+			return nil, fmt.Errorf("miniflux needs the Sync URL and Sync Token settings")
+		}
+		return NewMiniflux(cfg.URL, cfg.Token, client), nil
+	case ServiceGoogleReader:
+		username, password, ok := strings.Cut(cfg.Token, ":")
+		if cfg.URL == "" || !ok || username == "" || password == "" {
+			return nil, fmt.Errorf("greader needs the Sync URL setting and username:password as the Sync Token")
+		}
+		return NewGoogleReader(cfg.URL, username, password, client), nil
+	case "":
+		return nil, fmt.Errorf("no sync service set, choose one in settings (c)")
+	}
+	return nil, fmt.Errorf("unknown sync service %q", cfg.Service)
+}
+
+// Subscription is a feed the user follows on the service
+type Subscription struct {
+	FeedURL  string
+	Title    string
+	Category string // Empty when the feed isn't in a category
+}
+
+// Entry is an item as the service knows it
+type Entry struct {
+	ID      string
+	FeedURL string
+	URL     string // Link of the article, used to find the local item
+	Read    bool
+	Starred bool
+}
+
+// Backend is the API of a sync service
+type Backend interface {
+	// Name is the service stored with the entries
+	Name() string
+
+	// Subscriptions returns the feeds the user follows
+	Subscriptions(ctx context.Context) ([]Subscription, error)
+
+	// Entries returns the entries whose state changed since a time, or the
+	// recent and starred ones for the zero time
+	Entries(ctx context.Context, since time.Time) ([]Entry, error)
+
+	// SetRead marks entries as read or unread
+	SetRead(ctx context.Context, ids []string, read bool) error
+
+	// SetStarred stars or unstars entries, it is only called for entries
+	// whose starred state is the opposite
+	SetStarred(ctx context.Context, ids []string, starred bool) error
+}
+
+// StateLister is implemented by backends whose Entries only returns new
+// entries. The state of entries seen before comes from the IDs of the
+// unread and starred ones.
+type StateLister interface {
+	UnreadIDs(ctx context.Context) ([]string, error)
+	StarredIDs(ctx context.Context) ([]string, error)
+}
+
+// Store is where the local state is kept, implemented by *feeds.Manager
+type Store interface {
+	GetSyncEntries(ctx context.Context, service string) ([]database.GetSyncEntriesRow, error)
+	FindSyncItem(ctx context.Context, feedURL, link string) (database.FindSyncItemRow, bool, error)
+	SaveSyncEntry(ctx context.Context, entry database.UpsertSyncEntryParams) error
+	PruneSyncEntries(ctx context.Context, service string, before time.Time) (int64, error)
+	SyncCursor(ctx context.Context, service string) (time.Time, error)
+	SetSyncCursor(ctx context.Context, service string, cursor time.Time) error
+	MarkItemRead(itemID int64) error
+	MarkItemUnread(itemID int64) error
+	SetItemStarred(itemID int64, starred bool) error
+}
+
+// Result says what a sync changed
+type Result struct {
+	Pulled int // Local items changed to the state on the service
+	Pushed int // Entries changed on the service to the local state
+}
+
+// state is the read and starred state of an item or entry
+type state struct {
+	read, starred bool
+}
+
+// merge decides the state of an entry from its local and remote state and
+// the base both agreed on at the last sync. A side that changed since wins,
+// when both did they agree since the flags only have two values.
+func merge(local, base, remote state) state {
+	merged := remote
+	if local.read != base.read {
+		merged.read = local.read
+	}
+	if local.starred != base.starred {
+		merged.starred = local.starred
+	}
+	return merged
+}
+
+// union is the state of an entry seen for the first time, read or starred
+// on either side is kept
+func union(local, remote state) state {
+	return state{read: local.read || remote.read, starred: local.starred || remote.starred}
+}
+
+// syncer collects the changes of one sync
+type syncer struct {
+	store   Store
+	backend Backend
+	now     time.Time
+
+	result               Result
+	markRead, markUnread []string
+	star, unstar         []string
+	saves                []database.UpsertSyncEntryParams
+}
+
+// Run syncs the read and starred state of items with the service. Entries
+// are matched to local items by their link. Entries of feeds that aren't
+// fetched yet are kept and matched by a later sync.
+func Run(ctx context.Context, store Store, backend Backend) (Result, error) {
+	s := &syncer{store: store, backend: backend, now: time.Now()}
+	service := backend.Name()
+
+	cursor, err := store.SyncCursor(ctx, service)
+	if err != nil {
+		return Result{}, fmt.Errorf("reading the sync cursor failed: %w", err)
+	}
+	remote, err := backend.Entries(ctx, cursor)
+	if err != nil {
+		return Result{}, err
+	}
+	known, err := store.GetSyncEntries(ctx, service)
+	if err != nil {
+		return Result{}, fmt.Errorf("reading synced entries failed: %w", err)
+	}
+	byID := make(map[string]database.GetSyncEntriesRow, len(known))
+	for _, row := range known {
+		byID[row.RemoteID] = row
+	}
+	var states *remoteStates
+	if lister, ok := backend.(StateLister); ok && len(known) > 0 {
+		if states, err = listStates(ctx, lister); err != nil {
+			return Result{}, err
+		}
+	}
+
+	seen := make(map[string]bool, len(remote))
+	for _, entry := range remote {
+		seen[entry.ID] = true
+		remoteState := state{read: entry.Read, starred: entry.Starred}
+		if row, ok := byID[entry.ID]; ok && row.ItemID.Valid {
+			local := state{read: row.LocalRead, starred: row.LocalStarred}
+			base := state{read: row.Read, starred: row.Starred}
+			s.apply(entry, row.ItemID.Int64, local, &base, remoteState, merge(local, base, remoteState))
+			continue
+		}
+		found, err := s.match(ctx, entry, remoteState)
+		if err != nil {
+			return Result{}, err
+		}
+		if !found {
+			s.save(entry, sql.NullInt64{}, remoteState)
+		}
+	}
+
+	// Entries that didn't change on the service may have changed locally
+	for _, row := range known {
+		if seen[row.RemoteID] {
+			continue
+		}
+		entry := Entry{ID: row.RemoteID, FeedURL: row.FeedUrl, URL: row.Url, Read: row.Read, Starred: row.Starred}
+		base := state{read: row.Read, starred: row.Starred}
+		remoteState := base
+		if states != nil {
+			remoteState = states.of(row.RemoteID)
+		}
+		if !row.ItemID.Valid {
+			// Left alone when still not found, so it is pruned eventually
+			if _, err := s.match(ctx, entry, remoteState); err != nil {
+				return Result{}, err
+			}
+			continue
+		}
+		local := state{read: row.LocalRead, starred: row.LocalStarred}
+		if local != base || remoteState != base {
+			s.apply(entry, row.ItemID.Int64, local, &base, remoteState, merge(local, base, remoteState))
+		}
+	}
+
+	if err := s.push(ctx); err != nil {
+		return Result{}, err
+	}
+	for _, save := range s.saves {
+		if err := store.SaveSyncEntry(ctx, save); err != nil {
+			return Result{}, fmt.Errorf("saving synced entry failed: %w", err)
+		}
+	}
+	if pruned, err := store.PruneSyncEntries(ctx, service, s.now.Add(-unmatchedRetention)); err != nil {
+		logging.Warn("Pruning unmatched sync entries failed", "service", service, "error", err)
+	} else if pruned > 0 {
+		logging.DebugCategory(logging.CategoryDB, "Pruned unmatched sync entries", "service", service, "entries", pruned)
+	}
+	if err := store.SetSyncCursor(ctx, service, s.now.Add(-cursorOverlap)); err != nil {
+		return Result{}, fmt.Errorf("saving the sync cursor failed: %w", err)
+	}
+	return s.result, nil
+}
+
+// remoteStates holds the IDs of the unread and starred entries of a
+// StateLister
+type remoteStates struct {
+	unread, starred map[string]bool
+}
+
+func listStates(ctx context.Context, lister StateLister) (*remoteStates, error) {
+	unread, err := lister.UnreadIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing unread entries failed: %w", err)
+	}
+	starred, err := lister.StarredIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing starred entries failed: %w", err)
+	}
+	states := &remoteStates{
+		unread:  make(map[string]bool, len(unread)),
+		starred: make(map[string]bool, len(starred)),
+	}
+	for _, id := range unread {
+		states.unread[id] = true
+	}
+	for _, id := range starred {
+		states.starred[id] = true
+	}
+	return states, nil
+}
+
+func (r *remoteStates) of(id string) state {
+	return state{read: !r.unread[id], starred: r.starred[id]}
+}
+
+// match looks for the local item of an entry without one and merges their
+// state when it is found
+func (s *syncer) match(ctx context.Context, entry Entry, remote state) (bool, error) {
+	item, found, err := s.store.FindSyncItem(ctx, entry.FeedURL, entry.URL)
+	if err != nil {
+		return false, fmt.Errorf("finding the item of %s failed: %w", entry.URL, err)
+	}
+	if !found {
+		return false, nil
+	}
+	local := state{read: item.Read, starred: item.Starred}
+	s.apply(entry, item.ID, local, nil, remote, union(local, remote))
+	return true, nil
+}
+
+// apply changes the local item and queues the changes to the service that
+// bring both to the merged state. base is nil for an entry matched to its
+// item for the first time.
+func (s *syncer) apply(entry Entry, itemID int64, local state, base *state, remote, merged state) {
+	if merged != local {
+		if err := s.setLocal(itemID, local, merged); err != nil {
+			// Leave the base alone so the next sync tries again
+			logging.Warn("Updating a synced item failed", "item_id", itemID, "error", err)
+			return
+		}
+		s.result.Pulled++
+	}
+
+	if merged.read != remote.read {
+		if merged.read {
+			s.markRead = append(s.markRead, entry.ID)
+		} else {
+			s.markUnread = append(s.markUnread, entry.ID)
+		}
+	}
+	if merged.starred != remote.starred {
+		if merged.starred {
+			s.star = append(s.star, entry.ID)
+		} else {
+			s.unstar = append(s.unstar, entry.ID)
+		}
+	}
+	if merged != remote {
+		s.result.Pushed++
+	}
+
+	if base == nil || merged != *base {
+		s.save(entry, sql.NullInt64{Int64: itemID, Valid: true}, merged)
+	}
+}
+
+func (s *syncer) setLocal(itemID int64, local, merged state) error {
+	if merged.read != local.read {
+		var err error
+		if merged.read {
+			err = s.store.MarkItemRead(itemID)
+		} else {
+			err = s.store.MarkItemUnread(itemID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if merged.starred != local.starred {
+		return s.store.SetItemStarred(itemID, merged.starred)
+	}
+	return nil
+}
+
+func (s *syncer) save(entry Entry, itemID sql.NullInt64, agreed state) {
+	s.saves = append(s.saves, database.UpsertSyncEntryParams{
+		Service:   s.backend.Name(),
+		RemoteID:  entry.ID,
+		ItemID:    itemID,
+		FeedUrl:   entry.FeedURL,
+		Url:       entry.URL,
+		Read:      agreed.read,
+		Starred:   agreed.starred,
+		UpdatedAt: s.now,
+	})
+}
+
+// push sends the queued changes to the service
+func (s *syncer) push(ctx context.Context) error {
+	if len(s.markRead) > 0 {
+		if err := s.backend.SetRead(ctx, s.markRead, true); err != nil {
+			return fmt.Errorf("marking entries read failed: %w", err)
+		}
+	}
+	if len(s.markUnread) > 0 {
+		if err := s.backend.SetRead(ctx, s.markUnread, false); err != nil {
+			return fmt.Errorf("marking entries unread failed: %w", err)
+		}
+	}
+	if len(s.star) > 0 {
+		if err := s.backend.SetStarred(ctx, s.star, true); err != nil {
+			return fmt.Errorf("starring entries failed: %w", err)
+		}
+	}
+	if len(s.unstar) > 0 {
+		if err := s.backend.SetStarred(ctx, s.unstar, false); err != nil {
+			return fmt.Errorf("unstarring entries failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// MissingSubscriptions returns the subscriptions that aren't in the URLs file
+func MissingSubscriptions(subscriptions []Subscription, entries []config.URLEntry) []Subscription {
+	have := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		have[entry.URL] = true
+	}
+	var missing []Subscription
+	for _, sub := range subscriptions {
+		if sub.FeedURL != "" && !have[sub.FeedURL] {
+			have[sub.FeedURL] = true
+			missing = append(missing, sub)
+		}
+	}
+	return missing
+}
+
+// URLLine is the line of the URLs file for a subscription, its category
+// becomes the folder
+func URLLine(sub Subscription) string {
+	entry := config.URLEntry{URL: sub.FeedURL}
+	if sub.Category != "" {
+		entry.Folders = []string{sub.Category}
+	}
+	return config.FormatURLLine(entry)
+}
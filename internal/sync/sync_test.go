@@ -0,0 +1,299 @@
+package sync
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+// fakeItem is a local item of fakeStore
+type fakeItem struct {
+	link          string
+	read, starred bool
+}
+
+// fakeStore keeps items and sync entries in memory
+type fakeStore struct {
+	items   map[int64]*fakeItem
+	entries map[string]database.UpsertSyncEntryParams
+	cursor  time.Time
+}
+
+func (s *fakeStore) GetSyncEntries(ctx context.Context, service string) ([]database.GetSyncEntriesRow, error) {
+	var rows []database.GetSyncEntriesRow
+	for _, entry := range s.entries {
+		row := database.GetSyncEntriesRow{
+			RemoteID: entry.RemoteID,
+			FeedUrl:  entry.FeedUrl,
+			Url:      entry.Url,
+			Read:     entry.Read,
+			Starred:  entry.Starred,
+		}
+		if item, ok := s.items[entry.ItemID.Int64]; ok && entry.ItemID.Valid {
+			row.ItemID = entry.ItemID
+			row.LocalRead, row.LocalStarred = item.read, item.starred
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (s *fakeStore) FindSyncItem(ctx context.Context, feedURL, link string) (database.FindSyncItemRow, bool, error) {
+	for id, item := range s.items {
+		if item.link == link {
+			return database.FindSyncItemRow{ID: id, Read: item.read, Starred: item.starred}, true, nil
+		}
+	}
+	return database.FindSyncItemRow{}, false, nil
+}
+
+func (s *fakeStore) SaveSyncEntry(ctx context.Context, entry database.UpsertSyncEntryParams) error {
+	s.entries[entry.RemoteID] = entry
+	return nil
+}
+
+func (s *fakeStore) PruneSyncEntries(ctx context.Context, service string, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (s *fakeStore) SyncCursor(ctx context.Context, service string) (time.Time, error) {
+	return s.cursor, nil
+}
+
+func (s *fakeStore) SetSyncCursor(ctx context.Context, service string, cursor time.Time) error {
+	s.cursor = cursor
+	return nil
+}
+
+func (s *fakeStore) MarkItemRead(itemID int64) error {
+	s.items[itemID].read = true
+	return nil
+}
+
+func (s *fakeStore) MarkItemUnread(itemID int64) error {
+	s.items[itemID].read = false
+	return nil
+}
+
+func (s *fakeStore) SetItemStarred(itemID int64, starred bool) error {
+	s.items[itemID].starred = starred
+	return nil
+}
+
+// fakeBackend returns the entries changed since the last sync and records
+// what was pushed to it
+type fakeBackend struct {
+	entries map[string]*Entry
+	changed map[string]bool
+}
+
+func (b *fakeBackend) Name() string { return "fake" }
+
+func (b *fakeBackend) Subscriptions(ctx context.Context) ([]Subscription, error) {
+	return nil, nil
+}
+
+func (b *fakeBackend) Entries(ctx context.Context, since time.Time) ([]Entry, error) {
+	var entries []Entry
+	for id, entry := range b.entries {
+		if since.IsZero() || b.changed[id] {
+			entries = append(entries, *entry)
+		}
+	}
+	b.changed = map[string]bool{}
+	return entries, nil
+}
+
+func (b *fakeBackend) SetRead(ctx context.Context, ids []string, read bool) error {
+	for _, id := range ids {
+		b.entries[id].Read = read
+	}
+	return nil
+}
+
+func (b *fakeBackend) SetStarred(ctx context.Context, ids []string, starred bool) error {
+	for _, id := range ids {
+		b.entries[id].Starred = starred
+	}
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{
+		items: map[int64]*fakeItem{
+			1: {link: "https://example.com/1"},
+			2: {link: "https://example.com/2", read: true},
+			3: {link: "https://example.com/3", starred: true},
+		},
+		entries: map[string]database.UpsertSyncEntryParams{},
+	}
+	backend := &fakeBackend{
+		entries: map[string]*Entry{
+			"a": {ID: "a", URL: "https://example.com/1", Read: true},
+			"b": {ID: "b", URL: "https://example.com/2"},
+			"c": {ID: "c", URL: "https://example.com/3"},
+			"d": {ID: "d", URL: "https://example.com/not-fetched-yet", Starred: true},
+		},
+	}
+
+	// The first sync keeps read and starred from either side
+	result, err := Run(ctx, store, backend)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Pulled != 1 || result.Pushed != 2 {
+		t.Errorf("Run() = %+v, want 1 pulled and 2 pushed", result)
+	}
+	if !store.items[1].read || !backend.entries["b"].Read || !backend.entries["c"].Starred {
+		t.Fatalf("first sync didn't merge: items %+v %+v %+v, entries %+v %+v",
+			*store.items[1], *store.items[2], *store.items[3], *backend.entries["b"], *backend.entries["c"])
+	}
+	if store.entries["d"].ItemID.Valid {
+		t.Errorf("entry d was matched to an item that doesn't exist")
+	}
+	if store.cursor.IsZero() {
+		t.Errorf("Run() didn't save the cursor")
+	}
+
+	// Local changes are pushed, remote changes pulled
+	store.items[1].read = false
+	backend.entries["b"].Read = false
+	backend.changed["b"] = true
+	if _, err := Run(ctx, store, backend); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if backend.entries["a"].Read {
+		t.Errorf("marking item 1 unread wasn't pushed")
+	}
+	if store.items[2].read {
+		t.Errorf("marking entry b unread wasn't pulled")
+	}
+
+	// An entry seen before its item is fetched is matched later
+	store.items[4] = &fakeItem{link: "https://example.com/not-fetched-yet"}
+	if _, err := Run(ctx, store, backend); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !store.items[4].starred || !store.entries["d"].ItemID.Valid {
+		t.Errorf("entry d wasn't matched to item 4: %+v", store.entries["d"])
+	}
+}
+
+func TestMerge(t *testing.T) {
+	read := state{read: true}
+	unread := state{}
+	tests := []struct {
+		name                string
+		local, base, remote state
+		want                state
+	}{
+		{"unchanged", unread, unread, unread, unread},
+		{"changed locally", read, unread, unread, read},
+		{"changed remotely", unread, unread, read, read},
+		{"changed on both", read, unread, read, read},
+		{"starred locally, read remotely", state{starred: true}, unread, read, state{read: true, starred: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := merge(tt.local, tt.base, tt.remote); got != tt.want {
+				t.Errorf("merge() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMissingSubscriptions(t *testing.T) {
+	subscriptions := []Subscription{
+		{FeedURL: "https://example.com/a.xml"},
+		{FeedURL: "https://example.com/b.xml", Category: "Tech"},
+		{FeedURL: "https://example.com/b.xml", Category: "Tech"},
+	}
+	entries := []config.URLEntry{{URL: "https://example.com/a.xml"}}
+
+	missing := MissingSubscriptions(subscriptions, entries)
+	var lines []string
+	for _, sub := range missing {
+		lines = append(lines, URLLine(sub))
+	}
+	sort.Strings(lines)
+	if want := []string{"https://example.com/b.xml Tech"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("missing subscriptions = %q, want %q", lines, want)
+	}
+}
+
+// listerBackend only returns new entries after the first sync, like the
+// Google Reader API
+type listerBackend struct {
+	fakeBackend
+}
+
+func (b *listerBackend) Entries(ctx context.Context, since time.Time) ([]Entry, error) {
+	if !since.IsZero() {
+		return nil, nil
+	}
+	return b.fakeBackend.Entries(ctx, since)
+}
+
+func (b *listerBackend) UnreadIDs(ctx context.Context) ([]string, error) {
+	var ids []string
+	for id, entry := range b.entries {
+		if !entry.Read {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (b *listerBackend) StarredIDs(ctx context.Context) ([]string, error) {
+	var ids []string
+	for id, entry := range b.entries {
+		if entry.Starred {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func TestRunStateLister(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{
+		items: map[int64]*fakeItem{
+			1: {link: "https://example.com/1"},
+			2: {link: "https://example.com/2"},
+		},
+		entries: map[string]database.UpsertSyncEntryParams{},
+	}
+	backend := &listerBackend{fakeBackend{
+		entries: map[string]*Entry{
+			"a": {ID: "a", URL: "https://example.com/1"},
+			"b": {ID: "b", URL: "https://example.com/2"},
+		},
+	}}
+	if _, err := Run(ctx, store, backend); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Changes on the service come from the ID lists, local ones are pushed
+	backend.entries["a"].Read = true
+	backend.entries["a"].Starred = true
+	store.items[2].read = true
+	result, err := Run(ctx, store, backend)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Pulled != 1 || result.Pushed != 1 {
+		t.Errorf("Run() = %+v, want 1 pulled and 1 pushed", result)
+	}
+	if !store.items[1].read || !store.items[1].starred {
+		t.Errorf("entry a wasn't pulled: %+v", *store.items[1])
+	}
+	if !backend.entries["b"].Read {
+		t.Errorf("reading item 2 wasn't pushed")
+	}
+}
@@ -0,0 +1,52 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// CleanupHandler prunes old items of every feed with the feed manager's
+// retention policy
+type CleanupHandler struct {
+	feedManager *feeds.Manager
+}
+
+// NewCleanupHandler creates a new cleanup handler
+func NewCleanupHandler(feedManager *feeds.Manager) *CleanupHandler {
+	return &CleanupHandler{
+		feedManager: feedManager,
+	}
+}
+
+// Execute prunes the items of all feeds
+func (h *CleanupHandler) Execute(ctx context.Context, task *Task) error {
+	policy := h.feedManager.RetentionPolicy()
+	if !policy.Enabled() {
+		return fmt.Errorf("no retention limits set, set Max Items Per Feed or Max Item Age in settings (c)")
+	}
+
+	pruned, err := h.feedManager.PruneAllFeeds(feedProgress(ctx), policy)
+	if err != nil {
+		logging.Error("Pruning items failed", "error", err)
+		return fmt.Errorf("pruning items failed: %w", err)
+	}
+	logging.Info("Pruned old items", "items", pruned)
+
+	return nil
+}
+
+// CanHandle returns true if this handler can handle the given task type
+func (h *CleanupHandler) CanHandle(taskType TaskType) bool {
+	return taskType == TaskTypeCleanup
+}
+
+// CreateCleanupTask creates a task that prunes old items of every feed
+func CreateCleanupTask() *Task {
+	return &Task{
+		Type: TaskTypeCleanup,
+		Data: map[string]interface{}{},
+	}
+}
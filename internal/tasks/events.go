@@ -0,0 +1,80 @@
+package tasks
+
+import (
+	"sync"
+
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// subscriberBuffer is how many events a subscriber can fall behind before
+// its events are dropped
+const subscriberBuffer = 100
+
+// eventBus hands every task event to each subscriber on its own buffered
+// channel, so a slow subscriber only loses its own events
+type eventBus struct {
+	mutex       sync.Mutex
+	subscribers map[<-chan TaskEvent]chan TaskEvent
+	closed      bool
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{subscribers: make(map[<-chan TaskEvent]chan TaskEvent)}
+}
+
+// subscribe returns a channel receiving the events published from now on,
+// it is closed by unsubscribe or when the bus is closed
+func (b *eventBus) subscribe() <-chan TaskEvent {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	events := make(chan TaskEvent, subscriberBuffer)
+	if b.closed {
+		close(events)
+		return events
+	}
+	b.subscribers[events] = events
+	return events
+}
+
+// unsubscribe stops sending events to a subscriber and closes its channel
+func (b *eventBus) unsubscribe(events <-chan TaskEvent) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if subscriber, ok := b.subscribers[events]; ok {
+		delete(b.subscribers, events)
+		close(subscriber)
+	}
+}
+
+// publish sends an event to every subscriber without waiting, subscribers
+// whose buffer is full miss it
+func (b *eventBus) publish(event TaskEvent) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, subscriber := range b.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			logging.Warn("Task event subscriber is falling behind, dropping event", "type", event.Type, "taskID", event.TaskID)
+		}
+	}
+}
+
+// close closes the channels of all subscribers, later subscribers get a
+// closed channel
+func (b *eventBus) close() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for events, subscriber := range b.subscribers {
+		delete(b.subscribers, events)
+		close(subscriber)
+	}
+}
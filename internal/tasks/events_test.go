@@ -0,0 +1,98 @@
+package tasks
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEventBusFanOut(t *testing.T) {
+	bus := newEventBus()
+	first, second := bus.subscribe(), bus.subscribe()
+
+	bus.publish(TaskEvent{Type: TaskEventStarted, TaskID: "a"})
+	for _, events := range []<-chan TaskEvent{first, second} {
+		if event := <-events; event.TaskID != "a" {
+			t.Errorf("got event of task %q, want a", event.TaskID)
+		}
+	}
+
+	bus.unsubscribe(first)
+	if _, ok := <-first; ok {
+		t.Error("unsubscribed channel is still open")
+	}
+	bus.publish(TaskEvent{Type: TaskEventCompleted, TaskID: "b"})
+	if event := <-second; event.TaskID != "b" {
+		t.Errorf("got event of task %q, want b", event.TaskID)
+	}
+	// Unsubscribing twice is a no-op
+	bus.unsubscribe(first)
+}
+
+func TestEventBusSlowSubscriber(t *testing.T) {
+	bus := newEventBus()
+	slow, fast := bus.subscribe(), bus.subscribe()
+
+	// The fast subscriber keeps up while the slow one doesn't read at all
+	done := make(chan int)
+	go func() {
+		received := 0
+		for range fast {
+			received++
+		}
+		done <- received
+	}()
+
+	published := subscriberBuffer * 3
+	for i := range published {
+		bus.publish(TaskEvent{Type: TaskEventProgress, TaskID: "a", Progress: &TaskProgress{Done: int64(i)}})
+		// Give the fast subscriber time to keep up
+		if i%subscriberBuffer == 0 {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	bus.close()
+
+	if received := <-done; received != published {
+		t.Errorf("fast subscriber received %d events, want %d", received, published)
+	}
+
+	// The slow one got the events that fit its buffer and missed the rest
+	var received []int64
+	for event := range slow {
+		received = append(received, event.Progress.Done)
+	}
+	if len(received) != subscriberBuffer {
+		t.Fatalf("slow subscriber received %d events, want %d", len(received), subscriberBuffer)
+	}
+	if received[0] != 0 || received[len(received)-1] != subscriberBuffer-1 {
+		t.Errorf("slow subscriber received events %d to %d, want the first %d", received[0], received[len(received)-1], subscriberBuffer)
+	}
+}
+
+func TestSubscribersOfManager(t *testing.T) {
+	manager := startManager(t, nil, func(ctx context.Context, task *Task) error {
+		return nil
+	})
+	first, second := manager.Subscribe(), manager.Subscribe()
+
+	task := CreateFeedRefreshTask(1, "https://example.com/feed")
+	if err := manager.AddTask(task); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	for _, events := range []<-chan TaskEvent{first, second} {
+		if event := nextEvent(t, events); event.Type != TaskEventCompleted || event.TaskID != task.ID {
+			t.Errorf("got %s of task %s, want task %s to complete", event.Type, event.TaskID, task.ID)
+		}
+	}
+
+	manager.Unsubscribe(first)
+	if err := manager.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	for range second {
+	}
+	if _, ok := <-manager.Subscribe(); ok {
+		t.Error("subscribing to a stopped manager returned an open channel")
+	}
+}
@@ -0,0 +1,59 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// FeedIconHandler looks up the favicon of a feed's site
+type FeedIconHandler struct {
+	feedManager *feeds.Manager
+}
+
+// NewFeedIconHandler creates a new feed icon handler
+func NewFeedIconHandler(feedManager *feeds.Manager) *FeedIconHandler {
+	return &FeedIconHandler{
+		feedManager: feedManager,
+	}
+}
+
+// Execute looks up and saves the icon of the task's feed, a site without
+// one isn't a failure
+func (h *FeedIconHandler) Execute(ctx context.Context, task *Task) error {
+	feedID, err := TaskFeedID(task)
+	if err != nil {
+		return err
+	}
+
+	err = h.feedManager.FetchFeedIcon(ctx, feedID)
+	if errors.Is(err, feeds.ErrNoIcon) {
+		logging.DebugCategory(logging.CategoryTasks, "Feed has no icon", "feedID", feedID)
+		return nil
+	}
+	if err != nil {
+		logging.Warn("Feed icon lookup failed", "feedID", feedID, "error", err)
+		return fmt.Errorf("feed icon lookup failed: %w", err)
+	}
+	return nil
+}
+
+// CanHandle returns true if this handler can handle the given task type
+func (h *FeedIconHandler) CanHandle(taskType TaskType) bool {
+	return taskType == TaskTypeFeedIcon
+}
+
+// CreateFeedIconTask creates a low priority task that looks up the icon of a feed
+func CreateFeedIconTask(feedID int64, url string) *Task {
+	return &Task{
+		Type:     TaskTypeFeedIcon,
+		Priority: TaskPriorityLow,
+		Data: map[string]interface{}{
+			"feed_id": feedID,
+			"url":     logging.Redact(url),
+		},
+	}
+}
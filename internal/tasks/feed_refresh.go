@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"sync/atomic"
 
 	"github.com/jarv/newsgoat/internal/feeds"
 	"github.com/jarv/newsgoat/internal/logging"
@@ -13,52 +14,103 @@ import (
 type FeedRefreshTaskData struct {
 	FeedID int64  `json:"feed_id"`
 	URL    string `json:"url"`
+	Pushed bool   `json:"pushed"` // A WebSub hub pushed an update, the feed's cache period is ignored
 }
 
 // FeedRefreshHandler handles feed refresh tasks
 type FeedRefreshHandler struct {
 	feedManager *feeds.Manager
+	taskManager Manager
+	running     atomic.Int64 // Refreshes being executed by the workers
 }
 
-// NewFeedRefreshHandler creates a new feed refresh handler
-func NewFeedRefreshHandler(feedManager *feeds.Manager) *FeedRefreshHandler {
+// NewFeedRefreshHandler creates a new feed refresh handler, taskManager is
+// used to queue follow-up tasks and may be nil
+func NewFeedRefreshHandler(feedManager *feeds.Manager, taskManager Manager) *FeedRefreshHandler {
 	return &FeedRefreshHandler{
 		feedManager: feedManager,
+		taskManager: taskManager,
 	}
 }
 
 // Execute executes a feed refresh task
 func (h *FeedRefreshHandler) Execute(ctx context.Context, task *Task) error {
 	// Parse task data
+	feedID, err := TaskFeedID(task)
+	if err != nil {
+		return err
+	}
+
+	h.running.Add(1)
+	defer h.refreshDone()
+
+	// Perform the feed refresh, stopping the task manager aborts it
+	pushed, _ := task.Data["pushed"].(bool)
+	err = h.feedManager.RefreshFeedContext(feedProgress(ctx), feedID, pushed)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		logging.Error("Feed refresh failed", "feedID", feedID, "error", err)
+		return fmt.Errorf("feed refresh failed: %w", err)
+	}
+
+	// Look up pull requests and CI state of new commits in the background
+	if h.taskManager != nil && h.feedManager.FeedEnrichEnabled(feedID) {
+		url, _ := task.Data["url"].(string)
+		if err := h.taskManager.AddTask(CreateItemEnrichmentTask(feedID, url)); err != nil {
+			logging.Warn("Failed to queue item enrichment", "feedID", feedID, "error", err)
+		}
+	}
+
+	// Look up the favicon of the feed's site the first time it is refreshed
+	if h.taskManager != nil && h.feedManager.NeedsFeedIcon(feedID) {
+		url, _ := task.Data["url"].(string)
+		if err := h.taskManager.AddTask(CreateFeedIconTask(feedID, url)); err != nil {
+			logging.Warn("Failed to queue feed icon lookup", "feedID", feedID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// refreshDone sends the notification about new items once the last of the
+// refreshes running together is done, so refreshing every feed notifies once
+func (h *FeedRefreshHandler) refreshDone() {
+	if h.running.Add(-1) > 0 {
+		return
+	}
+	if h.taskManager != nil {
+		taskType, status := TaskTypeFeedRefresh, TaskStatusPending
+		pending, err := h.taskManager.ListTasks(TaskFilter{Type: &taskType, Status: &status, Limit: 1})
+		if err == nil && len(pending) > 0 {
+			return
+		}
+	}
+	h.feedManager.FlushNotifications()
+}
+
+// TaskFeedID reads the feed_id from the task data
+func TaskFeedID(task *Task) (int64, error) {
 	feedIDValue, ok := task.Data["feed_id"]
 	if !ok {
-		return fmt.Errorf("missing feed_id in task data")
+		return 0, fmt.Errorf("missing feed_id in task data")
 	}
 
-	var feedID int64
 	switch v := feedIDValue.(type) {
 	case int64:
-		feedID = v
+		return v, nil
 	case float64:
-		feedID = int64(v)
+		return int64(v), nil
 	case string:
-		var err error
-		feedID, err = strconv.ParseInt(v, 10, 64)
+		feedID, err := strconv.ParseInt(v, 10, 64)
 		if err != nil {
-			return fmt.Errorf("invalid feed_id format: %v", v)
+			return 0, fmt.Errorf("invalid feed_id format: %v", v)
 		}
+		return feedID, nil
 	default:
-		return fmt.Errorf("invalid feed_id type: %T", v)
+		return 0, fmt.Errorf("invalid feed_id type: %T", v)
 	}
-
-	// Perform the feed refresh
-	err := h.feedManager.RefreshFeed(feedID)
-	if err != nil {
-		logging.Error("Feed refresh failed", "feedID", feedID, "error", err)
-		return fmt.Errorf("feed refresh failed: %w", err)
-	}
-
-	return nil
 }
 
 // CanHandle returns true if this handler can handle the given task type
@@ -72,8 +124,15 @@ func CreateFeedRefreshTask(feedID int64, url string) *Task {
 		Type: TaskTypeFeedRefresh,
 		Data: map[string]interface{}{
 			"feed_id": feedID,
-			"url":     url,
+			"url":     logging.Redact(url),
 		},
 	}
 }
 
+// CreatePushedFeedRefreshTask creates a feed refresh task for an update a
+// WebSub hub pushed
+func CreatePushedFeedRefreshTask(feedID int64, url string) *Task {
+	task := CreateFeedRefreshTask(feedID, url)
+	task.Data["pushed"] = true
+	return task
+}
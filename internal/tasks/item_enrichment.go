@@ -0,0 +1,55 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// ItemEnrichmentHandler looks up the pull request and CI state of commit feed items
+type ItemEnrichmentHandler struct {
+	feedManager *feeds.Manager
+}
+
+// NewItemEnrichmentHandler creates a new item enrichment handler
+func NewItemEnrichmentHandler(feedManager *feeds.Manager) *ItemEnrichmentHandler {
+	return &ItemEnrichmentHandler{
+		feedManager: feedManager,
+	}
+}
+
+// Execute enriches the items of the task's feed
+func (h *ItemEnrichmentHandler) Execute(ctx context.Context, task *Task) error {
+	feedID, err := TaskFeedID(task)
+	if err != nil {
+		return err
+	}
+
+	enriched, err := h.feedManager.EnrichFeedItems(ctx, feedID)
+	if err != nil {
+		logging.Error("Item enrichment failed", "feedID", feedID, "error", err)
+		return fmt.Errorf("item enrichment failed: %w", err)
+	}
+	logging.DebugCategory(logging.CategoryTasks, "Items enriched", "feedID", feedID, "items", enriched)
+
+	return nil
+}
+
+// CanHandle returns true if this handler can handle the given task type
+func (h *ItemEnrichmentHandler) CanHandle(taskType TaskType) bool {
+	return taskType == TaskTypeItemEnrichment
+}
+
+// CreateItemEnrichmentTask creates a low priority task that enriches the items of a feed
+func CreateItemEnrichmentTask(feedID int64, url string) *Task {
+	return &Task{
+		Type:     TaskTypeItemEnrichment,
+		Priority: TaskPriorityLow,
+		Data: map[string]interface{}{
+			"feed_id": feedID,
+			"url":     logging.Redact(url),
+		},
+	}
+}
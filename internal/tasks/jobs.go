@@ -0,0 +1,258 @@
+package tasks
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/logging"
+	feedsync "github.com/jarv/newsgoat/internal/sync"
+)
+
+// Names of the recurring jobs
+const (
+	RefreshJobName     = "feed_refresh"
+	LogCleanupJobName  = "log_cleanup"
+	PruneJobName       = "prune"
+	UpdateCheckJobName = "update_check"
+	SyncJobName        = "sync"
+)
+
+// refreshCheckInterval is how often the refresh job looks for feeds that are
+// due
+const refreshCheckInterval = time.Minute
+
+// logCleanupInterval is how often log messages beyond the retention limits
+// are deleted
+const logCleanupInterval = time.Hour
+
+// syncJobInterval is how often the sync job syncs with the sync service
+const syncJobInterval = 15 * time.Minute
+
+// RefreshJob refreshes the feeds whose reload interval elapsed, every feed
+// on its first run with Reload On Startup, and updates the reading log on
+// the same schedule
+type RefreshJob struct {
+	feedManager *feeds.Manager
+	taskManager Manager
+	settings    func() config.Config
+
+	mutex       sync.Mutex
+	schedule    *feeds.RefreshSchedule
+	firstRun    bool
+	firstReload bool // Suppress First Reload skips it
+	next        time.Time
+}
+
+// NewRefreshJob creates the job that reloads feeds automatically, settings
+// are read on every run so changes apply right away
+func NewRefreshJob(feedManager *feeds.Manager, taskManager Manager, settings func() config.Config) *RefreshJob {
+	return &RefreshJob{
+		feedManager: feedManager,
+		taskManager: taskManager,
+		settings:    settings,
+		schedule:    feeds.NewRefreshSchedule(time.Now()),
+		firstRun:    true,
+		firstReload: true,
+	}
+}
+
+// Job returns the job to schedule, it looks for due feeds every minute
+func (j *RefreshJob) Job() Job {
+	return Job{Name: RefreshJobName, Schedule: Every(refreshCheckInterval), Tasks: j.tasks}
+}
+
+// NextRefresh returns when the next feed is due, zero when auto reload is off
+func (j *RefreshJob) NextRefresh() time.Time {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.next
+}
+
+// FeedNextRefresh returns when the auto reload refreshes a feed next and how
+// often it does, the zero time when auto reload is off
+func (j *RefreshJob) FeedNextRefresh(feedID int64) (time.Time, time.Duration) {
+	cfg := j.settings()
+	if !cfg.AutoReload || cfg.ReloadTime <= 0 {
+		return time.Time{}, 0
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.schedule.NextRefresh(feedID, time.Duration(cfg.ReloadTime)*time.Minute)
+}
+
+// Refreshing reports whether refreshes are still queued or running, the
+// feeds that are due wait for them to finish
+func (j *RefreshJob) Refreshing() bool {
+	return hasTasks(j.taskManager, TaskTypeFeedRefresh)
+}
+
+// hasTasks reports whether tasks of the type are queued or running
+func hasTasks(manager Manager, taskType TaskType) bool {
+	for _, status := range []TaskStatus{TaskStatusPending, TaskStatusRunning} {
+		tasks, err := manager.ListTasks(TaskFilter{Type: &taskType, Status: &status, Limit: 1})
+		if err == nil && len(tasks) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// tasks returns the refreshes of the feeds that are due
+func (j *RefreshJob) tasks(now time.Time) []*Task {
+	cfg := j.settings()
+	feedStats, err := j.feedManager.GetFeedStats()
+	if err != nil {
+		logging.Error("Failed to get feeds to reload", "error", err)
+		return nil
+	}
+	intervals, err := j.feedManager.GetFeedReloadIntervals()
+	if err != nil {
+		logging.Warn("Failed to get feed reload intervals", "error", err)
+	}
+
+	feedIDs := make([]int64, 0, len(feedStats))
+	feedURLs := make(map[int64]string, len(feedStats))
+	for _, feed := range feedStats {
+		if feed.Paused {
+			continue
+		}
+		feedIDs = append(feedIDs, feed.ID)
+		feedURLs[feed.ID] = feed.Url
+	}
+	defaultInterval := time.Duration(cfg.ReloadTime) * time.Minute
+	autoReload := cfg.AutoReload && cfg.ReloadTime > 0
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if intervals != nil {
+		j.schedule.SetIntervals(intervals)
+	}
+
+	// The startup reload refreshes every feed that isn't fresh, later runs
+	// only the feeds whose interval elapsed
+	var due []int64
+	if j.firstRun && cfg.ReloadOnStartup {
+		due = PlanRefreshAll(j.feedManager, feedIDs, now).FeedIDs
+		j.schedule.Skip(feedIDs, now)
+	} else if autoReload && !j.Refreshing() {
+		due = j.schedule.Due(feedIDs, defaultInterval, now)
+	}
+	j.firstRun = false
+
+	j.next = time.Time{}
+	if autoReload {
+		j.next = now.Add(j.schedule.Next(feedIDs, defaultInterval, now))
+	}
+	if len(due) == 0 {
+		return nil
+	}
+
+	var queued []*Task
+	if j.firstReload && cfg.SuppressFirstReload {
+		logging.Info("Suppressed the first reload")
+	} else {
+		for _, feedID := range due {
+			queued = append(queued, CreateFeedRefreshTask(feedID, feedURLs[feedID]))
+		}
+	}
+	j.firstReload = false
+
+	// Update the shared reading log on the same schedule
+	if cfg.ReadingExport != "" && (cfg.ReadingExportPath != "" || cfg.ReadingExportGist != "") {
+		queued = append(queued, CreateReadingExportTask(cfg.ReadingExport, cfg.ReadingExportPath, cfg.ReadingExportGist))
+	}
+	return queued
+}
+
+// PlanRefreshAll orders the feeds a refresh of every feed fetches with the
+// feed manager's PlanRefreshAll, all of them in their order when that fails
+func PlanRefreshAll(feedManager *feeds.Manager, feedIDs []int64, now time.Time) feeds.RefreshPlan {
+	plan, err := feedManager.PlanRefreshAll(feedIDs, now)
+	if err != nil {
+		logging.Warn("Failed to plan refresh, refreshing every feed", "error", err)
+		return feeds.RefreshPlan{FeedIDs: feedIDs}
+	}
+	if plan.Fresh > 0 {
+		logging.Info("Skipped fresh feeds", "count", plan.Fresh)
+	}
+	return plan
+}
+
+// LogCleanupJob returns the job that deletes old log messages every hour
+func LogCleanupJob(settings func() config.Config) Job {
+	return Job{
+		Name:     LogCleanupJobName,
+		Schedule: Every(logCleanupInterval),
+		Tasks: func(time.Time) []*Task {
+			cfg := settings()
+			if cfg.LogMaxMessages <= 0 && cfg.LogMaxAgeDays <= 0 {
+				return nil
+			}
+			return []*Task{CreateLogCleanupTask(cfg.LogMaxMessages, cfg.LogMaxAgeDays)}
+		},
+	}
+}
+
+// SyncJob returns the job that syncs with the sync service every 15 minutes,
+// when it is set up and no sync is running
+func SyncJob(manager Manager, settings func() config.Config) Job {
+	return Job{
+		Name:     SyncJobName,
+		Schedule: Every(syncJobInterval),
+		Tasks: func(time.Time) []*Task {
+			if !feedsync.NewConfig(settings()).Enabled() || hasTasks(manager, TaskTypeSync) {
+				return nil
+			}
+			return []*Task{CreateSyncTask()}
+		},
+	}
+}
+
+// SchedulePrune prunes old items on the schedule spec, when retention limits
+// are set. An empty spec stops it.
+func SchedulePrune(manager Manager, spec string, settings func() config.Config) error {
+	if spec == "" {
+		manager.UnscheduleJob(PruneJobName)
+		return nil
+	}
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return err
+	}
+	manager.ScheduleJob(Job{
+		Name:     PruneJobName,
+		Schedule: schedule,
+		Tasks: func(time.Time) []*Task {
+			if !settings().RetentionPolicy().Enabled() {
+				return nil
+			}
+			task := CreateCleanupTask()
+			task.Priority = TaskPriorityLow
+			return []*Task{task}
+		},
+	})
+	return nil
+}
+
+// ScheduleUpdateCheck checks for a newer release on the schedule spec, an
+// empty spec stops it
+func ScheduleUpdateCheck(manager Manager, spec string) error {
+	if spec == "" {
+		manager.UnscheduleJob(UpdateCheckJobName)
+		return nil
+	}
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return err
+	}
+	manager.ScheduleJob(Job{
+		Name:     UpdateCheckJobName,
+		Schedule: schedule,
+		Tasks: func(time.Time) []*Task {
+			return []*Task{CreateUpdateCheckTask()}
+		},
+	})
+	return nil
+}
@@ -0,0 +1,72 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// LogCleanupHandler deletes old log messages so the log doesn't grow without bound
+type LogCleanupHandler struct {
+	feedManager *feeds.Manager
+}
+
+// NewLogCleanupHandler creates a new log cleanup handler
+func NewLogCleanupHandler(feedManager *feeds.Manager) *LogCleanupHandler {
+	return &LogCleanupHandler{
+		feedManager: feedManager,
+	}
+}
+
+// Execute deletes the log messages beyond the limits in the task data
+func (h *LogCleanupHandler) Execute(ctx context.Context, task *Task) error {
+	maxMessages := taskInt(task, "max_messages")
+	maxAgeDays := taskInt(task, "max_age_days")
+	if maxMessages == 0 && maxAgeDays == 0 {
+		return nil
+	}
+
+	pruned, err := h.feedManager.PruneLogMessages(ctx, maxMessages, maxAgeDays)
+	if err != nil {
+		return fmt.Errorf("pruning log messages failed: %w", err)
+	}
+	if pruned > 0 {
+		logging.DebugCategory(logging.CategoryDB, "Pruned log messages", "messages", pruned)
+	}
+
+	return nil
+}
+
+// taskInt reads a number from the task data, 0 when it is missing
+func taskInt(task *Task, key string) int {
+	switch v := task.Data[key].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	default:
+		return 0
+	}
+}
+
+// CanHandle returns true if this handler can handle the given task type
+func (h *LogCleanupHandler) CanHandle(taskType TaskType) bool {
+	return taskType == TaskTypeLogCleanup
+}
+
+// CreateLogCleanupTask creates a low priority task that deletes the log
+// messages beyond maxMessages or older than maxAgeDays
+func CreateLogCleanupTask(maxMessages, maxAgeDays int) *Task {
+	return &Task{
+		Type:     TaskTypeLogCleanup,
+		Priority: TaskPriorityLow,
+		Data: map[string]interface{}{
+			"max_messages": maxMessages,
+			"max_age_days": maxAgeDays,
+		},
+	}
+}
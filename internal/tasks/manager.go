@@ -7,22 +7,34 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jarv/newsgoat/internal/config"
 	"github.com/jarv/newsgoat/internal/logging"
 )
 
+// queueFullDelay is how long a task that found the queue full waits before
+// it is queued again
+const queueFullDelay = 5 * time.Second
+
 // DefaultManager implements the Manager interface
 type DefaultManager struct {
-	maxWorkers int
-	tasks      map[string]*Task
-	taskQueue  chan *Task
-	handlers   map[TaskType]TaskHandler
-	events     chan TaskEvent
-	workers    []*worker
-	mutex      sync.RWMutex
-	ctx        context.Context
-	cancel     context.CancelFunc
-	wg         sync.WaitGroup
-	running    bool
+	maxWorkers    int
+	tasks         map[string]*Task
+	taskQueue     chan *Task
+	lowQueue      chan *Task
+	handlers      map[TaskType]TaskHandler
+	events        *eventBus
+	workers       []*worker
+	mutex         sync.RWMutex
+	ctx           context.Context
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+	running       bool
+	stopped       bool  // The queues are closed
+	store         Store // nil when tasks only live in memory
+	retryPolicies map[TaskType]config.RetryPolicy
+	retryTimers   map[string]*time.Timer // Tasks waiting to run again by ID
+	jobs          map[string]*scheduledJob
+	jobWake       chan struct{}
 }
 
 // worker represents a worker that executes tasks
@@ -32,14 +44,26 @@ type worker struct {
 	ctx     context.Context
 }
 
-// NewManager creates a new task manager
+// NewManager creates a new task manager keeping its tasks in memory
 func NewManager(maxWorkers int) Manager {
+	return NewManagerWithStore(maxWorkers, nil)
+}
+
+// NewManagerWithStore creates a new task manager keeping the tasks that
+// didn't finish in store, RestoreTasks queues them again after a restart
+func NewManagerWithStore(maxWorkers int, store Store) Manager {
 	return &DefaultManager{
-		maxWorkers: maxWorkers,
-		tasks:      make(map[string]*Task),
-		taskQueue:  make(chan *Task, 100), // Buffered channel for task queue
-		handlers:   make(map[TaskType]TaskHandler),
-		events:     make(chan TaskEvent, 100), // Buffered channel for events
+		maxWorkers:    maxWorkers,
+		tasks:         make(map[string]*Task),
+		taskQueue:     make(chan *Task, 100), // Buffered channel for task queue
+		lowQueue:      make(chan *Task, 100), // Buffered channel for low priority tasks
+		handlers:      make(map[TaskType]TaskHandler),
+		events:        newEventBus(),
+		store:         store,
+		retryPolicies: make(map[TaskType]config.RetryPolicy),
+		retryTimers:   make(map[string]*time.Timer),
+		jobs:          make(map[string]*scheduledJob),
+		jobWake:       make(chan struct{}, 1),
 	}
 }
 
@@ -68,6 +92,10 @@ func (m *DefaultManager) Start(ctx context.Context) error {
 		go worker.start()
 	}
 
+	// Start the scheduler of recurring jobs
+	m.wg.Add(1)
+	go m.runScheduler(m.ctx)
+
 	return nil
 }
 
@@ -81,13 +109,19 @@ func (m *DefaultManager) Stop() error {
 	}
 
 	m.cancel()
+	for id, timer := range m.retryTimers {
+		timer.Stop()
+		delete(m.retryTimers, id)
+	}
 	close(m.taskQueue)
+	close(m.lowQueue)
+	m.stopped = true
 
 	// Don't wait for workers to finish - they will complete in the background
 	// This allows for immediate shutdown when the user quits
 	go func() {
 		m.wg.Wait()
-		close(m.events)
+		m.events.close()
 	}()
 
 	m.running = false
@@ -107,16 +141,140 @@ func (m *DefaultManager) AddTask(task *Task) error {
 
 	m.mutex.Lock()
 	m.tasks[task.ID] = task
+	stored := *task
 	m.mutex.Unlock()
 
+	m.saveTask(stored)
+
+	// The lock keeps Stop from closing the queue meanwhile
+	m.mutex.RLock()
+	err := m.enqueue(task)
+	m.mutex.RUnlock()
+	if err != nil {
+		m.deleteStoredTask(task.ID)
+		return err
+	}
+	return nil
+}
+
+// enqueue puts a task in the queue of its priority, the caller holds the lock
+func (m *DefaultManager) enqueue(task *Task) error {
+	if m.stopped {
+		return fmt.Errorf("task manager is stopped")
+	}
+	queue := m.taskQueue
+	if task.Priority == TaskPriorityLow {
+		queue = m.lowQueue
+	}
+
 	select {
-	case m.taskQueue <- task:
+	case queue <- task:
 		return nil
 	default:
 		return fmt.Errorf("task queue is full")
 	}
 }
 
+// SetRetryPolicy sets how often failed tasks of a type run again, tasks of
+// types without a policy fail on their first error
+func (m *DefaultManager) SetRetryPolicy(taskType TaskType, policy config.RetryPolicy) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.retryPolicies[taskType] = policy
+}
+
+// RestoreTasks queues the stored tasks that didn't finish before the last
+// stop. Tasks that were waiting or running start over, retrying tasks wait
+// for their retry time.
+func (m *DefaultManager) RestoreTasks() error {
+	if m.store == nil {
+		return nil
+	}
+	stored, err := m.store.LoadTasks()
+	if err != nil {
+		return fmt.Errorf("failed to load stored tasks: %w", err)
+	}
+
+	for _, task := range stored {
+		m.mutex.Lock()
+		if _, exists := m.tasks[task.ID]; exists {
+			m.mutex.Unlock()
+			continue
+		}
+		m.tasks[task.ID] = task
+		delay := time.Duration(0)
+		if task.Status == TaskStatusRetrying && task.NextRetry != nil {
+			delay = time.Until(*task.NextRetry)
+		}
+		task.Status = TaskStatusRetrying
+		m.mutex.Unlock()
+
+		// Queueing them through the retry timers spreads more tasks than
+		// the queue holds over time
+		m.scheduleRetry(task, delay)
+	}
+
+	logging.DebugCategory(logging.CategoryTasks, "Restored stored tasks", "count", len(stored))
+	return nil
+}
+
+// scheduleRetry queues a retrying task again after delay
+func (m *DefaultManager) scheduleRetry(task *Task, delay time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.running {
+		return
+	}
+	m.retryTimers[task.ID] = time.AfterFunc(max(delay, 0), func() {
+		m.retry(task)
+	})
+}
+
+// retry queues a task whose retry time came, unless it was removed meanwhile
+func (m *DefaultManager) retry(task *Task) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	delete(m.retryTimers, task.ID)
+	if !m.running || m.tasks[task.ID] != task || task.Status != TaskStatusRetrying {
+		return
+	}
+
+	// The lock keeps Stop from closing the queue meanwhile
+	task.Status = TaskStatusPending
+	if err := m.enqueue(task); err != nil {
+		task.Status = TaskStatusRetrying
+		next := time.Now().Add(queueFullDelay)
+		task.NextRetry = &next
+		m.retryTimers[task.ID] = time.AfterFunc(queueFullDelay, func() {
+			m.retry(task)
+		})
+		return
+	}
+	task.NextRetry = nil
+}
+
+// saveTask stores a copy of a task, taken under the lock
+func (m *DefaultManager) saveTask(task Task) {
+	if m.store == nil {
+		return
+	}
+	if err := m.store.SaveTask(&task); err != nil {
+		logging.Warn("Failed to store task", "taskID", task.ID, "error", err)
+	}
+}
+
+// deleteStoredTask forgets a task that finished or was removed
+func (m *DefaultManager) deleteStoredTask(id string) {
+	if m.store == nil {
+		return
+	}
+	if err := m.store.DeleteTask(id); err != nil {
+		logging.Warn("Failed to delete stored task", "taskID", id, "error", err)
+	}
+}
+
 // GetTask retrieves a task by ID
 func (m *DefaultManager) GetTask(id string) (*Task, error) {
 	m.mutex.RLock()
@@ -156,16 +314,20 @@ func (m *DefaultManager) ListTasks(filter TaskFilter) ([]*Task, error) {
 	return tasks, nil
 }
 
-// Subscribe returns a channel for task events
+// Subscribe returns a channel receiving every task event from now on, it is
+// closed by Unsubscribe or once the manager stopped
 func (m *DefaultManager) Subscribe() <-chan TaskEvent {
-	return m.events
+	return m.events.subscribe()
+}
+
+// Unsubscribe stops sending events to a channel returned by Subscribe and
+// closes it
+func (m *DefaultManager) Unsubscribe(events <-chan TaskEvent) {
+	m.events.unsubscribe(events)
 }
 
 // RegisterHandler registers a task handler
 func (m *DefaultManager) RegisterHandler(handler TaskHandler) error {
-	// Find all task types this handler can handle
-	taskTypes := []TaskType{TaskTypeFeedRefresh} // Add more as needed
-
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
@@ -181,14 +343,9 @@ func (m *DefaultManager) RegisterHandler(handler TaskHandler) error {
 	return nil
 }
 
-// publishEvent publishes a task event
+// publishEvent publishes a task event to every subscriber
 func (m *DefaultManager) publishEvent(event TaskEvent) {
-	select {
-	case m.events <- event:
-	default:
-		// Event channel is full, drop the event
-		logging.Warn("Event channel full, dropping event", "type", event.Type, "taskID", event.TaskID)
-	}
+	m.events.publish(event)
 }
 
 // RemoveTask removes a task from the manager
@@ -207,7 +364,12 @@ func (m *DefaultManager) RemoveTask(id string) error {
 	}
 
 	delete(m.tasks, id)
-	logging.Debug("Task removed", "taskID", id)
+	if timer, ok := m.retryTimers[id]; ok {
+		timer.Stop()
+		delete(m.retryTimers, id)
+	}
+	m.deleteStoredTask(id)
+	logging.DebugCategory(logging.CategoryTasks, "Task removed", "taskID", id)
 	return nil
 }
 
@@ -233,7 +395,15 @@ func (m *DefaultManager) ClearFailedTasks() error {
 func (w *worker) start() {
 	defer w.manager.wg.Done()
 
+	// Only the first worker takes low priority tasks so they never hold up
+	// more than one worker
+	var lowQueue chan *Task
+	if w.id == 0 {
+		lowQueue = w.manager.lowQueue
+	}
+
 	for {
+		// Drain normal priority tasks before looking at the low priority queue
 		select {
 		case <-w.ctx.Done():
 			return
@@ -243,18 +413,47 @@ func (w *worker) start() {
 				return
 			}
 			w.executeTask(task)
+			continue
+		default:
+		}
+
+		select {
+		case <-w.ctx.Done():
+			return
+		case task, ok := <-w.manager.taskQueue:
+			if !ok {
+				// Channel closed, worker should stop
+				return
+			}
+			w.executeTask(task)
+		case task, ok := <-lowQueue:
+			if !ok {
+				return
+			}
+			w.executeTask(task)
 		}
 	}
 }
 
 // executeTask executes a single task
 func (w *worker) executeTask(task *Task) {
+	// Tasks still queued when the manager stops are dropped
+	if w.ctx.Err() != nil {
+		return
+	}
+
 	// Update task status
 	w.manager.mutex.Lock()
 	task.Status = TaskStatusRunning
 	now := time.Now()
 	task.StartedAt = &now
+	task.EndedAt = nil
+	task.NextRetry = nil
+	task.Progress = nil
+	task.Attempts++
+	stored := *task
 	w.manager.mutex.Unlock()
+	w.manager.saveTask(stored)
 
 	// Publish started event
 	w.manager.publishEvent(TaskEvent{
@@ -276,8 +475,12 @@ func (w *worker) executeTask(task *Task) {
 		return
 	}
 
-	// Execute the task
-	err := handler.Execute(w.ctx, task)
+	// Execute the task, the handler reports its progress on the context
+	var lastProgress time.Time
+	ctx := withProgress(w.ctx, func(progress TaskProgress) {
+		w.manager.setProgress(task, progress, &lastProgress)
+	})
+	err := handler.Execute(ctx, task)
 
 	if err != nil {
 		w.completeTaskWithError(task, err)
@@ -290,9 +493,11 @@ func (w *worker) executeTask(task *Task) {
 func (w *worker) completeTask(task *Task) {
 	w.manager.mutex.Lock()
 	task.Status = TaskStatusCompleted
+	task.Error = ""
 	now := time.Now()
 	task.EndedAt = &now
 	w.manager.mutex.Unlock()
+	w.manager.deleteStoredTask(task.ID)
 
 	w.manager.publishEvent(TaskEvent{
 		Type:      TaskEventCompleted,
@@ -304,24 +509,53 @@ func (w *worker) completeTask(task *Task) {
 	})
 }
 
-// completeTaskWithError marks a task as failed
+// completeTaskWithError marks a task as failed, or as retrying when its
+// type's retry policy has retries left
 func (w *worker) completeTaskWithError(task *Task, err error) {
+	// Stopping the manager aborted the task, it stays stored as running and
+	// starts over on the next start
+	stopped := w.ctx.Err() != nil
+
 	w.manager.mutex.Lock()
 	task.Status = TaskStatusFailed
-	task.Error = err.Error()
+	// Errors of failed requests contain the request URL
+	task.Error = logging.Redact(err.Error())
 	now := time.Now()
 	task.EndedAt = &now
+	policy, hasPolicy := w.manager.retryPolicies[task.Type]
+	var delay time.Duration
+	if hasPolicy && !stopped && task.Attempts <= policy.MaxRetries {
+		delay = policy.Backoff(task.Attempts)
+		next := now.Add(delay)
+		task.NextRetry = &next
+		task.Status = TaskStatusRetrying
+	}
+	stored := *task
 	w.manager.mutex.Unlock()
 
+	switch {
+	case stopped:
+	case stored.Status == TaskStatusRetrying:
+		w.manager.saveTask(stored)
+		w.manager.scheduleRetry(task, delay)
+	default:
+		w.manager.deleteStoredTask(task.ID)
+	}
+
 	w.manager.publishEvent(TaskEvent{
 		Type:      TaskEventFailed,
 		TaskID:    task.ID,
 		TaskType:  task.Type,
-		Status:    TaskStatusFailed,
+		Status:    stored.Status,
 		Data:      task.Data,
-		Error:     err.Error(),
+		Error:     stored.Error,
 		Timestamp: time.Now(),
 	})
 
+	if stored.Status == TaskStatusRetrying {
+		logging.Warn("Task failed, retrying", "taskID", task.ID, "type", task.Type,
+			"attempt", stored.Attempts, "nextRetry", stored.NextRetry.Format(time.RFC3339), "error", err)
+		return
+	}
 	logging.Error("Task failed", "taskID", task.ID, "type", task.Type, "error", err)
 }
@@ -0,0 +1,273 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+// testHandler handles feed refresh tasks with run
+type testHandler struct {
+	run func(ctx context.Context, task *Task) error
+}
+
+func (h *testHandler) Execute(ctx context.Context, task *Task) error {
+	return h.run(ctx, task)
+}
+
+func (h *testHandler) CanHandle(taskType TaskType) bool {
+	return taskType == TaskTypeFeedRefresh
+}
+
+// openTestStore returns a store on a new database with the schema
+func openTestStore(t *testing.T) *DBStore {
+	t.Helper()
+	schema, err := os.ReadFile("../../sql/schema.sql")
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	db, queries, err := database.Open(filepath.Join(t.TempDir(), "newsgoat.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	if _, err := db.Exec(string(schema)); err != nil {
+		t.Fatalf("failed to create tables: %v", err)
+	}
+	return NewDBStore(queries)
+}
+
+// startManager starts a manager with one worker running handler
+func startManager(t *testing.T, store Store, run func(ctx context.Context, task *Task) error) Manager {
+	t.Helper()
+	manager := NewManagerWithStore(1, store)
+	if err := manager.RegisterHandler(&testHandler{run: run}); err != nil {
+		t.Fatalf("RegisterHandler() error = %v", err)
+	}
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = manager.Stop()
+	})
+	return manager
+}
+
+// nextEvent returns the next completed or failed event
+func nextEvent(t *testing.T, events <-chan TaskEvent) TaskEvent {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-events:
+			if event.Type == TaskEventCompleted || event.Type == TaskEventFailed {
+				return event
+			}
+		case <-timeout:
+			t.Fatal("no task finished")
+		}
+	}
+}
+
+func TestRetryFailedTask(t *testing.T) {
+	var runs atomic.Int64
+	manager := startManager(t, nil, func(ctx context.Context, task *Task) error {
+		if runs.Add(1) < 3 {
+			return errors.New("server error")
+		}
+		return nil
+	})
+	manager.SetRetryPolicy(TaskTypeFeedRefresh, config.RetryPolicy{MaxRetries: 3, Delay: 10 * time.Millisecond})
+	events := manager.Subscribe()
+
+	task := CreateFeedRefreshTask(1, "https://example.com/feed")
+	if err := manager.AddTask(task); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	for range 2 {
+		event := nextEvent(t, events)
+		if event.Type != TaskEventFailed || event.Status != TaskStatusRetrying {
+			t.Fatalf("got %s %s, want a failure that is retried", event.Type, event.Status)
+		}
+	}
+	if event := nextEvent(t, events); event.Type != TaskEventCompleted {
+		t.Fatalf("got %s, want the third attempt to complete", event.Type)
+	}
+
+	got, err := manager.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if got.Attempts != 3 || got.NextRetry != nil {
+		t.Errorf("task has %d attempts and next retry %v, want 3 and none", got.Attempts, got.NextRetry)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	manager := startManager(t, nil, func(ctx context.Context, task *Task) error {
+		return errors.New("not found")
+	})
+	manager.SetRetryPolicy(TaskTypeFeedRefresh, config.RetryPolicy{MaxRetries: 1, Delay: 10 * time.Millisecond})
+	events := manager.Subscribe()
+
+	task := CreateFeedRefreshTask(1, "https://example.com/feed")
+	if err := manager.AddTask(task); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	if event := nextEvent(t, events); event.Status != TaskStatusRetrying {
+		t.Fatalf("first failure has status %s, want retrying", event.Status)
+	}
+	if event := nextEvent(t, events); event.Status != TaskStatusFailed {
+		t.Fatalf("second failure has status %s, want failed", event.Status)
+	}
+	if got, _ := manager.GetTask(task.ID); got.Attempts != 2 {
+		t.Errorf("task ran %d times, want 2", got.Attempts)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := config.RetryPolicy{MaxRetries: 3, Delay: time.Minute}
+	for retry, want := range map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute, 3: 4 * time.Minute, 20: 24 * time.Hour} {
+		if got := policy.Backoff(retry); got != want {
+			t.Errorf("Backoff(%d) = %v, want %v", retry, got, want)
+		}
+	}
+}
+
+func TestRemoveRetryingTask(t *testing.T) {
+	var runs atomic.Int64
+	store := openTestStore(t)
+	manager := startManager(t, store, func(ctx context.Context, task *Task) error {
+		runs.Add(1)
+		return errors.New("server error")
+	})
+	manager.SetRetryPolicy(TaskTypeFeedRefresh, config.RetryPolicy{MaxRetries: 1, Delay: 200 * time.Millisecond})
+	events := manager.Subscribe()
+
+	task := CreateFeedRefreshTask(1, "https://example.com/feed")
+	if err := manager.AddTask(task); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	nextEvent(t, events)
+	if err := manager.RemoveTask(task.ID); err != nil {
+		t.Fatalf("RemoveTask() error = %v", err)
+	}
+	time.Sleep(400 * time.Millisecond)
+	if n := runs.Load(); n != 1 {
+		t.Errorf("removed task ran %d times, want 1", n)
+	}
+	if stored, _ := store.LoadTasks(); len(stored) != 0 {
+		t.Errorf("store still has %d tasks", len(stored))
+	}
+}
+
+func TestStoreKeepsUnfinishedTasks(t *testing.T) {
+	store := openTestStore(t)
+
+	// The first manager stops while the task runs
+	started := make(chan struct{})
+	first := startManager(t, store, func(ctx context.Context, task *Task) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	task := CreateFeedRefreshTask(42, "https://example.com/feed")
+	if err := first.AddTask(task); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	<-started
+	if err := first.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	for range first.Subscribe() {
+	}
+
+	stored, err := store.LoadTasks()
+	if err != nil {
+		t.Fatalf("LoadTasks() error = %v", err)
+	}
+	if len(stored) != 1 || stored[0].ID != task.ID || stored[0].Attempts != 1 {
+		t.Fatalf("stored tasks = %+v, want the aborted task after 1 attempt", stored)
+	}
+
+	// The next one runs it again
+	var mu sync.Mutex
+	var feedID int64
+	second := startManager(t, store, func(ctx context.Context, task *Task) error {
+		mu.Lock()
+		defer mu.Unlock()
+		var err error
+		feedID, err = TaskFeedID(task)
+		return err
+	})
+	events := second.Subscribe()
+	if err := second.RestoreTasks(); err != nil {
+		t.Fatalf("RestoreTasks() error = %v", err)
+	}
+	if event := nextEvent(t, events); event.Type != TaskEventCompleted || event.TaskID != task.ID {
+		t.Fatalf("got %s of task %s, want task %s to complete", event.Type, event.TaskID, task.ID)
+	}
+	mu.Lock()
+	if feedID != 42 {
+		t.Errorf("restored task refreshed feed %d, want 42", feedID)
+	}
+	mu.Unlock()
+	if got, _ := second.GetTask(task.ID); got.Attempts != 2 {
+		t.Errorf("restored task has %d attempts, want 2", got.Attempts)
+	}
+	if stored, _ := store.LoadTasks(); len(stored) != 0 {
+		t.Errorf("store still has %d tasks after they completed", len(stored))
+	}
+}
+
+func TestProgressEvents(t *testing.T) {
+	manager := startManager(t, nil, func(ctx context.Context, task *Task) error {
+		for done := int64(1); done <= 1000; done++ {
+			ReportProgress(ctx, TaskProgress{Done: done, Total: 1000, Message: "working"})
+		}
+		return nil
+	})
+	events := manager.Subscribe()
+
+	task := CreateFeedRefreshTask(1, "https://example.com/feed")
+	if err := manager.AddTask(task); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	var progress []TaskEvent
+	timeout := time.After(5 * time.Second)
+	for done := false; !done; {
+		select {
+		case event := <-events:
+			switch event.Type {
+			case TaskEventProgress:
+				progress = append(progress, event)
+			case TaskEventCompleted:
+				done = true
+			}
+		case <-timeout:
+			t.Fatal("task didn't complete")
+		}
+	}
+
+	// Reports in quick succession are published once, the last one always
+	if len(progress) == 0 || len(progress) > 10 {
+		t.Fatalf("got %d progress events, want a few", len(progress))
+	}
+	last := progress[len(progress)-1].Progress
+	if last == nil || last.Done != 1000 || last.Percent() != 100 {
+		t.Errorf("last progress = %+v, want 1000 of 1000", last)
+	}
+	if got, _ := manager.GetTask(task.ID); got.Progress == nil || got.Progress.Done != 1000 {
+		t.Errorf("task progress = %+v, want 1000 of 1000", got.Progress)
+	}
+}
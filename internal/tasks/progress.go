@@ -0,0 +1,81 @@
+package tasks
+
+import (
+	"context"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/feeds"
+)
+
+// progressInterval is how often a task's progress is published at most, the
+// last step is always published
+const progressInterval = 250 * time.Millisecond
+
+// TaskProgress is how far a running task got
+type TaskProgress struct {
+	Done    int64  `json:"done"`
+	Total   int64  `json:"total,omitempty"` // 0 when the total isn't known
+	Message string `json:"message"`         // Like "parsed 120/450 items"
+}
+
+// Percent returns how much of the task is done, -1 when the total isn't known
+func (p TaskProgress) Percent() int {
+	if p.Total <= 0 {
+		return -1
+	}
+	return int(min(p.Done, p.Total) * 100 / p.Total)
+}
+
+// progressKey is the context key of the function reporting a task's progress
+type progressKey struct{}
+
+// withProgress returns a context handlers report the progress of their task
+// on with ReportProgress
+func withProgress(ctx context.Context, report func(TaskProgress)) context.Context {
+	return context.WithValue(ctx, progressKey{}, report)
+}
+
+// ReportProgress tells the task manager how far the task executed with ctx
+// got. It is a no-op outside a task.
+func ReportProgress(ctx context.Context, progress TaskProgress) {
+	if report, ok := ctx.Value(progressKey{}).(func(TaskProgress)); ok {
+		report(progress)
+	}
+}
+
+// feedProgress returns a context the feed manager reports the progress of
+// the task executed with ctx on
+func feedProgress(ctx context.Context) context.Context {
+	return feeds.WithProgress(ctx, func(progress feeds.Progress) {
+		ReportProgress(ctx, TaskProgress{Done: progress.Done, Total: progress.Total, Message: progress.String()})
+	})
+}
+
+// setProgress records the progress of a running task and publishes it, at
+// most every progressInterval unless it is done
+func (m *DefaultManager) setProgress(task *Task, progress TaskProgress, lastSent *time.Time) {
+	m.mutex.Lock()
+	if task.Status != TaskStatusRunning {
+		m.mutex.Unlock()
+		return
+	}
+	task.Progress = &progress
+	now := time.Now()
+	finished := progress.Total > 0 && progress.Done >= progress.Total
+	if !finished && now.Sub(*lastSent) < progressInterval {
+		m.mutex.Unlock()
+		return
+	}
+	*lastSent = now
+	m.mutex.Unlock()
+
+	m.publishEvent(TaskEvent{
+		Type:      TaskEventProgress,
+		TaskID:    task.ID,
+		TaskType:  task.Type,
+		Status:    TaskStatusRunning,
+		Data:      task.Data,
+		Progress:  &progress,
+		Timestamp: now,
+	})
+}
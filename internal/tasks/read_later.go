@@ -0,0 +1,58 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// ReadLaterHandler sends items to a read-later service
+type ReadLaterHandler struct {
+	feedManager *feeds.Manager
+	// loadConfig returns the current service settings, they are read when the
+	// task runs so credentials aren't kept in the task list
+	loadConfig func() feeds.ReadLaterConfig
+}
+
+// NewReadLaterHandler creates a new read-later handler
+func NewReadLaterHandler(feedManager *feeds.Manager, loadConfig func() feeds.ReadLaterConfig) *ReadLaterHandler {
+	return &ReadLaterHandler{
+		feedManager: feedManager,
+		loadConfig:  loadConfig,
+	}
+}
+
+// Execute sends the task's item to the read-later service
+func (h *ReadLaterHandler) Execute(ctx context.Context, task *Task) error {
+	link, _ := task.Data["url"].(string)
+	title, _ := task.Data["title"].(string)
+	feed, _ := task.Data["feed"].(string)
+
+	cfg := h.loadConfig()
+	if err := h.feedManager.SaveForLater(ctx, cfg, feeds.ReadLaterItem{URL: link, Title: title, Feed: feed}); err != nil {
+		logging.Error("Read later failed", "service", cfg.Service, "url", link, "error", err)
+		return fmt.Errorf("%s: %w", cfg.ServiceName(), err)
+	}
+	logging.DebugCategory(logging.CategoryTasks, "Saved for later", "service", cfg.Service, "url", link)
+
+	return nil
+}
+
+// CanHandle returns true if this handler can handle the given task type
+func (h *ReadLaterHandler) CanHandle(taskType TaskType) bool {
+	return taskType == TaskTypeReadLater
+}
+
+// CreateReadLaterTask creates a task that sends an item to the read-later service
+func CreateReadLaterTask(link, title, feed string) *Task {
+	return &Task{
+		Type: TaskTypeReadLater,
+		Data: map[string]interface{}{
+			"url":   link,
+			"title": title,
+			"feed":  feed,
+		},
+	}
+}
@@ -0,0 +1,94 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// defaultGistFilename is used for the gist file when the reading log isn't also written to disk
+const defaultGistFilename = "reading.html"
+
+// ReadingExportHandler handles reading log export tasks
+type ReadingExportHandler struct {
+	feedManager *feeds.Manager
+}
+
+// NewReadingExportHandler creates a new reading log export handler
+func NewReadingExportHandler(feedManager *feeds.Manager) *ReadingExportHandler {
+	return &ReadingExportHandler{
+		feedManager: feedManager,
+	}
+}
+
+// Execute writes the reading log to a file and/or publishes it to a gist
+func (h *ReadingExportHandler) Execute(ctx context.Context, task *Task) error {
+	source, _ := task.Data["source"].(string)
+	path, _ := task.Data["path"].(string)
+	gistID, _ := task.Data["gist_id"].(string)
+	if path == "" && gistID == "" {
+		return fmt.Errorf("reading export has neither a path nor a gist")
+	}
+
+	readingLog, err := h.feedManager.GetReadingLog(source, feeds.DefaultReadingLogLimit)
+	if err != nil {
+		return fmt.Errorf("failed to build reading log: %w", err)
+	}
+
+	if path != "" {
+		path = expandHome(path)
+		if err := feeds.WriteReadingLogFile(path, readingLog); err != nil {
+			logging.Error("Reading log export failed", "path", path, "error", err)
+			return err
+		}
+		logging.DebugCategory(logging.CategoryTasks, "Reading log exported", "path", path, "items", len(readingLog.Items))
+	}
+
+	if gistID != "" {
+		filename := defaultGistFilename
+		if path != "" {
+			filename = filepath.Base(path)
+		}
+		if err := feeds.PublishReadingLogGist(ctx, gistID, filename, os.Getenv("GITHUB_GIST_TOKEN"), readingLog); err != nil {
+			logging.Error("Reading log gist publish failed", "gistID", gistID, "error", err)
+			return err
+		}
+		logging.DebugCategory(logging.CategoryTasks, "Reading log published", "gistID", gistID, "items", len(readingLog.Items))
+	}
+
+	return nil
+}
+
+// CanHandle returns true if this handler can handle the given task type
+func (h *ReadingExportHandler) CanHandle(taskType TaskType) bool {
+	return taskType == TaskTypeReadingExport
+}
+
+// CreateReadingExportTask creates a new reading log export task
+func CreateReadingExportTask(source, path, gistID string) *Task {
+	return &Task{
+		Type: TaskTypeReadingExport,
+		Data: map[string]interface{}{
+			"source":  source,
+			"path":    path,
+			"gist_id": gistID,
+		},
+	}
+}
+
+// expandHome replaces a leading ~/ in path with the user's home directory
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(homeDir, path[2:])
+}
@@ -0,0 +1,176 @@
+package tasks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule decides when a recurring job runs
+type Schedule interface {
+	// Next returns the first time after after the job runs, zero for never
+	Next(after time.Time) time.Time
+}
+
+// minScheduleInterval is the shortest interval a schedule repeats at
+const minScheduleInterval = time.Minute
+
+// intervalSchedule runs a job at a fixed interval
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+// Every returns a schedule running a job every interval
+func Every(interval time.Duration) Schedule {
+	return intervalSchedule{interval: interval}
+}
+
+func (s intervalSchedule) Next(after time.Time) time.Time {
+	return after.Add(s.interval)
+}
+
+// cronField is the set of values a field of a cron expression matches, bit
+// n is set for value n
+type cronField uint64
+
+func (f cronField) has(value int) bool {
+	return f&(1<<uint(value)) != 0
+}
+
+// cronSchedule runs a job at the minutes a cron expression matches, in
+// local time
+type cronSchedule struct {
+	minute, hour, day, month, weekday cronField
+	// A day of month and a weekday that are both restricted match either
+	anyDay, anyWeekday bool
+}
+
+// cronMacros are the shorthands of common cron expressions
+var cronMacros = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+}
+
+// ParseSchedule parses an interval like "30m" or "@every 6h", a five field
+// cron expression like "0 3 * * *" (minute, hour, day of month, month,
+// weekday) or one of @hourly, @daily, @weekly and @monthly
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if expr, ok := cronMacros[strings.ToLower(spec)]; ok {
+		spec = expr
+	}
+	interval := strings.TrimSpace(strings.TrimPrefix(spec, "@every"))
+	if d, err := time.ParseDuration(interval); err == nil {
+		if d < minScheduleInterval {
+			return nil, fmt.Errorf("schedule %q repeats more often than every %s", spec, minScheduleInterval)
+		}
+		return Every(d), nil
+	}
+	if strings.HasPrefix(spec, "@") {
+		return nil, fmt.Errorf("invalid schedule %q", spec)
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q, want an interval like 6h or a cron expression like \"0 3 * * *\"", spec)
+	}
+	var s cronSchedule
+	var err error
+	if s.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("invalid minute in schedule %q: %w", spec, err)
+	}
+	if s.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("invalid hour in schedule %q: %w", spec, err)
+	}
+	if s.day, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("invalid day of month in schedule %q: %w", spec, err)
+	}
+	if s.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("invalid month in schedule %q: %w", spec, err)
+	}
+	if s.weekday, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("invalid weekday in schedule %q: %w", spec, err)
+	}
+	// 7 is Sunday too
+	if s.weekday.has(7) {
+		s.weekday |= 1
+	}
+	s.anyDay = strings.HasPrefix(fields[2], "*")
+	s.anyWeekday = strings.HasPrefix(fields[4], "*")
+	return s, nil
+}
+
+// parseCronField parses a comma-separated list of values, ranges like 1-5
+// and * for every value, each optionally with a step like */15
+func parseCronField(field string, low, high int) (cronField, error) {
+	var set cronField
+	for _, part := range strings.Split(field, ",") {
+		values, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step %q", part[i+1:])
+			}
+			values, step = part[:i], n
+		}
+
+		start, end := low, high
+		if values != "*" {
+			from, to, isRange := strings.Cut(values, "-")
+			var err error
+			if start, err = strconv.Atoi(from); err != nil {
+				return 0, fmt.Errorf("invalid value %q", from)
+			}
+			end = start
+			if isRange {
+				if end, err = strconv.Atoi(to); err != nil {
+					return 0, fmt.Errorf("invalid value %q", to)
+				}
+			} else if step > 1 {
+				// 5/15 runs from 5 to the end
+				end = high
+			}
+		}
+		if start < low || end > high || start > end {
+			return 0, fmt.Errorf("%q is outside %d-%d", part, low, high)
+		}
+		for v := start; v <= end; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// dayMatches reports whether the day of t is one the schedule runs on
+func (s cronSchedule) dayMatches(t time.Time) bool {
+	day, weekday := s.day.has(t.Day()), s.weekday.has(int(t.Weekday()))
+	if s.anyDay || s.anyWeekday {
+		return day && weekday
+	}
+	return day || weekday
+}
+
+func (s cronSchedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	// Expressions like "0 0 30 2 *" never match
+	for limit := t.AddDate(5, 0, 0); t.Before(limit); {
+		switch {
+		case !s.month.has(int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !s.hour.has(t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case !s.minute.has(t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
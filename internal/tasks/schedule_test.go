@@ -0,0 +1,116 @@
+package tasks
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	// Wednesday
+	now := time.Date(2026, time.March, 4, 10, 17, 30, 0, time.Local)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"30m", now.Add(30 * time.Minute)},
+		{"@every 6h", now.Add(6 * time.Hour)},
+		{"@daily", time.Date(2026, time.March, 5, 0, 0, 0, 0, time.Local)},
+		{"@hourly", time.Date(2026, time.March, 4, 11, 0, 0, 0, time.Local)},
+		{"@monthly", time.Date(2026, time.April, 1, 0, 0, 0, 0, time.Local)},
+		{"0 3 * * *", time.Date(2026, time.March, 5, 3, 0, 0, 0, time.Local)},
+		{"*/15 * * * *", time.Date(2026, time.March, 4, 10, 30, 0, 0, time.Local)},
+		{"5/20 10 * * *", time.Date(2026, time.March, 4, 10, 25, 0, 0, time.Local)},
+		{"0 9 * * 1-5", time.Date(2026, time.March, 5, 9, 0, 0, 0, time.Local)},
+		{"0 9 * * 6,7", time.Date(2026, time.March, 7, 9, 0, 0, 0, time.Local)},
+		{"0 0 * 2 *", time.Date(2027, time.February, 1, 0, 0, 0, 0, time.Local)},
+		// A day of month and a weekday match either
+		{"0 0 20 * 5", time.Date(2026, time.March, 6, 0, 0, 0, 0, time.Local)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		schedule, err := ParseSchedule(tt.spec)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q) error = %v", tt.spec, err)
+			continue
+		}
+		if got := schedule.Next(now); !got.Equal(tt.want) {
+			t.Errorf("ParseSchedule(%q).Next() = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParseScheduleInvalid(t *testing.T) {
+	for _, spec := range []string{"", "10s", "@yearly", "0 3 * *", "60 * * * *", "0 24 * * *", "0 0 0 * *", "* * * 13 *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestRunJob(t *testing.T) {
+	manager := startManager(t, nil, func(ctx context.Context, task *Task) error {
+		return nil
+	})
+	events := manager.Subscribe()
+
+	var runs atomic.Int64
+	manager.ScheduleJob(Job{
+		Name:     "test",
+		Schedule: Every(time.Hour),
+		Tasks: func(time.Time) []*Task {
+			runs.Add(1)
+			return []*Task{CreateFeedRefreshTask(1, "https://example.com/feed")}
+		},
+	})
+	next := manager.NextRun("test")
+	if until := time.Until(next); until <= 59*time.Minute || until > time.Hour {
+		t.Fatalf("job runs next in %v, want an hour", until)
+	}
+
+	// Scheduling the same job again keeps its next run
+	time.Sleep(10 * time.Millisecond)
+	manager.ScheduleJob(Job{Name: "test", Schedule: Every(time.Hour), Tasks: func(time.Time) []*Task { return nil }})
+	if got := manager.NextRun("test"); !got.Equal(next) {
+		t.Errorf("rescheduled job runs next at %v, want %v", got, next)
+	}
+	manager.ScheduleJob(Job{
+		Name:     "test",
+		Schedule: Every(time.Hour),
+		Tasks: func(time.Time) []*Task {
+			runs.Add(1)
+			return []*Task{CreateFeedRefreshTask(1, "https://example.com/feed")}
+		},
+	})
+
+	manager.RunJob("test")
+	timeout := time.After(5 * time.Second)
+	var scheduled bool
+	for !scheduled {
+		select {
+		case event := <-events:
+			if event.Type != TaskEventScheduled {
+				continue
+			}
+			scheduled = true
+			counts, _ := event.Data["tasks"].(map[TaskType]int)
+			if event.Data["job"] != "test" || counts[TaskTypeFeedRefresh] != 1 {
+				t.Errorf("scheduled event data = %v, want one refresh of job test", event.Data)
+			}
+		case <-timeout:
+			t.Fatal("job didn't run")
+		}
+	}
+	if event := nextEvent(t, events); event.Type != TaskEventCompleted {
+		t.Errorf("got %s, want the queued task to complete", event.Type)
+	}
+	if n := runs.Load(); n != 1 {
+		t.Errorf("job ran %d times, want 1", n)
+	}
+
+	manager.UnscheduleJob("test")
+	if got := manager.NextRun("test"); !got.IsZero() {
+		t.Errorf("unscheduled job runs next at %v", got)
+	}
+}
@@ -0,0 +1,164 @@
+package tasks
+
+import (
+	"context"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// Job is a recurring job of the task manager's scheduler, it queues tasks
+// on its schedule
+type Job struct {
+	Name     string
+	Schedule Schedule
+	// Tasks returns the tasks to queue when the job runs, none skips the run.
+	// It runs on the scheduler's goroutine.
+	Tasks func(now time.Time) []*Task
+}
+
+// scheduledJob is a job and when it runs next
+type scheduledJob struct {
+	job    Job
+	next   time.Time
+	runNow bool // RunJob asked for a run before next
+}
+
+// ScheduleJob adds a job or replaces the job of the same name. A job with
+// the same schedule keeps its next run, so scheduling it again on every
+// settings change doesn't hold it off.
+func (m *DefaultManager) ScheduleJob(job Job) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if existing, ok := m.jobs[job.Name]; ok && existing.job.Schedule == job.Schedule {
+		existing.job = job
+		return
+	}
+	m.jobs[job.Name] = &scheduledJob{job: job, next: job.Schedule.Next(time.Now())}
+	m.wakeScheduler()
+	logging.DebugCategory(logging.CategoryTasks, "Job scheduled", "job", job.Name, "next", m.jobs[job.Name].next)
+}
+
+// UnscheduleJob removes a job, it is a no-op for jobs that aren't scheduled
+func (m *DefaultManager) UnscheduleJob(name string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.jobs[name]; ok {
+		delete(m.jobs, name)
+		m.wakeScheduler()
+	}
+}
+
+// RunJob runs a scheduled job now, its schedule goes on from there
+func (m *DefaultManager) RunJob(name string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if scheduled, ok := m.jobs[name]; ok {
+		scheduled.runNow = true
+		m.wakeScheduler()
+	}
+}
+
+// NextRun returns when a job runs next, zero when it isn't scheduled
+func (m *DefaultManager) NextRun(name string) time.Time {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if scheduled, ok := m.jobs[name]; ok {
+		return scheduled.next
+	}
+	return time.Time{}
+}
+
+// wakeScheduler makes the scheduler look at its jobs again, the caller holds
+// the lock
+func (m *DefaultManager) wakeScheduler() {
+	select {
+	case m.jobWake <- struct{}{}:
+	default:
+	}
+}
+
+// runScheduler runs the jobs when they are due until ctx is done
+func (m *DefaultManager) runScheduler(ctx context.Context) {
+	defer m.wg.Done()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		m.mutex.RLock()
+		var next time.Time
+		for _, scheduled := range m.jobs {
+			if scheduled.runNow {
+				next = time.Now()
+				break
+			}
+			if !scheduled.next.IsZero() && (next.IsZero() || scheduled.next.Before(next)) {
+				next = scheduled.next
+			}
+		}
+		m.mutex.RUnlock()
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		var due <-chan time.Time
+		if !next.IsZero() {
+			timer.Reset(time.Until(next))
+			due = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.jobWake:
+		case <-due:
+			m.runDueJobs(time.Now())
+		}
+	}
+}
+
+// runDueJobs queues the tasks of the jobs that are due and works out when
+// they run next
+func (m *DefaultManager) runDueJobs(now time.Time) {
+	m.mutex.Lock()
+	var due []Job
+	for _, scheduled := range m.jobs {
+		if scheduled.runNow || (!scheduled.next.IsZero() && !scheduled.next.After(now)) {
+			scheduled.runNow = false
+			scheduled.next = scheduled.job.Schedule.Next(now)
+			due = append(due, scheduled.job)
+		}
+	}
+	m.mutex.Unlock()
+
+	// The event goes out before the tasks are queued, so subscribers see it
+	// before any of them starts
+	for _, job := range due {
+		queued := job.Tasks(now)
+		if len(queued) == 0 {
+			continue
+		}
+		counts := make(map[TaskType]int)
+		for _, task := range queued {
+			counts[task.Type]++
+		}
+		logging.DebugCategory(logging.CategoryTasks, "Job queued tasks", "job", job.Name, "tasks", counts)
+		m.publishEvent(TaskEvent{
+			Type:      TaskEventScheduled,
+			Data:      map[string]interface{}{"job": job.Name, "tasks": counts},
+			Timestamp: now,
+		})
+		for _, task := range queued {
+			if err := m.AddTask(task); err != nil {
+				logging.Warn("Failed to queue scheduled task", "job", job.Name, "type", task.Type, "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package tasks
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// Store keeps the tasks that didn't finish, so they run again after a crash
+// or restart
+type Store interface {
+	// SaveTask adds a task or updates its status, attempts and retry time
+	SaveTask(task *Task) error
+
+	// DeleteTask forgets a task that finished or was removed
+	DeleteTask(id string) error
+
+	// LoadTasks returns the stored tasks, oldest first
+	LoadTasks() ([]*Task, error)
+}
+
+// DBStore stores tasks in the tasks table of the database
+type DBStore struct {
+	queries *database.Queries
+}
+
+// NewDBStore creates a store keeping tasks in the database
+func NewDBStore(queries *database.Queries) *DBStore {
+	return &DBStore{queries: queries}
+}
+
+// SaveTask adds a task or updates its status, attempts and retry time
+func (s *DBStore) SaveTask(task *Task) error {
+	data, err := json.Marshal(task.Data)
+	if err != nil {
+		return fmt.Errorf("failed to encode task data: %w", err)
+	}
+	var nextRetry sql.NullTime
+	if task.NextRetry != nil {
+		nextRetry = sql.NullTime{Time: *task.NextRetry, Valid: true}
+	}
+	return s.queries.SaveTask(context.Background(), database.SaveTaskParams{
+		ID:        task.ID,
+		Type:      string(task.Type),
+		Status:    string(task.Status),
+		Priority:  int64(task.Priority),
+		Data:      string(data),
+		Attempts:  int64(task.Attempts),
+		NextRetry: nextRetry,
+		Error:     task.Error,
+		CreatedAt: task.CreatedAt,
+	})
+}
+
+// DeleteTask forgets a task that finished or was removed
+func (s *DBStore) DeleteTask(id string) error {
+	return s.queries.DeleteTask(context.Background(), id)
+}
+
+// LoadTasks returns the stored tasks, oldest first. Numbers in their data
+// come back as float64, like tasks sent as JSON.
+func (s *DBStore) LoadTasks() ([]*Task, error) {
+	rows, err := s.queries.GetTasks(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]*Task, 0, len(rows))
+	for _, row := range rows {
+		task := &Task{
+			ID:        row.ID,
+			Type:      TaskType(row.Type),
+			Status:    TaskStatus(row.Status),
+			Priority:  TaskPriority(row.Priority),
+			Attempts:  int(row.Attempts),
+			Error:     row.Error,
+			CreatedAt: row.CreatedAt,
+		}
+		if err := json.Unmarshal([]byte(row.Data), &task.Data); err != nil {
+			logging.Warn("Skipping stored task with invalid data", "taskID", row.ID, "error", err)
+			continue
+		}
+		if task.Data == nil {
+			task.Data = map[string]interface{}{}
+		}
+		if row.NextRetry.Valid {
+			nextRetry := row.NextRetry.Time
+			task.NextRetry = &nextRetry
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
@@ -0,0 +1,81 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/logging"
+	feedsync "github.com/jarv/newsgoat/internal/sync"
+)
+
+// SyncHandler syncs subscriptions and the read and starred state of items
+// with a sync service
+type SyncHandler struct {
+	feedManager *feeds.Manager
+	// loadConfig returns the current sync settings, they are read when the
+	// task runs so the token isn't kept in the task list
+	loadConfig func() feedsync.Config
+}
+
+// NewSyncHandler creates a new sync handler
+func NewSyncHandler(feedManager *feeds.Manager, loadConfig func() feedsync.Config) *SyncHandler {
+	return &SyncHandler{
+		feedManager: feedManager,
+		loadConfig:  loadConfig,
+	}
+}
+
+// Execute adds the feeds followed on the service to the URLs file and syncs
+// the state of items. What changed is left in the task data for the UI.
+func (h *SyncHandler) Execute(ctx context.Context, task *Task) error {
+	cfg := h.loadConfig()
+	backend, err := feedsync.NewBackend(cfg, h.feedManager.SyncClient())
+	if err != nil {
+		return err
+	}
+
+	subscriptions, err := backend.Subscriptions(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cfg.ServiceName(), err)
+	}
+	entries, err := config.ReadURLsFile()
+	if err != nil {
+		return fmt.Errorf("reading the URLs file failed: %w", err)
+	}
+	added := 0
+	for _, sub := range feedsync.MissingSubscriptions(subscriptions, entries) {
+		if err := config.AddURLLine(feedsync.URLLine(sub)); err != nil {
+			return fmt.Errorf("adding %s to the URLs file failed: %w", sub.FeedURL, err)
+		}
+		logging.Info("Added feed from sync", "service", cfg.Service, "url", sub.FeedURL)
+		added++
+	}
+
+	result, err := feedsync.Run(ctx, h.feedManager, backend)
+	if err != nil {
+		logging.Error("Sync failed", "service", cfg.Service, "error", err)
+		return fmt.Errorf("%s: %w", cfg.ServiceName(), err)
+	}
+	logging.DebugCategory(logging.CategoryTasks, "Synced", "service", cfg.Service,
+		"pulled", result.Pulled, "pushed", result.Pushed, "added_feeds", added)
+
+	task.Data["pulled"] = result.Pulled
+	task.Data["pushed"] = result.Pushed
+	task.Data["added_feeds"] = added
+	return nil
+}
+
+// CanHandle returns true if this handler can handle the given task type
+func (h *SyncHandler) CanHandle(taskType TaskType) bool {
+	return taskType == TaskTypeSync
+}
+
+// CreateSyncTask creates a task that syncs with the sync service
+func CreateSyncTask() *Task {
+	return &Task{
+		Type: TaskTypeSync,
+		Data: map[string]interface{}{},
+	}
+}
@@ -3,13 +3,36 @@ package tasks
 import (
 	"context"
 	"time"
+
+	"github.com/jarv/newsgoat/internal/config"
 )
 
 // TaskType represents the type of task
 type TaskType string
 
 const (
-	TaskTypeFeedRefresh TaskType = "feed_refresh"
+	TaskTypeFeedRefresh    TaskType = "feed_refresh"
+	TaskTypeReadingExport  TaskType = "reading_export"
+	TaskTypeItemEnrichment TaskType = "item_enrichment"
+	TaskTypeReadLater      TaskType = "read_later"
+	TaskTypeCleanup        TaskType = "cleanup"
+	TaskTypeLogCleanup     TaskType = "log_cleanup"
+	TaskTypeSync           TaskType = "sync"
+	TaskTypeUpdateCheck    TaskType = "update_check"
+	TaskTypeUpdateInstall  TaskType = "update_install"
+	TaskTypeFeedIcon       TaskType = "feed_icon"
+)
+
+// taskTypes lists every task type a handler can be registered for
+var taskTypes = []TaskType{TaskTypeFeedRefresh, TaskTypeReadingExport, TaskTypeItemEnrichment, TaskTypeReadLater, TaskTypeCleanup, TaskTypeLogCleanup, TaskTypeSync, TaskTypeUpdateCheck, TaskTypeUpdateInstall, TaskTypeFeedIcon}
+
+// TaskPriority decides which queue a task waits in
+type TaskPriority int
+
+const (
+	TaskPriorityNormal TaskPriority = iota
+	// TaskPriorityLow tasks only run when no normal task is waiting
+	TaskPriorityLow
 )
 
 // TaskStatus represents the current status of a task
@@ -20,6 +43,8 @@ const (
 	TaskStatusRunning   TaskStatus = "running"
 	TaskStatusCompleted TaskStatus = "completed"
 	TaskStatusFailed    TaskStatus = "failed"
+	// TaskStatusRetrying tasks failed and run again at their NextRetry
+	TaskStatusRetrying TaskStatus = "retrying"
 )
 
 // Task represents a unit of work that can be executed
@@ -27,11 +52,15 @@ type Task struct {
 	ID        string                 `json:"id"`
 	Type      TaskType               `json:"type"`
 	Status    TaskStatus             `json:"status"`
+	Priority  TaskPriority           `json:"priority,omitempty"`
 	Data      map[string]interface{} `json:"data"`
 	CreatedAt time.Time              `json:"created_at"`
 	StartedAt *time.Time             `json:"started_at,omitempty"`
 	EndedAt   *time.Time             `json:"ended_at,omitempty"`
 	Error     string                 `json:"error,omitempty"`
+	Attempts  int                    `json:"attempts,omitempty"`   // Times the task was started
+	NextRetry *time.Time             `json:"next_retry,omitempty"` // When a retrying task runs again
+	Progress  *TaskProgress          `json:"progress,omitempty"`   // How far a running task got, nil before its handler reports any
 }
 
 // TaskHandler defines the interface for executing tasks
@@ -48,6 +77,7 @@ type TaskEvent struct {
 	Status    TaskStatus             `json:"status"`
 	Data      map[string]interface{} `json:"data"`
 	Error     string                 `json:"error,omitempty"`
+	Progress  *TaskProgress          `json:"progress,omitempty"` // Set on progress events
 	Timestamp time.Time              `json:"timestamp"`
 }
 
@@ -58,7 +88,11 @@ const (
 	TaskEventStarted   TaskEventType = "task_started"
 	TaskEventCompleted TaskEventType = "task_completed"
 	TaskEventFailed    TaskEventType = "task_failed"
-	TaskEventProgress  TaskEventType = "task_progress"
+	// TaskEventProgress is sent when a running task reports how far it got
+	TaskEventProgress TaskEventType = "task_progress"
+	// TaskEventScheduled is sent when a scheduled job queued tasks, its data
+	// has the job's name and the number of tasks of each type
+	TaskEventScheduled TaskEventType = "job_scheduled"
 )
 
 // Manager defines the interface for the task manager
@@ -78,9 +112,13 @@ type Manager interface {
 	// ListTasks returns all tasks with optional filtering
 	ListTasks(filter TaskFilter) ([]*Task, error)
 
-	// Subscribe to task events
+	// Subscribe returns a channel of its own receiving every task event from
+	// now on
 	Subscribe() <-chan TaskEvent
 
+	// Unsubscribe stops sending events to a subscriber and closes its channel
+	Unsubscribe(events <-chan TaskEvent)
+
 	// RegisterHandler registers a task handler
 	RegisterHandler(handler TaskHandler) error
 
@@ -89,6 +127,25 @@ type Manager interface {
 
 	// ClearFailedTasks removes all failed tasks
 	ClearFailedTasks() error
+
+	// SetRetryPolicy sets how often failed tasks of a type run again
+	SetRetryPolicy(taskType TaskType, policy config.RetryPolicy)
+
+	// RestoreTasks queues the stored tasks that didn't finish before the last
+	// stop, once the handlers are registered
+	RestoreTasks() error
+
+	// ScheduleJob adds a recurring job or replaces the one of the same name
+	ScheduleJob(job Job)
+
+	// UnscheduleJob removes a recurring job
+	UnscheduleJob(name string)
+
+	// RunJob runs a scheduled job now
+	RunJob(name string)
+
+	// NextRun returns when a job runs next, zero when it isn't scheduled
+	NextRun(name string) time.Time
 }
 
 // TaskFilter represents filtering options for listing tasks
@@ -0,0 +1,99 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jarv/newsgoat/internal/updater"
+)
+
+// UpdateCheckHandler looks for a newer NewsGoat release
+type UpdateCheckHandler struct{}
+
+// NewUpdateCheckHandler creates a new update check handler
+func NewUpdateCheckHandler() *UpdateCheckHandler {
+	return &UpdateCheckHandler{}
+}
+
+// Execute checks for a newer release, the task's data gets its version and
+// download URL when there is one
+func (h *UpdateCheckHandler) Execute(ctx context.Context, task *Task) error {
+	info, err := updater.CheckForUpdate()
+	if err != nil {
+		return fmt.Errorf("update check failed: %w", err)
+	}
+	if info != nil {
+		task.Data["current_version"] = info.CurrentVersion
+		task.Data["latest_version"] = info.LatestVersion
+		task.Data["download_url"] = info.DownloadURL
+	}
+	return nil
+}
+
+// CanHandle returns true if this handler can handle the given task type
+func (h *UpdateCheckHandler) CanHandle(taskType TaskType) bool {
+	return taskType == TaskTypeUpdateCheck
+}
+
+// CreateUpdateCheckTask creates a low priority task that checks for a newer
+// release
+func CreateUpdateCheckTask() *Task {
+	return &Task{
+		Type:     TaskTypeUpdateCheck,
+		Priority: TaskPriorityLow,
+		Data:     map[string]interface{}{},
+	}
+}
+
+// UpdateInstallHandler downloads a release and replaces the running binary
+// with it
+type UpdateInstallHandler struct{}
+
+// NewUpdateInstallHandler creates a new update install handler
+func NewUpdateInstallHandler() *UpdateInstallHandler {
+	return &UpdateInstallHandler{}
+}
+
+// Execute downloads the release in the task's data and installs it,
+// reporting how much was downloaded
+func (h *UpdateInstallHandler) Execute(ctx context.Context, task *Task) error {
+	downloadURL, _ := task.Data["download_url"].(string)
+	if downloadURL == "" {
+		return fmt.Errorf("missing download_url in task data")
+	}
+	if err := updater.CheckWritePermission(); err != nil {
+		return err
+	}
+	err := updater.DownloadAndInstallWithProgress(downloadURL, func(done, total int64) {
+		ReportProgress(ctx, TaskProgress{Done: done, Total: total, Message: downloadMessage(done, total)})
+	})
+	if err != nil {
+		return fmt.Errorf("update installation failed: %w", err)
+	}
+	return nil
+}
+
+// downloadMessage describes how much of a download is done
+func downloadMessage(done, total int64) string {
+	if total > 0 {
+		return fmt.Sprintf("downloaded %d%%", min(done, total)*100/total)
+	}
+	return fmt.Sprintf("downloaded %.1f MB", float64(done)/(1<<20))
+}
+
+// CanHandle returns true if this handler can handle the given task type
+func (h *UpdateInstallHandler) CanHandle(taskType TaskType) bool {
+	return taskType == TaskTypeUpdateInstall
+}
+
+// CreateUpdateInstallTask creates a task that installs the release version
+// downloaded from downloadURL
+func CreateUpdateInstallTask(version, downloadURL string) *Task {
+	return &Task{
+		Type: TaskTypeUpdateInstall,
+		Data: map[string]interface{}{
+			"version":      version,
+			"download_url": downloadURL,
+		},
+	}
+}
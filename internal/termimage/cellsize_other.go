@@ -0,0 +1,9 @@
+//go:build !unix
+
+package termimage
+
+// TerminalCellSize returns DefaultCellSize, the cell size isn't available
+// here
+func TerminalCellSize() CellSize {
+	return DefaultCellSize
+}
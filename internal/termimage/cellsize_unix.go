@@ -0,0 +1,19 @@
+//go:build unix
+
+package termimage
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// TerminalCellSize returns the cell size the terminal on stdout reports, or
+// DefaultCellSize when it reports none
+func TerminalCellSize() CellSize {
+	size, err := unix.IoctlGetWinsize(int(os.Stdout.Fd()), unix.TIOCGWINSZ)
+	if err != nil || size.Col == 0 || size.Row == 0 || size.Xpixel == 0 || size.Ypixel == 0 {
+		return DefaultCellSize
+	}
+	return CellSize{Width: int(size.Xpixel / size.Col), Height: int(size.Ypixel / size.Row)}
+}
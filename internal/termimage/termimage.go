@@ -0,0 +1,293 @@
+// Package termimage draws pictures in terminals that support the kitty,
+// iTerm2 or sixel graphics protocols.
+package termimage
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"strings"
+)
+
+// Protocol is a terminal graphics protocol
+type Protocol string
+
+// Supported protocols, ProtocolNone when pictures can't be drawn
+const (
+	ProtocolNone   Protocol = ""
+	ProtocolKitty  Protocol = "kitty"
+	ProtocolITerm2 Protocol = "iterm2"
+	ProtocolSixel  Protocol = "sixel"
+)
+
+// SettingAuto detects the protocol from the environment
+const SettingAuto = "auto"
+
+const (
+	// minImageSize skips tracking pixels and spacers, in pixels
+	minImageSize = 16
+	// kittyChunkSize is the most base64 data kitty accepts per escape sequence
+	kittyChunkSize = 4096
+)
+
+// ErrTooSmall is returned for images too small to be worth drawing
+var ErrTooSmall = errors.New("image too small")
+
+// Select returns the protocol of a setting: "auto", "kitty", "iterm2" or
+// "sixel". Anything else turns images off.
+func Select(setting string, getenv func(string) string) Protocol {
+	switch Protocol(setting) {
+	case ProtocolKitty, ProtocolITerm2, ProtocolSixel:
+		return Protocol(setting)
+	}
+	if setting == SettingAuto {
+		return Detect(getenv)
+	}
+	return ProtocolNone
+}
+
+// Detect guesses the protocol of the terminal from its environment variables.
+// Terminal multiplexers hide the terminal and don't pass graphics through, so
+// no protocol is used inside them.
+func Detect(getenv func(string) string) Protocol {
+	if getenv("TMUX") != "" || strings.HasPrefix(getenv("TERM"), "screen") {
+		return ProtocolNone
+	}
+	term := getenv("TERM")
+	termProgram := getenv("TERM_PROGRAM")
+	switch {
+	case getenv("KITTY_WINDOW_ID") != "", term == "xterm-kitty", term == "xterm-ghostty", termProgram == "ghostty":
+		return ProtocolKitty
+	case termProgram == "iTerm.app", termProgram == "WezTerm", getenv("LC_TERMINAL") == "iTerm2":
+		return ProtocolITerm2
+	case strings.Contains(term, "sixel"), strings.HasPrefix(term, "foot"), strings.HasPrefix(term, "mlterm"),
+		strings.HasPrefix(term, "contour"), getenv("WT_SESSION") != "":
+		return ProtocolSixel
+	}
+	return ProtocolNone
+}
+
+// CellSize is the size of a terminal cell in pixels
+type CellSize struct {
+	Width, Height int
+}
+
+// DefaultCellSize is used when the terminal doesn't report its cell size
+var DefaultCellSize = CellSize{Width: 10, Height: 20}
+
+// Image is a picture encoded for the terminal. Drawn at the cursor it covers
+// Cols cells of Rows lines, the cursor position afterwards depends on the
+// protocol.
+type Image struct {
+	Cols, Rows int
+	sequence   string
+}
+
+// Sequence returns the escape sequence that draws the image
+func (img *Image) Sequence() string {
+	return img.sequence
+}
+
+// Encode decodes a PNG, JPEG or GIF image and encodes it for protocol, scaled
+// down to fit in maxCols cells and maxRows lines
+func Encode(data []byte, protocol Protocol, maxCols, maxRows int, cell CellSize) (*Image, error) {
+	if protocol == ProtocolNone {
+		return nil, fmt.Errorf("no graphics protocol")
+	}
+	if cell.Width <= 0 || cell.Height <= 0 {
+		cell = DefaultCellSize
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	bounds := src.Bounds()
+	if bounds.Dx() < minImageSize || bounds.Dy() < minImageSize {
+		return nil, ErrTooSmall
+	}
+
+	width, height := fitSize(bounds.Dx(), bounds.Dy(), max(maxCols, 1)*cell.Width, max(maxRows, 1)*cell.Height)
+	scaled := scale(src, width, height)
+	drawnHeight := height
+	if protocol == ProtocolSixel {
+		// Sixel images are drawn in bands of six pixels
+		drawnHeight = ceilDiv(height, 6) * 6
+	}
+	img := &Image{
+		Cols: ceilDiv(width, cell.Width),
+		Rows: min(ceilDiv(drawnHeight, cell.Height), max(maxRows, 1)),
+	}
+
+	switch protocol {
+	case ProtocolKitty:
+		encoded, err := encodePNG(scaled)
+		if err != nil {
+			return nil, err
+		}
+		img.sequence = kittySequence(encoded, img.Cols, img.Rows)
+	case ProtocolITerm2:
+		encoded, err := encodePNG(scaled)
+		if err != nil {
+			return nil, err
+		}
+		img.sequence = fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\a",
+			len(encoded), img.Cols, img.Rows, base64.StdEncoding.EncodeToString(encoded))
+	case ProtocolSixel:
+		img.sequence = sixelSequence(scaled)
+	default:
+		return nil, fmt.Errorf("unknown graphics protocol %q", protocol)
+	}
+	return img, nil
+}
+
+// Clear returns the escape sequence that removes every image drawn with
+// protocol. Images drawn with the others are text cells that are cleared with
+// the screen.
+func Clear(protocol Protocol) string {
+	if protocol == ProtocolKitty {
+		return "\x1b_Ga=d,d=A,q=2\x1b\\"
+	}
+	return ""
+}
+
+// fitSize scales width and height down to fit in maxWidth and maxHeight,
+// keeping the aspect ratio
+func fitSize(width, height, maxWidth, maxHeight int) (int, int) {
+	if width > maxWidth {
+		height = max(height*maxWidth/width, 1)
+		width = maxWidth
+	}
+	if height > maxHeight {
+		width = max(width*maxHeight/height, 1)
+		height = maxHeight
+	}
+	return width, height
+}
+
+// scale resizes an image with nearest neighbour sampling, which is plenty for
+// a preview in a terminal
+func scale(src image.Image, width, height int) *image.NRGBA {
+	bounds := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		sy := bounds.Min.Y + y*bounds.Dy()/height
+		for x := range width {
+			sx := bounds.Min.X + x*bounds.Dx()/width
+			dst.Set(x, y, src.At(sx, sy))
+		}
+	}
+	return dst
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// kittySequence transmits a PNG and places it over cols by rows cells without
+// moving the cursor
+func kittySequence(encoded []byte, cols, rows int) string {
+	data := base64.StdEncoding.EncodeToString(encoded)
+	var b strings.Builder
+	for i := 0; i < len(data); i += kittyChunkSize {
+		chunk := data[i:min(i+kittyChunkSize, len(data))]
+		more := 0
+		if i+kittyChunkSize < len(data) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,q=2,C=1,c=%d,r=%d,m=%d;%s\x1b\\", cols, rows, more, chunk)
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	return b.String()
+}
+
+// sixelSequence encodes an image as sixels with the web-safe palette,
+// transparent pixels are left unpainted
+func sixelSequence(img *image.NRGBA) string {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	colors := color.Palette(palette.WebSafe)
+	paletted := image.NewPaletted(bounds, colors)
+	draw.FloydSteinberg.Draw(paletted, bounds, img, bounds.Min)
+
+	var b strings.Builder
+	// P2=1 keeps unpainted pixels transparent, the raster attributes give the size
+	fmt.Fprintf(&b, "\x1bP0;1;0q\"1;1;%d;%d", width, height)
+	for i, c := range colors {
+		r, g, bl, _ := c.RGBA()
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, bl*100/0xffff)
+	}
+
+	row := make([]byte, width)
+	for top := 0; top < height; top += 6 {
+		// Colors used in this band of six pixel rows
+		used := make(map[uint8]bool)
+		for y := top; y < min(top+6, height); y++ {
+			for x := range width {
+				if img.NRGBAAt(x, y).A >= 128 {
+					used[paletted.ColorIndexAt(x, y)] = true
+				}
+			}
+		}
+		first := true
+		for index := range len(colors) {
+			if !used[uint8(index)] {
+				continue
+			}
+			for x := range width {
+				var bits byte
+				for dy := range 6 {
+					y := top + dy
+					if y < height && img.NRGBAAt(x, y).A >= 128 && paletted.ColorIndexAt(x, y) == uint8(index) {
+						bits |= 1 << dy
+					}
+				}
+				row[x] = '?' + bits
+			}
+			if !first {
+				// Back to the start of the band for the next color
+				b.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(&b, "#%d", index)
+			writeSixelRun(&b, row)
+		}
+		b.WriteByte('-')
+	}
+	b.WriteString("\x1b\\")
+	return b.String()
+}
+
+// writeSixelRun writes a row of sixels, repeats are run-length encoded
+func writeSixelRun(b *strings.Builder, row []byte) {
+	for i := 0; i < len(row); {
+		j := i
+		for j < len(row) && row[j] == row[i] {
+			j++
+		}
+		if count := j - i; count > 3 {
+			fmt.Fprintf(b, "!%d%c", count, row[i])
+		} else {
+			b.Write(row[i:j])
+		}
+		i = j
+	}
+}
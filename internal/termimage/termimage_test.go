@@ -0,0 +1,112 @@
+package termimage
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want Protocol
+	}{
+		{map[string]string{"TERM": "xterm-kitty"}, ProtocolKitty},
+		{map[string]string{"TERM": "xterm-256color", "KITTY_WINDOW_ID": "1"}, ProtocolKitty},
+		{map[string]string{"TERM_PROGRAM": "iTerm.app"}, ProtocolITerm2},
+		{map[string]string{"TERM": "foot"}, ProtocolSixel},
+		{map[string]string{"TERM": "xterm-256color"}, ProtocolNone},
+		{map[string]string{"TERM": "xterm-kitty", "TMUX": "/tmp/tmux-1000/default,1,0"}, ProtocolNone},
+	}
+	for _, tt := range tests {
+		getenv := func(key string) string { return tt.env[key] }
+		if got := Detect(getenv); got != tt.want {
+			t.Errorf("Detect(%v) = %q, want %q", tt.env, got, tt.want)
+		}
+	}
+
+	none := func(string) string { return "" }
+	if got := Select("sixel", none); got != ProtocolSixel {
+		t.Errorf("Select(sixel) = %q", got)
+	}
+	if got := Select("", none); got != ProtocolNone {
+		t.Errorf("Select(\"\") = %q", got)
+	}
+}
+
+// testPNG is a width by height PNG, opaque on the left half and transparent
+// on the right
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width / 2 {
+			img.SetNRGBA(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestEncode(t *testing.T) {
+	cell := CellSize{Width: 10, Height: 20}
+	data := testPNG(t, 400, 240)
+
+	for _, protocol := range []Protocol{ProtocolKitty, ProtocolITerm2, ProtocolSixel} {
+		// 400x240 pixels fit in 40 columns and 12 rows, 20 columns halve it
+		img, err := Encode(data, protocol, 20, 30, cell)
+		if err != nil {
+			t.Fatalf("Encode(%s) error = %v", protocol, err)
+		}
+		if img.Cols != 20 || img.Rows != 6 {
+			t.Errorf("Encode(%s) = %dx%d cells, want 20x6", protocol, img.Cols, img.Rows)
+		}
+		// The renderer must see the escape sequence as taking no space
+		if width := ansi.StringWidth(img.Sequence()); width != 0 {
+			t.Errorf("Encode(%s) sequence is %d cells wide", protocol, width)
+		}
+	}
+
+	// Tall images are limited by the rows
+	img, err := Encode(testPNG(t, 100, 1000), ProtocolKitty, 80, 10, cell)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Rows != 10 || img.Cols != 2 {
+		t.Errorf("tall image = %dx%d cells, want 2x10", img.Cols, img.Rows)
+	}
+
+	if _, err := Encode(testPNG(t, 1, 1), ProtocolKitty, 80, 10, cell); err != ErrTooSmall {
+		t.Errorf("Encode() of a tracking pixel error = %v, want ErrTooSmall", err)
+	}
+	if _, err := Encode([]byte("<svg/>"), ProtocolKitty, 80, 10, cell); err == nil {
+		t.Error("Encode() of an SVG succeeded")
+	}
+}
+
+func TestSixelSequence(t *testing.T) {
+	img, err := Encode(testPNG(t, 40, 20), ProtocolSixel, 80, 10, CellSize{Width: 10, Height: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seq := img.Sequence()
+	if !strings.HasPrefix(seq, "\x1bP0;1;0q\"1;1;40;20") || !strings.HasSuffix(seq, "-\x1b\\") {
+		t.Fatalf("sixel sequence = %q", seq)
+	}
+	// Four bands of six rows, the red left half is one run and the
+	// transparent right half is never painted
+	if got := strings.Count(seq, "-"); got != 4 {
+		t.Errorf("%d bands, want 4", got)
+	}
+	if !strings.Contains(seq, "!20~") || strings.Contains(seq, "!40") {
+		t.Errorf("sixel data = %q", seq[strings.LastIndex(seq, "#"):])
+	}
+}
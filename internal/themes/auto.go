@@ -0,0 +1,23 @@
+package themes
+
+// AutoTheme is the theme name that follows the terminal's background, with
+// the light theme on light backgrounds and the dark theme on the others
+const AutoTheme = "auto"
+
+// Resolve returns the name of the theme to draw with, the light or dark
+// theme for AutoTheme and name itself otherwise
+func Resolve(name string, darkBackground bool) string {
+	if name != AutoTheme {
+		return name
+	}
+	if darkBackground {
+		return "dark"
+	}
+	return "light"
+}
+
+// GetThemeChoices returns the themes the theme picker offers, AutoTheme
+// followed by the built-in and custom themes
+func GetThemeChoices() []string {
+	return append([]string{AutoTheme}, GetThemeNames()...)
+}
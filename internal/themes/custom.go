@@ -0,0 +1,168 @@
+package themes
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/glamour/styles"
+)
+
+// colorPattern matches the colors lipgloss takes, an ANSI color number or a
+// hex color
+var colorPattern = regexp.MustCompile(`^([0-9]{1,3}|#[0-9a-fA-F]{3}|#[0-9a-fA-F]{6})$`)
+
+// themeColors are the keys of the colors in a theme file
+var themeColors = map[string]func(*Theme) *string{
+	"title_color":         func(t *Theme) *string { return &t.TitleColor },
+	"title_color_fg":      func(t *Theme) *string { return &t.TitleColorFg },
+	"selected_item_color": func(t *Theme) *string { return &t.SelectedItemColor },
+	"filter_color":        func(t *Theme) *string { return &t.FilterColor },
+	"keyword_color":       func(t *Theme) *string { return &t.KeywordColor },
+	"unread_color":        func(t *Theme) *string { return &t.UnreadColor },
+	"old_item_color":      func(t *Theme) *string { return &t.OldItemColor },
+	"error_color":         func(t *Theme) *string { return &t.ErrorColor },
+	"folder_color":        func(t *Theme) *string { return &t.FolderColor },
+}
+
+// LoadCustomThemes adds the themes of the .toml files in dir to the built-in
+// ones, sorted by name. A missing directory has none, files that can't be
+// used are skipped and returned as errors.
+func LoadCustomThemes(dir string) []error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.toml"))
+	if err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	var custom []Theme
+	for _, path := range paths {
+		theme, err := loadThemeFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if theme.Name == AutoTheme || slices.Contains(GetThemeNames(), theme.Name) || slices.ContainsFunc(custom, func(t Theme) bool { return t.Name == theme.Name }) {
+			errs = append(errs, fmt.Errorf("%s: a theme named %q already exists", path, theme.Name))
+			continue
+		}
+		custom = append(custom, theme)
+	}
+	sort.Slice(custom, func(i, j int) bool { return custom[i].Name < custom[j].Name })
+	AvailableThemes = append(AvailableThemes, custom...)
+	return errs
+}
+
+// loadThemeFile reads a theme from lines like `unread_color = "#50fa7b"`, a
+// subset of TOML. The theme is named after the file unless it sets a name,
+// what it doesn't set comes from the built-in theme its base names, dark by
+// default.
+func loadThemeFile(path string) (Theme, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Theme{}, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return Theme{}, fmt.Errorf("%s:%d: expected key = \"value\"", path, lineNumber)
+		}
+		value, err := parseThemeValue(strings.TrimSpace(value))
+		if err != nil {
+			return Theme{}, fmt.Errorf("%s:%d: %w", path, lineNumber, err)
+		}
+		if _, isColor := themeColors[key]; !isColor && key != "name" && key != "base" && key != "glamour_style" && key != "highlight_style" {
+			return Theme{}, fmt.Errorf("%s:%d: unknown key %q", path, lineNumber, key)
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return Theme{}, err
+	}
+
+	base := values["base"]
+	if base == "" {
+		base = AvailableThemes[0].Name
+	}
+	if !slices.Contains(GetThemeNames(), base) {
+		return Theme{}, fmt.Errorf("%s: unknown base theme %q", path, base)
+	}
+	theme := *GetThemeByName(base)
+	theme.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if name := values["name"]; name != "" {
+		theme.Name = name
+	}
+	theme.Path = path
+	theme.HighlightStyle = ""
+
+	for key, field := range themeColors {
+		value, ok := values[key]
+		if !ok {
+			continue
+		}
+		if !colorPattern.MatchString(value) {
+			return Theme{}, fmt.Errorf("%s: %s %q isn't a color number or #rrggbb", path, key, value)
+		}
+		if n, err := strconv.Atoi(value); err == nil && n > 255 {
+			return Theme{}, fmt.Errorf("%s: %s %q isn't a color number or #rrggbb", path, key, value)
+		}
+		*field(&theme) = value
+	}
+
+	// A glamour style is one of glamour's own or a JSON style file, relative
+	// to the theme file
+	if style := values["glamour_style"]; style != "" {
+		if _, ok := styles.DefaultStyles[style]; !ok {
+			if !filepath.IsAbs(style) {
+				style = filepath.Join(filepath.Dir(path), style)
+			}
+			if _, err := os.Stat(style); err != nil {
+				return Theme{}, fmt.Errorf("%s: glamour_style %q is neither a glamour style nor a file", path, values["glamour_style"])
+			}
+		}
+		theme.GlamourStyle = style
+	}
+
+	if highlight := values["highlight_style"]; highlight != "" {
+		if !slices.Contains(GetHighlightStyles(), highlight) {
+			return Theme{}, fmt.Errorf("%s: highlight_style %q isn't one of %s", path, highlight, strings.Join(GetHighlightStyles(), ", "))
+		}
+		theme.HighlightStyle = highlight
+	}
+	return theme, nil
+}
+
+// parseThemeValue reads a quoted TOML string, a trailing comment is ignored
+func parseThemeValue(value string) (string, error) {
+	if value == "" || (value[0] != '"' && value[0] != '\'') {
+		return "", fmt.Errorf("expected a quoted value, got %q", value)
+	}
+	end := strings.IndexByte(value[1:], value[0])
+	if end < 0 {
+		return "", fmt.Errorf("missing closing quote in %s", value)
+	}
+	rest := strings.TrimSpace(value[end+2:])
+	if rest != "" && !strings.HasPrefix(rest, "#") {
+		return "", fmt.Errorf("unexpected %q after the value", rest)
+	}
+	return value[1 : end+1], nil
+}
@@ -0,0 +1,86 @@
+package themes
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoadCustomThemes(t *testing.T) {
+	builtIn := slices.Clone(AvailableThemes)
+	t.Cleanup(func() {
+		AvailableThemes = builtIn
+	})
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"solarized.toml": `# Solarized, on top of the light theme
+base = "light"
+unread_color = "#859900"   # green
+error_color = '160'
+highlight_style = "underline"
+`,
+		"night.toml": `name = "Night Owl"
+glamour_style = "owl.json"
+folder_color = "#82aaff"
+`,
+		"owl.json":       `{}`,
+		"broken.toml":    "unread_color #859900\n",
+		"badcolor.toml":  `unread_color = "green"` + "\n",
+		"duplicate.toml": `name = "dark"` + "\n",
+		"unknown.toml":   `background = "#000000"` + "\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	errs := LoadCustomThemes(dir)
+	if len(errs) != 4 {
+		t.Errorf("LoadCustomThemes() returned %d errors, want 4: %v", len(errs), errs)
+	}
+	for _, err := range errs {
+		if strings.Contains(err.Error(), "solarized") || strings.Contains(err.Error(), "night") {
+			t.Errorf("valid theme failed to load: %v", err)
+		}
+	}
+
+	names := GetThemeNames()
+	if want := []string{"Night Owl", "solarized"}; !slices.Equal(names[len(builtIn):], want) {
+		t.Fatalf("custom themes = %v, want %v", names[len(builtIn):], want)
+	}
+
+	solarized := GetThemeByName("solarized")
+	light := GetThemeByName("light")
+	if solarized.UnreadColor != "#859900" || solarized.ErrorColor != "160" || solarized.HighlightStyle != "underline" {
+		t.Errorf("solarized = %+v, want its own unread and error colors and highlight style", *solarized)
+	}
+	if solarized.FolderColor != light.FolderColor || solarized.GlamourStyle != "light" {
+		t.Errorf("solarized = %+v, want the rest from the light theme", *solarized)
+	}
+
+	night := GetThemeByName("Night Owl")
+	if night.GlamourStyle != filepath.Join(dir, "owl.json") {
+		t.Errorf("GlamourStyle = %q, want the style file next to the theme", night.GlamourStyle)
+	}
+	if night.FolderColor != "#82aaff" || night.UnreadColor != GetThemeByName("dark").UnreadColor || night.HighlightStyle != "" {
+		t.Errorf("Night Owl = %+v, want its folder color on top of the dark theme", *night)
+	}
+}
+
+func TestLoadCustomThemesMissingDir(t *testing.T) {
+	builtIn := slices.Clone(AvailableThemes)
+	t.Cleanup(func() {
+		AvailableThemes = builtIn
+	})
+
+	if errs := LoadCustomThemes(filepath.Join(t.TempDir(), "themes")); len(errs) != 0 {
+		t.Errorf("LoadCustomThemes() errors = %v, want none", errs)
+	}
+	if len(AvailableThemes) != len(builtIn) {
+		t.Errorf("%d themes, want the %d built-in ones", len(AvailableThemes), len(builtIn))
+	}
+}
@@ -7,7 +7,13 @@ type Theme struct {
 	TitleColorFg      string
 	SelectedItemColor string
 	FilterColor       string
+	KeywordColor      string // Color of highlight keywords in titles and articles
+	UnreadColor       string // Feeds, folders and items with unread items
+	OldItemColor      string // Items older than the "Old Item Days" setting
+	ErrorColor        string // Feeds whose last refresh failed and error messages
+	FolderColor       string // Folder rows in the feed list
 	HighlightStyle    string // "background", "underline", "prefix", "prefix-underline"
+	Path              string // File a custom theme was loaded from, empty for built-in themes
 }
 
 var AvailableThemes = []Theme{
@@ -18,6 +24,11 @@ var AvailableThemes = []Theme{
 		TitleColorFg:      "231",
 		SelectedItemColor: "170",
 		FilterColor:       "#555555",
+		KeywordColor:      "214",
+		UnreadColor:       "2",
+		OldItemColor:      "244",
+		ErrorColor:        "9",
+		FolderColor:       "110",
 		HighlightStyle:    "prefix-underline",
 	},
 	{
@@ -27,6 +38,11 @@ var AvailableThemes = []Theme{
 		TitleColorFg:      "0",
 		SelectedItemColor: "75",
 		FilterColor:       "#999999",
+		KeywordColor:      "166",
+		UnreadColor:       "28",
+		OldItemColor:      "248",
+		ErrorColor:        "160",
+		FolderColor:       "25",
 		HighlightStyle:    "prefix-underline",
 	},
 	{
@@ -36,6 +52,11 @@ var AvailableThemes = []Theme{
 		TitleColorFg:      "231",
 		SelectedItemColor: "212",
 		FilterColor:       "#6272a4",
+		KeywordColor:      "#f1fa8c",
+		UnreadColor:       "#50fa7b",
+		OldItemColor:      "#6272a4",
+		ErrorColor:        "#ff5555",
+		FolderColor:       "#8be9fd",
 		HighlightStyle:    "prefix-underline",
 	},
 	{
@@ -45,6 +66,11 @@ var AvailableThemes = []Theme{
 		TitleColorFg:      "0",
 		SelectedItemColor: "205",
 		FilterColor:       "#cc99cc",
+		KeywordColor:      "226",
+		UnreadColor:       "2",
+		OldItemColor:      "#a07ca0",
+		ErrorColor:        "9",
+		FolderColor:       "213",
 		HighlightStyle:    "prefix-underline",
 	},
 	{
@@ -54,6 +80,11 @@ var AvailableThemes = []Theme{
 		TitleColorFg:      "0",
 		SelectedItemColor: "7",
 		FilterColor:       "#808080",
+		KeywordColor:      "15",
+		UnreadColor:       "15",
+		OldItemColor:      "8",
+		ErrorColor:        "15",
+		FolderColor:       "7",
 		HighlightStyle:    "prefix",
 	},
 }
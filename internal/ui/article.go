@@ -0,0 +1,271 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/termimage"
+)
+
+const (
+	// backgroundRenderSize is the article size from which articles are
+	// rendered in the background, glamour takes long enough on them to hold
+	// up keys
+	backgroundRenderSize = 32 << 10
+	// maxCachedArticles is how many rendered articles are kept for going
+	// back to them
+	maxCachedArticles = 50
+	// defaultArticleWrapWidth is the width articles are wrapped at until the
+	// terminal reports its size
+	defaultArticleWrapWidth = 80
+	// minArticleWrapWidth keeps articles readable in very narrow terminals
+	minArticleWrapWidth = 20
+)
+
+// articleKey is what an article is rendered from, the rendered lines are
+// kept until it changes
+type articleKey struct {
+	itemID    int64
+	content   string
+	raw       bool
+	width     int
+	wrapWidth int
+	theme     string
+	keywords  string
+	direction string
+	reorder   bool
+	images    int // Images that can be drawn, -1 when images are off
+}
+
+// renderedArticle is an article rendered for the article view
+type renderedArticle struct {
+	key        articleKey
+	lines      []string
+	placements []imagePlacement
+	words      int // Words in the text of the article, for its reading time
+}
+
+// articleCache keeps the latest rendered articles by item, so going back to
+// one with n/N shows it without rendering it again
+type articleCache struct {
+	articles map[int64]renderedArticle
+	order    []int64 // Item IDs, least recently rendered first
+}
+
+func newArticleCache() *articleCache {
+	return &articleCache{articles: make(map[int64]renderedArticle)}
+}
+
+// get returns the rendered article of an item when it was rendered from the
+// same key, a different width, theme or content needs rendering again
+func (c *articleCache) get(key articleKey) (renderedArticle, bool) {
+	article, ok := c.articles[key.itemID]
+	if !ok || article.key != key {
+		return renderedArticle{}, false
+	}
+	return article, true
+}
+
+// put keeps a rendered article in place of the item's earlier one, dropping
+// the least recently rendered article when the cache is full
+func (c *articleCache) put(article renderedArticle) {
+	itemID := article.key.itemID
+	if _, ok := c.articles[itemID]; ok {
+		c.order = slices.DeleteFunc(c.order, func(id int64) bool { return id == itemID })
+	} else if len(c.order) == maxCachedArticles {
+		delete(c.articles, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.articles[itemID] = article
+	c.order = append(c.order, itemID)
+}
+
+// ArticleRenderedMsg carries an article rendered in the background
+type ArticleRenderedMsg struct {
+	Article renderedArticle
+}
+
+// articleJob is everything an article is rendered from, copied out of the
+// model so it can be rendered away from it
+type articleJob struct {
+	key          articleKey
+	link         string
+	links        []string
+	images       map[string]*termimage.Image // Images that can be drawn, by URL
+	keywords     []string
+	keywordStyle lipgloss.Style
+	linksStyle   lipgloss.Style
+	feedManager  *feeds.Manager
+}
+
+// articleJob returns the job that renders the current article
+func (m Model) articleJob() articleJob {
+	keywords := m.keywordsForFeed(m.currentItem.FeedID)
+	job := articleJob{
+		key: articleKey{
+			itemID:    m.currentItem.ID,
+			content:   itemContent(m.currentItem),
+			raw:       m.showRawHTML,
+			width:     m.width,
+			wrapWidth: m.articleWrapWidth(),
+			theme:     m.themeName(),
+			keywords:  strings.Join(keywords, "\n"),
+			direction: m.articleDirection(),
+			reorder:   m.config.RTLDisplay != config.RTLDisplayTerminal,
+			images:    -1,
+		},
+		link:         m.currentItem.Link,
+		links:        append([]string(nil), m.links...),
+		keywords:     keywords,
+		keywordStyle: m.getKeywordStyle(),
+		linksStyle:   m.getHelpStyle(),
+		feedManager:  m.feedManager,
+	}
+	if m.imageProtocol != termimage.ProtocolNone {
+		job.images = make(map[string]*termimage.Image)
+		for url, image := range m.articleImages {
+			if image != nil {
+				job.images[url] = image
+			}
+		}
+		job.key.images = len(job.images)
+	}
+	return job
+}
+
+// render renders the article with renderer, which the job must have to
+// itself
+func (job articleJob) render(renderer *glamour.TermRenderer) renderedArticle {
+	article := renderedArticle{key: job.key, words: feeds.WordCount(job.key.content)}
+	content := job.key.content
+
+	// If showing raw HTML, apply word wrapping and skip processing
+	if job.key.raw {
+		// Apply word wrap to each line, leaving some margin
+		wrapWidth := max(job.key.width-4, 40)
+		for _, line := range strings.Split(content, "\n") {
+			if line == "" {
+				article.lines = append(article.lines, "")
+			} else {
+				article.lines = append(article.lines, wrapText(line, wrapWidth)...)
+			}
+		}
+		return article
+	}
+
+	// Images that can be drawn get a label to find them by once rendered
+	var numberedImages []string
+	if job.images != nil {
+		content, numberedImages = labelArticleImages(content, job.link, job.images)
+	}
+
+	// Add link markers to HTML BEFORE converting to markdown
+	// This ensures the markers are properly preserved during conversion
+	content, _ = job.feedManager.AddLinkMarkersToHTML(content)
+
+	// Convert HTML to markdown
+	content = job.feedManager.ConvertHTMLToMarkdown(content)
+
+	// Render markdown content using glamour
+	if renderer != nil {
+		renderedContent, err := renderer.Render(content)
+		if err == nil {
+			content = renderedContent
+		}
+	}
+	content = strings.Join(layoutBidi(strings.Split(content, "\n"), job.key.direction, job.key.reorder, job.key.wrapWidth), "\n")
+
+	content = highlightKeywords(content, job.keywords, job.keywordStyle)
+
+	var contentBuilder strings.Builder
+	contentBuilder.WriteString(content)
+	contentBuilder.WriteString("\n\n")
+
+	if len(job.links) > 0 {
+		contentBuilder.WriteString(job.linksStyle.Render("Links:"))
+		contentBuilder.WriteString("\n")
+		for i, link := range job.links {
+			contentBuilder.WriteString(fmt.Sprintf("[%d] %s\n", i+1, link))
+		}
+	}
+
+	// Split content into lines for scrolling
+	article.lines = strings.Split(contentBuilder.String(), "\n")
+	if len(numberedImages) > 0 {
+		article.lines, article.placements = placeArticleImages(article.lines, numberedImages, job.images)
+	}
+	return article
+}
+
+// articleWrapWidth returns the width glamour wraps articles at, including its
+// left margin of two columns: the terminal's width less a margin on the
+// right, at most the Article Max Width setting
+func (m Model) articleWrapWidth() int {
+	if m.width == 0 {
+		return defaultArticleWrapWidth
+	}
+	width := m.width - 2
+	if m.config.ArticleMaxWidth > 0 {
+		width = min(width, m.config.ArticleMaxWidth)
+	}
+	return max(width, minArticleWrapWidth)
+}
+
+// updateGlamourRenderer creates the renderer again for the current theme
+// and wrap width
+func (m *Model) updateGlamourRenderer() {
+	renderer, err := createGlamourRenderer(m.themeName(), m.articleWrapWidth())
+	if err != nil {
+		return
+	}
+	m.glamourRenderer = renderer
+	m.glamourWrapWidth = m.articleWrapWidth()
+}
+
+// renderArticleInBackground renders a large article with a renderer of its
+// own, so keys are handled while glamour works on it
+func renderArticleInBackground(job articleJob) tea.Cmd {
+	return func() tea.Msg {
+		renderer, err := createGlamourRenderer(job.key.theme, job.key.wrapWidth)
+		if err != nil {
+			renderer, _ = glamour.NewTermRenderer()
+		}
+		return ArticleRenderedMsg{Article: job.render(renderer)}
+	}
+}
+
+// updateArticleRender renders the shown article again when anything it is
+// rendered from changed. Small articles are rendered right away, large ones
+// in the background while the article view says they are rendering.
+func (m Model) updateArticleRender(cmd tea.Cmd) (Model, tea.Cmd) {
+	if m.state != ArticleView {
+		return m, cmd
+	}
+	job := m.articleJob()
+	if m.article.key == job.key || m.articleRendering == job.key {
+		return m, cmd
+	}
+	if article, ok := m.articleCache.get(job.key); ok {
+		m.article = article
+		return m, cmd
+	}
+	if len(job.key.content) < backgroundRenderSize {
+		m.article = job.render(m.glamourRenderer)
+		m.articleCache.put(m.article)
+		return m, cmd
+	}
+	m.articleRendering = job.key
+	return m, tea.Batch(cmd, renderArticleInBackground(job))
+}
+
+// articleRendered reports whether the lines of the shown article are
+// rendered, large articles take a moment after they are opened
+func (m Model) articleRendered() bool {
+	return m.state == ArticleView && m.article.key == m.articleJob().key
+}
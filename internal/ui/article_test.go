@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/tasks"
+	"github.com/jarv/newsgoat/internal/termimage"
+)
+
+func TestArticleViewKeysReuseRenderedArticle(t *testing.T) {
+	m := NewModel(feeds.NewManager(nil, nil), tasks.NewManager(1), nil, config.GetDefaultConfig())
+	m.imageProtocol = termimage.ProtocolNone
+	m.width, m.height = 80, 10
+	m.state = ArticleView
+	m.currentItem = database.GetItemsWithReadStatusRow{
+		ID:          1,
+		Title:       "Article",
+		Read:        true,
+		Description: "<h1>Title</h1>" + strings.Repeat("<p>A paragraph of the article.</p>", 40),
+	}
+	m, _ = m.updateArticleRender(nil)
+	if !m.articleRendered() {
+		t.Fatal("article not rendered after opening it")
+	}
+	rendered := &m.article.lines[0]
+
+	// Scrolling only moves over the rendered lines
+	for _, key := range []string{"j", "j", "ctrl+d", "k", "ctrl+u", "g", "G"} {
+		model, _ := m.Update(keyMsgFor(key, false))
+		m = model.(Model)
+		if &m.article.lines[0] != rendered {
+			t.Fatalf("article rendered again after %q", key)
+		}
+	}
+
+	// A new width needs it rendered again
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 10})
+	m = model.(Model)
+	if &m.article.lines[0] == rendered {
+		t.Error("article not rendered again for a new width")
+	}
+}
@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+// articleSearch is a search in the text of the shown article, started with /
+// in the article view
+type articleSearch struct {
+	query   string
+	key     articleKey // Rendered article the hits were found in
+	hits    []articleHit
+	current int // Index of the hit n and p moved to
+}
+
+// articleHit is where the search matched a line of the rendered article,
+// start and end are rune offsets of the line's visible text
+type articleHit struct {
+	line       int
+	start, end int
+}
+
+// findArticleHits returns every match of query in the rendered lines,
+// ignoring case and accents like the global search
+func findArticleHits(lines []string, query string) []articleHit {
+	needle := database.FoldText(query)
+	if needle == "" {
+		return nil
+	}
+	var hits []articleHit
+	for i, line := range lines {
+		runes, _ := splitStyledText(line)
+		for _, r := range matchRanges(string(runes), []string{needle}) {
+			hits = append(hits, articleHit{line: i, start: r[0], end: r[1]})
+		}
+	}
+	return hits
+}
+
+// searchArticle finds the query in the rendered article and shows the first
+// match from the top of the screen on
+func (m Model) searchArticle() Model {
+	m.articleSearch.key = m.article.key
+	m.articleSearch.hits = findArticleHits(m.article.lines, m.articleSearch.query)
+	m.articleSearch.current = 0
+	for i, hit := range m.articleSearch.hits {
+		if hit.line >= m.articleViewScroll {
+			m.articleSearch.current = i
+			break
+		}
+	}
+	return m.showArticleHit()
+}
+
+// showArticleHit scrolls the current match into view, a third of the way
+// down the screen when it has to scroll
+func (m Model) showArticleHit() Model {
+	if len(m.articleSearch.hits) == 0 {
+		return m
+	}
+	line := m.articleSearch.hits[m.articleSearch.current].line
+	availableHeight := max(m.height-3, 1)
+	if line < m.articleViewScroll || line >= m.articleViewScroll+availableHeight {
+		m.articleViewScroll = min(max(line-availableHeight/3, 0), m.articleMaxScroll())
+	}
+	return m
+}
+
+// nextArticleHit moves to the next match, or the previous one when step is
+// -1, going round at either end
+func (m Model) nextArticleHit(step int) Model {
+	if n := len(m.articleSearch.hits); n > 0 {
+		m.articleSearch.current = (m.articleSearch.current + step + n) % n
+	}
+	return m.showArticleHit()
+}
+
+// updateArticleSearch searches the article again once it is rendered anew,
+// after going to another article or resizing the terminal
+func (m Model) updateArticleSearch(cmd tea.Cmd) (Model, tea.Cmd) {
+	if m.articleSearch.query == "" || !m.articleRendered() || m.articleSearch.key == m.article.key {
+		return m, cmd
+	}
+	return m.searchArticle(), cmd
+}
+
+// handleArticleSearchKeys edits the search prompt of the article view,
+// enter searches and an empty search clears the last one
+func (m Model) handleArticleSearchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.articleSearching = false
+		m.articleSearchInput = ""
+		return m, nil
+	case tea.KeyEnter:
+		m.articleSearching = false
+		m.articleSearch = articleSearch{query: m.articleSearchInput}
+		m.articleSearchInput = ""
+		if m.articleSearch.query != "" && m.articleRendered() {
+			m = m.searchArticle()
+		}
+		return m, nil
+	case tea.KeyBackspace:
+		if len(m.articleSearchInput) > 0 {
+			runes := []rune(m.articleSearchInput)
+			m.articleSearchInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.articleSearchInput += " "
+	case tea.KeyRunes:
+		m.articleSearchInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// highlightArticleHits colors the matches on a line of the article, the
+// current one stands out from the others
+func (m Model) highlightArticleHits(line string, lineIndex int) string {
+	var others, current [][2]int
+	for i, hit := range m.articleSearch.hits {
+		if hit.line != lineIndex {
+			continue
+		}
+		if i == m.articleSearch.current {
+			current = append(current, [2]int{hit.start, hit.end})
+		} else {
+			others = append(others, [2]int{hit.start, hit.end})
+		}
+	}
+	line = highlightRanges(line, others, m.getSearchMatchStyle())
+	return highlightRanges(line, current, m.getSearchMatchStyle().Reverse(true))
+}
+
+// articleSearchStatus is the status line of the article view while a search
+// is typed or shown
+func (m Model) articleSearchStatus() string {
+	if m.articleSearching {
+		return "Search article: " + m.articleSearchInput
+	}
+	if m.articleSearch.query == "" {
+		return ""
+	}
+	if len(m.articleSearch.hits) == 0 {
+		return fmt.Sprintf("No matches for %q | esc: clear", m.articleSearch.query)
+	}
+	return fmt.Sprintf("Match %d of %d for %q | n/p: next/prev match | esc: clear", m.articleSearch.current+1, len(m.articleSearch.hits), m.articleSearch.query)
+}
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jarv/newsgoat/internal/logging"
+	"github.com/jarv/newsgoat/internal/themes"
+)
+
+// Terminals that support color palette update notifications (mode 2031)
+// report a switch to a dark or light palette with these sequences. Bubble
+// Tea doesn't know them and hands them on as unknown CSI sequences, which
+// are told apart by how they print.
+var (
+	darkPaletteReport  = fmt.Sprintf("?CSI%+v?", []byte("?997;1n"))
+	lightPaletteReport = fmt.Sprintf("?CSI%+v?", []byte("?997;2n"))
+)
+
+// EnablePaletteUpdates asks the terminal to report when its palette
+// switches between light and dark, terminals without support ignore it
+func EnablePaletteUpdates(w io.Writer) {
+	_, _ = io.WriteString(w, "\x1b[?2031h")
+}
+
+// DisablePaletteUpdates stops the reports EnablePaletteUpdates asked for
+func DisablePaletteUpdates(w io.Writer) {
+	_, _ = io.WriteString(w, "\x1b[?2031l")
+}
+
+// DetectDarkBackground asks the terminal for its background color with
+// OSC 11, falling back to COLORFGBG. It has to be called before the
+// program reads the terminal's input.
+func DetectDarkBackground() bool {
+	return lipgloss.HasDarkBackground()
+}
+
+// colorFGBGDark reports whether COLORFGBG, like "15;0", names a dark
+// background, and whether it is set. The light gray (7) and white (15)
+// backgrounds are light.
+func colorFGBGDark() (bool, bool) {
+	value := os.Getenv("COLORFGBG")
+	i := strings.LastIndexByte(value, ';')
+	if i < 0 {
+		return false, false
+	}
+	background, err := strconv.Atoi(value[i+1:])
+	if err != nil {
+		return false, false
+	}
+	return background != 7 && background != 15, true
+}
+
+// SetDarkBackground sets whether the terminal's background is dark, which
+// the auto theme picks the light or dark theme by
+func (m *Model) SetDarkBackground(dark bool) {
+	m.darkBackground = dark
+	m.backgroundDetected = true
+	m.updateGlamourRenderer()
+}
+
+// themeName returns the theme drawn with, the light or dark one for the
+// auto theme
+func (m Model) themeName() string {
+	return themes.Resolve(m.config.ThemeName, m.darkBackground)
+}
+
+// paletteChanged reports whether msg is a palette update notification and
+// whether the new palette is dark
+func paletteChanged(msg tea.Msg) (dark bool, ok bool) {
+	stringer, isStringer := msg.(fmt.Stringer)
+	if !isStringer {
+		return false, false
+	}
+	switch stringer.String() {
+	case darkPaletteReport:
+		return true, true
+	case lightPaletteReport:
+		return false, true
+	}
+	return false, false
+}
+
+// backgroundChanged switches the auto theme to the terminal's new palette
+func (m Model) backgroundChanged(dark bool) (Model, tea.Cmd) {
+	if dark == m.darkBackground {
+		return m, nil
+	}
+	logging.DebugCategory(logging.CategoryUI, "Terminal palette changed", "dark", dark)
+	m.darkBackground = dark
+	m.backgroundDetected = true
+	if m.config.ThemeName == themes.AutoTheme {
+		m.updateGlamourRenderer()
+	}
+	return m, nil
+}
+
+// autoThemeSelected makes sure the auto theme knows the background when it
+// is picked without it being detected at startup, from COLORFGBG as the
+// terminal can't be asked anymore
+func (m *Model) autoThemeSelected() {
+	if m.backgroundDetected {
+		return
+	}
+	if dark, ok := colorFGBGDark(); ok {
+		m.darkBackground = dark
+	}
+	m.backgroundDetected = true
+}
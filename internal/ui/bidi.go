@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/logging"
+	"golang.org/x/text/unicode/bidi"
+)
+
+// Marks that force the direction of a line for the bidi algorithm
+const (
+	leftToRightMark = '\u200e'
+	rightToLeftMark = '\u200f'
+)
+
+// SetFeedDirections sets the text direction of the feeds with a !direction
+// option in the URLs file
+func (m *Model) SetFeedDirections(entries []config.URLEntry) {
+	directions, errs := config.FeedDirections(entries)
+	for _, err := range errs {
+		logging.Warn("Invalid feed option", "error", err)
+	}
+	m.feedDirections = directions
+}
+
+// articleDirection returns the direction override of the current item's
+// feed, DirectionAuto when it has none
+func (m Model) articleDirection() string {
+	for _, feed := range m.allFeeds {
+		if feed.ID == m.currentItem.FeedID {
+			if direction, ok := m.feedDirections[feed.Url]; ok {
+				return direction
+			}
+			break
+		}
+	}
+	return config.DirectionAuto
+}
+
+// bidiTitle puts the title of the current item in display order when it is
+// right-to-left
+func (m Model) bidiTitle(title string) string {
+	if m.config.RTLDisplay == config.RTLDisplayTerminal || !containsRTL(title) {
+		return title
+	}
+	rtl := m.articleDirection() == config.DirectionRTL
+	if m.articleDirection() == config.DirectionAuto {
+		rtl = firstStrongRTL(title)
+	}
+	return visualOrder(title, rtl)
+}
+
+// isRTLRune reports whether r is a strong right-to-left letter, such as
+// Hebrew or Arabic
+func isRTLRune(r rune) bool {
+	props, _ := bidi.LookupRune(r)
+	class := props.Class()
+	return class == bidi.R || class == bidi.AL
+}
+
+// isLTRRune reports whether r is a strong left-to-right letter
+func isLTRRune(r rune) bool {
+	props, _ := bidi.LookupRune(r)
+	return props.Class() == bidi.L && unicode.IsLetter(r)
+}
+
+// firstStrongRTL reports whether the first letter of text with a direction is
+// right-to-left, which makes the paragraph right-to-left
+func firstStrongRTL(text string) bool {
+	for _, r := range text {
+		if isRTLRune(r) {
+			return true
+		}
+		if isLTRRune(r) {
+			return false
+		}
+	}
+	return false
+}
+
+func containsRTL(text string) bool {
+	return strings.IndexFunc(text, isRTLRune) >= 0
+}
+
+// layoutBidi lays out the rendered lines of an article for a terminal that
+// shows characters left to right in the order they are written. Paragraphs,
+// separated by blank lines, are right-to-left when their first letter is or
+// when direction says so. Their lines are right-aligned and, when reorder is
+// set, put in visual order, within width, the width glamour wrapped them at.
+// Lines were already wrapped in logical order, so
+// each one is reordered on its own like the bidi algorithm asks for.
+// Right-to-left lines lose their styling.
+func layoutBidi(lines []string, direction string, reorder bool, width int) []string {
+	out := make([]string, len(lines))
+	copy(out, lines)
+
+	for start := 0; start < len(lines); {
+		end := start
+		for end < len(lines) && strings.TrimSpace(ansi.Strip(lines[end])) != "" {
+			end++
+		}
+		if end == start {
+			start++
+			continue
+		}
+
+		rtl := direction == config.DirectionRTL
+		if direction == config.DirectionAuto {
+			rtl = firstStrongRTL(ansi.Strip(strings.Join(lines[start:end], " ")))
+		}
+		for i := start; i < end; i++ {
+			plain := ansi.Strip(lines[i])
+			switch {
+			case rtl:
+				text := strings.TrimSpace(plain)
+				if reorder {
+					text = visualOrder(text, true)
+				}
+				pad := width - 2 - ansi.StringWidth(text)
+				out[i] = strings.Repeat(" ", max(pad, 0)) + text
+			case reorder && containsRTL(plain):
+				// Words of a right-to-left script in a left-to-right paragraph
+				out[i] = visualOrder(plain, false)
+			}
+		}
+		start = end
+	}
+	return out
+}
+
+// visualOrder reorders a line written in logical order into the order it is
+// shown in. Right-to-left runs are reversed with their brackets mirrored, and
+// in a right-to-left line the runs themselves are reversed.
+func visualOrder(line string, rtl bool) string {
+	mark := leftToRightMark
+	if rtl {
+		mark = rightToLeftMark
+	}
+	var p bidi.Paragraph
+	if _, err := p.SetString(string(mark) + line); err != nil {
+		return line
+	}
+	ordering, err := p.Order()
+	if err != nil {
+		return line
+	}
+
+	runs := make([]string, ordering.NumRuns())
+	for i := range runs {
+		run := ordering.Run(i)
+		text := strings.TrimPrefix(run.String(), string(mark))
+		if run.Direction() == bidi.RightToLeft {
+			text = bidi.ReverseString(text)
+		}
+		runs[i] = text
+	}
+	if rtl {
+		for i, j := 0, len(runs)-1; i < j; i, j = i+1, j-1 {
+			runs[i], runs[j] = runs[j], runs[i]
+		}
+	}
+	return strings.Join(runs, "")
+}
@@ -2,12 +2,19 @@ package ui
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
 	"os/exec"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/jarv/newsgoat/internal/clipboard"
 	"github.com/jarv/newsgoat/internal/config"
 	"github.com/jarv/newsgoat/internal/database"
 	"github.com/jarv/newsgoat/internal/discovery"
@@ -24,13 +31,54 @@ func loadFeedList(feedManager *feeds.Manager) tea.Cmd {
 			logging.Error("loadFeedList failed", "error", err)
 			return ErrorMsg{Err: err}
 		}
-		return FeedListLoadedMsg{Feeds: feeds}
+		starred, err := feedManager.GetStarredStats()
+		if err != nil {
+			logging.Error("loadFeedList failed", "error", err)
+			return ErrorMsg{Err: err}
+		}
+		folders, err := feedManager.GetFeedFolders()
+		if err != nil {
+			logging.Error("loadFeedList failed", "error", err)
+			return ErrorMsg{Err: err}
+		}
+		// The list is shown without icons when they can't be loaded
+		icons, err := feedManager.GetFeedIcons()
+		if err != nil {
+			logging.Warn("Failed to load feed icons", "error", err)
+		}
+		return FeedListLoadedMsg{Feeds: feeds, Starred: starred, Folders: folders, Icons: icons}
 	}
 }
 
-func loadItemList(feedManager *feeds.Manager, feedID int64) tea.Cmd {
+// loadItemList loads the items of a feed. Lists that are loaded a page at a
+// time load as many rows as loaded were before, at least a page.
+func loadItemList(feedManager *feeds.Manager, feedID int64, cfg config.Config, loaded int) tea.Cmd {
 	return func() tea.Msg {
-		items, err := feedManager.GetItemsWithReadStatus(feedID)
+		if pagedItemList(feedID, cfg) {
+			limit := max(loaded, itemPageSize)
+			items, err := loadItemPage(feedManager, feedID, cfg.UnreadOnTop, int64(limit), 0)
+			if err != nil {
+				logging.Error("loadItemList failed", "feedID", feedID, "error", err)
+				return ErrorMsg{Err: err}
+			}
+			return ItemListLoadedMsg{Items: items, Paged: true, More: len(items) == limit}
+		}
+
+		var items []database.GetItemsWithReadStatusRow
+		var err error
+		switch {
+		case isQueryFeed(feedID):
+			// Narrowed to the query's items when they are loaded
+			items, err = feedManager.GetAllItemsWithReadStatus()
+		case feedID == HotItemsFeedID:
+			items, err = feedManager.GetHotItems(cfg.HotKeywords)
+		case feedID == StarredFeedID:
+			items, err = feedManager.GetStarredItems()
+		case feedID == AllItemsFeedID:
+			items, err = feedManager.GetAllItemsWithReadStatus()
+		default:
+			items, err = feedManager.GetItemsWithReadStatus(feedID)
+		}
 		if err != nil {
 			logging.Error("loadItemList failed", "feedID", feedID, "error", err)
 			return ErrorMsg{Err: err}
@@ -39,14 +87,31 @@ func loadItemList(feedManager *feeds.Manager, feedID int64) tea.Cmd {
 	}
 }
 
-func loadLogList(feedManager *feeds.Manager) tea.Cmd {
+// logPageSize is the number of log messages on a page of the log view
+const logPageSize = 500
+
+func loadLogList(feedManager *feeds.Manager, filter feeds.LogFilter, page int) tea.Cmd {
 	return func() tea.Msg {
-		logs, err := feedManager.GetLogMessages(1000) // Get last 1000 log messages
+		total, err := feedManager.CountFilteredLogMessages(filter)
+		if err != nil {
+			logging.Error("loadLogList failed", "error", err)
+			return ErrorMsg{Err: err}
+		}
+		// The page may be gone after old messages were pruned
+		if lastPage := max(int(total-1)/logPageSize, 0); page > lastPage {
+			page = lastPage
+		}
+		logs, err := feedManager.GetFilteredLogMessages(filter, logPageSize, int64(page*logPageSize))
+		if err != nil {
+			logging.Error("loadLogList failed", "error", err)
+			return ErrorMsg{Err: err}
+		}
+		stored, size, err := feedManager.LogStorage()
 		if err != nil {
 			logging.Error("loadLogList failed", "error", err)
 			return ErrorMsg{Err: err}
 		}
-		return LogListLoadedMsg{Logs: logs}
+		return LogListLoadedMsg{Logs: logs, Page: page, Total: total, Stored: stored, Size: size}
 	}
 }
 
@@ -116,6 +181,15 @@ func refreshFeedAndReload(feedManager *feeds.Manager, feedID int64) tea.Cmd {
 	}
 }
 
+// flushNotifications sends the notification about new items of refreshes
+// that ran outside the task manager
+func flushNotifications(feedManager *feeds.Manager) tea.Cmd {
+	return func() tea.Msg {
+		feedManager.FlushNotifications()
+		return nil
+	}
+}
+
 func refreshAllFeedsConcurrent(feedManager *feeds.Manager) tea.Cmd {
 	return func() tea.Msg {
 		return RefreshAllStartMsg{}
@@ -132,14 +206,56 @@ func markItemRead(feedManager *feeds.Manager, itemID int64) tea.Cmd {
 	}
 }
 
+func fetchFullContent(feedManager *feeds.Manager, itemID int64) tea.Cmd {
+	return func() tea.Msg {
+		content, err := feedManager.FetchFullContent(itemID)
+		if err != nil {
+			logging.Warn("Failed to fetch full article", "itemID", itemID, "error", err)
+		}
+		return FullContentFetchedMsg{ItemID: itemID, Content: content, Err: err}
+	}
+}
+
+func recordItemEvent(feedManager *feeds.Manager, itemID, feedID int64, event string) tea.Cmd {
+	return func() tea.Msg {
+		if err := feedManager.RecordItemEvent(itemID, feedID, event); err != nil {
+			logging.Error("Error recording item event", "itemID", itemID, "event", event, "error", err)
+		}
+		return nil
+	}
+}
+
+// recordSkippedItems records a skip event for every item that was passed over without opening it
+func recordSkippedItems(feedManager *feeds.Manager, skipped map[int64]int64) tea.Cmd {
+	return func() tea.Msg {
+		for itemID, feedID := range skipped {
+			if err := feedManager.RecordItemEvent(itemID, feedID, feeds.ItemEventSkip); err != nil {
+				logging.Error("Error recording item event", "itemID", itemID, "event", feeds.ItemEventSkip, "error", err)
+			}
+		}
+		return nil
+	}
+}
+
+// markItemsRead marks the given items as read, used for lists that aggregate several feeds
+func markItemsRead(feedManager *feeds.Manager, feedID int64, itemIDs []int64) tea.Cmd {
+	return func() tea.Msg {
+		if err := feedManager.MarkItemsRead(itemIDs); err != nil {
+			logging.Error("Error marking items as read", "count", len(itemIDs), "error", err)
+			return ErrorMsg{Err: err}
+		}
+		return AllItemsMarkedReadMsg{FeedID: feedID, ItemIDs: itemIDs}
+	}
+}
+
 func markAllItemsReadInFeed(feedManager *feeds.Manager, feedID int64) tea.Cmd {
 	return func() tea.Msg {
-		err := feedManager.MarkAllItemsReadInFeed(feedID)
+		itemIDs, err := feedManager.MarkAllItemsReadInFeed(feedID)
 		if err != nil {
 			logging.Error("Error marking all items as read", "feedID", feedID, "error", err)
 			return ErrorMsg{Err: err}
 		}
-		return AllItemsMarkedReadMsg{FeedID: feedID}
+		return AllItemsMarkedReadMsg{FeedID: feedID, ItemIDs: itemIDs}
 	}
 }
 
@@ -155,6 +271,7 @@ func markAllItemsReadInFolder(feedManager *feeds.Manager, queries *database.Quer
 		}
 
 		// Mark all items in each feed as read
+		var itemIDs []int64
 		for _, feed := range allFeeds {
 			// Check if this feed is in the folder
 			folders, err := queries.GetFeedFolders(ctx, feed.ID)
@@ -162,20 +279,32 @@ func markAllItemsReadInFolder(feedManager *feeds.Manager, queries *database.Quer
 				continue
 			}
 
-			// Check if folder matches
+			// Check if the feed is in the folder or one of its sub-folders
 			for _, f := range folders {
-				if f == folderName {
+				if inFolder(f, folderName) {
 					// Mark all items in this feed as read
-					if err := feedManager.MarkAllItemsReadInFeed(feed.ID); err != nil {
+					marked, err := feedManager.MarkAllItemsReadInFeed(feed.ID)
+					if err != nil {
 						logging.Error("Error marking feed items as read", "feedID", feed.ID, "error", err)
 					}
+					itemIDs = append(itemIDs, marked...)
 					break
 				}
 			}
 		}
 
-		// Reload feed list to show updated counts
-		return loadFeedList(feedManager)()
+		return AllItemsMarkedReadMsg{ItemIDs: itemIDs}
+	}
+}
+
+// restoreUnread marks items unread again to undo marking them read
+func restoreUnread(feedManager *feeds.Manager, itemIDs []int64) tea.Cmd {
+	return func() tea.Msg {
+		if err := feedManager.MarkItemsUnread(itemIDs); err != nil {
+			logging.Error("Error restoring unread items", "count", len(itemIDs), "error", err)
+			return ErrorMsg{Err: err}
+		}
+		return ItemsRestoredMsg{Count: len(itemIDs)}
 	}
 }
 
@@ -197,6 +326,16 @@ func toggleItemReadStatus(feedManager *feeds.Manager, itemID int64, currentlyRea
 	}
 }
 
+func toggleItemStarred(feedManager *feeds.Manager, itemID int64, currentlyStarred bool) tea.Cmd {
+	return func() tea.Msg {
+		if err := feedManager.SetItemStarred(itemID, !currentlyStarred); err != nil {
+			logging.Error("Error toggling item starred", "itemID", itemID, "error", err)
+			return ErrorMsg{Err: err}
+		}
+		return ItemStarToggledMsg{ItemID: itemID}
+	}
+}
+
 func openLink(url string) tea.Cmd {
 	return func() tea.Msg {
 		var cmd *exec.Cmd
@@ -234,44 +373,163 @@ func countdownTick() tea.Cmd {
 	})
 }
 
-func listenForTaskEvents(taskManager tasks.Manager) tea.Cmd {
+// listenForTaskEvents waits for the next task event, nothing comes once the
+// task manager stopped
+func listenForTaskEvents(events <-chan tasks.TaskEvent) tea.Cmd {
 	return func() tea.Msg {
-		events := taskManager.Subscribe()
-		event := <-events
+		event, ok := <-events
+		if !ok {
+			return nil
+		}
 		return TaskEventMsg{Event: event}
 	}
 }
 
-func waitForReloadTimer(minutes int) tea.Cmd {
-	return tea.Tick(time.Duration(minutes)*time.Minute, func(time.Time) tea.Msg {
-		return ReloadTimerMsg{}
-	})
-}
-
 func restartReloadTimer() tea.Cmd {
 	return func() tea.Msg {
 		return RestartReloadTimerMsg{}
 	}
 }
 
+// runJobs runs scheduled jobs of the task manager now
+func runJobs(taskManager tasks.Manager, names ...string) tea.Cmd {
+	return func() tea.Msg {
+		for _, name := range names {
+			taskManager.RunJob(name)
+		}
+		return nil
+	}
+}
+
+// queueReadLater adds a task that sends an item to the read-later service and
+// returns the status to show while it runs
+func (m Model) queueReadLater(item database.GetItemsWithReadStatusRow) (string, error) {
+	cfg := feeds.NewReadLaterConfig(m.config)
+	if cfg.Service == "" {
+		return "", fmt.Errorf("no read-later service set, choose one in settings (c)")
+	}
+	if item.Link == "" {
+		return "", fmt.Errorf("item has no link to save")
+	}
+	if err := m.taskManager.AddTask(tasks.CreateReadLaterTask(item.Link, item.Title, m.feedTitle(item.FeedID))); err != nil {
+		logging.Error("Failed to queue read later", "error", err)
+		return "", err
+	}
+	return "Sending to " + cfg.ServiceName() + "...", nil
+}
+
 func quitApp(taskManager tasks.Manager) tea.Cmd {
 	return func() tea.Msg {
 		// Stop task manager to cancel all in-progress tasks
 		if err := taskManager.Stop(); err != nil {
-			logging.Debug("Task manager already stopped", "error", err)
+			logging.DebugCategory(logging.CategoryTasks, "Task manager already stopped", "error", err)
 		}
 		return tea.Quit()
 	}
 }
 
-func loadFeedInfo(queries *database.Queries, feedID int64) tea.Cmd {
+func loadFeedInfo(queries *database.Queries, taskManager tasks.Manager, feedID int64) tea.Cmd {
 	return func() tea.Msg {
 		feed, err := queries.GetFeed(context.Background(), feedID)
 		if err != nil {
 			logging.Error("loadFeedInfo failed", "feedID", feedID, "error", err)
 			return ErrorMsg{Err: err}
 		}
-		return FeedInfoLoadedMsg{Feed: feed}
+		msg := FeedInfoLoadedMsg{Feed: feed}
+		if sub, err := queries.GetWebSubSubscription(context.Background(), feedID); err == nil {
+			msg.WebSub = &sub
+		}
+		if msg.ItemCount, err = queries.CountFeedItems(context.Background(), feedID); err != nil {
+			logging.Warn("loadFeedInfo: failed to count items", "feedID", feedID, "error", err)
+		}
+		// A failed refresh waiting to be retried fetches the feed before the schedule
+		taskType, status := tasks.TaskTypeFeedRefresh, tasks.TaskStatusRetrying
+		if retrying, err := taskManager.ListTasks(tasks.TaskFilter{Type: &taskType, Status: &status}); err == nil {
+			for _, task := range retrying {
+				if id, err := tasks.TaskFeedID(task); err == nil && id == feedID && task.NextRetry != nil {
+					msg.RetryAt = *task.NextRetry
+				}
+			}
+		}
+		return msg
+	}
+}
+
+// toggleFeedPaused pauses or resumes a feed, the !pause option is written to
+// the URLs file first so the next sync doesn't undo it
+func toggleFeedPaused(feedManager *feeds.Manager, feed database.GetFeedStatsRow) tea.Cmd {
+	return func() tea.Msg {
+		paused := !feed.Paused
+		title := getDisplayTitle(feed)
+
+		urlsPath, err := config.GetURLsFilePath()
+		if err != nil {
+			return FeedPausedMsg{Title: title, Err: err}
+		}
+		if err := config.SetURLOption(urlsPath, feed.Url, config.OptionPause, paused); err != nil {
+			logging.Error("toggleFeedPaused: failed to update URLs file", "url", feed.Url, "error", err)
+			return FeedPausedMsg{Title: title, Err: err}
+		}
+
+		if paused {
+			err = feedManager.PauseFeed(feed.ID)
+		} else {
+			err = feedManager.ResumeFeed(feed.ID)
+		}
+		if err != nil {
+			logging.Error("toggleFeedPaused failed", "feedID", feed.ID, "error", err)
+			return FeedPausedMsg{Title: title, Err: err}
+		}
+		return FeedPausedMsg{Title: title, Paused: paused}
+	}
+}
+
+// rediscoverFeed looks for the new URL of a failing feed on its site and
+// moves the feed there, in the URLs file first so the next sync doesn't
+// subscribe to the new URL as another feed
+func rediscoverFeed(feedManager *feeds.Manager, feed database.GetFeedStatsRow) tea.Cmd {
+	return func() tea.Msg {
+		msg := FeedRediscoveredMsg{FeedID: feed.ID, Title: getDisplayTitle(feed)}
+		// A feed that redirects permanently already told where it went
+		newURL := feedManager.MovedURL(feed.ID)
+		if newURL == "" {
+			var err error
+			newURL, err = feedManager.RediscoverFeed(feed.Url)
+			if err != nil {
+				msg.Err = err
+				return msg
+			}
+		}
+		msg.URL = newURL
+
+		urlsPath, err := config.GetURLsFilePath()
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+		if err := feedManager.MoveFeed(feed.ID, feed.Url, newURL, urlsPath); err != nil {
+			logging.Error("rediscoverFeed failed", "feedID", feed.ID, "error", err)
+			msg.Err = err
+			return msg
+		}
+		logging.Info("Feed moved to a new URL", "from", feed.Url, "to", newURL)
+
+		msg.URLs, err = config.ReadURLsFileFromPath(urlsPath)
+		if err != nil {
+			msg.Err = err
+		}
+		return msg
+	}
+}
+
+func testFetchFeed(feedManager *feeds.Manager, feedID int64) tea.Cmd {
+	return func() tea.Msg {
+		report, err := feedManager.TestFetch(context.Background(), feedID)
+		if err != nil {
+			logging.Error("testFetchFeed failed", "feedID", feedID, "error", err)
+			return FetchReportLoadedMsg{Err: err}
+		}
+		return FetchReportLoadedMsg{Report: report}
 	}
 }
 
@@ -286,7 +544,14 @@ func reloadURLsFromFile(feedManager *feeds.Manager) tea.Cmd {
 		if pathErr != nil {
 			urlsPath = ""
 		}
-		return URLsReloadedMsg{URLs: urls, FilePath: urlsPath}
+		var queryFeeds []config.QueryFeed
+		if urlsPath != "" {
+			if queryFeeds, err = config.ReadQueryFeedsFromPath(urlsPath); err != nil {
+				logging.Error("reloadURLsFromFile failed", "error", err)
+				return ErrorMsg{Err: err}
+			}
+		}
+		return URLsReloadedMsg{URLs: urls, QueryFeeds: queryFeeds, FilePath: urlsPath}
 	}
 }
 
@@ -316,6 +581,164 @@ func openURLsFileInEditor() tea.Cmd {
 	})
 }
 
+// pipeArticle runs the pipe command through the shell with the article on
+// stdin, the UI is suspended while it runs so pagers like w3m can take over
+// the terminal
+func pipeArticle(feedManager *feeds.Manager, item database.GetItemsWithReadStatusRow, command, format string) tea.Cmd {
+	if strings.TrimSpace(command) == "" {
+		return func() tea.Msg {
+			return ArticlePipedMsg{ItemID: item.ID, Err: fmt.Errorf("no pipe command set, add one in settings (c)")}
+		}
+	}
+
+	content := itemContent(item)
+	if format != config.PipeFormatHTML {
+		content = "# " + item.Title + "\n\n" + item.Link + "\n\n" + feedManager.ConvertHTMLToMarkdown(content)
+	}
+
+	shell := "sh"
+	args := []string{"-c", command}
+	if runtime.GOOS == "windows" {
+		shell = "cmd"
+		args = []string{"/C", command}
+	}
+
+	c := exec.Command(shell, args...)
+	c.Stdin = strings.NewReader(content)
+	// Scripts such as bookmark commands get the article details without parsing stdin
+	c.Env = append(os.Environ(), "NEWSGOAT_TITLE="+item.Title, "NEWSGOAT_URL="+item.Link)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		if err != nil {
+			logging.Error("pipeArticle: pipe command failed", "command", command, "error", err)
+		}
+		return ArticlePipedMsg{ItemID: item.ID, Err: err}
+	})
+}
+
+// saveRawHTML writes the original HTML of an article to a temp file. For a
+// browser it is wrapped in a page whose base is the article link, so
+// relative links and images resolve.
+func saveRawHTML(item database.GetItemsWithReadStatusRow, forBrowser bool) (string, error) {
+	content := itemContent(item)
+	if forBrowser {
+		content = "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n" +
+			"<base href=\"" + html.EscapeString(item.Link) + "\">\n" +
+			"<title>" + html.EscapeString(item.Title) + "</title>\n</head>\n<body>\n" +
+			content + "\n</body>\n</html>\n"
+	}
+
+	f, err := os.CreateTemp("", "newsgoat-article-*.html")
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		_ = f.Close()
+		return "", err
+	}
+	return f.Name(), f.Close()
+}
+
+// viewRawHTML opens the original HTML of an article in $PAGER, NewsGoat is
+// suspended until the pager exits
+func viewRawHTML(item database.GetItemsWithReadStatusRow) tea.Cmd {
+	path, err := saveRawHTML(item, false)
+	if err != nil {
+		return func() tea.Msg {
+			return RawHTMLSavedMsg{ItemID: item.ID, Err: err}
+		}
+	}
+
+	pager := strings.TrimSpace(os.Getenv("PAGER"))
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		if pager == "" {
+			pager = "more"
+		}
+		c = exec.Command("cmd", "/C", pager+` "`+path+`"`)
+	} else {
+		if pager == "" {
+			pager = "less"
+		}
+		// $PAGER may have arguments, the path is passed separately
+		c = exec.Command("sh", "-c", pager+` "$1"`, "sh", path)
+	}
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		if err != nil {
+			logging.Error("viewRawHTML: pager failed", "pager", pager, "error", err)
+		}
+		// The pager is done with the file once it exits
+		if removeErr := os.Remove(path); removeErr != nil {
+			logging.Warn("viewRawHTML: failed to remove the raw HTML file", "path", path, "error", removeErr)
+		}
+		return RawHTMLSavedMsg{ItemID: item.ID, Paged: true, Err: err}
+	})
+}
+
+// browserFiles are the raw HTML files opened in the browser. The browser may
+// read them any time, so they are only removed when NewsGoat exits.
+var (
+	browserFiles      []string
+	browserFilesMutex sync.Mutex
+)
+
+// RemoveBrowserFiles removes the raw HTML files opened in the browser, for
+// when NewsGoat exits
+func RemoveBrowserFiles() {
+	browserFilesMutex.Lock()
+	defer browserFilesMutex.Unlock()
+	for _, path := range browserFiles {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			logging.Warn("Failed to remove a raw HTML file", "path", path, "error", err)
+		}
+	}
+	browserFiles = nil
+}
+
+// openRawHTML opens the original HTML of an article in the browser
+func openRawHTML(item database.GetItemsWithReadStatusRow) tea.Cmd {
+	return func() tea.Msg {
+		path, err := saveRawHTML(item, true)
+		if err != nil {
+			return RawHTMLSavedMsg{ItemID: item.ID, Err: err}
+		}
+		browserFilesMutex.Lock()
+		browserFiles = append(browserFiles, path)
+		browserFilesMutex.Unlock()
+		openLink(path)()
+		return RawHTMLSavedMsg{ItemID: item.ID, Path: path}
+	}
+}
+
+// copyLogMessage copies a log message to the clipboard as a line of the
+// "newsgoat logs export" format, so it can be pasted into a bug report
+func copyLogMessage(log feeds.LogMessage) tea.Cmd {
+	return func() tea.Msg {
+		line, err := json.Marshal(logging.NewExportEntry(log))
+		if err != nil {
+			return LogCopiedMsg{Err: err}
+		}
+		return LogCopiedMsg{Err: clipboard.Copy(string(line))}
+	}
+}
+
+// copyText copies a link or the text of an article to the clipboard, what
+// names it in the status line
+func copyText(what, text string) tea.Cmd {
+	return func() tea.Msg {
+		return ClipboardCopiedMsg{What: what, Err: clipboard.Copy(text)}
+	}
+}
+
+// articleText returns the text of a rendered article without its styling,
+// for copying it
+func articleText(lines []string) string {
+	text := make([]string, len(lines))
+	for i, line := range lines {
+		text[i] = strings.TrimRight(ansi.Strip(line), " ")
+	}
+	return strings.TrimSpace(strings.Join(text, "\n")) + "\n"
+}
+
 func addURLAndDiscover(feedManager *feeds.Manager, input string) tea.Cmd {
 	return func() tea.Msg {
 		// Parse input: URL followed by optional folders
@@ -378,6 +801,23 @@ func syncFeedsWithURLs(feedManager *feeds.Manager, queries *database.Queries, ur
 			urlsFromFileSet[entry.URL] = entry
 		}
 
+		// Credentials stay in memory, they are never written to the database
+		feedAuths, errs := config.FeedAuths(urlEntries)
+		for _, err := range errs {
+			logging.Warn("Ignoring auth option", "error", err)
+		}
+		feedManager.SetFeedAuths(feedAuths)
+		requestOptions, errs := config.FeedRequestOptions(urlEntries)
+		for _, err := range errs {
+			logging.Warn("Ignoring request options", "error", err)
+		}
+		feedManager.SetFeedRequestOptions(requestOptions)
+		feedNotify, errs := config.FeedNotify(urlEntries)
+		for _, err := range errs {
+			logging.Warn("Ignoring notify option", "error", err)
+		}
+		feedManager.SetFeedNotify(feedNotify)
+
 		// Create a set of URLs from DB for quick lookup
 		urlsFromDBSet := make(map[string]bool)
 		for _, feed := range allFeeds {
@@ -439,6 +879,27 @@ func syncFeedsWithURLs(feedManager *feeds.Manager, queries *database.Queries, ur
 					logging.Warn("Failed to add folder", "feed_id", feedID, "folder", folder, "error", err)
 				}
 			}
+
+			// Apply per-feed options
+			if err := feedManager.SetFeedFullText(feedID, entry.HasOption(config.OptionFullText)); err != nil {
+				logging.Warn("Failed to update full-text option", "feed_id", feedID, "error", err)
+			}
+			reloadInterval, err := entry.ReloadInterval()
+			if err != nil {
+				logging.Warn("Ignoring reload interval option", "url", entry.URL, "error", err)
+			}
+			if err := feedManager.SetFeedReloadInterval(feedID, reloadInterval); err != nil {
+				logging.Warn("Failed to update reload interval option", "feed_id", feedID, "error", err)
+			}
+			if err := feedManager.SetFeedEnrich(feedID, entry.HasOption(config.OptionEnrich)); err != nil {
+				logging.Warn("Failed to update enrich option", "feed_id", feedID, "error", err)
+			}
+			if err := feedManager.SetFeedPaused(feedID, entry.HasOption(config.OptionPause)); err != nil {
+				logging.Warn("Failed to update pause option", "feed_id", feedID, "error", err)
+			}
+			if err := feedManager.SetFeedCustomTitle(feedID, entry.Title); err != nil {
+				logging.Warn("Failed to update custom title", "feed_id", feedID, "error", err)
+			}
 		}
 
 		// Reload feed list after syncing
@@ -446,7 +907,7 @@ func syncFeedsWithURLs(feedManager *feeds.Manager, queries *database.Queries, ur
 	}
 }
 
-func performSearch(feedManager *feeds.Manager, viewState ViewState, feedID int64, searchType SearchType, query string) tea.Cmd {
+func performSearch(feedManager *feeds.Manager, viewState ViewState, feedID int64, searchType SearchType, query string, items []database.GetItemsWithReadStatusRow) tea.Cmd {
 	return func() tea.Msg {
 		// If query is empty, return empty results (will restore unfiltered list)
 		if query == "" {
@@ -478,6 +939,15 @@ func performSearch(feedManager *feeds.Manager, viewState ViewState, feedID int64
 				return SearchResultsMsg{FeedResults: converted, IsGlobal: true}
 			}
 		case ItemListView:
+			// Aggregated lists are filtered in place to keep their order
+			if isVirtualFeed(feedID) {
+				results := filterItems(items, searchType, query)
+				if searchType == GlobalSearch {
+					return SearchResultsMsg{ItemResults: results, IsGlobal: true, Matches: findSearchMatches(results, query)}
+				}
+				return SearchResultsMsg{ItemResults: results, IsGlobal: false}
+			}
+
 			// Search items in current feed
 			if searchType == TitleSearch {
 				results, err := feedManager.SearchItemsByTitle(feedID, query)
@@ -498,7 +968,7 @@ func performSearch(feedManager *feeds.Manager, viewState ViewState, feedID int64
 				for i, r := range results {
 					converted[i] = database.SearchItemsByTitleRow(r)
 				}
-				return SearchResultsMsg{ItemResults: converted, IsGlobal: true}
+				return SearchResultsMsg{ItemResults: converted, IsGlobal: true, Matches: findSearchMatches(converted, query)}
 			}
 		}
 
@@ -506,11 +976,29 @@ func performSearch(feedManager *feeds.Manager, viewState ViewState, feedID int64
 	}
 }
 
+// filterItems returns the items matching query, ignoring case and accents
+// like the database search does
+func filterItems(items []database.GetItemsWithReadStatusRow, searchType SearchType, query string) []database.SearchItemsByTitleRow {
+	folded := database.FoldText(query)
+	results := []database.SearchItemsByTitleRow{}
+	for _, item := range items {
+		match := strings.Contains(database.FoldText(item.Title), folded)
+		if !match && searchType == GlobalSearch {
+			match = strings.Contains(database.FoldText(item.Description), folded) ||
+				strings.Contains(database.FoldText(item.Content), folded)
+		}
+		if match {
+			results = append(results, database.SearchItemsByTitleRow(item))
+		}
+	}
+	return results
+}
+
 func checkForUpdate() tea.Cmd {
 	return func() tea.Msg {
 		updateInfo, err := updater.CheckForUpdate()
 		if err != nil {
-			logging.Debug("Update check failed", "error", err)
+			logging.DebugCategory(logging.CategoryHTTP, "Update check failed", "error", err)
 			return UpdateCheckErrorMsg{err: err}
 		}
 		if updateInfo == nil {
@@ -524,14 +1012,3 @@ func checkForUpdate() tea.Cmd {
 		}
 	}
 }
-
-func installUpdate(downloadURL string) tea.Cmd {
-	return func() tea.Msg {
-		err := updater.DownloadAndInstall(downloadURL)
-		if err != nil {
-			logging.Error("Update installation failed", "error", err)
-			return UpdateInstallErrorMsg{err: err}
-		}
-		return UpdateInstallCompleteMsg{}
-	}
-}
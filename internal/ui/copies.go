@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// maxCopiesShown is the number of other feeds named in the "also in" note,
+// so it fits on the title line
+const maxCopiesShown = 3
+
+// ItemCopiesLoadedMsg carries the titles of the other feeds that published
+// the article of an item
+type ItemCopiesLoadedMsg struct {
+	ItemID int64
+	Feeds  []string
+}
+
+func loadItemCopies(feedManager *feeds.Manager, itemID, feedID int64, canonicalLink, contentHash string) tea.Cmd {
+	return func() tea.Msg {
+		copies, err := feedManager.GetItemCopies(feedID, canonicalLink, contentHash)
+		if err != nil {
+			logging.Warn("Failed to find copies of item", "itemID", itemID, "error", err)
+		}
+		var titles []string
+		seen := make(map[int64]bool)
+		for _, c := range copies {
+			if !seen[c.FeedID] {
+				seen[c.FeedID] = true
+				titles = append(titles, c.FeedTitle)
+			}
+		}
+		return ItemCopiesLoadedMsg{ItemID: itemID, Feeds: titles}
+	}
+}
+
+// updateArticleCopies looks up which other feeds published the article
+// when it is shown, for the "also in" note of its title
+func (m Model) updateArticleCopies(cmd tea.Cmd) (Model, tea.Cmd) {
+	if m.state != ArticleView || m.articleCopiesItemID == m.currentItem.ID {
+		return m, cmd
+	}
+	m.articleCopiesItemID = m.currentItem.ID
+	m.articleCopies = nil
+	item := m.currentItem
+	return m, tea.Batch(cmd, loadItemCopies(m.feedManager, item.ID, item.FeedID, item.CanonicalLink, item.ContentHash))
+}
+
+// articleCopiesNote returns the "also in" note of the shown article, empty
+// when no other feed published it
+func (m Model) articleCopiesNote() string {
+	if len(m.articleCopies) == 0 {
+		return ""
+	}
+	titles := m.articleCopies
+	if len(titles) > maxCopiesShown {
+		titles = append(titles[:maxCopiesShown:maxCopiesShown], fmt.Sprintf("%d more", len(m.articleCopies)-maxCopiesShown))
+	}
+	return "also in: " + strings.Join(titles, ", ")
+}
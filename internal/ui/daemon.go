@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jarv/newsgoat/internal/ipc"
+)
+
+// daemonPollInterval is how often a window asks the daemon whether it
+// changed the database
+const daemonPollInterval = 5 * time.Second
+
+// DaemonStatusMsg carries the status polled from the daemon
+type DaemonStatusMsg struct {
+	Status ipc.DaemonStatus
+	Err    error
+}
+
+// SetDaemon leaves refreshing, syncing and cleaning up to the daemon with
+// the given pid, its status is polled on the socket at socketPath
+func (m *Model) SetDaemon(pid int, socketPath string) {
+	m.daemonPID = pid
+	m.daemonSocket = socketPath
+	m.reloadTimerRunning = false
+}
+
+func waitForDaemonStatus(socketPath string) tea.Cmd {
+	return tea.Tick(daemonPollInterval, func(time.Time) tea.Msg {
+		status, err := ipc.GetStatus(socketPath)
+		return DaemonStatusMsg{Status: status, Err: err}
+	})
+}
+
+// daemonStatusUpdated reloads the lists when the daemon changed the
+// database since the last poll, and says once when it went away
+func (m Model) daemonStatusUpdated(msg DaemonStatusMsg) (Model, tea.Cmd) {
+	cmds := []tea.Cmd{waitForDaemonStatus(m.daemonSocket)}
+	if msg.Err != nil {
+		if !m.daemonGone {
+			m.daemonGone = true
+			m.nextReloadTime = time.Time{}
+			m.statusMessage = fmt.Sprintf("The newsgoat daemon (pid %d) stopped, feeds aren't refreshed until it is started again", m.daemonPID)
+			m.statusMessageType = "error"
+		}
+		return m, tea.Batch(cmds...)
+	}
+
+	m.daemonGone = false
+	m.nextReloadTime = msg.Status.NextRefresh
+	if msg.Status.Changes != m.daemonChanges {
+		m.daemonChanges = msg.Status.Changes
+		cmds = append(cmds, func() tea.Msg { return ReadStateChangedMsg{} })
+	}
+	return m, tea.Batch(cmds...)
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/jarv/newsgoat/internal/config"
+)
+
+const (
+	// defaultItemDateLayout is the date of items in the item list when the
+	// Date Format setting is empty
+	defaultItemDateLayout = "01-02"
+	// defaultArticleDateLayout is the date of the shown article when the
+	// Date Format setting is empty
+	defaultArticleDateLayout = "2006-01-02 15:04"
+	// relativeDateWidth fits the longest relative date, such as "11mo"
+	relativeDateWidth = 4
+)
+
+// widestDate is a Wednesday in September, so layouts are measured with the
+// longest day and month names and two digit numbers
+var widestDate = time.Date(2026, time.September, 23, 22, 58, 58, 0, time.Local)
+
+// relativeDate returns how long before now t was, as compactly as "3h" or
+// "2d". Dates in the future are "now".
+func relativeDate(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	case d < 7*24*time.Hour:
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dw", int(d/(7*24*time.Hour)))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dmo", int(d/(30*24*time.Hour)))
+	}
+	return fmt.Sprintf("%dy", int(d/(365*24*time.Hour)))
+}
+
+// itemDateLayout returns the layout of dates in the item list
+func (m Model) itemDateLayout() string {
+	if m.config.DateFormat == "" {
+		return defaultItemDateLayout
+	}
+	return m.config.DateFormat
+}
+
+// itemDate formats a date for the item list, padded to the same width for
+// every item so the titles line up. Items without a date get blanks.
+func (m Model) itemDate(t time.Time, valid bool) string {
+	var date string
+	width := relativeDateWidth
+	if layout := m.itemDateLayout(); layout == config.DateFormatRelative {
+		if valid {
+			date = relativeDate(t, time.Now())
+		}
+	} else {
+		width = ansi.StringWidth(widestDate.Format(layout))
+		if valid {
+			date = t.Local().Format(layout)
+		}
+	}
+	return fmt.Sprintf("%*s", width, date)
+}
+
+// articleDate formats the date of the shown article, "3h ago" when dates are
+// relative
+func (m Model) articleDate(t time.Time) string {
+	switch m.config.DateFormat {
+	case "":
+		return t.Local().Format(defaultArticleDateLayout)
+	case config.DateFormatRelative:
+		date := relativeDate(t, time.Now())
+		if date == "now" {
+			return "just now"
+		}
+		return date + " ago"
+	}
+	return t.Local().Format(m.config.DateFormat)
+}
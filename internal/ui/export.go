@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// exportFormatKeys are the keys picking the format of an exported article
+var exportFormatKeys = map[string]string{
+	"m": feeds.ArticleMarkdown,
+	"h": feeds.ArticleHTML,
+	"p": feeds.ArticlePDF,
+}
+
+// ArticleExportedMsg reports an article being saved to a file
+type ArticleExportedMsg struct {
+	ItemID int64
+	Path   string
+	Err    error
+}
+
+// exportArticle saves an article to the export directory, PDFs are
+// converted by the Export PDF Command
+func exportArticle(feedManager *feeds.Manager, item database.GetItemsWithReadStatusRow, feedTitle string, cfg config.Config, format string) tea.Cmd {
+	return func() tea.Msg {
+		article := feeds.ExportedArticle{
+			Title:   item.Title,
+			Link:    item.Link,
+			Author:  item.Author,
+			Feed:    feedTitle,
+			Content: itemContent(item),
+		}
+		if item.Published.Valid {
+			article.Published = item.Published.Time
+		}
+		path, err := feedManager.ExportArticle(context.Background(), article, exportDir(cfg.ExportDir), cfg.ExportFileName, format, cfg.ExportPDFCommand)
+		if err != nil {
+			logging.Error("exportArticle failed", "itemID", item.ID, "format", format, "error", err)
+		}
+		return ArticleExportedMsg{ItemID: item.ID, Path: path, Err: err}
+	}
+}
+
+// exportDir replaces a leading ~/ of the export directory with the home
+// directory
+func exportDir(dir string) string {
+	if !strings.HasPrefix(dir, "~/") {
+		return dir
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return dir
+	}
+	return filepath.Join(homeDir, dir[2:])
+}
+
+// handleExportPromptKeys picks the format the shown article is exported to
+func (m Model) handleExportPromptKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.exportingArticle = false
+	format, ok := exportFormatKeys[msg.String()]
+	if !ok {
+		m.fullTextStatus = ""
+		return m, nil
+	}
+	m.fullTextStatus = "Exporting..."
+	return m, exportArticle(m.feedManager, m.currentItem, m.feedTitle(m.currentItem.FeedID), m.config, format)
+}
+
+// articleExported reports where the article was saved
+func (m Model) articleExported(msg ArticleExportedMsg) Model {
+	if m.state != ArticleView || msg.ItemID != m.currentItem.ID {
+		return m
+	}
+	if msg.Err != nil {
+		m.fullTextStatus = "Export failed: " + msg.Err.Error()
+	} else {
+		m.fullTextStatus = "Saved to " + msg.Path
+	}
+	return m
+}
@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/logging"
+	"github.com/jarv/newsgoat/internal/tasks"
+)
+
+// FeedIconsLoadedMsg carries the favicon colors by feed host
+type FeedIconsLoadedMsg struct {
+	Icons map[string]string
+}
+
+// glyphIcon is drawn in the color of a site's favicon with the glyph style
+const glyphIcon = "●"
+
+// nerdFontRSS is the Nerd Font icon of feeds from sites without their own
+const nerdFontRSS = "\uf09e"
+
+// nerdFontIcons are the Nerd Font icons of well known sites, by domain
+var nerdFontIcons = []struct {
+	domain string
+	icon   string
+}{
+	{"github.com", "\uf09b"},
+	{"gitlab.com", "\uf296"},
+	{"youtube.com", "\uf16a"},
+	{"reddit.com", "\uf1a1"},
+	{"stackoverflow.com", "\uf16c"},
+	{"stackexchange.com", "\uf18d"},
+	{"news.ycombinator.com", "\uf1d4"},
+	{"hnrss.org", "\uf1d4"},
+	{"medium.com", "\uf23a"},
+	{"twitter.com", "\uf099"},
+	{"x.com", "\uf099"},
+	{"wikipedia.org", "\uf266"},
+}
+
+// loadFeedIcons loads the favicon colors after icons were looked up
+func loadFeedIcons(feedManager *feeds.Manager) tea.Cmd {
+	return func() tea.Msg {
+		icons, err := feedManager.GetFeedIcons()
+		if err != nil {
+			logging.Warn("Failed to load feed icons", "error", err)
+			return nil
+		}
+		return FeedIconsLoadedMsg{Icons: icons}
+	}
+}
+
+// queueFeedIcons looks up the icons of the feeds that don't have one yet,
+// when icons were just turned on
+func queueFeedIcons(feedManager *feeds.Manager, taskManager tasks.Manager, feedList []database.GetFeedStatsRow) tea.Cmd {
+	return func() tea.Msg {
+		for _, feed := range feedList {
+			if !feedManager.NeedsFeedIcon(feed.ID) {
+				continue
+			}
+			if err := taskManager.AddTask(tasks.CreateFeedIconTask(feed.ID, feed.Url)); err != nil {
+				logging.Warn("Failed to queue feed icon lookup", "feedID", feed.ID, "error", err)
+			}
+		}
+		return nil
+	}
+}
+
+// feedIcon returns the icon drawn in front of a feed's title and its color,
+// two spaces keep the titles of feeds without one lined up. It is empty
+// when icons are off.
+func (m Model) feedIcon(feed database.GetFeedStatsRow) (string, string) {
+	if m.config.FeedIcons != config.FeedIconsGlyph && m.config.FeedIcons != config.FeedIconsNerdFont {
+		return "", ""
+	}
+	host := feeds.FeedIconHost(feed.Url)
+	if isVirtualFeed(feed.ID) || host == "" {
+		return "  ", ""
+	}
+
+	color := m.feedIcons[host]
+	if m.config.FeedIcons == config.FeedIconsGlyph {
+		if color == "" {
+			return "  ", ""
+		}
+		return glyphIcon + " ", color
+	}
+	for _, known := range nerdFontIcons {
+		if host == known.domain || strings.HasSuffix(host, "."+known.domain) {
+			return known.icon + " ", color
+		}
+	}
+	return nerdFontRSS + " ", color
+}
+
+// renderFeedIcon draws a feed's icon in its color with the rest of the
+// line's style, so the title after it keeps that style
+func renderFeedIcon(icon, color string, style lipgloss.Style) string {
+	if color != "" {
+		style = style.Foreground(lipgloss.Color(color))
+	}
+	return style.Render(icon)
+}
@@ -0,0 +1,356 @@
+package ui
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/logging"
+	"github.com/jarv/newsgoat/internal/tasks"
+)
+
+// Fields of a feed that can be edited in the feed info
+const (
+	feedInfoTitle = "title"
+	feedInfoURL   = "url"
+)
+
+// FeedRenamedMsg is sent when the custom title of a feed was changed, an
+// empty title goes back to the feed's own
+type FeedRenamedMsg struct {
+	FeedID int64
+	Title  string
+	URLs   []config.URLEntry
+	Err    error
+}
+
+// FeedURLChangedMsg is sent when the URL of a feed was edited in the feed
+// info
+type FeedURLChangedMsg struct {
+	FeedID int64
+	URL    string
+	URLs   []config.URLEntry
+	Err    error
+}
+
+// FeedCacheClearedMsg is sent when the cache of a feed was cleared to
+// refresh it in full
+type FeedCacheClearedMsg struct {
+	FeedID int64
+	URL    string
+	Err    error
+}
+
+// renameFeed writes the custom title of a feed to its line in the URLs file,
+// so the next sync keeps it, and to the database
+func renameFeed(feedManager *feeds.Manager, feed database.Feed, title string) tea.Cmd {
+	return func() tea.Msg {
+		msg := FeedRenamedMsg{FeedID: feed.ID, Title: title}
+		urlsPath, err := config.GetURLsFilePath()
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+		if err := config.SetURLTitle(urlsPath, feed.Url, title); err != nil {
+			logging.Error("renameFeed: failed to update URLs file", "url", feed.Url, "error", err)
+			msg.Err = err
+			return msg
+		}
+		if err := feedManager.SetFeedCustomTitle(feed.ID, title); err != nil {
+			logging.Error("renameFeed failed", "feedID", feed.ID, "error", err)
+			msg.Err = err
+			return msg
+		}
+		msg.URLs, err = config.ReadURLsFileFromPath(urlsPath)
+		if err != nil {
+			msg.Err = err
+		}
+		return msg
+	}
+}
+
+// changeFeedURL moves a feed to the URL typed in the feed info, keeping its
+// items, in the URLs file first so the next sync doesn't subscribe to the
+// new URL as another feed
+func changeFeedURL(feedManager *feeds.Manager, feed database.Feed, newURL string) tea.Cmd {
+	return func() tea.Msg {
+		msg := FeedURLChangedMsg{FeedID: feed.ID, URL: newURL}
+		urlsPath, err := config.GetURLsFilePath()
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+		if err := feedManager.MoveFeed(feed.ID, feed.Url, newURL, urlsPath); err != nil {
+			logging.Error("changeFeedURL failed", "feedID", feed.ID, "error", err)
+			msg.Err = err
+			return msg
+		}
+		logging.Info("Feed URL changed", "from", feed.Url, "to", newURL)
+
+		msg.URLs, err = config.ReadURLsFileFromPath(urlsPath)
+		if err != nil {
+			msg.Err = err
+		}
+		return msg
+	}
+}
+
+// clearFeedCache forgets what the last fetch of a feed told about its
+// cache, before refreshing it
+func clearFeedCache(feedManager *feeds.Manager, feed database.Feed) tea.Cmd {
+	return func() tea.Msg {
+		if err := feedManager.ClearFeedCache(feed.ID); err != nil {
+			logging.Error("clearFeedCache failed", "feedID", feed.ID, "error", err)
+			return FeedCacheClearedMsg{FeedID: feed.ID, Err: err}
+		}
+		return FeedCacheClearedMsg{FeedID: feed.ID, URL: feed.Url}
+	}
+}
+
+// validFeedURL checks a URL typed in the feed info
+func validFeedURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// startFeedInfoEdit opens the prompt of a field of the feed info, filled in
+// with its value
+func (m Model) startFeedInfoEdit(field string) Model {
+	if m.readOnly {
+		m.feedInfoStatus = m.readOnlyMessage()
+		return m
+	}
+	m.editingFeedInfo = field
+	m.feedInfoStatus = ""
+	switch field {
+	case feedInfoTitle:
+		m.feedInfoInput = m.currentFeed.Title
+	case feedInfoURL:
+		m.feedInfoInput = m.currentFeed.Url
+	}
+	return m
+}
+
+// handleFeedInfoEditKeys edits the prompt of the feed info. An empty title
+// goes back to the feed's own, a new URL asks to confirm it first.
+func (m Model) handleFeedInfoEditKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.editingFeedInfo = ""
+		m.feedInfoInput = ""
+		return m, nil
+	case tea.KeyEnter:
+		field := m.editingFeedInfo
+		input := strings.TrimSpace(m.feedInfoInput)
+		m.editingFeedInfo = ""
+		m.feedInfoInput = ""
+		switch field {
+		case feedInfoTitle:
+			if input == m.currentFeed.Title || (input == "" && m.currentFeed.CustomTitle == "") {
+				return m, nil
+			}
+			m.feedInfoStatus = "Renaming..."
+			return m, renameFeed(m.feedManager, m.currentFeed, input)
+		case feedInfoURL:
+			if input == m.currentFeed.Url {
+				return m, nil
+			}
+			if !validFeedURL(input) {
+				m.feedInfoStatus = input + " isn't an http or https URL"
+				return m, nil
+			}
+			m.feedInfoNewURL = input
+		}
+		return m, nil
+	case tea.KeyBackspace:
+		if len(m.feedInfoInput) > 0 {
+			runes := []rune(m.feedInfoInput)
+			m.feedInfoInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.feedInfoInput += " "
+	case tea.KeyRunes:
+		m.feedInfoInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// confirmFeedURL changes the URL of the feed on y, any other key keeps it
+func (m Model) confirmFeedURL(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	newURL := m.feedInfoNewURL
+	m.feedInfoNewURL = ""
+	if msg.String() != "y" {
+		m.feedInfoStatus = "URL not changed"
+		return m, nil
+	}
+	m.feedInfoStatus = "Changing URL..."
+	return m, changeFeedURL(m.feedManager, m.currentFeed, newURL)
+}
+
+// feedInfoChanged loads the changed URLs file like reloading it does and
+// shows the feed info again, refreshing the feed when it needs it
+func (m Model) feedInfoChanged(feedID int64, feedURL string, urls []config.URLEntry, refresh bool) (Model, tea.Cmd) {
+	m.urlsList = urls
+	m.SetFeedDirections(urls)
+	cmds := []tea.Cmd{
+		syncFeedsWithURLs(m.feedManager, m.queries, urls),
+		loadFeedInfo(m.queries, m.taskManager, feedID),
+	}
+	if refresh {
+		var cmd tea.Cmd
+		m, cmd = m.refreshFeedInfoFeed(feedID, feedURL)
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// refreshFeedInfoFeed queues a refresh of the feed in feed info, unless
+// feeds are being refreshed already
+func (m Model) refreshFeedInfoFeed(feedID int64, feedURL string) (Model, tea.Cmd) {
+	if m.refreshing {
+		return m, nil
+	}
+	if err := m.taskManager.AddTask(tasks.CreateFeedRefreshTask(feedID, feedURL)); err != nil {
+		m.feedInfoStatus = "Refresh failed: " + err.Error()
+		return m, nil
+	}
+	m.refreshing = true
+	m.refreshStatus = "Refreshing feed..."
+	return m, func() tea.Msg { return RefreshStartMsg{Status: "Refreshing feed..."} }
+}
+
+// forceRefresh refreshes the feed in feed info right away, fetching and
+// parsing it in full even when its cache says it didn't change. A server
+// that asked to wait with Retry-After is still left alone.
+func (m Model) forceRefresh() (tea.Model, tea.Cmd) {
+	if m.readOnly {
+		m.feedInfoStatus = m.readOnlyMessage()
+		return m, nil
+	}
+	if m.refreshing {
+		m.feedInfoStatus = "Feeds are being refreshed, try again when they are done"
+		return m, nil
+	}
+	if until, reason := feeds.FetchEligibleAt(m.currentFeed, time.Now()); reason == "Retry-After" {
+		m.feedInfoStatus = "The server asked to wait until " + until.Local().Format("15:04") + " (Retry-After)"
+		return m, nil
+	}
+	m.feedInfoStatus = "Refreshing, ignoring the cache..."
+	return m, clearFeedCache(m.feedManager, m.currentFeed)
+}
+
+// feedCacheCleared refreshes the feed whose cache was cleared
+func (m Model) feedCacheCleared(msg FeedCacheClearedMsg) (Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.feedInfoStatus = "Refresh failed: " + msg.Err.Error()
+		return m, nil
+	}
+	return m.refreshFeedInfoFeed(msg.FeedID, msg.URL)
+}
+
+// formatFetchTime shows a time of feed info with how long until it
+func formatFetchTime(t, now time.Time) string {
+	until := t.Sub(now).Round(time.Minute)
+	if until < time.Minute {
+		return t.Local().Format("2006-01-02 15:04:05") + " (now)"
+	}
+	return t.Local().Format("2006-01-02 15:04:05") + " (in " + strings.TrimSuffix(until.String(), "0s") + ")"
+}
+
+// nextFetchDescription tells when the feed in feed info is fetched next: by
+// the auto reload, a retry of a failed refresh, or once its Retry-After or
+// Cache-Control max-age passed, refreshes before that skip it
+func (m Model) nextFetchDescription() string {
+	feed := m.currentFeed
+	if feed.Paused {
+		return "never, the feed is paused"
+	}
+	now := time.Now()
+	eligible, reason := feeds.FetchEligibleAt(feed, now)
+
+	var next time.Time
+	via := "auto reload"
+	if m.refreshJob != nil {
+		if refresh, interval := m.refreshJob.FeedNextRefresh(feed.ID); !refresh.IsZero() {
+			next = feeds.NextFetch(refresh, interval, eligible)
+		}
+	}
+	if retry := m.currentFeedRetryAt; !retry.IsZero() && (next.IsZero() || retry.Before(next)) && !retry.Before(eligible) {
+		next, via = retry, "retry of the failed refresh"
+	}
+
+	switch {
+	case !next.IsZero() && reason != "":
+		return fmt.Sprintf("%s by %s, waiting for the %s", formatFetchTime(next, now), via, reason)
+	case !next.IsZero():
+		return formatFetchTime(next, now) + " by " + via
+	}
+
+	autoReload := "auto reload is off"
+	if m.config.AutoReload && m.config.ReloadTime > 0 {
+		autoReload = "another newsgoat reloads feeds automatically"
+	}
+	if reason != "" {
+		return fmt.Sprintf("not before %s for the %s, %s", formatFetchTime(eligible, now), reason, autoReload)
+	}
+	return "on the next refresh, " + autoReload
+}
+
+// lastFetchDescription tells how the last fetch of a feed went
+func lastFetchDescription(feed database.Feed) string {
+	if feed.LastError.Valid {
+		return "failed at " + formatNullTime(feed.LastErrorTime) + ": " + feed.LastError.String
+	}
+	if !feed.LastUpdated.Valid {
+		return "(not fetched yet)"
+	}
+	if feed.LastResult == "" {
+		return "succeeded at " + formatNullTime(feed.LastUpdated)
+	}
+	return feed.LastResult + " at " + formatNullTime(feed.LastUpdated)
+}
+
+// feedRenamed reports a feed getting a new title, a cleared title gets the
+// feed's own back with a refresh
+func (m Model) feedRenamed(msg FeedRenamedMsg) (Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.feedInfoStatus = "Renaming failed: " + msg.Err.Error()
+		return m, nil
+	}
+	if msg.Title == "" {
+		m.feedInfoStatus = "Custom title removed, refreshing for the feed's own"
+	} else {
+		m.feedInfoStatus = "Renamed to " + msg.Title
+	}
+	return m.feedInfoChanged(msg.FeedID, m.currentFeed.Url, msg.URLs, msg.Title == "")
+}
+
+// feedURLChanged reports a feed moving to the URL typed and refreshes it
+// from there
+func (m Model) feedURLChanged(msg FeedURLChangedMsg) (Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.feedInfoStatus = "Changing URL failed: " + msg.Err.Error()
+		return m, nil
+	}
+	m.feedInfoStatus = "URL changed to " + msg.URL
+	return m.feedInfoChanged(msg.FeedID, msg.URL, msg.URLs, true)
+}
+
+// feedInfoPrompt is the status line of the feed info while a field is
+// edited or a new URL waits to be confirmed
+func (m Model) feedInfoPrompt() string {
+	switch {
+	case m.editingFeedInfo == feedInfoTitle:
+		return "Title (empty uses the feed's own): " + m.feedInfoInput
+	case m.editingFeedInfo == feedInfoURL:
+		return "URL: " + m.feedInfoInput
+	case m.feedInfoNewURL != "":
+		return "Change the URL to " + m.feedInfoNewURL + "? Items and read state are kept (y/n)"
+	}
+	return m.feedInfoStatus
+}
@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"database/sql"
+	"errors"
+	"maps"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// UpdateFeedStatsMsg carries the stats of a feed that was just refreshed
+type UpdateFeedStatsMsg struct {
+	Feed database.GetFeedStatsRow
+}
+
+// loadFeedStats loads the stats of one feed, or the whole feed list when the
+// feed isn't shown anymore
+func loadFeedStats(feedManager *feeds.Manager, feedID int64) tea.Cmd {
+	return func() tea.Msg {
+		feed, err := feedManager.GetFeedStatsByID(feedID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return loadFeedList(feedManager)()
+		}
+		if err != nil {
+			logging.Error("loadFeedStats failed", "feedID", feedID, "error", err)
+			return ErrorMsg{Err: err}
+		}
+		return UpdateFeedStatsMsg{Feed: feed}
+	}
+}
+
+// feedStatsUpdated puts the new stats of a feed in the feed list. Only the
+// feed's rows and the counts of its folders and All Items change, unless the
+// feed moves, shows up or goes away, then the list is built again from the
+// feeds in memory.
+func (m Model) feedStatsUpdated(feed database.GetFeedStatsRow) (Model, tea.Cmd) {
+	index := -1
+	for i := range m.allFeeds {
+		if m.allFeeds[i].ID == feed.ID {
+			index = i
+			break
+		}
+	}
+	// A new feed or a new title changes the order the database returns
+	if index < 0 || m.allFeeds[index].Title != feed.Title {
+		return m, loadFeedList(m.feedManager)
+	}
+	// Earlier copies of the model share the slices and the map, so they are
+	// copied before changing them
+	old := m.allFeeds[index]
+	m.allFeeds = slices.Clone(m.allFeeds)
+	m.allFeeds[index] = feed
+
+	var allTotal int64
+	for _, f := range m.allFeeds {
+		allTotal += f.TotalItems
+	}
+	allTotalBefore := allTotal - feed.TotalItems + old.TotalItems
+	unreadChanged := (old.UnreadItems > 0) != (feed.UnreadItems > 0)
+	if m.feedFilter != nil || (allTotal > 0) != (allTotalBefore > 0) ||
+		(unreadChanged && (!m.config.ShowReadFeeds || m.config.UnreadOnTop)) {
+		m.rebuildFeedList()
+		return m, nil
+	}
+
+	unreadDelta := feed.UnreadItems - old.UnreadItems
+	totalDelta := feed.TotalItems - old.TotalItems
+
+	// Folders only count the feeds that are shown, each once
+	paths := make(map[string]bool)
+	m.folderStats = maps.Clone(m.folderStats)
+	shown := (m.config.ShowReadFeeds || feed.UnreadItems > 0) &&
+		(m.selectedTag == "" || m.hasTag(feed.ID, m.selectedTag))
+	if shown {
+		for _, folder := range m.feedFolders[feed.ID] {
+			for path := folder; path != "" && !paths[path]; path = folderParent(path) {
+				paths[path] = true
+				stats := m.folderStats[path]
+				stats.UnreadItems += unreadDelta
+				stats.TotalItems += totalDelta
+				m.folderStats[path] = stats
+			}
+		}
+	}
+
+	m.feedList = slices.Clone(m.feedList)
+	for i := range m.feedList {
+		item := &m.feedList[i]
+		switch {
+		case item.IsFolder:
+			if paths[item.FolderName] {
+				stats := m.folderStats[item.FolderName]
+				item.UnreadItems, item.TotalItems = stats.UnreadItems, stats.TotalItems
+			}
+		case item.Feed == nil:
+		case item.Feed.ID == feed.ID:
+			feedCopy := feed
+			item.Feed = &feedCopy
+			item.UnreadItems, item.TotalItems = feed.UnreadItems, feed.TotalItems
+		case item.Feed.ID == AllItemsFeedID:
+			all := *item.Feed
+			all.UnreadItems += unreadDelta
+			all.TotalItems += totalDelta
+			item.Feed = &all
+			item.UnreadItems, item.TotalItems = all.UnreadItems, all.TotalItems
+		}
+	}
+	return m, nil
+}
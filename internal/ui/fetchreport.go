@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m Model) handleFetchReportKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc", "ctrl+c":
+		m.state = m.previousState
+		m.fetchReportScroll = 0
+		return m, nil
+
+	case "j", "down":
+		m.fetchReportScroll++
+		return m, nil
+
+	case "k", "up":
+		if m.fetchReportScroll > 0 {
+			m.fetchReportScroll--
+		}
+		return m, nil
+
+	case "ctrl+d":
+		pageSize := m.height / 2
+		if pageSize < 1 {
+			pageSize = 5
+		}
+		m.fetchReportScroll += pageSize
+		return m, nil
+
+	case "ctrl+u":
+		pageSize := m.height / 2
+		if pageSize < 1 {
+			pageSize = 5
+		}
+		m.fetchReportScroll = max(m.fetchReportScroll-pageSize, 0)
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m Model) renderFetchReport() string {
+	report := m.fetchReport
+
+	// Build the full content first
+	var allLines []string
+	allLines = append(allLines, m.getHelpStyle().Render("Nothing was saved, a refresh would do the following"), "")
+
+	allLines = append(allLines, fmt.Sprintf("URL:               %s", report.URL))
+	if report.FinalURL != "" {
+		allLines = append(allLines, fmt.Sprintf("Redirected to:     %s", report.FinalURL))
+	}
+
+	allLines = append(allLines, "", "Request")
+	ifNoneMatch, ifModifiedSince := "(not sent)", "(not sent)"
+	if report.IfNoneMatch != "" {
+		ifNoneMatch = report.IfNoneMatch
+	}
+	if report.IfModifiedSince != "" {
+		ifModifiedSince = report.IfModifiedSince
+	}
+	allLines = append(allLines, fmt.Sprintf("  If-None-Match:     %s", ifNoneMatch))
+	allLines = append(allLines, fmt.Sprintf("  If-Modified-Since: %s", ifModifiedSince))
+	if !report.CachedUntil.IsZero() {
+		allLines = append(allLines, fmt.Sprintf("  Cached until:      %s", report.CachedUntil.Local().Format("2006-01-02 15:04")))
+	}
+
+	if report.StatusCode != 0 {
+		allLines = append(allLines, "", "Response")
+		allLines = append(allLines, fmt.Sprintf("  Status:            %d %s (%s)", report.StatusCode, report.Status, report.Duration.Round(time.Millisecond)))
+		names := make([]string, 0, len(report.ResponseHeaders))
+		for name := range report.ResponseHeaders {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			allLines = append(allLines, fmt.Sprintf("  %-18s %s", name+":", report.ResponseHeaders.Get(name)))
+		}
+	}
+
+	if report.FeedTitle != "" || report.Items > 0 {
+		allLines = append(allLines, "", "Feed")
+		allLines = append(allLines, fmt.Sprintf("  Title:             %s", report.FeedTitle))
+		allLines = append(allLines, fmt.Sprintf("  Format:            %s", report.FeedType))
+		allLines = append(allLines, fmt.Sprintf("  Items:             %d, %d new", report.Items, report.NewCount))
+		for _, title := range report.NewItems {
+			if title == "" {
+				title = "(no title)"
+			}
+			allLines = append(allLines, "    + "+title)
+		}
+		if report.NewCount > len(report.NewItems) {
+			allLines = append(allLines, fmt.Sprintf("    ... and %d more", report.NewCount-len(report.NewItems)))
+		}
+	}
+
+	if report.Err != nil {
+		allLines = append(allLines, "", m.getErrorStyle().Render("Error: "+report.Err.Error()))
+	}
+
+	if len(report.Warnings) > 0 {
+		allLines = append(allLines, "", "Warnings")
+		for _, warning := range report.Warnings {
+			allLines = append(allLines, m.getErrorStyle().Render("  ! "+warning))
+		}
+	}
+
+	// Reserve space for: title (1), empty line (1), status bar (1) = 3 lines
+	availableHeight := m.height - 3
+	if availableHeight < 3 {
+		availableHeight = 3
+	}
+
+	// Ensure scroll doesn't go past the end
+	maxScroll := max(len(allLines)-availableHeight, 0)
+	scroll := min(m.fetchReportScroll, maxScroll)
+
+	start := scroll
+	end := min(start+availableHeight, len(allLines))
+	visibleLines := allLines[start:end]
+
+	var b strings.Builder
+	b.WriteString(m.getTitleStyle().Render("🐐 NewsGoat - Test Fetch"))
+	b.WriteString("\n\n")
+
+	for _, line := range visibleLines {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	// Calculate padding to push status bar to bottom
+	usedLines := 2 + len(visibleLines)
+	padding := max(m.height-usedLines-1, 0)
+	b.WriteString(strings.Repeat("\n", padding))
+
+	if len(allLines) > availableHeight {
+		scrollInfo := fmt.Sprintf("(%d-%d of %d) ", start+1, end, len(allLines))
+		b.WriteString(m.getHelpStyle().Render(scrollInfo))
+	}
+	b.WriteString(m.getHelpStyle().Render("j/k: scroll | esc: return"))
+
+	return b.String()
+}
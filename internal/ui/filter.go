@@ -0,0 +1,233 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/filter"
+)
+
+// feedFilterFields are the fields a feed list filter can use. They can also
+// be written with the feed. prefix of item filters, like feed.folder.
+var feedFilterFields = filter.Fields{
+	"title":        filter.String,
+	"feed.title":   filter.String,
+	"url":          filter.String,
+	"feed.url":     filter.String,
+	"folder":       filter.Strings,
+	"feed.folder":  filter.Strings,
+	"unread":       filter.Bool,
+	"unread_count": filter.Number,
+	"total":        filter.Number,
+	"error":        filter.Bool,
+	"paused":       filter.Bool,
+}
+
+// itemFilterFields are the fields an item list filter can use
+var itemFilterFields = filter.Fields{
+	"title":       filter.String,
+	"content":     filter.String,
+	"link":        filter.String,
+	"feed":        filter.String,
+	"feed.title":  filter.String,
+	"feed.folder": filter.Strings,
+	"unread":      filter.Bool,
+	"read":        filter.Bool,
+	"starred":     filter.Bool,
+	"age":         filter.Duration,
+}
+
+// feedRecord returns the fields of a feed for filtering
+func (m Model) feedRecord(feed database.GetFeedStatsRow) filter.Record {
+	return func(field string) any {
+		switch field {
+		case "title", "feed.title":
+			return feed.Title
+		case "url", "feed.url":
+			return feed.Url
+		case "folder", "feed.folder":
+			return m.feedFolders[feed.ID]
+		case "unread":
+			return feed.UnreadItems > 0
+		case "unread_count":
+			return feed.UnreadItems
+		case "total":
+			return feed.TotalItems
+		case "error":
+			return feed.LastError.Valid && feed.LastError.String != ""
+		case "paused":
+			return feed.Paused
+		}
+		return nil
+	}
+}
+
+// itemRecord returns the fields of an item for filtering
+func (m Model) itemRecord(item database.GetItemsWithReadStatusRow, now time.Time) filter.Record {
+	return func(field string) any {
+		switch field {
+		case "title":
+			return item.Title
+		case "content":
+			return htmlText(itemContent(item))
+		case "link":
+			return item.Link
+		case "feed", "feed.title":
+			return m.feedTitle(item.FeedID)
+		case "feed.folder":
+			return m.feedFolders[item.FeedID]
+		case "unread":
+			return !item.Read
+		case "read":
+			return item.Read
+		case "starred":
+			return item.Starred
+		case "age":
+			if item.Published.Valid {
+				return now.Sub(item.Published.Time)
+			}
+			if item.CreatedAt.Valid {
+				return now.Sub(item.CreatedAt.Time)
+			}
+			return time.Duration(0)
+		}
+		return nil
+	}
+}
+
+// applyItemFilter returns the items matching the item filter, and the filter
+// of the query feed when one is open
+func (m Model) applyItemFilter(items []database.GetItemsWithReadStatusRow) []database.GetItemsWithReadStatusRow {
+	var queryFilter *filter.Filter
+	if isQueryFeed(m.selectedFeed) {
+		query := m.queryFeedFor(m.selectedFeed)
+		if query == nil || query.filter == nil {
+			return nil
+		}
+		queryFilter = query.filter
+	}
+	if m.itemFilter == nil && queryFilter == nil {
+		return items
+	}
+
+	now := time.Now()
+	var filtered []database.GetItemsWithReadStatusRow
+	for _, item := range items {
+		record := m.itemRecord(item, now)
+		if queryFilter != nil && !queryFilter.Match(record) {
+			continue
+		}
+		if m.itemFilter != nil && !m.itemFilter.Match(record) {
+			continue
+		}
+		filtered = append(filtered, item)
+	}
+	return filtered
+}
+
+// filterTitle shows the active filter and tag of the current list next to
+// the title
+func (m Model) filterTitle() string {
+	var title string
+	var active *filter.Filter
+	switch m.state {
+	case FeedListView:
+		active = m.feedFilter
+		if m.selectedTag != "" {
+			title = " - " + m.getHelpStyle().Render("tag: "+m.selectedTag)
+		}
+	case ItemListView:
+		active = m.itemFilter
+	}
+	if active == nil {
+		return title
+	}
+	return title + " - " + m.getHelpStyle().Render("filter: "+active.String())
+}
+
+// handleCommandKeys edits the : prompt of the feed and item lists
+func (m Model) handleCommandKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.commandMode = false
+		m.commandInput = ""
+		return m, nil
+	case "enter":
+		input := m.commandInput
+		m.commandMode = false
+		m.commandInput = ""
+		return m.runCommand(input)
+	case "backspace":
+		if len(m.commandInput) > 0 {
+			runes := []rune(m.commandInput)
+			m.commandInput = string(runes[:len(runes)-1])
+		}
+		return m, nil
+	}
+	switch msg.Type {
+	case tea.KeySpace:
+		m.commandInput += " "
+	case tea.KeyRunes:
+		m.commandInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// runCommand runs a command typed at the : prompt. "filter <expression>"
+// narrows the current list and "filter" alone shows everything again.
+func (m Model) runCommand(input string) (tea.Model, tea.Cmd) {
+	name, args, _ := strings.Cut(strings.TrimSpace(input), " ")
+	switch name {
+	case "":
+		return m, nil
+	case "filter":
+		return m.setFilter(strings.TrimSpace(args))
+	case "sync":
+		if m.readOnly {
+			m.statusMessage = m.readOnlyMessage()
+			m.statusMessageType = "error"
+			return m, nil
+		}
+		m.startSync(true)
+		return m, nil
+	}
+	m.statusMessage = "Unknown command: " + name + ", use filter <expression> or sync"
+	m.statusMessageType = "error"
+	return m, nil
+}
+
+// setFilter narrows the feed or item list to the entries matching expr, an
+// empty expr removes the filter
+func (m Model) setFilter(expr string) (tea.Model, tea.Cmd) {
+	fields := feedFilterFields
+	if m.state == ItemListView {
+		fields = itemFilterFields
+	}
+
+	var parsed *filter.Filter
+	if expr != "" {
+		var err error
+		parsed, err = filter.Parse(expr, fields)
+		if err != nil {
+			m.statusMessage = "Filter: " + err.Error()
+			m.statusMessageType = "error"
+			return m, nil
+		}
+	}
+
+	switch m.state {
+	case FeedListView:
+		m.feedFilter = parsed
+		m.cursor = 0
+		m.savedFeedCursor = 0
+		return m, loadFeedList(m.feedManager)
+	case ItemListView:
+		m.itemFilter = parsed
+		m.cursor = 0
+		m.savedItemCursor = 0
+		return m, loadItemList(m.feedManager, m.selectedFeed, m.config, len(m.loadedItems))
+	}
+	return m, nil
+}
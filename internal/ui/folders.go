@@ -0,0 +1,192 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// FeedFoldersSetMsg is sent when the folders of a feed were changed in the
+// URLs file
+type FeedFoldersSetMsg struct {
+	Title   string
+	Folders []string
+	URLs    []config.URLEntry
+	Err     error
+}
+
+// setFeedFolders writes the folders of a feed to its line in the URLs file,
+// the database is updated from the file like after editing it
+func setFeedFolders(feed database.GetFeedStatsRow, folders []string) tea.Cmd {
+	return func() tea.Msg {
+		msg := FeedFoldersSetMsg{Title: getDisplayTitle(feed), Folders: folders}
+		urlsPath, err := config.GetURLsFilePath()
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+		if err := config.SetURLFolders(urlsPath, feed.Url, folders); err != nil {
+			logging.Error("setFeedFolders: failed to update URLs file", "url", feed.Url, "error", err)
+			msg.Err = err
+			return msg
+		}
+		msg.URLs, err = config.ReadURLsFileFromPath(urlsPath)
+		if err != nil {
+			msg.Err = err
+		}
+		return msg
+	}
+}
+
+// startFolderEdit opens the folder prompt for a feed, filled in with the
+// folders it is in
+func (m Model) startFolderEdit(feed database.GetFeedStatsRow) Model {
+	var folders []string
+	for _, entry := range m.urlsList {
+		if entry.URL == feed.Url {
+			folders = entry.Folders
+		}
+	}
+	m.editingFolders = true
+	m.folderFeed = feed
+	m.folderInput = formatFolders(folders)
+	m.folderCompletions = nil
+	return m
+}
+
+// formatFolders writes folders as they are typed at the folder prompt,
+// quoting names with commas or spaces
+func formatFolders(folders []string) string {
+	names := make([]string, len(folders))
+	for i, folder := range folders {
+		names[i] = quoteFolder(folder)
+	}
+	return strings.Join(names, ", ")
+}
+
+func quoteFolder(folder string) string {
+	if strings.ContainsAny(folder, " ,") {
+		return `"` + strings.ReplaceAll(folder, `"`, "") + `"`
+	}
+	return folder
+}
+
+// folderNames returns every folder in the URLs file, sorted
+func (m Model) folderNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, entry := range m.urlsList {
+		for _, folder := range entry.Folders {
+			if !seen[folder] {
+				seen[folder] = true
+				names = append(names, folder)
+			}
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+	return names
+}
+
+// completeFolder completes the folder being typed, the last one of the
+// input, with the existing folders it is the start of. Pressing tab again
+// goes on to the next match.
+func (m Model) completeFolder() Model {
+	if len(m.folderCompletions) == 0 {
+		// Complete after the last comma that isn't inside quotes
+		start := 0
+		inQuotes := false
+		for i, ch := range m.folderInput {
+			switch ch {
+			case '"':
+				inQuotes = !inQuotes
+			case ',':
+				if !inQuotes {
+					start = i + 1
+				}
+			}
+		}
+		partial := strings.ToLower(strings.Trim(strings.TrimSpace(m.folderInput[start:]), `"`))
+		for _, name := range m.folderNames() {
+			if strings.HasPrefix(strings.ToLower(name), partial) {
+				m.folderCompletions = append(m.folderCompletions, name)
+			}
+		}
+		if len(m.folderCompletions) == 0 {
+			return m
+		}
+		m.folderCompletionBase = m.folderInput[:start]
+		if start > 0 {
+			m.folderCompletionBase = strings.TrimRight(m.folderCompletionBase, " ") + " "
+		}
+		m.folderCompletionIndex = 0
+	} else {
+		m.folderCompletionIndex = (m.folderCompletionIndex + 1) % len(m.folderCompletions)
+	}
+	m.folderInput = m.folderCompletionBase + quoteFolder(m.folderCompletions[m.folderCompletionIndex])
+	return m
+}
+
+// handleFolderKeys edits the folder prompt of the feed list. Enter saves the
+// folders typed, separated by commas, and an empty prompt removes the feed
+// from all folders.
+func (m Model) handleFolderKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type != tea.KeyTab {
+		m.folderCompletions = nil
+	}
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.editingFolders = false
+		m.folderInput = ""
+		return m, nil
+	case tea.KeyEnter:
+		m.editingFolders = false
+		folders := config.ParseFolders(m.folderInput)
+		m.folderInput = ""
+		return m, setFeedFolders(m.folderFeed, folders)
+	case tea.KeyTab:
+		return m.completeFolder(), nil
+	case tea.KeyBackspace:
+		if len(m.folderInput) > 0 {
+			runes := []rune(m.folderInput)
+			m.folderInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.folderInput += " "
+	case tea.KeyRunes:
+		m.folderInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// folderPrompt is the status line while the folders of a feed are edited
+func (m Model) folderPrompt() string {
+	prompt := "Folders of " + getDisplayTitle(m.folderFeed) + " (tab completes, empty removes): " + m.folderInput
+	if len(m.folderCompletions) > 1 {
+		prompt += "  [" + strings.Join(m.folderCompletions, " | ") + "]"
+	}
+	return prompt
+}
+
+// foldersSet reports the folders of a feed being changed and loads the
+// changed URLs file like reloading it does
+func (m Model) foldersSet(msg FeedFoldersSetMsg) (Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.statusMessage = "Setting folders failed: " + msg.Err.Error()
+		m.statusMessageType = "error"
+		return m, nil
+	}
+	if len(msg.Folders) == 0 {
+		m.statusMessage = "Removed " + msg.Title + " from its folders"
+	} else {
+		m.statusMessage = "Moved " + msg.Title + " to " + strings.Join(msg.Folders, ", ")
+	}
+	m.statusMessageType = "info"
+	m.urlsList = msg.URLs
+	return m, syncFeedsWithURLs(m.feedManager, m.queries, msg.URLs)
+}
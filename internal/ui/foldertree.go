@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+// Folders are paths like "Tech/Go", each part is a folder inside the one
+// before it
+
+// folderParent returns the folder a folder is in, "" for top level folders
+func folderParent(folder string) string {
+	if i := strings.LastIndex(folder, "/"); i >= 0 {
+		return folder[:i]
+	}
+	return ""
+}
+
+// folderLeaf returns the name of a folder without the folders it is in
+func folderLeaf(folder string) string {
+	return folder[strings.LastIndex(folder, "/")+1:]
+}
+
+// folderDepth returns how many folders a folder is inside of
+func folderDepth(folder string) int {
+	return strings.Count(folder, "/")
+}
+
+// inFolder reports whether a feed in folder is part of path, the folder
+// itself or one of its sub-folders
+func inFolder(folder, path string) bool {
+	return folder == path || strings.HasPrefix(folder, path+"/")
+}
+
+// folderTree returns the sub-folders of every folder, sorted, with the top
+// level folders under "". Folders that only hold sub-folders are added too.
+func folderTree(folders []string) map[string][]string {
+	children := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, folder := range folders {
+		for path := folder; path != "" && !seen[path]; path = folderParent(path) {
+			seen[path] = true
+			parent := folderParent(path)
+			children[parent] = append(children[parent], path)
+		}
+	}
+	for _, paths := range children {
+		sortFolders(paths)
+	}
+	return children
+}
+
+// sortFolders sorts folders by name, ignoring case and accents
+func sortFolders(folders []string) {
+	sort.SliceStable(folders, func(i, j int) bool {
+		return database.FoldText(folders[i]) < database.FoldText(folders[j])
+	})
+}
@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/themes"
+)
+
+// getKeywordStyle returns the style used for highlight keywords
+func (m Model) getKeywordStyle() lipgloss.Style {
+	theme := themes.GetThemeByName(m.themeName())
+	return lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(theme.KeywordColor))
+}
+
+// keywordsForFeed returns the highlight keywords that apply to items of a feed
+func (m Model) keywordsForFeed(feedID int64) []string {
+	if m.config.HighlightKeywords == "" {
+		return nil
+	}
+	return config.KeywordsForFolders(config.ParseHighlightKeywords(m.config.HighlightKeywords), m.feedFolders[feedID])
+}
+
+// highlightKeywords colors every case-insensitive occurrence of the keywords
+// in text. The text may already be styled with ANSI escape sequences, the
+// styling that was active before a match is restored after it.
+func highlightKeywords(text string, keywords []string, style lipgloss.Style) string {
+	if len(keywords) == 0 || text == "" {
+		return text
+	}
+
+	runes, sequences := splitStyledText(text)
+
+	lowerRunes := make([]rune, len(runes))
+	for i, r := range runes {
+		lowerRunes[i] = unicode.ToLower(r)
+	}
+	lowerKeywords := make([][]rune, 0, len(keywords))
+	for _, keyword := range keywords {
+		if keyword != "" {
+			lowerKeywords = append(lowerKeywords, []rune(strings.ToLower(keyword)))
+		}
+	}
+
+	// Mark where matches end, preferring the longest keyword at each position
+	matchEnd := make(map[int]int)
+	for i := 0; i < len(lowerRunes); {
+		longest := 0
+		for _, keyword := range lowerKeywords {
+			if len(keyword) > longest && hasRunePrefix(lowerRunes[i:], keyword) {
+				longest = len(keyword)
+			}
+		}
+		if longest == 0 {
+			i++
+			continue
+		}
+		matchEnd[i] = i + longest
+		i += longest
+	}
+	return highlightRunes(text, runes, sequences, matchEnd, style)
+}
+
+// highlightRanges colors the given rune ranges of the visible text, which may
+// already be styled like in highlightKeywords
+func highlightRanges(text string, ranges [][2]int, style lipgloss.Style) string {
+	if len(ranges) == 0 || text == "" {
+		return text
+	}
+
+	runes, sequences := splitStyledText(text)
+	matchEnd := make(map[int]int)
+	for _, r := range ranges {
+		start, end := max(r[0], 0), min(r[1], len(runes))
+		if start < end {
+			matchEnd[start] = end
+		}
+	}
+	return highlightRunes(text, runes, sequences, matchEnd, style)
+}
+
+// splitStyledText splits text into visible runes and the escape sequences in
+// front of them, the last sequence trails the text
+func splitStyledText(text string) ([]rune, []string) {
+	var runes []rune
+	var sequences []string // sequences[i] precedes runes[i], the last entry trails the text
+	var pending strings.Builder
+	for i := 0; i < len(text); {
+		if text[i] == '\x1b' && i+1 < len(text) && text[i+1] == '[' {
+			j := i + 2
+			for j < len(text) && (text[j] < 0x40 || text[j] > 0x7e) {
+				j++
+			}
+			if j < len(text) {
+				j++
+			}
+			pending.WriteString(text[i:j])
+			i = j
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(text[i:])
+		runes = append(runes, r)
+		sequences = append(sequences, pending.String())
+		pending.Reset()
+		i += size
+	}
+	sequences = append(sequences, pending.String())
+	return runes, sequences
+}
+
+// highlightRunes styles the runes from each key of matchEnd up to its value
+func highlightRunes(text string, runes []rune, sequences []string, matchEnd map[int]int, style lipgloss.Style) string {
+	if len(matchEnd) == 0 {
+		return text
+	}
+
+	// Render a placeholder to get the escape sequences for the terminal's color profile
+	start, end, ok := strings.Cut(style.Render("\x00"), "\x00")
+	if !ok || start == "" {
+		return text
+	}
+
+	var b strings.Builder
+	var active []string // Styling since the last reset, restored after a match
+	inMatchUntil := -1
+	for i := 0; i <= len(runes); i++ {
+		if i == inMatchUntil {
+			b.WriteString(end)
+			b.WriteString(strings.Join(active, ""))
+			inMatchUntil = -1
+		}
+
+		seq := sequences[i]
+		b.WriteString(seq)
+		active = trackStyling(active, seq)
+		if seq != "" && inMatchUntil > i {
+			b.WriteString(start)
+		}
+
+		if i == len(runes) {
+			break
+		}
+		if matchTo, ok := matchEnd[i]; ok {
+			b.WriteString(start)
+			inMatchUntil = matchTo
+		}
+		b.WriteRune(runes[i])
+	}
+
+	return b.String()
+}
+
+// trackStyling updates the list of styling sequences active after seq
+func trackStyling(active []string, seq string) []string {
+	for seq != "" {
+		next := strings.Index(seq[1:], "\x1b")
+		current := seq
+		if next >= 0 {
+			current = seq[:next+1]
+			seq = seq[next+1:]
+		} else {
+			seq = ""
+		}
+		if current == "\x1b[0m" || current == "\x1b[m" {
+			active = nil
+		} else if strings.HasSuffix(current, "m") {
+			active = append(active, current)
+		}
+	}
+	return active
+}
+
+func hasRunePrefix(runes, prefix []rune) bool {
+	if len(prefix) > len(runes) {
+		return false
+	}
+	for i, r := range prefix {
+		if runes[i] != r {
+			return false
+		}
+	}
+	return true
+}
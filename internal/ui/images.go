@@ -0,0 +1,232 @@
+package ui
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/logging"
+	"github.com/jarv/newsgoat/internal/termimage"
+)
+
+const (
+	// maxArticleImages bounds the images downloaded for one article
+	maxArticleImages = 20
+	// imageCacheMaxAge is how long an image that isn't shown stays cached
+	imageCacheMaxAge = 30 * 24 * time.Hour
+)
+
+var (
+	imgTagPattern  = regexp.MustCompile(`(?is)<img\s[^>]*>`)
+	imgAttrPattern = regexp.MustCompile(`(?is)\s(src|alt)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	// imageLabelPattern finds the labels images are drawn below
+	imageLabelPattern = regexp.MustCompile(`\[image(\d+)`)
+)
+
+// ArticleImageLoadedMsg carries an image of an article, encoded for the
+// terminal. Image is nil when it can't be drawn.
+type ArticleImageLoadedMsg struct {
+	ItemID int64
+	URL    string
+	Image  *termimage.Image
+}
+
+// articleImage is an image of an article
+type articleImage struct {
+	url string // Absolute URL of the image
+	alt string
+}
+
+// imagePlacement is where an image is drawn in the article: above line,
+// over the Rows lines before it
+type imagePlacement struct {
+	line  int
+	image *termimage.Image
+}
+
+// parseImageTag returns the image of an <img> tag, relative sources are
+// resolved against base. Images that aren't on the web, such as data: URLs,
+// aren't drawn.
+func parseImageTag(tag string, base *url.URL) (articleImage, bool) {
+	var image articleImage
+	for _, attr := range imgAttrPattern.FindAllStringSubmatch(tag, -1) {
+		value := html.UnescapeString(attr[2] + attr[3])
+		if strings.EqualFold(attr[1], "src") {
+			image.url = strings.TrimSpace(value)
+		} else {
+			image.alt = strings.Join(strings.Fields(value), " ")
+		}
+	}
+	src, err := url.Parse(image.url)
+	if err != nil || image.url == "" {
+		return image, false
+	}
+	if base != nil {
+		src = base.ResolveReference(src)
+	}
+	if src.Scheme != "http" && src.Scheme != "https" {
+		return image, false
+	}
+	image.url = src.String()
+	return image, true
+}
+
+// findArticleImages returns the images of an article in order, at most
+// maxArticleImages
+func findArticleImages(content, link string) []articleImage {
+	base, _ := url.Parse(link)
+	var images []articleImage
+	for _, tag := range imgTagPattern.FindAllString(content, -1) {
+		if image, ok := parseImageTag(tag, base); ok {
+			images = append(images, image)
+			if len(images) == maxArticleImages {
+				break
+			}
+		}
+	}
+	return images
+}
+
+// labelArticleImages replaces the <img> tags of the images that can be drawn
+// with an "[image1: alt]" label the image is drawn below, the others keep
+// showing their alt text. It returns the URL of every numbered image.
+func labelArticleImages(content, link string, images map[string]*termimage.Image) (string, []string) {
+	base, _ := url.Parse(link)
+	var numbered []string
+	content = imgTagPattern.ReplaceAllStringFunc(content, func(tag string) string {
+		image, ok := parseImageTag(tag, base)
+		if !ok || len(numbered) == maxArticleImages {
+			return tag
+		}
+		numbered = append(numbered, image.url)
+		if images[image.url] == nil {
+			return tag
+		}
+		label := fmt.Sprintf("[image%d]", len(numbered))
+		if image.alt != "" {
+			label = fmt.Sprintf("[image%d: %s]", len(numbered), html.EscapeString(image.alt))
+		}
+		return "<span>" + label + "</span>"
+	})
+	return content, numbered
+}
+
+// placeArticleImages adds blank lines for each labelled image to be drawn
+// over, below the line with its label, and returns where the images go
+func placeArticleImages(lines []string, numbered []string, images map[string]*termimage.Image) ([]string, []imagePlacement) {
+	var placed []string
+	var placements []imagePlacement
+	for _, line := range lines {
+		placed = append(placed, line)
+		for _, match := range imageLabelPattern.FindAllStringSubmatch(ansi.Strip(line), -1) {
+			n, err := strconv.Atoi(match[1])
+			if err != nil || n < 1 || n > len(numbered) {
+				continue
+			}
+			image := images[numbered[n-1]]
+			if image == nil {
+				continue
+			}
+			for range image.Rows {
+				placed = append(placed, "")
+			}
+			// The line below the image draws it, so clearing the rest of
+			// that line doesn't clear part of the image
+			placements = append(placements, imagePlacement{line: len(placed), image: image})
+			placed = append(placed, "")
+		}
+	}
+	return placed, placements
+}
+
+// drawImageAbove returns the escape sequences that draw an image over the
+// lines above the cursor and put the cursor back
+func drawImageAbove(image *termimage.Image) string {
+	// Save the cursor, move up and past the article margin, restore the cursor
+	return fmt.Sprintf("\x1b7\x1b[%dA\x1b[2C%s\x1b8", image.Rows, image.Sequence())
+}
+
+// articleImageSize returns the most columns and lines an image of the article
+// takes, so it is drawn whole within the article text
+func (m Model) articleImageSize() (cols, rows int) {
+	cols = min(m.articleWrapWidth(), m.width) - 4
+	// Room for the label and the line that draws the image
+	rows = m.height - 3 - 2
+	return max(cols, 1), max(rows, 1)
+}
+
+// loadArticleImage downloads an image of an article and encodes it for the
+// terminal
+func loadArticleImage(feedManager *feeds.Manager, itemID int64, imageURL string, protocol termimage.Protocol, cols, rows int, cell termimage.CellSize) tea.Cmd {
+	return func() tea.Msg {
+		data, err := feedManager.FetchImage(imageURL)
+		if err != nil {
+			logging.DebugCategory(logging.CategoryHTTP, "Failed to download article image", "url", imageURL, "error", err)
+			return ArticleImageLoadedMsg{ItemID: itemID, URL: imageURL}
+		}
+		image, err := termimage.Encode(data, protocol, cols, rows, cell)
+		if err != nil {
+			logging.DebugCategory(logging.CategoryUI, "Article image can't be drawn", "url", imageURL, "error", err)
+			return ArticleImageLoadedMsg{ItemID: itemID, URL: imageURL}
+		}
+		return ArticleImageLoadedMsg{ItemID: itemID, URL: imageURL, Image: image}
+	}
+}
+
+// updateArticleImages downloads the images of an article when it is shown
+// and clears the screen when the images move, so none are left behind where
+// the terminal drew them before
+func (m Model) updateArticleImages(cmd tea.Cmd) (Model, tea.Cmd) {
+	cmds := []tea.Cmd{cmd}
+
+	if m.state == ArticleView && !m.showRawHTML {
+		content := itemContent(m.currentItem)
+		if content != m.articleImagesContent {
+			m.articleImagesContent = content
+			m.articleImages = make(map[string]*termimage.Image)
+			cols, rows := m.articleImageSize()
+			for _, image := range findArticleImages(content, m.currentItem.Link) {
+				cmds = append(cmds, loadArticleImage(m.feedManager, m.currentItem.ID, image.url, m.imageProtocol, cols, rows, m.cellSize))
+			}
+		}
+	}
+
+	layout := ""
+	if m.state == ArticleView && !m.showRawHTML {
+		drawn := 0
+		for _, image := range m.articleImages {
+			if image != nil {
+				drawn++
+			}
+		}
+		if drawn > 0 {
+			layout = fmt.Sprintf("%d %d %d %d %d", m.currentItem.ID, drawn, m.articleViewScroll, m.width, m.height)
+		}
+	}
+	if layout != m.articleImagesLayout {
+		m.articleImagesLayout = layout
+		cmds = append(cmds, tea.ClearScreen)
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+// pruneImageCache deletes the article images that weren't shown for a month
+func pruneImageCache(feedManager *feeds.Manager) tea.Cmd {
+	return func() tea.Msg {
+		pruned, err := feedManager.PruneImageCache(imageCacheMaxAge)
+		if err != nil {
+			logging.Warn("Failed to prune image cache", "error", err)
+		} else if pruned > 0 {
+			logging.DebugCategory(logging.CategoryUI, "Pruned image cache", "images", pruned)
+		}
+		return nil
+	}
+}
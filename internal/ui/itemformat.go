@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+// Item list formats used when the Item List Format setting is empty,
+// aggregated lists show which feed an item comes from
+const (
+	defaultItemListFormat        = "%D %t"
+	defaultVirtualItemListFormat = "%D %-16T %t"
+)
+
+// Tokens of the item list format
+const (
+	itemFormatDate   = 'D' // Published date
+	itemFormatFeed   = 'T' // Title of the item's feed
+	itemFormatAuthor = 'a'
+	itemFormatFlags  = 'f' // N when unread
+	itemFormatTitle  = 't' // Title with the star, badges and cluster markers in front
+)
+
+// itemFormatPart is literal text or a token of an item list format
+type itemFormatPart struct {
+	literal string
+	token   byte
+	width   int  // Pads and truncates the token to this many columns, 0 leaves it
+	left    bool // Pads on the right, like %-16T
+}
+
+// parseItemFormat splits an item list format like newsboat's
+// articlelist-format into its parts. A token is % followed by an optional
+// width, "-" aligns it left, and one of the letters above. %% is a percent
+// sign, anything else is kept as written.
+func parseItemFormat(format string) []itemFormatPart {
+	var parts []itemFormatPart
+	var literal strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			literal.WriteByte(format[i])
+			continue
+		}
+		j := i + 1
+		part := itemFormatPart{}
+		if j < len(format) && format[j] == '-' {
+			part.left = true
+			j++
+		}
+		for j < len(format) && format[j] >= '0' && format[j] <= '9' {
+			part.width = part.width*10 + int(format[j]-'0')
+			j++
+		}
+		if j >= len(format) {
+			literal.WriteString(format[i:])
+			break
+		}
+		switch format[j] {
+		case '%':
+			literal.WriteByte('%')
+		case itemFormatDate, itemFormatFeed, itemFormatAuthor, itemFormatFlags, itemFormatTitle:
+			if literal.Len() > 0 {
+				parts = append(parts, itemFormatPart{literal: literal.String()})
+				literal.Reset()
+			}
+			part.token = format[j]
+			parts = append(parts, part)
+		default:
+			literal.WriteString(format[i : j+1])
+		}
+		i = j
+	}
+	if literal.Len() > 0 {
+		parts = append(parts, itemFormatPart{literal: literal.String()})
+	}
+	return parts
+}
+
+// fit pads or truncates a token's value to the part's width
+func (part itemFormatPart) fit(value string) string {
+	if part.width == 0 {
+		return value
+	}
+	value = ansi.Truncate(value, part.width, "")
+	pad := strings.Repeat(" ", part.width-ansi.StringWidth(value))
+	if part.left {
+		return value + pad
+	}
+	return pad + value
+}
+
+// itemListFormat returns the format of the item list rows
+func (m Model) itemListFormat() string {
+	if m.config.ItemListFormat != "" {
+		return m.config.ItemListFormat
+	}
+	if isVirtualFeed(m.selectedFeed) {
+		return defaultVirtualItemListFormat
+	}
+	return defaultItemListFormat
+}
+
+// formatItemLine lays out the row of an item, markers and title make up the
+// title token. titleAt is the rune offset of the title in the row, so matches
+// in the title can be found in it, or -1 when the format has no title.
+func (m Model) formatItemLine(parts []itemFormatPart, item database.GetItemsWithReadStatusRow, markers, title string) (line string, titleAt int) {
+	var b strings.Builder
+	titleAt = -1
+	for _, part := range parts {
+		var value string
+		switch part.token {
+		case 0:
+			b.WriteString(part.literal)
+			continue
+		case itemFormatDate:
+			value = m.itemDate(item.Published.Time, item.Published.Valid)
+		case itemFormatFeed:
+			value = m.feedTitle(item.FeedID)
+		case itemFormatAuthor:
+			value = item.Author
+		case itemFormatFlags:
+			value = " "
+			if !item.Read {
+				value = "N"
+			}
+		case itemFormatTitle:
+			if titleAt < 0 {
+				titleAt = utf8.RuneCountInString(b.String() + markers)
+			}
+			value = markers + title
+		}
+		b.WriteString(part.fit(value))
+	}
+	return b.String(), titleAt
+}
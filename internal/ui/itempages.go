@@ -0,0 +1,180 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// itemPageSize is the number of items read from the database at a time for
+// lists that are loaded a page at a time
+const itemPageSize = 500
+
+// MoreItemsLoadedMsg carries the next page of the item list
+type MoreItemsLoadedMsg struct {
+	FeedID int64
+	Loaded int // Rows that were loaded when the page was asked for
+	Items  []database.GetItemsWithReadStatusRow
+	More   bool // The list has rows after this page
+}
+
+// pagedItemList tells whether the list of feedID is loaded a page at a time.
+// Lists that are deduplicated in memory need all their items at once, story
+// clusters are grouped from the items that are loaded.
+func pagedItemList(feedID int64, cfg config.Config) bool {
+	switch {
+	case feedID == AllItemsFeedID || feedID == StarredFeedID:
+		return !cfg.HideDuplicates
+	case isVirtualFeed(feedID):
+		return false
+	}
+	return true
+}
+
+// loadItemPage reads limit items of a paged list after skipping offset of
+// them, a negative limit reads all the rest
+func loadItemPage(feedManager *feeds.Manager, feedID int64, unreadFirst bool, limit, offset int64) ([]database.GetItemsWithReadStatusRow, error) {
+	switch feedID {
+	case AllItemsFeedID:
+		return feedManager.GetAllItemsWithReadStatusPage(unreadFirst, limit, offset)
+	case StarredFeedID:
+		return feedManager.GetStarredItemsPage(unreadFirst, limit, offset)
+	}
+	return feedManager.GetItemsWithReadStatusPage(feedID, unreadFirst, limit, offset)
+}
+
+// loadedItemIDs maps the IDs of the rows of a page to whether they were unread
+func loadedItemIDs(items []database.GetItemsWithReadStatusRow) map[int64]bool {
+	loaded := make(map[int64]bool, len(items))
+	for _, item := range items {
+		loaded[item.ID] = !item.Read
+	}
+	return loaded
+}
+
+// nextItemOffset is where the next page of the item list starts. Rows added
+// at the top since make it start a bit early, the rows it reads again are
+// skipped. With unread items first the items read since they were loaded
+// moved behind the others, so the page starts early enough for that too.
+func (m Model) nextItemOffset() int {
+	offset := len(m.loadedItems)
+	if !m.config.UnreadOnTop {
+		return offset
+	}
+	items := m.clusterSourceItems
+	if m.searchMode || m.searchActive {
+		items = m.unfilteredItemList
+	}
+	for _, item := range items {
+		if item.Read && m.loadedItems[item.ID] {
+			offset--
+		}
+	}
+	return max(offset, 0)
+}
+
+// loadMoreItems loads the page of the item list at offset, or all the rest
+// with a negative limit
+func (m Model) loadMoreItems(limit int) tea.Cmd {
+	feedManager, feedID, unreadFirst := m.feedManager, m.selectedFeed, m.config.UnreadOnTop
+	loaded, offset := len(m.loadedItems), m.nextItemOffset()
+	return func() tea.Msg {
+		items, err := loadItemPage(feedManager, feedID, unreadFirst, int64(limit), int64(offset))
+		if err != nil {
+			logging.Error("loadMoreItems failed", "feedID", feedID, "offset", offset, "error", err)
+			return ErrorMsg{Err: err}
+		}
+		return MoreItemsLoadedMsg{FeedID: feedID, Loaded: loaded, Items: items, More: limit >= 0 && len(items) == limit}
+	}
+}
+
+// updateItemPaging asks for the next page of the item list when the cursor
+// gets within a screen of the end of what is loaded
+func (m Model) updateItemPaging(cmd tea.Cmd) (Model, tea.Cmd) {
+	if !m.moreItems || m.loadingItems || m.searchMode || m.searchActive {
+		return m, cmd
+	}
+	if m.state != ItemListView && m.state != ArticleView {
+		return m, cmd
+	}
+	if len(m.itemList)-m.cursor > max(m.height, 1) {
+		return m, cmd
+	}
+	m.loadingItems = true
+	return m, tea.Batch(cmd, m.loadMoreItems(itemPageSize))
+}
+
+// loadRestOfItems loads all the items of the list that aren't loaded yet,
+// searching an aggregated list looks at the items in memory
+func (m Model) loadRestOfItems() (Model, tea.Cmd) {
+	if !m.moreItems || m.loadingItems || !isVirtualFeed(m.selectedFeed) {
+		return m, nil
+	}
+	m.loadingItems = true
+	return m, m.loadMoreItems(-1)
+}
+
+// moreItemsLoaded adds a page to the end of the item list, leaving out the
+// rows that are loaded already
+func (m Model) moreItemsLoaded(msg MoreItemsLoadedMsg) (Model, tea.Cmd) {
+	if msg.FeedID != m.selectedFeed || msg.Loaded != len(m.loadedItems) {
+		// The list was left or loaded again meanwhile
+		return m, nil
+	}
+	m.loadingItems = false
+	m.moreItems = msg.More
+
+	var page []database.GetItemsWithReadStatusRow
+	for _, item := range msg.Items {
+		if _, ok := m.loadedItems[item.ID]; !ok {
+			m.loadedItems[item.ID] = !item.Read
+			page = append(page, item)
+		}
+	}
+	searching := m.searchMode || m.searchActive
+	items := m.clusterSourceItems
+	if searching {
+		items = m.unfilteredItemList
+	}
+	items = append(items, m.applyItemFilter(page)...)
+
+	if !searching {
+		m.buildItemDisplayList(items)
+		return m, nil
+	}
+	// Search results stay on screen, the search runs again over all items
+	m.unfilteredItemList = items
+	m.clusterSourceItems = items
+	if m.searchQuery != "" && isVirtualFeed(m.selectedFeed) {
+		return m, performSearch(m.feedManager, m.state, m.selectedFeed, m.searchType, m.searchQuery, m.unfilteredItemList)
+	}
+	return m, nil
+}
+
+// markItemListRead marks the items of an aggregated list read, including
+// the ones that aren't loaded yet
+func (m Model) markItemListRead(itemIDs []int64) tea.Cmd {
+	if !m.moreItems {
+		return markItemsRead(m.feedManager, m.selectedFeed, itemIDs)
+	}
+	feedManager, feedID, unreadFirst, offset := m.feedManager, m.selectedFeed, m.config.UnreadOnTop, m.nextItemOffset()
+	return func() tea.Msg {
+		rest, err := loadItemPage(feedManager, feedID, unreadFirst, -1, int64(offset))
+		if err != nil {
+			logging.Error("Error loading items to mark as read", "feedID", feedID, "error", err)
+			return ErrorMsg{Err: err}
+		}
+		marked := make(map[int64]bool, len(itemIDs))
+		for _, id := range itemIDs {
+			marked[id] = true
+		}
+		for _, item := range m.applyItemFilter(rest) {
+			if !item.Read && !marked[item.ID] {
+				itemIDs = append(itemIDs, item.ID)
+			}
+		}
+		return markItemsRead(feedManager, feedID, itemIDs)()
+	}
+}
@@ -25,11 +25,12 @@ var GlobalKeys = []KeyBinding{
 	{"enter", "select / open"},
 	{"ctrl+d", "page down"},
 	{"ctrl+u", "page up"},
+	{",<key>", "run a macro"},
 }
 
 // View-specific key bindings
 var FeedListViewKeys = ViewKeyBindings{
-	AllowedKeys: []string{"r", "R", "l", "t", "c", "U", "u", "i", "/", "ctrl+f"},
+	AllowedKeys: []string{"r", "R", "l", "t", "c", "U", "u", "i", "p", "F", "H", "K", "S", "n", "N", "/", ":", "ctrl+f", "left", "right"},
 	StatusBar: []KeyBinding{
 		{"/", "search"},
 		{"c", "config"},
@@ -38,7 +39,7 @@ var FeedListViewKeys = ViewKeyBindings{
 }
 
 var ItemListViewKeys = ViewKeyBindings{
-	AllowedKeys: []string{"r", "R", "A", "/", "ctrl+f", "h", "l", "left", "right", "0", "$"},
+	AllowedKeys: []string{"r", "R", "A", "/", ":", "ctrl+f", "h", "l", "left", "right", "0", "$", " ", "s", "b", "y", "tab"},
 	StatusBar: []KeyBinding{
 		{"/", "search"},
 		{"r/R", "reload"},
@@ -47,16 +48,17 @@ var ItemListViewKeys = ViewKeyBindings{
 }
 
 var ArticleViewKeys = ViewKeyBindings{
-	AllowedKeys: []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "n", "N", "o", "r"},
+	AllowedKeys: []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "f", "g", "G", "n", "N", "o", "p", "r", "s", "S", "|", "e", "b", "y", "Y", "/", "tab"},
 	StatusBar: []KeyBinding{
 		{"n/N", "next/prev"},
 	}, // No custom status bar for article view
 }
 
 var SettingsViewKeys = ViewKeyBindings{
-	AllowedKeys: []string{"?"},
+	AllowedKeys: []string{"?", "P"},
 	StatusBar: []KeyBinding{
 		{"?", "settings help"},
+		{"P", "prune now"},
 	},
 }
 
@@ -68,13 +70,23 @@ var TasksViewKeys = ViewKeyBindings{
 }
 
 var FeedInfoViewKeys = ViewKeyBindings{
-	AllowedKeys: []string{},
-	StatusBar:   []KeyBinding{},
+	AllowedKeys: []string{"t", "e", "r", "l"},
+	StatusBar: []KeyBinding{
+		{Key: "t", Description: "rename"},
+		{Key: "e", Description: "edit URL"},
+		{Key: "r", Description: "refresh ignoring cache"},
+		{Key: "l", Description: "logs"},
+	},
 }
 
 var LogViewKeys = ViewKeyBindings{
-	AllowedKeys: []string{"c"},
+	AllowedKeys: []string{"c", "y", "n", "p", "e", "w", "i", "d", "/", "f"},
 	StatusBar: []KeyBinding{
+		{Key: "e/w/i/d", Description: "levels"},
+		{Key: "/", Description: "filter"},
+		{Key: "f", Description: "follow"},
+		{Key: "y", Description: "copy"},
+		{Key: "n/p", Description: "page"},
 		{Key: "A", Description: "clear all"},
 	},
 }
@@ -0,0 +1,704 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// KeyScope groups the actions that are active at the same time, global
+// actions are active in every view
+type KeyScope string
+
+const (
+	ScopeGlobal  KeyScope = "global"
+	ScopeFeeds   KeyScope = "feeds"
+	ScopeItems   KeyScope = "items"
+	ScopeArticle KeyScope = "article"
+	ScopeTasks   KeyScope = "tasks"
+	ScopeLogs    KeyScope = "logs"
+)
+
+// Action is something keys can be bound to. The first default key is the
+// one the view's key handler switches on, pressed keys are translated to it
+type Action struct {
+	Name        string // "<scope>.<name>", as written in the keys file
+	Scope       KeyScope
+	Description string
+	Defaults    []string
+}
+
+// Actions lists every action that can be rebound
+var Actions = []Action{
+	{"global.help", ScopeGlobal, "Show help", []string{"?"}},
+	{"global.quit", ScopeGlobal, "Quit / go back", []string{"q"}},
+	{"global.back", ScopeGlobal, "Go back", []string{"esc"}},
+	{"global.down", ScopeGlobal, "Move down", []string{"j", "down"}},
+	{"global.up", ScopeGlobal, "Move up", []string{"k", "up"}},
+	{"global.page_down", ScopeGlobal, "Page down", []string{"ctrl+d"}},
+	{"global.page_up", ScopeGlobal, "Page up", []string{"ctrl+u"}},
+	{"global.select", ScopeGlobal, "Select / open", []string{"enter"}},
+
+	{"feeds.refresh", ScopeFeeds, "Refresh selected feed or folder", []string{"r"}},
+	{"feeds.refresh_all", ScopeFeeds, "Refresh all feeds", []string{"R"}},
+	{"feeds.next_unread", ScopeFeeds, "Jump to the next feed with unread items", []string{"n"}},
+	{"feeds.prev_unread", ScopeFeeds, "Jump to the previous feed with unread items", []string{"N"}},
+	{"feeds.mark_all_read", ScopeFeeds, "Mark all items in feed/folder as read", []string{"A"}},
+	{"feeds.info", ScopeFeeds, "Show feed info", []string{"i"}},
+	{"feeds.pause", ScopeFeeds, "Pause/resume refreshing the selected feed", []string{"p"}},
+	{"feeds.folders", ScopeFeeds, "Set the folders of the selected feed", []string{"f"}},
+	{"feeds.tags", ScopeFeeds, "Show only the feeds of a folder (tag)", []string{"T"}},
+	{"feeds.rules", ScopeFeeds, "Rules hiding or marking read items at refresh", []string{"X"}},
+	{"feeds.stats", ScopeFeeds, "Reading statistics and feed activity", []string{"S"}},
+	{"feeds.test_fetch", ScopeFeeds, "Test fetch the selected feed without saving", []string{"F"}},
+	{"feeds.rediscover", ScopeFeeds, "Move a redirecting or failing feed to its new URL", []string{"D"}},
+	{"feeds.update", ScopeFeeds, "Update to the new version, when one is available (ctrl+u too)", []string{"ctrl+g"}},
+	{"feeds.hot", ScopeFeeds, "Hot items", []string{"H"}},
+	{"feeds.search", ScopeFeeds, "Global search", []string{"/"}},
+	{"feeds.title_search", ScopeFeeds, "Title search", []string{"ctrl+f"}},
+	{"feeds.filter", ScopeFeeds, "Command prompt (:filter <expression>, :sync)", []string{":"}},
+	{"feeds.add_url", ScopeFeeds, "Add URL", []string{"u"}},
+	{"feeds.edit_urls", ScopeFeeds, "Edit URLs in $EDITOR", []string{"U"}},
+	{"feeds.reload_urls", ScopeFeeds, "Reload URLs from file", []string{"ctrl+r"}},
+	{"feeds.logs", ScopeFeeds, "View logs", []string{"l"}},
+	{"feeds.tasks", ScopeFeeds, "View tasks", []string{"t"}},
+	{"feeds.settings", ScopeFeeds, "View settings", []string{"c"}},
+	{"feeds.keymap", ScopeFeeds, "View and rebind key bindings", []string{"K"}},
+	{"feeds.prev_column", ScopeFeeds, "Previous column (columns layout)", []string{"left"}},
+	{"feeds.next_column", ScopeFeeds, "Next column (columns layout)", []string{"right"}},
+
+	{"items.refresh", ScopeItems, "Refresh feed", []string{"r"}},
+	{"items.mark_all_read", ScopeItems, "Mark all items as read", []string{"A"}},
+	{"items.toggle_read", ScopeItems, "Toggle read status of item", []string{"N"}},
+	{"items.mark_read_next", ScopeItems, "Mark item read and move to the next one", []string{"m"}},
+	{"items.mark_above_read", ScopeItems, "Mark all items above the cursor as read", []string{"M"}},
+	{"items.next_unread", ScopeItems, "Jump to the next unread item, in the next feeds when there are none left", []string{"tab"}},
+	{"items.star", ScopeItems, "Star/unstar item", []string{"s"}},
+	{"items.read_later", ScopeItems, "Send item to read-later service", []string{"b"}},
+	{"items.copy_link", ScopeItems, "Copy item link to clipboard", []string{"y"}},
+	{"items.open_link", ScopeItems, "Open item link in browser", []string{"o"}},
+	{"items.expand_cluster", ScopeItems, "Expand/collapse story cluster", []string{" "}},
+	{"items.scroll_left", ScopeItems, "Scroll title left", []string{"h", "left"}},
+	{"items.scroll_right", ScopeItems, "Scroll title right", []string{"l", "right"}},
+	{"items.scroll_start", ScopeItems, "Jump to start of title", []string{"0"}},
+	{"items.scroll_end", ScopeItems, "Jump to end of title", []string{"$"}},
+	{"items.search", ScopeItems, "Global search", []string{"/"}},
+	{"items.title_search", ScopeItems, "Title search", []string{"ctrl+f"}},
+	{"items.filter", ScopeItems, "Command prompt (:filter <expression>, :sync)", []string{":"}},
+	{"items.settings", ScopeItems, "View settings", []string{"c"}},
+	{"items.tasks", ScopeItems, "View tasks", []string{"t"}},
+
+	{"article.next", ScopeArticle, "Next article, or next match of the article search", []string{"n"}},
+	{"article.prev", ScopeArticle, "Previous article", []string{"N"}},
+	{"article.next_unread", ScopeArticle, "Open the next unread article, in the next feeds when there are none left", []string{"tab"}},
+	{"article.top", ScopeArticle, "Jump to the top of the article", []string{"g"}},
+	{"article.bottom", ScopeArticle, "Jump to the bottom of the article", []string{"G"}},
+	{"article.search", ScopeArticle, "Search the article", []string{"/"}},
+	{"article.prev_match", ScopeArticle, "Previous match of the article search", []string{"p"}},
+	{"article.open_link", ScopeArticle, "Open article link in browser", []string{"o"}},
+	{"article.raw", ScopeArticle, "Toggle raw HTML view", []string{"r"}},
+	{"article.raw_pager", ScopeArticle, "View raw HTML in $PAGER", []string{"R"}},
+	{"article.raw_browser", ScopeArticle, "Open raw HTML in browser", []string{"O"}},
+	{"article.full_text", ScopeArticle, "Fetch full article from the link", []string{"f"}},
+	{"article.star", ScopeArticle, "Star/unstar article", []string{"s"}},
+	{"article.pipe", ScopeArticle, "Pipe article to the pipe command", []string{"|"}},
+	{"article.share", ScopeArticle, "Share article with a share command", []string{"S"}},
+	{"article.export", ScopeArticle, "Export article to a markdown, HTML or PDF file", []string{"e"}},
+	{"article.read_later", ScopeArticle, "Send article to read-later service", []string{"b"}},
+	{"article.copy_link", ScopeArticle, "Copy article link to clipboard", []string{"y"}},
+	{"article.copy_text", ScopeArticle, "Copy article text to clipboard", []string{"Y"}},
+	{"article.settings", ScopeArticle, "View settings", []string{"c"}},
+	{"article.tasks", ScopeArticle, "View tasks", []string{"t"}},
+
+	{"tasks.clear_failed", ScopeTasks, "Clear all failed tasks", []string{"A"}},
+	{"tasks.remove", ScopeTasks, "Remove selected task", []string{"D"}},
+	{"tasks.reload", ScopeTasks, "Reload the task list", []string{"r"}},
+
+	{"logs.clear", ScopeLogs, "Clear all log messages", []string{"A"}},
+	{"logs.copy", ScopeLogs, "Copy log message to clipboard", []string{"y"}},
+	{"logs.next_page", ScopeLogs, "Next page of older log messages", []string{"n"}},
+	{"logs.prev_page", ScopeLogs, "Previous page of newer log messages", []string{"p"}},
+	{"logs.toggle_error", ScopeLogs, "Show/hide error messages", []string{"e"}},
+	{"logs.toggle_warn", ScopeLogs, "Show/hide warning messages", []string{"w"}},
+	{"logs.toggle_info", ScopeLogs, "Show/hide info messages", []string{"i"}},
+	{"logs.toggle_debug", ScopeLogs, "Show/hide debug messages", []string{"d"}},
+	{"logs.filter", ScopeLogs, "Filter log messages by text", []string{"/"}},
+	{"logs.follow", ScopeLogs, "Follow new log messages", []string{"f"}},
+}
+
+// Keymap holds the keys bound to each action
+type Keymap struct {
+	bindings map[string][]string
+}
+
+// KeyConflict is a key bound to several actions that are active together
+type KeyConflict struct {
+	Scope   KeyScope
+	Key     string
+	Actions []string
+}
+
+// DefaultKeymap binds every action to its default keys
+func DefaultKeymap() Keymap {
+	k := Keymap{bindings: make(map[string][]string, len(Actions))}
+	for _, action := range Actions {
+		k.bindings[action.Name] = action.Defaults
+	}
+	return k
+}
+
+func findAction(name string) (Action, bool) {
+	for _, action := range Actions {
+		if action.Name == name {
+			return action, true
+		}
+	}
+	return Action{}, false
+}
+
+// Keys returns the keys bound to an action
+func (k Keymap) Keys(action string) []string {
+	return k.bindings[action]
+}
+
+// Bind replaces the keys of an action
+func (k Keymap) Bind(action string, keys []string) error {
+	if _, ok := findAction(action); !ok {
+		return fmt.Errorf("unknown action %q", action)
+	}
+	k.bindings[action] = keys
+	return nil
+}
+
+// scopeForView returns the scope of the actions active in a view besides the
+// global ones
+func scopeForView(state ViewState) KeyScope {
+	switch state {
+	case FeedListView:
+		return ScopeFeeds
+	case ItemListView:
+		return ScopeItems
+	case ArticleView:
+		return ScopeArticle
+	case TasksView:
+		return ScopeTasks
+	case LogView, LogDetailView:
+		return ScopeLogs
+	}
+	return ScopeGlobal
+}
+
+// activeActions returns the actions of a scope followed by the global ones,
+// so a view's own bindings win over global ones
+func activeActions(scope KeyScope) []Action {
+	var actions []Action
+	for _, action := range Actions {
+		if action.Scope == scope && scope != ScopeGlobal {
+			actions = append(actions, action)
+		}
+	}
+	for _, action := range Actions {
+		if action.Scope == ScopeGlobal {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// Resolve translates a pressed key to the default key of the action it is
+// bound to. Keys not bound to anything are passed through, unless they are
+// the default of an action that was bound to other keys, then ok is false
+func (k Keymap) Resolve(scope KeyScope, key string) (resolved string, ok bool) {
+	actions := activeActions(scope)
+	for _, action := range actions {
+		for _, bound := range k.bindings[action.Name] {
+			if bound != key {
+				continue
+			}
+			for _, def := range action.Defaults {
+				if def == key {
+					return key, true
+				}
+			}
+			return action.Defaults[0], true
+		}
+	}
+
+	for _, action := range actions {
+		for _, def := range action.Defaults {
+			if def == key {
+				return "", false
+			}
+		}
+	}
+	return key, true
+}
+
+// Conflicts returns the keys bound to more than one action in the same
+// scope, or shadowing a global action
+func (k Keymap) Conflicts() []KeyConflict {
+	var conflicts []KeyConflict
+	for _, scope := range []KeyScope{ScopeGlobal, ScopeFeeds, ScopeItems, ScopeArticle, ScopeTasks, ScopeLogs} {
+		actionsByKey := make(map[string][]string)
+		for _, action := range activeActions(scope) {
+			for _, key := range k.bindings[action.Name] {
+				actionsByKey[key] = append(actionsByKey[key], action.Name)
+			}
+		}
+		for key, actions := range actionsByKey {
+			if len(actions) > 1 {
+				conflicts = append(conflicts, KeyConflict{Scope: scope, Key: key, Actions: actions})
+			}
+		}
+	}
+
+	// A global conflict shows up in every scope, only report it once
+	seen := make(map[string]bool)
+	var unique []KeyConflict
+	for _, conflict := range conflicts {
+		id := conflict.Key + "=" + strings.Join(conflict.Actions, ",")
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, conflict)
+	}
+
+	sort.SliceStable(unique, func(i, j int) bool {
+		if unique[i].Scope != unique[j].Scope {
+			return unique[i].Scope < unique[j].Scope
+		}
+		return unique[i].Key < unique[j].Key
+	})
+	return unique
+}
+
+// conflictingActions returns the names of actions that share a key with
+// another action
+func (k Keymap) conflictingActions() map[string]bool {
+	conflicting := make(map[string]bool)
+	for _, conflict := range k.Conflicts() {
+		for _, action := range conflict.Actions {
+			conflicting[action] = true
+		}
+	}
+	return conflicting
+}
+
+// boundTo returns the action other than action that key is bound to among
+// the actions active together with it, or "" when the key is free
+func (k Keymap) boundTo(action Action, key string) string {
+	for _, other := range Actions {
+		if other.Name == action.Name {
+			continue
+		}
+		if other.Scope != action.Scope && other.Scope != ScopeGlobal && action.Scope != ScopeGlobal {
+			continue
+		}
+		for _, bound := range k.bindings[other.Name] {
+			if bound == key {
+				return other.Name
+			}
+		}
+	}
+	return ""
+}
+
+// isDefault reports whether an action is bound to its default keys
+func (k Keymap) isDefault(action Action) bool {
+	return strings.Join(k.bindings[action.Name], " ") == strings.Join(action.Defaults, " ")
+}
+
+// changedCount returns how many actions aren't bound to their defaults
+func (k Keymap) changedCount() int {
+	changed := 0
+	for _, action := range Actions {
+		if !k.isDefault(action) {
+			changed++
+		}
+	}
+	return changed
+}
+
+// saveBinding writes the keys of an action to the keys file, replacing the
+// action's line or removing it when the keys are the defaults. Comments and
+// the other lines are kept.
+func saveBinding(path string, action Action, keys []string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var line string
+	if strings.Join(keys, " ") != strings.Join(action.Defaults, " ") {
+		names := make([]string, 0, len(keys))
+		for _, key := range keys {
+			names = append(names, formatKeyName(key))
+		}
+		line = action.Name + " " + strings.Join(names, " ")
+	}
+
+	var lines []string
+	if len(data) > 0 {
+		lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	}
+	replaced := false
+	kept := lines[:0]
+	for _, existing := range lines {
+		fields := strings.Fields(existing)
+		if len(fields) > 0 && fields[0] == action.Name {
+			if line != "" && !replaced {
+				kept = append(kept, line)
+			}
+			replaced = true
+			continue
+		}
+		kept = append(kept, existing)
+	}
+	if !replaced && line != "" {
+		kept = append(kept, line)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	content := strings.Join(kept, "\n")
+	if content != "" {
+		content += "\n"
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
+// LoadKeymap reads key bindings from a file with one action per line
+// followed by its keys, e.g. "feeds.refresh F ctrl+r". A missing file
+// leaves the defaults in place, invalid lines are returned as errors
+func LoadKeymap(path string) (Keymap, []error) {
+	keymap := DefaultKeymap()
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return keymap, nil
+		}
+		return keymap, []error{err}
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var errs []error
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			errs = append(errs, fmt.Errorf("%s:%d: expected an action followed by keys", path, lineNumber))
+			continue
+		}
+
+		keys := make([]string, 0, len(fields)-1)
+		for _, key := range fields[1:] {
+			keys = append(keys, parseKeyName(key))
+		}
+		if err := keymap.Bind(fields[0], keys); err != nil {
+			errs = append(errs, fmt.Errorf("%s:%d: %w", path, lineNumber, err))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return keymap, errs
+}
+
+// parseKeyName accepts "space" for the space bar, which can't be written
+// between the whitespace separated keys
+func parseKeyName(name string) string {
+	if name == "space" {
+		return " "
+	}
+	return name
+}
+
+// formatKeyName is the inverse of parseKeyName for display
+func formatKeyName(key string) string {
+	if key == " " {
+		return "space"
+	}
+	return key
+}
+
+// keyTypesByName maps the names bubbletea uses for special keys, such as
+// "enter" or "ctrl+d", back to their key type
+var keyTypesByName = func() map[string]tea.KeyType {
+	names := make(map[string]tea.KeyType)
+	for t := tea.KeyType(-100); t <= 127; t++ {
+		if name := t.String(); name != "" {
+			if _, ok := names[name]; !ok {
+				names[name] = t
+			}
+		}
+	}
+	return names
+}()
+
+// keyMsgFor builds the key message the key handlers would receive for a key
+func keyMsgFor(key string, paste bool) tea.KeyMsg {
+	alt := false
+	if strings.HasPrefix(key, "alt+") && len(key) > len("alt+") {
+		alt = true
+		key = strings.TrimPrefix(key, "alt+")
+	}
+	if t, ok := keyTypesByName[key]; ok && key != " " {
+		return tea.KeyMsg{Type: t, Alt: alt, Paste: paste}
+	}
+	if key == " " {
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" "), Alt: alt, Paste: paste}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key), Alt: alt, Paste: paste}
+}
+
+func (m Model) handleKeymapViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.capturingKey {
+		return m.captureKey(msg)
+	}
+	m.statusMessage = ""
+	m.statusMessageType = ""
+
+	switch msg.String() {
+	case "q", "esc", "ctrl+c":
+		m.state = m.previousState
+		m.keymapViewScroll = 0
+		m.keymapCursor = 0
+		return m, nil
+
+	case "j", "down":
+		m.moveKeymapCursor(1)
+		return m, nil
+
+	case "k", "up":
+		m.moveKeymapCursor(-1)
+		return m, nil
+
+	case "ctrl+d":
+		m.moveKeymapCursor(max(m.height/2, 5))
+		return m, nil
+
+	case "ctrl+u":
+		m.moveKeymapCursor(-max(m.height/2, 5))
+		return m, nil
+
+	case "enter", "a":
+		// Press a key to replace the action's keys, or with a to add one
+		if m.readOnly {
+			m.statusMessage = m.readOnlyMessage()
+			m.statusMessageType = "error"
+			return m, nil
+		}
+		m.capturingKey = true
+		m.captureAddsKey = msg.String() == "a"
+		return m, nil
+
+	case "x":
+		// Reset the action to its default keys
+		action := Actions[m.keymapCursor]
+		return m.rebind(action, action.Defaults)
+	}
+
+	return m, nil
+}
+
+// captureKey binds the next key pressed to the action under the cursor,
+// unless another action active at the same time has it
+func (m Model) captureKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.capturingKey = false
+	key := msg.String()
+	if key == "esc" {
+		return m, nil
+	}
+
+	action := Actions[m.keymapCursor]
+	if other := m.keymap.boundTo(action, key); other != "" {
+		m.statusMessage = fmt.Sprintf("%s is already bound to %s, rebind that first", formatKeyName(key), other)
+		m.statusMessageType = "error"
+		return m, nil
+	}
+
+	keys := []string{key}
+	if m.captureAddsKey {
+		keys = append([]string{}, m.keymap.Keys(action.Name)...)
+		for _, bound := range keys {
+			if bound == key {
+				return m, nil
+			}
+		}
+		keys = append(keys, key)
+	}
+	return m.rebind(action, keys)
+}
+
+// rebind binds an action to keys and saves them to the keys file
+func (m Model) rebind(action Action, keys []string) (tea.Model, tea.Cmd) {
+	if m.readOnly {
+		m.statusMessage = m.readOnlyMessage()
+		m.statusMessageType = "error"
+		return m, nil
+	}
+	if m.keysFilePath == "" {
+		m.statusMessage = "No key bindings file to save to"
+		m.statusMessageType = "error"
+		return m, nil
+	}
+	if err := saveBinding(m.keysFilePath, action, keys); err != nil {
+		logging.Error("Failed to save key binding", "action", action.Name, "error", err)
+		m.statusMessage = fmt.Sprintf("Failed to save key binding: %v", err)
+		m.statusMessageType = "error"
+		return m, nil
+	}
+	_ = m.keymap.Bind(action.Name, keys)
+
+	names := make([]string, 0, len(keys))
+	for _, key := range keys {
+		names = append(names, formatKeyName(key))
+	}
+	m.statusMessage = fmt.Sprintf("%s bound to %s", action.Name, strings.Join(names, ", "))
+	m.statusMessageType = "info"
+	return m, nil
+}
+
+// moveKeymapCursor moves the cursor by delta actions and scrolls it into view
+func (m *Model) moveKeymapCursor(delta int) {
+	m.keymapCursor = min(max(m.keymapCursor+delta, 0), len(Actions)-1)
+
+	_, actionLines := m.keymapLines()
+	line := actionLines[m.keymapCursor]
+	availableHeight := m.keymapViewHeight()
+	if line < m.keymapViewScroll {
+		m.keymapViewScroll = line
+	} else if line >= m.keymapViewScroll+availableHeight {
+		m.keymapViewScroll = line - availableHeight + 1
+	}
+	// Show the scope above the first action of the list
+	if m.keymapCursor == 0 {
+		m.keymapViewScroll = 0
+	}
+}
+
+// keymapViewHeight is how many lines of bindings fit between the title and
+// the status bar
+func (m Model) keymapViewHeight() int {
+	// Reserve space for: title (1), empty line (1), status bar (1) = 3 lines
+	return max(m.height-3, 3)
+}
+
+// keymapLines returns the lines of the key bindings view and the line of
+// each action
+func (m Model) keymapLines() ([]string, []int) {
+	var allLines []string
+
+	source := "Default key bindings"
+	if m.keysFilePath != "" {
+		source = "Key bindings, saved to " + m.keysFilePath
+	}
+	allLines = append(allLines, m.getHelpStyle().Render(source), "")
+
+	conflicts := m.keymap.Conflicts()
+	if len(conflicts) > 0 {
+		allLines = append(allLines, m.getErrorStyle().Render(fmt.Sprintf("%d conflicting keys:", len(conflicts))))
+		for _, conflict := range conflicts {
+			line := fmt.Sprintf("  %-8s %-8s %s", conflict.Scope, formatKeyName(conflict.Key), strings.Join(conflict.Actions, ", "))
+			allLines = append(allLines, m.getErrorStyle().Render(line))
+		}
+		allLines = append(allLines, "")
+	}
+
+	conflicting := m.keymap.conflictingActions()
+	actionLines := make([]int, len(Actions))
+	var scope KeyScope
+	for i, action := range Actions {
+		if action.Scope != scope {
+			if scope != "" {
+				allLines = append(allLines, "")
+			}
+			scope = action.Scope
+			allLines = append(allLines, string(scope))
+		}
+
+		keys := make([]string, 0, len(m.keymap.Keys(action.Name)))
+		for _, key := range m.keymap.Keys(action.Name) {
+			keys = append(keys, formatKeyName(key))
+		}
+		keyList := strings.Join(keys, ", ")
+		if m.capturingKey && i == m.keymapCursor {
+			keyList = "press a key"
+		}
+		line := fmt.Sprintf("  %-24s %-15s %s", action.Name, keyList, action.Description)
+		if !m.keymap.isDefault(action) {
+			line += " *"
+		}
+		if i == m.keymapCursor {
+			line = m.applyHighlight(line, true)
+		} else if conflicting[action.Name] {
+			line = m.getErrorStyle().Render(line)
+		}
+		actionLines[i] = len(allLines)
+		allLines = append(allLines, line)
+	}
+	return allLines, actionLines
+}
+
+func (m Model) renderKeymapView() string {
+	allLines, _ := m.keymapLines()
+	availableHeight := m.keymapViewHeight()
+
+	// Ensure scroll doesn't go past the end
+	maxScroll := max(len(allLines)-availableHeight, 0)
+	scroll := min(m.keymapViewScroll, maxScroll)
+
+	start := scroll
+	end := min(start+availableHeight, len(allLines))
+	visibleLines := allLines[start:end]
+
+	var b strings.Builder
+	b.WriteString(m.getTitleStyle().Render("🐐 NewsGoat - Key Bindings"))
+	b.WriteString("\n\n")
+
+	for _, line := range visibleLines {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	// Calculate padding to push status bar to bottom
+	usedLines := 2 + len(visibleLines)
+	padding := max(m.height-usedLines-1, 0)
+	b.WriteString(strings.Repeat("\n", padding))
+
+	switch {
+	case m.capturingKey:
+		b.WriteString(m.getHelpStyle().Render(fmt.Sprintf("Press a key for %s, esc cancels", Actions[m.keymapCursor].Name)))
+	case m.statusMessageType == "error":
+		b.WriteString(m.getErrorStyle().Render(m.statusMessage))
+	case m.statusMessage != "":
+		b.WriteString(m.getHelpStyle().Render(m.statusMessage))
+	default:
+		if len(allLines) > availableHeight {
+			scrollInfo := fmt.Sprintf("(%d-%d of %d) ", start+1, end, len(allLines))
+			b.WriteString(m.getHelpStyle().Render(scrollInfo))
+		}
+		b.WriteString(m.getHelpStyle().Render("enter: rebind | a: add key | x: reset | esc: return"))
+	}
+
+	return b.String()
+}
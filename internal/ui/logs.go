@@ -0,0 +1,210 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/feeds"
+)
+
+// logFollowInterval is how often the log view looks for new messages while
+// following them
+const logFollowInterval = 2 * time.Second
+
+// logLevelKeys are the keys showing or hiding the messages of a level, in
+// the order the levels are listed
+var logLevelKeys = []struct {
+	key   string
+	level string
+}{
+	{"e", "ERROR"},
+	{"w", "WARN"},
+	{"i", "INFO"},
+	{"d", "DEBUG"},
+}
+
+// LogFollowTickMsg loads the newest log messages again while the log view
+// follows them
+type LogFollowTickMsg struct{}
+
+func logFollowTick() tea.Cmd {
+	return tea.Tick(logFollowInterval, func(time.Time) tea.Msg {
+		return LogFollowTickMsg{}
+	})
+}
+
+// openLogView shows the log messages from the newest, those of a feed when
+// opened from its feed info. The levels hidden stay hidden.
+func (m Model) openLogView(feed *database.Feed) (Model, tea.Cmd) {
+	m.logFilter = feeds.LogFilter{HiddenLevels: m.logFilter.HiddenLevels}
+	m.logFeedTitle = ""
+	m.logFromFeedInfo = feed != nil
+	if feed != nil {
+		m.logFilter.FeedID = feed.ID
+		m.logFilter.FeedURL = feed.Url
+		m.logFeedTitle = feed.Title
+		m.logReturnPreviousState = m.previousState
+		m.logReturnCursor = m.cursor
+	}
+	m.logFollow = false
+	m.state = LogView
+	m.cursor = 0
+	m.savedLogCursor = 0
+	return m, loadLogList(m.feedManager, m.logFilter, 0)
+}
+
+// closeLogView goes back to the feed info the log view was opened from, or
+// to the feed list
+func (m Model) closeLogView() (Model, tea.Cmd) {
+	m.logFollow = false
+	if m.logFromFeedInfo {
+		m.logFromFeedInfo = false
+		m.state = FeedInfoView
+		m.previousState = m.logReturnPreviousState
+		m.cursor = m.logReturnCursor
+		return m, nil
+	}
+	// Clear search mode when returning to feed list
+	m.searchMode = false
+	m.searchActive = false
+	m.searchQuery = ""
+	m.state = FeedListView
+	return m, loadFeedList(m.feedManager)
+}
+
+// toggleLogLevel shows or hides the messages of a level
+func (m Model) toggleLogLevel(level string) (Model, tea.Cmd) {
+	// The filter is copied with the model, so the slice is never changed in place
+	hidden := slices.Clone(m.logFilter.HiddenLevels)
+	if i := slices.Index(hidden, level); i >= 0 {
+		hidden = slices.Delete(hidden, i, i+1)
+	} else {
+		hidden = append(hidden, level)
+	}
+	m.logFilter.HiddenLevels = hidden
+	return m.reloadLogView()
+}
+
+// reloadLogView loads the newest messages matching the filter
+func (m Model) reloadLogView() (Model, tea.Cmd) {
+	m.cursor = 0
+	m.savedLogCursor = 0
+	return m, loadLogList(m.feedManager, m.logFilter, 0)
+}
+
+// toggleLogFollow starts or stops following new log messages. Following
+// keeps the newest message selected and loads new ones every few seconds.
+func (m Model) toggleLogFollow() (Model, tea.Cmd) {
+	m.logFollow = !m.logFollow
+	if !m.logFollow {
+		return m, nil
+	}
+	m, cmd := m.reloadLogView()
+	if m.logFollowTicking {
+		return m, cmd
+	}
+	m.logFollowTicking = true
+	return m, tea.Batch(cmd, logFollowTick())
+}
+
+// logFollowTicked loads the newest messages while the log view follows them
+func (m Model) logFollowTicked() (Model, tea.Cmd) {
+	if !m.logFollow || m.state != LogView {
+		m.logFollowTicking = false
+		return m, nil
+	}
+	return m, tea.Batch(loadLogList(m.feedManager, m.logFilter, 0), logFollowTick())
+}
+
+// stopLogFollow stops following new messages when moving away from the
+// newest one
+func (m Model) stopLogFollow() Model {
+	if m.logFollow {
+		m.logFollow = false
+		m.logStatus = "Stopped following new messages"
+	}
+	return m
+}
+
+// handleLogSearchKeys edits the text the log messages are filtered by
+func (m Model) handleLogSearchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.logSearching = false
+		m.logSearchInput = ""
+		return m, nil
+	case tea.KeyEnter:
+		m.logSearching = false
+		m.logFilter.Search = strings.TrimSpace(m.logSearchInput)
+		m.logSearchInput = ""
+		return m.reloadLogView()
+	case tea.KeyBackspace:
+		if len(m.logSearchInput) > 0 {
+			runes := []rune(m.logSearchInput)
+			m.logSearchInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.logSearchInput += " "
+	case tea.KeyRunes:
+		m.logSearchInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// logFilterDescription tells which log messages are shown, on one line of
+// the screen, empty when all of them are
+func (m Model) logFilterDescription() string {
+	var parts []string
+	if len(m.logFilter.HiddenLevels) > 0 {
+		var levels []string
+		for _, level := range logLevelKeys {
+			if !slices.Contains(m.logFilter.HiddenLevels, level.level) {
+				levels = append(levels, strings.ToLower(level.level))
+			}
+		}
+		if len(levels) == 0 {
+			levels = []string{"none"}
+		}
+		parts = append(parts, "levels: "+strings.Join(levels, ","))
+	}
+	if m.logFilter.Search != "" {
+		parts = append(parts, "filter: "+m.logFilter.Search)
+	}
+	if m.logFilter.FeedID != 0 {
+		parts = append(parts, "feed: "+m.logFeedTitle)
+	}
+	if m.logFollow {
+		parts = append(parts, "following new messages")
+	}
+	return ansi.Truncate(strings.Join(parts, " | "), m.width, "…")
+}
+
+// logStorageDescription tells how much the log takes up and how much of it
+// is kept
+func (m Model) logStorageDescription() string {
+	var limits []string
+	if m.config.LogMaxMessages > 0 {
+		limits = append(limits, fmt.Sprintf("at most %d", m.config.LogMaxMessages))
+	}
+	if m.config.LogMaxAgeDays > 0 {
+		limits = append(limits, fmt.Sprintf("for %d days", m.config.LogMaxAgeDays))
+	}
+	keeps := "keeping everything"
+	if len(limits) > 0 {
+		keeps = "keeping " + strings.Join(limits, " ")
+	}
+	return fmt.Sprintf("(%d stored, %s, %s)", m.logStored, formatLogSize(m.logSize), keeps)
+}
+
+// formatLogSize shows a number of bytes in KB or MB
+func formatLogSize(bytes int64) string {
+	if bytes < 1<<20 {
+		return fmt.Sprintf("%.1f KB", float64(bytes)/(1<<10))
+	}
+	return fmt.Sprintf("%.1f MB", float64(bytes)/(1<<20))
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jarv/newsgoat/internal/config"
+)
+
+// parseMacros parses the Macros setting and checks that every step is an
+// action of the key bindings or a : command
+func parseMacros(value string) ([]config.Macro, error) {
+	macros, err := config.ParseMacros(value)
+	if err != nil {
+		return nil, err
+	}
+	for _, macro := range macros {
+		for _, step := range macro.Steps {
+			if strings.HasPrefix(step, ":") {
+				continue
+			}
+			if _, ok := findAction(step); !ok {
+				return nil, fmt.Errorf("macro %q: unknown action %q, the actions are listed with K", macro.Key, step)
+			}
+		}
+	}
+	return macros, nil
+}
+
+// runMacro runs the steps of the macro bound to key one after another, like
+// their keys were pressed. The commands they return run in the same order.
+// A step that isn't available in the view the previous ones left open stops
+// the macro.
+func (m Model) runMacro(key string) (tea.Model, tea.Cmd) {
+	macros, err := parseMacros(m.config.Macros)
+	if err != nil {
+		return m.macroError("Macros setting: " + err.Error()), nil
+	}
+	var macro *config.Macro
+	for i := range macros {
+		if macros[i].Key == key {
+			macro = &macros[i]
+			break
+		}
+	}
+	if macro == nil {
+		return m.macroError("No macro on ," + key), nil
+	}
+
+	var cmds []tea.Cmd
+	for _, step := range macro.Steps {
+		var model tea.Model
+		var cmd tea.Cmd
+		if command, ok := strings.CutPrefix(step, ":"); ok {
+			model, cmd = m.runCommand(command)
+		} else {
+			action, _ := findAction(step)
+			if action.Scope != ScopeGlobal && action.Scope != scopeForView(m.state) {
+				m = m.macroError(fmt.Sprintf("Macro ,%s stopped: %s isn't available here", key, step))
+				break
+			}
+			model, cmd = m.handleResolvedKey(keyMsgFor(action.Defaults[0], false))
+		}
+		m = model.(Model)
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Sequence(cmds...)
+}
+
+// macroError shows why a macro didn't run where the current view shows
+// messages
+func (m Model) macroError(message string) Model {
+	if m.state == ArticleView {
+		m.fullTextStatus = message
+		return m
+	}
+	m.statusMessage = message
+	m.statusMessageType = "error"
+	return m
+}
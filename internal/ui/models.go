@@ -4,11 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
@@ -17,8 +20,13 @@ import (
 	"github.com/jarv/newsgoat/internal/database"
 	"github.com/jarv/newsgoat/internal/discovery"
 	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/filter"
 	"github.com/jarv/newsgoat/internal/logging"
+	"github.com/jarv/newsgoat/internal/rules"
+	"github.com/jarv/newsgoat/internal/secrets"
+	feedsync "github.com/jarv/newsgoat/internal/sync"
 	"github.com/jarv/newsgoat/internal/tasks"
+	"github.com/jarv/newsgoat/internal/termimage"
 	"github.com/jarv/newsgoat/internal/themes"
 	"github.com/jarv/newsgoat/internal/updater"
 	"github.com/jarv/newsgoat/internal/version"
@@ -108,6 +116,7 @@ type FeedListItem struct {
 	TotalItems    int64
 	IsExpanded    bool
 	IsUnderFolder bool // True if this feed is displayed under a folder
+	Depth         int  // Folders this folder or feed is shown inside of
 }
 
 // getDisplayTitle returns the display title for a feed, overriding for GitHub/GitLab
@@ -145,8 +154,69 @@ const (
 	HelpView
 	SettingsView
 	URLsView
+	KeymapView
+	FetchReportView
+	TagView
+	ShareView
+	RulesView
+	StatsView
 )
 
+// Virtual feed IDs for item lists that aggregate items across feeds
+const (
+	HotItemsFeedID int64 = -1
+	StarredFeedID  int64 = -2
+	AllItemsFeedID int64 = -3
+)
+
+// isVirtualFeed reports whether a feed ID refers to an aggregated item list
+func isVirtualFeed(feedID int64) bool {
+	return feedID < 0
+}
+
+// feedTitle returns the title of a feed, used to show where items of an
+// aggregated list come from
+func (m Model) feedTitle(feedID int64) string {
+	for _, feed := range m.allFeeds {
+		if feed.ID == feedID {
+			return feed.Title
+		}
+	}
+	return ""
+}
+
+// feedIsDead reports whether a feed failed so many refreshes in a row that
+// it probably moved or went away
+func (m Model) feedIsDead(feed database.GetFeedStatsRow) bool {
+	threshold := int64(m.config.DeadFeedThreshold)
+	return threshold > 0 && feed.ConsecutiveFailures >= threshold
+}
+
+// itemBadges shows the pull request and CI state of an enriched commit
+func itemBadges(item database.GetItemsWithReadStatusRow) string {
+	var badges []string
+	if item.PrNumber > 0 {
+		// GitLab refers to merge requests with ! instead of #
+		marker := "#"
+		if discovery.GetURLType(item.Link) == discovery.URLTypeGitLab {
+			marker = "!"
+		}
+		badges = append(badges, fmt.Sprintf("%s%d %s", marker, item.PrNumber, item.PrState))
+	}
+	switch item.CiState {
+	case feeds.CIStateSuccess:
+		badges = append(badges, "✓")
+	case feeds.CIStateFailure:
+		badges = append(badges, "✗")
+	case feeds.CIStatePending:
+		badges = append(badges, "●")
+	}
+	if len(badges) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(badges, " ") + "] "
+}
+
 type SearchType int
 
 const (
@@ -157,20 +227,57 @@ const (
 type Model struct {
 	feedManager                     *feeds.Manager
 	taskManager                     tasks.Manager
+	taskEvents                      <-chan tasks.TaskEvent // The UI's subscription to the task manager's events
 	queries                         *database.Queries
 	config                          config.Config
 	glamourRenderer                 *glamour.TermRenderer
+	glamourWrapWidth                int // Width the renderer wraps at
 	state                           ViewState
 	previousState                   ViewState // Store previous state when entering help view
 	feedList                        []FeedListItem
 	allFeeds                        []database.GetFeedStatsRow // Unfiltered list of all feeds (for reload operations)
 	expandedFolders                 map[string]bool            // Track which folders are expanded
 	folderStats                     map[string]struct{ UnreadItems, TotalItems int64 }
-	totalFeedCount                  int // Total number of feeds in database (before filtering)
+	feedFolders                     map[int64][]string          // Feed ID -> folders the feed belongs to
+	feedIcons                       map[string]string           // Feed host -> color of its site's favicon
+	darkBackground                  bool                        // The terminal's background is dark, the auto theme is dark then
+	backgroundDetected              bool                        // darkBackground was detected rather than assumed
+	starredStats                    database.GetStarredStatsRow // Counts shown for the virtual Starred feed
+	queryFeeds                      []queryFeed                 // Virtual feeds defined by filters in the URLs file
+	feedDirections                  map[string]string           // Feed URL -> text direction set with !direction
+	undoStack                       []undoEntry                 // Mark-all-reads that can be undone, the last one first to go
+	undoPrompt                      string                      // Status message offering the undo, u undoes while it is shown
+	totalFeedCount                  int                         // Total number of feeds in database (before filtering)
 	itemList                        []database.GetItemsWithReadStatusRow
+	clusterSourceItems              []database.GetItemsWithReadStatusRow           // Item list before clustering (for rebuilding)
+	itemClusters                    map[int64][]database.GetItemsWithReadStatusRow // Cluster lead item ID -> other items covering the same story
+	expandedClusters                map[int64]bool                                 // Track which clusters are expanded
+	clusterMembers                  map[int64]bool                                 // Items displayed under an expanded cluster
+	skippedItems                    map[int64]int64                                // Unread items passed over in the item list (item ID -> feed ID)
+	jumpingToUnread                 bool                                           // The next unread item is being looked for in the next feeds
 	currentItem                     database.GetItemsWithReadStatusRow
 	currentFeed                     database.Feed // For feed info view
+	editingFeedInfo                 string        // The feed info field being edited, feedInfoTitle or feedInfoURL
+	feedInfoInput                   string        // Text typed for the edited feed info field
+	feedInfoNewURL                  string        // URL of the feed waiting for y to confirm it
+	feedInfoStatus                  string        // Result of the last feed info edit
+	currentFeedItems                int64         // Items stored of the feed in feed info
+	currentFeedRetryAt              time.Time     // When a failed refresh of the feed in feed info is retried
+	currentWebSub                   *database.WebsubSubscription
 	logList                         []database.LogMessage
+	logPage                         int   // Page of the log view, 0 is the newest messages
+	logTotal                        int64 // Number of stored log messages matching the filter
+	logStored                       int64 // Number of stored log messages
+	logSize                         int64 // Bytes the stored log messages take up
+	logFilter                       feeds.LogFilter
+	logFeedTitle                    string    // Title of the feed the log view shows the messages of
+	logSearching                    bool      // Track if the log filter prompt is open
+	logSearchInput                  string    // Filter being typed in the log view
+	logFollow                       bool      // The log view keeps showing the newest messages
+	logFollowTicking                bool      // A LogFollowTickMsg is on its way
+	logFromFeedInfo                 bool      // The log view was opened from feed info and goes back to it
+	logReturnPreviousState          ViewState // View feed info goes back to, after the log view opened from it
+	logReturnCursor                 int       // Cursor to restore when going back to feed info
 	currentLog                      database.LogMessage
 	taskList                        []*tasks.Task
 	urlsList                        []config.URLEntry
@@ -182,9 +289,18 @@ type Model struct {
 	savedLogCursor                  int
 	savedTasksCursor                int
 	savedSettingsCursor             int
-	helpViewScroll                  int // Scroll offset for help view
-	articleViewScroll               int // Scroll offset for article view
-	urlsViewScroll                  int // Scroll offset for URLs view
+	helpViewScroll                  int  // Scroll offset for help view
+	articleViewScroll               int  // Scroll offset for article view
+	urlsViewScroll                  int  // Scroll offset for URLs view
+	keymapViewScroll                int  // Scroll offset for key bindings view
+	keymapCursor                    int  // Action selected in the key bindings view
+	capturingKey                    bool // The next key pressed is bound to the selected action
+	macroPending                    bool // , was pressed, the next key picks the macro to run
+	captureAddsKey                  bool // The captured key is added to the action's keys instead of replacing them
+	fetchReport                     feeds.FetchReport
+	fetchReportScroll               int // Scroll offset for test fetch report view
+	keymap                          Keymap
+	keysFilePath                    string
 	itemTitleScrollOffset           int // Horizontal scroll offset for item titles
 	selectedFeed                    int64
 	width                           int
@@ -197,9 +313,17 @@ type Model struct {
 	maxConcurrency                  int                                  // Max concurrent refreshes allowed
 	spinnerFrame                    int                                  // Current spinner animation frame
 	spinnerRunning                  bool                                 // Track if spinner timer is already running
-	firstAutoReload                 bool                                 // Track if this is the first auto reload (for SuppressFirstReload)
-	pendingStartupReload            bool                                 // Track if we need to reload on startup after feed list loads
 	nextReloadTime                  time.Time                            // Time when next auto reload is scheduled
+	reloadTimerRunning              bool                                 // Track if the auto reload timer is ticking
+	readOnly                        bool                                 // Another NewsGoat is running, nothing is refreshed or changed
+	readOnlyPID                     int                                  // Pid of the NewsGoat holding the lock
+	daemonPID                       int                                  // Pid of the daemon refreshing the feeds, 0 without one
+	daemonSocket                    string                               // Socket the daemon's status is polled on
+	daemonChanges                   int64                                // Changes count of the last poll
+	daemonGone                      bool                                 // The last poll found no daemon
+	syncing                         bool                                 // A sync task is queued or running
+	syncManual                      bool                                 // The running sync was started with :sync and reports its result
+	refreshJob                      *tasks.RefreshJob                    // Scheduled job reloading the feeds that are due, nil when it runs elsewhere
 	editingSettings                 bool                                 // Track if we're editing a setting
 	selectingTheme                  bool                                 // Track if we're selecting a theme
 	selectingHighlight              bool                                 // Track if we're selecting a highlight style
@@ -210,7 +334,28 @@ type Model struct {
 	selectingReloadOnStartup        bool                                 // Track if we're selecting reload on startup
 	selectingUnreadOnTop            bool                                 // Track if we're selecting unread on top
 	selectingCheckForUpdates        bool                                 // Track if we're selecting check for updates
+	selectingClusterStories         bool                                 // Track if we're selecting cluster stories
+	selectingUpdateRedirects        bool                                 // Track if we're selecting update redirects
+	selectingHideDuplicates         bool                                 // Track if we're selecting hide duplicates
+	selectingMarkReadOnOpen         bool                                 // Track if we're selecting mark read on open
+	selectingMarkSkippedRead        bool                                 // Track if we're selecting mark skipped read
 	showRawHTML                     bool                                 // Track if showing raw HTML in article view
+	fullTextStatus                  string                               // Status of a full article fetch or pipe command in article view
+	imageProtocol                   termimage.Protocol                   // Terminal graphics article images are drawn with, none shows their alt text
+	cellSize                        termimage.CellSize                   // Size of a terminal cell in pixels, for scaling images
+	articleImages                   map[string]*termimage.Image          // Images of the shown article by URL, nil when one can't be drawn
+	articleImagesContent            string                               // Article content the images were downloaded for
+	articleImagesLayout             string                               // Where article images were last drawn, a change clears the screen
+	articleCopiesItemID             int64                                // Item the other feeds publishing it were looked up for
+	articleCopies                   []string                             // Titles of the other feeds publishing the shown article
+	article                         renderedArticle                      // The shown article, rendered
+	articleRendering                articleKey                           // The article being rendered in the background
+	articleSearching                bool                                 // Track if the article search prompt is open
+	articleSearchInput              string                               // Search being typed in the article view
+	articleSearch                   articleSearch                        // Search shown in the article, n/p move between its matches
+	articleCache                    *articleCache                        // Rendered articles by item
+	exportingArticle                bool                                 // The next key picks the format the article is exported to
+	logStatus                       string                               // Result of copying a log message in the log views
 	themeSelectCursor               int                                  // Cursor position in theme selector
 	highlightSelectCursor           int                                  // Cursor position in highlight style selector
 	spinnerSelectCursor             int                                  // Cursor position in spinner type selector
@@ -220,6 +365,11 @@ type Model struct {
 	reloadOnStartupSelectCursor     int                                  // Cursor position in reload on startup selector
 	unreadOnTopSelectCursor         int                                  // Cursor position in unread on top selector
 	checkForUpdatesSelectCursor     int                                  // Cursor position in check for updates selector
+	clusterStoriesSelectCursor      int                                  // Cursor position in cluster stories selector
+	updateRedirectsSelectCursor     int                                  // Cursor position in update redirects selector
+	hideDuplicatesSelectCursor      int                                  // Cursor position in hide duplicates selector
+	markReadOnOpenSelectCursor      int                                  // Cursor position in mark read on open selector
+	markSkippedReadSelectCursor     int                                  // Cursor position in mark skipped read selector
 	settingInput                    string                               // Current input value when editing
 	showSettingsHelp                bool                                 // Track if we're showing settings help
 	searchMode                      bool                                 // Track if search mode is active
@@ -228,6 +378,33 @@ type Model struct {
 	searchActive                    bool                                 // Track if feeds/items are currently filtered by search
 	unfilteredFeedList              []FeedListItem                       // Feed list before search filtering (for restoring)
 	unfilteredItemList              []database.GetItemsWithReadStatusRow // Item list before search filtering (for restoring)
+	loadedItems                     map[int64]bool                       // Rows of a paged item list read from the database, true for the ones that were unread
+	moreItems                       bool                                 // The paged item list has rows that aren't loaded yet
+	loadingItems                    bool                                 // The next page of the item list is being loaded
+	searchMatches                   map[int64]SearchMatch                // Where global search results matched, by item ID
+	commandMode                     bool                                 // Track if the : prompt is open
+	commandInput                    string                               // Current : prompt text
+	editingFolders                  bool                                 // Track if the folder prompt of a feed is open
+	folderFeed                      database.GetFeedStatsRow             // Feed whose folders are edited
+	folderInput                     string                               // Current folder prompt text
+	folderCompletions               []string                             // Folders matching the name being completed with tab
+	folderCompletionBase            string                               // Folder prompt text before the completed name
+	folderCompletionIndex           int                                  // Completion shown, tab goes to the next one
+	feedFilter                      *filter.Filter                       // Filter expression narrowing the feed list
+	selectedTag                     string                               // Folder the feed list is narrowed to with T
+	tagCursor                       int                                  // Cursor position in the tag view
+	shareCursor                     int                                  // Cursor position in the share menu
+	rules                           []rules.Rule                         // Item rules, highlight rules mark items of the item list
+	rulesCursor                     int                                  // Cursor position in the rules view
+	rulesStatus                     string                               // Result of the last change in the rules view
+	rulesFeedID                     int64                                // Feed the rules view was opened on, rules added with f apply to it
+	addingRule                      bool                                 // A rule is being typed in the rules view
+	ruleInput                       string                               // Rule being typed
+	ruleFeedID                      int64                                // Feed the rule being typed applies to, 0 for every feed
+	stats                           *feeds.ReadingStats                  // Reading statistics, nil until loaded
+	statsErr                        error                                // Why the reading statistics failed to load
+	statsScroll                     int                                  // Scroll position in the stats view
+	itemFilter                      *filter.Filter                       // Filter expression narrowing item lists
 	statusMessage                   string                               // Message to display above status bar
 	statusMessageType               string                               // Type of message: "error" or "info"
 	quitPressed                     bool                                 // Track if 'q' was pressed once (for quit confirmation)
@@ -277,17 +454,23 @@ type TaskEventMsg struct {
 }
 
 type FeedListLoadedMsg struct {
-	Feeds []database.GetFeedStatsRow
+	Feeds   []database.GetFeedStatsRow
+	Starred database.GetStarredStatsRow
+	Folders map[int64][]string
+	Icons   map[string]string // Feed host -> favicon color
 }
 
 type ItemListLoadedMsg struct {
 	Items []database.GetItemsWithReadStatusRow
+	Paged bool // Only the first rows of the list were asked for
+	More  bool // The list has more rows than were loaded
 }
 
 type SearchResultsMsg struct {
 	FeedResults []database.SearchFeedsByTitleRow
 	ItemResults []database.SearchItemsByTitleRow
 	IsGlobal    bool
+	Matches     map[int64]SearchMatch // Matched title text and body snippet of global item results
 }
 
 type ErrorMsg struct {
@@ -295,7 +478,11 @@ type ErrorMsg struct {
 }
 
 type LogListLoadedMsg struct {
-	Logs []database.LogMessage
+	Logs   []database.LogMessage
+	Page   int
+	Total  int64
+	Stored int64 // Log messages stored, matching the filter or not
+	Size   int64 // Bytes the stored log messages take up
 }
 
 type TaskListLoadedMsg struct {
@@ -308,8 +495,9 @@ type URLsListLoadedMsg struct {
 }
 
 type URLsReloadedMsg struct {
-	URLs     []config.URLEntry
-	FilePath string
+	URLs       []config.URLEntry
+	QueryFeeds []config.QueryFeed
+	FilePath   string
 }
 
 type EditorFinishedMsg struct{}
@@ -318,18 +506,77 @@ type EditorErrorMsg struct {
 	Err string
 }
 
+type ArticlePipedMsg struct {
+	ItemID int64
+	Err    error
+}
+
+// RawHTMLSavedMsg reports where the raw HTML of an article was saved to
+// open it in the browser, or that the pager showing it exited
+type RawHTMLSavedMsg struct {
+	ItemID int64
+	Path   string
+	Paged  bool // Shown in the pager, its file is removed already
+	Err    error
+}
+
+type LogCopiedMsg struct {
+	Err error
+}
+
+// ClipboardCopiedMsg reports copying a link or article to the clipboard
+type ClipboardCopiedMsg struct {
+	What string
+	Err  error
+}
+
+type FeedPausedMsg struct {
+	Title  string
+	Paused bool
+	Err    error
+}
+
+// FeedRediscoveredMsg reports the new URL a failing feed was moved to, URLs
+// are the entries of the URLs file afterwards
+type FeedRediscoveredMsg struct {
+	FeedID int64
+	Title  string
+	URL    string
+	URLs   []config.URLEntry
+	Err    error
+}
+
+type FetchReportLoadedMsg struct {
+	Report feeds.FetchReport
+	Err    error
+}
+
 type FeedInfoLoadedMsg struct {
-	Feed database.Feed
+	Feed      database.Feed
+	WebSub    *database.WebsubSubscription // nil when the feed has no hub
+	ItemCount int64
+	RetryAt   time.Time // When a failed refresh of the feed is retried, zero when none is
 }
 
 type AllItemsMarkedReadMsg struct {
-	FeedID int64
+	FeedID  int64
+	ItemIDs []int64 // The items that were unread, to undo it
 }
 
 type ItemReadStatusToggledMsg struct {
 	ItemID int64
 }
 
+type ItemStarToggledMsg struct {
+	ItemID int64
+}
+
+type FullContentFetchedMsg struct {
+	ItemID  int64
+	Content string
+	Err     error
+}
+
 type URLAddSuccessMsg struct {
 	URL           string
 	DiscoveredURL bool
@@ -339,7 +586,15 @@ type URLAddErrorMsg struct {
 	Err string
 }
 
-type ReloadTimerMsg struct{}
+// AddURLMsg adds a feed like entering its URL after pressing u, sent for
+// subscribe links opened while NewsGoat is running
+type AddURLMsg struct {
+	URL string
+}
+
+// ReadStateChangedMsg reloads the lists after items were marked read from
+// the command line while NewsGoat is running
+type ReadStateChangedMsg struct{}
 
 type RestartReloadTimerMsg struct{}
 
@@ -365,15 +620,16 @@ type UpdateInstallErrorMsg struct {
 	err error
 }
 
-// createGlamourRenderer creates a glamour renderer with the given theme
-// and configures it to hide link URLs (since we add [1], [2] markers manually)
-func createGlamourRenderer(themeName string) (*glamour.TermRenderer, error) {
+// createGlamourRenderer creates a glamour renderer with the given theme that
+// wraps at wrapWidth and configures it to hide link URLs (since we add [1],
+// [2] markers manually)
+func createGlamourRenderer(themeName string, wrapWidth int) (*glamour.TermRenderer, error) {
 	theme := themes.GetThemeByName(themeName)
 
 	// First create a renderer with the standard style to get the base config
 	baseRenderer, err := glamour.NewTermRenderer(
-		glamour.WithStandardStyle(theme.GlamourStyle),
-		glamour.WithWordWrap(80),
+		glamour.WithStylePath(theme.GlamourStyle),
+		glamour.WithWordWrap(wrapWidth),
 	)
 	if err != nil {
 		return nil, err
@@ -382,8 +638,8 @@ func createGlamourRenderer(themeName string) (*glamour.TermRenderer, error) {
 	// Create a new renderer with the custom Link style to hide URLs
 	// The format template returns empty string, effectively hiding the URL
 	renderer, err := glamour.NewTermRenderer(
-		glamour.WithStandardStyle(theme.GlamourStyle),
-		glamour.WithWordWrap(80),
+		glamour.WithStylePath(theme.GlamourStyle),
+		glamour.WithWordWrap(wrapWidth),
 		glamour.WithStylesFromJSONBytes([]byte(`{"link": {"format": "{{if false}}{{.text}}{{end}}"}}`)),
 	)
 
@@ -397,7 +653,7 @@ func createGlamourRenderer(themeName string) (*glamour.TermRenderer, error) {
 
 func NewModel(feedManager *feeds.Manager, taskManager tasks.Manager, queries *database.Queries, cfg config.Config) Model {
 	// Create glamour renderer based on theme
-	renderer, err := createGlamourRenderer(cfg.ThemeName)
+	renderer, err := createGlamourRenderer(themes.Resolve(cfg.ThemeName, true), defaultArticleWrapWidth)
 
 	if err != nil {
 		// Fallback to default renderer if creation fails
@@ -405,27 +661,36 @@ func NewModel(feedManager *feeds.Manager, taskManager tasks.Manager, queries *da
 	}
 
 	return Model{
-		feedManager:          feedManager,
-		taskManager:          taskManager,
-		queries:              queries,
-		config:               cfg,
-		glamourRenderer:      renderer,
-		state:                FeedListView,
-		cursor:               0,
-		savedItemCursor:      0,
-		savedFeedCursor:      0,
-		savedLogCursor:       0,
-		savedTasksCursor:     0,
-		savedSettingsCursor:  0,
-		refreshingFeeds:      make(map[int64]bool),
-		pendingFeeds:         []int64{},
-		maxConcurrency:       cfg.ReloadConcurrency,
-		spinnerFrame:         0,
-		spinnerRunning:       false,
-		firstAutoReload:      true,                // First reload should be suppressed if configured
-		pendingStartupReload: cfg.ReloadOnStartup, // Will trigger reload after feed list loads
-		expandedFolders:      make(map[string]bool),
-		folderStats:          make(map[string]struct{ UnreadItems, TotalItems int64 }),
+		feedManager:         feedManager,
+		taskManager:         taskManager,
+		taskEvents:          taskManager.Subscribe(),
+		queries:             queries,
+		config:              cfg,
+		glamourRenderer:     renderer,
+		glamourWrapWidth:    defaultArticleWrapWidth,
+		darkBackground:      true,
+		state:               FeedListView,
+		cursor:              0,
+		savedItemCursor:     0,
+		savedFeedCursor:     0,
+		savedLogCursor:      0,
+		savedTasksCursor:    0,
+		savedSettingsCursor: 0,
+		refreshingFeeds:     make(map[int64]bool),
+		pendingFeeds:        []int64{},
+		maxConcurrency:      cfg.ReloadConcurrency,
+		spinnerFrame:        0,
+		spinnerRunning:      false,
+		expandedFolders:     make(map[string]bool),
+		expandedClusters:    make(map[int64]bool),
+		skippedItems:        make(map[int64]int64),
+		folderStats:         make(map[string]struct{ UnreadItems, TotalItems int64 }),
+		feedFolders:         make(map[int64][]string),
+		keymap:              DefaultKeymap(),
+		imageProtocol:       termimage.Select(cfg.Images, os.Getenv),
+		cellSize:            termimage.TerminalCellSize(),
+		articleImages:       make(map[string]*termimage.Image),
+		articleCache:        newArticleCache(),
 	}
 }
 
@@ -433,12 +698,43 @@ func (m *Model) SetURLsFilePath(path string) {
 	m.urlsFilePath = path
 }
 
+// activeFeedCount returns the number of feeds that aren't paused
+func (m Model) activeFeedCount() int {
+	count := 0
+	for _, feed := range m.allFeeds {
+		if !feed.Paused {
+			count++
+		}
+	}
+	return count
+}
+
+// SetRefreshJob shows the countdown to the next auto reload of job, which
+// the task manager runs on its schedule
+func (m *Model) SetRefreshJob(job *tasks.RefreshJob) {
+	m.refreshJob = job
+	m.reloadTimerRunning = m.config.AutoReload && m.config.ReloadTime > 0
+}
+
+// SetKeymap replaces the default key bindings with the ones loaded from path
+func (m *Model) SetKeymap(keymap Keymap, path string) {
+	m.keymap = keymap
+	m.keysFilePath = path
+}
+
+// SetStatusMessage shows an info message when the UI starts
+func (m *Model) SetStatusMessage(message string) {
+	m.statusMessage = message
+	m.statusMessageType = "info"
+}
+
 func (m Model) Init() tea.Cmd {
 	var cmds []tea.Cmd
 	cmds = append(cmds,
 		loadFeedList(m.feedManager),
+		loadRules(m.feedManager),
 		tea.WindowSize(),
-		listenForTaskEvents(m.taskManager),
+		listenForTaskEvents(m.taskEvents),
 	)
 
 	// Check for updates on startup if enabled
@@ -446,10 +742,26 @@ func (m Model) Init() tea.Cmd {
 		cmds = append(cmds, checkForUpdate())
 	}
 
-	// Start the reload timer if auto reload is enabled
-	if m.config.AutoReload && m.config.ReloadTime > 0 {
-		// Note: nextReloadTime will be set in Update() when ReloadTimerMsg is processed
-		cmds = append(cmds, waitForReloadTimer(m.config.ReloadTime))
+	// The NewsGoat holding the lock cleans up and reloads when read-only
+	if m.readOnly {
+		return tea.Batch(cmds...)
+	}
+	if m.imageProtocol != termimage.ProtocolNone {
+		cmds = append(cmds, pruneImageCache(m.feedManager))
+	}
+	// The daemon cleans up, syncs and reloads for every window
+	if m.daemonPID != 0 {
+		cmds = append(cmds, waitForDaemonStatus(m.daemonSocket))
+		return tea.Batch(cmds...)
+	}
+	cmds = append(cmds, func() tea.Msg { return SyncTimerMsg{} })
+
+	// Reload on startup and clean up the log now, the task manager runs
+	// both on their schedules from then on
+	if m.refreshJob != nil {
+		cmds = append(cmds, runJobs(m.taskManager, tasks.RefreshJobName, tasks.LogCleanupJobName))
+	}
+	if m.reloadTimerRunning {
 		cmds = append(cmds, countdownTick())
 	}
 
@@ -457,18 +769,65 @@ func (m Model) Init() tea.Cmd {
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	updated, ok := model.(Model)
+	if !ok {
+		return model, cmd
+	}
+	if updated.imageProtocol != termimage.ProtocolNone {
+		updated, cmd = updated.updateArticleImages(cmd)
+	}
+	updated, cmd = updated.updateArticleCopies(cmd)
+	updated, cmd = updated.updateArticleRender(cmd)
+	updated, cmd = updated.updateItemPaging(cmd)
+	return updated.updateArticleSearch(cmd)
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if dark, ok := paletteChanged(msg); ok {
+		return m.backgroundChanged(dark)
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
+		logging.DebugCategory(logging.CategoryUI, "Window resized", "width", msg.Width, "height", msg.Height)
 		m.width = msg.Width
 		m.height = msg.Height
+		if m.imageProtocol != termimage.ProtocolNone {
+			m.cellSize = termimage.TerminalCellSize()
+		}
+		if m.articleWrapWidth() != m.glamourWrapWidth {
+			m.updateGlamourRenderer()
+		}
 		return m, nil
 
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
 	case tea.KeyMsg:
 		// Handle paste events for URL input and search
 		if msg.Paste {
 			if m.addingURL {
 				m.urlInput += string(msg.Runes)
 				return m, nil
+			} else if m.commandMode {
+				m.commandInput += string(msg.Runes)
+				return m, nil
+			} else if m.editingFolders {
+				m.folderInput += string(msg.Runes)
+				return m, nil
+			} else if m.addingRule {
+				m.ruleInput += string(msg.Runes)
+				return m, nil
+			} else if m.editingFeedInfo != "" {
+				m.feedInfoInput += string(msg.Runes)
+				return m, nil
+			} else if m.logSearching {
+				m.logSearchInput += string(msg.Runes)
+				return m, nil
+			} else if m.articleSearching {
+				m.articleSearchInput += string(msg.Runes)
+				return m, nil
 			} else if m.searchMode {
 				m.searchQuery += string(msg.Runes)
 				switch m.state {
@@ -479,7 +838,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.cursor = 0
 					m.savedItemCursor = 0
 				}
-				return m, performSearch(m.feedManager, m.state, m.selectedFeed, m.searchType, m.searchQuery)
+				return m, performSearch(m.feedManager, m.state, m.selectedFeed, m.searchType, m.searchQuery, m.unfilteredItemList)
 			}
 		}
 		return m.handleKeyPress(msg)
@@ -488,65 +847,44 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Store unfiltered feeds for reload operations
 		m.allFeeds = msg.Feeds
 		m.totalFeedCount = len(msg.Feeds)
+		m.starredStats = msg.Starred
 
-		// Filter feeds based on ShowReadFeeds config
-		var feedsToDisplay []database.GetFeedStatsRow
-		if m.config.ShowReadFeeds {
-			feedsToDisplay = msg.Feeds
-		} else {
-			// Filter out feeds with no unread items
-			for _, feed := range msg.Feeds {
-				if feed.UnreadItems > 0 {
-					feedsToDisplay = append(feedsToDisplay, feed)
-				}
-			}
-		}
+		m.feedFolders = msg.Folders
+		m.feedIcons = msg.Icons
+		m.rebuildFeedList()
+		// Note: if not in FeedListView, don't modify cursor or savedFeedCursor
+		// They will be set appropriately when we transition back to FeedListView
 
-		// Sort feeds if UnreadOnTop is enabled (before building display list)
-		if m.config.UnreadOnTop {
-			sort.SliceStable(feedsToDisplay, func(i, j int) bool {
-				// Feeds with unread items come first
-				iHasUnread := feedsToDisplay[i].UnreadItems > 0
-				jHasUnread := feedsToDisplay[j].UnreadItems > 0
-				if iHasUnread != jHasUnread {
-					return iHasUnread
-				}
-				// Within each group, maintain original order (stable sort)
-				return false
-			})
-		}
+		return m, m.countQueryFeeds()
 
-		// Build display list with folders
-		m.buildFeedDisplayList(feedsToDisplay)
+	case MoreItemsLoadedMsg:
+		return m.moreItemsLoaded(msg)
 
-		if m.state == FeedListView {
-			// Preserve cursor position when refreshing feed list
-			m.cursor = m.savedFeedCursor
-			if m.cursor >= len(m.feedList) {
-				m.cursor = max(0, len(m.feedList)-1)
-			}
-			m.savedFeedCursor = m.cursor
-		}
-		// Note: if not in FeedListView, don't modify cursor or savedFeedCursor
-		// They will be set appropriately when we transition back to FeedListView
+	case UpdateFeedStatsMsg:
+		return m.feedStatsUpdated(msg.Feed)
 
-		// Trigger reload on startup if configured and this is the first load
-		if m.pendingStartupReload && len(m.allFeeds) > 0 {
-			m.pendingStartupReload = false
-			return m, func() tea.Msg { return ReloadTimerMsg{} }
-		}
+	case QueryFeedCountsMsg:
+		m.setQueryFeedCounts(msg.Counts)
+		return m, nil
 
+	case FeedIconsLoadedMsg:
+		m.feedIcons = msg.Icons
 		return m, nil
 
 	case ItemListLoadedMsg:
-		m.itemList = msg.Items
+		items := m.applyItemFilter(msg.Items)
+
+		// Articles several feeds publish are shown once in aggregated lists
+		if m.config.HideDuplicates && isVirtualFeed(m.selectedFeed) {
+			items = feeds.HideDuplicates(items)
+		}
 
 		// Sort items if UnreadOnTop is enabled
 		if m.config.UnreadOnTop {
-			sort.SliceStable(m.itemList, func(i, j int) bool {
+			sort.SliceStable(items, func(i, j int) bool {
 				// Unread items (Read = false) come first
-				iIsUnread := !m.itemList[i].Read
-				jIsUnread := !m.itemList[j].Read
+				iIsUnread := !items[i].Read
+				jIsUnread := !items[j].Read
 				if iIsUnread != jIsUnread {
 					return iIsUnread
 				}
@@ -555,6 +893,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			})
 		}
 
+		// Build display list with story clusters
+		m.buildItemDisplayList(items)
+		m.loadedItems, m.moreItems, m.loadingItems = nil, msg.More, false
+		if msg.Paged {
+			m.loadedItems = loadedItemIDs(msg.Items)
+		}
+
 		if m.state == ItemListView {
 			// Preserve cursor position when refreshing
 			m.cursor = m.savedItemCursor
@@ -567,6 +912,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.cursor = 0
 			m.savedItemCursor = 0
 		}
+		if m.jumpingToUnread {
+			return m.unreadFeedLoaded()
+		}
 		return m, nil
 
 	case SearchResultsMsg:
@@ -601,6 +949,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			for i, result := range msg.ItemResults {
 				m.itemList[i] = database.GetItemsWithReadStatusRow(result)
 			}
+			m.searchMatches = msg.Matches
 			m.cursor = 0
 			m.savedItemCursor = 0
 		}
@@ -608,7 +957,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case LogListLoadedMsg:
 		m.logList = msg.Logs
-		if m.state == LogView {
+		m.logPage = msg.Page
+		m.logTotal = msg.Total
+		m.logStored = msg.Stored
+		m.logSize = msg.Size
+		if m.logFollow {
+			m.cursor = 0
+			m.savedLogCursor = 0
+		} else if m.state == LogView {
 			// Preserve cursor position when refreshing
 			m.cursor = m.savedLogCursor
 			if m.cursor >= len(m.logList) {
@@ -645,6 +1001,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case URLsReloadedMsg:
 		m.urlsList = msg.URLs
+		m.SetQueryFeeds(msg.QueryFeeds)
+		m.SetFeedDirections(msg.URLs)
 		// Set info message
 		m.statusMessage = "urls reloaded from " + msg.FilePath
 		m.statusMessageType = "info"
@@ -662,10 +1020,26 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case FeedInfoLoadedMsg:
 		m.currentFeed = msg.Feed
-		m.previousState = m.state
-		m.state = FeedInfoView
+		m.currentWebSub = msg.WebSub
+		m.currentFeedItems = msg.ItemCount
+		m.currentFeedRetryAt = msg.RetryAt
+		// An edited feed's info is loaded again in place
+		if m.state != FeedInfoView {
+			m.previousState = m.state
+			m.state = FeedInfoView
+			m.feedInfoStatus = ""
+		}
 		return m, nil
 
+	case FeedRenamedMsg:
+		return m.feedRenamed(msg)
+
+	case FeedURLChangedMsg:
+		return m.feedURLChanged(msg)
+
+	case FeedCacheClearedMsg:
+		return m.feedCacheCleared(msg)
+
 	case RefreshStartMsg:
 		m.refreshing = true
 		m.refreshStatus = msg.Status
@@ -677,7 +1051,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.state == ItemListView {
 			cmd = tea.Batch(
 				loadFeedList(m.feedManager),
-				loadItemList(m.feedManager, m.selectedFeed),
+				loadItemList(m.feedManager, m.selectedFeed, m.config, len(m.loadedItems)),
 			)
 		}
 		return m, tea.Batch(
@@ -723,10 +1097,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, func() tea.Msg { return ErrorMsg{Err: err} }
 		}
 
-		// Initialize pending feeds queue
-		m.pendingFeeds = make([]int64, len(feeds))
-		for i, feed := range feeds {
-			m.pendingFeeds[i] = feed.ID
+		// Initialize pending feeds queue, paused feeds are skipped
+		m.pendingFeeds = make([]int64, 0, len(feeds))
+		for _, feed := range feeds {
+			if feed.Paused {
+				continue
+			}
+			m.pendingFeeds = append(m.pendingFeeds, feed.ID)
 		}
 
 		// Start initial batch of feeds (up to maxConcurrency)
@@ -745,12 +1122,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		delete(m.refreshingFeeds, msg.FeedID)
 
 		// If we have more pending feeds, start the next one
-		cmd := loadFeedList(m.feedManager)
+		cmd := loadFeedStats(m.feedManager, msg.FeedID)
 		if len(m.pendingFeeds) > 0 {
 			cmd = tea.Batch(cmd, m.startNextBatchOfFeeds())
 		} else if len(m.refreshingFeeds) == 0 {
-			// No more refreshing feeds and no pending feeds - refresh all is complete
-			cmd = tea.Batch(cmd, func() tea.Msg { return RefreshCompleteMsg{} })
+			// No more refreshing feeds and no pending feeds - refresh all is
+			// complete, the whole list is loaded once to catch up on the rest
+			cmd = tea.Batch(loadFeedList(m.feedManager), func() tea.Msg { return RefreshCompleteMsg{} }, flushNotifications(m.feedManager))
+			// Feeds that redirect were moved in the URLs file
+			if m.feedManager.URLsFileChanged() {
+				cmd = tea.Batch(cmd, reloadURLsFromFile(m.feedManager))
+			}
 		}
 
 		return m, cmd
@@ -766,57 +1148,32 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.spinnerRunning = false
 		return m, nil
 
-	case ReloadTimerMsg:
-		// Check if we should suppress the first reload
-		if m.firstAutoReload && m.config.SuppressFirstReload {
-			// Skip this reload but mark that we've passed the first one
-			m.firstAutoReload = false
-		} else {
-			// Automatic reload triggered
-			if !m.refreshing && len(m.allFeeds) > 0 {
-				m.refreshing = true
-				m.refreshStatus = "Auto-refreshing all feeds..."
-
-				// Create tasks for all feeds (use allFeeds to include filtered feeds)
-				for _, feed := range m.allFeeds {
-					task := tasks.CreateFeedRefreshTask(feed.ID, feed.Url)
-					if err := m.taskManager.AddTask(task); err != nil {
-						continue
-					}
-				}
-
-				m.firstAutoReload = false
-			}
-		}
-
-		// Restart the timer for the next reload if auto reload is enabled
-		var cmds []tea.Cmd
-		if !m.refreshing || m.config.SuppressFirstReload {
-			// Only send RefreshStartMsg if we actually started a refresh
-			if m.refreshing {
-				cmds = append(cmds, func() tea.Msg { return RefreshStartMsg{Status: "Auto-refreshing all feeds..."} })
-			}
-		}
-		// Restart timer only if auto reload is enabled
-		if m.config.AutoReload && m.config.ReloadTime > 0 {
-			m.nextReloadTime = time.Now().Add(time.Duration(m.config.ReloadTime) * time.Minute)
-			cmds = append(cmds, waitForReloadTimer(m.config.ReloadTime))
-		}
-		return m, tea.Batch(cmds...)
+	case SyncTimerMsg:
+		m.startSync(false)
+		return m, waitForSyncTimer()
 
 	case RestartReloadTimerMsg:
-		// Restart the timer (triggered when config changes)
-		if m.config.AutoReload && m.config.ReloadTime > 0 {
-			m.nextReloadTime = time.Now().Add(time.Duration(m.config.ReloadTime) * time.Minute)
-			return m, tea.Batch(waitForReloadTimer(m.config.ReloadTime), countdownTick())
+		// Settings changed, the refresh job works out the next reload again
+		if m.refreshJob != nil && m.config.AutoReload && m.config.ReloadTime > 0 {
+			m.taskManager.RunJob(tasks.RefreshJobName)
+			if !m.reloadTimerRunning {
+				m.reloadTimerRunning = true
+				return m, countdownTick()
+			}
+			return m, nil
 		}
 		// Clear next reload time if auto reload is disabled
+		m.reloadTimerRunning = false
 		m.nextReloadTime = time.Time{}
 		return m, nil
 
+	case LogFollowTickMsg:
+		return m.logFollowTicked()
+
 	case CountdownTickMsg:
-		// Continue countdown ticker if auto reload is enabled
-		if m.config.AutoReload && m.config.ReloadTime > 0 {
+		// Continue countdown ticker while the auto reload timer is running
+		if m.reloadTimerRunning {
+			m.nextReloadTime = m.refreshJob.NextRefresh()
 			return m, countdownTick()
 		}
 		return m, nil
@@ -844,7 +1201,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						if !m.spinnerRunning {
 							m.spinnerRunning = true
 							var cmds []tea.Cmd
-							cmds = append(cmds, listenForTaskEvents(m.taskManager))
+							cmds = append(cmds, listenForTaskEvents(m.taskEvents))
 							cmds = append(cmds, spinnerTick())
 							// Refresh task list if we're viewing it
 							if m.state == TasksView {
@@ -859,7 +1216,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Refresh task list if we're viewing it
 			if m.state == TasksView {
 				return m, tea.Batch(
-					listenForTaskEvents(m.taskManager),
+					listenForTaskEvents(m.taskEvents),
 					loadTaskList(m.taskManager),
 				)
 			}
@@ -880,13 +1237,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						delete(m.refreshingFeeds, feedID)
 
 						var cmds []tea.Cmd
-						cmds = append(cmds, listenForTaskEvents(m.taskManager))
-						cmds = append(cmds, loadFeedList(m.feedManager))
+						cmds = append(cmds, listenForTaskEvents(m.taskEvents))
+						if len(m.refreshingFeeds) > 0 {
+							cmds = append(cmds, loadFeedStats(m.feedManager, feedID))
+						} else {
+							cmds = append(cmds, loadFeedList(m.feedManager))
+						}
 
 						// Refresh task list if we're viewing it
 						if m.state == TasksView {
 							cmds = append(cmds, loadTaskList(m.taskManager))
 						}
+						// Show the result in the info of the feed
+						if m.state == FeedInfoView && feedID == m.currentFeed.ID {
+							cmds = append(cmds, loadFeedInfo(m.queries, m.taskManager, feedID))
+						}
 
 						// Check if all refreshes are complete
 						if len(m.refreshingFeeds) == 0 && m.refreshing {
@@ -898,36 +1263,159 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
+			// Report where items sent to the read-later service ended up
+			if event.TaskType == tasks.TaskTypeReadLater {
+				status, statusType := "Saved for later", "info"
+				if title, ok := event.Data["title"].(string); ok && title != "" {
+					status += ": " + title
+				}
+				if event.Type == tasks.TaskEventFailed {
+					status, statusType = "Read later failed: "+event.Error, "error"
+				}
+				if m.state == ArticleView {
+					m.fullTextStatus = status
+				} else {
+					m.statusMessage = status
+					m.statusMessageType = statusType
+				}
+			}
+
+			// Report the result of pruning started with P in settings
+			if event.TaskType == tasks.TaskTypeCleanup {
+				if event.Type == tasks.TaskEventFailed {
+					m.statusMessage, m.statusMessageType = "Pruning failed: "+event.Error, "error"
+				} else {
+					m.statusMessage, m.statusMessageType = "Pruned old items", "info"
+					return m, tea.Batch(listenForTaskEvents(m.taskEvents), loadFeedList(m.feedManager))
+				}
+			}
+
+			// Report the sync and reload what it changed
+			if event.TaskType == tasks.TaskTypeSync {
+				cmds := []tea.Cmd{listenForTaskEvents(m.taskEvents), m.syncFinished(event)}
+				if m.state == TasksView {
+					cmds = append(cmds, loadTaskList(m.taskManager))
+				}
+				return m, tea.Batch(cmds...)
+			}
+
+			// Show the new badges once an item enrichment is done
+			if event.TaskType == tasks.TaskTypeItemEnrichment && event.Type == tasks.TaskEventCompleted && m.state == ItemListView {
+				return m, tea.Batch(
+					listenForTaskEvents(m.taskEvents),
+					loadItemList(m.feedManager, m.selectedFeed, m.config, len(m.loadedItems)),
+				)
+			}
+
+			// Show the icon of a site once it was looked up
+			if event.TaskType == tasks.TaskTypeFeedIcon && event.Type == tasks.TaskEventCompleted {
+				cmds := []tea.Cmd{listenForTaskEvents(m.taskEvents), loadFeedIcons(m.feedManager)}
+				if m.state == TasksView {
+					cmds = append(cmds, loadTaskList(m.taskManager))
+				}
+				return m, tea.Batch(cmds...)
+			}
+
+			// Report the result of installing the new version
+			if event.TaskType == tasks.TaskTypeUpdateInstall {
+				var result tea.Msg = UpdateInstallCompleteMsg{}
+				if event.Type == tasks.TaskEventFailed {
+					result = UpdateInstallErrorMsg{err: errors.New(event.Error)}
+				}
+				return m, tea.Batch(listenForTaskEvents(m.taskEvents), func() tea.Msg { return result })
+			}
+
+			// Say when the scheduled update check found a newer release
+			if event.TaskType == tasks.TaskTypeUpdateCheck && event.Type == tasks.TaskEventCompleted {
+				if latest, ok := event.Data["latest_version"].(string); ok && (m.updateInfo == nil || m.updateInfo.LatestVersion != latest) {
+					current, _ := event.Data["current_version"].(string)
+					downloadURL, _ := event.Data["download_url"].(string)
+					update := UpdateAvailableMsg{CurrentVersion: current, LatestVersion: latest, DownloadURL: downloadURL}
+					return m, tea.Batch(listenForTaskEvents(m.taskEvents), func() tea.Msg { return update })
+				}
+			}
+
 			// Refresh task list if we're viewing it (for non-feed-refresh tasks)
 			if m.state == TasksView {
 				return m, tea.Batch(
-					listenForTaskEvents(m.taskManager),
+					listenForTaskEvents(m.taskEvents),
+					loadTaskList(m.taskManager),
+				)
+			}
+
+		case tasks.TaskEventProgress:
+			// Show how much of the new version was downloaded
+			if event.TaskType == tasks.TaskTypeUpdateInstall && event.Progress != nil && m.updateInfo != nil {
+				m.statusMessage = "Updating to " + m.updateInfo.LatestVersion + ": " + event.Progress.Message
+				m.statusMessageType = "info"
+			}
+			// Show how far running tasks got
+			if m.state == TasksView {
+				return m, tea.Batch(
+					listenForTaskEvents(m.taskEvents),
 					loadTaskList(m.taskManager),
 				)
 			}
+
+		case tasks.TaskEventScheduled:
+			// The refresh job queued the feeds that are due
+			if job, _ := event.Data["job"].(string); job == tasks.RefreshJobName {
+				counts, _ := event.Data["tasks"].(map[tasks.TaskType]int)
+				if due := counts[tasks.TaskTypeFeedRefresh]; due > 0 {
+					status := "Auto-refreshing all feeds..."
+					if due < m.activeFeedCount() {
+						status = fmt.Sprintf("Auto-refreshing %d feeds...", due)
+					}
+					return m, tea.Batch(
+						listenForTaskEvents(m.taskEvents),
+						func() tea.Msg { return RefreshStartMsg{Status: status} },
+					)
+				}
+			}
 		}
 
 		// Continue listening for task events
-		return m, listenForTaskEvents(m.taskManager)
+		return m, listenForTaskEvents(m.taskEvents)
 
 	case AllItemsMarkedReadMsg:
+		if len(msg.ItemIDs) > 0 {
+			m.pushUndo(msg.ItemIDs)
+		}
+
 		// Items were marked as read, reload the appropriate lists
 		var cmds []tea.Cmd
 		cmds = append(cmds, loadFeedList(m.feedManager))
 
 		// If we're in the item list view for this feed, reload it too
 		if m.state == ItemListView && m.selectedFeed == msg.FeedID {
-			cmds = append(cmds, loadItemList(m.feedManager, msg.FeedID))
+			cmds = append(cmds, loadItemList(m.feedManager, msg.FeedID, m.config, len(m.loadedItems)))
 		}
 
 		return m, tea.Batch(cmds...)
 
+	case ItemsRestoredMsg:
+		m.restored(msg.Count)
+		cmds := []tea.Cmd{loadFeedList(m.feedManager)}
+		if m.state == ItemListView {
+			cmds = append(cmds, loadItemList(m.feedManager, m.selectedFeed, m.config, len(m.loadedItems)))
+		}
+		return m, tea.Batch(cmds...)
+
 	case ItemReadStatusToggledMsg:
 		// Item read status was toggled, reload the item list and feed list
 		var cmds []tea.Cmd
 		cmds = append(cmds, loadFeedList(m.feedManager))
 		if m.state == ItemListView {
-			cmds = append(cmds, loadItemList(m.feedManager, m.selectedFeed))
+			cmds = append(cmds, loadItemList(m.feedManager, m.selectedFeed, m.config, len(m.loadedItems)))
+		}
+		return m, tea.Batch(cmds...)
+
+	case ItemStarToggledMsg:
+		// Item was starred or unstarred, reload the item list and feed list
+		var cmds []tea.Cmd
+		cmds = append(cmds, loadFeedList(m.feedManager))
+		if m.state == ItemListView {
+			cmds = append(cmds, loadItemList(m.feedManager, m.selectedFeed, m.config, len(m.loadedItems)))
 		}
 		return m, tea.Batch(cmds...)
 
@@ -942,6 +1430,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Reload feed list and sync feeds
 		return m, tea.Batch(loadFeedList(m.feedManager), reloadURLsFromFile(m.feedManager))
 
+	case DaemonStatusMsg:
+		return m.daemonStatusUpdated(msg)
+
+	case ReadStateChangedMsg:
+		cmds := []tea.Cmd{loadFeedList(m.feedManager)}
+		if m.state == ItemListView {
+			cmds = append(cmds, loadItemList(m.feedManager, m.selectedFeed, m.config, len(m.loadedItems)))
+		}
+		return m, tea.Batch(cmds...)
+
+	case AddURLMsg:
+		m.statusMessage = "Adding feed from link: " + msg.URL
+		m.statusMessageType = "info"
+		return m, addURLAndDiscover(m.feedManager, msg.URL)
+
 	case URLAddErrorMsg:
 		// Set error message
 		m.statusMessage = msg.Err
@@ -956,13 +1459,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			LatestVersion:  msg.LatestVersion,
 			DownloadURL:    msg.DownloadURL,
 		}
-		m.statusMessage = "Update available: " + msg.LatestVersion + " (press ctrl-u to upgrade)"
+		m.statusMessage = "New version " + msg.LatestVersion + " available — press ctrl+g to update"
 		m.statusMessageType = "info"
 		return m, nil
 
 	case UpdateCheckErrorMsg:
 		// Silently ignore update check errors (don't disturb the user)
-		logging.Debug("Update check failed", "error", msg.err)
+		logging.DebugCategory(logging.CategoryHTTP, "Update check failed", "error", msg.err)
 		return m, nil
 
 	case UpdateInstallStartMsg:
@@ -983,77 +1486,353 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.installingUpdate = false
 		return m, nil
 
-	case ErrorMsg:
-		m.err = msg.Err
-		m.refreshing = false
-		m.refreshStatus = ""
-		m.refreshingFeeds = make(map[int64]bool)
+	case ArticleExportedMsg:
+		return m.articleExported(msg), nil
+
+	case ArticlePipedMsg:
+		if m.state != ArticleView || msg.ItemID != m.currentItem.ID {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.fullTextStatus = "Pipe failed: " + msg.Err.Error()
+		} else {
+			m.fullTextStatus = "Piped to " + m.config.PipeCommand
+		}
 		return m, nil
-	}
 
-	return m, nil
-}
+	case NextUnreadFeedMsg:
+		return m.nextUnreadFeedFound(msg)
 
-func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch m.state {
-	case FeedListView:
-		return m.handleFeedListKeys(msg)
-	case ItemListView:
-		return m.handleItemListKeys(msg)
-	case ArticleView:
-		return m.handleArticleKeys(msg)
-	case FeedInfoView:
-		return m.handleFeedInfoKeys(msg)
-	case LogView:
-		return m.handleLogListKeys(msg)
-	case LogDetailView:
-		return m.handleLogDetailKeys(msg)
-	case TasksView:
-		return m.handleTasksViewKeys(msg)
-	case HelpView:
-		return m.handleHelpViewKeys(msg)
-	case SettingsView:
-		return m.handleSettingsViewKeys(msg)
-	case URLsView:
-		return m.handleURLsViewKeys(msg)
-	}
-	return m, nil
-}
+	case StatsLoadedMsg:
+		m.stats, m.statsErr = msg.Stats, msg.Err
+		return m, nil
 
-func (m Model) handleFeedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	// Clear status message and quit state on any keypress (except 'q' and 'ctrl+c' themselves)
-	key := msg.String()
-	if key != "q" && key != "ctrl+c" {
-		if m.statusMessage != "" {
-			m.statusMessage = ""
-			m.statusMessageType = ""
-		}
-		m.quitPressed = false
-		m.ctrlCPressed = false
-	}
+	case RulesLoadedMsg:
+		m.rules = msg.Rules
+		m.rulesCursor = min(m.rulesCursor, max(len(m.rules)-1, 0))
+		return m, nil
 
-	// Handle URL adding mode separately
-	if m.addingURL {
-		switch msg.String() {
-		case "esc", "ctrl+c":
-			// Cancel URL adding
-			m.addingURL = false
-			m.urlInput = ""
+	case RuleChangedMsg:
+		if msg.Err != nil {
+			m.rulesStatus = "Failed: " + msg.Err.Error()
 			return m, nil
-		case "enter":
-			// Submit URL
-			if m.urlInput != "" {
-				url := m.urlInput
-				m.addingURL = false
-				m.urlInput = ""
-				return m, addURLAndDiscover(m.feedManager, url)
-			}
-			// Empty input, just cancel
-			m.addingURL = false
+		}
+		m.rulesStatus = msg.Status
+		return m, loadRules(m.feedManager)
+
+	case ArticleSharedMsg:
+		if m.state != ArticleView || msg.ItemID != m.currentItem.ID {
 			return m, nil
-		case "backspace":
-			// Remove last character
-			if len(m.urlInput) > 0 {
+		}
+		if msg.Err != nil {
+			m.fullTextStatus = "Share failed: " + msg.Err.Error()
+		} else {
+			m.fullTextStatus = "Shared with " + msg.Name
+		}
+		return m, nil
+
+	case RawHTMLSavedMsg:
+		if m.state != ArticleView || msg.ItemID != m.currentItem.ID {
+			return m, nil
+		}
+		switch {
+		case msg.Paged && msg.Err != nil:
+			m.fullTextStatus = "Pager failed: " + msg.Err.Error()
+		case msg.Paged:
+		case msg.Err != nil:
+			m.fullTextStatus = "Saving raw HTML failed: " + msg.Err.Error()
+		default:
+			m.fullTextStatus = "Raw HTML saved to " + msg.Path
+		}
+		return m, nil
+
+	case FeedPausedMsg:
+		if msg.Err != nil {
+			m.statusMessage = "Pause failed: " + msg.Err.Error()
+			m.statusMessageType = "error"
+			return m, nil
+		}
+		if msg.Paused {
+			m.statusMessage = "Paused " + msg.Title
+		} else {
+			m.statusMessage = "Resumed " + msg.Title
+		}
+		m.statusMessageType = "info"
+		return m, loadFeedList(m.feedManager)
+
+	case FeedFoldersSetMsg:
+		return m.foldersSet(msg)
+
+	case FeedRediscoveredMsg:
+		if msg.Err != nil {
+			m.statusMessage = "No new URL for " + msg.Title + ": " + msg.Err.Error()
+			m.statusMessageType = "error"
+			return m, nil
+		}
+		m.statusMessage = msg.Title + " moved to " + msg.URL
+		m.statusMessageType = "info"
+		m.urlsList = msg.URLs
+		m.SetFeedDirections(msg.URLs)
+		cmds := []tea.Cmd{syncFeedsWithURLs(m.feedManager, m.queries, msg.URLs)}
+		if !m.refreshing {
+			if err := m.taskManager.AddTask(tasks.CreateFeedRefreshTask(msg.FeedID, msg.URL)); err == nil {
+				m.refreshing = true
+				m.refreshStatus = "Refreshing feed..."
+				cmds = append(cmds, func() tea.Msg { return RefreshStartMsg{Status: "Refreshing feed..."} })
+			}
+		}
+		return m, tea.Batch(cmds...)
+
+	case FetchReportLoadedMsg:
+		if msg.Err != nil {
+			m.statusMessage = "Test fetch failed: " + msg.Err.Error()
+			m.statusMessageType = "error"
+			return m, nil
+		}
+		m.statusMessage = ""
+		m.fetchReport = msg.Report
+		m.fetchReportScroll = 0
+		m.previousState = m.state
+		m.state = FetchReportView
+		return m, nil
+
+	case LogCopiedMsg:
+		if msg.Err != nil {
+			m.logStatus = "Copy failed: " + msg.Err.Error()
+		} else {
+			m.logStatus = "Copied to clipboard"
+		}
+		return m, nil
+
+	case ClipboardCopiedMsg:
+		status, statusType := "Copied "+msg.What+" to clipboard", "info"
+		if msg.Err != nil {
+			status, statusType = "Copy failed: "+msg.Err.Error(), "error"
+		}
+		if m.state == ArticleView {
+			m.fullTextStatus = status
+		} else {
+			m.statusMessage, m.statusMessageType = status, statusType
+		}
+		return m, nil
+
+	case ArticleRenderedMsg:
+		if msg.Article.key == m.articleRendering {
+			m.articleRendering = articleKey{}
+		}
+		// Only shown when its article still is, but kept for going back to it
+		m.articleCache.put(msg.Article)
+		if m.state == ArticleView && msg.Article.key == m.articleJob().key {
+			m.article = msg.Article
+		}
+		return m, nil
+
+	case ItemCopiesLoadedMsg:
+		if msg.ItemID == m.currentItem.ID {
+			m.articleCopies = msg.Feeds
+		}
+		return m, nil
+
+	case ArticleImageLoadedMsg:
+		if msg.ItemID == m.currentItem.ID {
+			m.articleImages[msg.URL] = msg.Image
+		}
+		return m, nil
+
+	case FullContentFetchedMsg:
+		// Ignore results for an article that is no longer shown
+		if m.state != ArticleView || msg.ItemID != m.currentItem.ID {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.fullTextStatus = "Full article unavailable: " + msg.Err.Error()
+			return m, nil
+		}
+		m.fullTextStatus = ""
+		m.currentItem.FullContent = msg.Content
+		for i := range m.itemList {
+			if m.itemList[i].ID == msg.ItemID {
+				m.itemList[i].FullContent = msg.Content
+			}
+		}
+		m.links = m.feedManager.ExtractLinks(msg.Content)
+		m.showRawHTML = false
+		m.articleViewScroll = 0
+		return m, nil
+
+	case ErrorMsg:
+		m.err = msg.Err
+		m.refreshing = false
+		m.refreshStatus = ""
+		m.refreshingFeeds = make(map[int64]bool)
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	logging.DebugCategory(logging.CategoryUI, "Key pressed", "key", msg.String(), "view", m.state)
+
+	typing := m.addingURL || m.searchMode || m.editingSettings || m.commandMode || m.editingFolders || m.addingRule || m.articleSearching || m.capturingKey || m.editingFeedInfo != "" || m.logSearching || m.exportingArticle
+
+	// , followed by a key runs the macro bound to it
+	if m.macroPending && !typing {
+		m.macroPending = false
+		return m.runMacro(msg.String())
+	}
+	if msg.String() == "," && m.config.Macros != "" && !typing {
+		m.macroPending = true
+		return m, nil
+	}
+
+	// u undoes marking items read while the status line offers it
+	if msg.String() == "u" && m.undoOffered() && !typing {
+		return m.undo()
+	}
+
+	// Translate rebound keys to the keys the handlers switch on, text being
+	// typed and keys being bound are left alone
+	if !typing {
+		key, ok := m.keymap.Resolve(scopeForView(m.state), msg.String())
+		if !ok {
+			return m, nil
+		}
+		if key != msg.String() {
+			msg = keyMsgFor(key, msg.Paste)
+		}
+	}
+	return m.handleResolvedKey(msg)
+}
+
+// handleResolvedKey passes a key, already translated to the default key of
+// its action, to the handler of the current view
+func (m Model) handleResolvedKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.commandMode {
+		return m.handleCommandKeys(msg)
+	}
+
+	if m.editingFolders {
+		return m.handleFolderKeys(msg)
+	}
+
+	if m.addingRule {
+		return m.handleRuleInputKeys(msg)
+	}
+
+	if m.articleSearching {
+		return m.handleArticleSearchKeys(msg)
+	}
+
+	if m.editingFeedInfo != "" {
+		return m.handleFeedInfoEditKeys(msg)
+	}
+
+	if m.logSearching {
+		return m.handleLogSearchKeys(msg)
+	}
+
+	if m.exportingArticle {
+		return m.handleExportPromptKeys(msg)
+	}
+
+	if !m.addingURL && !m.searchMode && !m.editingSettings && !m.addingRule && m.readOnlyAction(msg.String()) {
+		m.statusMessage = m.readOnlyMessage()
+		m.statusMessageType = "error"
+		return m, nil
+	}
+
+	switch m.state {
+	case FeedListView:
+		return m.handleFeedListKeys(msg)
+	case ItemListView:
+		return m.handleItemListKeys(msg)
+	case ArticleView:
+		return m.handleArticleKeys(msg)
+	case FeedInfoView:
+		return m.handleFeedInfoKeys(msg)
+	case LogView:
+		return m.handleLogListKeys(msg)
+	case LogDetailView:
+		return m.handleLogDetailKeys(msg)
+	case TasksView:
+		return m.handleTasksViewKeys(msg)
+	case HelpView:
+		return m.handleHelpViewKeys(msg)
+	case SettingsView:
+		return m.handleSettingsViewKeys(msg)
+	case URLsView:
+		return m.handleURLsViewKeys(msg)
+	case KeymapView:
+		return m.handleKeymapViewKeys(msg)
+	case FetchReportView:
+		return m.handleFetchReportKeys(msg)
+	case TagView:
+		return m.handleTagViewKeys(msg)
+	case ShareView:
+		return m.handleShareViewKeys(msg)
+	case RulesView:
+		return m.handleRulesViewKeys(msg)
+	case StatsView:
+		return m.handleStatsViewKeys(msg)
+	}
+	return m, nil
+}
+
+// installUpdate installs the new version in a task, the status bar shows how
+// much was downloaded
+func (m Model) installUpdate() (tea.Model, tea.Cmd) {
+	// Check write permission before attempting update
+	if err := updater.CheckWritePermission(); err != nil {
+		m.statusMessage = fmt.Sprintf("Update failed: %v", err)
+		m.statusMessageType = "error"
+		return m, nil
+	}
+	if err := m.taskManager.AddTask(tasks.CreateUpdateInstallTask(m.updateInfo.LatestVersion, m.updateInfo.DownloadURL)); err != nil {
+		m.statusMessage = fmt.Sprintf("Update failed: %v", err)
+		m.statusMessageType = "error"
+		return m, nil
+	}
+	m.installingUpdate = true
+	m.statusMessage = "Downloading " + m.updateInfo.LatestVersion + "..."
+	m.statusMessageType = "info"
+	return m, nil
+}
+
+func (m Model) handleFeedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Clear status message and quit state on any keypress (except 'q' and 'ctrl+c' themselves)
+	key := msg.String()
+	if key != "q" && key != "ctrl+c" {
+		if m.statusMessage != "" {
+			m.statusMessage = ""
+			m.statusMessageType = ""
+		}
+		m.quitPressed = false
+		m.ctrlCPressed = false
+	}
+
+	// Handle URL adding mode separately
+	if m.addingURL {
+		switch msg.String() {
+		case "esc", "ctrl+c":
+			// Cancel URL adding
+			m.addingURL = false
+			m.urlInput = ""
+			return m, nil
+		case "enter":
+			// Submit URL
+			if m.urlInput != "" {
+				url := m.urlInput
+				m.addingURL = false
+				m.urlInput = ""
+				return m, addURLAndDiscover(m.feedManager, url)
+			}
+			// Empty input, just cancel
+			m.addingURL = false
+			return m, nil
+		case "backspace":
+			// Remove last character
+			if len(m.urlInput) > 0 {
 				m.urlInput = m.urlInput[:len(m.urlInput)-1]
 			}
 			return m, nil
@@ -1082,6 +1861,7 @@ func (m Model) handleFeedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.savedFeedCursor = 0
 			case ItemListView:
 				m.itemList = m.unfilteredItemList
+				m.searchMatches = nil
 				m.cursor = 0
 				m.savedItemCursor = 0
 			}
@@ -1092,7 +1872,7 @@ func (m Model) handleFeedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if m.searchType != GlobalSearch {
 				m.searchType = GlobalSearch
 				// Trigger search with current query
-				return m, performSearch(m.feedManager, m.state, m.selectedFeed, m.searchType, m.searchQuery)
+				return m, performSearch(m.feedManager, m.state, m.selectedFeed, m.searchType, m.searchQuery, m.unfilteredItemList)
 			}
 			return m, nil
 
@@ -1101,7 +1881,7 @@ func (m Model) handleFeedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if m.searchType != TitleSearch {
 				m.searchType = TitleSearch
 				// Trigger search with current query
-				return m, performSearch(m.feedManager, m.state, m.selectedFeed, m.searchType, m.searchQuery)
+				return m, performSearch(m.feedManager, m.state, m.selectedFeed, m.searchType, m.searchQuery, m.unfilteredItemList)
 			}
 			return m, nil
 
@@ -1115,6 +1895,7 @@ func (m Model) handleFeedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					m.feedList = m.unfilteredFeedList
 				case ItemListView:
 					m.itemList = m.unfilteredItemList
+					m.searchMatches = nil
 				}
 			} else {
 				m.searchMode = false
@@ -1142,11 +1923,12 @@ func (m Model) handleFeedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 						m.feedList = m.unfilteredFeedList
 					case ItemListView:
 						m.itemList = m.unfilteredItemList
+						m.searchMatches = nil
 					}
 					return m, nil
 				}
 				// Trigger search with updated query
-				return m, performSearch(m.feedManager, m.state, m.selectedFeed, m.searchType, m.searchQuery)
+				return m, performSearch(m.feedManager, m.state, m.selectedFeed, m.searchType, m.searchQuery, m.unfilteredItemList)
 			}
 			return m, nil
 
@@ -1164,7 +1946,7 @@ func (m Model) handleFeedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					m.savedItemCursor = 0
 				}
 				// Trigger search with updated query
-				return m, performSearch(m.feedManager, m.state, m.selectedFeed, m.searchType, m.searchQuery)
+				return m, performSearch(m.feedManager, m.state, m.selectedFeed, m.searchType, m.searchQuery, m.unfilteredItemList)
 			}
 			return m, nil
 		}
@@ -1181,6 +1963,11 @@ func (m Model) handleFeedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// Show every feed again when the list is narrowed to a tag
+		if m.selectedTag != "" {
+			return m.selectTag("")
+		}
+
 		// If on a folder or a feed inside a folder, collapse the folder
 		if len(m.feedList) > 0 && m.cursor < len(m.feedList) {
 			item := m.feedList[m.cursor]
@@ -1204,11 +1991,11 @@ func (m Model) handleFeedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 				// Keep cursor on the folder
 				return m, nil
-			} else if item.IsUnderFolder {
+			} else if item.IsUnderFolder || (item.IsFolder && item.Depth > 0) {
 				// Find the parent folder and collapse it
 				// Search backwards to find the folder
 				for i := m.cursor - 1; i >= 0; i-- {
-					if m.feedList[i].IsFolder {
+					if m.feedList[i].IsFolder && m.feedList[i].Depth == item.Depth-1 {
 						folderName := m.feedList[i].FolderName
 						m.expandedFolders[folderName] = false
 
@@ -1277,6 +2064,18 @@ func (m Model) handleFeedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.savedFeedCursor = m.cursor
 		}
 
+	case "left", "right":
+		// Move between columns in the columns layout
+		rows, cols := m.feedColumnLayout()
+		if cols > 1 {
+			if msg.String() == "left" && m.cursor-rows >= 0 {
+				m.cursor -= rows
+			} else if msg.String() == "right" && m.cursor/rows < (len(m.feedList)-1)/rows {
+				m.cursor = min(m.cursor+rows, len(m.feedList)-1)
+			}
+			m.savedFeedCursor = m.cursor
+		}
+
 	case "ctrl+d":
 		if len(m.feedList) > 0 {
 			pageSize := m.height / 2
@@ -1287,19 +2086,16 @@ func (m Model) handleFeedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.savedFeedCursor = m.cursor
 		}
 
+	case "ctrl+g":
+		if m.updateAvailable && m.updateInfo != nil && !m.installingUpdate {
+			return m.installUpdate()
+		}
+
 	case "ctrl+u":
-		// If update is available, install it (takes priority)
+		// Installed updates before ctrl+g did, and still does while one is
+		// available
 		if m.updateAvailable && m.updateInfo != nil && !m.installingUpdate {
-			// Check write permission before attempting update
-			if err := updater.CheckWritePermission(); err != nil {
-				m.statusMessage = fmt.Sprintf("Update failed: %v", err)
-				m.statusMessageType = "error"
-				return m, nil
-			}
-			m.installingUpdate = true
-			m.statusMessage = "Installing update..."
-			m.statusMessageType = "info"
-			return m, installUpdate(m.updateInfo.DownloadURL)
+			return m.installUpdate()
 		}
 		// Otherwise, scroll up by half a page
 		if len(m.feedList) > 0 {
@@ -1362,25 +2158,50 @@ func (m Model) handleFeedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.state = ItemListView
 				m.cursor = 0
 				m.savedItemCursor = 0
-				return m, loadItemList(m.feedManager, m.selectedFeed)
+				m.loadedItems, m.moreItems = nil, false
+				return m, loadItemList(m.feedManager, m.selectedFeed, m.config, len(m.loadedItems))
 			}
 		}
 
 	case "R":
 		if !m.refreshing {
-			m.refreshing = true
-			m.refreshStatus = "Refreshing all feeds..."
-
-			// Create tasks for all feeds (use allFeeds to include filtered feeds)
+			// Create tasks for all feeds (use allFeeds to include filtered
+			// feeds), the likeliest to have changed first
+			var feedIDs []int64
+			feedURLs := make(map[int64]string)
 			for _, feed := range m.allFeeds {
-				task := tasks.CreateFeedRefreshTask(feed.ID, feed.Url)
+				if feed.Paused {
+					continue
+				}
+				feedIDs = append(feedIDs, feed.ID)
+				feedURLs[feed.ID] = feed.Url
+			}
+			plan, err := m.feedManager.PlanRefreshAll(feedIDs, time.Now())
+			if err != nil {
+				logging.Warn("Failed to plan refresh, refreshing every feed", "error", err)
+				plan = feeds.RefreshPlan{FeedIDs: feedIDs}
+			}
+			if len(plan.FeedIDs) == 0 {
+				m.statusMessage = fmt.Sprintf("Nothing to refresh, skipped %d fresh feeds", plan.Fresh)
+				m.statusMessageType = "info"
+				return m, nil
+			}
+
+			status := "Refreshing all feeds..."
+			if plan.Fresh > 0 {
+				status = fmt.Sprintf("Refreshing %d feeds, skipped %d fresh feeds...", len(plan.FeedIDs), plan.Fresh)
+			}
+			m.refreshing = true
+			m.refreshStatus = status
+			for _, feedID := range plan.FeedIDs {
+				task := tasks.CreateFeedRefreshTask(feedID, feedURLs[feedID])
 				if err := m.taskManager.AddTask(task); err != nil {
 					// If task creation fails, log it but continue with other feeds
 					continue
 				}
 			}
 
-			return m, func() tea.Msg { return RefreshStartMsg{Status: "Refreshing all feeds..."} }
+			return m, func() tea.Msg { return RefreshStartMsg{Status: status} }
 		}
 
 	case "r":
@@ -1401,12 +2222,15 @@ func (m Model) handleFeedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 
-				// Find feeds in this folder and create tasks
+				// Find feeds in this folder and create tasks, paused feeds are skipped
 				for _, feed := range allFeeds {
+					if feed.Paused {
+						continue
+					}
 					folders, err := m.queries.GetFeedFolders(ctx, feed.ID)
 					if err == nil {
 						for _, folder := range folders {
-							if folder == item.FolderName {
+							if inFolder(folder, item.FolderName) {
 								task := tasks.CreateFeedRefreshTask(feed.ID, feed.Url)
 								if err := m.taskManager.AddTask(task); err != nil {
 									logging.Error("Failed to add refresh task", "feedID", feed.ID, "error", err)
@@ -1418,7 +2242,7 @@ func (m Model) handleFeedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				}
 
 				return m, func() tea.Msg { return RefreshStartMsg{Status: "Refreshing folder..."} }
-			} else {
+			} else if !isVirtualFeed(item.Feed.ID) {
 				// Refresh single feed
 				m.refreshing = true
 				m.refreshStatus = "Refreshing feed..."
@@ -1436,10 +2260,46 @@ func (m Model) handleFeedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 	case "l":
-		m.state = LogView
+		return m.openLogView(nil)
+
+	case "K":
+		m.previousState = m.state
+		m.state = KeymapView
+		m.keymapViewScroll = 0
+		m.keymapCursor = 0
+		return m, nil
+
+	case "T":
+		// Pick a folder to show only its feeds
+		return m.openTagView(), nil
+
+	case "X":
+		// Rules hiding or marking read items at refresh time
+		return m.openRulesView()
+
+	case "S":
+		// Reading habits and feed activity
+		return m.openStatsView()
+
+	case "n":
+		// Jump to the next feed with unread items
+		return m.jumpToUnreadFeed(1), nil
+
+	case "N":
+		// Jump to the previous feed with unread items
+		return m.jumpToUnreadFeed(-1), nil
+
+	case "H":
+		// Show unread items of all feeds ranked best-first
+		m.searchMode = false
+		m.searchActive = false
+		m.searchQuery = ""
+		m.selectedFeed = HotItemsFeedID
+		m.state = ItemListView
 		m.cursor = 0
-		m.savedLogCursor = 0
-		return m, loadLogList(m.feedManager)
+		m.savedItemCursor = 0
+		m.loadedItems, m.moreItems = nil, false
+		return m, loadItemList(m.feedManager, m.selectedFeed, m.config, len(m.loadedItems))
 
 	case "t":
 		m.state = TasksView
@@ -1473,8 +2333,59 @@ func (m Model) handleFeedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Show feed info (only for feeds, not folders)
 		if len(m.feedList) > 0 && m.cursor < len(m.feedList) {
 			item := m.feedList[m.cursor]
-			if !item.IsFolder {
-				return m, loadFeedInfo(m.queries, item.Feed.ID)
+			if !item.IsFolder && !isVirtualFeed(item.Feed.ID) {
+				return m, loadFeedInfo(m.queries, m.taskManager, item.Feed.ID)
+			}
+		}
+
+	case "p":
+		// Pause or resume refreshing the highlighted feed
+		if len(m.feedList) > 0 && m.cursor < len(m.feedList) {
+			item := m.feedList[m.cursor]
+			if !item.IsFolder && !isVirtualFeed(item.Feed.ID) {
+				return m, toggleFeedPaused(m.feedManager, *item.Feed)
+			}
+		}
+
+	case "f":
+		// Change the folders of the highlighted feed
+		if len(m.feedList) > 0 && m.cursor < len(m.feedList) {
+			item := m.feedList[m.cursor]
+			if !item.IsFolder && !isVirtualFeed(item.Feed.ID) {
+				return m.startFolderEdit(*item.Feed), nil
+			}
+		}
+
+	case "D":
+		// Move the highlighted feed to where it redirects, or look for its
+		// new URL when it keeps failing
+		if len(m.feedList) > 0 && m.cursor < len(m.feedList) {
+			item := m.feedList[m.cursor]
+			if !item.IsFolder && !isVirtualFeed(item.Feed.ID) {
+				if movedURL := m.feedManager.MovedURL(item.Feed.ID); movedURL != "" {
+					m.statusMessage = "Moving " + getDisplayTitle(*item.Feed) + " to " + movedURL + "..."
+					m.statusMessageType = "info"
+					return m, rediscoverFeed(m.feedManager, *item.Feed)
+				}
+				if !item.Feed.LastError.Valid {
+					m.statusMessage = getDisplayTitle(*item.Feed) + " isn't failing"
+					m.statusMessageType = "info"
+					return m, nil
+				}
+				m.statusMessage = "Looking for a new URL of " + getDisplayTitle(*item.Feed) + "..."
+				m.statusMessageType = "info"
+				return m, rediscoverFeed(m.feedManager, *item.Feed)
+			}
+		}
+
+	case "F":
+		// Fetch the highlighted feed without saving anything and show a report
+		if len(m.feedList) > 0 && m.cursor < len(m.feedList) {
+			item := m.feedList[m.cursor]
+			if !item.IsFolder && !isVirtualFeed(item.Feed.ID) {
+				m.statusMessage = "Test fetching " + getDisplayTitle(*item.Feed) + "..."
+				m.statusMessageType = "info"
+				return m, testFetchFeed(m.feedManager, item.Feed.ID)
 			}
 		}
 
@@ -1485,12 +2396,18 @@ func (m Model) handleFeedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if item.IsFolder {
 				// Mark all feeds in this folder as read
 				return m, markAllItemsReadInFolder(m.feedManager, m.queries, item.FolderName)
-			} else {
+			} else if !isVirtualFeed(item.Feed.ID) {
 				// Mark all items in single feed as read
 				return m, markAllItemsReadInFeed(m.feedManager, item.Feed.ID)
 			}
 		}
 
+	case ":":
+		// Open the command prompt, e.g. to filter the feed list
+		m.commandMode = true
+		m.commandInput = ""
+		return m, nil
+
 	case "/":
 		// Enter global search mode
 		m.searchMode = true
@@ -1502,8 +2419,8 @@ func (m Model) handleFeedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.unfilteredFeedList = make([]FeedListItem, len(m.feedList))
 			copy(m.unfilteredFeedList, m.feedList)
 		case ItemListView:
-			m.unfilteredItemList = make([]database.GetItemsWithReadStatusRow, len(m.itemList))
-			copy(m.unfilteredItemList, m.itemList)
+			m.unfilteredItemList = m.itemList
+			return m.loadRestOfItems()
 		}
 		return m, nil
 
@@ -1518,8 +2435,8 @@ func (m Model) handleFeedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.unfilteredFeedList = make([]FeedListItem, len(m.feedList))
 			copy(m.unfilteredFeedList, m.feedList)
 		case ItemListView:
-			m.unfilteredItemList = make([]database.GetItemsWithReadStatusRow, len(m.itemList))
-			copy(m.unfilteredItemList, m.itemList)
+			m.unfilteredItemList = m.itemList
+			return m.loadRestOfItems()
 		}
 		return m, nil
 	}
@@ -1537,6 +2454,7 @@ func (m Model) handleItemListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.searchActive = false
 			m.searchQuery = ""
 			m.itemList = m.unfilteredItemList
+			m.searchMatches = nil
 			m.cursor = 0
 			m.savedItemCursor = 0
 			return m, nil
@@ -1546,7 +2464,7 @@ func (m Model) handleItemListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if m.searchType != GlobalSearch {
 				m.searchType = GlobalSearch
 				// Trigger search with current query
-				return m, performSearch(m.feedManager, m.state, m.selectedFeed, m.searchType, m.searchQuery)
+				return m, performSearch(m.feedManager, m.state, m.selectedFeed, m.searchType, m.searchQuery, m.unfilteredItemList)
 			}
 			return m, nil
 
@@ -1555,7 +2473,7 @@ func (m Model) handleItemListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if m.searchType != TitleSearch {
 				m.searchType = TitleSearch
 				// Trigger search with current query
-				return m, performSearch(m.feedManager, m.state, m.selectedFeed, m.searchType, m.searchQuery)
+				return m, performSearch(m.feedManager, m.state, m.selectedFeed, m.searchType, m.searchQuery, m.unfilteredItemList)
 			}
 			return m, nil
 
@@ -1565,6 +2483,7 @@ func (m Model) handleItemListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.searchMode = false
 				m.searchActive = false
 				m.itemList = m.unfilteredItemList
+				m.searchMatches = nil
 			} else {
 				m.searchMode = false
 				m.searchActive = true // Mark that list is filtered by search
@@ -1581,10 +2500,11 @@ func (m Model) handleItemListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				// If query is now empty, restore unfiltered list
 				if m.searchQuery == "" {
 					m.itemList = m.unfilteredItemList
+					m.searchMatches = nil
 					return m, nil
 				}
 				// Trigger search with updated query
-				return m, performSearch(m.feedManager, m.state, m.selectedFeed, m.searchType, m.searchQuery)
+				return m, performSearch(m.feedManager, m.state, m.selectedFeed, m.searchType, m.searchQuery, m.unfilteredItemList)
 			}
 			return m, nil
 
@@ -1596,12 +2516,16 @@ func (m Model) handleItemListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.cursor = 0
 				m.savedItemCursor = 0
 				// Trigger search with updated query
-				return m, performSearch(m.feedManager, m.state, m.selectedFeed, m.searchType, m.searchQuery)
+				return m, performSearch(m.feedManager, m.state, m.selectedFeed, m.searchType, m.searchQuery, m.unfilteredItemList)
 			}
 			return m, nil
 		}
 	}
 
+	// Clear a read-later status on the next key press
+	m.statusMessage = ""
+	m.statusMessageType = ""
+
 	switch msg.String() {
 	case "?":
 		m.previousState = m.state
@@ -1615,10 +2539,12 @@ func (m Model) handleItemListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.searchQuery = ""
 		m.state = FeedListView
 		m.cursor = m.savedFeedCursor
-		return m, loadFeedList(m.feedManager)
+
+		return m, tea.Batch(loadFeedList(m.feedManager), m.flushSkippedItems())
 
 	case "j", "down":
 		if len(m.itemList) > 0 && m.cursor < len(m.itemList)-1 {
+			m.markSkipped(m.itemList[m.cursor])
 			m.cursor++
 			m.savedItemCursor = m.cursor
 			m.itemTitleScrollOffset = 0 // Reset horizontal scroll when moving to a new item
@@ -1637,7 +2563,11 @@ func (m Model) handleItemListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if pageSize < 1 {
 				pageSize = 5
 			}
-			m.cursor = min(m.cursor+pageSize, len(m.itemList)-1)
+			newCursor := min(m.cursor+pageSize, len(m.itemList)-1)
+			for i := m.cursor; i < newCursor; i++ {
+				m.markSkipped(m.itemList[i])
+			}
+			m.cursor = newCursor
 			m.savedItemCursor = m.cursor
 			m.itemTitleScrollOffset = 0 // Reset horizontal scroll when moving to a new item
 		}
@@ -1702,23 +2632,47 @@ func (m Model) handleItemListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.itemTitleScrollOffset = maxScroll
 		}
 
-	case "enter":
+	case " ":
+		// Expand or collapse the story cluster under the cursor
 		if len(m.itemList) > 0 && m.cursor < len(m.itemList) {
-			m.currentItem = m.itemList[m.cursor]
-			content := m.currentItem.Content
-			if content == "" {
-				content = m.currentItem.Description
-			}
-			m.links = m.feedManager.ExtractLinks(content)
-			m.state = ArticleView
+			item := m.itemList[m.cursor]
 
-			if !m.currentItem.Read {
-				return m, markItemRead(m.feedManager, m.currentItem.ID)
+			if _, ok := m.itemClusters[item.ID]; ok {
+				m.expandedClusters[item.ID] = !m.expandedClusters[item.ID]
+				m.buildItemDisplayList(m.clusterSourceItems)
+				return m, nil
+			} else if m.clusterMembers[item.ID] {
+				// Find the cluster lead and collapse it
+				for i := m.cursor - 1; i >= 0; i-- {
+					if _, ok := m.itemClusters[m.itemList[i].ID]; ok {
+						m.expandedClusters[m.itemList[i].ID] = false
+						m.buildItemDisplayList(m.clusterSourceItems)
+						m.cursor = i
+						m.savedItemCursor = m.cursor
+						return m, nil
+					}
+				}
+			}
+		}
+
+	case "enter":
+		if len(m.itemList) > 0 && m.cursor < len(m.itemList) {
+			m.currentItem = m.itemList[m.cursor]
+			m.links = m.feedManager.ExtractLinks(itemContent(m.currentItem))
+			m.state = ArticleView
+			delete(m.skippedItems, m.currentItem.ID)
+
+			if !m.currentItem.Read {
+				return m, tea.Batch(
+					markItemRead(m.feedManager, m.currentItem.ID),
+					recordItemEvent(m.feedManager, m.currentItem.ID, m.currentItem.FeedID, feeds.ItemEventOpen),
+				)
 			}
 		}
 
 	case "r":
-		if !m.refreshing {
+		// Aggregated lists have no single feed to refresh
+		if !m.refreshing && !isVirtualFeed(m.selectedFeed) {
 			m.refreshing = true
 			m.refreshStatus = "Refreshing feed..."
 			return m, tea.Batch(
@@ -1728,9 +2682,23 @@ func (m Model) handleItemListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 	case "A":
+		// Mark all items in an aggregated list as read
+		if isVirtualFeed(m.selectedFeed) {
+			itemIDs := make([]int64, 0, len(m.itemList))
+			for _, item := range m.itemList {
+				if !item.Read {
+					itemIDs = append(itemIDs, item.ID)
+				}
+			}
+			return m, m.markItemListRead(itemIDs)
+		}
 		// Mark all items in the current feed as read
 		return m, markAllItemsReadInFeed(m.feedManager, m.selectedFeed)
 
+	case "tab":
+		// Jump to the next unread item, across feeds like newsboat's next-unread
+		return m.nextUnreadItem()
+
 	case "N":
 		// Toggle read status of current item
 		if len(m.itemList) > 0 && m.cursor < len(m.itemList) {
@@ -1738,12 +2706,78 @@ func (m Model) handleItemListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, toggleItemReadStatus(m.feedManager, item.ID, item.Read)
 		}
 
+	case "m":
+		// Mark the current item read and move to the next one
+		if len(m.itemList) > 0 && m.cursor < len(m.itemList) {
+			index := m.cursor
+			item := m.itemList[index]
+			m.markSkipped(item)
+			if m.cursor < len(m.itemList)-1 {
+				m.cursor++
+				m.savedItemCursor = m.cursor
+				m.itemTitleScrollOffset = 0
+			}
+			if !item.Read {
+				// Show it read right away, the list is reloaded once it is saved
+				m.itemList[index].Read = true
+				return m, toggleItemReadStatus(m.feedManager, item.ID, false)
+			}
+		}
+
+	case "M":
+		// Mark every item above the cursor read
+		itemIDs := make([]int64, 0, m.cursor)
+		for _, item := range m.itemList[:m.cursor] {
+			if !item.Read {
+				itemIDs = append(itemIDs, item.ID)
+			}
+		}
+		if len(itemIDs) > 0 {
+			return m, markItemsRead(m.feedManager, m.selectedFeed, itemIDs)
+		}
+
+	case "s":
+		// Star or unstar the current item
+		if len(m.itemList) > 0 && m.cursor < len(m.itemList) {
+			item := m.itemList[m.cursor]
+			return m, toggleItemStarred(m.feedManager, item.ID, item.Starred)
+		}
+
+	case "y":
+		// Copy the link of the current item
+		if len(m.itemList) > 0 && m.cursor < len(m.itemList) && m.itemList[m.cursor].Link != "" {
+			return m, copyText("link", m.itemList[m.cursor].Link)
+		}
+
+	case "b":
+		// Send the current item to the read-later service
+		if len(m.itemList) > 0 && m.cursor < len(m.itemList) {
+			status, err := m.queueReadLater(m.itemList[m.cursor])
+			if err != nil {
+				m.statusMessage = "Read later failed: " + err.Error()
+				m.statusMessageType = "error"
+			} else {
+				m.statusMessage = status
+				m.statusMessageType = "info"
+			}
+		}
+
 	case "o":
 		// Open the current item's link in the browser
 		if len(m.itemList) > 0 && m.cursor < len(m.itemList) {
 			item := m.itemList[m.cursor]
 			if item.Link != "" {
-				return m, openLink(item.Link)
+				delete(m.skippedItems, item.ID)
+				cmds := []tea.Cmd{
+					openLink(item.Link),
+					recordItemEvent(m.feedManager, item.ID, item.FeedID, feeds.ItemEventOpen),
+				}
+				if m.config.MarkReadOnOpen && !item.Read {
+					// Show it read right away, the list is reloaded once it is saved
+					m.itemList[m.cursor].Read = true
+					cmds = append(cmds, toggleItemReadStatus(m.feedManager, item.ID, false))
+				}
+				return m, tea.Batch(cmds...)
 			}
 		}
 
@@ -1761,25 +2795,31 @@ func (m Model) handleItemListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.savedTasksCursor = 0
 		return m, loadTaskList(m.taskManager)
 
+	case ":":
+		// Open the command prompt, e.g. to filter the item list
+		m.commandMode = true
+		m.commandInput = ""
+		return m, nil
+
 	case "/":
 		// Enter global search mode for items
 		m.searchMode = true
 		m.searchType = GlobalSearch
 		m.searchQuery = ""
-		// Save current item list to restore on cancel
-		m.unfilteredItemList = make([]database.GetItemsWithReadStatusRow, len(m.itemList))
-		copy(m.unfilteredItemList, m.itemList)
-		return m, nil
+		// Save current item list to restore on cancel, an aggregated list
+		// loads the rest of its items to search them too
+		m.unfilteredItemList = m.itemList
+		return m.loadRestOfItems()
 
 	case "ctrl+f":
 		// Enter title search mode for items
 		m.searchMode = true
 		m.searchType = TitleSearch
 		m.searchQuery = ""
-		// Save current item list to restore on cancel
-		m.unfilteredItemList = make([]database.GetItemsWithReadStatusRow, len(m.itemList))
-		copy(m.unfilteredItemList, m.itemList)
-		return m, nil
+		// Save current item list to restore on cancel, an aggregated list
+		// loads the rest of its items to search them too
+		m.unfilteredItemList = m.itemList
+		return m.loadRestOfItems()
 	}
 
 	return m, nil
@@ -1793,24 +2833,33 @@ func (m Model) handleArticleKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "q", "esc", "ctrl+c":
+		// Esc clears the search in the article before leaving it
+		if msg.String() == "esc" && m.articleSearch.query != "" {
+			m.articleSearch = articleSearch{}
+			return m, nil
+		}
 		m.state = ItemListView
 		m.cursor = m.savedItemCursor
 		m.showRawHTML = false   // Reset raw HTML view when exiting
 		m.articleViewScroll = 0 // Reset scroll position when exiting
-		return m, loadItemList(m.feedManager, m.selectedFeed)
+		m.articleSearch = articleSearch{}
+		m.fullTextStatus = ""
+		return m, loadItemList(m.feedManager, m.selectedFeed, m.config, len(m.loadedItems))
 
-	case "j", "down":
-		// Calculate max scroll based on content
-		allLines := m.getArticleContentLines()
-		availableHeight := m.height - 3
-		if availableHeight < 1 {
-			availableHeight = 1
-		}
-		maxScroll := len(allLines) - availableHeight
-		if maxScroll < 0 {
-			maxScroll = 0
+	case "/":
+		// Search the text of the article
+		m.articleSearching = true
+		m.articleSearchInput = ""
+		return m, nil
+
+	case "p":
+		// Go back to the previous match of the search
+		if m.articleSearch.query != "" {
+			return m.nextArticleHit(-1), nil
 		}
-		if m.articleViewScroll < maxScroll {
+
+	case "j", "down":
+		if m.articleViewScroll < m.articleMaxScroll() {
 			m.articleViewScroll++
 		}
 
@@ -1819,17 +2868,14 @@ func (m Model) handleArticleKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.articleViewScroll--
 		}
 
+	case "g":
+		m.articleViewScroll = 0
+
+	case "G":
+		m.articleViewScroll = m.articleMaxScroll()
+
 	case "ctrl+d":
-		// Calculate max scroll based on content
-		allLines := m.getArticleContentLines()
-		availableHeight := m.height - 3
-		if availableHeight < 1 {
-			availableHeight = 1
-		}
-		maxScroll := len(allLines) - availableHeight
-		if maxScroll < 0 {
-			maxScroll = 0
-		}
+		maxScroll := m.articleMaxScroll()
 		pageSize := m.height / 2
 		if pageSize < 1 {
 			pageSize = 5
@@ -1854,13 +2900,80 @@ func (m Model) handleArticleKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.showRawHTML = !m.showRawHTML
 		return m, nil
 
+	case "R":
+		// Read the original HTML in $PAGER
+		return m, viewRawHTML(m.currentItem)
+
+	case "O":
+		// Open the original HTML in the browser
+		return m, openRawHTML(m.currentItem)
+
 	case "o":
 		// Open the current item's link in the browser
 		if m.currentItem.Link != "" {
 			return m, openLink(m.currentItem.Link)
 		}
 
+	case "s":
+		// Star or unstar the article
+		starred := m.currentItem.Starred
+		m.currentItem.Starred = !starred
+		for i := range m.itemList {
+			if m.itemList[i].ID == m.currentItem.ID {
+				m.itemList[i].Starred = !starred
+			}
+		}
+		return m, toggleItemStarred(m.feedManager, m.currentItem.ID, starred)
+
+	case "S":
+		// Pick a share command for the article
+		return m.openShareMenu(), nil
+
+	case "e":
+		// Save the article to a file, the next key picks the format
+		m.exportingArticle = true
+		m.fullTextStatus = "Export as m: markdown | h: html | p: pdf (other keys cancel)"
+		return m, nil
+
+	case "|":
+		// Pipe the article to the configured command
+		return m, pipeArticle(m.feedManager, m.currentItem, m.config.PipeCommand, m.config.PipeFormat)
+
+	case "y":
+		// Copy the link of the article
+		if m.currentItem.Link != "" {
+			return m, copyText("link", m.currentItem.Link)
+		}
+
+	case "Y":
+		// Copy the text of the article as it is shown
+		if m.articleRendered() {
+			return m, copyText("article", articleText(m.article.lines))
+		}
+
+	case "b":
+		// Send the article to the read-later service
+		status, err := m.queueReadLater(m.currentItem)
+		if err != nil {
+			m.fullTextStatus = "Read later failed: " + err.Error()
+		} else {
+			m.fullTextStatus = status
+		}
+		return m, nil
+
+	case "f":
+		// Download the linked article for feeds that only publish summaries
+		if m.currentItem.Link != "" {
+			m.fullTextStatus = "Fetching full article..."
+			return m, fetchFullContent(m.feedManager, m.currentItem.ID)
+		}
+
 	case "n":
+		// Go on to the next match while searching the article
+		if m.articleSearch.query != "" {
+			return m.nextArticleHit(1), nil
+		}
+
 		// Advance to the next article
 		if len(m.itemList) > 0 {
 			nextCursor := (m.savedItemCursor + 1) % len(m.itemList)
@@ -1868,20 +2981,24 @@ func (m Model) handleArticleKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.savedItemCursor = nextCursor
 				m.cursor = nextCursor
 				m.currentItem = m.itemList[nextCursor]
-				content := m.currentItem.Content
-				if content == "" {
-					content = m.currentItem.Description
-				}
-				m.links = m.feedManager.ExtractLinks(content)
+				m.links = m.feedManager.ExtractLinks(itemContent(m.currentItem))
 				m.showRawHTML = false   // Reset raw HTML view when navigating
 				m.articleViewScroll = 0 // Reset scroll position when navigating
+				m.fullTextStatus = ""
 
 				if !m.currentItem.Read {
-					return m, markItemRead(m.feedManager, m.currentItem.ID)
+					return m, tea.Batch(
+						markItemRead(m.feedManager, m.currentItem.ID),
+						recordItemEvent(m.feedManager, m.currentItem.ID, m.currentItem.FeedID, feeds.ItemEventOpen),
+					)
 				}
 			}
 		}
 
+	case "tab":
+		// Open the next unread article, across feeds like newsboat's next-unread
+		return m.nextUnreadItem()
+
 	case "N":
 		// Go back to the previous article
 		if len(m.itemList) > 0 {
@@ -1893,16 +3010,16 @@ func (m Model) handleArticleKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.savedItemCursor = prevCursor
 				m.cursor = prevCursor
 				m.currentItem = m.itemList[prevCursor]
-				content := m.currentItem.Content
-				if content == "" {
-					content = m.currentItem.Description
-				}
-				m.links = m.feedManager.ExtractLinks(content)
+				m.links = m.feedManager.ExtractLinks(itemContent(m.currentItem))
 				m.showRawHTML = false   // Reset raw HTML view when navigating
 				m.articleViewScroll = 0 // Reset scroll position when navigating
+				m.fullTextStatus = ""
 
 				if !m.currentItem.Read {
-					return m, markItemRead(m.feedManager, m.currentItem.ID)
+					return m, tea.Batch(
+						markItemRead(m.feedManager, m.currentItem.ID),
+						recordItemEvent(m.feedManager, m.currentItem.ID, m.currentItem.FeedID, feeds.ItemEventOpen),
+					)
 				}
 			}
 		}
@@ -1933,9 +3050,15 @@ func (m Model) handleArticleKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 func (m Model) View() string {
 	if m.err != nil {
-		return fmt.Sprintf("Error: %v\n\nPress q to quit", m.err)
+		return fmt.Sprintf("Error: %s\n\nPress q to quit", logging.Redact(m.err.Error()))
 	}
 
+	// Kitty keeps images until they are deleted, every full repaint draws
+	// the ones still shown again
+	return termimage.Clear(m.imageProtocol) + m.view()
+}
+
+func (m Model) view() string {
 	switch m.state {
 	case FeedListView:
 		return m.renderFeedList()
@@ -1957,18 +3080,30 @@ func (m Model) View() string {
 		return m.renderSettingsView()
 	case URLsView:
 		return m.renderURLsView()
+	case KeymapView:
+		return m.renderKeymapView()
+	case FetchReportView:
+		return m.renderFetchReport()
+	case TagView:
+		return m.renderTagView()
+	case ShareView:
+		return m.renderShareView()
+	case RulesView:
+		return m.renderRulesView()
+	case StatsView:
+		return m.renderStatsView()
 	}
 
 	return "Loading..."
 }
 
 func (m Model) getTitleStyle() lipgloss.Style {
-	theme := themes.GetThemeByName(m.config.ThemeName)
+	theme := themes.GetThemeByName(m.themeName())
 	return lipgloss.NewStyle().Bold(true).Background(lipgloss.Color(theme.FilterColor)).Foreground(lipgloss.Color(theme.TitleColorFg)).Width(m.width)
 }
 
 func (m Model) getSelectedStyle() lipgloss.Style {
-	theme := themes.GetThemeByName(m.config.ThemeName)
+	theme := themes.GetThemeByName(m.themeName())
 
 	switch m.config.HighlightStyle {
 	case "underline":
@@ -2009,50 +3144,179 @@ func (m Model) applyHighlight(line string, isSelected bool) string {
 }
 
 func (m Model) getHelpStyle() lipgloss.Style {
-	theme := themes.GetThemeByName(m.config.ThemeName)
+	theme := themes.GetThemeByName(m.themeName())
 	return lipgloss.NewStyle().Foreground(lipgloss.Color(theme.FilterColor))
 }
 
 func (m Model) getUnreadStyle() lipgloss.Style {
-	return lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("2"))
+	theme := themes.GetThemeByName(m.themeName())
+	return lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(theme.UnreadColor))
+}
+
+func (m Model) getErrorStyle() lipgloss.Style {
+	theme := themes.GetThemeByName(m.themeName())
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ErrorColor))
+}
+
+func (m Model) getFolderStyle() lipgloss.Style {
+	theme := themes.GetThemeByName(m.themeName())
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(theme.FolderColor))
+}
+
+// getItemStyle colors unread items and items older than the Old Item Days
+// setting, an old unread item stays bold in the old item color
+func (m Model) getItemStyle(item database.GetItemsWithReadStatusRow) (lipgloss.Style, bool) {
+	style := lipgloss.NewStyle()
+	styled := false
+	if !item.Read {
+		style = m.getUnreadStyle()
+		styled = true
+	}
+	if m.config.OldItemDays > 0 && item.Published.Valid &&
+		time.Since(item.Published.Time) > time.Duration(m.config.OldItemDays)*24*time.Hour {
+		theme := themes.GetThemeByName(m.themeName())
+		style = style.Foreground(lipgloss.Color(theme.OldItemColor))
+		styled = true
+	}
+	// Highlight rules without a color use the keyword color, unless they
+	// only add a marker
+	if highlight, ok := m.itemHighlight(item); ok && (highlight.Color != "" || highlight.Marker == "") {
+		color := highlight.Color
+		if color == "" {
+			color = themes.GetThemeByName(m.themeName()).KeywordColor
+		}
+		style = style.Foreground(lipgloss.Color(color))
+		styled = true
+	}
+	return style, styled
+}
+
+// rebuildFeedList builds the feed list from the feeds in memory, keeping the
+// cursor where it was
+func (m *Model) rebuildFeedList() {
+	// Filter feeds based on ShowReadFeeds config
+	var feedsToDisplay []database.GetFeedStatsRow
+	if m.config.ShowReadFeeds {
+		feedsToDisplay = m.allFeeds
+	} else {
+		// Filter out feeds with no unread items
+		for _, feed := range m.allFeeds {
+			if feed.UnreadItems > 0 {
+				feedsToDisplay = append(feedsToDisplay, feed)
+			}
+		}
+	}
+
+	// Sort feeds if UnreadOnTop is enabled (before building display list)
+	if m.config.UnreadOnTop {
+		sort.SliceStable(feedsToDisplay, func(i, j int) bool {
+			// Feeds with unread items come first
+			iHasUnread := feedsToDisplay[i].UnreadItems > 0
+			jHasUnread := feedsToDisplay[j].UnreadItems > 0
+			if iHasUnread != jHasUnread {
+				return iHasUnread
+			}
+			// Within each group, maintain original order (stable sort)
+			return false
+		})
+	}
+
+	// Build display list with folders
+	m.buildFeedDisplayList(feedsToDisplay)
+
+	if m.state == FeedListView {
+		// Preserve cursor position when refreshing feed list
+		m.cursor = m.savedFeedCursor
+		if m.cursor >= len(m.feedList) {
+			m.cursor = max(0, len(m.feedList)-1)
+		}
+		m.savedFeedCursor = m.cursor
+	}
 }
 
 // buildFeedDisplayList creates a flat list of folders and feeds for display
 func (m *Model) buildFeedDisplayList(feeds []database.GetFeedStatsRow) {
-	ctx := context.Background()
-
 	// Group feeds by folders
 	feedsByFolder := make(map[string][]database.GetFeedStatsRow)
 	feedsWithoutFolders := []database.GetFeedStatsRow{}
-
+	var feedsInFolders []database.GetFeedStatsRow
 	for _, feed := range feeds {
-		// Get folders for this feed
-		folders, err := m.queries.GetFeedFolders(ctx, feed.ID)
-		if err != nil || len(folders) == 0 {
+		folders := m.feedFolders[feed.ID]
+		if m.feedFilter != nil && !m.feedFilter.Match(m.feedRecord(feed)) {
+			continue
+		}
+		if m.selectedTag != "" && !m.hasTag(feed.ID, m.selectedTag) {
+			continue
+		}
+		if len(folders) == 0 {
 			// Feed has no folders
 			feedsWithoutFolders = append(feedsWithoutFolders, feed)
 		} else {
 			// Add feed to each of its folders
+			feedsInFolders = append(feedsInFolders, feed)
 			for _, folder := range folders {
 				feedsByFolder[folder] = append(feedsByFolder[folder], feed)
 			}
 		}
 	}
 
-	// Calculate folder stats
+	// Calculate folder stats, folders count the feeds of their sub-folders
+	// too and a feed in several of them once
 	m.folderStats = make(map[string]struct{ UnreadItems, TotalItems int64 })
-	for folderName, folderFeeds := range feedsByFolder {
-		var unread, total int64
-		for _, feed := range folderFeeds {
-			unread += feed.UnreadItems
-			total += feed.TotalItems
+	for _, feed := range feedsInFolders {
+		counted := make(map[string]bool)
+		for _, folder := range m.feedFolders[feed.ID] {
+			for path := folder; path != "" && !counted[path]; path = folderParent(path) {
+				counted[path] = true
+				stats := m.folderStats[path]
+				stats.UnreadItems += feed.UnreadItems
+				stats.TotalItems += feed.TotalItems
+				m.folderStats[path] = stats
+			}
 		}
-		m.folderStats[folderName] = struct{ UnreadItems, TotalItems int64 }{unread, total}
 	}
 
 	// Build display list
 	m.feedList = []FeedListItem{}
 
+	// Items of all feeds are shown as a feed at the very top
+	var allUnread, allTotal int64
+	for _, feed := range m.allFeeds {
+		allUnread += feed.UnreadItems
+		allTotal += feed.TotalItems
+	}
+	if allTotal > 0 {
+		m.feedList = append(m.feedList, FeedListItem{
+			IsFolder: false,
+			Feed: &database.GetFeedStatsRow{
+				ID:          AllItemsFeedID,
+				Title:       "≡ All Items",
+				TotalItems:  allTotal,
+				UnreadItems: allUnread,
+			},
+			UnreadItems: allUnread,
+			TotalItems:  allTotal,
+		})
+	}
+
+	// Starred items are shown below
+	if m.starredStats.TotalItems > 0 {
+		m.feedList = append(m.feedList, FeedListItem{
+			IsFolder: false,
+			Feed: &database.GetFeedStatsRow{
+				ID:          StarredFeedID,
+				Title:       "★ Starred",
+				TotalItems:  m.starredStats.TotalItems,
+				UnreadItems: m.starredStats.UnreadItems,
+			},
+			UnreadItems: m.starredStats.UnreadItems,
+			TotalItems:  m.starredStats.TotalItems,
+		})
+	}
+
+	// Query feeds from the URLs file follow
+	m.feedList = append(m.feedList, m.queryFeedListItems()...)
+
 	// If UnreadOnTop is enabled, show unread feeds without folders first
 	if m.config.UnreadOnTop {
 		// Add unread feeds without folders first
@@ -2069,76 +3333,64 @@ func (m *Model) buildFeedDisplayList(feeds []database.GetFeedStatsRow) {
 		}
 	}
 
-	// Get sorted folder names
+	// Add folders (always visible), with their sub-folders and then their
+	// feeds when they are expanded
 	folderNames := make([]string, 0, len(feedsByFolder))
 	for name := range feedsByFolder {
 		folderNames = append(folderNames, name)
 	}
-	sort.Strings(folderNames)
-
-	// Add folders (always visible)
-	for _, folderName := range folderNames {
+	children := folderTree(folderNames)
+	var addFolder func(folderName string)
+	addFolder = func(folderName string) {
 		stats := m.folderStats[folderName]
-		item := FeedListItem{
+		depth := folderDepth(folderName)
+		m.feedList = append(m.feedList, FeedListItem{
 			IsFolder:    true,
 			FolderName:  folderName,
 			UnreadItems: stats.UnreadItems,
 			TotalItems:  stats.TotalItems,
 			IsExpanded:  m.expandedFolders[folderName],
+			Depth:       depth,
+		})
+		if !m.expandedFolders[folderName] {
+			return
 		}
-		m.feedList = append(m.feedList, item)
 
-		// If folder is expanded, add its feeds
-		if m.expandedFolders[folderName] {
-			folderFeeds := feedsByFolder[folderName]
+		for _, child := range children[folderName] {
+			addFolder(child)
+		}
 
-			// Sort feeds in folder by unread status if UnreadOnTop is enabled
-			if m.config.UnreadOnTop {
-				// Separate unread and read feeds
-				var unreadFeeds, readFeeds []database.GetFeedStatsRow
-				for _, feed := range folderFeeds {
-					if feed.UnreadItems > 0 {
-						unreadFeeds = append(unreadFeeds, feed)
-					} else {
-						readFeeds = append(readFeeds, feed)
-					}
-				}
-				// Add unread feeds first, then read feeds
-				for _, feed := range unreadFeeds {
-					feedCopy := feed
-					m.feedList = append(m.feedList, FeedListItem{
-						IsFolder:      false,
-						Feed:          &feedCopy,
-						UnreadItems:   feed.UnreadItems,
-						TotalItems:    feed.TotalItems,
-						IsUnderFolder: true,
-					})
-				}
-				for _, feed := range readFeeds {
-					feedCopy := feed
-					m.feedList = append(m.feedList, FeedListItem{
-						IsFolder:      false,
-						Feed:          &feedCopy,
-						UnreadItems:   feed.UnreadItems,
-						TotalItems:    feed.TotalItems,
-						IsUnderFolder: true,
-					})
+		folderFeeds := feedsByFolder[folderName]
+		// Sort feeds in folder by unread status if UnreadOnTop is enabled
+		if m.config.UnreadOnTop {
+			sorted := make([]database.GetFeedStatsRow, 0, len(folderFeeds))
+			for _, feed := range folderFeeds {
+				if feed.UnreadItems > 0 {
+					sorted = append(sorted, feed)
 				}
-			} else {
-				// No sorting, add feeds in original order
-				for _, feed := range folderFeeds {
-					feedCopy := feed
-					m.feedList = append(m.feedList, FeedListItem{
-						IsFolder:      false,
-						Feed:          &feedCopy,
-						UnreadItems:   feed.UnreadItems,
-						TotalItems:    feed.TotalItems,
-						IsUnderFolder: true,
-					})
+			}
+			for _, feed := range folderFeeds {
+				if feed.UnreadItems == 0 {
+					sorted = append(sorted, feed)
 				}
 			}
+			folderFeeds = sorted
+		}
+		for _, feed := range folderFeeds {
+			feedCopy := feed
+			m.feedList = append(m.feedList, FeedListItem{
+				IsFolder:      false,
+				Feed:          &feedCopy,
+				UnreadItems:   feed.UnreadItems,
+				TotalItems:    feed.TotalItems,
+				IsUnderFolder: true,
+				Depth:         depth + 1,
+			})
 		}
 	}
+	for _, folderName := range children[""] {
+		addFolder(folderName)
+	}
 
 	// Add feeds without folders (or read feeds if UnreadOnTop is enabled)
 	if m.config.UnreadOnTop {
@@ -2168,9 +3420,90 @@ func (m *Model) buildFeedDisplayList(feeds []database.GetFeedStatsRow) {
 	}
 }
 
+// flushSkippedItems records the items that were passed over without being
+// opened when leaving an item list, and marks them read with Mark Skipped Read
+func (m *Model) flushSkippedItems() tea.Cmd {
+	skipped := m.skippedItems
+	m.skippedItems = make(map[int64]int64)
+	cmds := []tea.Cmd{recordSkippedItems(m.feedManager, skipped)}
+	if m.config.MarkSkippedRead && !m.readOnly && len(skipped) > 0 {
+		// Items marked read since, like with m, aren't part of the undo
+		itemIDs := make([]int64, 0, len(skipped))
+		for _, item := range m.itemList {
+			if _, ok := skipped[item.ID]; ok && !item.Read {
+				itemIDs = append(itemIDs, item.ID)
+			}
+		}
+		cmds = append(cmds, markItemsRead(m.feedManager, m.selectedFeed, itemIDs))
+	}
+	return tea.Batch(cmds...)
+}
+
+// markSkipped remembers an unread item that the cursor moved past without opening it
+func (m *Model) markSkipped(item database.GetItemsWithReadStatusRow) {
+	if !item.Read {
+		m.skippedItems[item.ID] = item.FeedID
+	}
+}
+
+// buildItemDisplayList groups items that cover the same story in different
+// feeds and creates the flat item list for display. Collapsed clusters only
+// show their first item. Only lists combining feeds, like All Items, are
+// clustered, the items of a single feed never are.
+func (m *Model) buildItemDisplayList(items []database.GetItemsWithReadStatusRow) {
+	m.clusterSourceItems = items
+	m.itemClusters = make(map[int64][]database.GetItemsWithReadStatusRow)
+	m.clusterMembers = make(map[int64]bool)
+
+	if !m.config.ClusterStories || !isVirtualFeed(m.selectedFeed) {
+		m.itemList = items
+		return
+	}
+
+	entries := make([]feeds.ClusterEntry, len(items))
+	for i, item := range items {
+		entries[i] = feeds.ClusterEntry{
+			ID:     item.ID,
+			FeedID: item.FeedID,
+			Title:  item.Title,
+		}
+		if item.Published.Valid {
+			entries[i].Published = item.Published.Time
+		}
+	}
+
+	m.itemList = make([]database.GetItemsWithReadStatusRow, 0, len(items))
+	for _, cluster := range feeds.ClusterItems(entries, feeds.DefaultClusterWindow, feeds.DefaultClusterThreshold) {
+		lead := items[cluster[0]]
+		m.itemList = append(m.itemList, lead)
+		if len(cluster) == 1 {
+			continue
+		}
+
+		for _, idx := range cluster[1:] {
+			m.itemClusters[lead.ID] = append(m.itemClusters[lead.ID], items[idx])
+		}
+
+		// If cluster is expanded, add the other items of the story
+		if m.expandedClusters[lead.ID] {
+			for _, member := range m.itemClusters[lead.ID] {
+				m.itemList = append(m.itemList, member)
+				m.clusterMembers[member.ID] = true
+			}
+		}
+	}
+}
+
 func (m Model) renderFeedList() string {
 	var b strings.Builder
 	b.WriteString(m.getTitleStyle().Render("🐐 NewsGoat " + version.GetVersion() + " - RSS Reader"))
+	b.WriteString(m.filterTitle())
+	if m.readOnly {
+		b.WriteString(" - " + m.getErrorStyle().Render("read-only"))
+	}
+	if m.daemonPID != 0 {
+		b.WriteString(" - " + m.getHelpStyle().Render("daemon"))
+	}
 
 	if m.refreshing {
 		b.WriteString(" - ")
@@ -2260,10 +3593,10 @@ func (m Model) renderFeedList() string {
 		// Show status message line or search line
 		b.WriteString("\n")
 		if m.statusMessage != "" {
-			theme := themes.GetThemeByName(m.config.ThemeName)
+			theme := themes.GetThemeByName(m.themeName())
 			var messageStyle lipgloss.Style
 			if m.statusMessageType == "error" {
-				messageStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9")) // Red
+				messageStyle = m.getErrorStyle()
 			} else {
 				messageStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.SelectedItemColor))
 			}
@@ -2280,130 +3613,28 @@ func (m Model) renderFeedList() string {
 		return b.String()
 	}
 
-	// Calculate viewport for scrolling
-	// Reserve space for:
-	// - Title line (1)
-	// - Empty line after header (1)
-	// - Status bar at bottom (1)
-	// - Scroll indicator line (1)
-	// - Search prompt line (1) - always allocated
-	// Total: 5 lines
-	availableHeight := m.height - 5
-	if availableHeight < 3 {
-		availableHeight = 3 // Minimum usable height
-	}
-
-	// Calculate start and end indices for viewport
-	start := 0
-	end := len(m.feedList)
-
-	if len(m.feedList) > availableHeight {
-		// Center the cursor in the viewport when possible
-		halfHeight := availableHeight / 2
-		start = max(0, m.cursor-halfHeight)
-		end = min(len(m.feedList), start+availableHeight)
-
-		// Adjust start if we're near the end
-		if end-start < availableHeight {
-			start = max(0, end-availableHeight)
-		}
-	}
+	rows, cols := m.feedColumnLayout()
 
-	// Render visible items (folders and feeds)
+	var start, end int
 	feedLines := 0
-	for i := start; i < end; i++ {
-		item := m.feedList[i]
-		var line string
-
-		if item.IsFolder {
-			// Render folder
-			// Use different icon for open/closed folders
-			var folderIcon string
-			if item.IsExpanded {
-				folderIcon = "📂" // Open folder
-			} else {
-				folderIcon = "📁" // Closed folder
-			}
-			countStr := fmt.Sprintf("(%d/%d)", item.UnreadItems, item.TotalItems)
-			paddedCount := fmt.Sprintf("%9s", countStr)
-			// Add 2 spaces after emoji to align with feed items (which have statusEmoji + 2-char spinner)
-			line = folderIcon + "  " + paddedCount + " " + item.FolderName
-
-			// Apply highlighting
-			if i == m.cursor {
-				line = m.applyHighlight(line, true)
-			} else {
-				if item.UnreadItems > 0 {
-					line = m.getUnreadStyle().Render(line)
-				}
-				line = m.applyHighlight(line, false)
-			}
-		} else {
-			// Render feed
-			feed := *item.Feed
-
-			// Status emoji: error emoji if error (but not when refreshing), unread if has unread items, nothing if all read
-			var statusEmoji string
-			// Don't show error emoji when actively refreshing - let the spinner show instead
-			if feed.LastError.Valid && feed.LastError.String != "" && !m.refreshingFeeds[feed.ID] {
-				// Try to determine error type from error message
-				errorMsg := feed.LastError.String
-				if strings.Contains(errorMsg, "404") {
-					statusEmoji = "🔍" // Not found
-				} else if strings.Contains(errorMsg, "403") {
-					statusEmoji = "🚫" // Forbidden
-				} else if strings.Contains(errorMsg, "429") {
-					statusEmoji = "⏱️" // Too many requests
-				} else if strings.Contains(errorMsg, "500") || strings.Contains(errorMsg, "502") || strings.Contains(errorMsg, "503") {
-					statusEmoji = "⚠️" // Server error
-				} else if strings.Contains(errorMsg, "timeout") || strings.Contains(errorMsg, "context deadline exceeded") {
-					statusEmoji = "⌛" // Timeout
-				} else {
-					statusEmoji = "❌" // Generic error
-				}
-			}
-
-			// Spinner - 2 character space reserved for spinner when refreshing
-			var spinner string
-			if m.refreshingFeeds[feed.ID] {
-				spinnerFrames := themes.GetSpinnerFrames(m.config.SpinnerType)
-				spinner = spinnerFrames[m.spinnerFrame%len(spinnerFrames)] + " "
-			} else {
-				spinner = "  " // Two spaces when not spinning
-			}
-
-			// Count string right-justified to 9 characters
-			countStr := fmt.Sprintf("(%d/%d)", feed.UnreadItems, feed.TotalItems)
-			paddedCount := fmt.Sprintf("%9s", countStr)
-
-			// Get display title - override for GitHub and GitLab feeds
-			displayTitle := getDisplayTitle(feed)
-
-			// Add vertical bar prefix if this feed is under a folder
-			var prefix string
-			if item.IsUnderFolder {
-				prefix = "│ "
-			} else {
-				prefix = ""
-			}
-
-			// Construct the line: prefix + status emoji (if error) + spinner (2 chars) + count (9 chars) + space + feed title
-			line = prefix + statusEmoji + spinner + paddedCount + " " + displayTitle
-
-			// Apply highlighting
-			if i == m.cursor {
-				line = m.applyHighlight(line, true)
-			} else {
-				if feed.UnreadItems > 0 {
-					line = m.getUnreadStyle().Render(line)
-				}
-				line = m.applyHighlight(line, false)
-			}
+	if cols > 1 {
+		var lines []string
+		lines, start, end = m.renderFeedColumns(rows, cols)
+		for _, line := range lines {
+			b.WriteString(line)
+			b.WriteString("\n")
+			feedLines++
 		}
+	} else {
+		// Calculate start and end indices for viewport
+		start, end = listWindow(m.cursor, len(m.feedList), rows)
 
-		b.WriteString(line)
-		b.WriteString("\n")
-		feedLines++
+		// Render visible items (folders and feeds)
+		for i := start; i < end; i++ {
+			b.WriteString(m.renderFeedLine(i))
+			b.WriteString("\n")
+			feedLines++
+		}
 	}
 
 	// Calculate padding to push status bar to bottom
@@ -2418,7 +3649,7 @@ func (m Model) renderFeedList() string {
 
 	// Show scroll indicator if there are more feeds
 	var scrollInfo string
-	if len(m.feedList) > availableHeight {
+	if start > 0 || end < len(m.feedList) {
 		scrollInfo = fmt.Sprintf("(%d-%d of %d)  ", start+1, end, len(m.feedList))
 		b.WriteString(m.getHelpStyle().Render(scrollInfo))
 	}
@@ -2447,10 +3678,10 @@ func (m Model) renderFeedList() string {
 	// Show status message line above search line if present
 	b.WriteString("\n")
 	if m.statusMessage != "" {
-		theme := themes.GetThemeByName(m.config.ThemeName)
+		theme := themes.GetThemeByName(m.themeName())
 		var messageStyle lipgloss.Style
 		if m.statusMessageType == "error" {
-			messageStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9")) // Red
+			messageStyle = m.getErrorStyle()
 		} else {
 			messageStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.SelectedItemColor))
 		}
@@ -2459,6 +3690,10 @@ func (m Model) renderFeedList() string {
 		// Show URL input modal
 		urlPrompt := "Add URL [folders]: " + m.urlInput
 		b.WriteString(m.getHelpStyle().Render(urlPrompt))
+	} else if m.commandMode {
+		b.WriteString(m.getHelpStyle().Render(":" + m.commandInput))
+	} else if m.editingFolders {
+		b.WriteString(m.getHelpStyle().Render(m.folderPrompt()))
 	} else if m.searchMode {
 		var searchPrompt string
 		if m.searchType == GlobalSearch {
@@ -2472,9 +3707,263 @@ func (m Model) renderFeedList() string {
 	return b.String()
 }
 
+// feedListHeight is the number of feed list lines that fit between the
+// title and the status bar
+func (m Model) feedListHeight() int {
+	// Reserve space for:
+	// - Title line (1)
+	// - Empty line after header (1)
+	// - Status bar at bottom (1)
+	// - Scroll indicator line (1)
+	// - Search prompt line (1) - always allocated
+	// Total: 5 lines
+	availableHeight := m.height - 5
+	if availableHeight < 3 {
+		availableHeight = 3 // Minimum usable height
+	}
+	return availableHeight
+}
+
+// itemListRows is the number of items that fit between the title and the
+// status bar, global search results take two lines each
+func (m Model) itemListRows() int {
+	// Reserve space for:
+	// - Title line (1)
+	// - Empty line after header (1)
+	// - Status bar at bottom (1)
+	// - Scroll indicator line (1)
+	// - Search prompt line (1) - always allocated
+	// Total: 5 lines
+	availableHeight := m.height - 5
+	if availableHeight < 3 {
+		availableHeight = 3 // Minimum usable height
+	}
+
+	// Global search results take two lines, the title and a snippet of the body
+	if m.searchMatches != nil {
+		return max(availableHeight/2, 1)
+	}
+	return availableHeight
+}
+
+// listWindow returns the range of a list of total entries shown in height
+// lines, with the cursor centered when possible
+func listWindow(cursor, total, height int) (start, end int) {
+	if total <= height {
+		return 0, total
+	}
+	start = max(0, cursor-height/2)
+	end = min(total, start+height)
+
+	// Adjust start if we're near the end
+	if end-start < height {
+		start = max(0, end-height)
+	}
+	return start, end
+}
+
+const (
+	minFeedColumnWidth = 30
+	maxFeedColumnWidth = 60
+	feedColumnGap      = 2
+	// Folder bar, status emoji, spinner, count and highlight prefix in front of the title
+	feedLineOverhead = 18
+)
+
+// feedColumnLayout returns the number of rows and columns the feed list is
+// drawn in, a single column unless the columns layout is enabled and the
+// terminal is wide enough for more
+func (m Model) feedColumnLayout() (rows, cols int) {
+	rows = m.feedListHeight()
+	if m.config.FeedListLayout != config.FeedListLayoutColumns || len(m.feedList) == 0 {
+		return rows, 1
+	}
+
+	cols = max(1, (m.width+feedColumnGap)/(m.feedColumnWidth()+feedColumnGap))
+	if cols == 1 {
+		return rows, 1
+	}
+
+	// Spread the feeds evenly over the columns like ls instead of filling the first ones
+	rows = min(rows, (len(m.feedList)+cols-1)/cols)
+	return rows, cols
+}
+
+// feedColumnWidth fits the longest title, within limits so one long title
+// doesn't take away all the columns
+func (m Model) feedColumnWidth() int {
+	width := minFeedColumnWidth
+	for _, item := range m.feedList {
+		title := folderLeaf(item.FolderName)
+		if !item.IsFolder {
+			icon, _ := m.feedIcon(*item.Feed)
+			title = icon + getDisplayTitle(*item.Feed)
+		}
+		width = max(width, feedLineOverhead+2*max(item.Depth-1, 0)+lipgloss.Width(title))
+	}
+	return min(width, maxFeedColumnWidth)
+}
+
+// renderFeedColumns fills the columns top to bottom a page at a time and
+// returns the lines along with the range of feeds shown
+func (m Model) renderFeedColumns(rows, cols int) (lines []string, start, end int) {
+	width := m.feedColumnWidth()
+	perPage := rows * cols
+	start = (m.cursor / perPage) * perPage
+	end = min(len(m.feedList), start+perPage)
+
+	lines = make([]string, rows)
+	cellStyle := lipgloss.NewStyle().MaxWidth(width)
+	for i := start; i < end; i++ {
+		row := (i - start) % rows
+		cell := cellStyle.Render(m.renderFeedLine(i))
+		if i+rows < end {
+			// Pad all but the last column so the next one lines up
+			cell += strings.Repeat(" ", max(0, width+feedColumnGap-lipgloss.Width(cell)))
+		}
+		lines[row] += cell
+	}
+	return lines, start, end
+}
+
+// renderFeedLine renders a folder or feed of the feed list
+func (m Model) renderFeedLine(i int) string {
+	item := m.feedList[i]
+	var line string
+
+	if item.IsFolder {
+		// Render folder
+		// Use different icon for open/closed folders
+		var folderIcon string
+		if item.IsExpanded {
+			folderIcon = "📂" // Open folder
+		} else {
+			folderIcon = "📁" // Closed folder
+		}
+		countStr := fmt.Sprintf("(%d/%d)", item.UnreadItems, item.TotalItems)
+		paddedCount := fmt.Sprintf("%9s", countStr)
+		// Add 2 spaces after emoji to align with feed items (which have statusEmoji + 2-char spinner)
+		line = strings.Repeat("│ ", item.Depth) + folderIcon + "  " + paddedCount + " " + folderLeaf(item.FolderName)
+
+		// Apply highlighting
+		if i == m.cursor {
+			line = m.applyHighlight(line, true)
+		} else {
+			if item.UnreadItems > 0 {
+				line = m.getFolderStyle().Bold(true).Render(line)
+			} else {
+				line = m.getFolderStyle().Render(line)
+			}
+			line = m.applyHighlight(line, false)
+		}
+	} else {
+		// Render feed
+		feed := *item.Feed
+
+		// Status emoji: error emoji if error (but not when refreshing), unread if has unread items, nothing if all read
+		var statusEmoji string
+		// Don't show error emoji when actively refreshing - let the spinner show instead
+		if feed.Paused && !m.refreshingFeeds[feed.ID] {
+			statusEmoji = "⏸️" // Paused, the last error doesn't matter until it is resumed
+		} else if m.feedIsDead(feed) && !m.refreshingFeeds[feed.ID] {
+			statusEmoji = "💀" // Failing for so long it probably moved, D looks for it
+		} else if feed.LastError.Valid && feed.LastError.String != "" && !m.refreshingFeeds[feed.ID] {
+			// Try to determine error type from error message
+			errorMsg := feed.LastError.String
+			if strings.Contains(errorMsg, "404") {
+				statusEmoji = "🔍" // Not found
+			} else if strings.Contains(errorMsg, "403") {
+				statusEmoji = "🚫" // Forbidden
+			} else if strings.Contains(errorMsg, "429") {
+				statusEmoji = "⏱️" // Too many requests
+			} else if strings.Contains(errorMsg, "500") || strings.Contains(errorMsg, "502") || strings.Contains(errorMsg, "503") {
+				statusEmoji = "⚠️" // Server error
+			} else if strings.Contains(errorMsg, "timeout") || strings.Contains(errorMsg, "context deadline exceeded") {
+				statusEmoji = "⌛" // Timeout
+			} else {
+				statusEmoji = "❌" // Generic error
+			}
+		}
+
+		// Spinner - 2 character space reserved for spinner when refreshing
+		var spinner string
+		if m.refreshingFeeds[feed.ID] {
+			spinnerFrames := themes.GetSpinnerFrames(m.config.SpinnerType)
+			spinner = spinnerFrames[m.spinnerFrame%len(spinnerFrames)] + " "
+		} else {
+			spinner = "  " // Two spaces when not spinning
+		}
+
+		// Count string right-justified to 9 characters
+		countStr := fmt.Sprintf("(%d/%d)", feed.UnreadItems, feed.TotalItems)
+		paddedCount := fmt.Sprintf("%9s", countStr)
+
+		// Get display title - override for GitHub and GitLab feeds
+		displayTitle := getDisplayTitle(feed)
+
+		// Add a vertical bar prefix for each folder this feed is under
+		prefix := strings.Repeat("│ ", item.Depth)
+
+		// Construct the line: prefix + status emoji (if error) + spinner (2 chars) + count (9 chars) + space + feed title
+		line = prefix + statusEmoji + spinner + paddedCount + " " + displayTitle
+
+		// The icon is colored on its own, the parts around it get the line's style
+		if icon, color := m.feedIcon(feed); icon != "" {
+			style := lipgloss.NewStyle()
+			if i == m.cursor {
+				style = m.getSelectedStyle()
+			} else if statusEmoji != "" {
+				style = m.getErrorStyle().Bold(feed.UnreadItems > 0)
+			} else if feed.UnreadItems > 0 {
+				style = m.getUnreadStyle()
+			}
+			head := prefix + statusEmoji + spinner + paddedCount + " "
+			if m.config.HighlightStyle == "prefix" || m.config.HighlightStyle == "prefix-underline" {
+				if i == m.cursor {
+					head = "> " + head
+				} else {
+					head = "  " + head
+				}
+			}
+			return style.Render(head) + renderFeedIcon(icon, color, style) + style.Render(displayTitle)
+		}
+
+		// Apply highlighting
+		if i == m.cursor {
+			line = m.applyHighlight(line, true)
+		} else {
+			if statusEmoji != "" {
+				line = m.getErrorStyle().Bold(feed.UnreadItems > 0).Render(line)
+			} else if feed.UnreadItems > 0 {
+				line = m.getUnreadStyle().Render(line)
+			}
+			line = m.applyHighlight(line, false)
+		}
+	}
+
+	return line
+}
+
 func (m Model) renderItemList() string {
 	var b strings.Builder
-	b.WriteString(m.getTitleStyle().Render("🐐 NewsGoat - Feed Items"))
+	switch m.selectedFeed {
+	case HotItemsFeedID:
+		b.WriteString(m.getTitleStyle().Render("🐐 NewsGoat - Hot Items"))
+	case StarredFeedID:
+		b.WriteString(m.getTitleStyle().Render("🐐 NewsGoat - Starred Items"))
+	case AllItemsFeedID:
+		b.WriteString(m.getTitleStyle().Render("🐐 NewsGoat - All Items"))
+	default:
+		if query := m.queryFeedFor(m.selectedFeed); query != nil {
+			b.WriteString(m.getTitleStyle().Render("🐐 NewsGoat - " + query.title))
+		} else {
+			b.WriteString(m.getTitleStyle().Render("🐐 NewsGoat - Feed Items"))
+		}
+	}
+	b.WriteString(m.filterTitle())
+	if m.readOnly {
+		b.WriteString(" - " + m.getErrorStyle().Render("read-only"))
+	}
 
 	if m.refreshing {
 		b.WriteString(" - ")
@@ -2512,7 +4001,9 @@ func (m Model) renderItemList() string {
 		b.WriteString(statusBar)
 		b.WriteString("\n")
 		// Add search prompt line if in search mode
-		if m.searchMode {
+		if m.commandMode {
+			b.WriteString(m.getHelpStyle().Render(":" + m.commandInput))
+		} else if m.searchMode {
 			var searchPrompt string
 			if m.searchType == GlobalSearch {
 				searchPrompt = "Global search (ctrl-f to search only titles): " + m.searchQuery
@@ -2520,52 +4011,24 @@ func (m Model) renderItemList() string {
 				searchPrompt = "Title search ('/' for global search): " + m.searchQuery
 			}
 			b.WriteString(m.getHelpStyle().Render(searchPrompt))
+		} else if m.statusMessage != "" && m.statusMessageType == "error" {
+			b.WriteString(m.getErrorStyle().Render(m.statusMessage))
 		}
 		return b.String()
 	}
 
-	// Calculate viewport for scrolling
-	// Reserve space for:
-	// - Title line (1)
-	// - Empty line after header (1)
-	// - Status bar at bottom (1)
-	// - Scroll indicator line (1)
-	// - Search prompt line (1) - always allocated
-	// Total: 5 lines
-	availableHeight := m.height - 5
-	if availableHeight < 3 {
-		availableHeight = 3 // Minimum usable height
-	}
-
 	// Calculate start and end indices for viewport
-	start := 0
-	end := len(m.itemList)
-
-	if len(m.itemList) > availableHeight {
-		// Center the cursor in the viewport when possible
-		halfHeight := availableHeight / 2
-		start = max(0, m.cursor-halfHeight)
-		end = min(len(m.itemList), start+availableHeight)
-
-		// Adjust start if we're near the end
-		if end-start < availableHeight {
-			start = max(0, end-availableHeight)
-		}
-	}
+	start, end := listWindow(m.cursor, len(m.itemList), m.itemListRows())
 
 	// Render visible items
 	itemLines := 0
+	format := parseItemFormat(m.itemListFormat())
 	for i := start; i < end; i++ {
 		item := m.itemList[i]
 
-		// Format date as MM-DD
-		datePrefix := "     " // Default fallback if no date
-		if item.Published.Valid {
-			datePrefix = item.Published.Time.Format("01-02")
-		}
-
 		// Apply horizontal scrolling to title if this is the selected item
 		title := item.Title
+		scrolledRunes := 0
 		if i == m.cursor && m.itemTitleScrollOffset > 0 {
 			// Apply scroll offset to title only
 			if m.itemTitleScrollOffset < len(title) {
@@ -2573,23 +4036,65 @@ func (m Model) renderItemList() string {
 			} else {
 				title = "" // Scrolled past the end
 			}
+			scrolledRunes = utf8.RuneCountInString(item.Title) - utf8.RuneCountInString(title)
+		}
+
+		// Show cluster size for stories covered by several feeds
+		var clusterPrefix string
+		if members, ok := m.itemClusters[item.ID]; ok {
+			if m.expandedClusters[item.ID] {
+				clusterPrefix = fmt.Sprintf("[-%d] ", len(members))
+			} else {
+				clusterPrefix = fmt.Sprintf("[+%d] ", len(members))
+			}
+		} else if m.clusterMembers[item.ID] {
+			clusterPrefix = "│ "
+		}
+
+		var starPrefix string
+		if item.Starred {
+			starPrefix = "★ "
+		}
+
+		highlight, highlighted := m.itemHighlight(item)
+		var markerPrefix string
+		if highlighted && highlight.Marker != "" {
+			markerPrefix = highlight.Marker + " "
 		}
 
-		line := datePrefix + " " + title
+		line, titleAt := m.formatItemLine(format, item, starPrefix+itemBadges(item)+clusterPrefix+markerPrefix, title)
 
 		// Apply highlighting
 		if i == m.cursor {
 			line = m.applyHighlight(line, true)
 		} else {
-			if !item.Read {
-				line = m.getUnreadStyle().Render(line)
+			if style, ok := m.getItemStyle(item); ok {
+				line = style.Render(line)
 			}
 			line = m.applyHighlight(line, false)
 		}
+		line = highlightKeywords(line, m.keywordsForFeed(item.FeedID), m.getKeywordStyle())
+
+		match, matched := m.searchMatches[item.ID]
+		if matched && titleAt >= 0 {
+			// Match offsets are in the title, move them past what comes before it
+			shift := titleAt - scrolledRunes
+			ranges := make([][2]int, 0, len(match.TitleRanges))
+			for _, r := range match.TitleRanges {
+				ranges = append(ranges, [2]int{max(r[0]+shift, titleAt), r[1] + shift})
+			}
+			line = highlightRanges(line, ranges, m.getSearchMatchStyle())
+		}
 
 		b.WriteString(line)
 		b.WriteString("\n")
 		itemLines++
+
+		if m.searchMatches != nil {
+			b.WriteString(m.renderSearchSnippet(match))
+			b.WriteString("\n")
+			itemLines++
+		}
 	}
 
 	// Calculate padding to push status bar to bottom
@@ -2603,8 +4108,11 @@ func (m Model) renderItemList() string {
 	b.WriteString(strings.Repeat("\n", padding))
 
 	// Show scroll indicator if there are more items
-	if len(m.itemList) > availableHeight {
+	if start > 0 || end < len(m.itemList) {
 		scrollInfo := fmt.Sprintf("(%d-%d of %d)", start+1, end, len(m.itemList))
+		if m.moreItems {
+			scrollInfo = fmt.Sprintf("(%d-%d of %d+)", start+1, end, len(m.itemList))
+		}
 		b.WriteString(m.getHelpStyle().Render(scrollInfo))
 		b.WriteString("  ")
 	}
@@ -2613,7 +4121,9 @@ func (m Model) renderItemList() string {
 
 	// Show search prompt line
 	b.WriteString("\n")
-	if m.searchMode {
+	if m.commandMode {
+		b.WriteString(m.getHelpStyle().Render(":" + m.commandInput))
+	} else if m.searchMode {
 		var searchPrompt string
 		if m.searchType == GlobalSearch {
 			searchPrompt = "Global search (ctrl-f to search only titles): " + m.searchQuery
@@ -2621,76 +4131,52 @@ func (m Model) renderItemList() string {
 			searchPrompt = "Title search ('/' for global search): " + m.searchQuery
 		}
 		b.WriteString(m.getHelpStyle().Render(searchPrompt))
+	} else if m.statusMessage != "" {
+		var messageStyle lipgloss.Style
+		if m.statusMessageType == "error" {
+			messageStyle = m.getErrorStyle()
+		} else {
+			messageStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(themes.GetThemeByName(m.themeName()).SelectedItemColor))
+		}
+		b.WriteString(messageStyle.Render(m.statusMessage))
 	}
 
 	return b.String()
 }
 
-func (m *Model) getArticleContentLines() []string {
-	// Build content
-	var contentBuilder strings.Builder
-
-	content := m.currentItem.Content
-	if content == "" {
-		content = m.currentItem.Description
-	}
-
-	// If showing raw HTML, apply word wrapping and skip processing
-	if m.showRawHTML {
-		// Split content by newlines to preserve existing line breaks
-		lines := strings.Split(content, "\n")
-		var wrappedLines []string
-
-		// Apply word wrap to each line
-		wrapWidth := m.width - 4 // Leave some margin
-		if wrapWidth < 40 {
-			wrapWidth = 40
-		}
-
-		for _, line := range lines {
-			if line == "" {
-				wrappedLines = append(wrappedLines, "")
-			} else {
-				wrapped := wrapText(line, wrapWidth)
-				wrappedLines = append(wrappedLines, wrapped...)
-			}
-		}
-
-		return wrappedLines
+// itemContent returns the downloaded full article of an item if there is one,
+// otherwise the content or description from the feed
+func itemContent(item database.GetItemsWithReadStatusRow) string {
+	if item.FullContent != "" {
+		return item.FullContent
 	}
-
-	// Add link markers to HTML BEFORE converting to markdown
-	// This ensures the markers are properly preserved during conversion
-	content, _ = m.feedManager.AddLinkMarkersToHTML(content)
-
-	// Convert HTML to markdown
-	content = m.feedManager.ConvertHTMLToMarkdown(content)
-
-	// Render markdown content using glamour
-	if m.glamourRenderer != nil {
-		renderedContent, err := m.glamourRenderer.Render(content)
-		if err == nil {
-			content = renderedContent
-		}
+	if item.Content != "" {
+		return item.Content
 	}
+	return item.Description
+}
 
-	contentBuilder.WriteString(content)
-	contentBuilder.WriteString("\n\n")
+func (m *Model) getArticleContentLines() []string {
+	return m.article.lines
+}
 
-	if len(m.links) > 0 {
-		contentBuilder.WriteString(m.getHelpStyle().Render("Links:"))
-		contentBuilder.WriteString("\n")
-		for i, link := range m.links {
-			contentBuilder.WriteString(fmt.Sprintf("[%d] %s\n", i+1, link))
-		}
+// articleMaxScroll returns the scroll offset that shows the end of the
+// article
+func (m *Model) articleMaxScroll() int {
+	// Calculate max scroll based on content
+	allLines := m.getArticleContentLines()
+	availableHeight := m.height - 3
+	if availableHeight < 1 {
+		availableHeight = 1
 	}
-
-	// Split content into lines for scrolling
-	return strings.Split(contentBuilder.String(), "\n")
+	return max(len(allLines)-availableHeight, 0)
 }
 
 func (m Model) renderArticle() string {
-	allLines := m.getArticleContentLines()
+	allLines, placements := m.article.lines, m.article.placements
+	if !m.articleRendered() {
+		allLines, placements = []string{m.getHelpStyle().Render("  Rendering…")}, nil
+	}
 
 	// Calculate available height for content (height - title - status bar)
 	availableHeight := m.height - 3 // -3 for title (2 lines) and status bar (1 line)
@@ -2712,11 +4198,42 @@ func (m Model) renderArticle() string {
 		end = len(allLines)
 	}
 
-	visibleLines := allLines[start:end]
+	// Copy the shown lines, highlighting and images mustn't change the
+	// rendered article
+	visibleLines := append([]string(nil), allLines[start:end]...)
+	if len(m.articleSearch.hits) > 0 && m.articleRendered() {
+		for i := range visibleLines {
+			visibleLines[i] = m.highlightArticleHits(visibleLines[i], start+i)
+		}
+	}
+
+	// Images are only drawn when all of them is shown, the terminal would
+	// scroll or draw over the status bar otherwise
+	for _, placement := range placements {
+		if placement.line-placement.image.Rows >= start && placement.line < end {
+			visibleLines[placement.line-start] = drawImageAbove(placement.image) + visibleLines[placement.line-start]
+		}
+	}
 
 	// Build final output
 	var b strings.Builder
-	b.WriteString(m.getTitleStyle().Render(m.currentItem.Title))
+	title := m.bidiTitle(m.currentItem.Title)
+	if m.currentItem.Starred {
+		title = "★ " + title
+	}
+	b.WriteString(highlightKeywords(m.getTitleStyle().Render(title), m.keywordsForFeed(m.currentItem.FeedID), m.getKeywordStyle()))
+	if m.currentItem.Published.Valid {
+		b.WriteString("  ")
+		b.WriteString(m.getHelpStyle().Render(m.articleDate(m.currentItem.Published.Time)))
+	}
+	if note := m.articleCopiesNote(); note != "" {
+		b.WriteString(" - ")
+		b.WriteString(m.getHelpStyle().Render(note))
+	}
+	if m.fullTextStatus != "" {
+		b.WriteString(" - ")
+		b.WriteString(m.getHelpStyle().Render(m.fullTextStatus))
+	}
 	b.WriteString("\n\n")
 
 	for _, line := range visibleLines {
@@ -2741,44 +4258,73 @@ func (m Model) renderArticle() string {
 	} else {
 		statusBarText = globalHelp
 	}
+	if status := m.articleSearchStatus(); status != "" {
+		statusBarText = status
+	}
 	statusBar := m.getHelpStyle().Render(statusBarText)
 	if len(allLines) > availableHeight {
-		scrollInfo := fmt.Sprintf("(%d-%d of %d) ", start+1, end, len(allLines))
+		scrollInfo := fmt.Sprintf("(%d-%d of %d) (%d%%) ", start+1, end, len(allLines), end*100/len(allLines))
 		b.WriteString(m.getHelpStyle().Render(scrollInfo))
 	}
+	if minutes := feeds.ReadingMinutes(m.article.words); minutes > 0 && m.articleRendered() {
+		b.WriteString(m.getHelpStyle().Render(fmt.Sprintf("%d min read | ", minutes)))
+	}
 	b.WriteString(statusBar)
 
 	return b.String()
 }
 
 func (m Model) handleLogListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.logStatus = ""
+
 	switch msg.String() {
 	case "?":
 		m.previousState = m.state
 		m.state = HelpView
 		return m, nil
 
-	case "q", "esc", "ctrl+c":
-		// Clear search mode when returning to feed list
-		m.searchMode = false
-		m.searchActive = false
-		m.searchQuery = ""
-		m.state = FeedListView
-		return m, loadFeedList(m.feedManager)
+	case "esc":
+		// Esc clears the filter before leaving
+		if m.logFilter.Search != "" {
+			m.logFilter.Search = ""
+			return m.reloadLogView()
+		}
+		return m.closeLogView()
+
+	case "q", "ctrl+c":
+		return m.closeLogView()
+
+	case "e", "w", "i", "d":
+		for _, level := range logLevelKeys {
+			if level.key == msg.String() {
+				return m.toggleLogLevel(level.level)
+			}
+		}
+
+	case "/":
+		m.logSearching = true
+		m.logSearchInput = m.logFilter.Search
+		return m, nil
+
+	case "f":
+		return m.toggleLogFollow()
 
 	case "j", "down":
+		m = m.stopLogFollow()
 		if len(m.logList) > 0 {
 			m.cursor = (m.cursor + 1) % len(m.logList)
 			m.savedLogCursor = m.cursor
 		}
 
 	case "k", "up":
+		m = m.stopLogFollow()
 		if len(m.logList) > 0 {
 			m.cursor = (m.cursor - 1 + len(m.logList)) % len(m.logList)
 			m.savedLogCursor = m.cursor
 		}
 
 	case "ctrl+d":
+		m = m.stopLogFollow()
 		if len(m.logList) > 0 {
 			pageSize := m.height / 2
 			if pageSize < 1 {
@@ -2800,10 +4346,34 @@ func (m Model) handleLogListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case "enter":
 		if len(m.logList) > 0 && m.cursor < len(m.logList) {
+			// Ticks stop outside the log view, follow again with f
+			m.logFollow = false
 			m.currentLog = m.logList[m.cursor]
 			m.state = LogDetailView
 		}
 
+	case "y":
+		if len(m.logList) > 0 && m.cursor < len(m.logList) {
+			return m, copyLogMessage(m.logList[m.cursor])
+		}
+
+	case "n":
+		// Older messages
+		if int64(m.logPage+1)*logPageSize < m.logTotal {
+			m = m.stopLogFollow()
+			m.cursor = 0
+			m.savedLogCursor = 0
+			return m, loadLogList(m.feedManager, m.logFilter, m.logPage+1)
+		}
+
+	case "p":
+		// Newer messages
+		if m.logPage > 0 {
+			m.cursor = 0
+			m.savedLogCursor = 0
+			return m, loadLogList(m.feedManager, m.logFilter, m.logPage-1)
+		}
+
 	case "A":
 		return m, clearAllLogMessages(m.feedManager)
 	}
@@ -2812,6 +4382,8 @@ func (m Model) handleLogListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleLogDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.logStatus = ""
+
 	switch msg.String() {
 	case "?":
 		m.previousState = m.state
@@ -2822,6 +4394,9 @@ func (m Model) handleLogDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.state = LogView
 		m.cursor = m.savedLogCursor
 		return m, nil
+
+	case "y":
+		return m, copyLogMessage(m.currentLog)
 	}
 
 	return m, nil
@@ -2894,7 +4469,19 @@ func (m Model) handleTasksViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m Model) renderLogList() string {
 	var b strings.Builder
 	b.WriteString(m.getTitleStyle().Render("🐐 NewsGoat - Log Messages"))
-	b.WriteString("\n\n")
+	if m.logStatus != "" {
+		b.WriteString(" - ")
+		b.WriteString(m.getHelpStyle().Render(m.logStatus))
+	} else {
+		b.WriteString(" ")
+		b.WriteString(m.getHelpStyle().Render(m.logStorageDescription()))
+	}
+	// The line under the title tells which messages are shown
+	b.WriteString("\n")
+	if filter := m.logFilterDescription(); filter != "" {
+		b.WriteString(m.getHelpStyle().Render(filter))
+	}
+	b.WriteString("\n")
 
 	// Build status bar
 	viewKeys := GetViewKeys(LogView)
@@ -2906,9 +4493,15 @@ func (m Model) renderLogList() string {
 		statusBarText = globalHelp
 	}
 	statusBar := m.getHelpStyle().Render(statusBarText)
+	if m.logSearching {
+		statusBar = m.getHelpStyle().Render("Filter log messages: " + m.logSearchInput)
+	}
 
 	if len(m.logList) == 0 {
 		content := "No log messages found."
+		if m.logFilter.Active() {
+			content = "No log messages match the filter."
+		}
 		// Calculate padding to push status bar to bottom
 		contentLines := strings.Count(b.String()+content, "\n") + 2
 		padding := m.height - contentLines - 1
@@ -2957,14 +4550,15 @@ func (m Model) renderLogList() string {
 			timestampStr = log.Timestamp.Time.Format("2006-01-02 15:04:05")
 		}
 
-		line := timestampStr + "  " + log.Message
+		// Messages logged before redaction existed may still hold tokens
+		line := timestampStr + "  " + logging.Redact(log.Message)
 
 		// Parse attributes to check for error
 		if log.Attributes.Valid && log.Attributes.String != "" {
 			var attrs map[string]interface{}
 			if err := json.Unmarshal([]byte(log.Attributes.String), &attrs); err == nil {
 				if errMsg, ok := attrs["error"]; ok {
-					line += " | error: " + fmt.Sprintf("%v", errMsg)
+					line += " | error: " + logging.Redact(fmt.Sprintf("%v", errMsg))
 				}
 			}
 		}
@@ -2987,9 +4581,10 @@ func (m Model) renderLogList() string {
 	}
 	b.WriteString(strings.Repeat("\n", padding))
 
-	// Show scroll indicator if there are more logs
-	if len(m.logList) > availableHeight {
-		scrollInfo := fmt.Sprintf("(%d-%d of %d)", start+1, end, len(m.logList))
+	// Show scroll indicator if there are more logs, counting from the newest message
+	if len(m.logList) > availableHeight || m.logTotal > int64(len(m.logList)) {
+		offset := m.logPage * logPageSize
+		scrollInfo := fmt.Sprintf("(%d-%d of %d)", offset+start+1, offset+end, max(int(m.logTotal), len(m.logList)))
 		b.WriteString(m.getHelpStyle().Render(scrollInfo))
 		b.WriteString("  ")
 	}
@@ -3002,6 +4597,10 @@ func (m Model) renderLogList() string {
 func (m Model) renderLogDetail() string {
 	var b strings.Builder
 	b.WriteString(m.getTitleStyle().Render("🐐 NewsGoat - Log Message Details"))
+	if m.logStatus != "" {
+		b.WriteString(" - ")
+		b.WriteString(m.getHelpStyle().Render(m.logStatus))
+	}
 	b.WriteString("\n\n")
 
 	// Timestamp
@@ -3013,7 +4612,7 @@ func (m Model) renderLogDetail() string {
 	b.WriteString(fmt.Sprintf("Level: %s\n", m.currentLog.Level))
 
 	// Message
-	b.WriteString(fmt.Sprintf("Message: %s\n\n", m.currentLog.Message))
+	b.WriteString(fmt.Sprintf("Message: %s\n\n", logging.Redact(m.currentLog.Message)))
 
 	// Attributes (if any)
 	if m.currentLog.Attributes.Valid && m.currentLog.Attributes.String != "" {
@@ -3038,7 +4637,7 @@ func (m Model) renderLogDetail() string {
 				value := attrs[key]
 
 				// Format the value as a string
-				valueStr := fmt.Sprintf("%v", value)
+				valueStr := logging.Redact(fmt.Sprintf("%v", value))
 
 				// Word wrap the value if it's long
 				// Calculate available width: total width - indent (2) - key length - ": " (2) - margin (2)
@@ -3063,7 +4662,7 @@ func (m Model) renderLogDetail() string {
 			}
 		} else {
 			// Failed to parse JSON, just display raw (with wrapping)
-			wrappedLines := wrapText(m.currentLog.Attributes.String, m.width-4)
+			wrappedLines := wrapText(logging.Redact(m.currentLog.Attributes.String), m.width-4)
 			for _, line := range wrappedLines {
 				b.WriteString("  " + line + "\n")
 			}
@@ -3079,7 +4678,7 @@ func (m Model) renderLogDetail() string {
 	}
 	b.WriteString(strings.Repeat("\n", padding))
 
-	b.WriteString(m.getHelpStyle().Render("h: help"))
+	b.WriteString(m.getHelpStyle().Render("h: help | y: copy"))
 
 	return b.String()
 }
@@ -3177,17 +4776,30 @@ func (m Model) renderHelpView() string {
 	content.WriteString("Feed List View\n")
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "r", "Refresh selected feed"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "R", "Refresh all feeds"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "n, N", "Jump to the next/previous feed with unread items"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "A", "Mark all items in feed as read"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "u", "Undo marking all read, while the status line offers it"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "i", "Show feed info"))
-	content.WriteString(fmt.Sprintf("  %-15s %s\n", "ctrl+u", "Upgrade to new version (when available)"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "p", "Pause/resume refreshing the selected feed"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "f", "Set the folders of the selected feed"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "T", "Show only the feeds of a folder (tag), esc shows all"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "X", "Rules hiding or marking read items at refresh"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "S", "Reading statistics and feed activity"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "F", "Test fetch the selected feed without saving"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "D", "Move a redirecting or failing feed to its new URL"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "ctrl+g", "Update to the new version (when available, ctrl+u too)"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "/", "Global search (text of all feeds)"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "ctrl+f", "Title search only"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", ":", "filter <expression> narrows the feed list, sync syncs"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "u", "Add URL (with discovery)"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "U", "Edit URLs in $EDITOR"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "ctrl+r", "Reload URLs from file"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "H", "Hot items (unread ranked best-first)"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "left, right", "Previous/next column (columns layout)"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "l", "View logs"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "t", "View tasks"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "c", "View settings"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "K", "View and rebind key bindings"))
 	content.WriteString("\n")
 
 	// Item List View keys
@@ -3195,14 +4807,23 @@ func (m Model) renderHelpView() string {
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "r", "Refresh feed"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "R", "Refresh all feeds"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "A", "Mark all items as read"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "u", "Undo marking all read, while the status line offers it"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "/", "Global search (text of all feeds)"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "ctrl+f", "Title search only"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", ":", "filter <expression> narrows the item list, sync syncs"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "h, left", "Scroll title left"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "l, right", "Scroll title right"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "0", "Jump to start of title"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "$", "Jump to end of title"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "N", "Toggle read status of item"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "tab", "Jump to the next unread item, in the next feeds when none are left"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "m", "Mark item read and move to the next one"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "M", "Mark all items above the cursor as read"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "s", "Star/unstar item"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "b", "Send item to read-later service"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "y", "Copy item link to clipboard"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "o", "Open item link in browser"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "space", "Expand/collapse story cluster"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "c", "View settings"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "t", "View tasks"))
 	content.WriteString("\n")
@@ -3213,7 +4834,20 @@ func (m Model) renderHelpView() string {
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "o", "Open article link in browser"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "n", "Next article"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "N", "Previous article"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "tab", "Next unread article, in the next feeds when none are left"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "g/G", "Jump to the top/bottom of the article"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "/", "Search the article, n/p jump between matches"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "r", "Toggle raw HTML view"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "R", "View raw HTML in $PAGER"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "O", "Open raw HTML in browser"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "f", "Fetch full article from the link"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "|", "Pipe article to the pipe command"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "e", "Export article to a markdown, HTML or PDF file"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "S", "Share article with a share command"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "s", "Star/unstar article"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "b", "Send article to read-later service"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "y", "Copy article link to clipboard"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "Y", "Copy article text to clipboard"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "c", "View settings"))
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "t", "View tasks"))
 	content.WriteString("\n")
@@ -3221,6 +4855,7 @@ func (m Model) renderHelpView() string {
 	// Settings View keys
 	content.WriteString("Settings View\n")
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "?", "Toggle settings help"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "P", "Prune old items now"))
 	content.WriteString("\n")
 
 	// Tasks View keys
@@ -3230,9 +4865,21 @@ func (m Model) renderHelpView() string {
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "l", "View logs"))
 	content.WriteString("\n")
 
+	// Feed Info View keys
+	content.WriteString("Feed Info View\n")
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "t", "Rename the feed, an empty title uses the feed's own"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "e", "Edit the URL of the feed, keeping its items"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "r", "Refresh the feed now, ignoring its cache"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "l", "View the log messages of the feed"))
+	content.WriteString("\n")
+
 	// Log View keys
 	content.WriteString("Log View\n")
 	content.WriteString(fmt.Sprintf("  %-15s %s\n", "c", "Clear all log messages"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "y", "Copy log message to clipboard"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "e/w/i/d", "Show/hide error, warning, info or debug messages"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "/", "Filter messages by text, esc clears the filter"))
+	content.WriteString(fmt.Sprintf("  %-15s %s\n", "f", "Follow new messages, moving the cursor stops following"))
 	content.WriteString("\n")
 
 	// Status icons legend - unified section
@@ -3243,6 +4890,7 @@ func (m Model) renderHelpView() string {
 	content.WriteString("  ⚠️              500/502/503 Server Error\n")
 	content.WriteString("  ⌛              Timeout\n")
 	content.WriteString("  ❌              Other Error\n")
+	content.WriteString("  ⏸️              Paused feed\n")
 	content.WriteString("  🕓              Pending task\n")
 	content.WriteString("  🔄              Running task\n")
 	content.WriteString("  💥              Failed task\n")
@@ -3362,6 +5010,15 @@ func (m Model) renderTasksView() string {
 		}
 	}
 
+	// Running tasks that report their progress get a progress column
+	showProgress := false
+	for _, task := range m.taskList[start:end] {
+		if task.Status == tasks.TaskStatusRunning && task.Progress != nil {
+			showProgress = true
+			break
+		}
+	}
+
 	// Render visible tasks
 	taskLines := 0
 	for i := start; i < end; i++ {
@@ -3376,6 +5033,8 @@ func (m Model) renderTasksView() string {
 			statusEmoji = "🔄"
 		case tasks.TaskStatusFailed:
 			statusEmoji = "💥"
+		case tasks.TaskStatusRetrying:
+			statusEmoji = "🔁"
 		default:
 			statusEmoji = " "
 		}
@@ -3384,12 +5043,30 @@ func (m Model) renderTasksView() string {
 		taskDesc := string(task.Type)
 		if feedURL, ok := task.Data["url"].(string); ok {
 			taskDesc = feedURL
+			if task.Type != tasks.TaskTypeFeedRefresh {
+				taskDesc = string(task.Type) + " " + feedURL
+			}
 		}
 
 		// Format timestamp
 		timeStr := task.CreatedAt.Format("15:04:05")
 
 		line := fmt.Sprintf("%s %s %s", statusEmoji, timeStr, taskDesc)
+		if showProgress {
+			line = fmt.Sprintf("%s %s %s %s", statusEmoji, timeStr, progressColumn(task), taskDesc)
+		}
+
+		if task.Status == tasks.TaskStatusRunning && task.Progress != nil && task.Progress.Message != "" {
+			line += " (" + task.Progress.Message + ")"
+		}
+
+		// Tasks that failed before show how often they ran and when they
+		// run again
+		if task.Status == tasks.TaskStatusRetrying && task.NextRetry != nil {
+			line += fmt.Sprintf(" (attempt %d, retry at %s)", task.Attempts, task.NextRetry.Format("15:04:05"))
+		} else if task.Attempts > 1 {
+			line += fmt.Sprintf(" (attempt %d)", task.Attempts)
+		}
 
 		// Apply highlighting
 		line = m.applyHighlight(line, i == m.cursor)
@@ -3421,7 +5098,64 @@ func (m Model) renderTasksView() string {
 	return b.String()
 }
 
+// progressBarWidth is the number of cells of the progress bar in the task list
+const progressBarWidth = 10
+
+// progressColumn renders how far a running task got as a bar with its
+// percentage, blank for tasks without progress
+func progressColumn(task *tasks.Task) string {
+	width := progressBarWidth + 5 // bar and " 100%"
+	if task.Status != tasks.TaskStatusRunning || task.Progress == nil {
+		return strings.Repeat(" ", width)
+	}
+	percent := task.Progress.Percent()
+	if percent < 0 {
+		return "…" + strings.Repeat(" ", width-1)
+	}
+	filled := percent * progressBarWidth / 100
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+	return fmt.Sprintf("%s %3d%%", bar, percent)
+}
+
+// applyRetryPolicy hands changed retry settings to the task manager, a
+// daemon reads them itself and read-only windows don't refresh
+func (m Model) applyRetryPolicy() {
+	if m.readOnly || m.daemonPID != 0 {
+		return
+	}
+	m.taskManager.SetRetryPolicy(tasks.TaskTypeFeedRefresh, m.config.RefreshRetryPolicy())
+}
+
+// applySchedules reschedules pruning and the update check after their
+// settings changed, pruning only where the feeds are refreshed
+func (m Model) applySchedules() error {
+	if m.refreshJob != nil {
+		settings := func() config.Config {
+			current, err := config.LoadConfig(m.queries)
+			if err != nil {
+				logging.Warn("Failed to load settings", "error", err)
+			}
+			return current
+		}
+		if err := tasks.SchedulePrune(m.taskManager, m.config.PruneSchedule, settings); err != nil {
+			return err
+		}
+	}
+	if m.readOnly {
+		return nil
+	}
+	spec := m.config.UpdateCheckSchedule
+	if !m.config.CheckForUpdates {
+		spec = ""
+	}
+	return tasks.ScheduleUpdateCheck(m.taskManager, spec)
+}
+
 func (m Model) handleSettingsViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Clear a pruning status on the next key press
+	m.statusMessage = ""
+	m.statusMessageType = ""
+
 	// If we're selecting a theme, handle theme selector
 	if m.selectingTheme {
 		switch msg.String() {
@@ -3431,7 +5165,7 @@ func (m Model) handleSettingsViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case "j", "down":
-			themeNames := themes.GetThemeNames()
+			themeNames := themes.GetThemeChoices()
 			if m.themeSelectCursor < len(themeNames)-1 {
 				m.themeSelectCursor++
 			}
@@ -3445,17 +5179,21 @@ func (m Model) handleSettingsViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 		case "enter":
 			// Apply the selected theme
-			themeNames := themes.GetThemeNames()
+			themeNames := themes.GetThemeChoices()
 			m.config.ThemeName = themeNames[m.themeSelectCursor]
+			if m.config.ThemeName == themes.AutoTheme {
+				m.autoThemeSelected()
+			}
+			// Custom themes may come with their own highlight style
+			if theme := themes.GetThemeByName(m.themeName()); theme.Path != "" && theme.HighlightStyle != "" {
+				m.config.HighlightStyle = theme.HighlightStyle
+			}
 			if err := config.SaveConfig(m.queries, m.config); err != nil {
 				m.err = err
 			}
 
 			// Update glamour renderer
-			renderer, err := createGlamourRenderer(m.config.ThemeName)
-			if err == nil {
-				m.glamourRenderer = renderer
-			}
+			m.updateGlamourRenderer()
 
 			m.selectingTheme = false
 			return m, nil
@@ -3689,12 +5427,155 @@ func (m Model) handleSettingsViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if err := config.SaveConfig(m.queries, m.config); err != nil {
 				m.err = err
 			}
+			if err := m.applySchedules(); err != nil {
+				m.err = err
+			}
 			m.selectingCheckForUpdates = false
 			return m, nil
 		}
 		return m, nil
 	}
 
+	// If we're selecting cluster stories, handle selector navigation
+	if m.selectingClusterStories {
+		switch msg.String() {
+		case "esc":
+			m.selectingClusterStories = false
+			return m, nil
+		case "j", "down":
+			if m.clusterStoriesSelectCursor < 1 {
+				m.clusterStoriesSelectCursor++
+			}
+			return m, nil
+		case "k", "up":
+			if m.clusterStoriesSelectCursor > 0 {
+				m.clusterStoriesSelectCursor--
+			}
+			return m, nil
+		case "enter":
+			m.config.ClusterStories = (m.clusterStoriesSelectCursor == 0)
+			if err := config.SaveConfig(m.queries, m.config); err != nil {
+				m.err = err
+			}
+			m.selectingClusterStories = false
+			return m, nil
+		}
+		return m, nil
+	}
+
+	// If we're selecting update redirects, handle selector navigation
+	if m.selectingUpdateRedirects {
+		switch msg.String() {
+		case "esc":
+			m.selectingUpdateRedirects = false
+			return m, nil
+		case "j", "down":
+			if m.updateRedirectsSelectCursor < 1 {
+				m.updateRedirectsSelectCursor++
+			}
+			return m, nil
+		case "k", "up":
+			if m.updateRedirectsSelectCursor > 0 {
+				m.updateRedirectsSelectCursor--
+			}
+			return m, nil
+		case "enter":
+			m.config.UpdateRedirects = (m.updateRedirectsSelectCursor == 0)
+			if err := config.SaveConfig(m.queries, m.config); err != nil {
+				m.err = err
+			}
+			if m.config.UpdateRedirects {
+				m.feedManager.SetRedirectUpdates(m.urlsFilePath)
+			} else {
+				m.feedManager.SetRedirectUpdates("")
+			}
+			m.selectingUpdateRedirects = false
+			return m, nil
+		}
+		return m, nil
+	}
+
+	// If we're selecting hide duplicates, handle selector navigation
+	if m.selectingHideDuplicates {
+		switch msg.String() {
+		case "esc":
+			m.selectingHideDuplicates = false
+			return m, nil
+		case "j", "down":
+			if m.hideDuplicatesSelectCursor < 1 {
+				m.hideDuplicatesSelectCursor++
+			}
+			return m, nil
+		case "k", "up":
+			if m.hideDuplicatesSelectCursor > 0 {
+				m.hideDuplicatesSelectCursor--
+			}
+			return m, nil
+		case "enter":
+			m.config.HideDuplicates = (m.hideDuplicatesSelectCursor == 0)
+			if err := config.SaveConfig(m.queries, m.config); err != nil {
+				m.err = err
+			}
+			m.selectingHideDuplicates = false
+			return m, nil
+		}
+		return m, nil
+	}
+
+	// If we're selecting mark read on open, handle selector navigation
+	if m.selectingMarkReadOnOpen {
+		switch msg.String() {
+		case "esc":
+			m.selectingMarkReadOnOpen = false
+			return m, nil
+		case "j", "down":
+			if m.markReadOnOpenSelectCursor < 1 {
+				m.markReadOnOpenSelectCursor++
+			}
+			return m, nil
+		case "k", "up":
+			if m.markReadOnOpenSelectCursor > 0 {
+				m.markReadOnOpenSelectCursor--
+			}
+			return m, nil
+		case "enter":
+			m.config.MarkReadOnOpen = (m.markReadOnOpenSelectCursor == 0)
+			if err := config.SaveConfig(m.queries, m.config); err != nil {
+				m.err = err
+			}
+			m.selectingMarkReadOnOpen = false
+			return m, nil
+		}
+		return m, nil
+	}
+
+	// If we're selecting mark skipped read, handle selector navigation
+	if m.selectingMarkSkippedRead {
+		switch msg.String() {
+		case "esc":
+			m.selectingMarkSkippedRead = false
+			return m, nil
+		case "j", "down":
+			if m.markSkippedReadSelectCursor < 1 {
+				m.markSkippedReadSelectCursor++
+			}
+			return m, nil
+		case "k", "up":
+			if m.markSkippedReadSelectCursor > 0 {
+				m.markSkippedReadSelectCursor--
+			}
+			return m, nil
+		case "enter":
+			m.config.MarkSkippedRead = (m.markSkippedReadSelectCursor == 0)
+			if err := config.SaveConfig(m.queries, m.config); err != nil {
+				m.err = err
+			}
+			m.selectingMarkSkippedRead = false
+			return m, nil
+		}
+		return m, nil
+	}
+
 	// If we're editing reload concurrency, handle input
 	if m.editingSettings {
 		switch msg.Type {
@@ -3720,25 +5601,459 @@ func (m Model) handleSettingsViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 						}
 					}
 				}
-			case 1:
-				// Reload time
-				if val, parseErr := strconv.Atoi(m.settingInput); parseErr == nil {
-					if val >= 0 {
-						m.config.ReloadTime = val
-						if err := config.SaveConfig(m.queries, m.config); err != nil {
-							m.err = err
-						}
-						// If reload time changed, restart the timer
-						if oldReloadTime != m.config.ReloadTime {
-							return m, restartReloadTimer()
-						}
+			case 1:
+				// Reload time
+				if val, parseErr := strconv.Atoi(m.settingInput); parseErr == nil {
+					if val >= 0 {
+						m.config.ReloadTime = val
+						if err := config.SaveConfig(m.queries, m.config); err != nil {
+							m.err = err
+						}
+						// If reload time changed, restart the timer
+						if oldReloadTime != m.config.ReloadTime {
+							return m, restartReloadTimer()
+						}
+					}
+				}
+			case 12:
+				// Hot keywords
+				m.config.HotKeywords = strings.TrimSpace(m.settingInput)
+				if err := config.SaveConfig(m.queries, m.config); err != nil {
+					m.err = err
+				}
+			case 13:
+				// Highlight keywords
+				m.config.HighlightKeywords = strings.TrimSpace(m.settingInput)
+				if err := config.SaveConfig(m.queries, m.config); err != nil {
+					m.err = err
+				}
+			case 14:
+				// Reading export
+				source := strings.ToLower(strings.TrimSpace(m.settingInput))
+				if source == "off" {
+					source = ""
+				}
+				if source == "" || source == feeds.ReadingLogStarred || source == feeds.ReadingLogRead {
+					m.config.ReadingExport = source
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+				}
+			case 15:
+				// Reading export path
+				m.config.ReadingExportPath = strings.TrimSpace(m.settingInput)
+				if err := config.SaveConfig(m.queries, m.config); err != nil {
+					m.err = err
+				}
+			case 16:
+				// Reading export gist
+				m.config.ReadingExportGist = strings.TrimSpace(m.settingInput)
+				if err := config.SaveConfig(m.queries, m.config); err != nil {
+					m.err = err
+				}
+			case 17:
+				// HTTP proxy
+				proxy := strings.TrimSpace(m.settingInput)
+				if _, err := config.ParseProxyURL(proxy); err != nil {
+					m.err = err
+				} else {
+					m.config.HTTPProxy = proxy
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+					m.feedManager.SetRequestOptions(m.config.RequestOptions())
+				}
+			case 18:
+				// User-Agent
+				m.config.UserAgent = strings.TrimSpace(m.settingInput)
+				if err := config.SaveConfig(m.queries, m.config); err != nil {
+					m.err = err
+				}
+				m.feedManager.SetRequestOptions(m.config.RequestOptions())
+			case 19:
+				// Request headers
+				headers := strings.TrimSpace(m.settingInput)
+				if _, err := config.ParseHeaders(headers); err != nil {
+					m.err = err
+				} else {
+					m.config.RequestHeaders = headers
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+					m.feedManager.SetRequestOptions(m.config.RequestOptions())
+				}
+			case 20:
+				// Feed list layout
+				layout := strings.ToLower(strings.TrimSpace(m.settingInput))
+				if layout == config.FeedListLayoutSingle || layout == config.FeedListLayoutColumns {
+					m.config.FeedListLayout = layout
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+				}
+			case 21:
+				// Pipe command
+				m.config.PipeCommand = strings.TrimSpace(m.settingInput)
+				if err := config.SaveConfig(m.queries, m.config); err != nil {
+					m.err = err
+				}
+			case 22:
+				// Pipe format
+				format := strings.ToLower(strings.TrimSpace(m.settingInput))
+				if format == config.PipeFormatMarkdown || format == config.PipeFormatHTML {
+					m.config.PipeFormat = format
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+				}
+			case 23:
+				// Old item days
+				if val, parseErr := strconv.Atoi(strings.TrimSpace(m.settingInput)); parseErr == nil && val >= 0 {
+					m.config.OldItemDays = val
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+				}
+			case 24:
+				// Debug categories
+				categories, err := logging.ParseDebugCategories(m.settingInput)
+				if err != nil {
+					m.err = err
+				} else {
+					m.config.DebugCategories = strings.Join(categories, ",")
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+					logging.SetDebugCategories(categories)
+				}
+			case 25:
+				// Read later service
+				service := strings.ToLower(strings.TrimSpace(m.settingInput))
+				if service == "off" {
+					service = ""
+				}
+				if service == "" || feeds.IsReadLaterService(service) {
+					m.config.ReadLater = service
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+				}
+			case 26:
+				// Read later URL
+				m.config.ReadLaterURL = strings.TrimSpace(m.settingInput)
+				if err := config.SaveConfig(m.queries, m.config); err != nil {
+					m.err = err
+				}
+			case 27:
+				// Read later auth
+				m.config.ReadLaterAuth = strings.TrimSpace(m.settingInput)
+				feeds.NewReadLaterConfig(m.config).RegisterSecrets()
+				if err := config.SaveConfig(m.queries, m.config); err != nil {
+					m.err = err
+				}
+			case 28:
+				// Max items per feed
+				if val, parseErr := strconv.Atoi(strings.TrimSpace(m.settingInput)); parseErr == nil && val >= 0 {
+					m.config.MaxItemsPerFeed = val
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+					m.feedManager.SetRetentionPolicy(m.config.RetentionPolicy())
+				}
+			case 29:
+				// Max item age
+				if val, parseErr := strconv.Atoi(strings.TrimSpace(m.settingInput)); parseErr == nil && val >= 0 {
+					m.config.MaxItemAgeDays = val
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+					m.feedManager.SetRetentionPolicy(m.config.RetentionPolicy())
+				}
+			case 30:
+				// Prune keep
+				keep, err := config.ParsePruneKeep(m.settingInput)
+				if err != nil {
+					m.err = err
+				} else {
+					m.config.PruneKeep = strings.Join(keep, ",")
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+					m.feedManager.SetRetentionPolicy(m.config.RetentionPolicy())
+				}
+			case 31:
+				// WebSub listen address
+				m.config.WebSubListen = strings.TrimSpace(m.settingInput)
+				if err := config.SaveConfig(m.queries, m.config); err != nil {
+					m.err = err
+				}
+			case 32:
+				// WebSub callback URL
+				m.config.WebSubCallbackURL = strings.TrimSpace(m.settingInput)
+				if err := config.SaveConfig(m.queries, m.config); err != nil {
+					m.err = err
+				}
+			case 33:
+				// Log max messages
+				if val, parseErr := strconv.Atoi(strings.TrimSpace(m.settingInput)); parseErr == nil && val >= 0 {
+					m.config.LogMaxMessages = val
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+				}
+			case 34:
+				// Log max age
+				if val, parseErr := strconv.Atoi(strings.TrimSpace(m.settingInput)); parseErr == nil && val >= 0 {
+					m.config.LogMaxAgeDays = val
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+				}
+			case 35:
+				// Sync service
+				service := strings.ToLower(strings.TrimSpace(m.settingInput))
+				if service == "off" {
+					service = ""
+				}
+				if service == "" || feedsync.IsService(service) {
+					m.config.Sync = service
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+				}
+			case 36:
+				// Sync URL
+				m.config.SyncURL = strings.TrimSpace(m.settingInput)
+				if err := config.SaveConfig(m.queries, m.config); err != nil {
+					m.err = err
+				}
+			case 37:
+				// Sync token, kept in the keychain instead of the database
+				token := strings.TrimSpace(m.settingInput)
+				if token == "" {
+					_ = secrets.Delete(feedsync.TokenSecret)
+				} else if !secrets.IsReference(token) {
+					if err := secrets.Set(feedsync.TokenSecret, token); err != nil {
+						logging.Warn("Failed to keep the sync token in the keychain", "error", err)
+						logging.RegisterSecret(token)
+					} else {
+						token = secrets.Reference(feedsync.TokenSecret)
+					}
+				}
+				m.config.SyncToken = token
+				feedsync.NewConfig(m.config).RegisterSecrets()
+				if err := config.SaveConfig(m.queries, m.config); err != nil {
+					m.err = err
+				}
+			case 38:
+				// RTL display
+				display := strings.ToLower(strings.TrimSpace(m.settingInput))
+				if display == config.RTLDisplayReorder || display == config.RTLDisplayTerminal {
+					m.config.RTLDisplay = display
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+				}
+			case 39:
+				// Notify
+				notify := strings.TrimSpace(m.settingInput)
+				if strings.EqualFold(notify, "off") {
+					notify = ""
+				}
+				m.config.Notify = notify
+				if err := config.SaveConfig(m.queries, m.config); err != nil {
+					m.err = err
+				}
+				m.feedManager.SetNotifyPolicy(m.config.NotifyPolicy())
+			case 40:
+				// Images
+				images := strings.ToLower(strings.TrimSpace(m.settingInput))
+				if images == "off" {
+					images = ""
+				}
+				switch images {
+				case "", config.ImagesAuto, config.ImagesKitty, config.ImagesITerm2, config.ImagesSixel:
+					m.config.Images = images
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+					m.imageProtocol = termimage.Select(images, os.Getenv)
+					m.articleImagesContent = ""
+				}
+			case 41:
+				// Article max width
+				if val, parseErr := strconv.Atoi(strings.TrimSpace(m.settingInput)); parseErr == nil && val >= 0 {
+					m.config.ArticleMaxWidth = val
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+					m.updateGlamourRenderer()
+				}
+			case 42:
+				// Item list format, the spaces in it are kept
+				m.config.ItemListFormat = m.settingInput
+				if strings.TrimSpace(m.settingInput) == "" {
+					m.config.ItemListFormat = ""
+				}
+				if err := config.SaveConfig(m.queries, m.config); err != nil {
+					m.err = err
+				}
+			case 43:
+				// Date format, a layout may have spaces in it
+				m.config.DateFormat = m.settingInput
+				if strings.TrimSpace(m.settingInput) == "" {
+					m.config.DateFormat = ""
+				} else if strings.EqualFold(strings.TrimSpace(m.settingInput), config.DateFormatRelative) {
+					m.config.DateFormat = config.DateFormatRelative
+				}
+				if err := config.SaveConfig(m.queries, m.config); err != nil {
+					m.err = err
+				}
+			case 44:
+				// Dead feed threshold
+				if val, parseErr := strconv.Atoi(strings.TrimSpace(m.settingInput)); parseErr == nil && val >= 0 {
+					m.config.DeadFeedThreshold = val
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+				}
+			case 45:
+				// Host request rate
+				if val, parseErr := strconv.Atoi(strings.TrimSpace(m.settingInput)); parseErr == nil && val >= 0 {
+					m.config.HostRequestRate = val
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+					m.feedManager.SetHostLimits(m.config.HostLimits())
+				}
+			case 46:
+				// Host max parallel
+				if val, parseErr := strconv.Atoi(strings.TrimSpace(m.settingInput)); parseErr == nil && val >= 0 {
+					m.config.HostMaxParallel = val
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+					m.feedManager.SetHostLimits(m.config.HostLimits())
+				}
+			case 51:
+				// Share commands
+				commands := strings.TrimSpace(m.settingInput)
+				if _, err := config.ParseShareActions(commands); err != nil {
+					m.err = err
+				} else {
+					m.config.ShareCommands = commands
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+				}
+			case 52:
+				// Refresh retries
+				if val, parseErr := strconv.Atoi(strings.TrimSpace(m.settingInput)); parseErr == nil && val >= 0 {
+					m.config.RefreshRetries = val
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+					m.applyRetryPolicy()
+				}
+			case 53:
+				// Refresh retry delay
+				if val, parseErr := strconv.Atoi(strings.TrimSpace(m.settingInput)); parseErr == nil && val > 0 {
+					m.config.RefreshRetryDelay = val
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+					m.applyRetryPolicy()
+				}
+			case 54, 55:
+				// Prune and update check schedules, empty turns them off
+				spec := strings.TrimSpace(m.settingInput)
+				if spec != "" {
+					if _, err := tasks.ParseSchedule(spec); err != nil {
+						m.err = err
+						break
+					}
+				}
+				if m.cursor == 54 {
+					m.config.PruneSchedule = spec
+				} else {
+					m.config.UpdateCheckSchedule = spec
+				}
+				if err := config.SaveConfig(m.queries, m.config); err != nil {
+					m.err = err
+				}
+				if err := m.applySchedules(); err != nil {
+					m.err = err
+				}
+			case 56:
+				// Feed icons
+				style := strings.ToLower(strings.TrimSpace(m.settingInput))
+				if style == config.FeedIconsOff || style == config.FeedIconsGlyph || style == config.FeedIconsNerdFont {
+					wasOff := m.config.FeedIcons == config.FeedIconsOff
+					m.config.FeedIcons = style
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+					// Another NewsGoat looks up the icons in read-only mode
+					enabled := style != config.FeedIconsOff && !m.readOnly
+					m.feedManager.SetFeedIcons(enabled)
+					if enabled && wasOff {
+						m.settingInput = ""
+						return m, queueFeedIcons(m.feedManager, m.taskManager, m.allFeeds)
+					}
+				}
+			case 57:
+				// Macros
+				macros := strings.TrimSpace(m.settingInput)
+				if _, err := parseMacros(macros); err != nil {
+					m.err = err
+				} else {
+					m.config.Macros = macros
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
 					}
 				}
+			case 58:
+				// Feed history pages
+				if val, parseErr := strconv.Atoi(strings.TrimSpace(m.settingInput)); parseErr == nil && val >= 0 {
+					m.config.FeedHistoryPages = val
+					if err := config.SaveConfig(m.queries, m.config); err != nil {
+						m.err = err
+					}
+					m.feedManager.SetHistoryPages(val)
+				}
+			case 59:
+				// Export directory, empty goes back to the default
+				m.config.ExportDir = strings.TrimSpace(m.settingInput)
+				if m.config.ExportDir == "" {
+					m.config.ExportDir = config.GetDefaultConfig().ExportDir
+				}
+				if err := config.SaveConfig(m.queries, m.config); err != nil {
+					m.err = err
+				}
+			case 60:
+				// Export file name, empty goes back to the default
+				m.config.ExportFileName = strings.TrimSpace(m.settingInput)
+				if m.config.ExportFileName == "" {
+					m.config.ExportFileName = config.DefaultExportFileName
+				}
+				if err := config.SaveConfig(m.queries, m.config); err != nil {
+					m.err = err
+				}
+			case 61:
+				// Export PDF command
+				m.config.ExportPDFCommand = strings.TrimSpace(m.settingInput)
+				if err := config.SaveConfig(m.queries, m.config); err != nil {
+					m.err = err
+				}
 			}
 
 			m.settingInput = ""
 			return m, nil
 
+		case tea.KeySpace:
+			m.settingInput += " "
+			return m, nil
+
 		case tea.KeyBackspace:
 			// Delete last character
 			if len(m.settingInput) > 0 {
@@ -3770,8 +6085,8 @@ func (m Model) handleSettingsViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, loadFeedList(m.feedManager)
 
 	case "j", "down":
-		// 11 total settings
-		if m.cursor < 10 {
+		// 63 total settings
+		if m.cursor < 62 {
 			m.cursor++
 			m.savedSettingsCursor = m.cursor
 		}
@@ -3819,7 +6134,7 @@ func (m Model) handleSettingsViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		} else if m.cursor == 5 {
 			// Theme - open theme selector
 			m.selectingTheme = true
-			themeNames := themes.GetThemeNames()
+			themeNames := themes.GetThemeChoices()
 			for i, name := range themeNames {
 				if name == m.config.ThemeName {
 					m.themeSelectCursor = i
@@ -3870,7 +6185,253 @@ func (m Model) handleSettingsViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			} else {
 				m.checkForUpdatesSelectCursor = 1
 			}
+		} else if m.cursor == 11 {
+			// Cluster stories - open selector
+			m.selectingClusterStories = true
+			if m.config.ClusterStories {
+				m.clusterStoriesSelectCursor = 0
+			} else {
+				m.clusterStoriesSelectCursor = 1
+			}
+		} else if m.cursor == 12 {
+			// Hot keywords - text input
+			m.editingSettings = true
+			m.settingInput = m.config.HotKeywords
+		} else if m.cursor == 13 {
+			// Highlight keywords - text input
+			m.editingSettings = true
+			m.settingInput = m.config.HighlightKeywords
+		} else if m.cursor == 14 {
+			// Reading export - text input
+			m.editingSettings = true
+			m.settingInput = m.config.ReadingExport
+		} else if m.cursor == 15 {
+			// Reading export path - text input
+			m.editingSettings = true
+			m.settingInput = m.config.ReadingExportPath
+		} else if m.cursor == 16 {
+			// Reading export gist - text input
+			m.editingSettings = true
+			m.settingInput = m.config.ReadingExportGist
+		} else if m.cursor == 17 {
+			// HTTP proxy - text input
+			m.editingSettings = true
+			m.settingInput = m.config.HTTPProxy
+		} else if m.cursor == 18 {
+			// User-Agent - text input
+			m.editingSettings = true
+			m.settingInput = m.config.UserAgent
+		} else if m.cursor == 19 {
+			// Request headers - text input
+			m.editingSettings = true
+			m.settingInput = m.config.RequestHeaders
+		} else if m.cursor == 20 {
+			// Feed list layout - text input
+			m.editingSettings = true
+			m.settingInput = m.config.FeedListLayout
+		} else if m.cursor == 21 {
+			// Pipe command - text input
+			m.editingSettings = true
+			m.settingInput = m.config.PipeCommand
+		} else if m.cursor == 22 {
+			// Pipe format - text input
+			m.editingSettings = true
+			m.settingInput = m.config.PipeFormat
+		} else if m.cursor == 23 {
+			// Old item days - text input
+			m.editingSettings = true
+			m.settingInput = fmt.Sprintf("%d", m.config.OldItemDays)
+		} else if m.cursor == 24 {
+			// Debug categories - text input
+			m.editingSettings = true
+			m.settingInput = m.config.DebugCategories
+		} else if m.cursor == 25 {
+			// Read later service - text input
+			m.editingSettings = true
+			m.settingInput = m.config.ReadLater
+		} else if m.cursor == 26 {
+			// Read later URL - text input
+			m.editingSettings = true
+			m.settingInput = m.config.ReadLaterURL
+		} else if m.cursor == 27 {
+			// Read later auth - text input
+			m.editingSettings = true
+			m.settingInput = m.config.ReadLaterAuth
+		} else if m.cursor == 28 {
+			// Max items per feed - text input
+			m.editingSettings = true
+			m.settingInput = fmt.Sprintf("%d", m.config.MaxItemsPerFeed)
+		} else if m.cursor == 29 {
+			// Max item age - text input
+			m.editingSettings = true
+			m.settingInput = fmt.Sprintf("%d", m.config.MaxItemAgeDays)
+		} else if m.cursor == 30 {
+			// Prune keep - text input
+			m.editingSettings = true
+			m.settingInput = m.config.PruneKeep
+		} else if m.cursor == 31 {
+			// WebSub listen address - text input
+			m.editingSettings = true
+			m.settingInput = m.config.WebSubListen
+		} else if m.cursor == 32 {
+			// WebSub callback URL - text input
+			m.editingSettings = true
+			m.settingInput = m.config.WebSubCallbackURL
+		} else if m.cursor == 33 {
+			// Log max messages - text input
+			m.editingSettings = true
+			m.settingInput = fmt.Sprintf("%d", m.config.LogMaxMessages)
+		} else if m.cursor == 34 {
+			// Log max age - text input
+			m.editingSettings = true
+			m.settingInput = fmt.Sprintf("%d", m.config.LogMaxAgeDays)
+		} else if m.cursor == 35 {
+			// Sync service - text input
+			m.editingSettings = true
+			m.settingInput = m.config.Sync
+		} else if m.cursor == 36 {
+			// Sync URL - text input
+			m.editingSettings = true
+			m.settingInput = m.config.SyncURL
+		} else if m.cursor == 37 {
+			// Sync token - text input
+			m.editingSettings = true
+			m.settingInput = m.config.SyncToken
+		} else if m.cursor == 38 {
+			// RTL display - text input
+			m.editingSettings = true
+			m.settingInput = m.config.RTLDisplay
+		} else if m.cursor == 39 {
+			// Notify - text input
+			m.editingSettings = true
+			m.settingInput = m.config.Notify
+		} else if m.cursor == 40 {
+			// Images - text input
+			m.editingSettings = true
+			m.settingInput = m.config.Images
+		} else if m.cursor == 41 {
+			// Article max width - text input
+			m.editingSettings = true
+			m.settingInput = fmt.Sprintf("%d", m.config.ArticleMaxWidth)
+		} else if m.cursor == 42 {
+			// Item list format - text input
+			m.editingSettings = true
+			m.settingInput = m.config.ItemListFormat
+		} else if m.cursor == 43 {
+			// Date format - text input
+			m.editingSettings = true
+			m.settingInput = m.config.DateFormat
+		} else if m.cursor == 44 {
+			// Dead feed threshold - text input
+			m.editingSettings = true
+			m.settingInput = fmt.Sprintf("%d", m.config.DeadFeedThreshold)
+		} else if m.cursor == 45 {
+			// Host request rate - text input
+			m.editingSettings = true
+			m.settingInput = fmt.Sprintf("%d", m.config.HostRequestRate)
+		} else if m.cursor == 46 {
+			// Host max parallel - text input
+			m.editingSettings = true
+			m.settingInput = fmt.Sprintf("%d", m.config.HostMaxParallel)
+		} else if m.cursor == 47 {
+			// Update redirects - open selector
+			m.selectingUpdateRedirects = true
+			if m.config.UpdateRedirects {
+				m.updateRedirectsSelectCursor = 0
+			} else {
+				m.updateRedirectsSelectCursor = 1
+			}
+		} else if m.cursor == 48 {
+			// Hide duplicates - open selector
+			m.selectingHideDuplicates = true
+			if m.config.HideDuplicates {
+				m.hideDuplicatesSelectCursor = 0
+			} else {
+				m.hideDuplicatesSelectCursor = 1
+			}
+		} else if m.cursor == 49 {
+			// Mark read on open - open selector
+			m.selectingMarkReadOnOpen = true
+			if m.config.MarkReadOnOpen {
+				m.markReadOnOpenSelectCursor = 0
+			} else {
+				m.markReadOnOpenSelectCursor = 1
+			}
+		} else if m.cursor == 50 {
+			// Mark skipped read - open selector
+			m.selectingMarkSkippedRead = true
+			if m.config.MarkSkippedRead {
+				m.markSkippedReadSelectCursor = 0
+			} else {
+				m.markSkippedReadSelectCursor = 1
+			}
+		} else if m.cursor == 51 {
+			// Share commands - text input
+			m.editingSettings = true
+			m.settingInput = m.config.ShareCommands
+		} else if m.cursor == 52 {
+			// Refresh retries - text input
+			m.editingSettings = true
+			m.settingInput = fmt.Sprintf("%d", m.config.RefreshRetries)
+		} else if m.cursor == 53 {
+			// Refresh retry delay - text input
+			m.editingSettings = true
+			m.settingInput = fmt.Sprintf("%d", m.config.RefreshRetryDelay)
+		} else if m.cursor == 54 {
+			// Prune schedule - text input
+			m.editingSettings = true
+			m.settingInput = m.config.PruneSchedule
+		} else if m.cursor == 55 {
+			// Update check schedule - text input
+			m.editingSettings = true
+			m.settingInput = m.config.UpdateCheckSchedule
+		} else if m.cursor == 56 {
+			// Feed icons - text input
+			m.editingSettings = true
+			m.settingInput = m.config.FeedIcons
+		} else if m.cursor == 57 {
+			// Macros - text input
+			m.editingSettings = true
+			m.settingInput = m.config.Macros
+		} else if m.cursor == 58 {
+			// Feed history pages - text input
+			m.editingSettings = true
+			m.settingInput = fmt.Sprintf("%d", m.config.FeedHistoryPages)
+		} else if m.cursor == 59 {
+			// Export directory - text input
+			m.editingSettings = true
+			m.settingInput = m.config.ExportDir
+		} else if m.cursor == 60 {
+			// Export file name - text input
+			m.editingSettings = true
+			m.settingInput = m.config.ExportFileName
+		} else if m.cursor == 61 {
+			// Export PDF command - text input
+			m.editingSettings = true
+			m.settingInput = m.config.ExportPDFCommand
+		} else if m.cursor == 62 {
+			// Key bindings - open the key bindings view to rebind them
+			m.previousState = m.state
+			m.state = KeymapView
+			m.keymapViewScroll = 0
+			m.keymapCursor = 0
+		}
+		return m, nil
+
+	case "P":
+		// Prune old items of every feed now
+		if !m.config.RetentionPolicy().Enabled() {
+			m.statusMessage = "Set Max Items Per Feed or Max Item Age to prune items"
+			m.statusMessageType = "error"
+			return m, nil
+		}
+		if err := m.taskManager.AddTask(tasks.CreateCleanupTask()); err != nil {
+			m.statusMessage = "Failed to start pruning: " + err.Error()
+			m.statusMessageType = "error"
+			return m, nil
 		}
+		m.statusMessage = "Pruning old items..."
+		m.statusMessageType = "info"
 		return m, nil
 	}
 
@@ -3887,9 +6448,14 @@ func (m Model) renderSettingsView() string {
 		b.WriteString("Select Theme:\n")
 		b.WriteString(m.getHelpStyle().Render("Color scheme for the UI"))
 		b.WriteString("\n\n")
-		themeNames := themes.GetThemeNames()
+		themeNames := themes.GetThemeChoices()
 		for i, name := range themeNames {
 			line := name
+			if name == themes.AutoTheme {
+				line += " (light or dark like the terminal)"
+			} else if themes.GetThemeByName(name).Path != "" {
+				line += " (custom)"
+			}
 			line = m.applyHighlight(line, i == m.themeSelectCursor)
 			b.WriteString(line)
 			b.WriteString("\n")
@@ -4071,6 +6637,96 @@ func (m Model) renderSettingsView() string {
 		return b.String()
 	}
 
+	// If selecting cluster stories, show selector
+	if m.selectingClusterStories {
+		b.WriteString("Cluster Stories:\n")
+		b.WriteString(m.getHelpStyle().Render("Group items covering the same story in different feeds"))
+		b.WriteString("\n\n")
+		options := []string{"yes", "no"}
+		for i, option := range options {
+			line := option
+			line = m.applyHighlight(line, i == m.clusterStoriesSelectCursor)
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+
+		b.WriteString(strings.Repeat("\n", m.height-8))
+		b.WriteString(m.getHelpStyle().Render("enter: select | esc: cancel"))
+		return b.String()
+	}
+
+	// If selecting update redirects, show selector
+	if m.selectingUpdateRedirects {
+		b.WriteString("Update Redirected URLs:\n")
+		b.WriteString(m.getHelpStyle().Render("Move feeds that redirect permanently to their new URL in the URLs file"))
+		b.WriteString("\n\n")
+		options := []string{"yes", "no"}
+		for i, option := range options {
+			line := option
+			line = m.applyHighlight(line, i == m.updateRedirectsSelectCursor)
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+
+		b.WriteString(strings.Repeat("\n", m.height-8))
+		b.WriteString(m.getHelpStyle().Render("enter: select | esc: cancel"))
+		return b.String()
+	}
+
+	// If selecting hide duplicates, show selector
+	if m.selectingHideDuplicates {
+		b.WriteString("Hide Duplicates:\n")
+		b.WriteString(m.getHelpStyle().Render("Show articles republished by several feeds once in aggregated lists"))
+		b.WriteString("\n\n")
+		options := []string{"yes", "no"}
+		for i, option := range options {
+			line := option
+			line = m.applyHighlight(line, i == m.hideDuplicatesSelectCursor)
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+
+		b.WriteString(strings.Repeat("\n", m.height-8))
+		b.WriteString(m.getHelpStyle().Render("enter: select | esc: cancel"))
+		return b.String()
+	}
+
+	// If selecting mark read on open, show selector
+	if m.selectingMarkReadOnOpen {
+		b.WriteString("Mark Read On Open:\n")
+		b.WriteString(m.getHelpStyle().Render("Mark items read when their link is opened in the browser from the item list"))
+		b.WriteString("\n\n")
+		options := []string{"yes", "no"}
+		for i, option := range options {
+			line := option
+			line = m.applyHighlight(line, i == m.markReadOnOpenSelectCursor)
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+
+		b.WriteString(strings.Repeat("\n", m.height-8))
+		b.WriteString(m.getHelpStyle().Render("enter: select | esc: cancel"))
+		return b.String()
+	}
+
+	// If selecting mark skipped read, show selector
+	if m.selectingMarkSkippedRead {
+		b.WriteString("Mark Skipped Read:\n")
+		b.WriteString(m.getHelpStyle().Render("Mark unread items the cursor moved past read when leaving the item list"))
+		b.WriteString("\n\n")
+		options := []string{"yes", "no"}
+		for i, option := range options {
+			line := option
+			line = m.applyHighlight(line, i == m.markSkippedReadSelectCursor)
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+
+		b.WriteString(strings.Repeat("\n", m.height-8))
+		b.WriteString(m.getHelpStyle().Render("enter: select | esc: cancel"))
+		return b.String()
+	}
+
 	// If showing settings help, show help text
 	if m.showSettingsHelp {
 		b.WriteString("Settings Help:\n\n")
@@ -4080,12 +6736,64 @@ func (m Model) renderSettingsView() string {
 			"Auto Reload: Enable continuous automatic reloads using reload time",
 			"Suppress First Reload: Skip the first automatic reload after startup",
 			"Reload On Startup: Reload all feeds when the app starts",
-			"Theme: Color scheme for the UI",
+			"Theme: Color scheme for the UI, auto uses the light or dark theme like the terminal's background and follows it when the terminal switches",
 			"Highlight Style: How the selected item is highlighted",
 			"Spinner Type: Animation style for the loading spinner",
 			"Show Read Feeds: Show feeds with no unread items in the list",
 			"Unread On Top: Show feeds with unread items at the top of the feed list",
 			"Check For Updates: Check for new versions when the application starts",
+			"Cluster Stories: Group items covering the same story in All Items and the other combined lists (space to expand)",
+			"Hot Keywords: Comma-separated keywords that rank items higher in the hot items view (H)",
+			"Highlight Keywords: Comma-separated keywords colored in item titles and articles, prefix with [Folder] to limit one to a folder",
+			"Reading Export: Share a reading log of \"starred\" or recently \"read\" items on every auto reload, \"off\" to disable",
+			"Reading Export Path: File the reading log is written to, a .json name writes JSON and anything else HTML",
+			"Reading Export Gist: ID of an existing GitHub gist to publish the reading log to, needs GITHUB_GIST_TOKEN",
+			"HTTP Proxy: Proxy for feed requests, e.g. http://proxy:3128 or socks5://127.0.0.1:1080, empty uses HTTP_PROXY/HTTPS_PROXY",
+			"User Agent: Replaces the default User-Agent sent with feed requests",
+			"Request Headers: Headers sent with every feed request, e.g. \"X-Api-Key: abc; Accept-Language: en\"",
+			"Feed List Layout: \"single\" for one feed per line or \"columns\" to fill wide terminals (left/right to move between columns)",
+			"Pipe Command: Shell command the article is piped to with | in article view, e.g. \"w3m -T text/html\" or \"wl-copy\"",
+			"Pipe Format: \"markdown\" pipes the article as markdown, \"html\" pipes its raw HTML",
+			"Old Item Days: Items published more than this many days ago use the theme's old item color, 0 to disable",
+			"Debug Categories: Comma-separated debug log categories (http, db, ui, tasks, discovery) or \"all\", -debug enables all of them",
+			"Read Later: Service b sends items to: \"wallabag\", \"pocket\", \"instapaper\" or \"webhook\", \"off\" to disable",
+			"Read Later URL: Wallabag instance, e.g. https://app.wallabag.it, or the URL the webhook posts items to as JSON",
+			"Read Later Auth: wallabag client_id:client_secret:username:password, pocket consumer_key:access_token, instapaper username:password, webhook Authorization header",
+			"Max Items Per Feed: Read items beyond the newest this many of a feed are deleted after each refresh, 0 keeps all (P prunes now)",
+			"Max Item Age: Read items published more than this many days ago are deleted after each refresh, 0 keeps all",
+			"Prune Keep: Items pruning never deletes, \"unread\", \"starred\", both comma-separated or \"none\"",
+			"WebSub Listen: Address hubs push feed updates to, e.g. \":8089\", empty to disable - Requires restart",
+			"WebSub Callback URL: Public URL that reaches the listen address, e.g. https://example.com/websub - Requires restart",
+			"Log Max Messages: Older log messages beyond this many are deleted every hour, 0 keeps all",
+			"Log Max Age: Log messages older than this many days are deleted every hour, 0 keeps all",
+			"Sync: Service subscriptions and read and starred state are synced with: \"miniflux\" or \"greader\" (Google Reader API), \"off\" to disable",
+			"Sync URL: Miniflux server, or the Google Reader API, e.g. https://freshrss.example.com/api/greader.php",
+			"Sync Token: Miniflux API key, or username:password for the Google Reader API",
+			"RTL Display: \"reorder\" lays out right-to-left articles, \"terminal\" only aligns them for terminals that reorder text themselves",
+			"Notify: Desktop notification about new items of \"all\" feeds, comma-separated folders or \"off\", !notify in the URLs file per feed",
+			"Images: Draw article images with \"kitty\", \"iterm2\" or \"sixel\" terminal graphics, \"auto\" detects the terminal's, \"off\" shows their alt text",
+			"Article Max Width: Most columns article text is wrapped at, 0 wraps it at the terminal's width",
+			"Item List Format: Item list rows with %D date, %T feed, %a author, %f N when unread and %t title, %-16T pads or cuts to 16 columns, empty for the default",
+			"Date Format: Dates of items, \"relative\" for how long ago like 3h, a Go time layout like \"Jan 2 15:04\", empty for month and day",
+			"Dead Feed Threshold: Failed refreshes in a row after which a feed is shown as dead with 💀, 0 never does",
+			"Host Request Rate: Requests per second refreshes start to the same host, e.g. github.com, 0 for no limit",
+			"Host Max Parallel: Requests to the same host running at once, on top of Reload Concurrency, 0 for no limit",
+			"Update Redirected URLs: Move feeds that redirect permanently (301/308) to their new URL in the URLs file, otherwise D in the feed list does",
+			"Hide Duplicates: Show an article once in All Items, Starred, Hot Items and query feeds when several feeds publish it, by its link or content",
+			"Mark Read On Open: Mark an item read when o opens its link in the browser from the item list, not only when it is read in the article view",
+			"Mark Skipped Read: Mark the unread items the cursor moved past without opening them read when leaving the item list, u undoes it",
+			"Share Commands: Commands S in article view shares with, \"Name: command\" separated by semicolons, e.g. \"Mastodon: toot {url}; Mail: mail -s {title} me@example.com\", {url}, {title} and {author} are replaced",
+			"Refresh Retries: Times a failed feed refresh is tried again, the task list (t) shows when, 0 never retries",
+			"Refresh Retry Delay: Minutes before the first retry of a failed refresh, each later retry waits twice as long",
+			"Prune Schedule: When old items are pruned, an interval like 12h or a cron expression like \"0 3 * * *\", empty turns it off",
+			"Update Check Schedule: When to look for a newer release while Check For Updates is on, like @daily or 6h, empty turns it off",
+			"Feed Icons: off, glyph for a dot in the color of each site's favicon, or nerdfont for Nerd Font icons of known sites in that color",
+			"Macros: Actions , followed by a key runs, \"key: action, action\" separated by semicolons, e.g. \"m: items.open_link, items.toggle_read, global.down\", actions are listed with K and : commands like :sync work too",
+			"Feed History Pages: Older pages a new feed's first refresh fetches when the feed is paginated (rel=\"next\", RFC 5005 archives or JSON Feed next_url), 0 fetches only the feed",
+			"Export Directory: Directory e in the article view saves articles to, ~/ is your home directory",
+			"Export File Name: Name of exported articles, {date}, {feed}, {title} and {author} are filled in and the extension is added",
+			"Export PDF Command: Command converting an article to PDF, {input} is its HTML file and {output} the PDF, e.g. \"pandoc {input} -o {output}\"",
+			"Key Bindings: Enter lists every action, press enter on one and then the new key to rebind it",
 		}
 		for _, line := range help {
 			wrapped := wrapText(line, m.width-4)
@@ -4108,6 +6816,12 @@ func (m Model) renderSettingsView() string {
 	var statusBar string
 	if m.editingSettings {
 		statusBar = m.getHelpStyle().Render("enter: save | esc: cancel")
+	} else if m.statusMessage != "" {
+		if m.statusMessageType == "error" {
+			statusBar = m.getErrorStyle().Render(m.statusMessage)
+		} else {
+			statusBar = lipgloss.NewStyle().Foreground(lipgloss.Color(themes.GetThemeByName(m.themeName()).SelectedItemColor)).Render(m.statusMessage)
+		}
 	} else {
 		viewKeys := GetViewKeys(SettingsView)
 		viewHelp := FormatStatusBar(viewKeys.StatusBar)
@@ -4133,6 +6847,198 @@ func (m Model) renderSettingsView() string {
 	if !m.config.CheckForUpdates {
 		checkForUpdatesStr = "no"
 	}
+	clusterStoriesStr := "yes"
+	if !m.config.ClusterStories {
+		clusterStoriesStr = "no"
+	}
+	hotKeywordsStr := m.config.HotKeywords
+	if hotKeywordsStr == "" {
+		hotKeywordsStr = "(none)"
+	}
+	highlightKeywordsStr := m.config.HighlightKeywords
+	if highlightKeywordsStr == "" {
+		highlightKeywordsStr = "(none)"
+	}
+	readingExportStr := m.config.ReadingExport
+	if readingExportStr == "" {
+		readingExportStr = "off"
+	}
+	readingExportPathStr := m.config.ReadingExportPath
+	if readingExportPathStr == "" {
+		readingExportPathStr = "(none)"
+	}
+	readingExportGistStr := m.config.ReadingExportGist
+	if readingExportGistStr == "" {
+		readingExportGistStr = "(none)"
+	}
+	httpProxyStr := m.config.HTTPProxy
+	if httpProxyStr == "" {
+		httpProxyStr = "(environment)"
+	}
+	userAgentStr := m.config.UserAgent
+	if userAgentStr == "" {
+		userAgentStr = "(default)"
+	}
+	requestHeadersStr := m.config.RequestHeaders
+	if requestHeadersStr == "" {
+		requestHeadersStr = "(none)"
+	}
+	macrosStr := m.config.Macros
+	if macrosStr == "" {
+		macrosStr = "(none)"
+	}
+	shareCommandsStr := m.config.ShareCommands
+	if shareCommandsStr == "" {
+		shareCommandsStr = "(none)"
+	}
+	refreshRetriesStr := fmt.Sprintf("%d", m.config.RefreshRetries)
+	if m.config.RefreshRetries == 0 {
+		refreshRetriesStr = "off"
+	}
+	refreshRetryDelayStr := fmt.Sprintf("%d minutes, doubled for each retry", m.config.RefreshRetryDelay)
+	pruneScheduleStr := m.config.PruneSchedule
+	if pruneScheduleStr == "" {
+		pruneScheduleStr = "off"
+	}
+	themeStr := m.config.ThemeName
+	if themeStr == themes.AutoTheme {
+		themeStr += " (" + m.themeName() + ")"
+	}
+	updateCheckScheduleStr := m.config.UpdateCheckSchedule
+	if updateCheckScheduleStr == "" {
+		updateCheckScheduleStr = "off"
+	}
+	pipeCommandStr := m.config.PipeCommand
+	if pipeCommandStr == "" {
+		pipeCommandStr = "(none)"
+	}
+	oldItemDaysStr := fmt.Sprintf("%d days", m.config.OldItemDays)
+	if m.config.OldItemDays == 0 {
+		oldItemDaysStr = "disabled"
+	}
+	debugCategoriesStr := m.config.DebugCategories
+	if debugCategoriesStr == "" {
+		debugCategoriesStr = "(none)"
+	}
+	readLaterStr := m.config.ReadLater
+	if readLaterStr == "" {
+		readLaterStr = "off"
+	}
+	readLaterURLStr := m.config.ReadLaterURL
+	if readLaterURLStr == "" {
+		readLaterURLStr = "(none)"
+	}
+	// Credentials are never shown
+	readLaterAuthStr := "(none)"
+	if m.config.ReadLaterAuth != "" {
+		readLaterAuthStr = "(set)"
+	}
+	maxItemsPerFeedStr := fmt.Sprintf("%d", m.config.MaxItemsPerFeed)
+	if m.config.MaxItemsPerFeed == 0 {
+		maxItemsPerFeedStr = "unlimited"
+	}
+	maxItemAgeStr := fmt.Sprintf("%d days", m.config.MaxItemAgeDays)
+	if m.config.MaxItemAgeDays == 0 {
+		maxItemAgeStr = "unlimited"
+	}
+	pruneKeepStr := m.config.PruneKeep
+	if pruneKeepStr == "" {
+		pruneKeepStr = "none"
+	}
+	webSubListenStr := m.config.WebSubListen
+	if webSubListenStr == "" {
+		webSubListenStr = "off"
+	}
+	webSubCallbackURLStr := m.config.WebSubCallbackURL
+	if webSubCallbackURLStr == "" {
+		webSubCallbackURLStr = "(none)"
+	}
+	logMaxMessagesStr := fmt.Sprintf("%d", m.config.LogMaxMessages)
+	if m.config.LogMaxMessages == 0 {
+		logMaxMessagesStr = "unlimited"
+	}
+	logMaxAgeStr := fmt.Sprintf("%d days", m.config.LogMaxAgeDays)
+	if m.config.LogMaxAgeDays == 0 {
+		logMaxAgeStr = "unlimited"
+	}
+	syncStr := m.config.Sync
+	if syncStr == "" {
+		syncStr = "off"
+	}
+	keyBindingsStr := "defaults"
+	if changed := m.keymap.changedCount(); changed > 0 {
+		keyBindingsStr = fmt.Sprintf("%d changed", changed)
+	}
+	notifyStr := m.config.Notify
+	if notifyStr == "" {
+		notifyStr = "off"
+	}
+	imagesStr := m.config.Images
+	switch {
+	case imagesStr == "":
+		imagesStr = "off"
+	case m.imageProtocol == termimage.ProtocolNone:
+		imagesStr += " (not supported by this terminal)"
+	case imagesStr == config.ImagesAuto:
+		imagesStr += " (" + string(m.imageProtocol) + ")"
+	}
+	articleMaxWidthStr := fmt.Sprintf("%d columns", m.config.ArticleMaxWidth)
+	if m.config.ArticleMaxWidth == 0 {
+		articleMaxWidthStr = "terminal width"
+	}
+	itemListFormatStr := m.config.ItemListFormat
+	if itemListFormatStr == "" {
+		itemListFormatStr = "(default)"
+	}
+	dateFormatStr := m.config.DateFormat
+	if dateFormatStr == "" {
+		dateFormatStr = "(default)"
+	}
+	deadFeedThresholdStr := fmt.Sprintf("%d failed refreshes", m.config.DeadFeedThreshold)
+	if m.config.DeadFeedThreshold == 0 {
+		deadFeedThresholdStr = "off"
+	}
+	hostRequestRateStr := fmt.Sprintf("%d per second", m.config.HostRequestRate)
+	if m.config.HostRequestRate == 0 {
+		hostRequestRateStr = "no limit"
+	}
+	exportPDFCommandStr := m.config.ExportPDFCommand
+	if exportPDFCommandStr == "" {
+		exportPDFCommandStr = "(none)"
+	}
+	feedHistoryPagesStr := fmt.Sprintf("%d", m.config.FeedHistoryPages)
+	if m.config.FeedHistoryPages == 0 {
+		feedHistoryPagesStr = "off"
+	}
+	hostMaxParallelStr := fmt.Sprintf("%d", m.config.HostMaxParallel)
+	if m.config.HostMaxParallel == 0 {
+		hostMaxParallelStr = "no limit"
+	}
+	updateRedirectsStr := "yes"
+	if !m.config.UpdateRedirects {
+		updateRedirectsStr = "no"
+	}
+	hideDuplicatesStr := "yes"
+	if !m.config.HideDuplicates {
+		hideDuplicatesStr = "no"
+	}
+	markReadOnOpenStr := "yes"
+	if !m.config.MarkReadOnOpen {
+		markReadOnOpenStr = "no"
+	}
+	markSkippedReadStr := "yes"
+	if !m.config.MarkSkippedRead {
+		markSkippedReadStr = "no"
+	}
+	syncURLStr := m.config.SyncURL
+	if syncURLStr == "" {
+		syncURLStr = "(none)"
+	}
+	// The token is never shown
+	syncTokenStr := "(none)"
+	if m.config.SyncToken != "" {
+		syncTokenStr = "(set)"
+	}
 	reloadTimeStr := fmt.Sprintf("%d minutes", m.config.ReloadTime)
 	if m.config.ReloadTime == 0 {
 		reloadTimeStr = "disabled"
@@ -4158,12 +7064,64 @@ func (m Model) renderSettingsView() string {
 		{"Auto Reload", autoReloadStr},
 		{"Suppress First Reload", suppressFirstReloadStr},
 		{"Reload On Startup", reloadOnStartupStr},
-		{"Theme", m.config.ThemeName},
+		{"Theme", themeStr},
 		{"Highlight Style", m.config.HighlightStyle},
 		{"Spinner Type", m.config.SpinnerType},
 		{"Show Read Feeds", showReadFeedsStr},
 		{"Unread On Top", unreadOnTopStr},
 		{"Check For Updates", checkForUpdatesStr},
+		{"Cluster Stories", clusterStoriesStr},
+		{"Hot Keywords", hotKeywordsStr},
+		{"Highlight Keywords", highlightKeywordsStr},
+		{"Reading Export", readingExportStr},
+		{"Reading Export Path", readingExportPathStr},
+		{"Reading Export Gist", readingExportGistStr},
+		{"HTTP Proxy", httpProxyStr},
+		{"User Agent", userAgentStr},
+		{"Request Headers", requestHeadersStr},
+		{"Feed List Layout", m.config.FeedListLayout},
+		{"Pipe Command", pipeCommandStr},
+		{"Pipe Format", m.config.PipeFormat},
+		{"Old Item Days", oldItemDaysStr},
+		{"Debug Categories", debugCategoriesStr},
+		{"Read Later", readLaterStr},
+		{"Read Later URL", readLaterURLStr},
+		{"Read Later Auth", readLaterAuthStr},
+		{"Max Items Per Feed", maxItemsPerFeedStr},
+		{"Max Item Age", maxItemAgeStr},
+		{"Prune Keep", pruneKeepStr},
+		{"WebSub Listen", webSubListenStr},
+		{"WebSub Callback URL", webSubCallbackURLStr},
+		{"Log Max Messages", logMaxMessagesStr},
+		{"Log Max Age", logMaxAgeStr},
+		{"Sync", syncStr},
+		{"Sync URL", syncURLStr},
+		{"Sync Token", syncTokenStr},
+		{"RTL Display", m.config.RTLDisplay},
+		{"Notify", notifyStr},
+		{"Images", imagesStr},
+		{"Article Max Width", articleMaxWidthStr},
+		{"Item List Format", itemListFormatStr},
+		{"Date Format", dateFormatStr},
+		{"Dead Feed Threshold", deadFeedThresholdStr},
+		{"Host Request Rate", hostRequestRateStr},
+		{"Host Max Parallel", hostMaxParallelStr},
+		{"Update Redirected URLs", updateRedirectsStr},
+		{"Hide Duplicates", hideDuplicatesStr},
+		{"Mark Read On Open", markReadOnOpenStr},
+		{"Mark Skipped Read", markSkippedReadStr},
+		{"Share Commands", shareCommandsStr},
+		{"Refresh Retries", refreshRetriesStr},
+		{"Refresh Retry Delay", refreshRetryDelayStr},
+		{"Prune Schedule", pruneScheduleStr},
+		{"Update Check Schedule", updateCheckScheduleStr},
+		{"Feed Icons", m.config.FeedIcons},
+		{"Macros", macrosStr},
+		{"Feed History Pages", feedHistoryPagesStr},
+		{"Export Directory", m.config.ExportDir},
+		{"Export File Name", m.config.ExportFileName},
+		{"Export PDF Command", exportPDFCommandStr},
+		{"Key Bindings", keyBindingsStr},
 	}
 
 	// Render settings
@@ -4201,7 +7159,23 @@ func (m Model) renderSettingsView() string {
 }
 
 func (m Model) handleFeedInfoKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.feedInfoNewURL != "" {
+		return m.confirmFeedURL(msg)
+	}
+
 	switch msg.String() {
+	case "t":
+		return m.startFeedInfoEdit(feedInfoTitle), nil
+
+	case "e":
+		return m.startFeedInfoEdit(feedInfoURL), nil
+
+	case "r":
+		return m.forceRefresh()
+
+	case "l":
+		return m.openLogView(&m.currentFeed)
+
 	case "?":
 		m.previousState = m.state
 		m.state = HelpView
@@ -4299,33 +7273,92 @@ func (m Model) renderFeedInfo() string {
 	}
 	statusBar := m.getHelpStyle().Render(statusBarText)
 
+	reloadIntervalStr := fmt.Sprintf("%d minutes (global reload time)", m.config.ReloadTime)
+	if m.currentFeed.ReloadInterval > 0 {
+		reloadIntervalStr = (time.Duration(m.currentFeed.ReloadInterval) * time.Second).String()
+	}
+	if m.currentFeed.Paused {
+		reloadIntervalStr = "paused (p to resume)"
+	}
+
+	titleStr := m.currentFeed.Title
+	if m.currentFeed.CustomTitle != "" {
+		titleStr += " (custom title, t with an empty title uses the feed's own)"
+	}
+
 	// Format feed information
 	info := []struct {
 		label string
 		value string
 	}{
-		{"URL", m.currentFeed.Url},
-		{"Title", m.currentFeed.Title},
+		{"URL", logging.Redact(m.currentFeed.Url)},
+		{"Title", titleStr},
 		{"Description", m.currentFeed.Description},
 		{"Last Updated", formatNullTime(m.currentFeed.LastUpdated)},
 		{"Created At", formatNullTime(m.currentFeed.CreatedAt)},
 		{"Feed Last Modified", formatNullString(m.currentFeed.LastModified)},
 		{"Feed ETag", formatNullString(m.currentFeed.Etag)},
 		{"Cache Control Max Age", formatNullInt64(m.currentFeed.CacheControlMaxAge)},
+		{"Reload Interval", reloadIntervalStr},
+		{"Next Fetch", m.nextFetchDescription()},
+		{"Last Fetch", lastFetchDescription(m.currentFeed)},
+		{"Items Stored", fmt.Sprintf("%d", m.currentFeedItems)},
+	}
+	if m.currentFeed.UnchangedFetches > 0 {
+		info = append(info, struct {
+			label string
+			value string
+		}{"Unchanged Fetches", fmt.Sprintf("%d (same feed sent again instead of 304 Not Modified)", m.currentFeed.UnchangedFetches)})
+	}
+	if m.currentFeed.ConsecutiveFailures > 0 {
+		failuresStr := fmt.Sprintf("%d in a row", m.currentFeed.ConsecutiveFailures)
+		if threshold := int64(m.config.DeadFeedThreshold); threshold > 0 && m.currentFeed.ConsecutiveFailures >= threshold {
+			failuresStr += " (dead, D in the feed list looks for its new URL)"
+		}
+		info = append(info, struct {
+			label string
+			value string
+		}{"Failed Refreshes", failuresStr})
+	}
+	if m.currentFeed.RetryAfter.Valid && time.Now().Before(m.currentFeed.RetryAfter.Time) {
+		info = append(info, struct {
+			label string
+			value string
+		}{"Cooling Down", "until " + m.currentFeed.RetryAfter.Time.Local().Format("15:04") + " (rate limited, Retry-After)"})
+	}
+	if movedURL := m.feedManager.MovedURL(m.currentFeed.ID); movedURL != "" {
+		info = append(info, struct {
+			label string
+			value string
+		}{"Moved To", movedURL + " (redirects permanently, D in the feed list moves it)"})
+	}
+	if m.currentWebSub != nil {
+		webSubStr := "waiting for the hub to verify"
+		if m.currentWebSub.LeaseExpires.Valid {
+			webSubStr = "subscribed until " + m.currentWebSub.LeaseExpires.Time.Local().Format("2006-01-02 15:04")
+		}
+		info = append(info, struct {
+			label string
+			value string
+		}{"WebSub Hub", m.currentWebSub.Hub + " (" + webSubStr + ")"})
 	}
 
 	for _, item := range info {
 		b.WriteString(fmt.Sprintf("%-23s: %s\n", item.label, item.value))
 	}
 
-	// Calculate padding to push status bar to bottom
+	// Calculate padding to push status bar and prompt to bottom
 	usedLines := len(info) + 3 // +3 for title and spacing
-	padding := m.height - usedLines - 1
+	padding := m.height - usedLines - 2
 	if padding < 0 {
 		padding = 0
 	}
 	b.WriteString(strings.Repeat("\n", padding))
 	b.WriteString(statusBar)
+	if prompt := m.feedInfoPrompt(); prompt != "" {
+		b.WriteString("\n")
+		b.WriteString(m.getHelpStyle().Render(prompt))
+	}
 
 	return b.String()
 }
@@ -4345,7 +7378,7 @@ func (m Model) renderURLsView() string {
 		allLines = append(allLines, "No URLs found.")
 	} else {
 		for _, entry := range m.urlsList {
-			line := entry.URL
+			line := logging.Redact(entry.URL)
 			if len(entry.Folders) > 0 {
 				line += " " + strings.Join(entry.Folders, ",")
 			}
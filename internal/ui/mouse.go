@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"regexp"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// listHeaderLines is the title and the empty line above the feed and item
+// lists and the article
+const listHeaderLines = 2
+
+// articleWheelLines is how far the wheel scrolls an article per step
+const articleWheelLines = 3
+
+// linkMarkerPattern matches the [N] markers of links in articles and the
+// numbered list of links below them
+var linkMarkerPattern = regexp.MustCompile(`\[(\d+)\]`)
+
+// handleMouse scrolls with the wheel and selects with the left button,
+// clicking the selected feed or item opens it like enter
+func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	// Text being typed and keys being bound are left alone
+	if m.addingURL || m.editingSettings || m.commandMode || m.editingFolders || m.addingRule || m.articleSearching || m.capturingKey || m.editingFeedInfo != "" || m.logSearching || m.exportingArticle {
+		return m, nil
+	}
+	if msg.Action != tea.MouseActionPress {
+		return m, nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp, tea.MouseButtonWheelDown:
+		if m.state == ArticleView {
+			if msg.Button == tea.MouseButtonWheelUp {
+				m.articleViewScroll = max(m.articleViewScroll-articleWheelLines, 0)
+			} else {
+				m.articleViewScroll = min(m.articleViewScroll+articleWheelLines, m.articleMaxScroll())
+			}
+			return m, nil
+		}
+		key := tea.KeyMsg{Type: tea.KeyDown}
+		if msg.Button == tea.MouseButtonWheelUp {
+			key = tea.KeyMsg{Type: tea.KeyUp}
+		}
+		return m.handleKeyPress(key)
+
+	case tea.MouseButtonLeft:
+		switch m.state {
+		case FeedListView:
+			return m.clickFeed(msg.X, msg.Y)
+		case ItemListView:
+			return m.clickItem(msg.Y)
+		case ArticleView:
+			return m, m.clickArticleLink(msg.X, msg.Y)
+		}
+	}
+	return m, nil
+}
+
+// clickFeed selects the feed or folder at the clicked cell
+func (m Model) clickFeed(x, y int) (tea.Model, tea.Cmd) {
+	row := y - listHeaderLines
+	rows, cols := m.feedColumnLayout()
+	if len(m.feedList) == 0 || row < 0 || row >= rows {
+		return m, nil
+	}
+
+	var i int
+	if cols > 1 {
+		col := x / (m.feedColumnWidth() + feedColumnGap)
+		if col >= cols {
+			return m, nil
+		}
+		i = (m.cursor/(rows*cols))*rows*cols + col*rows + row
+	} else {
+		start, _ := listWindow(m.cursor, len(m.feedList), rows)
+		i = start + row
+	}
+	if i >= len(m.feedList) {
+		return m, nil
+	}
+
+	if i == m.cursor {
+		return m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+	m.statusMessage = ""
+	m.statusMessageType = ""
+	m.cursor = i
+	m.savedFeedCursor = i
+	return m, nil
+}
+
+// clickItem selects the item at the clicked line
+func (m Model) clickItem(y int) (tea.Model, tea.Cmd) {
+	row := y - listHeaderLines
+	rows := m.itemListRows()
+	linesPerItem := 1
+	if m.searchMatches != nil {
+		linesPerItem = 2
+	}
+	if len(m.itemList) == 0 || row < 0 || row/linesPerItem >= rows {
+		return m, nil
+	}
+
+	start, _ := listWindow(m.cursor, len(m.itemList), rows)
+	i := start + row/linesPerItem
+	if i >= len(m.itemList) {
+		return m, nil
+	}
+
+	if i == m.cursor {
+		return m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+	// Items clicked past were skipped like with j
+	for skipped := m.cursor; skipped < i; skipped++ {
+		m.markSkipped(m.itemList[skipped])
+	}
+	m.cursor = i
+	m.savedItemCursor = i
+	m.itemTitleScrollOffset = 0
+	return m, nil
+}
+
+// clickArticleLink opens the link whose [N] marker was clicked, or the first
+// one after the click on that line so clicking a link's text opens it too
+func (m Model) clickArticleLink(x, y int) tea.Cmd {
+	line := m.articleViewScroll + y - listHeaderLines
+	if !m.articleRendered() || y < listHeaderLines || line >= len(m.article.lines) {
+		return nil
+	}
+
+	text := ansi.Strip(m.article.lines[line])
+	for _, match := range linkMarkerPattern.FindAllStringSubmatchIndex(text, -1) {
+		if ansi.StringWidth(text[:match[1]]) <= x {
+			continue
+		}
+		n, err := strconv.Atoi(text[match[2]:match[3]])
+		if err != nil || n < 1 || n > len(m.links) {
+			return nil
+		}
+		return openLink(m.links[n-1])
+	}
+	return nil
+}
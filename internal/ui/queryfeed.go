@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"database/sql"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/filter"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// queryFeedIDBase is the virtual feed ID of the first query feed, the ones
+// after it count down
+const queryFeedIDBase int64 = -100
+
+// queryFeed is a query feed from the URLs file, a virtual feed of the items
+// of all feeds matching a filter expression
+type queryFeed struct {
+	title  string
+	filter *filter.Filter // nil when the expression doesn't parse
+	err    error
+	counts queryFeedCount
+}
+
+type queryFeedCount struct {
+	UnreadItems, TotalItems int64
+}
+
+// QueryFeedCountsMsg carries the number of items matching each query feed
+type QueryFeedCountsMsg struct {
+	Counts []queryFeedCount
+}
+
+func queryFeedID(index int) int64 {
+	return queryFeedIDBase - int64(index)
+}
+
+// isQueryFeed reports whether a feed ID refers to a query feed
+func isQueryFeed(feedID int64) bool {
+	return feedID <= queryFeedIDBase
+}
+
+// queryFeedFor returns the query feed of a virtual feed ID, or nil
+func (m Model) queryFeedFor(feedID int64) *queryFeed {
+	index := int(queryFeedIDBase - feedID)
+	if !isQueryFeed(feedID) || index >= len(m.queryFeeds) {
+		return nil
+	}
+	return &m.queryFeeds[index]
+}
+
+// SetQueryFeeds sets the query feeds read from the URLs file
+func (m *Model) SetQueryFeeds(definitions []config.QueryFeed) {
+	m.queryFeeds = parseQueryFeeds(definitions)
+}
+
+// parseQueryFeeds parses the filter of each query feed with the fields of
+// item filters, a query feed whose filter has an error is shown with it
+func parseQueryFeeds(definitions []config.QueryFeed) []queryFeed {
+	parsed := make([]queryFeed, len(definitions))
+	for i, definition := range definitions {
+		parsed[i].title = definition.Title
+		parsed[i].filter, parsed[i].err = filter.Parse(definition.Filter, itemFilterFields)
+		if parsed[i].err != nil {
+			logging.Warn("Invalid query feed", "title", definition.Title, "error", parsed[i].err)
+		}
+	}
+	return parsed
+}
+
+// queryFeedListItems returns the feed list entries of the query feeds
+func (m Model) queryFeedListItems() []FeedListItem {
+	items := make([]FeedListItem, len(m.queryFeeds))
+	for i, query := range m.queryFeeds {
+		feed := &database.GetFeedStatsRow{
+			ID:          queryFeedID(i),
+			Title:       "⌕ " + query.title,
+			TotalItems:  query.counts.TotalItems,
+			UnreadItems: query.counts.UnreadItems,
+		}
+		if query.err != nil {
+			feed.LastError = sql.NullString{String: query.err.Error(), Valid: true}
+		}
+		items[i] = FeedListItem{
+			Feed:        feed,
+			UnreadItems: query.counts.UnreadItems,
+			TotalItems:  query.counts.TotalItems,
+		}
+	}
+	return items
+}
+
+// countQueryFeeds counts the items matching each query feed. Item content is
+// only loaded when a filter looks at it.
+func (m Model) countQueryFeeds() tea.Cmd {
+	if len(m.queryFeeds) == 0 {
+		return nil
+	}
+	needContent := false
+	for _, query := range m.queryFeeds {
+		if query.filter != nil && query.filter.Uses("content") {
+			needContent = true
+		}
+	}
+
+	return func() tea.Msg {
+		load := m.feedManager.GetAllItemHeaders
+		if needContent {
+			load = m.feedManager.GetAllItemsWithReadStatus
+		}
+		items, err := load()
+		if err != nil {
+			logging.Error("countQueryFeeds failed", "error", err)
+			return ErrorMsg{Err: err}
+		}
+
+		now := time.Now()
+		counts := make([]queryFeedCount, len(m.queryFeeds))
+		for _, item := range items {
+			record := m.itemRecord(item, now)
+			for i, query := range m.queryFeeds {
+				if query.filter == nil || !query.filter.Match(record) {
+					continue
+				}
+				counts[i].TotalItems++
+				if !item.Read {
+					counts[i].UnreadItems++
+				}
+			}
+		}
+		return QueryFeedCountsMsg{Counts: counts}
+	}
+}
+
+// setQueryFeedCounts stores the counts and updates the query feeds shown in
+// the feed list
+func (m *Model) setQueryFeedCounts(counts []queryFeedCount) {
+	if len(counts) != len(m.queryFeeds) {
+		// The URLs file was reloaded while counting
+		return
+	}
+	queries := make([]queryFeed, len(m.queryFeeds))
+	copy(queries, m.queryFeeds)
+	for i := range queries {
+		queries[i].counts = counts[i]
+	}
+	m.queryFeeds = queries
+
+	entries := m.queryFeedListItems()
+	for i, item := range m.feedList {
+		if !item.IsFolder && isQueryFeed(item.Feed.ID) {
+			if index := int(queryFeedIDBase - item.Feed.ID); index < len(entries) {
+				m.feedList[i] = entries[index]
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package ui
+
+import "fmt"
+
+// readOnlyBlocked are the actions that change the database or the URLs file,
+// they are turned off while another NewsGoat is running
+var readOnlyBlocked = map[string]bool{
+	"feeds.refresh":         true,
+	"feeds.refresh_all":     true,
+	"feeds.mark_all_read":   true,
+	"feeds.pause":           true,
+	"feeds.folders":         true,
+	"feeds.rediscover":      true,
+	"feeds.add_url":         true,
+	"feeds.edit_urls":       true,
+	"feeds.reload_urls":     true,
+	"items.refresh":         true,
+	"items.mark_all_read":   true,
+	"items.toggle_read":     true,
+	"items.mark_read_next":  true,
+	"items.mark_above_read": true,
+	"items.star":            true,
+	"items.read_later":      true,
+	"article.full_text":     true,
+	"article.star":          true,
+	"article.read_later":    true,
+	"logs.clear":            true,
+}
+
+// SetReadOnly opens the UI without refreshing feeds or changing anything,
+// the NewsGoat with the given pid already does that
+func (m *Model) SetReadOnly(pid int) {
+	m.readOnly = true
+	m.readOnlyPID = pid
+	m.reloadTimerRunning = false
+}
+
+// readOnlyAction reports whether key, already translated by the keymap, is
+// bound to an action that is turned off in read-only mode
+func (m Model) readOnlyAction(key string) bool {
+	if !m.readOnly {
+		return false
+	}
+	scope := scopeForView(m.state)
+	for _, action := range Actions {
+		if action.Scope != scope || !readOnlyBlocked[action.Name] {
+			continue
+		}
+		for _, def := range action.Defaults {
+			if def == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readOnlyMessage explains why an action did nothing
+func (m Model) readOnlyMessage() string {
+	if m.readOnlyPID == 0 {
+		return "Read-only, started with -readOnly"
+	}
+	return fmt.Sprintf("Read-only, newsgoat (pid %d) is already running and refreshes the feeds", m.readOnlyPID)
+}
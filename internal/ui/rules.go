@@ -0,0 +1,216 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/logging"
+	"github.com/jarv/newsgoat/internal/rules"
+)
+
+// RulesLoadedMsg carries the item rules of every feed
+type RulesLoadedMsg struct {
+	Rules []rules.Rule
+}
+
+// RuleChangedMsg reports a rule being added or deleted
+type RuleChangedMsg struct {
+	Status string
+	Err    error
+}
+
+func loadRules(feedManager *feeds.Manager) tea.Cmd {
+	return func() tea.Msg {
+		loaded, err := feedManager.GetItemRules()
+		if err != nil {
+			logging.Error("loadRules failed", "error", err)
+			return ErrorMsg{Err: err}
+		}
+		return RulesLoadedMsg{Rules: loaded}
+	}
+}
+
+func addRule(feedManager *feeds.Manager, rule rules.Rule) tea.Cmd {
+	return func() tea.Msg {
+		if _, err := feedManager.AddItemRule(rule); err != nil {
+			logging.Error("addRule failed", "rule", rule.String(), "error", err)
+			return RuleChangedMsg{Err: err}
+		}
+		status := "Added " + rule.String() + ", it applies from the next refresh"
+		if rule.Action == rules.Highlight {
+			status = "Added " + rule.String()
+		}
+		return RuleChangedMsg{Status: status}
+	}
+}
+
+func deleteRule(feedManager *feeds.Manager, rule rules.Rule) tea.Cmd {
+	return func() tea.Msg {
+		if err := feedManager.DeleteItemRule(rule.ID); err != nil {
+			logging.Error("deleteRule failed", "ruleID", rule.ID, "error", err)
+			return RuleChangedMsg{Err: err}
+		}
+		return RuleChangedMsg{Status: "Deleted " + rule.String()}
+	}
+}
+
+// itemHighlight returns the highlight rule matching an item of the item list
+func (m Model) itemHighlight(item database.GetItemsWithReadStatusRow) (rules.Rule, bool) {
+	return rules.HighlightFor(m.rules, item.FeedID, rules.Item{Title: item.Title, Author: item.Author, Link: item.Link})
+}
+
+// openRulesView lists the item rules, rules added with f apply to the feed
+// at the cursor
+func (m Model) openRulesView() (Model, tea.Cmd) {
+	m.rulesFeedID = 0
+	if m.cursor < len(m.feedList) && !m.feedList[m.cursor].IsFolder && !isVirtualFeed(m.feedList[m.cursor].Feed.ID) {
+		m.rulesFeedID = m.feedList[m.cursor].Feed.ID
+	}
+	m.previousState = m.state
+	m.state = RulesView
+	m.rulesCursor = 0
+	m.rulesStatus = ""
+	return m, loadRules(m.feedManager)
+}
+
+// handleRuleInputKeys edits the rule being added
+func (m Model) handleRuleInputKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.addingRule = false
+		m.ruleInput = ""
+		return m, nil
+	case "enter":
+		m.addingRule = false
+		input := m.ruleInput
+		m.ruleInput = ""
+		rule, err := rules.Parse(input)
+		if err != nil {
+			m.rulesStatus = err.Error()
+			return m, nil
+		}
+		rule.FeedID = m.ruleFeedID
+		return m, addRule(m.feedManager, rule)
+	case "backspace":
+		if len(m.ruleInput) > 0 {
+			runes := []rune(m.ruleInput)
+			m.ruleInput = string(runes[:len(runes)-1])
+		}
+		return m, nil
+	}
+	switch msg.Type {
+	case tea.KeySpace:
+		m.ruleInput += " "
+	case tea.KeyRunes:
+		m.ruleInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+func (m Model) handleRulesViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc", "ctrl+c":
+		m.state = m.previousState
+		return m, nil
+
+	case "j", "down":
+		if m.rulesCursor < len(m.rules)-1 {
+			m.rulesCursor++
+		}
+
+	case "k", "up":
+		if m.rulesCursor > 0 {
+			m.rulesCursor--
+		}
+
+	case "a", "f":
+		if m.readOnly {
+			m.rulesStatus = m.readOnlyMessage()
+			return m, nil
+		}
+		m.ruleFeedID = 0
+		if msg.String() == "f" {
+			if m.rulesFeedID == 0 {
+				m.rulesStatus = "Open the rules on a feed to add a rule for it"
+				return m, nil
+			}
+			m.ruleFeedID = m.rulesFeedID
+		}
+		m.addingRule = true
+		m.ruleInput = ""
+		m.rulesStatus = ""
+
+	case "d":
+		if m.readOnly {
+			m.rulesStatus = m.readOnlyMessage()
+			return m, nil
+		}
+		if m.rulesCursor < len(m.rules) {
+			return m, deleteRule(m.feedManager, m.rules[m.rulesCursor])
+		}
+	}
+	return m, nil
+}
+
+// ruleFeedName names the feed a rule applies to
+func (m Model) ruleFeedName(feedID int64) string {
+	if feedID == 0 {
+		return "all feeds"
+	}
+	if title := m.feedTitle(feedID); title != "" {
+		return title
+	}
+	return fmt.Sprintf("feed %d", feedID)
+}
+
+func (m Model) renderRulesView() string {
+	var allLines []string
+	for i, rule := range m.rules {
+		line := fmt.Sprintf("%-30s %s", ansi.Truncate(m.ruleFeedName(rule.FeedID), 30, "…"), rule.String())
+		allLines = append(allLines, m.applyHighlight(line, i == m.rulesCursor))
+	}
+	if len(allLines) == 0 {
+		allLines = []string{"No rules, press a to add one."}
+	}
+
+	// Reserve space for: title (1), empty line (1), status bar (1), prompt (1) = 4 lines
+	availableHeight := max(m.height-4, 3)
+	start := 0
+	if m.rulesCursor >= availableHeight {
+		start = m.rulesCursor - availableHeight + 1
+	}
+	end := min(start+availableHeight, len(allLines))
+	visibleLines := allLines[start:end]
+
+	var b strings.Builder
+	b.WriteString(m.getTitleStyle().Render("🐐 NewsGoat - Item Rules"))
+	b.WriteString("\n\n")
+
+	for _, line := range visibleLines {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	// Calculate padding to push status bar to bottom
+	usedLines := 2 + len(visibleLines)
+	padding := max(m.height-usedLines-2, 0)
+	b.WriteString(strings.Repeat("\n", padding))
+	help := "a: add rule for all feeds | d: delete | esc: return"
+	if m.rulesFeedID != 0 {
+		help = "a: add rule for all feeds | f: add rule for " + m.ruleFeedName(m.rulesFeedID) + " | d: delete | esc: return"
+	}
+	b.WriteString(m.getHelpStyle().Render(help))
+	b.WriteString("\n")
+
+	switch {
+	case m.addingRule:
+		b.WriteString(m.getHelpStyle().Render(fmt.Sprintf("Rule for %s (hide|read|highlight[=color,marker,notify] title|author|link pattern): %s", m.ruleFeedName(m.ruleFeedID), m.ruleInput)))
+	case m.rulesStatus != "":
+		b.WriteString(m.getHelpStyle().Render(m.rulesStatus))
+	}
+	return b.String()
+}
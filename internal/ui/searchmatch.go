@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jarv/newsgoat/internal/database"
+	"golang.org/x/net/html"
+)
+
+const (
+	// snippetLead is how much text is kept in front of the first match in a
+	// search snippet
+	snippetLead = 30
+
+	// snippetLength is the most text a search snippet keeps, the renderer cuts
+	// it to the width of the terminal
+	snippetLength = 200
+)
+
+// SearchMatch is where a global search matched an item. Ranges are rune
+// offsets, start inclusive and end exclusive.
+type SearchMatch struct {
+	TitleRanges   [][2]int
+	Snippet       string // One line of body text around the first match, empty when only the title matched
+	SnippetRanges [][2]int
+}
+
+// findSearchMatches returns where the words of query appear in the title and
+// body of each item, ignoring case and accents like the search does
+func findSearchMatches(items []database.SearchItemsByTitleRow, query string) map[int64]SearchMatch {
+	words := strings.Fields(database.FoldText(query))
+	if len(words) == 0 {
+		return nil
+	}
+
+	matches := make(map[int64]SearchMatch, len(items))
+	for _, item := range items {
+		match := SearchMatch{TitleRanges: matchRanges(item.Title, words)}
+		body := strings.Join(strings.Fields(htmlText(itemContent(database.GetItemsWithReadStatusRow(item)))), " ")
+		if ranges := matchRanges(body, words); len(ranges) > 0 {
+			match.Snippet, match.SnippetRanges = snippetAround(body, ranges)
+		}
+		matches[item.ID] = match
+	}
+	return matches
+}
+
+// matchRanges finds every occurrence of the folded words in text. Each rune is
+// folded on its own so the offsets point into the original text, even when
+// folding turns one rune into two like ß into ss.
+func matchRanges(text string, words []string) [][2]int {
+	var folded []rune
+	var origin []int // origin[i] is the rune of text that folded[i] came from
+	for i, r := range []rune(text) {
+		f := []rune(database.FoldText(string(r)))
+		if len(f) == 0 {
+			f = []rune{r}
+		}
+		folded = append(folded, f...)
+		for range f {
+			origin = append(origin, i)
+		}
+	}
+
+	matchEnd := make(map[int]int)
+	for _, word := range words {
+		needle := []rune(word)
+		for i := 0; i+len(needle) <= len(folded); i++ {
+			if hasRunePrefix(folded[i:], needle) {
+				start, end := origin[i], origin[i+len(needle)-1]+1
+				matchEnd[start] = max(matchEnd[start], end)
+			}
+		}
+	}
+
+	// Merge overlapping matches, in order
+	var ranges [][2]int
+	for i := 0; i < len([]rune(text)); i++ {
+		end, ok := matchEnd[i]
+		if !ok {
+			continue
+		}
+		if n := len(ranges); n > 0 && i <= ranges[n-1][1] {
+			ranges[n-1][1] = max(ranges[n-1][1], end)
+			continue
+		}
+		ranges = append(ranges, [2]int{i, end})
+	}
+	return ranges
+}
+
+// snippetAround cuts a window of text starting a little before the first
+// match, and moves the ranges inside it
+func snippetAround(text string, ranges [][2]int) (string, [][2]int) {
+	runes := []rune(text)
+	start := max(ranges[0][0]-snippetLead, 0)
+	// Start on a word
+	for i := start; start > 0 && i < ranges[0][0]; i++ {
+		if runes[i] == ' ' {
+			start = i + 1
+			break
+		}
+	}
+	end := min(start+snippetLength, len(runes))
+
+	snippet := string(runes[start:end])
+	shift := start
+	if start > 0 {
+		snippet = "…" + snippet
+		shift--
+	}
+
+	var moved [][2]int
+	for _, r := range ranges {
+		if r[0] >= end {
+			break
+		}
+		moved = append(moved, [2]int{r[0] - shift, min(r[1], end) - shift})
+	}
+	return snippet, moved
+}
+
+// htmlText returns the text of an HTML fragment without its markup
+func htmlText(fragment string) string {
+	var b strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(fragment))
+	skip := 0 // Inside <script> or <style>
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return b.String()
+		case html.TextToken:
+			if skip == 0 {
+				b.Write(tokenizer.Text())
+			}
+		case html.StartTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "script", "style":
+				skip++
+			case "br", "p", "div", "li", "h1", "h2", "h3", "h4", "h5", "h6":
+				b.WriteByte(' ')
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "script", "style":
+				skip = max(skip-1, 0)
+			case "p", "div", "li", "h1", "h2", "h3", "h4", "h5", "h6":
+				b.WriteByte(' ')
+			}
+		case html.SelfClosingTagToken:
+			b.WriteByte(' ')
+		}
+	}
+}
+
+// getSearchMatchStyle returns the style of the text a search matched
+func (m Model) getSearchMatchStyle() lipgloss.Style {
+	return m.getKeywordStyle().Underline(true)
+}
+
+// renderSearchSnippet renders the snippet line shown under a global search
+// result, cut to the width of the terminal
+func (m Model) renderSearchSnippet(match SearchMatch) string {
+	const indent = "      " // Lines the snippet up with the titles, after the date
+	snippet := []rune(match.Snippet)
+	if width := m.width - len(indent); m.width > 0 && len(snippet) > width {
+		snippet = append(snippet[:max(width-1, 0)], '…')
+	}
+	line := m.getHelpStyle().Render(string(snippet))
+	return indent + highlightRanges(line, match.SnippetRanges, m.getSearchMatchStyle())
+}
@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// ArticleSharedMsg reports a share command of the article view finishing
+type ArticleSharedMsg struct {
+	ItemID int64
+	Name   string
+	Err    error
+}
+
+// shareArticle runs a share command with the placeholders replaced by the
+// article's link, title and author. NewsGoat is suspended while it runs, so
+// commands can ask for input.
+func shareArticle(item database.GetItemsWithReadStatusRow, action config.ShareAction) tea.Cmd {
+	command := config.ExpandShareCommand(action.Command, map[string]string{
+		"url":    item.Link,
+		"title":  item.Title,
+		"author": item.Author,
+	})
+
+	shell := "sh"
+	args := []string{"-c", command}
+	if runtime.GOOS == "windows" {
+		shell = "cmd"
+		args = []string{"/C", command}
+	}
+
+	c := exec.Command(shell, args...)
+	c.Env = append(os.Environ(), "NEWSGOAT_TITLE="+item.Title, "NEWSGOAT_URL="+item.Link)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		if err != nil {
+			logging.Error("shareArticle: share command failed", "name", action.Name, "error", err)
+		}
+		return ArticleSharedMsg{ItemID: item.ID, Name: action.Name, Err: err}
+	})
+}
+
+// openShareMenu lists the share commands for the shown article, the status
+// line says why when there are none
+func (m Model) openShareMenu() Model {
+	actions, err := config.ParseShareActions(m.config.ShareCommands)
+	switch {
+	case err != nil:
+		m.fullTextStatus = "Share Commands setting: " + err.Error()
+	case len(actions) == 0:
+		m.fullTextStatus = "No share commands set, add them in settings (c)"
+	default:
+		m.previousState = m.state
+		m.state = ShareView
+		m.shareCursor = 0
+	}
+	return m
+}
+
+func (m Model) handleShareViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	actions, _ := config.ParseShareActions(m.config.ShareCommands)
+	switch msg.String() {
+	case "q", "esc", "ctrl+c":
+		m.state = m.previousState
+		return m, nil
+
+	case "j", "down":
+		if m.shareCursor < len(actions)-1 {
+			m.shareCursor++
+		}
+
+	case "k", "up":
+		if m.shareCursor > 0 {
+			m.shareCursor--
+		}
+
+	case "enter":
+		if m.shareCursor < len(actions) {
+			m.state = m.previousState
+			return m, shareArticle(m.currentItem, actions[m.shareCursor])
+		}
+
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if n := int(msg.String()[0] - '1'); n < len(actions) {
+			m.state = m.previousState
+			return m, shareArticle(m.currentItem, actions[n])
+		}
+	}
+	return m, nil
+}
+
+func (m Model) renderShareView() string {
+	actions, _ := config.ParseShareActions(m.config.ShareCommands)
+
+	var b strings.Builder
+	b.WriteString(m.getTitleStyle().Render("🐐 NewsGoat - Share"))
+	b.WriteString(" - ")
+	b.WriteString(m.getHelpStyle().Render(m.bidiTitle(m.currentItem.Title)))
+	b.WriteString("\n\n")
+
+	for i, action := range actions {
+		line := fmt.Sprintf("[%d] %-20s %s", i+1, action.Name, action.Command)
+		if i >= 9 {
+			line = fmt.Sprintf("    %-20s %s", action.Name, action.Command)
+		}
+		b.WriteString(m.applyHighlight(line, i == m.shareCursor))
+		b.WriteString("\n")
+	}
+
+	// Calculate padding to push status bar to bottom
+	usedLines := 2 + len(actions)
+	padding := max(m.height-usedLines-1, 0)
+	b.WriteString(strings.Repeat("\n", padding))
+	b.WriteString(m.getHelpStyle().Render("enter/1-9: share the article | esc: return"))
+
+	return b.String()
+}
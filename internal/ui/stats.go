@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// statsBarWidth is the most cells a bar of the stats view takes
+const statsBarWidth = 40
+
+// StatsLoadedMsg carries the reading statistics
+type StatsLoadedMsg struct {
+	Stats *feeds.ReadingStats
+	Err   error
+}
+
+func loadStats(feedManager *feeds.Manager) tea.Cmd {
+	return func() tea.Msg {
+		stats, err := feedManager.ReadingStats(time.Now())
+		if err != nil {
+			logging.Error("loadStats failed", "error", err)
+			return StatsLoadedMsg{Err: err}
+		}
+		return StatsLoadedMsg{Stats: &stats}
+	}
+}
+
+// openStatsView shows the reading habits and the activity of the feeds
+func (m Model) openStatsView() (Model, tea.Cmd) {
+	m.previousState = m.state
+	m.state = StatsView
+	m.stats = nil
+	m.statsErr = nil
+	m.statsScroll = 0
+	return m, loadStats(m.feedManager)
+}
+
+func (m Model) handleStatsViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	maxScroll := max(len(m.statsLines())-m.statsHeight(), 0)
+	switch msg.String() {
+	case "q", "esc", "ctrl+c":
+		m.state = m.previousState
+		return m, nil
+
+	case "j", "down":
+		m.statsScroll = min(m.statsScroll+1, maxScroll)
+
+	case "k", "up":
+		m.statsScroll = max(m.statsScroll-1, 0)
+
+	case "ctrl+d":
+		m.statsScroll = min(m.statsScroll+max(m.height/2, 1), maxScroll)
+
+	case "ctrl+u":
+		m.statsScroll = max(m.statsScroll-max(m.height/2, 1), 0)
+
+	case "r":
+		return m.openStatsView()
+	}
+	return m, nil
+}
+
+// statsHeight is how many lines of statistics fit between the title and the
+// status bar
+func (m Model) statsHeight() int {
+	return max(m.height-3, 3)
+}
+
+// statsLines lays out the reading statistics, one section after the other
+func (m Model) statsLines() []string {
+	if m.statsErr != nil {
+		return []string{"Failed to load the statistics: " + m.statsErr.Error()}
+	}
+	if m.stats == nil {
+		return []string{"Loading..."}
+	}
+	stats := m.stats
+
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Items read per day, last %d days", feeds.StatsDays))
+	lines = append(lines, m.statsBars(stats.ReadsPerDay, "Mon 01-02")...)
+	lines = append(lines, "", fmt.Sprintf("Items read per week, last %d weeks", feeds.StatsWeeks))
+	lines = append(lines, m.statsBars(stats.ReadsPerWeek, "week of 01-02")...)
+	lines = append(lines, "", "Unread backlog at the end of the day")
+	lines = append(lines, m.statsBars(stats.Backlog, "Mon 01-02")...)
+
+	lines = append(lines, "", fmt.Sprintf("Busiest feeds, last %d days", feeds.StatsActivityDays))
+	if len(stats.BusiestFeeds) == 0 {
+		lines = append(lines, "  No new items")
+	}
+	titleWidth := max(min(m.width-30, 40), 10)
+	for _, feed := range stats.BusiestFeeds {
+		title := ansi.Truncate(feed.Title, titleWidth, "…")
+		lines = append(lines, fmt.Sprintf("  %s%s %5d new %5d read", title, strings.Repeat(" ", max(titleWidth-ansi.StringWidth(title), 0)), feed.NewItems, feed.Reads))
+	}
+
+	lines = append(lines, "", fmt.Sprintf("Average article age when read, last %d days", feeds.StatsActivityDays))
+	if stats.AgedReads == 0 {
+		lines = append(lines, "  No articles read")
+	} else {
+		lines = append(lines, fmt.Sprintf("  %s, over %d articles", formatArticleAge(stats.AverageAge), stats.AgedReads))
+	}
+	return lines
+}
+
+// statsBars draws a bar per period, scaled to the largest count
+func (m Model) statsBars(counts []feeds.PeriodCount, layout string) []string {
+	most := 0
+	for _, count := range counts {
+		most = max(most, count.Count)
+	}
+	width := max(min(m.width-24, statsBarWidth), 1)
+	lines := make([]string, 0, len(counts))
+	for _, count := range counts {
+		bar := ""
+		if most > 0 {
+			bar = strings.Repeat("█", count.Count*width/most)
+		}
+		lines = append(lines, fmt.Sprintf("  %-13s %s %d", count.Start.Format(layout), bar, count.Count))
+	}
+	return lines
+}
+
+// formatArticleAge shows how old an article was in days and hours, or
+// minutes under an hour
+func formatArticleAge(age time.Duration) string {
+	days := int(age / (24 * time.Hour))
+	hours := int(age % (24 * time.Hour) / time.Hour)
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, int(age%time.Hour/time.Minute))
+	}
+	return fmt.Sprintf("%dm", int(age/time.Minute))
+}
+
+func (m Model) renderStatsView() string {
+	lines := m.statsLines()
+	height := m.statsHeight()
+	start := min(m.statsScroll, max(len(lines)-height, 0))
+	end := min(start+height, len(lines))
+	visibleLines := lines[start:end]
+
+	var b strings.Builder
+	b.WriteString(m.getTitleStyle().Render("🐐 NewsGoat - Reading Stats"))
+	b.WriteString("\n\n")
+
+	for _, line := range visibleLines {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	// Calculate padding to push status bar to bottom
+	usedLines := 2 + len(visibleLines)
+	padding := max(m.height-usedLines-1, 0)
+	b.WriteString(strings.Repeat("\n", padding))
+	b.WriteString(m.getHelpStyle().Render("j/k: scroll | r: reload | esc: return"))
+	return b.String()
+}
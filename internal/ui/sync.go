@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jarv/newsgoat/internal/logging"
+	feedsync "github.com/jarv/newsgoat/internal/sync"
+	"github.com/jarv/newsgoat/internal/tasks"
+)
+
+// syncInterval is how often the sync service is synced with
+const syncInterval = 15 * time.Minute
+
+// SyncTimerMsg queues a sync with the sync service
+type SyncTimerMsg struct{}
+
+func waitForSyncTimer() tea.Cmd {
+	return tea.Tick(syncInterval, func(time.Time) tea.Msg {
+		return SyncTimerMsg{}
+	})
+}
+
+// startSync queues a sync unless one is already running, manual syncs
+// report in the status line
+func (m *Model) startSync(manual bool) {
+	cfg := feedsync.NewConfig(m.config)
+	if !cfg.Enabled() {
+		if manual {
+			m.statusMessage = "No sync service set, choose one in settings (c)"
+			m.statusMessageType = "error"
+		}
+		return
+	}
+	if m.syncing {
+		if manual {
+			m.statusMessage = "Already syncing with " + cfg.ServiceName()
+			m.statusMessageType = "info"
+		}
+		return
+	}
+	if err := m.taskManager.AddTask(tasks.CreateSyncTask()); err != nil {
+		logging.Error("Failed to queue sync", "error", err)
+		if manual {
+			m.statusMessage = "Failed to start sync: " + err.Error()
+			m.statusMessageType = "error"
+		}
+		return
+	}
+	m.syncing = true
+	m.syncManual = manual
+	if manual {
+		m.statusMessage = "Syncing with " + cfg.ServiceName() + "..."
+		m.statusMessageType = "info"
+	}
+}
+
+// syncFinished reports a sync and reloads what it changed, feeds it added
+// to the URLs file are picked up by reloading it
+func (m *Model) syncFinished(event tasks.TaskEvent) tea.Cmd {
+	m.syncing = false
+	service := feedsync.NewConfig(m.config).ServiceName()
+	if event.Type == tasks.TaskEventFailed {
+		// Automatic syncs fail quietly, the error is in the log
+		if m.syncManual {
+			m.statusMessage = "Sync failed: " + event.Error
+			m.statusMessageType = "error"
+		}
+		return nil
+	}
+
+	pulled, _ := event.Data["pulled"].(int)
+	pushed, _ := event.Data["pushed"].(int)
+	added, _ := event.Data["added_feeds"].(int)
+	if m.syncManual || added > 0 {
+		m.statusMessage = fmt.Sprintf("Synced with %s: %d %s updated here, %d there", service, pulled, pluralItems(pulled), pushed)
+		if added > 0 {
+			m.statusMessage += fmt.Sprintf(", %d new %s", added, pluralFeeds(added))
+		}
+		m.statusMessageType = "info"
+	}
+
+	var cmds []tea.Cmd
+	if added > 0 {
+		cmds = append(cmds, reloadURLsFromFile(m.feedManager))
+	}
+	if pulled > 0 {
+		cmds = append(cmds, loadFeedList(m.feedManager))
+		if m.state == ItemListView {
+			cmds = append(cmds, loadItemList(m.feedManager, m.selectedFeed, m.config, len(m.loadedItems)))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+func pluralFeeds(count int) string {
+	if count == 1 {
+		return "feed"
+	}
+	return "feeds"
+}
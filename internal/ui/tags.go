@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tagEntry is a line of the tag view, a folder with the unread items of its
+// feeds. The first line, with an empty name, shows every feed again.
+type tagEntry struct {
+	Name   string
+	Unread int64
+}
+
+// tagEntries lists every folder as a tag, sub-folders after the folder they
+// are in. Feeds of a sub-folder count for the folder too.
+func (m Model) tagEntries() []tagEntry {
+	var folders []string
+	unread := make(map[string]int64)
+	var allUnread int64
+	for _, feed := range m.allFeeds {
+		allUnread += feed.UnreadItems
+		counted := make(map[string]bool)
+		for _, folder := range m.feedFolders[feed.ID] {
+			folders = append(folders, folder)
+			for path := folder; path != "" && !counted[path]; path = folderParent(path) {
+				counted[path] = true
+				unread[path] += feed.UnreadItems
+			}
+		}
+	}
+
+	entries := []tagEntry{{Unread: allUnread}}
+	children := folderTree(folders)
+	var add func(folder string)
+	add = func(folder string) {
+		entries = append(entries, tagEntry{Name: folder, Unread: unread[folder]})
+		for _, child := range children[folder] {
+			add(child)
+		}
+	}
+	for _, folder := range children[""] {
+		add(folder)
+	}
+	return entries
+}
+
+// hasTag reports whether a feed is in the folder of a tag or one of its
+// sub-folders
+func (m Model) hasTag(feedID int64, tag string) bool {
+	for _, folder := range m.feedFolders[feedID] {
+		if inFolder(folder, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// openTagView shows the tags with the cursor on the one selected
+func (m Model) openTagView() Model {
+	m.previousState = m.state
+	m.state = TagView
+	m.tagCursor = 0
+	for i, entry := range m.tagEntries() {
+		if entry.Name == m.selectedTag {
+			m.tagCursor = i
+		}
+	}
+	return m
+}
+
+// selectTag narrows the feed list to the feeds of a tag, an empty tag shows
+// every feed again
+func (m Model) selectTag(tag string) (Model, tea.Cmd) {
+	m.selectedTag = tag
+	m.state = FeedListView
+	m.cursor = 0
+	m.savedFeedCursor = 0
+	return m, loadFeedList(m.feedManager)
+}
+
+func (m Model) handleTagViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	entries := m.tagEntries()
+	switch msg.String() {
+	case "q", "esc", "ctrl+c":
+		m.state = m.previousState
+		return m, nil
+
+	case "j", "down":
+		if m.tagCursor < len(entries)-1 {
+			m.tagCursor++
+		}
+
+	case "k", "up":
+		if m.tagCursor > 0 {
+			m.tagCursor--
+		}
+
+	case "ctrl+d":
+		m.tagCursor = min(m.tagCursor+max(m.height/2, 5), len(entries)-1)
+
+	case "ctrl+u":
+		m.tagCursor = max(m.tagCursor-max(m.height/2, 5), 0)
+
+	case "enter":
+		if m.tagCursor < len(entries) {
+			return m.selectTag(entries[m.tagCursor].Name)
+		}
+	}
+	return m, nil
+}
+
+func (m Model) renderTagView() string {
+	entries := m.tagEntries()
+	var allLines []string
+	for i, entry := range entries {
+		name := "(all feeds)"
+		indent := ""
+		if entry.Name != "" {
+			name = folderLeaf(entry.Name)
+			indent = strings.Repeat("  ", folderDepth(entry.Name))
+		}
+		line := fmt.Sprintf("%s%-*s %6d unread", indent, 30-len(indent), name, entry.Unread)
+		if entry.Name == m.selectedTag {
+			line += " ✓"
+		}
+		if i != m.tagCursor && entry.Unread > 0 {
+			line = m.getUnreadStyle().Render(line)
+		}
+		allLines = append(allLines, m.applyHighlight(line, i == m.tagCursor))
+	}
+
+	// Reserve space for: title (1), empty line (1), status bar (1) = 3 lines
+	availableHeight := max(m.height-3, 3)
+	start := 0
+	if m.tagCursor >= availableHeight {
+		start = m.tagCursor - availableHeight + 1
+	}
+	end := min(start+availableHeight, len(allLines))
+	visibleLines := allLines[start:end]
+
+	var b strings.Builder
+	b.WriteString(m.getTitleStyle().Render("🐐 NewsGoat - Tags"))
+	b.WriteString("\n\n")
+
+	for _, line := range visibleLines {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	// Calculate padding to push status bar to bottom
+	usedLines := 2 + len(visibleLines)
+	padding := max(m.height-usedLines-1, 0)
+	b.WriteString(strings.Repeat("\n", padding))
+	b.WriteString(m.getHelpStyle().Render("enter: show the feeds of the tag | esc: return"))
+
+	return b.String()
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxUndo is how many mark-all-read actions are remembered for undo
+const maxUndo = 10
+
+// undoEntry is a mark-all-read that can be undone, the items that were
+// unread before it
+type undoEntry struct {
+	itemIDs []int64
+}
+
+// ItemsRestoredMsg is sent when items were marked unread again by an undo
+type ItemsRestoredMsg struct {
+	Count int
+}
+
+// pushUndo remembers the items an action marked read and offers to undo it
+// in the status line
+func (m *Model) pushUndo(itemIDs []int64) {
+	m.undoStack = append(m.undoStack, undoEntry{itemIDs: itemIDs})
+	if len(m.undoStack) > maxUndo {
+		m.undoStack = m.undoStack[len(m.undoStack)-maxUndo:]
+	}
+	m.offerUndo(fmt.Sprintf("Marked %d %s read, press u to undo", len(itemIDs), pluralItems(len(itemIDs))))
+}
+
+// offerUndo shows the undo prompt, u undoes while it is shown
+func (m *Model) offerUndo(prompt string) {
+	m.undoPrompt = prompt
+	m.statusMessage = prompt
+	m.statusMessageType = "info"
+}
+
+// undoOffered reports whether the undo prompt is still in the status line
+func (m Model) undoOffered() bool {
+	return len(m.undoStack) > 0 && m.undoPrompt != "" && m.statusMessage == m.undoPrompt
+}
+
+// undo restores the read state of the last mark-all-read
+func (m Model) undo() (tea.Model, tea.Cmd) {
+	last := m.undoStack[len(m.undoStack)-1]
+	m.undoStack = m.undoStack[:len(m.undoStack)-1]
+	m.undoPrompt = ""
+	m.statusMessage = "Undoing..."
+	m.statusMessageType = "info"
+	return m, restoreUnread(m.feedManager, last.itemIDs)
+}
+
+// restored reports an undo, offering the one before it when there is one
+func (m *Model) restored(count int) {
+	message := fmt.Sprintf("Marked %d %s unread again", count, pluralItems(count))
+	if len(m.undoStack) > 0 {
+		m.offerUndo(message + ", press u to undo the one before")
+		return
+	}
+	m.statusMessage = message
+	m.statusMessageType = "info"
+}
+
+func pluralItems(count int) string {
+	if count == 1 {
+		return "item"
+	}
+	return "items"
+}
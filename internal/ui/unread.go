@@ -0,0 +1,200 @@
+package ui
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/logging"
+)
+
+// NextUnreadFeedMsg carries the feed to go on to for the next unread item,
+// 0 when no feed has unread items left
+type NextUnreadFeedMsg struct {
+	FeedID int64
+	Err    error
+}
+
+// findNextUnreadFeed picks the first feed after the current one, in the
+// order of the feed list, that has unread items. The current feed comes
+// last, to wrap around to its unread items above the cursor.
+func findNextUnreadFeed(feedManager *feeds.Manager, order []int64, current int64) tea.Cmd {
+	return func() tea.Msg {
+		unread, err := feedManager.GetUnreadFeedIDs()
+		if err != nil {
+			logging.Error("findNextUnreadFeed failed", "error", err)
+			return NextUnreadFeedMsg{Err: err}
+		}
+		// Aggregated lists aren't in the order, their next feed is the first one
+		start := slices.Index(order, current) + 1
+		for i := range order {
+			feedID := order[(start+i)%len(order)]
+			if slices.Contains(unread, feedID) {
+				return NextUnreadFeedMsg{FeedID: feedID}
+			}
+		}
+		return NextUnreadFeedMsg{}
+	}
+}
+
+// unreadJumpOrder lists the feeds in the order of the feed list. The feeds
+// of a collapsed folder take its place, those of an expanded one are listed
+// below it anyway.
+func (m Model) unreadJumpOrder() []int64 {
+	var order []int64
+	add := func(feedID int64) {
+		if !isVirtualFeed(feedID) && !slices.Contains(order, feedID) {
+			order = append(order, feedID)
+		}
+	}
+	for _, row := range m.feedList {
+		switch {
+		case !row.IsFolder:
+			add(row.Feed.ID)
+		case !row.IsExpanded:
+			for _, feed := range m.allFeeds {
+				if !m.hasTag(feed.ID, row.FolderName) {
+					continue
+				}
+				if m.feedFilter != nil && !m.feedFilter.Match(m.feedRecord(feed)) {
+					continue
+				}
+				if m.selectedTag != "" && !m.hasTag(feed.ID, m.selectedTag) {
+					continue
+				}
+				add(feed.ID)
+			}
+		}
+	}
+	return order
+}
+
+// jumpToUnreadFeed moves the feed list cursor to the next or previous feed
+// with unread items, or collapsed folder holding some, wrapping around
+func (m Model) jumpToUnreadFeed(step int) Model {
+	n := len(m.feedList)
+	for i := 1; i <= n; i++ {
+		index := ((m.cursor+step*i)%n + n) % n
+		row := m.feedList[index]
+		if row.UnreadItems == 0 || (row.IsFolder && row.IsExpanded) || (!row.IsFolder && isVirtualFeed(row.Feed.ID)) {
+			continue
+		}
+		m.cursor = index
+		m.savedFeedCursor = index
+		return m
+	}
+	m.statusMessage = "No unread feeds"
+	m.statusMessageType = "info"
+	return m
+}
+
+// nextUnreadItem goes to the next unread item below the cursor, opening it
+// in the article view. When the list has none left it goes on to the next
+// feed with unread items.
+func (m Model) nextUnreadItem() (Model, tea.Cmd) {
+	for i := m.cursor + 1; i < len(m.itemList); i++ {
+		item := m.itemList[i]
+		// Opened items are only marked read in the list when it is reloaded
+		if item.Read || (m.state == ArticleView && item.ID == m.currentItem.ID) {
+			continue
+		}
+		if m.state == ArticleView {
+			return m.openArticleAt(i)
+		}
+		for j := m.cursor; j < i; j++ {
+			m.markSkipped(m.itemList[j])
+		}
+		m.cursor = i
+		m.savedItemCursor = i
+		m.itemTitleScrollOffset = 0
+		return m, nil
+	}
+	m.jumpingToUnread = true
+	return m, findNextUnreadFeed(m.feedManager, m.unreadJumpOrder(), m.selectedFeed)
+}
+
+// nextUnreadFeedFound loads the items of the feed with the next unread item
+func (m Model) nextUnreadFeedFound(msg NextUnreadFeedMsg) (Model, tea.Cmd) {
+	if !m.jumpingToUnread || (m.state != ItemListView && m.state != ArticleView) {
+		m.jumpingToUnread = false
+		return m, nil
+	}
+	status := ""
+	switch {
+	case msg.Err != nil:
+		status = "Failed to find unread items: " + msg.Err.Error()
+	case msg.FeedID == 0:
+		status = "No unread items"
+	}
+	if status != "" {
+		m.jumpingToUnread = false
+		if m.state == ArticleView {
+			m.fullTextStatus = status
+		} else {
+			m.statusMessage = status
+			m.statusMessageType = "info"
+		}
+		return m, nil
+	}
+
+	// The items passed over are done with like when leaving the item list
+	cmd := m.flushSkippedItems()
+	m.searchMode = false
+	m.searchActive = false
+	m.searchQuery = ""
+	m.selectedFeed = msg.FeedID
+	// Leaving the item list goes back to the feed jumped to
+	if index := slices.IndexFunc(m.feedList, func(row FeedListItem) bool {
+		return !row.IsFolder && row.Feed.ID == msg.FeedID
+	}); index >= 0 {
+		m.savedFeedCursor = index
+	}
+	m.cursor = 0
+	m.savedItemCursor = 0
+	m.loadedItems, m.moreItems = nil, false
+	return m, tea.Batch(cmd, loadItemList(m.feedManager, m.selectedFeed, m.config, 0))
+}
+
+// unreadFeedLoaded puts the cursor on the first unread item of the feed
+// jumped to, opening it when jumping from the article view
+func (m Model) unreadFeedLoaded() (Model, tea.Cmd) {
+	m.jumpingToUnread = false
+	index := slices.IndexFunc(m.itemList, func(item database.GetItemsWithReadStatusRow) bool { return !item.Read })
+	if index < 0 {
+		// Filtered out, or beyond the loaded page
+		m.state = ItemListView
+		m.statusMessage = "No unread items left in " + m.feedTitle(m.selectedFeed)
+		m.statusMessageType = "info"
+		return m, nil
+	}
+	if m.state == ArticleView {
+		return m.openArticleAt(index)
+	}
+	m.cursor = index
+	m.savedItemCursor = index
+	m.itemTitleScrollOffset = 0
+	return m, nil
+}
+
+// openArticleAt shows the article of an item of the item list and marks it
+// read
+func (m Model) openArticleAt(index int) (Model, tea.Cmd) {
+	m.savedItemCursor = index
+	m.cursor = index
+	m.currentItem = m.itemList[index]
+	m.links = m.feedManager.ExtractLinks(itemContent(m.currentItem))
+	m.showRawHTML = false
+	m.articleViewScroll = 0
+	m.fullTextStatus = ""
+	m.state = ArticleView
+	delete(m.skippedItems, m.currentItem.ID)
+
+	if !m.currentItem.Read {
+		return m, tea.Batch(
+			markItemRead(m.feedManager, m.currentItem.ID),
+			recordItemEvent(m.feedManager, m.currentItem.ID, m.currentItem.FeedID, feeds.ItemEventOpen),
+		)
+	}
+	return m, nil
+}
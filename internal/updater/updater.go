@@ -45,11 +45,11 @@ func CheckForUpdate() (*UpdateInfo, error) {
 
 	// Skip update check for dev builds
 	if currentVersion == "dev" {
-		logging.Debug("Skipping update check for dev build")
+		logging.DebugCategory(logging.CategoryHTTP, "Skipping update check for dev build")
 		return nil, nil
 	}
 
-	logging.Debug("Checking for updates", "api_url", githubAPIURL)
+	logging.DebugCategory(logging.CategoryHTTP, "Checking for updates", "api_url", githubAPIURL)
 
 	client := &http.Client{Timeout: timeout}
 
@@ -66,7 +66,7 @@ func CheckForUpdate() (*UpdateInfo, error) {
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
-			logging.Debug("Failed to close response body", "error", closeErr)
+			logging.DebugCategory(logging.CategoryHTTP, "Failed to close response body", "error", closeErr)
 		}
 	}()
 
@@ -81,17 +81,17 @@ func CheckForUpdate() (*UpdateInfo, error) {
 
 	latestVersion := release.TagName
 
-	logging.Debug("Version comparison", "current", currentVersion, "latest", latestVersion)
+	logging.DebugCategory(logging.CategoryHTTP, "Version comparison", "current", currentVersion, "latest", latestVersion)
 
 	// If versions are the same, no update needed
 	if currentVersion == latestVersion {
-		logging.Debug("Already on latest version")
+		logging.DebugCategory(logging.CategoryHTTP, "Already on latest version")
 		return nil, nil
 	}
 
 	// Find the appropriate binary for this platform
 	binaryName := getBinaryName()
-	logging.Debug("Looking for binary", "name", binaryName, "platform", runtime.GOOS, "arch", runtime.GOARCH)
+	logging.DebugCategory(logging.CategoryHTTP, "Looking for binary", "name", binaryName, "platform", runtime.GOOS, "arch", runtime.GOARCH)
 
 	var downloadURL string
 
@@ -139,7 +139,7 @@ func CheckWritePermission() error {
 		return fmt.Errorf("cannot write to %s: %w", execPath, err)
 	}
 	if closeErr := file.Close(); closeErr != nil {
-		logging.Debug("Failed to close file during permission check", "path", execPath, "error", closeErr)
+		logging.DebugCategory(logging.CategoryHTTP, "Failed to close file during permission check", "path", execPath, "error", closeErr)
 	}
 
 	return nil
@@ -147,6 +147,13 @@ func CheckWritePermission() error {
 
 // DownloadAndInstall downloads the latest version and replaces the current binary
 func DownloadAndInstall(downloadURL string) error {
+	return DownloadAndInstallWithProgress(downloadURL, nil)
+}
+
+// DownloadAndInstallWithProgress works like DownloadAndInstall and calls
+// progress with the bytes downloaded so far and the size of the download,
+// 0 when the server doesn't say
+func DownloadAndInstallWithProgress(downloadURL string, progress func(done, total int64)) error {
 	logging.Info("Starting update installation", "download_url", downloadURL)
 
 	// Get current executable path
@@ -155,7 +162,7 @@ func DownloadAndInstall(downloadURL string) error {
 		return fmt.Errorf("failed to get executable path: %w", err)
 	}
 
-	logging.Debug("Current executable path", "path", execPath)
+	logging.DebugCategory(logging.CategoryHTTP, "Current executable path", "path", execPath)
 
 	// Resolve symlinks
 	execPath, err = filepath.EvalSymlinks(execPath)
@@ -163,7 +170,7 @@ func DownloadAndInstall(downloadURL string) error {
 		return fmt.Errorf("failed to resolve symlinks: %w", err)
 	}
 
-	logging.Debug("Resolved executable path", "path", execPath)
+	logging.DebugCategory(logging.CategoryHTTP, "Resolved executable path", "path", execPath)
 
 	// Download the new binary
 	logging.Info("Downloading update", "url", downloadURL)
@@ -174,7 +181,7 @@ func DownloadAndInstall(downloadURL string) error {
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
-			logging.Debug("Failed to close response body", "error", closeErr)
+			logging.DebugCategory(logging.CategoryHTTP, "Failed to close response body", "error", closeErr)
 		}
 	}()
 
@@ -182,7 +189,7 @@ func DownloadAndInstall(downloadURL string) error {
 		return fmt.Errorf("download failed with status: %d", resp.StatusCode)
 	}
 
-	logging.Debug("Download response received", "status", resp.StatusCode, "content_length", resp.ContentLength)
+	logging.DebugCategory(logging.CategoryHTTP, "Download response received", "status", resp.StatusCode, "content_length", resp.ContentLength)
 
 	// Create temporary file
 	tmpFile, err := os.CreateTemp("", "newsgoat-update-*")
@@ -192,14 +199,18 @@ func DownloadAndInstall(downloadURL string) error {
 	tmpPath := tmpFile.Name()
 	defer func() {
 		if removeErr := os.Remove(tmpPath); removeErr != nil {
-			logging.Debug("Failed to remove temp file", "path", tmpPath, "error", removeErr)
+			logging.DebugCategory(logging.CategoryHTTP, "Failed to remove temp file", "path", tmpPath, "error", removeErr)
 		}
 	}()
 
-	logging.Debug("Created temporary file", "path", tmpPath)
+	logging.DebugCategory(logging.CategoryHTTP, "Created temporary file", "path", tmpPath)
 
 	// Write downloaded content to temp file
-	bytesWritten, err := io.Copy(tmpFile, resp.Body)
+	var body io.Reader = resp.Body
+	if progress != nil {
+		body = &progressReader{r: resp.Body, total: max(resp.ContentLength, 0), progress: progress}
+	}
+	bytesWritten, err := io.Copy(tmpFile, body)
 	if closeErr := tmpFile.Close(); closeErr != nil {
 		return fmt.Errorf("failed to close temp file: %w", closeErr)
 	}
@@ -207,14 +218,14 @@ func DownloadAndInstall(downloadURL string) error {
 		return fmt.Errorf("failed to write update: %w", err)
 	}
 
-	logging.Debug("Downloaded binary written to temp file", "bytes", bytesWritten, "path", tmpPath)
+	logging.DebugCategory(logging.CategoryHTTP, "Downloaded binary written to temp file", "bytes", bytesWritten, "path", tmpPath)
 
 	// Make it executable
 	if err := os.Chmod(tmpPath, 0755); err != nil {
 		return fmt.Errorf("failed to make executable: %w", err)
 	}
 
-	logging.Debug("Set executable permissions on temp file")
+	logging.DebugCategory(logging.CategoryHTTP, "Set executable permissions on temp file")
 
 	// Create backup in temp directory by copying the current binary
 	// We use copy instead of rename because we may not have permission to rename/remove from /usr/local/bin
@@ -226,11 +237,11 @@ func DownloadAndInstall(downloadURL string) error {
 
 	defer func() {
 		if removeErr := os.Remove(backupPath); removeErr != nil {
-			logging.Debug("Failed to remove backup file", "path", backupPath, "error", removeErr)
+			logging.DebugCategory(logging.CategoryHTTP, "Failed to remove backup file", "path", backupPath, "error", removeErr)
 		}
 	}()
 
-	logging.Debug("Backing up current binary", "from", execPath, "to", backupPath)
+	logging.DebugCategory(logging.CategoryHTTP, "Backing up current binary", "from", execPath, "to", backupPath)
 
 	// Copy current binary to backup
 	currentFile, err := os.Open(execPath)
@@ -239,7 +250,7 @@ func DownloadAndInstall(downloadURL string) error {
 	}
 	_, err = io.Copy(backupFile, currentFile)
 	if closeErr := currentFile.Close(); closeErr != nil {
-		logging.Debug("Failed to close current binary file", "path", execPath, "error", closeErr)
+		logging.DebugCategory(logging.CategoryHTTP, "Failed to close current binary file", "path", execPath, "error", closeErr)
 	}
 	if closeErr := backupFile.Close(); closeErr != nil {
 		return fmt.Errorf("failed to close backup file: %w", closeErr)
@@ -248,7 +259,7 @@ func DownloadAndInstall(downloadURL string) error {
 		return fmt.Errorf("failed to copy current binary to backup: %w", err)
 	}
 
-	logging.Debug("Created backup of current binary")
+	logging.DebugCategory(logging.CategoryHTTP, "Created backup of current binary")
 
 	// Copy new binary to current location (overwriting the existing file)
 	logging.Info("Installing new binary", "from", tmpPath, "to", execPath)
@@ -308,3 +319,20 @@ func IsNewerVersion(current, latest string) bool {
 	// Simple string comparison (works for semantic versions)
 	return latest > current
 }
+
+// progressReader reports how much of the download was read
+type progressReader struct {
+	r        io.Reader
+	read     int64
+	total    int64
+	progress func(done, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.read += int64(n)
+		p.progress(p.read, p.total)
+	}
+	return n, err
+}
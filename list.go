@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jarv/newsgoat/internal/database"
+)
+
+// listedFeed is a feed as printed by the list command
+type listedFeed struct {
+	Title     string   `json:"title"`
+	URL       string   `json:"url"`
+	Folders   []string `json:"folders"`
+	Unread    int64    `json:"unread"`
+	Total     int64    `json:"total"`
+	Paused    bool     `json:"paused"`
+	LastError string   `json:"last_error,omitempty"`
+}
+
+// listedItem is an item as printed by the items command
+type listedItem struct {
+	ID        int64      `json:"id"`
+	Title     string     `json:"title"`
+	Link      string     `json:"link"`
+	Published *time.Time `json:"published,omitempty"`
+	Read      bool       `json:"read"`
+	Starred   bool       `json:"starred"`
+}
+
+// openDatabaseForListing opens the database read-only, so listing works
+// while NewsGoat is running
+func openDatabaseForListing() (*sql.DB, *database.Queries, error) {
+	db, queries, err := database.InitDBReadOnly()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return db, queries, nil
+}
+
+// listFeeds prints the feeds with their unread and total item counts as
+// tab-separated values or JSON, for scripts and status bars
+func listFeeds(args []string) error {
+	flags := flag.NewFlagSet("list", flag.ExitOnError)
+	unread := flags.Bool("unread", false, "Only list feeds with unread items")
+	asJSON := flags.Bool("json", false, "Print a JSON array instead of tab-separated values")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: newsgoat list [--unread] [--json]\n\n")
+		fmt.Fprintf(os.Stderr, "Tab-separated columns: unread, total, url, title, folders (comma-separated)\n\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return fmt.Errorf("'list' takes no arguments")
+	}
+
+	db, queries, err := openDatabaseForListing()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	ctx := context.Background()
+	stats, err := queries.GetFeedStats(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read feeds: %w", err)
+	}
+
+	listed := make([]listedFeed, 0, len(stats))
+	for _, feed := range stats {
+		if *unread && feed.UnreadItems == 0 {
+			continue
+		}
+		folders, err := queries.GetFeedFolders(ctx, feed.ID)
+		if err != nil {
+			return fmt.Errorf("failed to read folders of %s: %w", feed.Url, err)
+		}
+		if folders == nil {
+			folders = []string{}
+		}
+		listed = append(listed, listedFeed{
+			Title:     feed.Title,
+			URL:       feed.Url,
+			Folders:   folders,
+			Unread:    feed.UnreadItems,
+			Total:     feed.TotalItems,
+			Paused:    feed.Paused,
+			LastError: feed.LastError.String,
+		})
+	}
+
+	if *asJSON {
+		return writeJSON(os.Stdout, listed)
+	}
+	for _, feed := range listed {
+		fmt.Println(tsvLine(strconv.FormatInt(feed.Unread, 10), strconv.FormatInt(feed.Total, 10), feed.URL, feed.Title, strings.Join(feed.Folders, ",")))
+	}
+	return nil
+}
+
+// listItems prints the items of a feed, newest first, as tab-separated
+// values or JSON
+func listItems(args []string) error {
+	flags := flag.NewFlagSet("items", flag.ExitOnError)
+	unread := flags.Bool("unread", false, "Only list unread items")
+	starred := flags.Bool("starred", false, "Only list starred items")
+	limit := flags.Int("limit", 0, "List at most this many items, 0 for all")
+	asJSON := flags.Bool("json", false, "Print a JSON array instead of tab-separated values")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: newsgoat items [--unread] [--starred] [--limit n] [--json] <feed-url>\n\n")
+		fmt.Fprintf(os.Stderr, "Tab-separated columns: id, read, starred, published (RFC 3339), link, title\n\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("'items' requires a feed URL, see newsgoat list")
+	}
+	feedURL := flags.Arg(0)
+
+	db, queries, err := openDatabaseForListing()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	ctx := context.Background()
+	feed, err := queries.GetFeedByURL(ctx, feedURL)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("no feed with the URL %s, see newsgoat list", feedURL)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read feed: %w", err)
+	}
+	items, err := queries.GetItemsWithReadStatus(ctx, feed.ID)
+	if err != nil {
+		return fmt.Errorf("failed to read items: %w", err)
+	}
+
+	listed := make([]listedItem, 0, len(items))
+	for _, item := range items {
+		if (*unread && item.Read) || (*starred && !item.Starred) {
+			continue
+		}
+		if *limit > 0 && len(listed) == *limit {
+			break
+		}
+		entry := listedItem{
+			ID:      item.ID,
+			Title:   item.Title,
+			Link:    item.Link,
+			Read:    item.Read,
+			Starred: item.Starred,
+		}
+		if item.Published.Valid {
+			published := item.Published.Time
+			entry.Published = &published
+		}
+		listed = append(listed, entry)
+	}
+
+	if *asJSON {
+		return writeJSON(os.Stdout, listed)
+	}
+	for _, item := range listed {
+		published := ""
+		if item.Published != nil {
+			published = item.Published.Format(time.RFC3339)
+		}
+		fmt.Println(tsvLine(strconv.FormatInt(item.ID, 10), strconv.FormatBool(item.Read), strconv.FormatBool(item.Starred), published, item.Link, item.Title))
+	}
+	return nil
+}
+
+// writeJSON prints v as indented JSON
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// tsvLine joins fields with tabs, whitespace inside a field is collapsed to
+// single spaces so every record stays on one line
+func tsvLine(fields ...string) string {
+	cleaned := make([]string, len(fields))
+	for i, field := range fields {
+		cleaned[i] = strings.Join(strings.Fields(field), " ")
+	}
+	return strings.Join(cleaned, "\t")
+}
@@ -1,54 +1,95 @@
 package main
 
 import (
+	"bufio"
 	"context"
-	_ "embed"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jarv/newsgoat/internal/bugreport"
 	"github.com/jarv/newsgoat/internal/config"
 	"github.com/jarv/newsgoat/internal/database"
-	"github.com/jarv/newsgoat/internal/discovery"
 	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/hooks"
 	"github.com/jarv/newsgoat/internal/logging"
+	feedsync "github.com/jarv/newsgoat/internal/sync"
 	"github.com/jarv/newsgoat/internal/tasks"
+	"github.com/jarv/newsgoat/internal/themes"
 	"github.com/jarv/newsgoat/internal/ui"
 	"github.com/jarv/newsgoat/internal/version"
 )
 
-//go:embed sql/schema.sql
-var schemaSQL string
-
 var logger *slog.Logger
 
+// hooksExitTimeout is how long NewsGoat waits for the shutdown hook and
+// other running hooks before it exits
+const hooksExitTimeout = 10 * time.Second
+
 func setupLogging(queries *database.Queries, debug bool) {
 	slogHandler := logging.NewDatabaseHandlerWithDebug(queries, debug)
 	logger = slog.New(slogHandler)
 
 	// Set the global logger for other packages
 	logging.SetLogger(logger)
+
+	registerEnvSecrets()
+}
+
+// registerEnvSecrets keeps tokens from the environment out of logs and the UI
+func registerEnvSecrets() {
+	for _, name := range []string{"GITHUB_FEED_TOKEN", "GITLAB_FEED_TOKEN", "GITHUB_GIST_TOKEN"} {
+		logging.RegisterSecret(os.Getenv(name))
+	}
 }
 
 func main() {
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: newsgoat [options] [command]\n\n")
 		fmt.Fprintf(os.Stderr, "Commands:\n")
-		fmt.Fprintf(os.Stderr, "  add <url>    Add a feed URL to the URLs file\n\n")
+		fmt.Fprintf(os.Stderr, "  add [--quiet] <url> [folder1,folder2]\n")
+		fmt.Fprintf(os.Stderr, "                        Discover the feed of a URL and add it to the URLs file\n")
+		fmt.Fprintf(os.Stderr, "  import <file.opml>    Import feeds from an OPML file into the URLs file\n")
+		fmt.Fprintf(os.Stderr, "  refresh [--quiet]     Refresh every feed without starting the UI, e.g. from cron\n")
+		fmt.Fprintf(os.Stderr, "  daemon                Refresh feeds in the background, windows started later use it\n")
+		fmt.Fprintf(os.Stderr, "  list [--unread] [--json]\n")
+		fmt.Fprintf(os.Stderr, "                        Print the feeds with their unread counts as TSV or JSON\n")
+		fmt.Fprintf(os.Stderr, "  items [--unread] [--starred] [--limit n] [--json] <feed-url>\n")
+		fmt.Fprintf(os.Stderr, "                        Print the items of a feed as TSV or JSON\n")
+		fmt.Fprintf(os.Stderr, "  logs export [--since 24h] [--level error] <file.jsonl>\n")
+		fmt.Fprintf(os.Stderr, "                        Export log messages as JSON Lines, use - for stdout\n")
+		fmt.Fprintf(os.Stderr, "  mark-read <feed-url|--all>\n")
+		fmt.Fprintf(os.Stderr, "                        Mark every item of a feed, or of all feeds, read\n")
+		fmt.Fprintf(os.Stderr, "  mark-item-read <item-id>...\n")
+		fmt.Fprintf(os.Stderr, "                        Mark items read by the ids newsgoat items prints\n")
+		fmt.Fprintf(os.Stderr, "  secret <set|delete> <name>\n")
+		fmt.Fprintf(os.Stderr, "                        Keep a password or token in the OS keychain, used as keyring:<name>\n")
+		fmt.Fprintf(os.Stderr, "  register-links        Open feed:// and newsgoat:// links with newsgoat (Linux)\n")
+		fmt.Fprintf(os.Stderr, "  update [--check]      Replace newsgoat with the latest release\n")
+		fmt.Fprintf(os.Stderr, "  <feed://... | newsgoat://add?url=...>\n")
+		fmt.Fprintf(os.Stderr, "                        Add the feed of a link to the running newsgoat, or start with it added\n")
+		fmt.Fprintf(os.Stderr, "  bug-report [--since 24h] [-o file.md] [feed-url]\n")
+		fmt.Fprintf(os.Stderr, "                        Print a redacted report to paste into a GitHub issue\n\n")
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		flag.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nEnvironment Variables:\n")
 		fmt.Fprintf(os.Stderr, "  GITHUB_FEED_TOKEN   Access token for private GitHub repository feeds\n")
 		fmt.Fprintf(os.Stderr, "  GITLAB_FEED_TOKEN   Access token for private GitLab repository feeds\n")
+		fmt.Fprintf(os.Stderr, "  GITHUB_GIST_TOKEN   Access token for publishing the reading log to a gist\n")
 	}
 
 	var feedTest = flag.Bool("feedTest", false, "Run feed test harness server")
 	var showVersion = flag.Bool("version", false, "Show version information")
 	var debug = flag.Bool("debug", false, "Enable debug logging")
+	var debugCategories = flag.String("debugCategories", "", "Enable debug logging for some categories only: "+strings.Join(logging.Categories, ",")+" (overrides the setting)")
 	var urlFile = flag.String("u", "", "Path to URL file (overrides default location)")
 	flag.StringVar(urlFile, "urlFile", "", "Path to URL file (overrides default location)")
+	var readOnly = flag.Bool("readOnly", false, "Open without refreshing feeds or changing anything, while another newsgoat is running")
 	flag.Parse()
 
 	if *showVersion {
@@ -69,61 +110,411 @@ func main() {
 	if len(args) > 0 {
 		switch args[0] {
 		case "add":
+			if err := addURL(args[1:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "import":
 			if len(args) < 2 {
-				fmt.Fprintf(os.Stderr, "Error: 'add' command requires a URL argument\n")
-				fmt.Fprintf(os.Stderr, "Usage: newsgoat add <url>\n")
+				fmt.Fprintf(os.Stderr, "Error: 'import' command requires an OPML file argument\n")
+				fmt.Fprintf(os.Stderr, "Usage: newsgoat import <file.opml>\n")
+				os.Exit(1)
+			}
+			if err := importOPML(args[1]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "logs":
+			if len(args) < 2 || args[1] != "export" {
+				fmt.Fprintf(os.Stderr, "Usage: newsgoat logs export [--since 24h] [--level error] <file.jsonl>\n")
+				os.Exit(1)
+			}
+			if err := exportLogs(args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "bug-report":
+			if err := bugReport(args[1:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "list":
+			if err := listFeeds(args[1:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "items":
+			if err := listItems(args[1:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "refresh":
+			if err := refreshFeeds(args[1:], *urlFile, *debug); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
-			if err := addURL(args[1]); err != nil {
+			return
+		case "daemon":
+			if err := daemonCommand(args[1:], *urlFile, *debug); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "mark-read":
+			if err := markReadCommand(args[1:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "mark-item-read":
+			if err := markItemReadCommand(args[1:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "secret":
+			if err := secretCommand(args[1:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "register-links":
+			if err := registerLinks(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "update":
+			if err := updateCommand(args[1:]); err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
 			return
 		default:
-			fmt.Fprintf(os.Stderr, "Error: unknown command '%s'\n", args[0])
-			os.Exit(1)
+			feedURL, isLink, err := config.ParseDeepLink(args[0])
+			if !isLink {
+				fmt.Fprintf(os.Stderr, "Error: unknown command '%s'\n", args[0])
+				os.Exit(1)
+			}
+			if err == nil {
+				var sent bool
+				sent, err = openLink(feedURL)
+				if sent {
+					return
+				}
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			// The feed was added, start NewsGoat with it
 		}
 	}
 
-	if err := run(*urlFile, *debug); err != nil {
+	categories, err := logging.ParseDebugCategories(*debugCategories)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := run(*urlFile, *debug, categories, *readOnly); err != nil {
 		fmt.Fprintf(os.Stderr, "2Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func addURL(urlArg string) error {
-	// Try to discover the feed URL
-	fmt.Printf("Discovering feed URL from: %s\n", urlArg)
-	feedURL, err := discovery.DiscoverFeed(urlArg)
+func importOPML(opmlPath string) error {
+	file, err := os.Open(opmlPath)
 	if err != nil {
-		return fmt.Errorf("failed to discover feed: %w", err)
+		return fmt.Errorf("failed to open OPML file: %w", err)
 	}
+	defer func() {
+		_ = file.Close()
+	}()
 
-	if feedURL != urlArg {
-		fmt.Printf("Discovered feed URL: %s\n", feedURL)
+	entries, err := config.ParseOPML(file)
+	if err != nil {
+		return err
 	}
 
-	// Add the URL to the URLs file
-	if err := config.AddURL(feedURL); err != nil {
-		return fmt.Errorf("failed to add URL to file: %w", err)
+	// Get existing URLs so duplicates can be reported
+	existingEntries, err := config.ReadURLsFile()
+	if err != nil {
+		return fmt.Errorf("failed to read URLs file: %w", err)
+	}
+	existing := make(map[string]bool)
+	for _, entry := range existingEntries {
+		existing[entry.URL] = true
 	}
 
-	fmt.Printf("Successfully added feed: %s\n", feedURL)
+	added := 0
+	skipped := 0
+	for _, entry := range entries {
+		if existing[entry.URL] {
+			skipped++
+			continue
+		}
+		if err := config.AddURLLine(config.FormatURLLine(entry)); err != nil {
+			return fmt.Errorf("failed to add URL to file: %w", err)
+		}
+		existing[entry.URL] = true
+		added++
+	}
+
+	fmt.Printf("Imported %d feeds from %s (%d already present)\n", added, opmlPath, skipped)
 	return nil
 }
 
-func run(urlFile string, debug bool) error {
-	// Initialize database first
-	db, queries, err := database.InitDBWithSchema(schemaSQL)
+// exportLogs writes the log messages stored in the database as JSON Lines,
+// for attaching to bug reports
+func exportLogs(args []string) error {
+	flags := flag.NewFlagSet("logs export", flag.ExitOnError)
+	since := flags.Duration("since", 0, "Only export messages logged within this duration, e.g. 24h")
+	levelName := flags.String("level", "debug", "Only export messages at or above this level: debug, info, warn, error")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: newsgoat logs export [--since 24h] [--level error] <file.jsonl>\n\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("'logs export' requires an output file, use - for stdout")
+	}
+
+	level, err := logging.ParseLevel(*levelName)
+	if err != nil {
+		return err
+	}
+	filter := logging.ExportFilter{Level: level}
+	if *since > 0 {
+		filter.Since = time.Now().Add(-*since)
+	}
+
+	db, queries, err := database.InitDB()
 	if err != nil {
 		return fmt.Errorf("failed to initialize database: %w", err)
 	}
+	defer func() {
+		_ = db.Close()
+	}()
+	if err := RunMigrations(db); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	messages, err := queries.GetAllLogMessages(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to read log messages: %w", err)
+	}
+
+	path := flags.Arg(0)
+	if path == "-" {
+		_, err := logging.WriteJSONLines(os.Stdout, messages, filter)
+		return err
+	}
 
-	// Run migrations
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	written, err := logging.WriteJSONLines(file, messages, filter)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	fmt.Printf("Exported %d log messages to %s\n", written, path)
+	return nil
+}
+
+// bugReport prints version, settings, the failing feed and recent errors as
+// markdown for a GitHub issue, with credentials redacted
+func bugReport(args []string) error {
+	flags := flag.NewFlagSet("bug-report", flag.ExitOnError)
+	since := flags.Duration("since", 24*time.Hour, "Only include warnings and errors logged within this duration, 0 for all")
+	output := flags.String("o", "", "Write the report to a file instead of stdout")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: newsgoat bug-report [--since 24h] [-o file.md] [feed-url]\n\n")
+		fmt.Fprintf(os.Stderr, "Without a feed URL the report includes every feed whose last refresh failed.\n\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return fmt.Errorf("'bug-report' takes at most one feed URL")
+	}
+
+	db, queries, err := database.InitDB()
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
 	if err := RunMigrations(db); err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
+	// Register every secret the app knows about so Redact removes them
+	registerEnvSecrets()
+	cfg, err := config.LoadConfig(queries)
+	if err != nil {
+		cfg = config.GetDefaultConfig()
+	}
+	feeds.NewReadLaterConfig(cfg).RegisterSecrets()
+	feedsync.NewConfig(cfg).RegisterSecrets()
+
+	bundle, err := bugreport.Collect(context.Background(), queries, flags.Arg(0), *since, requestSecrets(cfg))
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		fmt.Print(bundle.Markdown())
+		return nil
+	}
+	if err := os.WriteFile(*output, []byte(bundle.Markdown()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", *output, err)
+	}
+	fmt.Printf("Wrote issue report to %s, check it before posting\n", *output)
+	return nil
+}
+
+// requestSecrets collects the credentials sent with feed requests, from the
+// request headers of the settings and the auth and headers of the URLs file
+func requestSecrets(cfg config.Config) []string {
+	secrets := cfg.RequestOptions().Secrets()
+	urlEntries, err := config.ReadURLsFile()
+	if err != nil {
+		return secrets
+	}
+	feedAuths, _ := config.FeedAuths(urlEntries)
+	for _, auth := range feedAuths {
+		secrets = append(secrets, auth.Secrets()...)
+	}
+	requestOptions, _ := config.FeedRequestOptions(urlEntries)
+	for _, options := range requestOptions {
+		secrets = append(secrets, options.Secrets()...)
+	}
+	return secrets
+}
+
+// migrateLegacyDir offers to move files from ~/.newsgoat to ~/.config/newsgoat,
+// it returns a status message for the UI when they were moved
+func migrateLegacyDir() string {
+	migration, err := config.PendingLegacyMigration()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to check for files in ~/.newsgoat: %v\n", err)
+		return ""
+	}
+	if migration == nil {
+		return ""
+	}
+
+	if len(migration.Conflicts) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %s exists in both %s and %s, still using %s until one is removed\n",
+			strings.Join(migration.Conflicts, ", "), migration.OldDir, migration.NewDir, migration.OldDir)
+		return ""
+	}
+
+	// Only ask when someone can answer
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return ""
+	}
+
+	fmt.Printf("NewsGoat now keeps its files in %s.\n", migration.NewDir)
+	fmt.Printf("Move %s from %s? [y/N] ", strings.Join(migration.Files, ", "), migration.OldDir)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer != "y" && answer != "yes" {
+		fmt.Printf("Keeping %s, you will be asked again on the next start\n", migration.OldDir)
+		return ""
+	}
+
+	if err := migration.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to move files from %s: %v\n", migration.OldDir, err)
+		return ""
+	}
+
+	return fmt.Sprintf("Moved %s from %s to %s", strings.Join(migration.Files, ", "), migration.OldDir, migration.NewDir)
+}
+
+// lockOrReadOnly takes the lock so only one newsgoat refreshes the feeds. When
+// another one holds it, it offers to open read-only and returns its pid, or 0
+// when the lock was taken.
+func lockOrReadOnly() (*config.Lock, int, error) {
+	lock, err := config.AcquireLock()
+	var locked *config.LockedError
+	if !errors.As(err, &locked) {
+		return lock, 0, err
+	}
+
+	// Only ask when someone can answer
+	if info, statErr := os.Stdin.Stat(); statErr != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil, 0, err
+	}
+
+	fmt.Printf("NewsGoat is already running (pid %d), open anyway read-only? [y/N] ", locked.PID)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer != "y" && answer != "yes" {
+		return nil, 0, err
+	}
+	return nil, locked.PID, nil
+}
+
+func run(urlFile string, debug bool, debugCategories []string, readOnly bool) error {
+	// With a daemon running this window leaves refreshing to it
+	var readOnlyPID int
+	daemonPID, socketPath := runningDaemon()
+	if !readOnly && daemonPID == 0 {
+		lock, pid, err := lockOrReadOnly()
+		if err != nil {
+			return err
+		}
+		if lock != nil {
+			defer func() {
+				_ = lock.Release()
+			}()
+		}
+		readOnly, readOnlyPID = pid != 0, pid
+	}
+
+	// Move files out of the old ~/.newsgoat directory before they are opened
+	var migrationStatus string
+	if !readOnly && daemonPID == 0 {
+		migrationStatus = migrateLegacyDir()
+	}
+
+	// Initialize database first, the schema is created by the migrations
+	openDB := database.InitDB
+	if readOnly {
+		openDB = database.InitDBReadOnly
+	}
+	db, queries, err := openDB()
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+
+	// Run migrations, a read-only database is kept up to date by the newsgoat
+	// holding the lock
+	if !readOnly && daemonPID == 0 {
+		if err := RunMigrations(db); err != nil {
+			return fmt.Errorf("failed to run migrations: %w", err)
+		}
+	}
+
 	// Load configuration from database
 	cfg, err := config.LoadConfig(queries)
 	if err != nil {
@@ -131,8 +522,17 @@ func run(urlFile string, debug bool) error {
 		cfg = config.GetDefaultConfig()
 	}
 
-	// Setup logging after database is initialized
+	// Setup logging after database is initialized, -debugCategories wins over the setting
 	setupLogging(queries, debug)
+	if debugCategories == nil {
+		var err error
+		if debugCategories, err = logging.ParseDebugCategories(cfg.DebugCategories); err != nil {
+			logger.Warn("Ignoring debug categories setting", "error", err)
+		}
+	}
+	logging.SetDebugCategories(debugCategories)
+	feeds.NewReadLaterConfig(cfg).RegisterSecrets()
+	feedsync.NewConfig(cfg).RegisterSecrets()
 	defer func() {
 		if closeErr := db.Close(); closeErr != nil {
 			logger.Error("Error closing database", "error", closeErr)
@@ -140,23 +540,87 @@ func run(urlFile string, debug bool) error {
 	}()
 
 	feedManager := feeds.NewManager(db, queries)
+	feedManager.SetRequestOptions(cfg.RequestOptions())
+	feedManager.SetRetentionPolicy(cfg.RetentionPolicy())
+	feedManager.SetHostLimits(cfg.HostLimits())
+	feedManager.SetHistoryPages(cfg.FeedHistoryPages)
+	feedManager.SetFeedIcons(!readOnly && cfg.FeedIcons != config.FeedIconsOff)
+	if imageCacheDir, err := config.GetImageCacheDir(); err == nil {
+		feedManager.SetImageCacheDir(imageCacheDir)
+	} else {
+		logger.Warn("Failed to get image cache directory", "error", err)
+	}
+
+	// Hooks don't run in read-only mode, the other NewsGoat runs them
+	var eventHooks *hooks.Hooks
+	if !readOnly {
+		eventHooks = loadHooks()
+		feedManager.SetHooks(eventHooks)
+		feedManager.SetNotifyPolicy(cfg.NotifyPolicy())
+	}
 
-	// Create and start task manager
+	// Create and start task manager, the NewsGoat that refreshes the feeds
+	// keeps unfinished tasks in the database to run them again after a restart
+	ownsTasks := !readOnly && daemonPID == 0
 	taskManager := tasks.NewManager(cfg.ReloadConcurrency)
+	if ownsTasks {
+		taskManager = tasks.NewManagerWithStore(cfg.ReloadConcurrency, tasks.NewDBStore(queries))
+	}
 	ctx := context.Background()
 	if err := taskManager.Start(ctx); err != nil {
 		return fmt.Errorf("failed to start task manager: %w", err)
 	}
 	defer func() {
 		if stopErr := taskManager.Stop(); stopErr != nil {
-			logger.Debug("Task manager already stopped", "error", stopErr)
+			logging.DebugCategory(logging.CategoryTasks, "Task manager already stopped", "error", stopErr)
 		}
 	}()
 
-	// Register feed refresh handler
-	feedRefreshHandler := tasks.NewFeedRefreshHandler(feedManager)
-	if err := taskManager.RegisterHandler(feedRefreshHandler); err != nil {
-		return fmt.Errorf("failed to register feed refresh handler: %w", err)
+	// A daemon refreshes the feeds for every window
+	var feedRefreshHandler tasks.TaskHandler = tasks.NewFeedRefreshHandler(feedManager, taskManager)
+	if daemonPID != 0 {
+		feedRefreshHandler = newDaemonRefreshHandler(socketPath)
+	}
+	if err := registerTaskHandlers(taskManager, feedManager, queries, feedRefreshHandler); err != nil {
+		return err
+	}
+	// The task manager reloads the feeds that are due, prunes and cleans up
+	// the log on their schedules
+	var refreshJob *tasks.RefreshJob
+	if ownsTasks {
+		taskManager.SetRetryPolicy(tasks.TaskTypeFeedRefresh, cfg.RefreshRetryPolicy())
+		if err := taskManager.RestoreTasks(); err != nil {
+			logger.Warn("Failed to restore unfinished tasks", "error", err)
+		}
+
+		settings := loadSettings(queries)
+		refreshJob = tasks.NewRefreshJob(feedManager, taskManager, settings)
+		taskManager.ScheduleJob(refreshJob.Job())
+		taskManager.ScheduleJob(tasks.LogCleanupJob(settings))
+		if err := tasks.SchedulePrune(taskManager, cfg.PruneSchedule, settings); err != nil {
+			logger.Warn("Ignoring prune schedule", "error", err)
+		}
+	}
+	if !readOnly && cfg.CheckForUpdates {
+		if err := tasks.ScheduleUpdateCheck(taskManager, cfg.UpdateCheckSchedule); err != nil {
+			logger.Warn("Ignoring update check schedule", "error", err)
+		}
+	}
+
+	// Listen for updates pushed by WebSub hubs, each one queues a refresh of its feed
+	if webSub := feeds.NewWebSubConfig(cfg); webSub.Enabled() && !readOnly && daemonPID == 0 {
+		stopWebSub, err := feedManager.StartWebSub(webSub, func(feedID int64, url string) {
+			if err := taskManager.AddTask(tasks.CreatePushedFeedRefreshTask(feedID, url)); err != nil {
+				logger.Warn("Failed to queue pushed feed refresh", "url", url, "error", err)
+			}
+		})
+		if err != nil {
+			logger.Warn("Failed to start WebSub listener", "address", webSub.Listen, "error", err)
+		} else {
+			defer func() {
+				_ = stopWebSub()
+			}()
+		}
 	}
 
 	if err := config.CreateSampleURLsFile(); err != nil {
@@ -186,21 +650,194 @@ func run(urlFile string, debug bool) error {
 		}
 	}
 
-	if err := syncFeedsWithURLsFile(feedManager, queries, urlEntries); err != nil {
-		logger.Warn("Failed to sync feeds with URLs file", "error", err)
+	if cfg.UpdateRedirects && !readOnly && daemonPID == 0 {
+		feedManager.SetRedirectUpdates(urlsPath)
+	}
+
+	// Read-only shows the feeds as the running newsgoat left them
+	if !readOnly {
+		if err := syncFeedsWithURLsFile(feedManager, queries, urlEntries); err != nil {
+			logger.Warn("Failed to sync feeds with URLs file", "error", err)
+		}
+	}
+
+	// Custom themes are loaded before the model renders articles with one
+	if themesDir, err := config.GetThemesDir(); err != nil {
+		logger.Warn("Failed to get themes directory", "error", err)
+	} else {
+		for _, err := range themes.LoadCustomThemes(themesDir) {
+			logger.Warn("Ignoring theme", "error", err)
+		}
 	}
 
 	model := ui.NewModel(feedManager, taskManager, queries, cfg)
 	model.SetURLsFilePath(urlsPath)
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	model.SetFeedDirections(urlEntries)
+	if urlsPath != "" {
+		if queryFeeds, err := config.ReadQueryFeedsFromPath(urlsPath); err != nil {
+			logger.Warn("Failed to read query feeds", "error", err)
+		} else {
+			model.SetQueryFeeds(queryFeeds)
+		}
+	}
+	if readOnly {
+		model.SetReadOnly(readOnlyPID)
+	} else if daemonPID != 0 {
+		model.SetDaemon(daemonPID, socketPath)
+	} else {
+		model.SetRefreshJob(refreshJob)
+	}
+
+	if keysPath, err := config.GetKeysFilePath(); err != nil {
+		logger.Warn("Failed to get key bindings file path", "error", err)
+	} else {
+		keymap, errs := ui.LoadKeymap(keysPath)
+		for _, err := range errs {
+			logger.Warn("Ignoring key binding", "error", err)
+		}
+		model.SetKeymap(keymap, keysPath)
+	}
 
-	if _, err := p.Run(); err != nil {
+	if migrationStatus != "" {
+		logger.Info(migrationStatus)
+		model.SetStatusMessage(migrationStatus)
+	}
+
+	// The terminal is asked for its background before the program reads
+	// its input, only for the auto theme as terminals may be slow to answer
+	if cfg.ThemeName == themes.AutoTheme {
+		model.SetDarkBackground(ui.DetectDarkBackground())
+	}
+	ui.EnablePaletteUpdates(os.Stdout)
+	defer ui.DisablePaletteUpdates(os.Stdout)
+	defer ui.RemoveBrowserFiles()
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
+
+	// Subscribe links and read state changes from the command line are
+	// handed to the running NewsGoat over a socket
+	if !readOnly && daemonPID == 0 {
+		if server := listenForRequests(p, feedManager); server != nil {
+			defer func() {
+				_ = server.Close()
+			}()
+		}
+	}
+
+	// The daemon runs the startup and shutdown hooks once for every window
+	if daemonPID == 0 {
+		eventHooks.Fire(hooks.EventStartup, nil)
+	}
+	_, err = p.Run()
+	if daemonPID == 0 {
+		eventHooks.Fire(hooks.EventShutdown, nil)
+	}
+	eventHooks.Wait(hooksExitTimeout)
+	if err != nil {
 		return fmt.Errorf("failed to run TUI: %w", err)
 	}
 
 	return nil
 }
 
+// registerTaskHandlers registers the handlers of every task type,
+// feedRefreshHandler refreshes feeds here or hands them to a daemon
+func registerTaskHandlers(taskManager tasks.Manager, feedManager *feeds.Manager, queries *database.Queries, feedRefreshHandler tasks.TaskHandler) error {
+	// Register feed refresh handler
+	if err := taskManager.RegisterHandler(feedRefreshHandler); err != nil {
+		return fmt.Errorf("failed to register feed refresh handler: %w", err)
+	}
+
+	// Register reading log export handler
+	readingExportHandler := tasks.NewReadingExportHandler(feedManager)
+	if err := taskManager.RegisterHandler(readingExportHandler); err != nil {
+		return fmt.Errorf("failed to register reading export handler: %w", err)
+	}
+
+	// Register pull request and CI lookup handler
+	itemEnrichmentHandler := tasks.NewItemEnrichmentHandler(feedManager)
+	if err := taskManager.RegisterHandler(itemEnrichmentHandler); err != nil {
+		return fmt.Errorf("failed to register item enrichment handler: %w", err)
+	}
+
+	// Register the handler that looks up the favicons of feeds
+	if err := taskManager.RegisterHandler(tasks.NewFeedIconHandler(feedManager)); err != nil {
+		return fmt.Errorf("failed to register feed icon handler: %w", err)
+	}
+
+	// Register read-later handler, settings are read when an item is sent so
+	// changes in the settings view apply right away
+	readLaterHandler := tasks.NewReadLaterHandler(feedManager, func() feeds.ReadLaterConfig {
+		current, err := config.LoadConfig(queries)
+		if err != nil {
+			logger.Warn("Failed to load read-later settings", "error", err)
+		}
+		return feeds.NewReadLaterConfig(current)
+	})
+	if err := taskManager.RegisterHandler(readLaterHandler); err != nil {
+		return fmt.Errorf("failed to register read-later handler: %w", err)
+	}
+
+	// Register the handler that prunes old items on demand
+	cleanupHandler := tasks.NewCleanupHandler(feedManager)
+	if err := taskManager.RegisterHandler(cleanupHandler); err != nil {
+		return fmt.Errorf("failed to register cleanup handler: %w", err)
+	}
+
+	// Register the handler that deletes old log messages
+	logCleanupHandler := tasks.NewLogCleanupHandler(feedManager)
+	if err := taskManager.RegisterHandler(logCleanupHandler); err != nil {
+		return fmt.Errorf("failed to register log cleanup handler: %w", err)
+	}
+
+	// Register the handlers that look for a newer release and install it
+	if err := taskManager.RegisterHandler(tasks.NewUpdateCheckHandler()); err != nil {
+		return fmt.Errorf("failed to register update check handler: %w", err)
+	}
+	if err := taskManager.RegisterHandler(tasks.NewUpdateInstallHandler()); err != nil {
+		return fmt.Errorf("failed to register update install handler: %w", err)
+	}
+
+	// Register the sync handler, settings are read when a sync starts
+	syncHandler := tasks.NewSyncHandler(feedManager, func() feedsync.Config {
+		current, err := config.LoadConfig(queries)
+		if err != nil {
+			logger.Warn("Failed to load sync settings", "error", err)
+		}
+		return feedsync.NewConfig(current)
+	})
+	if err := taskManager.RegisterHandler(syncHandler); err != nil {
+		return fmt.Errorf("failed to register sync handler: %w", err)
+	}
+
+	return nil
+}
+
+// loadSettings returns a function reading the settings from the database,
+// scheduled jobs call it on every run so changes apply right away
+func loadSettings(queries *database.Queries) func() config.Config {
+	return func() config.Config {
+		current, err := config.LoadConfig(queries)
+		if err != nil {
+			logger.Warn("Failed to load settings", "error", err)
+		}
+		return current
+	}
+}
+
+// loadHooks loads the hooks file, invalid lines are logged and skipped
+func loadHooks() *hooks.Hooks {
+	hooksPath, err := config.GetHooksFilePath()
+	if err != nil {
+		logger.Warn("Failed to get hooks file path", "error", err)
+		return nil
+	}
+	eventHooks, errs := hooks.Load(hooksPath)
+	for _, err := range errs {
+		logger.Warn("Ignoring hook", "error", err)
+	}
+	return eventHooks
+}
+
 func syncFeedsWithURLsFile(feedManager *feeds.Manager, queries *database.Queries, urlEntries []config.URLEntry) error {
 	// Get all feeds from database (including hidden ones)
 	allFeeds, err := feedManager.GetAllFeeds()
@@ -214,6 +851,23 @@ func syncFeedsWithURLsFile(feedManager *feeds.Manager, queries *database.Queries
 		urlsFromFileSet[entry.URL] = entry
 	}
 
+	// Credentials stay in memory, they are never written to the database
+	feedAuths, errs := config.FeedAuths(urlEntries)
+	for _, err := range errs {
+		logger.Warn("Ignoring auth option", "error", err)
+	}
+	feedManager.SetFeedAuths(feedAuths)
+	requestOptions, errs := config.FeedRequestOptions(urlEntries)
+	for _, err := range errs {
+		logger.Warn("Ignoring request options", "error", err)
+	}
+	feedManager.SetFeedRequestOptions(requestOptions)
+	feedNotify, errs := config.FeedNotify(urlEntries)
+	for _, err := range errs {
+		logger.Warn("Ignoring notify option", "error", err)
+	}
+	feedManager.SetFeedNotify(feedNotify)
+
 	// Create a set of URLs from DB for quick lookup
 	urlsFromDBSet := make(map[string]bool)
 	for _, feed := range allFeeds {
@@ -276,6 +930,27 @@ func syncFeedsWithURLsFile(feedManager *feeds.Manager, queries *database.Queries
 				logger.Warn("Failed to add folder", "feed_id", feedID, "folder", folder, "error", err)
 			}
 		}
+
+		// Apply per-feed options
+		if err := feedManager.SetFeedFullText(feedID, entry.HasOption(config.OptionFullText)); err != nil {
+			logger.Warn("Failed to update full-text option", "feed_id", feedID, "error", err)
+		}
+		reloadInterval, err := entry.ReloadInterval()
+		if err != nil {
+			logger.Warn("Ignoring reload interval option", "url", entry.URL, "error", err)
+		}
+		if err := feedManager.SetFeedReloadInterval(feedID, reloadInterval); err != nil {
+			logger.Warn("Failed to update reload interval option", "feed_id", feedID, "error", err)
+		}
+		if err := feedManager.SetFeedEnrich(feedID, entry.HasOption(config.OptionEnrich)); err != nil {
+			logger.Warn("Failed to update enrich option", "feed_id", feedID, "error", err)
+		}
+		if err := feedManager.SetFeedPaused(feedID, entry.HasOption(config.OptionPause)); err != nil {
+			logger.Warn("Failed to update pause option", "feed_id", feedID, "error", err)
+		}
+		if err := feedManager.SetFeedCustomTitle(feedID, entry.Title); err != nil {
+			logger.Warn("Failed to update custom title", "feed_id", feedID, "error", err)
+		}
 	}
 
 	return nil
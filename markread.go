@@ -0,0 +1,164 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/ipc"
+)
+
+// markReadCommand marks every item of a feed, or of all feeds, read
+func markReadCommand(args []string) error {
+	flags := flag.NewFlagSet("mark-read", flag.ExitOnError)
+	all := flags.Bool("all", false, "Mark the items of every feed read")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: newsgoat mark-read <feed-url|--all>\n\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *all == (flags.NArg() == 1) || flags.NArg() > 1 {
+		flags.Usage()
+		return fmt.Errorf("'mark-read' requires a feed URL or --all")
+	}
+	return sendOrRun(ipc.Request{Command: ipc.CommandMarkRead, URL: flags.Arg(0), All: *all})
+}
+
+// markItemReadCommand marks items read by the ids newsgoat items prints
+func markItemReadCommand(args []string) error {
+	flags := flag.NewFlagSet("mark-item-read", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: newsgoat mark-item-read <item-id>...\n\n")
+		fmt.Fprintf(os.Stderr, "Item ids are the first column of newsgoat items.\n")
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return fmt.Errorf("'mark-item-read' requires an item id")
+	}
+	itemIDs := make([]int64, 0, flags.NArg())
+	for _, arg := range flags.Args() {
+		itemID, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil || itemID <= 0 {
+			return fmt.Errorf("invalid item id %q", arg)
+		}
+		itemIDs = append(itemIDs, itemID)
+	}
+	return sendOrRun(ipc.Request{Command: ipc.CommandMarkItemRead, ItemIDs: itemIDs})
+}
+
+// sendOrRun hands a request to the running NewsGoat, so its feed manager
+// changes the database and its lists are reloaded. Without one the request
+// is run here, holding the lock like NewsGoat does.
+func sendOrRun(request ipc.Request) error {
+	path, err := config.GetSocketFilePath()
+	if err != nil {
+		return err
+	}
+	message, err := ipc.Send(path, request)
+	if !errors.Is(err, ipc.ErrNotRunning) {
+		if err == nil {
+			fmt.Println(message)
+		}
+		return err
+	}
+
+	lock, err := config.AcquireLock()
+	var locked *config.LockedError
+	if errors.As(err, &locked) {
+		return fmt.Errorf("newsgoat (pid %d) is running but doesn't accept requests on %s, restart it", locked.PID, path)
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = lock.Release()
+	}()
+
+	db, queries, err := database.InitDB()
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+	if err := RunMigrations(db); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+	setupLogging(queries, false)
+
+	feedManager := feeds.NewManager(db, queries)
+	eventHooks := loadHooks()
+	feedManager.SetHooks(eventHooks)
+	message, err = markRead(feedManager, request)
+	eventHooks.Wait(hooksExitTimeout)
+	if err != nil {
+		return err
+	}
+	fmt.Println(message)
+	return nil
+}
+
+// markRead runs a mark-read or mark-item-read request with the feed manager
+func markRead(feedManager *feeds.Manager, request ipc.Request) (string, error) {
+	if request.Command == ipc.CommandMarkItemRead {
+		for _, itemID := range request.ItemIDs {
+			if _, err := feedManager.GetItem(itemID); errors.Is(err, sql.ErrNoRows) {
+				return "", fmt.Errorf("no item with the id %d, see newsgoat items", itemID)
+			} else if err != nil {
+				return "", err
+			}
+		}
+		// One by one so item-read hooks run like when they are read in NewsGoat
+		for _, itemID := range request.ItemIDs {
+			if err := feedManager.MarkItemRead(itemID); err != nil {
+				return "", err
+			}
+		}
+		return fmt.Sprintf("Marked %d items read", len(request.ItemIDs)), nil
+	}
+
+	var feedIDs []int64
+	if request.All {
+		stats, err := feedManager.GetFeedStats()
+		if err != nil {
+			return "", err
+		}
+		for _, feed := range stats {
+			feedIDs = append(feedIDs, feed.ID)
+		}
+	} else {
+		allFeeds, err := feedManager.GetAllFeeds()
+		if err != nil {
+			return "", err
+		}
+		for _, feed := range allFeeds {
+			if feed.Url == request.URL {
+				feedIDs = append(feedIDs, feed.ID)
+			}
+		}
+		if len(feedIDs) == 0 {
+			return "", fmt.Errorf("no feed with the URL %s, see newsgoat list", request.URL)
+		}
+	}
+
+	marked := 0
+	for _, feedID := range feedIDs {
+		itemIDs, err := feedManager.MarkAllItemsReadInFeed(feedID)
+		if err != nil {
+			return "", err
+		}
+		marked += len(itemIDs)
+	}
+	return fmt.Sprintf("Marked %d items read", marked), nil
+}
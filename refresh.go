@@ -0,0 +1,194 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/database"
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/ipc"
+	"github.com/jarv/newsgoat/internal/logging"
+	feedsync "github.com/jarv/newsgoat/internal/sync"
+)
+
+// refreshResult is the outcome of refreshing one feed
+type refreshResult struct {
+	feed database.GetFeedStatsRow
+	err  error
+}
+
+// refreshFeeds refreshes every feed of the URLs file without starting the
+// UI, for cron jobs and systemd timers. It fails when any feed failed.
+func refreshFeeds(args []string, urlFile string, debug bool) error {
+	flags := flag.NewFlagSet("refresh", flag.ExitOnError)
+	quiet := flags.Bool("quiet", false, "Only print the feeds that failed")
+	flags.BoolVar(quiet, "q", false, "Only print the feeds that failed")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: newsgoat refresh [--quiet]\n\n")
+		fmt.Fprintf(os.Stderr, "Refreshes every feed that isn't paused and exits non-zero when one failed.\n\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return fmt.Errorf("'refresh' takes no arguments")
+	}
+
+	// A running NewsGoat refreshes the feeds itself, which isn't a failure
+	lock, err := config.AcquireLock()
+	var locked *config.LockedError
+	if errors.As(err, &locked) {
+		// A daemon refreshes them now, the UI on its own schedule
+		if daemonPID, path := runningDaemon(); daemonPID != 0 {
+			message, err := ipc.SendTimeout(path, ipc.Request{Command: ipc.CommandRefresh, All: true}, daemonRefreshTimeout)
+			if err == nil && !*quiet {
+				fmt.Println(message)
+			}
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Skipping refresh, NewsGoat is running (pid %d) and refreshes the feeds itself\n", locked.PID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = lock.Release()
+	}()
+
+	db, queries, err := database.InitDB()
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+	if err := RunMigrations(db); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	cfg, err := config.LoadConfig(queries)
+	if err != nil {
+		cfg = config.GetDefaultConfig()
+	}
+	setupLogging(queries, debug)
+	feeds.NewReadLaterConfig(cfg).RegisterSecrets()
+	feedsync.NewConfig(cfg).RegisterSecrets()
+
+	feedManager := feeds.NewManager(db, queries)
+	feedManager.SetRequestOptions(cfg.RequestOptions())
+	feedManager.SetRetentionPolicy(cfg.RetentionPolicy())
+	feedManager.SetHostLimits(cfg.HostLimits())
+	feedManager.SetHistoryPages(cfg.FeedHistoryPages)
+	eventHooks := loadHooks()
+	feedManager.SetHooks(eventHooks)
+	feedManager.SetNotifyPolicy(cfg.NotifyPolicy())
+
+	var urlEntries []config.URLEntry
+	if urlFile != "" {
+		urlEntries, err = config.ReadURLsFileFromPath(urlFile)
+	} else {
+		urlEntries, err = config.ReadURLsFile()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read URLs file: %w", err)
+	}
+	if cfg.UpdateRedirects {
+		urlsPath := urlFile
+		if urlsPath == "" {
+			urlsPath, _ = config.GetURLsFilePath()
+		}
+		feedManager.SetRedirectUpdates(urlsPath)
+	}
+	if err := syncFeedsWithURLsFile(feedManager, queries, urlEntries); err != nil {
+		return fmt.Errorf("failed to sync feeds with URLs file: %w", err)
+	}
+
+	before, err := feedManager.GetFeedStats()
+	if err != nil {
+		return fmt.Errorf("failed to get feeds: %w", err)
+	}
+	results, paused := refreshConcurrently(feedManager, before, cfg.ReloadConcurrency)
+	feedManager.FlushNotifications()
+	eventHooks.Wait(hooksExitTimeout)
+
+	// Items that weren't there before the refresh are new, pruning may
+	// remove some of the old ones
+	totalBefore := make(map[int64]int64, len(before))
+	for _, feed := range before {
+		totalBefore[feed.ID] = feed.TotalItems
+	}
+	after, err := feedManager.GetFeedStats()
+	if err != nil {
+		return fmt.Errorf("failed to get feeds: %w", err)
+	}
+	var newItems int64
+	for _, feed := range after {
+		if added := feed.TotalItems - totalBefore[feed.ID]; added > 0 {
+			newItems += added
+		}
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.err == nil {
+			continue
+		}
+		failed++
+		name := logging.Redact(result.feed.Url)
+		if result.feed.Title != "" && result.feed.Title != result.feed.Url {
+			name = fmt.Sprintf("%s (%s)", result.feed.Title, name)
+		}
+		fmt.Fprintf(os.Stderr, "Failed to refresh %s: %v\n", name, result.err)
+	}
+	if !*quiet {
+		fmt.Printf("Refreshed %d feeds, %d new items", len(results)-failed, newItems)
+		if paused > 0 {
+			fmt.Printf(", %d paused feeds skipped", paused)
+		}
+		fmt.Println()
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d feeds failed to refresh", failed, len(results))
+	}
+	return nil
+}
+
+// refreshConcurrently refreshes the feeds that aren't paused, at most
+// concurrency at a time like reloading in the UI, and returns how many were
+// paused
+func refreshConcurrently(feedManager *feeds.Manager, feedStats []database.GetFeedStatsRow, concurrency int) ([]refreshResult, int) {
+	concurrency = max(concurrency, 1)
+	queue := make(chan int, len(feedStats))
+	results := make([]refreshResult, 0, len(feedStats))
+	paused := 0
+	for _, feed := range feedStats {
+		if feed.Paused {
+			paused++
+			continue
+		}
+		results = append(results, refreshResult{feed: feed})
+		queue <- len(results) - 1
+	}
+	close(queue)
+
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				results[i].err = feedManager.RefreshFeed(results[i].feed.ID)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results, paused
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jarv/newsgoat/internal/config"
+	"github.com/jarv/newsgoat/internal/feeds"
+	"github.com/jarv/newsgoat/internal/ipc"
+	"github.com/jarv/newsgoat/internal/ui"
+)
+
+// listenForRequests accepts the requests other newsgoat commands send while
+// the UI runs: feeds of subscribe links are added like pressing u, and read
+// state changes go through the feed manager that owns the database
+func listenForRequests(p *tea.Program, feedManager *feeds.Manager) *ipc.Server {
+	path, err := config.GetSocketFilePath()
+	if err != nil {
+		logger.Warn("Failed to get socket path", "error", err)
+		return nil
+	}
+	server, err := ipc.Listen(path, func(request ipc.Request) (string, error) {
+		switch request.Command {
+		case ipc.CommandAdd:
+			if request.URL == "" {
+				return "", fmt.Errorf("no feed URL to add")
+			}
+			p.Send(ui.AddURLMsg{URL: request.URL})
+			return fmt.Sprintf("Sent %s to the running NewsGoat", request.URL), nil
+		case ipc.CommandMarkRead, ipc.CommandMarkItemRead:
+			message, err := markRead(feedManager, request)
+			if err == nil {
+				p.Send(ui.ReadStateChangedMsg{})
+			}
+			return message, err
+		}
+		return "", fmt.Errorf("unknown request %q", request.Command)
+	})
+	if err != nil {
+		logger.Warn("Failed to listen for requests", "path", path, "error", err)
+		return nil
+	}
+	return server
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jarv/newsgoat/internal/secrets"
+	"golang.org/x/term"
+)
+
+// secretCommand stores or deletes secrets the URLs file and the Sync Token
+// setting refer to with keyring:name
+func secretCommand(args []string) error {
+	usage := func() {
+		fmt.Fprintf(os.Stderr, "Usage: newsgoat secret set <name>\n")
+		fmt.Fprintf(os.Stderr, "       newsgoat secret delete <name>\n\n")
+		fmt.Fprintf(os.Stderr, "set reads the secret from stdin and stores it in the OS keychain, or an\n")
+		fmt.Fprintf(os.Stderr, "encrypted file without one. Use it as keyring:<name>, e.g. !auth=bearer:keyring:<name>\n")
+	}
+	if len(args) != 2 {
+		usage()
+		return fmt.Errorf("'secret' requires set or delete and a name")
+	}
+
+	name := args[1]
+	switch args[0] {
+	case "set":
+		value, err := readSecret(name)
+		if err != nil {
+			return err
+		}
+		if value == "" {
+			return fmt.Errorf("the secret is empty")
+		}
+		if err := secrets.Set(name, value); err != nil {
+			return err
+		}
+		fmt.Printf("Stored %s, use it as %s\n", name, secrets.Reference(name))
+	case "delete":
+		if err := secrets.Delete(name); err != nil {
+			return err
+		}
+		fmt.Printf("Deleted %s\n", name)
+	default:
+		usage()
+		return fmt.Errorf("unknown secret command '%s'", args[0])
+	}
+	return nil
+}
+
+// readSecret reads a secret from stdin, without echoing it on a terminal
+func readSecret(name string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		fmt.Fprintf(os.Stderr, "Secret for %s: ", name)
+		value, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		return strings.TrimSpace(string(value)), err
+	}
+	value, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && value == "" {
+		return "", err
+	}
+	return strings.TrimSpace(value), nil
+}
@@ -0,0 +1,3 @@
+ALTER TABLE feeds ADD COLUMN full_text BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE items ADD COLUMN full_content TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+ALTER TABLE items ADD COLUMN starred BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_items_starred ON items(starred);
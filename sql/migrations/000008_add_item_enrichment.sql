@@ -0,0 +1,6 @@
+ALTER TABLE feeds ADD COLUMN enrich BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE items ADD COLUMN pr_number INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE items ADD COLUMN pr_state TEXT NOT NULL DEFAULT '';
+ALTER TABLE items ADD COLUMN ci_state TEXT NOT NULL DEFAULT '';
+ALTER TABLE items ADD COLUMN enriched_at DATETIME;
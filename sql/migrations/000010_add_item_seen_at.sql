@@ -0,0 +1,3 @@
+-- When an item was last in a fetch of its feed, pruning skips items the feed
+-- still publishes. Existing items count as seen until their feed is fetched.
+ALTER TABLE items ADD COLUMN seen_at DATETIME;
@@ -0,0 +1,30 @@
+-- Full-text index of item titles, descriptions and content for global search.
+-- The index reads the text from items, the triggers keep it in sync.
+CREATE VIRTUAL TABLE IF NOT EXISTS items_fts USING fts5(
+    title,
+    description,
+    content,
+    content='items',
+    content_rowid='id',
+    tokenize='unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER IF NOT EXISTS items_fts_insert AFTER INSERT ON items BEGIN
+    INSERT INTO items_fts(rowid, title, description, content)
+    VALUES (new.id, new.title, new.description, new.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS items_fts_delete AFTER DELETE ON items BEGIN
+    INSERT INTO items_fts(items_fts, rowid, title, description, content)
+    VALUES ('delete', old.id, old.title, old.description, old.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS items_fts_update AFTER UPDATE OF title, description, content ON items BEGIN
+    INSERT INTO items_fts(items_fts, rowid, title, description, content)
+    VALUES ('delete', old.id, old.title, old.description, old.content);
+    INSERT INTO items_fts(rowid, title, description, content)
+    VALUES (new.id, new.title, new.description, new.content);
+END;
+
+-- Index the existing items
+INSERT INTO items_fts(items_fts) VALUES ('rebuild');
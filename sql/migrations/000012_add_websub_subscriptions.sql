@@ -0,0 +1,10 @@
+-- WebSub subscriptions of feeds whose hub pushes updates. lease_expires is
+-- NULL until the hub has verified the subscription.
+CREATE TABLE IF NOT EXISTS websub_subscriptions (
+    feed_id INTEGER PRIMARY KEY,
+    hub TEXT NOT NULL,
+    topic TEXT NOT NULL,
+    requested_at DATETIME NOT NULL,
+    lease_expires DATETIME,
+    FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+);
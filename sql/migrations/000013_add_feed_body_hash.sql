@@ -0,0 +1,4 @@
+-- Hash of the last feed body, a server that ignores conditional requests and
+-- sends the same body again is treated as 304 Not Modified
+ALTER TABLE feeds ADD COLUMN body_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE feeds ADD COLUMN unchanged_fetches INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,19 @@
+-- Entries of a sync service matched to local items. read and starred are the
+-- state both sides agreed on at the last sync, so a change on either side
+-- can be told apart from a change on the other. item_id is NULL until the
+-- item has been fetched locally.
+CREATE TABLE IF NOT EXISTS sync_entries (
+    service TEXT NOT NULL,
+    remote_id TEXT NOT NULL,
+    item_id INTEGER,
+    feed_url TEXT NOT NULL,
+    url TEXT NOT NULL,
+    read BOOLEAN NOT NULL,
+    starred BOOLEAN NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (service, remote_id),
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_entries_item_id ON sync_entries(item_id);
+CREATE INDEX IF NOT EXISTS idx_items_link ON items(link);
@@ -0,0 +1,2 @@
+-- Author of an item as its feed names them, shown in the item list format
+ALTER TABLE items ADD COLUMN author TEXT NOT NULL DEFAULT '';
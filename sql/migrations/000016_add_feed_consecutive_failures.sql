@@ -0,0 +1,3 @@
+-- Fetches of a feed that failed in a row, a feed failing for long enough is
+-- shown as dead
+ALTER TABLE feeds ADD COLUMN consecutive_failures INTEGER NOT NULL DEFAULT 0;
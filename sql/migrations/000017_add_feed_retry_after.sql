@@ -0,0 +1,3 @@
+-- Earliest time a rate limited feed may be fetched again, from the
+-- Retry-After header of a 429 or 503 response
+ALTER TABLE feeds ADD COLUMN retry_after DATETIME;
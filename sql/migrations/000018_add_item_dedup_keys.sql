@@ -0,0 +1,7 @@
+-- Keys of items to find the same article published by several feeds, the
+-- link without tracking parameters and a hash of the normalized content
+ALTER TABLE items ADD COLUMN canonical_link TEXT NOT NULL DEFAULT '';
+ALTER TABLE items ADD COLUMN content_hash TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_items_canonical_link ON items(canonical_link);
+CREATE INDEX IF NOT EXISTS idx_items_content_hash ON items(content_hash);
@@ -0,0 +1,13 @@
+-- Tasks that haven't finished, queued again when NewsGoat starts after a
+-- crash or restart
+CREATE TABLE IF NOT EXISTS tasks (
+    id TEXT PRIMARY KEY,
+    type TEXT NOT NULL,
+    status TEXT NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    data TEXT NOT NULL DEFAULT '{}', -- JSON of the task's data
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_retry DATETIME, -- NULL unless a failed task waits to run again
+    error TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL
+);
@@ -0,0 +1,7 @@
+-- Colors of the favicons of the sites feeds come from, by the host of the
+-- feed URL
+CREATE TABLE IF NOT EXISTS feed_icons (
+    host TEXT PRIMARY KEY,
+    color TEXT NOT NULL DEFAULT '', -- Main color as #rrggbb, empty when the site has no icon
+    fetched_at DATETIME NOT NULL
+);
@@ -0,0 +1,11 @@
+-- Rules hiding or marking read the new items of a refresh whose title,
+-- author or link match a pattern
+CREATE TABLE IF NOT EXISTS item_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    feed_id INTEGER, -- NULL for rules applying to every feed
+    field TEXT NOT NULL, -- title, author or link
+    pattern TEXT NOT NULL, -- Regular expression
+    action TEXT NOT NULL, -- hide or read
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+);
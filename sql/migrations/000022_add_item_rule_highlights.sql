@@ -0,0 +1,5 @@
+-- How highlight rules mark matching items in the item list, and whether new
+-- matching items send a desktop notification
+ALTER TABLE item_rules ADD COLUMN color TEXT NOT NULL DEFAULT '';
+ALTER TABLE item_rules ADD COLUMN marker TEXT NOT NULL DEFAULT '';
+ALTER TABLE item_rules ADD COLUMN notify BOOLEAN NOT NULL DEFAULT FALSE;
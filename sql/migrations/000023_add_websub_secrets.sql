@@ -0,0 +1,3 @@
+-- The secret hubs sign pushed updates with, '' for subscriptions requested
+-- before pushes were signed
+ALTER TABLE websub_subscriptions ADD COLUMN secret TEXT NOT NULL DEFAULT '';
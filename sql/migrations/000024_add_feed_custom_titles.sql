@@ -0,0 +1,3 @@
+-- The title set in the URLs file or feed info, shown instead of the one the
+-- feed has, '' for the feed's own
+ALTER TABLE feeds ADD COLUMN custom_title TEXT NOT NULL DEFAULT '';
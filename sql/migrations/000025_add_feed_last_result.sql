@@ -0,0 +1,3 @@
+-- What the last successful fetch of a feed got, e.g. 304 Not Modified, shown
+-- in feed info
+ALTER TABLE feeds ADD COLUMN last_result TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,12 @@
+-- Every time an item is read, for the reading statistics
+CREATE TABLE IF NOT EXISTS read_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    item_id INTEGER, -- NULL once the item is pruned
+    feed_id INTEGER NOT NULL,
+    read_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at DATETIME, -- When the item was published, or first seen when the feed doesn't date it
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE SET NULL,
+    FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_read_events_read_at ON read_events(read_at);
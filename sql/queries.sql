@@ -10,26 +10,61 @@ SELECT * FROM feeds WHERE id = ?;
 SELECT * FROM feeds WHERE url = ?;
 
 -- name: ListFeeds :many
-SELECT * FROM feeds WHERE visible = TRUE ORDER BY title;
+SELECT * FROM feeds WHERE visible = TRUE ORDER BY fold(title);
 
 -- name: ListAllFeeds :many
-SELECT * FROM feeds ORDER BY title;
+SELECT * FROM feeds ORDER BY fold(title);
 
 -- name: UpdateFeed :exec
+-- A custom title stays
 UPDATE feeds
-SET title = ?, description = ?, last_updated = ?, etag = ?, last_modified = ?, cache_control_max_age = ?
+SET title = CASE WHEN custom_title = '' THEN ? ELSE custom_title END, description = ?, last_updated = ?, etag = ?, last_modified = ?, cache_control_max_age = ?
 WHERE id = ?;
 
 -- name: UpdateFeedError :exec
 UPDATE feeds
-SET last_error = ?, last_error_time = ?
+SET last_error = ?, last_error_time = ?, consecutive_failures = consecutive_failures + 1
 WHERE id = ?;
 
 -- name: ClearFeedError :exec
 UPDATE feeds
-SET last_error = NULL, last_error_time = NULL
+SET last_error = NULL, last_error_time = NULL, consecutive_failures = 0
 WHERE id = ?;
 
+-- name: SetFeedRetryAfter :exec
+UPDATE feeds SET retry_after = ? WHERE id = ?;
+
+-- name: UpdateFeedURL :exec
+-- A new URL starts over, the cache headers and failures were the old one's
+UPDATE feeds
+SET url = ?, etag = NULL, last_modified = NULL, cache_control_max_age = NULL, body_hash = '', unchanged_fetches = 0,
+    last_error = NULL, last_error_time = NULL, consecutive_failures = 0
+WHERE id = ?;
+
+-- name: SetFeedCustomTitle :exec
+-- Clearing the title starts the cache over, the next refresh parses the feed
+-- again for its own title
+UPDATE feeds
+SET title = CASE WHEN sqlc.arg(custom_title) = '' THEN title ELSE sqlc.arg(custom_title) END,
+    etag = CASE WHEN sqlc.arg(custom_title) = '' AND custom_title != '' THEN NULL ELSE etag END,
+    last_modified = CASE WHEN sqlc.arg(custom_title) = '' AND custom_title != '' THEN NULL ELSE last_modified END,
+    cache_control_max_age = CASE WHEN sqlc.arg(custom_title) = '' AND custom_title != '' THEN NULL ELSE cache_control_max_age END,
+    body_hash = CASE WHEN sqlc.arg(custom_title) = '' AND custom_title != '' THEN '' ELSE body_hash END,
+    custom_title = sqlc.arg(custom_title)
+WHERE id = sqlc.arg(id);
+
+-- name: SetFeedLastResult :exec
+UPDATE feeds SET last_result = ? WHERE id = ?;
+
+-- name: ClearFeedCache :exec
+-- The next refresh fetches the whole feed again, without conditional headers
+UPDATE feeds
+SET etag = NULL, last_modified = NULL, cache_control_max_age = NULL, body_hash = ''
+WHERE id = ?;
+
+-- name: CountFeedItems :one
+SELECT COUNT(*) FROM items WHERE feed_id = ?;
+
 -- name: DeleteFeed :exec
 DELETE FROM feeds WHERE id = ?;
 
@@ -53,6 +88,17 @@ RETURNING *;
 -- name: GetItem :one
 SELECT * FROM items WHERE id = ?;
 
+-- name: GetItemCopies :many
+-- Items of other feeds that are the same article, with the same canonical
+-- link or content hash
+SELECT i.id, i.feed_id, f.title as feed_title
+FROM items i
+JOIN feeds f ON i.feed_id = f.id
+WHERE i.feed_id != sqlc.arg(feed_id)
+  AND ((sqlc.arg(canonical_link) != '' AND i.canonical_link = sqlc.arg(canonical_link))
+    OR (sqlc.arg(content_hash) != '' AND i.content_hash = sqlc.arg(content_hash)))
+ORDER BY f.title, i.id;
+
 -- name: ListItemsByFeed :many
 SELECT * FROM items
 WHERE feed_id = ?
@@ -62,14 +108,18 @@ ORDER BY published DESC;
 DELETE FROM items WHERE feed_id = ?;
 
 -- name: UpsertItem :one
-INSERT INTO items (feed_id, guid, title, description, content, link, published)
-VALUES (?, ?, ?, ?, ?, ?, ?)
+INSERT INTO items (feed_id, guid, title, description, content, link, published, seen_at, author, canonical_link, content_hash)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 ON CONFLICT(feed_id, guid) DO UPDATE SET
     title = excluded.title,
     description = excluded.description,
     content = excluded.content,
     link = excluded.link,
-    published = excluded.published
+    published = excluded.published,
+    seen_at = excluded.seen_at,
+    author = excluded.author,
+    canonical_link = excluded.canonical_link,
+    content_hash = excluded.content_hash
 RETURNING *;
 
 -- name: MarkItemRead :exec
@@ -86,6 +136,20 @@ ON CONFLICT(item_id) DO UPDATE SET
     read = FALSE,
     read_at = NULL;
 
+-- name: GetUnreadFeedIDs :many
+-- The visible feeds with unread items, for jumping to the next unread item
+SELECT DISTINCT i.feed_id
+FROM items i
+JOIN feeds f ON i.feed_id = f.id
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE f.visible = TRUE AND COALESCE(rs.read, FALSE) = FALSE;
+
+-- name: GetUnreadItemIDsInFeed :many
+SELECT i.id
+FROM items i
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE i.feed_id = ? AND COALESCE(rs.read, FALSE) = FALSE;
+
 -- name: MarkAllItemsReadInFeed :exec
 INSERT INTO read_status (item_id, read, read_at)
 SELECT i.id, TRUE, CURRENT_TIMESTAMP
@@ -100,6 +164,51 @@ SELECT COALESCE(read, FALSE) as read
 FROM read_status
 WHERE item_id = ?;
 
+-- name: CreateReadEvent :exec
+-- Records an item being read, unless it already is
+INSERT INTO read_events (item_id, feed_id, published_at)
+SELECT i.id, i.feed_id, COALESCE(i.published, i.created_at)
+FROM items i
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE i.id = ? AND COALESCE(rs.read, FALSE) = FALSE;
+
+-- name: GetReadEventsSince :many
+SELECT read_at, published_at
+FROM read_events
+WHERE datetime(read_at) >= datetime('now', printf('-%d days', sqlc.arg(days)))
+ORDER BY read_at;
+
+-- name: GetFeedActivity :many
+-- new_items counts the items first seen and reads the items read in the last
+-- days, the busiest feeds first
+SELECT
+    f.id,
+    f.title,
+    (SELECT COUNT(*) FROM items i WHERE i.feed_id = f.id AND datetime(i.created_at) >= datetime('now', printf('-%d days', sqlc.arg(days)))) AS new_items,
+    (SELECT COUNT(*) FROM read_events re WHERE re.feed_id = f.id AND datetime(re.read_at) >= datetime('now', printf('-%d days', sqlc.arg(days)))) AS reads
+FROM feeds f
+WHERE f.visible = TRUE
+ORDER BY new_items DESC, reads DESC, f.title
+LIMIT sqlc.arg(limit);
+
+-- name: CountUnreadItems :one
+SELECT COUNT(*)
+FROM items i
+JOIN feeds f ON i.feed_id = f.id
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE f.visible = TRUE AND COALESCE(rs.read, FALSE) = FALSE;
+
+-- name: GetBacklogChanges :many
+-- The items first seen or read in the last days, to work the unread backlog
+-- of earlier days out from the current one
+SELECT i.created_at, rs.read_at
+FROM items i
+JOIN feeds f ON i.feed_id = f.id
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE f.visible = TRUE
+  AND (datetime(i.created_at) >= datetime('now', printf('-%d days', sqlc.arg(days)))
+       OR (rs.read AND datetime(rs.read_at) >= datetime('now', printf('-%d days', sqlc.arg(days)))));
+
 -- name: GetFeedStats :many
 SELECT
     f.id,
@@ -107,14 +216,33 @@ SELECT
     f.url,
     f.last_error,
     f.last_error_time,
+    f.paused,
+    f.consecutive_failures,
     COUNT(i.id) as total_items,
     COUNT(CASE WHEN i.id IS NOT NULL AND COALESCE(rs.read, FALSE) = FALSE THEN 1 END) as unread_items
 FROM feeds f
 LEFT JOIN items i ON f.id = i.feed_id
 LEFT JOIN read_status rs ON i.id = rs.item_id
 WHERE f.visible = TRUE
-GROUP BY f.id, f.title, f.url, f.last_error, f.last_error_time
-ORDER BY f.title;
+GROUP BY f.id, f.title, f.url, f.last_error, f.last_error_time, f.paused, f.consecutive_failures
+ORDER BY fold(f.title);
+
+-- name: GetFeedStatsByID :one
+SELECT
+    f.id,
+    f.title,
+    f.url,
+    f.last_error,
+    f.last_error_time,
+    f.paused,
+    f.consecutive_failures,
+    COUNT(i.id) as total_items,
+    COUNT(CASE WHEN i.id IS NOT NULL AND COALESCE(rs.read, FALSE) = FALSE THEN 1 END) as unread_items
+FROM feeds f
+LEFT JOIN items i ON f.id = i.feed_id
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE f.id = ? AND f.visible = TRUE
+GROUP BY f.id, f.title, f.url, f.last_error, f.last_error_time, f.paused, f.consecutive_failures;
 
 -- name: GetItemsWithReadStatus :many
 SELECT
@@ -125,6 +253,47 @@ LEFT JOIN read_status rs ON i.id = rs.item_id
 WHERE i.feed_id = ?
 ORDER BY i.published DESC;
 
+-- name: GetItemsWithReadStatusPage :many
+-- Unread items first when unread_first is set, a negative limit returns all the rest
+SELECT
+    i.*,
+    COALESCE(rs.read, FALSE) as read
+FROM items i
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE i.feed_id = sqlc.arg(feed_id)
+ORDER BY CASE WHEN sqlc.arg(unread_first) THEN COALESCE(rs.read, FALSE) ELSE FALSE END, i.published DESC, i.id DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: GetAllItemsWithReadStatus :many
+SELECT
+    i.*,
+    COALESCE(rs.read, FALSE) as read
+FROM items i
+JOIN feeds f ON i.feed_id = f.id
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE f.visible = TRUE
+ORDER BY i.published DESC;
+
+-- name: GetAllItemsWithReadStatusPage :many
+SELECT
+    i.*,
+    COALESCE(rs.read, FALSE) as read
+FROM items i
+JOIN feeds f ON i.feed_id = f.id
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE f.visible = TRUE
+ORDER BY CASE WHEN sqlc.arg(unread_first) THEN COALESCE(rs.read, FALSE) ELSE FALSE END, i.published DESC, i.id DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: GetAllItemHeaders :many
+SELECT
+    i.id, i.feed_id, i.title, i.link, i.published, i.created_at, i.starred,
+    COALESCE(rs.read, FALSE) as read
+FROM items i
+JOIN feeds f ON i.feed_id = f.id
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE f.visible = TRUE;
+
 -- name: CreateLogMessage :exec
 INSERT INTO log_messages (level, message, timestamp, attributes)
 VALUES (?, ?, ?, ?);
@@ -132,8 +301,52 @@ VALUES (?, ?, ?, ?);
 -- name: GetLogMessages :many
 SELECT id, level, message, timestamp, attributes
 FROM log_messages
-ORDER BY timestamp DESC
-LIMIT ?;
+ORDER BY timestamp DESC, id DESC
+LIMIT ? OFFSET ?;
+
+-- name: CountLogMessages :one
+SELECT COUNT(*) FROM log_messages;
+
+-- name: GetLogStorage :one
+-- Number of log messages and the bytes their values take up, without the
+-- database's own overhead
+SELECT COUNT(*) AS messages,
+       CAST(COALESCE(SUM(length(CAST(level AS BLOB)) + length(CAST(message AS BLOB))
+                         + length(CAST(COALESCE(timestamp, '') AS BLOB))
+                         + length(CAST(COALESCE(attributes, '') AS BLOB))), 0) AS INTEGER) AS bytes
+FROM log_messages;
+
+-- name: GetFilteredLogMessages :many
+-- Log messages of the levels not in hidden_levels (",ERROR,DEBUG,"), whose
+-- message or error contains search and that are about the feed, when feed_id
+-- isn't 0, newest first
+SELECT id, level, message, timestamp, attributes
+FROM log_messages
+WHERE instr(sqlc.arg(hidden_levels), ',' || level || ',') = 0
+  AND (sqlc.arg(search) = ''
+       OR instr(lower(message || ' ' || coalesce(json_extract(attributes, '$.error'), '')), lower(sqlc.arg(search))) > 0)
+  AND (sqlc.arg(feed_id) = 0
+       OR json_extract(attributes, '$.feedID') = sqlc.arg(feed_id)
+       OR json_extract(attributes, '$.feed_id') = sqlc.arg(feed_id)
+       OR json_extract(attributes, '$.url') = sqlc.arg(feed_url))
+ORDER BY timestamp DESC, id DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: CountFilteredLogMessages :one
+-- Counts the log messages GetFilteredLogMessages pages through
+SELECT COUNT(*) FROM log_messages
+WHERE instr(sqlc.arg(hidden_levels), ',' || level || ',') = 0
+  AND (sqlc.arg(search) = ''
+       OR instr(lower(message || ' ' || coalesce(json_extract(attributes, '$.error'), '')), lower(sqlc.arg(search))) > 0)
+  AND (sqlc.arg(feed_id) = 0
+       OR json_extract(attributes, '$.feedID') = sqlc.arg(feed_id)
+       OR json_extract(attributes, '$.feed_id') = sqlc.arg(feed_id)
+       OR json_extract(attributes, '$.url') = sqlc.arg(feed_url));
+
+-- name: GetAllLogMessages :many
+SELECT id, level, message, timestamp, attributes
+FROM log_messages
+ORDER BY timestamp ASC, id ASC;
 
 -- name: GetLogMessage :one
 SELECT id, level, message, timestamp, attributes
@@ -143,6 +356,16 @@ WHERE id = ?;
 -- name: DeleteAllLogMessages :exec
 DELETE FROM log_messages;
 
+-- name: PruneLogMessages :execrows
+DELETE FROM log_messages
+WHERE (sqlc.arg(max_age_days) > 0
+       AND datetime(timestamp) < datetime('now', printf('-%d days', sqlc.arg(max_age_days))))
+   OR (sqlc.arg(max_messages) > 0
+       AND id NOT IN (
+         SELECT newest.id FROM log_messages AS newest
+         ORDER BY newest.id DESC
+         LIMIT sqlc.arg(max_messages)));
+
 -- name: GetSetting :one
 SELECT key, value, updated_at FROM settings WHERE key = ?;
 
@@ -167,6 +390,9 @@ ON CONFLICT(feed_id, folder_name) DO NOTHING;
 -- name: GetFeedFolders :many
 SELECT folder_name FROM feed_folders WHERE feed_id = ? ORDER BY folder_name;
 
+-- name: GetAllFeedFolders :many
+SELECT feed_id, folder_name FROM feed_folders ORDER BY feed_id, folder_name;
+
 -- name: DeleteFeedFolders :exec
 DELETE FROM feed_folders WHERE feed_id = ?;
 
@@ -195,11 +421,19 @@ SELECT
 FROM feeds f
 LEFT JOIN items i ON f.id = i.feed_id
 LEFT JOIN read_status rs ON i.id = rs.item_id
-WHERE f.visible = TRUE AND f.title LIKE '%' || ? || '%'
+WHERE f.visible = TRUE AND fold(f.title) LIKE '%' || fold(?) || '%'
 GROUP BY f.id, f.title, f.url, f.last_error, f.last_error_time
-ORDER BY f.title;
+ORDER BY fold(f.title);
 
 -- name: SearchFeedsGlobally :many
+-- Feeds with the most matching items come first
+WITH matches AS (
+    SELECT mi.feed_id, COUNT(*) AS hits
+    FROM items_fts
+    INNER JOIN items mi ON mi.id = items_fts.rowid
+    WHERE items_fts MATCH sqlc.arg(match)
+    GROUP BY mi.feed_id
+)
 SELECT
     f.id,
     f.title,
@@ -209,18 +443,15 @@ SELECT
     COUNT(i.id) as total_items,
     COUNT(CASE WHEN i.id IS NOT NULL AND COALESCE(rs.read, FALSE) = FALSE THEN 1 END) as unread_items
 FROM feeds f
+LEFT JOIN matches m ON f.id = m.feed_id
 LEFT JOIN items i ON f.id = i.feed_id
 LEFT JOIN read_status rs ON i.id = rs.item_id
 WHERE f.visible = TRUE
-    AND (f.title LIKE '%' || ? || '%'
-         OR f.description LIKE '%' || ? || '%'
-         OR EXISTS (
-             SELECT 1 FROM items i2
-             WHERE i2.feed_id = f.id
-             AND (i2.title LIKE '%' || ? || '%' OR i2.description LIKE '%' || ? || '%' OR i2.content LIKE '%' || ? || '%')
-         ))
+    AND (m.hits IS NOT NULL
+         OR fold(f.title) LIKE '%' || fold(sqlc.arg(pattern)) || '%'
+         OR fold(f.description) LIKE '%' || fold(sqlc.arg(pattern)) || '%')
 GROUP BY f.id, f.title, f.url, f.last_error, f.last_error_time
-ORDER BY f.title;
+ORDER BY COALESCE(MAX(m.hits), 0) DESC, fold(f.title);
 
 -- name: SearchItemsByTitle :many
 SELECT
@@ -228,14 +459,269 @@ SELECT
     COALESCE(rs.read, FALSE) as read
 FROM items i
 LEFT JOIN read_status rs ON i.id = rs.item_id
-WHERE i.feed_id = ? AND i.title LIKE '%' || ? || '%'
+WHERE i.feed_id = ? AND fold(i.title) LIKE '%' || fold(?) || '%'
 ORDER BY i.published DESC;
 
 -- name: SearchItemsGlobally :many
+-- Best matches first, a match in the title weighs more than one in the body
+SELECT
+    i.*,
+    COALESCE(rs.read, FALSE) as read
+FROM items_fts
+INNER JOIN items i ON i.id = items_fts.rowid
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE i.feed_id = sqlc.arg(feed_id) AND items_fts MATCH sqlc.arg(match)
+ORDER BY bm25(items_fts, 10.0, 2.0, 1.0), i.published DESC;
+
+-- name: RecordItemEvent :exec
+INSERT INTO item_events (item_id, feed_id, event)
+VALUES (?, ?, ?);
+
+-- name: GetFeedEngagement :many
+SELECT
+    feed_id,
+    COUNT(CASE WHEN event = 'open' THEN 1 END) as opens,
+    COUNT(CASE WHEN event = 'skip' THEN 1 END) as skips
+FROM item_events
+GROUP BY feed_id;
+
+-- name: GetUnreadItems :many
 SELECT
     i.*,
     COALESCE(rs.read, FALSE) as read
 FROM items i
+INNER JOIN feeds f ON i.feed_id = f.id
 LEFT JOIN read_status rs ON i.id = rs.item_id
-WHERE i.feed_id = ? AND (i.title LIKE '%' || ? || '%' OR i.description LIKE '%' || ? || '%' OR i.content LIKE '%' || ? || '%')
-ORDER BY i.published DESC;
\ No newline at end of file
+WHERE f.visible = TRUE AND COALESCE(rs.read, FALSE) = FALSE
+ORDER BY i.published DESC;
+
+-- name: SetFeedFullText :exec
+UPDATE feeds SET full_text = ? WHERE id = ?;
+
+-- name: UpdateItemFullContent :exec
+UPDATE items SET full_content = ? WHERE id = ?;
+
+-- name: SetItemStarred :exec
+UPDATE items SET starred = ? WHERE id = ?;
+
+-- name: GetStarredItems :many
+SELECT
+    i.*,
+    COALESCE(rs.read, FALSE) as read
+FROM items i
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE i.starred = TRUE
+ORDER BY i.published DESC;
+
+-- name: GetStarredItemsPage :many
+SELECT
+    i.*,
+    COALESCE(rs.read, FALSE) as read
+FROM items i
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE i.starred = TRUE
+ORDER BY CASE WHEN sqlc.arg(unread_first) THEN COALESCE(rs.read, FALSE) ELSE FALSE END, i.published DESC, i.id DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: GetStarredStats :one
+SELECT
+    COUNT(i.id) as total_items,
+    COUNT(CASE WHEN COALESCE(rs.read, FALSE) = FALSE THEN 1 END) as unread_items
+FROM items i
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE i.starred = TRUE;
+
+-- name: GetReadingLogRecent :many
+SELECT
+    i.id,
+    i.title,
+    i.link,
+    i.published,
+    f.title as feed_title,
+    rs.read_at
+FROM items i
+JOIN feeds f ON i.feed_id = f.id
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE COALESCE(rs.read, FALSE) = TRUE AND rs.read_at IS NOT NULL
+ORDER BY rs.read_at DESC
+LIMIT ?;
+
+-- name: GetReadingLogStarred :many
+SELECT
+    i.id,
+    i.title,
+    i.link,
+    i.published,
+    f.title as feed_title,
+    rs.read_at
+FROM items i
+JOIN feeds f ON i.feed_id = f.id
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE i.starred = TRUE
+ORDER BY i.published DESC
+LIMIT ?;
+
+-- name: SetFeedReloadInterval :exec
+UPDATE feeds SET reload_interval = ? WHERE id = ?;
+
+-- name: GetFeedReloadIntervals :many
+SELECT id, reload_interval FROM feeds WHERE visible = TRUE AND reload_interval > 0;
+
+-- name: GetFeedRefreshStats :many
+-- recent_items counts the items first seen in the last 30 days, how often
+-- the feed changed lately
+SELECT
+    f.id,
+    f.last_updated,
+    f.cache_control_max_age,
+    f.consecutive_failures,
+    (SELECT COUNT(*) FROM items i WHERE i.feed_id = f.id AND i.created_at >= datetime('now', '-30 days')) AS recent_items
+FROM feeds f;
+
+-- name: SetFeedEnrich :exec
+UPDATE feeds SET enrich = ? WHERE id = ?;
+
+-- name: SetFeedPaused :exec
+UPDATE feeds SET paused = ? WHERE id = ?;
+
+-- name: SetFeedBodyHash :exec
+UPDATE feeds SET body_hash = ? WHERE id = ?;
+
+-- name: RecordUnchangedFetch :exec
+UPDATE feeds SET unchanged_fetches = unchanged_fetches + 1 WHERE id = ?;
+
+-- name: GetItemsToEnrich :many
+SELECT * FROM items
+WHERE feed_id = ?
+  AND (enriched_at IS NULL
+       OR (enriched_at < ? AND (pr_state IN ('open', 'draft') OR ci_state = 'pending')))
+ORDER BY published DESC
+LIMIT ?;
+
+-- name: UpdateItemEnrichment :exec
+UPDATE items SET pr_number = ?, pr_state = ?, ci_state = ?, enriched_at = ? WHERE id = ?;
+
+-- name: PruneFeedItems :execrows
+-- Items missing from the latest fetch of the feed are the only ones pruned,
+-- anything the feed still publishes would come back unread on the next refresh
+DELETE FROM items
+WHERE items.feed_id = sqlc.arg(feed_id)
+  AND COALESCE(items.seen_at, '') < (SELECT MAX(latest.seen_at) FROM items AS latest WHERE latest.feed_id = items.feed_id)
+  AND NOT (sqlc.arg(keep_starred) AND items.starred)
+  AND NOT (sqlc.arg(keep_unread) AND items.id NOT IN (SELECT item_id FROM read_status WHERE read = TRUE))
+  AND (
+    (sqlc.arg(max_age_days) > 0
+     AND datetime(COALESCE(items.published, items.created_at)) < datetime('now', printf('-%d days', sqlc.arg(max_age_days))))
+    OR (sqlc.arg(max_items) > 0
+     AND items.id NOT IN (
+       SELECT newest.id FROM items AS newest
+       WHERE newest.feed_id = items.feed_id
+       ORDER BY datetime(COALESCE(newest.published, newest.created_at)) DESC, newest.id DESC
+       LIMIT sqlc.arg(max_items)))
+  );
+
+-- name: GetItemGUIDs :many
+SELECT guid FROM items WHERE feed_id = ?;
+
+-- name: GetWebSubSubscription :one
+SELECT * FROM websub_subscriptions WHERE feed_id = ?;
+
+-- name: RequestWebSubSubscription :exec
+-- The lease stays valid while the feed keeps its hub and topic
+INSERT INTO websub_subscriptions (feed_id, hub, topic, requested_at, secret)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(feed_id) DO UPDATE SET
+    lease_expires = CASE
+        WHEN websub_subscriptions.hub = excluded.hub AND websub_subscriptions.topic = excluded.topic
+        THEN websub_subscriptions.lease_expires
+    END,
+    hub = excluded.hub,
+    topic = excluded.topic,
+    requested_at = excluded.requested_at,
+    secret = excluded.secret;
+
+-- name: SetWebSubLease :exec
+UPDATE websub_subscriptions SET lease_expires = ? WHERE feed_id = ?;
+
+-- name: DeleteWebSubSubscription :exec
+DELETE FROM websub_subscriptions WHERE feed_id = ?;
+
+-- name: GetSyncEntries :many
+-- item_id is NULL when the item isn't fetched yet or was deleted
+SELECT
+    s.remote_id, i.id as item_id, s.feed_url, s.url, s.read, s.starred,
+    COALESCE(rs.read, FALSE) as local_read,
+    COALESCE(i.starred, FALSE) as local_starred
+FROM sync_entries s
+LEFT JOIN items i ON i.id = s.item_id
+LEFT JOIN read_status rs ON rs.item_id = s.item_id
+WHERE s.service = ?;
+
+-- name: FindSyncItem :one
+-- Items of the entry's feed are preferred, the feed URL can differ when the
+-- service followed a redirect
+SELECT i.id, i.starred, COALESCE(rs.read, FALSE) as read
+FROM items i
+JOIN feeds f ON i.feed_id = f.id
+LEFT JOIN read_status rs ON i.id = rs.item_id
+WHERE i.link = ?
+ORDER BY f.url = ? DESC, i.id
+LIMIT 1;
+
+-- name: UpsertSyncEntry :exec
+INSERT INTO sync_entries (service, remote_id, item_id, feed_url, url, read, starred, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(service, remote_id) DO UPDATE SET
+    item_id = excluded.item_id,
+    feed_url = excluded.feed_url,
+    url = excluded.url,
+    read = excluded.read,
+    starred = excluded.starred,
+    updated_at = excluded.updated_at;
+
+-- name: PruneSyncEntries :execrows
+DELETE FROM sync_entries
+WHERE service = ? AND item_id IS NULL AND updated_at < ?;
+
+-- name: SaveTask :exec
+INSERT INTO tasks (id, type, status, priority, data, attempts, next_retry, error, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+    status = excluded.status,
+    data = excluded.data,
+    attempts = excluded.attempts,
+    next_retry = excluded.next_retry,
+    error = excluded.error;
+
+-- name: DeleteTask :exec
+DELETE FROM tasks WHERE id = ?;
+
+-- name: GetTasks :many
+SELECT * FROM tasks ORDER BY created_at;
+
+-- name: SaveFeedIcon :exec
+INSERT INTO feed_icons (host, color, fetched_at)
+VALUES (?, ?, ?)
+ON CONFLICT(host) DO UPDATE SET
+    color = excluded.color,
+    fetched_at = excluded.fetched_at;
+
+-- name: GetFeedIcon :one
+SELECT * FROM feed_icons WHERE host = ?;
+
+-- name: GetFeedIcons :many
+SELECT * FROM feed_icons WHERE color != '';
+
+-- name: ListItemRules :many
+SELECT * FROM item_rules ORDER BY id;
+
+-- name: ListFeedItemRules :many
+SELECT * FROM item_rules WHERE feed_id IS NULL OR feed_id = ? ORDER BY id;
+
+-- name: CreateItemRule :one
+INSERT INTO item_rules (feed_id, field, pattern, action, color, marker, notify)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: DeleteItemRule :exec
+DELETE FROM item_rules WHERE id = ?;
@@ -10,7 +10,17 @@ CREATE TABLE IF NOT EXISTS feeds (
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     etag TEXT,
     last_modified TEXT,
-    cache_control_max_age INTEGER
+    cache_control_max_age INTEGER,
+    full_text BOOLEAN NOT NULL DEFAULT FALSE,
+    reload_interval INTEGER NOT NULL DEFAULT 0,
+    enrich BOOLEAN NOT NULL DEFAULT FALSE,
+    paused BOOLEAN NOT NULL DEFAULT FALSE,
+    body_hash TEXT NOT NULL DEFAULT '',
+    unchanged_fetches INTEGER NOT NULL DEFAULT 0,
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    retry_after DATETIME,
+    custom_title TEXT NOT NULL DEFAULT '',
+    last_result TEXT NOT NULL DEFAULT ''
 );
 
 CREATE TABLE IF NOT EXISTS items (
@@ -23,6 +33,16 @@ CREATE TABLE IF NOT EXISTS items (
     link TEXT NOT NULL DEFAULT '',
     published DATETIME,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    full_content TEXT NOT NULL DEFAULT '',
+    starred BOOLEAN NOT NULL DEFAULT FALSE,
+    pr_number INTEGER NOT NULL DEFAULT 0,
+    pr_state TEXT NOT NULL DEFAULT '',
+    ci_state TEXT NOT NULL DEFAULT '',
+    enriched_at DATETIME,
+    seen_at DATETIME,
+    author TEXT NOT NULL DEFAULT '',
+    canonical_link TEXT NOT NULL DEFAULT '', -- Link without tracking parameters, to find duplicates
+    content_hash TEXT NOT NULL DEFAULT '', -- Hash of the normalized text of long content, to find duplicates
     FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE,
     UNIQUE(feed_id, guid)
 );
@@ -52,6 +72,7 @@ CREATE TABLE IF NOT EXISTS settings (
 
 CREATE INDEX IF NOT EXISTS idx_items_feed_id ON items(feed_id);
 CREATE INDEX IF NOT EXISTS idx_items_published ON items(published);
+CREATE INDEX IF NOT EXISTS idx_items_starred ON items(starred);
 CREATE INDEX IF NOT EXISTS idx_read_status_item_id ON read_status(item_id);
 CREATE INDEX IF NOT EXISTS idx_read_status_read ON read_status(read);
 CREATE INDEX IF NOT EXISTS idx_log_messages_timestamp ON log_messages(timestamp);
@@ -66,4 +87,120 @@ CREATE TABLE IF NOT EXISTS feed_folders (
 );
 
 CREATE INDEX IF NOT EXISTS idx_feed_folders_feed_id ON feed_folders(feed_id);
-CREATE INDEX IF NOT EXISTS idx_feed_folders_folder_name ON feed_folders(folder_name);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_feed_folders_folder_name ON feed_folders(folder_name);
+
+CREATE TABLE IF NOT EXISTS item_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    item_id INTEGER NOT NULL,
+    feed_id INTEGER NOT NULL,
+    event TEXT NOT NULL, -- 'open' or 'skip'
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_events_feed_id ON item_events(feed_id);
+
+-- Full-text index of item titles, descriptions and content, kept in sync by triggers
+CREATE VIRTUAL TABLE IF NOT EXISTS items_fts USING fts5(
+    title,
+    description,
+    content,
+    content='items',
+    content_rowid='id',
+    tokenize='unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER IF NOT EXISTS items_fts_insert AFTER INSERT ON items BEGIN
+    INSERT INTO items_fts(rowid, title, description, content)
+    VALUES (new.id, new.title, new.description, new.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS items_fts_delete AFTER DELETE ON items BEGIN
+    INSERT INTO items_fts(items_fts, rowid, title, description, content)
+    VALUES ('delete', old.id, old.title, old.description, old.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS items_fts_update AFTER UPDATE OF title, description, content ON items BEGIN
+    INSERT INTO items_fts(items_fts, rowid, title, description, content)
+    VALUES ('delete', old.id, old.title, old.description, old.content);
+    INSERT INTO items_fts(rowid, title, description, content)
+    VALUES (new.id, new.title, new.description, new.content);
+END;
+
+
+CREATE TABLE IF NOT EXISTS websub_subscriptions (
+    feed_id INTEGER PRIMARY KEY,
+    hub TEXT NOT NULL,
+    topic TEXT NOT NULL,
+    requested_at DATETIME NOT NULL,
+    lease_expires DATETIME, -- NULL until the hub has verified the subscription
+    secret TEXT NOT NULL DEFAULT '', -- Pushed updates are signed with it, '' for older subscriptions
+    FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS sync_entries (
+    service TEXT NOT NULL,
+    remote_id TEXT NOT NULL,
+    item_id INTEGER, -- NULL until the item has been fetched locally
+    feed_url TEXT NOT NULL,
+    url TEXT NOT NULL,
+    read BOOLEAN NOT NULL, -- State both sides agreed on at the last sync
+    starred BOOLEAN NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (service, remote_id),
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_entries_item_id ON sync_entries(item_id);
+CREATE INDEX IF NOT EXISTS idx_items_link ON items(link);
+CREATE INDEX IF NOT EXISTS idx_items_canonical_link ON items(canonical_link);
+CREATE INDEX IF NOT EXISTS idx_items_content_hash ON items(content_hash);
+
+CREATE TABLE IF NOT EXISTS tasks (
+    id TEXT PRIMARY KEY,
+    type TEXT NOT NULL,
+    status TEXT NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    data TEXT NOT NULL DEFAULT '{}', -- JSON of the task's data
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_retry DATETIME, -- NULL unless a failed task waits to run again
+    error TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL
+);
+
+-- Colors of the favicons of the sites feeds come from, by the host of the
+-- feed URL
+CREATE TABLE IF NOT EXISTS feed_icons (
+    host TEXT PRIMARY KEY,
+    color TEXT NOT NULL DEFAULT '', -- Main color as #rrggbb, empty when the site has no icon
+    fetched_at DATETIME NOT NULL
+);
+
+-- Rules hiding, marking read or highlighting the items whose title, author
+-- or link match a pattern
+CREATE TABLE IF NOT EXISTS item_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    feed_id INTEGER, -- NULL for rules applying to every feed
+    field TEXT NOT NULL, -- title, author or link
+    pattern TEXT NOT NULL, -- Regular expression
+    action TEXT NOT NULL, -- hide, read or highlight
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    color TEXT NOT NULL DEFAULT '', -- Color of highlighted items, a color number or #rrggbb
+    marker TEXT NOT NULL DEFAULT '', -- Shown in front of the titles of highlighted items, like an emoji
+    notify BOOLEAN NOT NULL DEFAULT FALSE, -- New highlighted items send a desktop notification
+    FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+);
+
+-- Every time an item is read, for the reading statistics
+CREATE TABLE IF NOT EXISTS read_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    item_id INTEGER, -- NULL once the item is pruned
+    feed_id INTEGER NOT NULL,
+    read_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at DATETIME, -- When the item was published, or first seen when the feed doesn't date it
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE SET NULL,
+    FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_read_events_read_at ON read_events(read_at);
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jarv/newsgoat/internal/updater"
+	"github.com/jarv/newsgoat/internal/version"
+)
+
+// updateCommand replaces the newsgoat binary with the latest release, or
+// only says whether there is one with --check
+func updateCommand(args []string) error {
+	flags := flag.NewFlagSet("update", flag.ExitOnError)
+	check := flags.Bool("check", false, "Only print whether a new version is available")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: newsgoat update [--check]\n\n")
+		fmt.Fprintf(os.Stderr, "Downloads the latest release and replaces this binary with it.\n\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return fmt.Errorf("'update' takes no arguments")
+	}
+
+	if version.GetVersion() == "dev" {
+		return fmt.Errorf("this is a development build, install a release to update it")
+	}
+	info, err := updater.CheckForUpdate()
+	if err != nil {
+		return err
+	}
+	if info == nil {
+		fmt.Printf("newsgoat %s is up to date\n", version.GetVersion())
+		return nil
+	}
+	if *check {
+		fmt.Printf("New version %s available (running %s), run newsgoat update to install it\n", info.LatestVersion, info.CurrentVersion)
+		return nil
+	}
+
+	if err := updater.CheckWritePermission(); err != nil {
+		return err
+	}
+	fmt.Printf("Updating newsgoat %s to %s\n", info.CurrentVersion, info.LatestVersion)
+	lastPercent := -1
+	err = updater.DownloadAndInstallWithProgress(info.DownloadURL, func(done, total int64) {
+		if total <= 0 {
+			return
+		}
+		if percent := int(done * 100 / total); percent != lastPercent {
+			lastPercent = percent
+			fmt.Fprintf(os.Stderr, "\rDownloading... %3d%%", percent)
+		}
+	})
+	if lastPercent >= 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Updated to %s, restart newsgoat to use it\n", info.LatestVersion)
+	return nil
+}